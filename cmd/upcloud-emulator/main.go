@@ -0,0 +1,32 @@
+// Command upcloud-emulator serves an in-memory emulation of the UpCloud API, e.g. for developing against the API
+// without an account:
+//
+//	upcloud-emulator -listen 127.0.0.1:8080 -transition-delay 1s
+//
+// Clients point at it by setting their base URL to http://127.0.0.1:8080.
+package main
+
+import (
+	"flag"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/UpCloudLtd/upcloud-go-api/upcloud/emulator"
+)
+
+func main() {
+	listen := flag.String("listen", "127.0.0.1:8080", "the address to listen on")
+	username := flag.String("username", "", "the username clients must authenticate with, any credentials are accepted when empty")
+	password := flag.String("password", "", "the password clients must authenticate with")
+	delay := flag.Duration("transition-delay", 2*time.Second, "the time each state transition takes")
+	flag.Parse()
+
+	opts := []emulator.Option{emulator.WithTransitionDelay(*delay)}
+	if *username != "" {
+		opts = append(opts, emulator.WithCredentials(*username, *password))
+	}
+
+	log.Printf("Serving the UpCloud API emulator on http://%s", *listen)
+	log.Fatal(http.ListenAndServe(*listen, emulator.New(opts...)))
+}
@@ -2,10 +2,13 @@ package client
 
 import (
 	"bytes"
+	"context"
 	"fmt"
 	"io"
 	"io/ioutil"
 	"net/http"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/blang/semver"
@@ -19,46 +22,239 @@ const (
 
 	// The default timeout (in seconds)
 	DefaultTimeout = 60
+
+	// Version is the version of this library
+	Version = "3.0.0"
+	// DefaultUserAgent is the User-Agent header sent with every request
+	DefaultUserAgent = "upcloud-go-api/" + Version
 )
 
 // Client represents an API client
 type Client struct {
-	userName    string
-	password    string
-	httpClient  *http.Client
+	// Accessed atomically, kept first to guarantee 64-bit alignment
+	retryCount uint64
+
+	httpClient *http.Client
+	baseURL    string
+	apiVersion string
+	userAgent  string
+	headers    http.Header
+
+	// The settings below can be changed at any time and are guarded by mu
+	mu          sync.RWMutex
+	credentials CredentialsProvider
+	timeout     time.Duration
 	contentType string
+	retryPolicy *RetryPolicy
+	rateLimiter *RateLimiter
+	inFlight    chan struct{}
+	logger      Logger
+	logBodySize int
+	metrics     Metrics
+	dryRun      *Plan
 }
 
 // New creates ands returns a new client configured with the specified user and password
 func New(userName, password string) *Client {
-
-	return NewWithHTTPClient(userName, password, cleanhttp.DefaultClient())
+	return NewWithOptions(userName, password)
 }
 
 // NewWithHTTPClient creates ands returns a new client configured with the specified user and password and
 // using a supplied `http.Client`.
 func NewWithHTTPClient(userName string, password string, httpClient *http.Client) *Client {
-	client := Client{}
+	return NewWithOptions(userName, password, WithHTTPClient(httpClient))
+}
+
+// NewWithOptions creates and returns a new client configured with the specified user and password and the
+// specified options. Without any options the client talks to DefaultAPIBaseURL using DefaultAPIVersion.
+func NewWithOptions(userName, password string, opts ...Option) *Client {
+	return NewWithCredentials(StaticCredentials(userName, password), opts...)
+}
+
+// NewWithCredentials creates and returns a new client asking the specified provider for its credentials, e.g.
+// DefaultCredentials(), and configured with the specified options
+func NewWithCredentials(credentials CredentialsProvider, opts ...Option) *Client {
+	client := Client{
+		credentials: credentials,
+		baseURL:     DefaultAPIBaseURL,
+		apiVersion:  DefaultAPIVersion,
+		userAgent:   DefaultUserAgent,
+		headers:     http.Header{},
+	}
+
+	for _, opt := range opts {
+		opt(&client)
+	}
+
+	if client.httpClient == nil {
+		client.httpClient = cleanhttp.DefaultClient()
+	}
 
-	client.userName = userName
-	client.password = password
-	client.httpClient = httpClient
 	// Set the default timeout if the caller hasn't set its own
-	if client.httpClient.Timeout == 0 {
-		client.SetTimeout(time.Second * DefaultTimeout)
+	if client.timeout == 0 {
+		client.timeout = client.httpClient.Timeout
+	}
+	if client.timeout == 0 {
+		client.timeout = time.Second * DefaultTimeout
 	}
 
+	// The timeout is enforced per request so that a single request can override it. Work on a copy of the HTTP
+	// client to avoid changing the caller's one.
+	httpClient := *client.httpClient
+	httpClient.Timeout = 0
+	client.httpClient = &httpClient
+
 	return &client
 }
 
-// SetTimeout sets the client timeout to the specified amount of seconds
+// SetCredentialsProvider sets the provider the client asks for its credentials before every request
+func (c *Client) SetCredentialsProvider(credentials CredentialsProvider) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.credentials = credentials
+}
+
+// GetCredentialsProvider returns the current credentials provider
+func (c *Client) GetCredentialsProvider() CredentialsProvider {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return c.credentials
+}
+
+// SetTimeout sets the client timeout to the specified amount of seconds. The timeout applies to every attempt of
+// every request, unless overridden for a single request with WithRequestTimeout.
 func (c *Client) SetTimeout(timeout time.Duration) {
-	c.httpClient.Timeout = timeout
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.timeout = timeout
 }
 
 // GetTimeout returns current timeout
 func (c *Client) GetTimeout() time.Duration {
-	return c.httpClient.Timeout
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return c.timeout
+}
+
+// SetRetryPolicy sets the policy used to retry requests that failed with a transient error. Passing nil disables
+// retries, which is the default.
+func (c *Client) SetRetryPolicy(policy *RetryPolicy) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.retryPolicy = policy
+}
+
+// GetRetryPolicy returns the current retry policy, or nil if retries are disabled
+func (c *Client) GetRetryPolicy() *RetryPolicy {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return c.retryPolicy
+}
+
+// RetryCount returns the total number of retries performed by the client
+func (c *Client) RetryCount() uint64 {
+	return atomic.LoadUint64(&c.retryCount)
+}
+
+// SetRateLimiter sets the rate limiter used to pace the requests sent by the client. Passing nil disables rate
+// limiting, which is the default. The same limiter can be shared by several clients.
+func (c *Client) SetRateLimiter(limiter *RateLimiter) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.rateLimiter = limiter
+}
+
+// GetRateLimiter returns the current rate limiter, or nil if rate limiting is disabled
+func (c *Client) GetRateLimiter() *RateLimiter {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return c.rateLimiter
+}
+
+// SetMaxConcurrentRequests limits the number of requests the client has in flight at any time. Zero or a negative
+// value removes the limit, which is the default. Requests already in flight are not affected.
+func (c *Client) SetMaxConcurrentRequests(max int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if max <= 0 {
+		c.inFlight = nil
+		return
+	}
+
+	c.inFlight = make(chan struct{}, max)
+}
+
+// SetLogger sets the logger receiving an entry for every attempt to perform a request, with the request ID, method,
+// URL, status and latency of the attempt, and an entry for every retry. Passing nil disables logging, which is the
+// default.
+func (c *Client) SetLogger(logger Logger) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.logger = logger
+}
+
+// GetLogger returns the current logger, or nil if logging is disabled
+func (c *Client) GetLogger() Logger {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return c.logger
+}
+
+// SetBodyLogging makes the logger entries include the request headers and the request and response bodies, cut to
+// maxSize bytes. Zero, the default, leaves them out. The Authorization header, passwords and Object Storage keys are
+// redacted and only the size of bodies other than JSON, e.g. storage uploads, is logged.
+func (c *Client) SetBodyLogging(maxSize int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.logBodySize = maxSize
+}
+
+// SetMetrics sets the metrics receiving the method, endpoint, status and latency of every attempt to perform a
+// request and every retry. Passing nil disables metrics, which is the default. The same metrics can be shared by
+// several clients.
+func (c *Client) SetMetrics(metrics Metrics) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.metrics = metrics
+}
+
+// GetMetrics returns the current metrics, or nil if metrics are disabled
+func (c *Client) GetMetrics() Metrics {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return c.metrics
+}
+
+// SetDryRun puts the client in dry-run mode, or takes it out of it if the plan is nil. In dry-run mode GET requests
+// are sent as usual while requests that would change something, e.g. POST and DELETE requests, are added to the plan
+// instead of being sent and succeed with an empty response body. The service returns placeholder results for them.
+func (c *Client) SetDryRun(plan *Plan) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.dryRun = plan
+}
+
+// GetDryRun returns the plan of the client in dry-run mode, or nil if the client isn't in dry-run mode
+func (c *Client) GetDryRun() *Plan {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return c.dryRun
 }
 
 // CreateRequestURL creates and returns a complete request URL for the specified API location
@@ -69,128 +265,376 @@ func (c *Client) CreateRequestURL(location string) string {
 
 // PerformJSONGetRequest performs a GET request to the specified URL and returns the response body and eventual errors
 func (c *Client) PerformJSONGetRequest(url string) ([]byte, error) {
-	request, err := http.NewRequest(http.MethodGet, url, nil)
+	return c.PerformJSONGetRequestWithContext(context.Background(), url)
+}
+
+// PerformJSONGetRequestWithContext is the same as PerformJSONGetRequest but binds the request to the specified context
+// and applies the specified per-request options
+func (c *Client) PerformJSONGetRequestWithContext(ctx context.Context, url string, opts ...RequestOption) ([]byte, error) {
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
 
 	if err != nil {
 		return nil, err
 	}
 
-	return c.performJSONRequest(request)
+	return c.performJSONRequest(request, opts)
 }
 
 // PerformJSONPostRequest performs a POST request to the specified URL and returns the response body and eventual errors
 func (c *Client) PerformJSONPostRequest(url string, requestBody []byte) ([]byte, error) {
+	return c.PerformJSONPostRequestWithContext(context.Background(), url, requestBody)
+}
+
+// PerformJSONPostRequestWithContext is the same as PerformJSONPostRequest but binds the request to the specified context
+// and applies the specified per-request options
+func (c *Client) PerformJSONPostRequestWithContext(ctx context.Context, url string, requestBody []byte, opts ...RequestOption) ([]byte, error) {
 	var bodyReader io.Reader
 
 	if requestBody != nil {
 		bodyReader = bytes.NewBuffer(requestBody)
 	}
 
-	request, err := http.NewRequest(http.MethodPost, url, bodyReader)
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bodyReader)
 
 	if err != nil {
 		return nil, err
 	}
 
-	return c.performJSONRequest(request)
+	return c.performJSONRequest(request, opts)
 }
 
 // PerformJSONPutRequest performs a PUT request to the specified URL and returns the response body and eventual errors
 func (c *Client) PerformJSONPutRequest(url string, requestBody []byte) ([]byte, error) {
+	return c.PerformJSONPutRequestWithContext(context.Background(), url, requestBody)
+}
+
+// PerformJSONPutRequestWithContext is the same as PerformJSONPutRequest but binds the request to the specified context
+// and applies the specified per-request options
+func (c *Client) PerformJSONPutRequestWithContext(ctx context.Context, url string, requestBody []byte, opts ...RequestOption) ([]byte, error) {
 	var bodyReader io.Reader
 
 	if requestBody != nil {
 		bodyReader = bytes.NewBuffer(requestBody)
 	}
 
-	request, err := http.NewRequest(http.MethodPut, url, bodyReader)
+	request, err := http.NewRequestWithContext(ctx, http.MethodPut, url, bodyReader)
 
 	if err != nil {
 		return nil, err
 	}
 
-	return c.performJSONRequest(request)
+	return c.performJSONRequest(request, opts)
 }
 
 // PerformJSONPatchRequest performs a PATCH request to the specified URL and returns the response body and eventual errors
 func (c *Client) PerformJSONPatchRequest(url string, requestBody []byte) ([]byte, error) {
+	return c.PerformJSONPatchRequestWithContext(context.Background(), url, requestBody)
+}
+
+// PerformJSONPatchRequestWithContext is the same as PerformJSONPatchRequest but binds the request to the specified context
+// and applies the specified per-request options
+func (c *Client) PerformJSONPatchRequestWithContext(ctx context.Context, url string, requestBody []byte, opts ...RequestOption) ([]byte, error) {
 	var bodyReader io.Reader
 
 	if requestBody != nil {
 		bodyReader = bytes.NewBuffer(requestBody)
 	}
 
-	request, err := http.NewRequest(http.MethodPatch, url, bodyReader)
+	request, err := http.NewRequestWithContext(ctx, http.MethodPatch, url, bodyReader)
 
 	if err != nil {
 		return nil, err
 	}
 
-	return c.performJSONRequest(request)
+	return c.performJSONRequest(request, opts)
 }
 
 // PerformJSONDeleteRequest performs a DELETE request to the specified URL and returns the response body and eventual errors
 func (c *Client) PerformJSONDeleteRequest(url string) error {
-	request, err := http.NewRequest(http.MethodDelete, url, nil)
+	return c.PerformJSONDeleteRequestWithContext(context.Background(), url)
+}
+
+// PerformJSONDeleteRequestWithContext is the same as PerformJSONDeleteRequest but binds the request to the specified context
+// and applies the specified per-request options
+func (c *Client) PerformJSONDeleteRequestWithContext(ctx context.Context, url string, opts ...RequestOption) error {
+	request, err := http.NewRequestWithContext(ctx, http.MethodDelete, url, nil)
 
 	if err != nil {
 		return err
 	}
 
-	_, err = c.performJSONRequest(request)
+	_, err = c.performJSONRequest(request, opts)
 	return err
 }
 
 // PerformJSONPutUploadRequest performs a PUT request to the specified URL with an io.Reader
 // and returns the response body and eventual errors
 func (c *Client) PerformJSONPutUploadRequest(url string, requestBody io.Reader) ([]byte, error) {
+	return c.PerformJSONPutUploadRequestWithContext(context.Background(), url, requestBody)
+}
 
-	request, err := http.NewRequest(http.MethodPut, url, requestBody)
+// PerformJSONPutUploadRequestWithContext is the same as PerformJSONPutUploadRequest but binds the request to the specified context
+// and applies the specified per-request options
+func (c *Client) PerformJSONPutUploadRequestWithContext(ctx context.Context, url string, requestBody io.Reader, opts ...RequestOption) ([]byte, error) {
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodPut, url, requestBody)
 
 	if err != nil {
 		return nil, err
 	}
 
-	return c.performJSONRequest(request)
+	return c.performJSONRequest(request, opts)
 }
 
+// SetContentType sets the Content-Type header sent with every request.
+//
+// Deprecated: changing the content type affects all requests made through the client. Use WithRequestContentType
+// to set it for a single request instead.
 func (c *Client) SetContentType(ct string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
 	c.contentType = ct
 }
 
+// GetContentType returns the Content-Type header sent with every request
 func (c *Client) GetContentType() string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
 	if c.contentType == "" {
 		return "application/json"
 	}
 	return c.contentType
 }
 
-// Adds common headers to the specified request
-func (c *Client) addJSONRequestHeaders(request *http.Request) *http.Request {
-	request.SetBasicAuth(c.userName, c.password)
+// Returns the settings for a single request: a snapshot of the client settings with the per-request options applied
+func (c *Client) newRequestConfig(opts []RequestOption) *requestConfig {
+	c.mu.RLock()
+	config := requestConfig{
+		credentials: c.credentials,
+		timeout:     c.timeout,
+		contentType: c.contentType,
+		retryPolicy: c.retryPolicy,
+		rateLimiter: c.rateLimiter,
+		inFlight:    c.inFlight,
+		logger:      c.logger,
+		logBodySize: c.logBodySize,
+		metrics:     c.metrics,
+		dryRun:      c.dryRun,
+	}
+	c.mu.RUnlock()
+
+	if config.contentType == "" {
+		config.contentType = "application/json"
+	}
+
+	for _, opt := range opts {
+		opt(&config)
+	}
+
+	return &config
+}
+
+// Adds common headers and the credentials to the specified request
+func (c *Client) addJSONRequestHeaders(request *http.Request, config *requestConfig, credentials Credentials) *http.Request {
+	for key, values := range c.headers {
+		request.Header[key] = append([]string(nil), values...)
+	}
+	for key, values := range config.headers {
+		request.Header[key] = append([]string(nil), values...)
+	}
+	if request.Header.Get("User-Agent") == "" {
+		request.Header.Set("User-Agent", c.userAgent)
+	}
+
+	request.SetBasicAuth(credentials.Username, credentials.Password)
 	request.Header.Set("Accept", "application/json")
-	request.Header.Set("Content-Type", c.GetContentType())
+	request.Header.Set("Content-Type", config.contentType)
 
 	return request
 }
 
-// Performs the specified HTTP request and returns the response through handleResponse()
-func (c *Client) performJSONRequest(request *http.Request) ([]byte, error) {
-	c.addJSONRequestHeaders(request)
-	response, err := c.httpClient.Do(request)
+// Performs the specified HTTP request and returns the response through handleResponse(). Requests that fail with a
+// transient error are retried according to the retry policy. Requests rejected with 401 Unauthorized are retried
+// once if the credentials provider comes up with new credentials. In dry-run mode requests that would change
+// something are added to the plan instead.
+func (c *Client) performJSONRequest(request *http.Request, opts []RequestOption) ([]byte, error) {
+	config := c.newRequestConfig(opts)
+	if config.dryRun != nil && !isReadOnly(request.Method) {
+		config.planRequest(request)
+		return nil, nil
+	}
 
+	credentials, err := config.credentials.Credentials(request.Context())
 	if err != nil {
-		return nil, err
+		return nil, fmt.Errorf("resolving credentials: %w", err)
 	}
+	c.addJSONRequestHeaders(request, config, credentials)
+	policy := config.retryPolicy
+	requestID := newRequestID()
+	refreshed := false
+
+	for attempt := 1; ; attempt++ {
+		started := time.Now()
+		response, responseBody, err := c.performAttempt(request, config)
+		elapsed := time.Since(started)
+		config.logAttempt(request, requestID, attempt, elapsed, response, responseBody, err)
+		config.observeAttempt(request, attempt, elapsed, response, err)
+		if err == nil {
+			return responseBody, nil
+		}
+
+		if response != nil && response.StatusCode == http.StatusUnauthorized && !refreshed && canRewind(request) {
+			refreshed = true
+			if fresh, ok := config.refreshCredentials(request.Context(), credentials); ok {
+				if request, err = rewindRequest(request); err != nil {
+					return nil, err
+				}
+				credentials = fresh
+				request.SetBasicAuth(credentials.Username, credentials.Password)
+				continue
+			}
+		}
+
+		delay, retry := policy.retryDelay(request, response, err, attempt)
+		if !retry {
+			return nil, err
+		}
+
+		event := RetryEvent{
+			Method:  request.Method,
+			URL:     request.URL.String(),
+			Attempt: attempt,
+			Delay:   delay,
+			Err:     err,
+		}
+		if response != nil {
+			event.StatusCode = response.StatusCode
+		}
+		config.logRetry(request, requestID, event)
+		config.observeRetry(request, event)
+		if policy.OnRetry != nil {
+			policy.OnRetry(event)
+		}
+		atomic.AddUint64(&c.retryCount, 1)
+
+		if sleepErr := sleepWithContext(request.Context(), delay); sleepErr != nil {
+			return nil, sleepErr
+		}
+
+		if request, err = rewindRequest(request); err != nil {
+			return nil, err
+		}
+	}
+}
 
-	return handleResponse(response)
+// Sends the request once, respecting the rate and concurrency limits. The response is returned alongside
+// any error so that the caller can inspect its status and headers; it is nil if no response was received.
+func (c *Client) performAttempt(request *http.Request, config *requestConfig) (*http.Response, []byte, error) {
+	ctx := request.Context()
+
+	// Wait for the rate limiter before taking a slot, so that requests waiting for their turn don't keep others from
+	// being sent
+	limiter := config.rateLimiter
+	if limiter != nil {
+		if err := limiter.Wait(ctx); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	if inFlight := config.inFlight; inFlight != nil {
+		select {
+		case inFlight <- struct{}{}:
+			defer func() { <-inFlight }()
+		case <-ctx.Done():
+			return nil, nil, ctx.Err()
+		}
+	}
+
+	// The timeout only covers sending the request and reading the response, not waiting for the limits
+	if config.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, config.timeout)
+		defer cancel()
+		request = request.WithContext(ctx)
+	}
+
+	response, err := c.httpClient.Do(request)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	responseBody, err := handleResponse(response)
+
+	if limiter != nil {
+		if response.StatusCode == http.StatusTooManyRequests {
+			retryAfter, _ := parseRetryAfter(response.Header.Get("Retry-After"))
+			limiter.Throttled(retryAfter)
+		} else {
+			limiter.Succeeded()
+		}
+	}
+
+	return response, responseBody, err
+}
+
+// Invalidates the credentials rejected by the API and returns the ones provided next, if they are different
+func (c *requestConfig) refreshCredentials(ctx context.Context, rejected Credentials) (Credentials, bool) {
+	invalidator, ok := c.credentials.(CredentialsInvalidator)
+	if !ok {
+		return Credentials{}, false
+	}
+	invalidator.InvalidateCredentials()
+
+	fresh, err := c.credentials.Credentials(ctx)
+	if err != nil || fresh == rejected {
+		return Credentials{}, false
+	}
+	return fresh, true
+}
+
+// Returns whether the request can be sent again
+func canRewind(request *http.Request) bool {
+	return request.Body == nil || request.Body == http.NoBody || request.GetBody != nil
+}
+
+// Returns a copy of the request with a fresh body so that it can be sent again
+func rewindRequest(request *http.Request) (*http.Request, error) {
+	next := request.Clone(request.Context())
+
+	if request.GetBody != nil {
+		body, err := request.GetBody()
+		if err != nil {
+			return nil, err
+		}
+		next.Body = body
+	}
+
+	return next, nil
+}
+
+// Pauses for the specified duration or until the context is done, whichever happens first
+func sleepWithContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
 }
 
 // Returns the base URL to use for API requests
 func (c *Client) getBaseURL() string {
-	urlVersion, _ := semver.Make(DefaultAPIVersion)
+	urlVersion, err := semver.ParseTolerant(c.apiVersion)
+	if err != nil {
+		return fmt.Sprintf("%s/%s", c.baseURL, c.apiVersion)
+	}
 
-	return fmt.Sprintf("%s/%d.%d", DefaultAPIBaseURL, urlVersion.Major, urlVersion.Minor)
+	return fmt.Sprintf("%s/%d.%d", c.baseURL, urlVersion.Major, urlVersion.Minor)
 }
 
 // Parses the response and returns either the response body or an error
@@ -201,7 +645,17 @@ func handleResponse(response *http.Response) ([]byte, error) {
 	if response.StatusCode < 200 || response.StatusCode > 299 {
 		errorBody, _ := ioutil.ReadAll(response.Body)
 
-		return nil, &Error{response.StatusCode, response.Status, errorBody}
+		clientError := &Error{
+			ErrorCode:    response.StatusCode,
+			ErrorMessage: response.Status,
+			ResponseBody: errorBody,
+		}
+		if response.Request != nil {
+			clientError.Method = response.Request.Method
+			clientError.URL = response.Request.URL.String()
+		}
+
+		return nil, clientError
 	}
 
 	responseBody, err := ioutil.ReadAll(response.Body)
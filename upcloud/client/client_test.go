@@ -0,0 +1,58 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestPerformJSONGetRequestWithContext tests that a request is aborted when its context is cancelled
+func TestPerformJSONGetRequestWithContext(t *testing.T) {
+	done := make(chan struct{})
+	defer close(done)
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-done:
+		case <-r.Context().Done():
+		}
+	}))
+	defer srv.Close()
+
+	c := New("username", "password")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	_, err := c.PerformJSONGetRequestWithContext(ctx, srv.URL)
+	require.Error(t, err)
+	assert.True(t, errors.Is(err, context.DeadlineExceeded))
+	assert.True(t, time.Since(start) < DefaultTimeout*time.Second)
+}
+
+// TestPerformJSONPostRequestWithContext tests that the context-aware variants send the same request as the plain ones
+func TestPerformJSONPostRequestWithContext(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+		user, password, ok := r.BasicAuth()
+		assert.True(t, ok)
+		assert.Equal(t, "username", user)
+		assert.Equal(t, "password", password)
+		w.Write([]byte(`{"ok":true}`))
+	}))
+	defer srv.Close()
+
+	c := New("username", "password")
+
+	response, err := c.PerformJSONPostRequestWithContext(context.Background(), srv.URL, []byte(`{}`))
+	require.NoError(t, err)
+	assert.Equal(t, `{"ok":true}`, string(response))
+}
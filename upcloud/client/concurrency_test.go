@@ -0,0 +1,64 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestConcurrentRequestOptions tests that per-request options only apply to their own request while the client
+// settings are changed concurrently. Run it with the race detector enabled.
+func TestConcurrentRequestOptions(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Slow") != "" {
+			time.Sleep(500 * time.Millisecond)
+		}
+		w.Write([]byte(r.Header.Get("Content-Type")))
+	}))
+	defer srv.Close()
+
+	c := NewWithOptions("username", "password", WithTimeout(200*time.Millisecond))
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(4)
+		go func() {
+			defer wg.Done()
+			response, err := c.PerformJSONPutUploadRequestWithContext(context.Background(), srv.URL, nil,
+				WithRequestContentType("application/octet-stream"),
+				WithRequestHeader("X-Slow", "yes"),
+				WithRequestTimeout(2*time.Second))
+			if assert.NoError(t, err) {
+				assert.Equal(t, "application/octet-stream", string(response))
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			response, err := c.PerformJSONGetRequest(srv.URL)
+			if assert.NoError(t, err) {
+				assert.Equal(t, "application/json", string(response))
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			_, err := c.PerformJSONGetRequestWithContext(context.Background(), srv.URL, WithRequestHeader("X-Slow", "yes"))
+			assert.Error(t, err)
+		}()
+		go func() {
+			defer wg.Done()
+			c.SetRetryPolicy(nil)
+			c.SetRateLimiter(nil)
+			c.SetMaxConcurrentRequests(0)
+			c.SetTimeout(200 * time.Millisecond)
+			_ = c.GetContentType()
+		}()
+	}
+	wg.Wait()
+
+	assert.Equal(t, 200*time.Millisecond, c.GetTimeout())
+}
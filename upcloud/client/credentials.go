@@ -0,0 +1,316 @@
+package client
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ErrNoCredentials is returned by a CredentialsProvider that has no credentials to provide, e.g. because the
+// environment variables it reads aren't set. ChainCredentials moves on to the next provider on this error.
+var ErrNoCredentials = errors.New("no credentials")
+
+// Credentials are the username and password used to authenticate to the API
+type Credentials struct {
+	Username string `json:"username"`
+	Password string `json:"password"`
+}
+
+// CredentialsProvider provides the credentials of a client. The client asks for the credentials before every
+// request, so a provider can pick up rotated credentials without the client being rebuilt. Implementations must be
+// safe for concurrent use.
+type CredentialsProvider interface {
+	Credentials(ctx context.Context) (Credentials, error)
+}
+
+// CredentialsInvalidator is implemented by providers that cache credentials. The client invalidates the cached
+// credentials when the API rejects them and retries the request once with the credentials provided next.
+type CredentialsInvalidator interface {
+	InvalidateCredentials()
+}
+
+// CredentialsProviderFunc adapts a function to the CredentialsProvider interface
+type CredentialsProviderFunc func(ctx context.Context) (Credentials, error)
+
+// Credentials implements the CredentialsProvider interface
+func (f CredentialsProviderFunc) Credentials(ctx context.Context) (Credentials, error) {
+	return f(ctx)
+}
+
+// StaticCredentials returns a provider of fixed credentials
+func StaticCredentials(username, password string) CredentialsProvider {
+	return CredentialsProviderFunc(func(context.Context) (Credentials, error) {
+		return Credentials{Username: username, Password: password}, nil
+	})
+}
+
+// Environment variables read by EnvCredentials and DefaultCredentials
+const (
+	EnvUsername        = "UPCLOUD_USERNAME"
+	EnvPassword        = "UPCLOUD_PASSWORD"
+	EnvCredentialsFile = "UPCLOUD_CREDENTIALS_FILE"
+	EnvProfile         = "UPCLOUD_PROFILE"
+)
+
+// EnvCredentials returns a provider reading the credentials from the UPCLOUD_USERNAME and UPCLOUD_PASSWORD
+// environment variables, the same ones the example CLI reads. It fails with ErrNoCredentials unless both are set.
+func EnvCredentials() CredentialsProvider {
+	return CredentialsProviderFunc(func(context.Context) (Credentials, error) {
+		credentials := Credentials{Username: os.Getenv(EnvUsername), Password: os.Getenv(EnvPassword)}
+		if credentials.Username == "" || credentials.Password == "" {
+			return Credentials{}, fmt.Errorf("%s and %s: %w", EnvUsername, EnvPassword, ErrNoCredentials)
+		}
+
+		return credentials, nil
+	})
+}
+
+// DefaultCredentialsFile returns the path of the credentials file read by DefaultCredentials: the value of
+// UPCLOUD_CREDENTIALS_FILE if it's set and upcloud/credentials in the user's configuration directory otherwise,
+// e.g. ~/.config/upcloud/credentials on Linux
+func DefaultCredentialsFile() string {
+	if path := os.Getenv(EnvCredentialsFile); path != "" {
+		return path
+	}
+
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(dir, "upcloud", "credentials")
+}
+
+// FileCredentials returns a provider reading the credentials of a profile from a file with a section per profile:
+//
+//	[default]
+//	username = user
+//	password = secret
+//
+//	[staging]
+//	username = staging-user
+//	password = staging-secret
+//
+// Lines starting with # or ; are comments. An empty profile selects the one in UPCLOUD_PROFILE, or "default". The
+// file is read again whenever it changes. The provider fails with ErrNoCredentials if the file or the profile don't
+// exist.
+func FileCredentials(path, profile string) CredentialsProvider {
+	return &fileCredentials{path: path, profile: profile}
+}
+
+// fileCredentials is a provider reading a credentials file
+type fileCredentials struct {
+	path    string
+	profile string
+
+	mu       sync.Mutex
+	modTime  time.Time
+	size     int64
+	profiles map[string]Credentials
+}
+
+// Credentials implements the CredentialsProvider interface
+func (p *fileCredentials) Credentials(ctx context.Context) (Credentials, error) {
+	profile := p.profile
+	if profile == "" {
+		profile = os.Getenv(EnvProfile)
+	}
+	if profile == "" {
+		profile = "default"
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	info, err := os.Stat(p.path)
+	if os.IsNotExist(err) {
+		return Credentials{}, fmt.Errorf("%s: %w", p.path, ErrNoCredentials)
+	}
+	if err != nil {
+		return Credentials{}, err
+	}
+
+	if p.profiles == nil || !info.ModTime().Equal(p.modTime) || info.Size() != p.size {
+		profiles, err := readCredentialsFile(p.path)
+		if err != nil {
+			return Credentials{}, err
+		}
+		p.profiles, p.modTime, p.size = profiles, info.ModTime(), info.Size()
+	}
+
+	credentials, ok := p.profiles[profile]
+	if !ok {
+		return Credentials{}, fmt.Errorf("profile %q in %s: %w", profile, p.path, ErrNoCredentials)
+	}
+	if credentials.Username == "" || credentials.Password == "" {
+		return Credentials{}, fmt.Errorf("profile %q in %s is missing the username or password", profile, p.path)
+	}
+
+	return credentials, nil
+}
+
+// Parses a credentials file into the credentials of each profile
+func readCredentialsFile(path string) (map[string]Credentials, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	profiles := make(map[string]Credentials)
+	profile := ""
+	scanner := bufio.NewScanner(f)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		switch {
+		case text == "" || strings.HasPrefix(text, "#") || strings.HasPrefix(text, ";"):
+			continue
+		case strings.HasPrefix(text, "[") && strings.HasSuffix(text, "]"):
+			profile = strings.TrimSpace(text[1 : len(text)-1])
+			profiles[profile] = Credentials{}
+			continue
+		}
+
+		parts := strings.SplitN(text, "=", 2)
+		if len(parts) != 2 || profile == "" {
+			return nil, fmt.Errorf("%s:%d: expected a [profile] or a key = value line", path, line)
+		}
+
+		credentials := profiles[profile]
+		switch key, value := strings.TrimSpace(parts[0]), strings.TrimSpace(parts[1]); key {
+		case "username":
+			credentials.Username = value
+		case "password":
+			credentials.Password = value
+		default:
+			return nil, fmt.Errorf("%s:%d: unknown key %q", path, line, key)
+		}
+		profiles[profile] = credentials
+	}
+
+	return profiles, scanner.Err()
+}
+
+// CommandCredentials returns a provider running an external command, e.g. a password manager, that prints the
+// credentials as a JSON object with username and password fields. The command is run on every request, so it should
+// usually be wrapped with CachedCredentials.
+func CommandCredentials(name string, args ...string) CredentialsProvider {
+	return CredentialsProviderFunc(func(ctx context.Context) (Credentials, error) {
+		var stdout, stderr bytes.Buffer
+		cmd := exec.CommandContext(ctx, name, args...)
+		cmd.Stdout = &stdout
+		cmd.Stderr = &stderr
+
+		if err := cmd.Run(); err != nil {
+			return Credentials{}, fmt.Errorf("running %s: %w: %s", name, err, strings.TrimSpace(stderr.String()))
+		}
+
+		var credentials Credentials
+		if err := json.Unmarshal(stdout.Bytes(), &credentials); err != nil {
+			return Credentials{}, fmt.Errorf("parsing the output of %s: %w", name, err)
+		}
+		if credentials.Username == "" || credentials.Password == "" {
+			return Credentials{}, fmt.Errorf("the output of %s is missing the username or password", name)
+		}
+
+		return credentials, nil
+	})
+}
+
+// ChainCredentials returns a provider asking the providers in order and returning the credentials of the first one
+// that has them. Providers failing with ErrNoCredentials are skipped, other errors are returned immediately.
+func ChainCredentials(providers ...CredentialsProvider) CredentialsProvider {
+	return chainCredentials(providers)
+}
+
+// chainCredentials is a provider asking several providers in order
+type chainCredentials []CredentialsProvider
+
+// Credentials implements the CredentialsProvider interface
+func (c chainCredentials) Credentials(ctx context.Context) (Credentials, error) {
+	var reasons []string
+	for _, provider := range c {
+		credentials, err := provider.Credentials(ctx)
+		if err == nil {
+			return credentials, nil
+		}
+		if !errors.Is(err, ErrNoCredentials) {
+			return Credentials{}, err
+		}
+		reasons = append(reasons, strings.TrimSuffix(err.Error(), ": "+ErrNoCredentials.Error()))
+	}
+
+	return Credentials{}, fmt.Errorf("%w found in %s", ErrNoCredentials, strings.Join(reasons, ", "))
+}
+
+// InvalidateCredentials implements the CredentialsInvalidator interface by invalidating the providers that cache
+// credentials
+func (c chainCredentials) InvalidateCredentials() {
+	for _, provider := range c {
+		if invalidator, ok := provider.(CredentialsInvalidator); ok {
+			invalidator.InvalidateCredentials()
+		}
+	}
+}
+
+// DefaultCredentials returns a provider of the credentials in the UPCLOUD_USERNAME and UPCLOUD_PASSWORD
+// environment variables, falling back to the profile in UPCLOUD_PROFILE, or "default", in the
+// DefaultCredentialsFile
+func DefaultCredentials() CredentialsProvider {
+	return ChainCredentials(EnvCredentials(), FileCredentials(DefaultCredentialsFile(), ""))
+}
+
+// CachedCredentials wraps a provider so that the credentials it provides are reused for the specified time. A zero
+// ttl reuses them until they're invalidated, which the client does when the API rejects them.
+func CachedCredentials(provider CredentialsProvider, ttl time.Duration) *CachedCredentialsProvider {
+	return &CachedCredentialsProvider{provider: provider, ttl: ttl}
+}
+
+// CachedCredentialsProvider is a provider reusing the credentials of another provider, see CachedCredentials
+type CachedCredentialsProvider struct {
+	provider CredentialsProvider
+	ttl      time.Duration
+
+	mu          sync.Mutex
+	credentials *Credentials
+	expires     time.Time
+}
+
+// Credentials implements the CredentialsProvider interface
+func (p *CachedCredentialsProvider) Credentials(ctx context.Context) (Credentials, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.credentials != nil && (p.ttl == 0 || time.Now().Before(p.expires)) {
+		return *p.credentials, nil
+	}
+
+	credentials, err := p.provider.Credentials(ctx)
+	if err != nil {
+		return Credentials{}, err
+	}
+	p.credentials = &credentials
+	p.expires = time.Now().Add(p.ttl)
+
+	return credentials, nil
+}
+
+// InvalidateCredentials implements the CredentialsInvalidator interface. The next request for credentials asks the
+// wrapped provider again.
+func (p *CachedCredentialsProvider) InvalidateCredentials() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.credentials = nil
+	if invalidator, ok := p.provider.(CredentialsInvalidator); ok {
+		invalidator.InvalidateCredentials()
+	}
+}
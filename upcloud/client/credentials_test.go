@@ -0,0 +1,240 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Sets an environment variable for the duration of the test
+func setenv(t *testing.T, key, value string) {
+	previous, ok := os.LookupEnv(key)
+	require.NoError(t, os.Setenv(key, value))
+	t.Cleanup(func() {
+		if ok {
+			os.Setenv(key, previous)
+		} else {
+			os.Unsetenv(key)
+		}
+	})
+}
+
+// Writes a file in a temporary directory that's removed when the test completes
+func writeTempFile(t *testing.T, content string) string {
+	dir, err := ioutil.TempDir("", "credentials")
+	require.NoError(t, err)
+	t.Cleanup(func() { os.RemoveAll(dir) })
+
+	path := filepath.Join(dir, "credentials")
+	require.NoError(t, ioutil.WriteFile(path, []byte(content), 0600))
+	return path
+}
+
+// TestEnvCredentials tests reading the credentials from the environment
+func TestEnvCredentials(t *testing.T) {
+	setenv(t, EnvUsername, "env-user")
+	setenv(t, EnvPassword, "")
+
+	provider := EnvCredentials()
+	_, err := provider.Credentials(context.Background())
+	assert.True(t, errors.Is(err, ErrNoCredentials))
+
+	// The variables are read on every call
+	setenv(t, EnvPassword, "env-pass")
+	credentials, err := provider.Credentials(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, Credentials{Username: "env-user", Password: "env-pass"}, credentials)
+}
+
+// TestFileCredentials tests reading the credentials of profiles from a file
+func TestFileCredentials(t *testing.T) {
+	path := writeTempFile(t, `# UpCloud credentials
+[default]
+username = default-user
+password = pass = word
+
+; A second account
+[staging]
+username=staging-user
+password=staging-pass
+`)
+
+	credentials, err := FileCredentials(path, "").Credentials(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, Credentials{Username: "default-user", Password: "pass = word"}, credentials)
+
+	setenv(t, EnvProfile, "staging")
+	provider := FileCredentials(path, "")
+	credentials, err = provider.Credentials(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, Credentials{Username: "staging-user", Password: "staging-pass"}, credentials)
+
+	// A rotated password is picked up
+	require.NoError(t, ioutil.WriteFile(path, []byte("[staging]\nusername = staging-user\npassword = rotated-pass\n"), 0600))
+	credentials, err = provider.Credentials(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, "rotated-pass", credentials.Password)
+
+	_, err = FileCredentials(path, "production").Credentials(context.Background())
+	assert.True(t, errors.Is(err, ErrNoCredentials))
+
+	_, err = FileCredentials(path+".missing", "").Credentials(context.Background())
+	assert.True(t, errors.Is(err, ErrNoCredentials))
+
+	invalid := writeTempFile(t, "[default]\ntoken = abc\n")
+	_, err = FileCredentials(invalid, "").Credentials(context.Background())
+	assert.EqualError(t, err, invalid+`:2: unknown key "token"`)
+
+	_, err = FileCredentials(writeTempFile(t, "[default]\nusername = user\n"), "default").Credentials(context.Background())
+	assert.Error(t, err)
+	assert.False(t, errors.Is(err, ErrNoCredentials))
+}
+
+// TestHelperCredentialsCommand isn't a real test but the command run by TestCommandCredentials
+func TestHelperCredentialsCommand(t *testing.T) {
+	output := os.Getenv("UPCLOUD_GO_SDK_TEST_CREDENTIALS_OUTPUT")
+	if output == "" {
+		return
+	}
+	if output == "fail" {
+		fmt.Fprint(os.Stderr, "vault is sealed")
+		os.Exit(1)
+	}
+
+	fmt.Print(output)
+	os.Exit(0)
+}
+
+// TestCommandCredentials tests reading the credentials from the output of a command
+func TestCommandCredentials(t *testing.T) {
+	provider := CommandCredentials(os.Args[0], "-test.run=TestHelperCredentialsCommand")
+
+	setenv(t, "UPCLOUD_GO_SDK_TEST_CREDENTIALS_OUTPUT", `{"username":"cmd-user","password":"cmd-pass"}`)
+	credentials, err := provider.Credentials(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, Credentials{Username: "cmd-user", Password: "cmd-pass"}, credentials)
+
+	setenv(t, "UPCLOUD_GO_SDK_TEST_CREDENTIALS_OUTPUT", "fail")
+	_, err = provider.Credentials(context.Background())
+	assert.Contains(t, err.Error(), "vault is sealed")
+
+	setenv(t, "UPCLOUD_GO_SDK_TEST_CREDENTIALS_OUTPUT", `{"username":"cmd-user"}`)
+	_, err = provider.Credentials(context.Background())
+	assert.Contains(t, err.Error(), "missing the username or password")
+}
+
+// TestChainCredentials tests that the first provider with credentials is used
+func TestChainCredentials(t *testing.T) {
+	none := CredentialsProviderFunc(func(context.Context) (Credentials, error) {
+		return Credentials{}, fmt.Errorf("vault: %w", ErrNoCredentials)
+	})
+	failing := CredentialsProviderFunc(func(context.Context) (Credentials, error) {
+		return Credentials{}, errors.New("vault is sealed")
+	})
+
+	credentials, err := ChainCredentials(none, StaticCredentials("user", "pass"), failing).Credentials(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, Credentials{Username: "user", Password: "pass"}, credentials)
+
+	_, err = ChainCredentials(none, failing, StaticCredentials("user", "pass")).Credentials(context.Background())
+	assert.EqualError(t, err, "vault is sealed")
+
+	_, err = ChainCredentials(none, none).Credentials(context.Background())
+	assert.True(t, errors.Is(err, ErrNoCredentials))
+	assert.EqualError(t, err, "no credentials found in vault, vault")
+}
+
+// TestCachedCredentials tests that credentials are reused until they expire or are invalidated
+func TestCachedCredentials(t *testing.T) {
+	var calls int32
+	counting := CredentialsProviderFunc(func(context.Context) (Credentials, error) {
+		n := atomic.AddInt32(&calls, 1)
+		return Credentials{Username: "user", Password: fmt.Sprintf("pass-%d", n)}, nil
+	})
+
+	cached := CachedCredentials(counting, 0)
+	for i := 0; i < 3; i++ {
+		credentials, err := cached.Credentials(context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, "pass-1", credentials.Password)
+	}
+
+	ChainCredentials(cached).(CredentialsInvalidator).InvalidateCredentials()
+	credentials, err := cached.Credentials(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, "pass-2", credentials.Password)
+
+	expiring := CachedCredentials(counting, time.Millisecond)
+	_, err = expiring.Credentials(context.Background())
+	require.NoError(t, err)
+	time.Sleep(5 * time.Millisecond)
+	credentials, err = expiring.Credentials(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, "pass-4", credentials.Password)
+}
+
+// TestClientCredentialsRefresh tests that the client resolves its credentials lazily and retries requests
+// rejected with 401 Unauthorized with refreshed credentials
+func TestClientCredentialsRefresh(t *testing.T) {
+	var password atomic.Value
+	password.Store("old-pass")
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		username, pass, _ := r.BasicAuth()
+		body, _ := ioutil.ReadAll(r.Body)
+		if username != "user" || pass != password.Load().(string) {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.Write(body)
+	}))
+	defer srv.Close()
+
+	var stored atomic.Value
+	stored.Store("old-pass")
+	var calls int32
+	provider := CachedCredentials(CredentialsProviderFunc(func(context.Context) (Credentials, error) {
+		atomic.AddInt32(&calls, 1)
+		return Credentials{Username: "user", Password: stored.Load().(string)}, nil
+	}), 0)
+
+	c := NewWithCredentials(provider, WithBaseURL(srv.URL))
+	assert.Equal(t, int32(0), atomic.LoadInt32(&calls))
+
+	body, err := c.PerformJSONPostRequest(c.CreateRequestURL("/server"), []byte(`{"rotated":false}`))
+	require.NoError(t, err)
+	assert.Equal(t, `{"rotated":false}`, string(body))
+	assert.Equal(t, int32(1), atomic.LoadInt32(&calls))
+
+	// The password is rotated in the API and then in the store
+	password.Store("new-pass")
+	stored.Store("new-pass")
+	body, err = c.PerformJSONPostRequest(c.CreateRequestURL("/server"), []byte(`{"rotated":true}`))
+	require.NoError(t, err)
+	assert.Equal(t, `{"rotated":true}`, string(body))
+	assert.Equal(t, int32(2), atomic.LoadInt32(&calls))
+
+	// Credentials that stay rejected fail the request
+	password.Store("newer-pass")
+	_, err = c.PerformJSONGetRequest(c.CreateRequestURL("/account"))
+	var clientError *Error
+	require.True(t, errors.As(err, &clientError))
+	assert.Equal(t, http.StatusUnauthorized, clientError.ErrorCode)
+
+	c.SetCredentialsProvider(CredentialsProviderFunc(func(context.Context) (Credentials, error) {
+		return Credentials{}, ErrNoCredentials
+	}))
+	_, err = c.PerformJSONGetRequest(c.CreateRequestURL("/account"))
+	assert.True(t, errors.Is(err, ErrNoCredentials))
+	assert.EqualError(t, err, "resolving credentials: no credentials")
+}
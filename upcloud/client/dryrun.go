@@ -0,0 +1,104 @@
+package client
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+)
+
+// Plan collects the requests a client in dry-run mode didn't send, see SetDryRun. The zero value is an empty plan
+// ready to use. A Plan is safe for concurrent use.
+type Plan struct {
+	mu       sync.Mutex
+	requests []PlannedRequest
+}
+
+// PlannedRequest is a request that would have changed something but wasn't sent because the client was in dry-run
+// mode
+type PlannedRequest struct {
+	Method string `json:"method"`
+	URL    string `json:"url"`
+	// Type is the type of the request the body was encoded from, e.g. "request.CreateServerRequest". It's empty for
+	// requests made with the client directly rather than through the service.
+	Type string `json:"type,omitempty"`
+	// Body is the JSON request body, nil for requests without one or with a body that isn't JSON, e.g. uploads.
+	// Unlike the plan's String method, it contains any passwords the request sets.
+	Body json.RawMessage `json:"body,omitempty"`
+	// Request is the request the body was encoded from, nil for requests made with the client directly
+	Request interface{} `json:"-"`
+	// Result is the placeholder returned in place of the response, nil for requests without a result and requests
+	// made with the client directly
+	Result interface{} `json:"-"`
+}
+
+// Add adds a request to the plan and returns its number in the plan, starting from 1. The client adds the requests it
+// doesn't send by itself, the service adds them along with their types and placeholder results.
+func (p *Plan) Add(r PlannedRequest) int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.requests = append(p.requests, r)
+	return len(p.requests)
+}
+
+// Requests returns the planned requests in the order they were made
+func (p *Plan) Requests() []PlannedRequest {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return append([]PlannedRequest(nil), p.requests...)
+}
+
+// Reset removes all the requests from the plan
+func (p *Plan) Reset() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.requests = nil
+}
+
+// String returns the planned requests as numbered lines with passwords and keys redacted from the bodies, e.g.
+//
+//  1. POST https://api.upcloud.com/1.3/server (request.CreateServerRequest) {"server":{"title":"web-1",...}}
+//  2. DELETE https://api.upcloud.com/1.3/tag/DEV (request.DeleteTagRequest)
+func (p *Plan) String() string {
+	var b strings.Builder
+	for i, r := range p.Requests() {
+		fmt.Fprintf(&b, "%d. %s %s", i+1, r.Method, r.URL)
+		if r.Type != "" {
+			fmt.Fprintf(&b, " (%s)", r.Type)
+		}
+		if len(r.Body) > 0 {
+			fmt.Fprintf(&b, " %s", redactedLogFields.ReplaceAllString(string(r.Body), `${1}"`+redacted+`"`))
+		}
+		b.WriteString("\n")
+	}
+
+	return b.String()
+}
+
+// Returns whether a request with the method only reads and is sent in dry-run mode
+func isReadOnly(method string) bool {
+	return method == http.MethodGet || method == http.MethodHead || method == http.MethodOptions
+}
+
+// Adds a request that isn't sent in dry-run mode to the plan and logs it
+func (c *requestConfig) planRequest(request *http.Request) {
+	planned := PlannedRequest{
+		Method: request.Method,
+		URL:    request.URL.String(),
+	}
+	if body := requestBody(request); len(body) > 0 && json.Valid(body) {
+		planned.Body = body
+	}
+	c.dryRun.Add(planned)
+
+	if c.logger != nil {
+		c.logger.Log(request.Context(), LogLevelInfo, "request planned",
+			LogField{Key: "method", Value: request.Method},
+			LogField{Key: "url", Value: logURL(request.URL)},
+		)
+	}
+}
@@ -0,0 +1,83 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestDryRun tests that a client in dry-run mode sends GET requests and plans the rest
+func TestDryRun(t *testing.T) {
+	var mu sync.Mutex
+	var methods []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		methods = append(methods, r.Method)
+		mu.Unlock()
+		w.Write([]byte(`{"zones":{"zone":[]}}`))
+	}))
+	defer srv.Close()
+
+	var logged []string
+	logger := LoggerFunc(func(_ context.Context, level LogLevel, msg string, fields ...LogField) {
+		logged = append(logged, msg)
+	})
+
+	plan := &Plan{}
+	c := NewWithOptions("user", "pass", WithBaseURL(srv.URL), WithDryRun(plan), WithLogger(logger))
+	assert.Equal(t, plan, c.GetDryRun())
+
+	body, err := c.PerformJSONGetRequest(c.CreateRequestURL("/zone"))
+	require.NoError(t, err)
+	assert.Equal(t, `{"zones":{"zone":[]}}`, string(body))
+
+	body, err = c.PerformJSONPostRequest(c.CreateRequestURL("/server"), []byte(`{"server":{"title":"web","login_user":{"create_password":"yes"}}}`))
+	require.NoError(t, err)
+	assert.Empty(t, body)
+	_, err = c.PerformJSONPutRequest(c.CreateRequestURL("/server/0077fa3d-32db-4b09-9f5f-30d9e9afb565"), []byte(`{"server":{"title":"db"}}`))
+	require.NoError(t, err)
+	_, err = c.PerformJSONPatchRequest(c.CreateRequestURL("/network/03e4970d-7791-4b80-a892-682ae0faf46b"), []byte(`{}`))
+	require.NoError(t, err)
+	require.NoError(t, c.PerformJSONDeleteRequest(c.CreateRequestURL("/tag/DEV")))
+	_, err = c.PerformJSONPutUploadRequest(srv.URL+"/uploader/session/01d2f6d8-6e8f-4c18-b1b6-ab2f2a1c8c6a", strings.NewReader("image"))
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{http.MethodGet}, methods)
+	assert.Equal(t, []string{"request completed", "request planned", "request planned", "request planned", "request planned", "request planned"}, logged)
+
+	requests := plan.Requests()
+	require.Len(t, requests, 5)
+	assert.Equal(t, PlannedRequest{
+		Method: http.MethodPost,
+		URL:    srv.URL + "/1.3/server",
+		Body:   json.RawMessage(`{"server":{"title":"web","login_user":{"create_password":"yes"}}}`),
+	}, requests[0])
+	assert.Equal(t, http.MethodDelete, requests[3].Method)
+	assert.Empty(t, requests[3].Body)
+
+	assert.Equal(t, "1. POST "+srv.URL+`/1.3/server {"server":{"title":"web","login_user":{"create_password":"[REDACTED]"}}}
+2. PUT `+srv.URL+`/1.3/server/0077fa3d-32db-4b09-9f5f-30d9e9afb565 {"server":{"title":"db"}}
+3. PATCH `+srv.URL+`/1.3/network/03e4970d-7791-4b80-a892-682ae0faf46b {}
+4. DELETE `+srv.URL+`/1.3/tag/DEV
+5. PUT `+srv.URL+`/uploader/session/01d2f6d8-6e8f-4c18-b1b6-ab2f2a1c8c6a
+`, plan.String())
+
+	data, err := json.Marshal(requests[3])
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"method":"DELETE","url":"`+srv.URL+`/1.3/tag/DEV"}`, string(data))
+
+	plan.Reset()
+	assert.Empty(t, plan.Requests())
+
+	c.SetDryRun(nil)
+	require.NoError(t, c.PerformJSONDeleteRequest(c.CreateRequestURL("/tag/DEV")))
+	assert.Equal(t, []string{http.MethodGet, http.MethodDelete}, methods)
+	assert.Empty(t, plan.Requests())
+}
@@ -7,6 +7,9 @@ type Error struct {
 	ErrorCode    int
 	ErrorMessage string
 	ResponseBody []byte
+	// Method and URL identify the request that failed
+	Method string
+	URL    string
 }
 
 // Error implements the Error interface
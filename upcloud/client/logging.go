@@ -0,0 +1,267 @@
+package client
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// LogLevel is the severity of a log entry
+type LogLevel int
+
+// Log levels in increasing order of severity
+const (
+	LogLevelDebug LogLevel = iota
+	LogLevelInfo
+	LogLevelWarn
+	LogLevelError
+)
+
+// String implements the Stringer interface
+func (l LogLevel) String() string {
+	switch l {
+	case LogLevelDebug:
+		return "debug"
+	case LogLevelInfo:
+		return "info"
+	case LogLevelWarn:
+		return "warn"
+	case LogLevelError:
+		return "error"
+	}
+
+	return fmt.Sprintf("LogLevel(%d)", int(l))
+}
+
+// LogField is a key-value pair attached to a log entry
+type LogField struct {
+	Key   string
+	Value interface{}
+}
+
+// Logger receives the log entries of a client. Each entry has a level, a message and fields describing the
+// request, see SetLogger. Implementations must be safe for concurrent use.
+type Logger interface {
+	Log(ctx context.Context, level LogLevel, msg string, fields ...LogField)
+}
+
+// LoggerFunc adapts a function to the Logger interface
+type LoggerFunc func(ctx context.Context, level LogLevel, msg string, fields ...LogField)
+
+// Log implements the Logger interface
+func (f LoggerFunc) Log(ctx context.Context, level LogLevel, msg string, fields ...LogField) {
+	f(ctx, level, msg, fields...)
+}
+
+// textLogger writes log entries as lines of key=value pairs
+type textLogger struct {
+	mu    sync.Mutex
+	w     io.Writer
+	level LogLevel
+}
+
+// NewTextLogger returns a logger writing the entries of the specified level and above to w, one line of key=value
+// pairs per entry, e.g.
+//
+//	time=2020-10-26T12:00:00Z level=info msg="request completed" request_id=6f1c9a0e2b4d8c17 method=GET ...
+func NewTextLogger(w io.Writer, level LogLevel) Logger {
+	return &textLogger{w: w, level: level}
+}
+
+// Log implements the Logger interface
+func (l *textLogger) Log(ctx context.Context, level LogLevel, msg string, fields ...LogField) {
+	if level < l.level {
+		return
+	}
+
+	var b strings.Builder
+	b.WriteString("time=" + time.Now().UTC().Format(time.RFC3339Nano))
+	b.WriteString(" level=" + level.String())
+	b.WriteString(" msg=" + logfmtValue(msg))
+	for _, field := range fields {
+		b.WriteString(" " + field.Key + "=" + logfmtValue(fmt.Sprint(field.Value)))
+	}
+	b.WriteString("\n")
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	_, _ = io.WriteString(l.w, b.String())
+}
+
+// Returns the value quoted if it contains characters that would make the line ambiguous
+func logfmtValue(value string) string {
+	if value == "" || strings.ContainsAny(value, " =\"\\\n\t") {
+		return strconv.Quote(value)
+	}
+
+	return value
+}
+
+// The JSON fields whose values are redacted from logged bodies: passwords, whether a password is created for the
+// login user and Object Storage keys
+var redactedLogFields = regexp.MustCompile(
+	`("(?:password|remote_access_password|create_password|access_key|secret_key)"\s*:\s*)"(?:[^"\\]|\\.)*"`)
+
+// Replaces redacted values in logs
+const redacted = "[REDACTED]"
+
+// Returns the body for a log entry: JSON bodies are redacted and cut to maxSize, other bodies are only described
+func logBody(body []byte, contentType string, maxSize int) string {
+	if len(body) == 0 {
+		return ""
+	}
+	if !strings.Contains(contentType, "json") {
+		return fmt.Sprintf("[%d bytes of %s]", len(body), contentType)
+	}
+
+	s := redactedLogFields.ReplaceAllString(string(body), `${1}"`+redacted+`"`)
+	if len(s) > maxSize {
+		s = s[:maxSize] + "...[truncated]"
+	}
+	return s
+}
+
+// Returns the URL for a log entry with the password of any credentials it includes replaced by "xxxxx", like
+// url.URL.Redacted in newer Go versions
+func logURL(u *url.URL) string {
+	if u.User == nil {
+		return u.String()
+	}
+	if _, ok := u.User.Password(); !ok {
+		return u.String()
+	}
+
+	redactedURL := *u
+	redactedURL.User = url.UserPassword(u.User.Username(), "xxxxx")
+	return redactedURL.String()
+}
+
+// Returns the headers for a log entry with the credentials redacted
+func logHeaders(header http.Header) string {
+	keys := make([]string, 0, len(header))
+	for key := range header {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	parts := make([]string, 0, len(keys))
+	for _, key := range keys {
+		value := strings.Join(header[key], ",")
+		if strings.EqualFold(key, "Authorization") || strings.EqualFold(key, "Proxy-Authorization") {
+			value = redacted
+			if scheme := strings.Fields(header.Get(key)); len(scheme) > 1 {
+				value = scheme[0] + " " + redacted
+			}
+		}
+		parts = append(parts, key+": "+value)
+	}
+
+	return strings.Join(parts, "; ")
+}
+
+// Returns a random ID identifying a request and its retries in the logs
+func newRequestID() string {
+	var id [8]byte
+	if _, err := rand.Read(id[:]); err != nil {
+		return strconv.FormatInt(time.Now().UnixNano(), 16)
+	}
+
+	return hex.EncodeToString(id[:])
+}
+
+// Returns the request body for a log entry. Bodies that can't be read again, e.g. uploads, are only described.
+func requestBody(request *http.Request) []byte {
+	if request.GetBody == nil {
+		return nil
+	}
+
+	body, err := request.GetBody()
+	if err != nil {
+		return nil
+	}
+	defer body.Close()
+
+	data, _ := ioutil.ReadAll(body)
+	return data
+}
+
+// Logs an attempt to perform a request. Responses with an error status are logged as warnings and requests that
+// got no response as errors.
+func (c *requestConfig) logAttempt(request *http.Request, requestID string, attempt int, latency time.Duration,
+	response *http.Response, responseBody []byte, err error) {
+	if c.logger == nil {
+		return
+	}
+
+	fields := []LogField{
+		{Key: "request_id", Value: requestID},
+		{Key: "method", Value: request.Method},
+		{Key: "url", Value: logURL(request.URL)},
+		{Key: "attempt", Value: attempt},
+	}
+
+	level, msg := LogLevelInfo, "request completed"
+	if response != nil {
+		fields = append(fields, LogField{Key: "status", Value: response.StatusCode})
+		if response.StatusCode >= 400 {
+			level = LogLevelWarn
+		}
+	} else {
+		level, msg = LogLevelError, "request failed"
+	}
+	fields = append(fields, LogField{Key: "latency", Value: latency})
+	if err != nil {
+		fields = append(fields, LogField{Key: "error", Value: err.Error()})
+	}
+
+	if c.logBodySize > 0 {
+		fields = append(fields, LogField{Key: "request_headers", Value: logHeaders(request.Header)})
+
+		if body := requestBody(request); body != nil {
+			fields = append(fields, LogField{
+				Key:   "request_body",
+				Value: logBody(body, request.Header.Get("Content-Type"), c.logBodySize),
+			})
+		}
+
+		var clientError *Error
+		if errors.As(err, &clientError) {
+			responseBody = clientError.ResponseBody
+		}
+		if response != nil && len(responseBody) > 0 {
+			fields = append(fields, LogField{
+				Key:   "response_body",
+				Value: logBody(responseBody, response.Header.Get("Content-Type"), c.logBodySize),
+			})
+		}
+	}
+
+	c.logger.Log(request.Context(), level, msg, fields...)
+}
+
+// Logs that a request is about to be retried
+func (c *requestConfig) logRetry(request *http.Request, requestID string, event RetryEvent) {
+	if c.logger == nil {
+		return
+	}
+
+	c.logger.Log(request.Context(), LogLevelWarn, "retrying request",
+		LogField{Key: "request_id", Value: requestID},
+		LogField{Key: "method", Value: event.Method},
+		LogField{Key: "url", Value: logURL(request.URL)},
+		LogField{Key: "attempt", Value: event.Attempt},
+		LogField{Key: "delay", Value: event.Delay},
+	)
+}
@@ -0,0 +1,102 @@
+package client
+
+import (
+	"net/http"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// Metrics receives measurements of the requests performed by a client, e.g. to export them to a monitoring system.
+// Implementations must be safe for concurrent use.
+type Metrics interface {
+	// ObserveRequest is called after every attempt to perform a request
+	ObserveRequest(RequestObservation)
+	// ObserveRetry is called before a failed attempt is retried
+	ObserveRetry(RetryObservation)
+}
+
+// RequestObservation describes an attempt to perform a request
+type RequestObservation struct {
+	Method string
+	// Endpoint is the path of the request relative to the API version with the UUIDs and IP addresses replaced by
+	// placeholders, e.g. "/server/{uuid}/start", to keep the number of distinct endpoints small
+	Endpoint string
+	// StatusCode is the HTTP status of the response, or 0 if no response was received
+	StatusCode int
+	// Duration is the time from sending the request to reading the response body
+	Duration time.Duration
+	// Attempt is the number of the attempt, starting from 1
+	Attempt int
+	// Err is the error of the attempt, if any
+	Err error
+}
+
+// RetryObservation describes a failed attempt that is about to be retried
+type RetryObservation struct {
+	Method   string
+	Endpoint string
+	RetryEvent
+}
+
+var (
+	// Matches the API version at the start of a request path, e.g. "/1.3"
+	apiVersionPath = regexp.MustCompile(`^/[0-9]+\.[0-9]+(/|$)`)
+	uuidSegment    = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+	ipv4Segment    = regexp.MustCompile(`^[0-9]{1,3}(\.[0-9]{1,3}){3}$`)
+	ipv6Segment    = regexp.MustCompile(`^[0-9a-fA-F]*:[0-9a-fA-F:]+$`)
+)
+
+// Returns the endpoint of a request path for metrics, e.g. "/server/{uuid}/start" for
+// "/1.3/server/0077fa3d-32db-4b09-9f5f-30d9e9afb565/start"
+func metricsEndpoint(path string) string {
+	if loc := apiVersionPath.FindStringIndex(path); loc != nil {
+		path = "/" + path[loc[1]:]
+	}
+
+	segments := strings.Split(path, "/")
+	for i, segment := range segments {
+		switch {
+		case uuidSegment.MatchString(segment):
+			segments[i] = "{uuid}"
+		case ipv4Segment.MatchString(segment), ipv6Segment.MatchString(segment):
+			segments[i] = "{ip}"
+		}
+	}
+
+	return strings.Join(segments, "/")
+}
+
+// Reports an attempt to perform a request to the metrics
+func (c *requestConfig) observeAttempt(request *http.Request, attempt int, duration time.Duration,
+	response *http.Response, err error) {
+	if c.metrics == nil {
+		return
+	}
+
+	observation := RequestObservation{
+		Method:   request.Method,
+		Endpoint: metricsEndpoint(request.URL.Path),
+		Duration: duration,
+		Attempt:  attempt,
+		Err:      err,
+	}
+	if response != nil {
+		observation.StatusCode = response.StatusCode
+	}
+
+	c.metrics.ObserveRequest(observation)
+}
+
+// Reports a retry to the metrics
+func (c *requestConfig) observeRetry(request *http.Request, event RetryEvent) {
+	if c.metrics == nil {
+		return
+	}
+
+	c.metrics.ObserveRetry(RetryObservation{
+		Method:     request.Method,
+		Endpoint:   metricsEndpoint(request.URL.Path),
+		RetryEvent: event,
+	})
+}
@@ -0,0 +1,86 @@
+package client
+
+import (
+	"net/http"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testMetrics keeps the observations it receives
+type testMetrics struct {
+	mu       sync.Mutex
+	requests []RequestObservation
+	retries  []RetryObservation
+}
+
+// ObserveRequest implements the Metrics interface
+func (m *testMetrics) ObserveRequest(o RequestObservation) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.requests = append(m.requests, o)
+}
+
+// ObserveRetry implements the Metrics interface
+func (m *testMetrics) ObserveRetry(o RetryObservation) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.retries = append(m.retries, o)
+}
+
+// TestMetrics tests that every attempt and retry is reported to the metrics
+func TestMetrics(t *testing.T) {
+	srv, _ := newFlakyServer(t, 2, http.StatusServiceUnavailable, nil)
+	defer srv.Close()
+
+	metrics := &testMetrics{}
+	c := NewWithOptions("user", "pass", WithBaseURL(srv.URL), WithRetryPolicy(fastRetryPolicy()), WithMetrics(metrics))
+	assert.Equal(t, metrics, c.GetMetrics())
+
+	_, err := c.PerformJSONGetRequest(c.CreateRequestURL("/server/0077fa3d-32db-4b09-9f5f-30d9e9afb565"))
+	require.NoError(t, err)
+
+	require.Len(t, metrics.requests, 3)
+	for i, o := range metrics.requests {
+		assert.Equal(t, http.MethodGet, o.Method)
+		assert.Equal(t, "/server/{uuid}", o.Endpoint)
+		assert.Equal(t, i+1, o.Attempt)
+		assert.True(t, o.Duration > 0)
+	}
+	assert.Equal(t, http.StatusServiceUnavailable, metrics.requests[0].StatusCode)
+	assert.Error(t, metrics.requests[0].Err)
+	assert.Equal(t, http.StatusOK, metrics.requests[2].StatusCode)
+	assert.NoError(t, metrics.requests[2].Err)
+
+	require.Len(t, metrics.retries, 2)
+	assert.Equal(t, "/server/{uuid}", metrics.retries[0].Endpoint)
+	assert.Equal(t, 1, metrics.retries[0].Attempt)
+	assert.Equal(t, http.StatusServiceUnavailable, metrics.retries[0].StatusCode)
+
+	c.SetMetrics(nil)
+	_, err = c.PerformJSONGetRequest(c.CreateRequestURL("/zone"))
+	require.NoError(t, err)
+	assert.Len(t, metrics.requests, 3)
+}
+
+// TestMetricsEndpoint tests that UUIDs and IP addresses are removed from the endpoints
+func TestMetricsEndpoint(t *testing.T) {
+	tests := map[string]string{
+		"/1.3/zone": "/zone",
+		"/1.3":      "/",
+		"/1.3/server/0077fa3d-32db-4b09-9f5f-30d9e9afb565/start":           "/server/{uuid}/start",
+		"/1.3/server/0077FA3D-32DB-4B09-9F5F-30D9E9AFB565/tag/DEV":         "/server/{uuid}/tag/DEV",
+		"/1.3/server/0077fa3d-32db-4b09-9f5f-30d9e9afb565/firewall_rule/1": "/server/{uuid}/firewall_rule/1",
+		"/1.3/ip_address/94.237.117.150":                                   "/ip_address/{ip}",
+		"/1.3/ip_address/2a04:3540:1000:310::1":                            "/ip_address/{ip}",
+		"/uploader/session/01d2f6d8-6e8f-4c18-b1b6-ab2f2a1c8c6a":           "/uploader/session/{uuid}",
+	}
+
+	for path, endpoint := range tests {
+		assert.Equal(t, endpoint, metricsEndpoint(path), path)
+	}
+}
@@ -0,0 +1,160 @@
+package client
+
+import (
+	"net/http"
+	"strings"
+	"time"
+)
+
+// Option configures a client created with NewWithOptions
+type Option func(*Client)
+
+// WithBaseURL makes the client send requests to the specified base URL instead of DefaultAPIBaseURL, e.g. to use
+// a proxy or a local emulator. The API version is appended to the base URL.
+func WithBaseURL(baseURL string) Option {
+	return func(c *Client) {
+		c.baseURL = strings.TrimRight(baseURL, "/")
+	}
+}
+
+// WithAPIVersion makes the client use the specified API version instead of DefaultAPIVersion. Only the major and
+// minor versions end up in the request URLs.
+func WithAPIVersion(version string) Option {
+	return func(c *Client) {
+		c.apiVersion = version
+	}
+}
+
+// WithUserAgentSuffix appends the specified product token, e.g. "my-app/1.2.3", to the User-Agent header sent by
+// the client
+func WithUserAgentSuffix(suffix string) Option {
+	return func(c *Client) {
+		c.userAgent = DefaultUserAgent + " " + suffix
+	}
+}
+
+// WithHTTPClient makes the client use the specified HTTP client instead of a new one from cleanhttp
+func WithHTTPClient(httpClient *http.Client) Option {
+	return func(c *Client) {
+		c.httpClient = httpClient
+	}
+}
+
+// WithHeader adds a header that is sent with every request. Headers managed by the client itself, such as
+// Authorization and Content-Type, can't be overridden.
+func WithHeader(key, value string) Option {
+	return func(c *Client) {
+		c.headers.Add(key, value)
+	}
+}
+
+// WithCredentialsProvider makes the client ask the specified provider for its credentials instead of using the
+// ones passed to NewWithOptions, see SetCredentialsProvider
+func WithCredentialsProvider(credentials CredentialsProvider) Option {
+	return func(c *Client) {
+		c.credentials = credentials
+	}
+}
+
+// WithTimeout sets the client timeout, see SetTimeout
+func WithTimeout(timeout time.Duration) Option {
+	return func(c *Client) {
+		c.timeout = timeout
+	}
+}
+
+// WithRetryPolicy sets the retry policy, see SetRetryPolicy
+func WithRetryPolicy(policy *RetryPolicy) Option {
+	return func(c *Client) {
+		c.retryPolicy = policy
+	}
+}
+
+// WithRateLimiter sets the rate limiter, see SetRateLimiter
+func WithRateLimiter(limiter *RateLimiter) Option {
+	return func(c *Client) {
+		c.rateLimiter = limiter
+	}
+}
+
+// WithMaxConcurrentRequests limits the number of requests in flight, see SetMaxConcurrentRequests
+func WithMaxConcurrentRequests(max int) Option {
+	return func(c *Client) {
+		c.SetMaxConcurrentRequests(max)
+	}
+}
+
+// WithLogger sets the logger, see SetLogger
+func WithLogger(logger Logger) Option {
+	return func(c *Client) {
+		c.logger = logger
+	}
+}
+
+// WithBodyLogging includes the headers and bodies of requests in the log entries, see SetBodyLogging
+func WithBodyLogging(maxSize int) Option {
+	return func(c *Client) {
+		c.logBodySize = maxSize
+	}
+}
+
+// WithMetrics sets the metrics, see SetMetrics
+func WithMetrics(metrics Metrics) Option {
+	return func(c *Client) {
+		c.metrics = metrics
+	}
+}
+
+// WithDryRun puts the client in dry-run mode, see SetDryRun
+func WithDryRun(plan *Plan) Option {
+	return func(c *Client) {
+		c.dryRun = plan
+	}
+}
+
+// RequestOption configures a single request, overriding the client settings for that request only
+type RequestOption func(*requestConfig)
+
+// The settings used for a single request
+type requestConfig struct {
+	credentials CredentialsProvider
+	timeout     time.Duration
+	contentType string
+	headers     http.Header
+	retryPolicy *RetryPolicy
+	rateLimiter *RateLimiter
+	inFlight    chan struct{}
+	logger      Logger
+	logBodySize int
+	metrics     Metrics
+	dryRun      *Plan
+}
+
+// WithRequestTimeout overrides the client timeout for a single request
+func WithRequestTimeout(timeout time.Duration) RequestOption {
+	return func(c *requestConfig) {
+		if timeout > 0 {
+			c.timeout = timeout
+		}
+	}
+}
+
+// WithRequestContentType overrides the Content-Type header for a single request
+func WithRequestContentType(contentType string) RequestOption {
+	return func(c *requestConfig) {
+		if contentType != "" {
+			c.contentType = contentType
+		}
+	}
+}
+
+// WithRequestHeader adds a header to a single request. Like WithHeader, it can't override the headers managed by the
+// client itself.
+func WithRequestHeader(key, value string) RequestOption {
+	return func(c *requestConfig) {
+		if c.headers == nil {
+			c.headers = http.Header{}
+		}
+		c.headers.Add(key, value)
+	}
+}
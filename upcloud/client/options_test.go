@@ -0,0 +1,79 @@
+package client
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestNewDefaults tests that the plain constructors keep their previous behaviour
+func TestNewDefaults(t *testing.T) {
+	c := New("username", "password")
+	assert.Equal(t, "https://api.upcloud.com/1.3/server", c.CreateRequestURL("/server"))
+	assert.Equal(t, DefaultTimeout*time.Second, c.GetTimeout())
+
+	httpClient := &http.Client{Timeout: time.Second}
+	c = NewWithHTTPClient("username", "password", httpClient)
+	assert.Equal(t, time.Second, c.GetTimeout())
+}
+
+// TestNewWithOptions tests that the options end up in the requests sent by the client
+func TestNewWithOptions(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/1.4/zone", r.URL.Path)
+		assert.Equal(t, DefaultUserAgent+" my-app/1.2.3", r.UserAgent())
+		assert.Equal(t, "value", r.Header.Get("X-Custom"))
+		assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+		user, _, _ := r.BasicAuth()
+		assert.Equal(t, "username", user)
+		w.Write([]byte(`{}`))
+	}))
+	defer srv.Close()
+
+	c := NewWithOptions("username", "password",
+		WithBaseURL(srv.URL+"/"),
+		WithAPIVersion("1.4.0"),
+		WithUserAgentSuffix("my-app/1.2.3"),
+		WithHeader("X-Custom", "value"),
+		WithHeader("Content-Type", "text/plain"),
+		WithTimeout(5*time.Second),
+		WithRetryPolicy(DefaultRetryPolicy()),
+		WithRateLimiter(NewRateLimiter(10, 10)),
+		WithMaxConcurrentRequests(2),
+	)
+
+	assert.Equal(t, 5*time.Second, c.GetTimeout())
+	assert.NotNil(t, c.GetRetryPolicy())
+	assert.NotNil(t, c.GetRateLimiter())
+
+	_, err := c.PerformJSONGetRequest(c.CreateRequestURL("/zone"))
+	require.NoError(t, err)
+}
+
+// TestNewWithOptionsAPIVersion tests the different forms of API versions
+func TestNewWithOptionsAPIVersion(t *testing.T) {
+	c := NewWithOptions("username", "password", WithAPIVersion("1.3"))
+	assert.Equal(t, "https://api.upcloud.com/1.3/zone", c.CreateRequestURL("/zone"))
+
+	c = NewWithOptions("username", "password", WithBaseURL("http://localhost:8080"), WithAPIVersion("v2"))
+	assert.Equal(t, "http://localhost:8080/2.0/zone", c.CreateRequestURL("/zone"))
+
+	c = NewWithOptions("username", "password", WithAPIVersion("latest"))
+	assert.Equal(t, "https://api.upcloud.com/latest/zone", c.CreateRequestURL("/zone"))
+}
+
+// TestNewWithOptionsHTTPClient tests that the supplied HTTP client is used without being modified
+func TestNewWithOptionsHTTPClient(t *testing.T) {
+	httpClient := &http.Client{}
+	c := NewWithOptions("username", "password", WithHTTPClient(httpClient))
+	assert.Zero(t, httpClient.Timeout)
+	assert.Equal(t, DefaultTimeout*time.Second, c.GetTimeout())
+
+	c.SetTimeout(time.Second)
+	assert.Zero(t, httpClient.Timeout)
+	assert.Equal(t, time.Second, c.GetTimeout())
+}
@@ -0,0 +1,243 @@
+package client
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// DefaultLatencyBuckets are the upper bounds, in seconds, of the latency histogram buckets used by
+// PrometheusMetrics unless others are specified
+var DefaultLatencyBuckets = []float64{0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30}
+
+// PrometheusMetrics is an implementation of Metrics that keeps the measurements in memory and exposes them in the
+// Prometheus text format. It's an http.Handler, so it can be served as a metrics endpoint:
+//
+//	metrics := client.NewPrometheusMetrics("upcloud_api", nil)
+//	c := client.NewWithOptions(username, password, client.WithMetrics(metrics))
+//	http.Handle("/metrics", metrics)
+//
+// It exposes the following metrics, prefixed with the namespace:
+//
+//	requests_total{method, endpoint, status}          counter of attempts, status is "error" without a response
+//	request_duration_seconds{method, endpoint}         histogram of the latency of attempts
+//	retries_total{method, endpoint}                    counter of retries
+//
+// A PrometheusMetrics can be shared by several clients and is safe for concurrent use.
+type PrometheusMetrics struct {
+	namespace string
+	buckets   []float64
+
+	mu        sync.Mutex
+	requests  map[requestKey]uint64
+	latencies map[endpointKey]*histogram
+	retries   map[endpointKey]uint64
+}
+
+// endpointKey identifies the series of an endpoint
+type endpointKey struct {
+	method   string
+	endpoint string
+}
+
+// requestKey identifies the series of an endpoint and status
+type requestKey struct {
+	endpointKey
+	status string
+}
+
+// histogram counts observations into buckets. The counts are not cumulative.
+type histogram struct {
+	counts []uint64
+	count  uint64
+	sum    float64
+}
+
+// NewPrometheusMetrics returns metrics with names prefixed by the namespace, e.g. "upcloud_api", and latency
+// histograms with the specified bucket upper bounds in seconds. Nil buckets use DefaultLatencyBuckets.
+func NewPrometheusMetrics(namespace string, buckets []float64) *PrometheusMetrics {
+	if buckets == nil {
+		buckets = DefaultLatencyBuckets
+	}
+	buckets = append([]float64(nil), buckets...)
+	sort.Float64s(buckets)
+
+	return &PrometheusMetrics{
+		namespace: namespace,
+		buckets:   buckets,
+		requests:  make(map[requestKey]uint64),
+		latencies: make(map[endpointKey]*histogram),
+		retries:   make(map[endpointKey]uint64),
+	}
+}
+
+// ObserveRequest implements the Metrics interface
+func (m *PrometheusMetrics) ObserveRequest(o RequestObservation) {
+	key := endpointKey{method: o.Method, endpoint: o.Endpoint}
+	status := "error"
+	if o.StatusCode != 0 {
+		status = strconv.Itoa(o.StatusCode)
+	}
+	seconds := o.Duration.Seconds()
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.requests[requestKey{endpointKey: key, status: status}]++
+
+	h, ok := m.latencies[key]
+	if !ok {
+		h = &histogram{counts: make([]uint64, len(m.buckets))}
+		m.latencies[key] = h
+	}
+	for i, bound := range m.buckets {
+		if seconds <= bound {
+			h.counts[i]++
+			break
+		}
+	}
+	h.count++
+	h.sum += seconds
+}
+
+// ObserveRetry implements the Metrics interface
+func (m *PrometheusMetrics) ObserveRetry(o RetryObservation) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.retries[endpointKey{method: o.Method, endpoint: o.Endpoint}]++
+}
+
+// WriteTo writes the metrics to w in the Prometheus text format. It implements the io.WriterTo interface.
+func (m *PrometheusMetrics) WriteTo(w io.Writer) (int64, error) {
+	cw := &countingWriter{w: bufio.NewWriter(w)}
+
+	m.mu.Lock()
+	m.writeRequests(cw)
+	m.writeLatencies(cw)
+	m.writeRetries(cw)
+	m.mu.Unlock()
+
+	if cw.err == nil {
+		cw.err = cw.w.Flush()
+	}
+	return cw.n, cw.err
+}
+
+// ServeHTTP implements the http.Handler interface by responding with the metrics
+func (m *PrometheusMetrics) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	_, _ = m.WriteTo(w)
+}
+
+func (m *PrometheusMetrics) writeRequests(w *countingWriter) {
+	name := m.namespace + "_requests_total"
+	w.printf("# HELP %s Number of attempts to perform API requests.\n", name)
+	w.printf("# TYPE %s counter\n", name)
+
+	keys := make([]requestKey, 0, len(m.requests))
+	for key := range m.requests {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].endpointKey != keys[j].endpointKey {
+			return keys[i].endpointKey.less(keys[j].endpointKey)
+		}
+		return keys[i].status < keys[j].status
+	})
+
+	for _, key := range keys {
+		w.printf("%s{%s,status=%s} %d\n", name, key.labels(), quoteLabel(key.status), m.requests[key])
+	}
+}
+
+func (m *PrometheusMetrics) writeLatencies(w *countingWriter) {
+	name := m.namespace + "_request_duration_seconds"
+	w.printf("# HELP %s Latency of attempts to perform API requests.\n", name)
+	w.printf("# TYPE %s histogram\n", name)
+
+	for _, key := range sortedEndpointKeys(m.latencies) {
+		h := m.latencies[key]
+		var cumulative uint64
+		for i, bound := range m.buckets {
+			cumulative += h.counts[i]
+			w.printf("%s_bucket{%s,le=%s} %d\n", name, key.labels(), quoteLabel(formatFloat(bound)), cumulative)
+		}
+		w.printf("%s_bucket{%s,le=\"+Inf\"} %d\n", name, key.labels(), h.count)
+		w.printf("%s_sum{%s} %s\n", name, key.labels(), formatFloat(h.sum))
+		w.printf("%s_count{%s} %d\n", name, key.labels(), h.count)
+	}
+}
+
+func (m *PrometheusMetrics) writeRetries(w *countingWriter) {
+	name := m.namespace + "_retries_total"
+	w.printf("# HELP %s Number of retried API request attempts.\n", name)
+	w.printf("# TYPE %s counter\n", name)
+
+	keys := make([]endpointKey, 0, len(m.retries))
+	for key := range m.retries {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool { return keys[i].less(keys[j]) })
+
+	for _, key := range keys {
+		w.printf("%s{%s} %d\n", name, key.labels(), m.retries[key])
+	}
+}
+
+// Returns the keys of the histograms in a stable order
+func sortedEndpointKeys(histograms map[endpointKey]*histogram) []endpointKey {
+	keys := make([]endpointKey, 0, len(histograms))
+	for key := range histograms {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool { return keys[i].less(keys[j]) })
+
+	return keys
+}
+
+// Orders the keys by endpoint and method
+func (k endpointKey) less(other endpointKey) bool {
+	if k.endpoint != other.endpoint {
+		return k.endpoint < other.endpoint
+	}
+	return k.method < other.method
+}
+
+// Returns the labels of the key
+func (k endpointKey) labels() string {
+	return "endpoint=" + quoteLabel(k.endpoint) + ",method=" + quoteLabel(k.method)
+}
+
+// Returns a label value quoted and escaped as the text format requires
+func quoteLabel(value string) string {
+	value = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(value)
+	return `"` + value + `"`
+}
+
+// Formats a sample value or bucket bound
+func formatFloat(f float64) string {
+	return strconv.FormatFloat(f, 'g', -1, 64)
+}
+
+// countingWriter keeps track of the bytes written and the first error
+type countingWriter struct {
+	w   *bufio.Writer
+	n   int64
+	err error
+}
+
+func (w *countingWriter) printf(format string, args ...interface{}) {
+	if w.err != nil {
+		return
+	}
+
+	n, err := fmt.Fprintf(w.w, format, args...)
+	w.n += int64(n)
+	w.err = err
+}
@@ -0,0 +1,81 @@
+package client
+
+import (
+	"bytes"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestPrometheusMetrics tests the text format exposed by PrometheusMetrics
+func TestPrometheusMetrics(t *testing.T) {
+	metrics := NewPrometheusMetrics("upcloud_api", []float64{1, 0.1})
+
+	start := RequestObservation{Method: http.MethodPost, Endpoint: "/server/{uuid}/start"}
+	start.StatusCode, start.Duration = http.StatusServiceUnavailable, 50*time.Millisecond
+	metrics.ObserveRequest(start)
+	metrics.ObserveRetry(RetryObservation{Method: start.Method, Endpoint: start.Endpoint})
+	start.StatusCode, start.Duration = http.StatusOK, 500*time.Millisecond
+	metrics.ObserveRequest(start)
+	metrics.ObserveRequest(RequestObservation{
+		Method:   http.MethodGet,
+		Endpoint: `/tag/"quoted"`,
+		Duration: 2 * time.Second,
+		Err:      errors.New("connection reset"),
+	})
+
+	var buf bytes.Buffer
+	n, err := metrics.WriteTo(&buf)
+	require.NoError(t, err)
+	assert.Equal(t, int64(buf.Len()), n)
+
+	assert.Equal(t, `# HELP upcloud_api_requests_total Number of attempts to perform API requests.
+# TYPE upcloud_api_requests_total counter
+upcloud_api_requests_total{endpoint="/server/{uuid}/start",method="POST",status="200"} 1
+upcloud_api_requests_total{endpoint="/server/{uuid}/start",method="POST",status="503"} 1
+upcloud_api_requests_total{endpoint="/tag/\"quoted\"",method="GET",status="error"} 1
+# HELP upcloud_api_request_duration_seconds Latency of attempts to perform API requests.
+# TYPE upcloud_api_request_duration_seconds histogram
+upcloud_api_request_duration_seconds_bucket{endpoint="/server/{uuid}/start",method="POST",le="0.1"} 1
+upcloud_api_request_duration_seconds_bucket{endpoint="/server/{uuid}/start",method="POST",le="1"} 2
+upcloud_api_request_duration_seconds_bucket{endpoint="/server/{uuid}/start",method="POST",le="+Inf"} 2
+upcloud_api_request_duration_seconds_sum{endpoint="/server/{uuid}/start",method="POST"} 0.55
+upcloud_api_request_duration_seconds_count{endpoint="/server/{uuid}/start",method="POST"} 2
+upcloud_api_request_duration_seconds_bucket{endpoint="/tag/\"quoted\"",method="GET",le="0.1"} 0
+upcloud_api_request_duration_seconds_bucket{endpoint="/tag/\"quoted\"",method="GET",le="1"} 0
+upcloud_api_request_duration_seconds_bucket{endpoint="/tag/\"quoted\"",method="GET",le="+Inf"} 1
+upcloud_api_request_duration_seconds_sum{endpoint="/tag/\"quoted\"",method="GET"} 2
+upcloud_api_request_duration_seconds_count{endpoint="/tag/\"quoted\"",method="GET"} 1
+# HELP upcloud_api_retries_total Number of retried API request attempts.
+# TYPE upcloud_api_retries_total counter
+upcloud_api_retries_total{endpoint="/server/{uuid}/start",method="POST"} 1
+`, buf.String())
+}
+
+// TestPrometheusMetricsHandler tests serving the metrics of a client over HTTP
+func TestPrometheusMetricsHandler(t *testing.T) {
+	api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{}`))
+	}))
+	defer api.Close()
+
+	metrics := NewPrometheusMetrics("upcloud_api", nil)
+	c := NewWithOptions("user", "pass", WithBaseURL(api.URL), WithMetrics(metrics))
+	_, err := c.PerformJSONGetRequest(c.CreateRequestURL("/zone"))
+	require.NoError(t, err)
+
+	rec := httptest.NewRecorder()
+	metrics.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	assert.Equal(t, "text/plain; version=0.0.4; charset=utf-8", rec.Header().Get("Content-Type"))
+
+	body, err := ioutil.ReadAll(rec.Body)
+	require.NoError(t, err)
+	assert.Contains(t, string(body), `upcloud_api_requests_total{endpoint="/zone",method="GET",status="200"} 1`)
+	assert.Contains(t, string(body), `upcloud_api_request_duration_seconds_bucket{endpoint="/zone",method="GET",le="30"} 1`)
+}
@@ -0,0 +1,141 @@
+package client
+
+import (
+	"context"
+	"math"
+	"sync"
+	"time"
+)
+
+// RateLimiter is a token bucket limiting how often requests are sent. It adapts to the API: every 429 Too Many
+// Requests response halves the rate and pauses the bucket for the duration of an eventual Retry-After header, after
+// which the rate slowly recovers back to the configured one on successful requests.
+//
+// A RateLimiter is safe for concurrent use. Sharing a single limiter between several clients makes them share the
+// same budget, which is useful when they all use the same account.
+type RateLimiter struct {
+	mu sync.Mutex
+
+	limit   float64
+	rate    float64
+	burst   float64
+	tokens  float64
+	last    time.Time
+	paused  time.Time
+	resumed time.Time
+}
+
+// The rate never drops below this fraction of the configured limit
+const minRateFraction = 0.05
+
+// The rate used when the configured one isn't positive
+const fallbackRequestsPerSecond = 1.0
+
+// NewRateLimiter creates and returns a rate limiter allowing on average the specified amount of requests per second
+// with bursts of up to burst requests. A rate that isn't positive is replaced by one request per second.
+func NewRateLimiter(requestsPerSecond float64, burst int) *RateLimiter {
+	// Also catches NaN
+	if !(requestsPerSecond > 0) {
+		requestsPerSecond = fallbackRequestsPerSecond
+	}
+	if burst < 1 {
+		burst = 1
+	}
+
+	return &RateLimiter{
+		limit:  requestsPerSecond,
+		rate:   requestsPerSecond,
+		burst:  float64(burst),
+		tokens: float64(burst),
+		last:   time.Now(),
+	}
+}
+
+// Rate returns the current rate in requests per second. It is lower than the configured rate after the API has
+// responded with 429 Too Many Requests.
+func (l *RateLimiter) Rate() float64 {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return l.rate
+}
+
+// Wait blocks until a request is allowed to be sent or the context is done
+func (l *RateLimiter) Wait(ctx context.Context) error {
+	l.mu.Lock()
+	now := time.Now()
+	l.refill(now)
+
+	// Reserve a token even if it isn't available yet, this makes waiters queue up in order
+	l.tokens--
+	delay := time.Duration(0)
+	if l.tokens < 0 {
+		delay = time.Duration(-l.tokens / l.rate * float64(time.Second))
+	}
+	if l.paused.After(now) {
+		delay += l.paused.Sub(now)
+	}
+	l.mu.Unlock()
+
+	if delay <= 0 {
+		return nil
+	}
+
+	if err := sleepWithContext(ctx, delay); err != nil {
+		// Give back the reserved token
+		l.mu.Lock()
+		l.tokens = math.Min(l.tokens+1, l.burst)
+		l.mu.Unlock()
+		return err
+	}
+
+	return nil
+}
+
+// Throttled tells the limiter that the API responded with 429 Too Many Requests. The rate is halved and, if the
+// response specified how long to wait, the bucket is paused for that time.
+func (l *RateLimiter) Throttled(retryAfter time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := time.Now()
+	l.refill(now)
+
+	l.rate = math.Max(l.rate/2, l.limit*minRateFraction)
+	if l.tokens > 0 {
+		l.tokens = 0
+	}
+	if pause := now.Add(retryAfter); pause.After(l.paused) {
+		l.paused = pause
+	}
+	l.resumed = now
+}
+
+// Succeeded tells the limiter that a request went through without being throttled. The rate recovers additively,
+// by a tenth of the configured rate at most once per second.
+func (l *RateLimiter) Succeeded() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.rate >= l.limit {
+		return
+	}
+
+	now := time.Now()
+	if now.Sub(l.resumed) < time.Second {
+		return
+	}
+
+	l.refill(now)
+	l.rate = math.Min(l.rate+l.limit/10, l.limit)
+	l.resumed = now
+}
+
+// Adds the tokens accumulated since the last refill, the caller must hold the lock
+func (l *RateLimiter) refill(now time.Time) {
+	elapsed := now.Sub(l.last).Seconds()
+	if elapsed > 0 {
+		l.tokens = math.Min(l.tokens+elapsed*l.rate, l.burst)
+		l.last = now
+	}
+}
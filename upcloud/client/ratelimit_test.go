@@ -0,0 +1,182 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestRateLimiterBurst tests that a burst is allowed immediately and the requests after it are paced
+func TestRateLimiterBurst(t *testing.T) {
+	limiter := NewRateLimiter(20, 5)
+	ctx := context.Background()
+
+	start := time.Now()
+	for i := 0; i < 5; i++ {
+		require.NoError(t, limiter.Wait(ctx))
+	}
+	assert.True(t, time.Since(start) < 25*time.Millisecond)
+
+	for i := 0; i < 4; i++ {
+		require.NoError(t, limiter.Wait(ctx))
+	}
+	// Four more requests at 20 requests per second take at least 200ms
+	assert.True(t, time.Since(start) >= 190*time.Millisecond)
+}
+
+// TestRateLimiterWaitCancelled tests that waiting for a token stops when the context is done
+func TestRateLimiterWaitCancelled(t *testing.T) {
+	limiter := NewRateLimiter(0.1, 1)
+	require.NoError(t, limiter.Wait(context.Background()))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	err := limiter.Wait(ctx)
+	assert.True(t, errors.Is(err, context.DeadlineExceeded))
+}
+
+// TestRateLimiterInvalidRate tests that a rate that isn't positive falls back to one request per second instead of
+// breaking Wait
+func TestRateLimiterInvalidRate(t *testing.T) {
+	for _, rate := range []float64{0, -5, math.NaN()} {
+		limiter := NewRateLimiter(rate, 1)
+		assert.Equal(t, 1.0, limiter.Rate())
+		require.NoError(t, limiter.Wait(context.Background()))
+
+		ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+		err := limiter.Wait(ctx)
+		cancel()
+		assert.True(t, errors.Is(err, context.DeadlineExceeded))
+	}
+}
+
+// TestRateLimiterAdapts tests that the rate drops after throttling and recovers after successful requests
+func TestRateLimiterAdapts(t *testing.T) {
+	limiter := NewRateLimiter(10, 1)
+
+	limiter.Throttled(0)
+	assert.Equal(t, 5.0, limiter.Rate())
+	limiter.Throttled(0)
+	assert.Equal(t, 2.5, limiter.Rate())
+
+	// Recovery is limited to once per second
+	limiter.Succeeded()
+	assert.Equal(t, 2.5, limiter.Rate())
+
+	limiter.mu.Lock()
+	limiter.resumed = time.Now().Add(-time.Second)
+	limiter.mu.Unlock()
+	limiter.Succeeded()
+	assert.Equal(t, 3.5, limiter.Rate())
+
+	for i := 0; i < 100; i++ {
+		limiter.Throttled(0)
+	}
+	assert.Equal(t, 10*minRateFraction, limiter.Rate())
+}
+
+// TestRateLimiterRetryAfter tests that a Retry-After pauses the limiter
+func TestRateLimiterRetryAfter(t *testing.T) {
+	limiter := NewRateLimiter(1000, 10)
+	limiter.Throttled(100 * time.Millisecond)
+
+	start := time.Now()
+	require.NoError(t, limiter.Wait(context.Background()))
+	assert.True(t, time.Since(start) >= 90*time.Millisecond)
+}
+
+// TestClientRateLimiter tests that the client slows down after a 429 response
+func TestClientRateLimiter(t *testing.T) {
+	var calls int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&calls, 1) == 1 {
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		w.Write([]byte(`{}`))
+	}))
+	defer srv.Close()
+
+	limiter := NewRateLimiter(100, 10)
+
+	c := New("username", "password")
+	c.SetRateLimiter(limiter)
+	c.SetRetryPolicy(fastRetryPolicy())
+
+	_, err := c.PerformJSONGetRequest(srv.URL)
+	require.NoError(t, err)
+	assert.EqualValues(t, 2, atomic.LoadInt32(&calls))
+	assert.Equal(t, 50.0, limiter.Rate())
+}
+
+// TestClientMaxConcurrentRequests tests that the number of requests in flight never exceeds the limit
+func TestClientMaxConcurrentRequests(t *testing.T) {
+	var current, peak int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := atomic.AddInt32(&current, 1)
+		defer atomic.AddInt32(&current, -1)
+
+		for {
+			p := atomic.LoadInt32(&peak)
+			if n <= p || atomic.CompareAndSwapInt32(&peak, p, n) {
+				break
+			}
+		}
+		time.Sleep(10 * time.Millisecond)
+		w.Write([]byte(`{}`))
+	}))
+	defer srv.Close()
+
+	c := New("username", "password")
+	c.SetMaxConcurrentRequests(3)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := c.PerformJSONGetRequest(srv.URL)
+			assert.NoError(t, err)
+		}()
+	}
+	wg.Wait()
+
+	assert.EqualValues(t, 3, atomic.LoadInt32(&peak))
+}
+
+// TestClientRateLimiterBeforeConcurrency tests that a request waiting for the rate limiter doesn't hold one of the
+// concurrent request slots
+func TestClientRateLimiterBeforeConcurrency(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{}`))
+	}))
+	defer srv.Close()
+
+	limiter := NewRateLimiter(1000, 10)
+	limiter.Throttled(200 * time.Millisecond)
+
+	c := New("username", "password")
+	c.SetRateLimiter(limiter)
+	c.SetMaxConcurrentRequests(1)
+
+	done := make(chan error)
+	go func() {
+		_, err := c.PerformJSONGetRequest(srv.URL)
+		done <- err
+	}()
+
+	time.Sleep(50 * time.Millisecond)
+	assert.Len(t, c.inFlight, 0)
+
+	require.NoError(t, <-done)
+}
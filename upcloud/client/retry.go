@@ -0,0 +1,157 @@
+package client
+
+import (
+	"math/rand"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// RetryPolicy describes how requests that failed with a transient error are retried. A transient error is either
+// a transport level error (e.g. a reset connection), a 429 Too Many Requests response or a 5xx response other
+// than 501 Not Implemented.
+type RetryPolicy struct {
+	// MaxAttempts is the maximum number of attempts per request, including the first one. Values below 2 disable
+	// retries.
+	MaxAttempts int
+	// MinBackoff is the base delay before the first retry. The delay doubles on every subsequent retry.
+	MinBackoff time.Duration
+	// MaxBackoff caps the delay between two attempts, including delays requested through a Retry-After header.
+	MaxBackoff time.Duration
+	// RetryNonIdempotent enables retrying POST and PATCH requests. These are not retried by default since the
+	// API may have acted on a request even though the response never arrived.
+	RetryNonIdempotent bool
+	// OnRetry, when set, is called before every retry
+	OnRetry func(RetryEvent)
+}
+
+// RetryEvent describes a failed attempt that is about to be retried
+type RetryEvent struct {
+	Method string
+	URL    string
+	// Attempt is the number of the attempt that failed, starting from 1
+	Attempt int
+	// Delay is the time waited before the next attempt
+	Delay time.Duration
+	// StatusCode is the HTTP status of the failed attempt, or 0 if no response was received
+	StatusCode int
+	// Err is the error of the failed attempt
+	Err error
+}
+
+// DefaultRetryPolicy returns a retry policy suitable for most API clients
+func DefaultRetryPolicy() *RetryPolicy {
+	return &RetryPolicy{
+		MaxAttempts: 4,
+		MinBackoff:  500 * time.Millisecond,
+		MaxBackoff:  30 * time.Second,
+	}
+}
+
+var (
+	jitterMu   sync.Mutex
+	jitterRand = rand.New(rand.NewSource(time.Now().UnixNano()))
+)
+
+// Returns whether the request method can safely be sent more than once
+func (p *RetryPolicy) allowsMethod(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodPut, http.MethodDelete:
+		return true
+	}
+
+	return p.RetryNonIdempotent
+}
+
+// Returns whether the outcome of an attempt should be retried and how long to wait before doing so. The response
+// may be nil if the attempt failed with a transport error.
+func (p *RetryPolicy) retryDelay(request *http.Request, response *http.Response, err error, attempt int) (time.Duration, bool) {
+	if p == nil || attempt >= p.MaxAttempts || !p.allowsMethod(request.Method) {
+		return 0, false
+	}
+
+	// A body that can't be rewound can't be sent again
+	if request.Body != nil && request.Body != http.NoBody && request.GetBody == nil {
+		return 0, false
+	}
+
+	// Give up if the caller's context is done. A timed out attempt on the other hand is worth retrying.
+	if request.Context().Err() != nil {
+		return 0, false
+	}
+
+	if response != nil && !isRetryableStatus(response.StatusCode) {
+		return 0, false
+	}
+
+	if response != nil {
+		if delay, ok := parseRetryAfter(response.Header.Get("Retry-After")); ok {
+			return p.capBackoff(delay), true
+		}
+	}
+
+	return p.backoff(attempt), true
+}
+
+// Returns the exponential backoff with jitter for the specified attempt
+func (p *RetryPolicy) backoff(attempt int) time.Duration {
+	delay := p.MinBackoff
+	for i := 1; i < attempt && (p.MaxBackoff <= 0 || delay < p.MaxBackoff); i++ {
+		delay *= 2
+	}
+	delay = p.capBackoff(delay)
+
+	if delay <= 0 {
+		return 0
+	}
+
+	// Use "equal jitter": half of the delay is fixed, the other half is random
+	jitterMu.Lock()
+	jitter := time.Duration(jitterRand.Int63n(int64(delay/2) + 1))
+	jitterMu.Unlock()
+
+	return delay/2 + jitter
+}
+
+// Limits the specified delay to MaxBackoff
+func (p *RetryPolicy) capBackoff(delay time.Duration) time.Duration {
+	if p.MaxBackoff > 0 && delay > p.MaxBackoff {
+		return p.MaxBackoff
+	}
+
+	return delay
+}
+
+// Returns whether a response with the specified status code is worth retrying
+func isRetryableStatus(statusCode int) bool {
+	if statusCode == http.StatusTooManyRequests {
+		return true
+	}
+
+	return statusCode >= 500 && statusCode != http.StatusNotImplemented
+}
+
+// Parses the value of a Retry-After header, which is either a number of seconds or an HTTP date
+func parseRetryAfter(value string) (time.Duration, bool) {
+	if value == "" {
+		return 0, false
+	}
+
+	if seconds, err := strconv.Atoi(value); err == nil {
+		if seconds < 0 {
+			return 0, false
+		}
+		return time.Duration(seconds) * time.Second, true
+	}
+
+	if date, err := http.ParseTime(value); err == nil {
+		delay := time.Until(date)
+		if delay < 0 {
+			delay = 0
+		}
+		return delay, true
+	}
+
+	return 0, false
+}
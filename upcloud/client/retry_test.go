@@ -0,0 +1,205 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Returns a server that fails the first `failures` requests with the specified status code
+func newFlakyServer(t *testing.T, failures int32, statusCode int, header http.Header) (*httptest.Server, *int32) {
+	var calls int32
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := ioutil.ReadAll(r.Body)
+		assert.NoError(t, err)
+		if r.Method != http.MethodGet {
+			assert.Equal(t, `{"retry":"me"}`, string(body))
+		}
+
+		if atomic.AddInt32(&calls, 1) <= failures {
+			for k, v := range header {
+				w.Header()[k] = v
+			}
+			w.WriteHeader(statusCode)
+			w.Write([]byte(`{"error":{"error_code":"FLAKY","error_message":"Try again"}}`))
+			return
+		}
+		w.Write([]byte(`{"ok":true}`))
+	}))
+
+	return srv, &calls
+}
+
+func fastRetryPolicy() *RetryPolicy {
+	return &RetryPolicy{
+		MaxAttempts: 3,
+		MinBackoff:  time.Millisecond,
+		MaxBackoff:  5 * time.Millisecond,
+	}
+}
+
+// TestRetryTransientErrors tests that idempotent requests are retried until they succeed
+func TestRetryTransientErrors(t *testing.T) {
+	for _, statusCode := range []int{http.StatusServiceUnavailable, http.StatusBadGateway, http.StatusTooManyRequests} {
+		srv, calls := newFlakyServer(t, 2, statusCode, nil)
+
+		var events []RetryEvent
+		policy := fastRetryPolicy()
+		policy.OnRetry = func(e RetryEvent) {
+			events = append(events, e)
+		}
+
+		c := New("username", "password")
+		c.SetRetryPolicy(policy)
+
+		response, err := c.PerformJSONPutRequest(srv.URL, []byte(`{"retry":"me"}`))
+		require.NoError(t, err)
+		assert.Equal(t, `{"ok":true}`, string(response))
+		assert.EqualValues(t, 3, atomic.LoadInt32(calls))
+		assert.EqualValues(t, 2, c.RetryCount())
+		require.Len(t, events, 2)
+		assert.Equal(t, http.MethodPut, events[0].Method)
+		assert.Equal(t, 1, events[0].Attempt)
+		assert.Equal(t, 2, events[1].Attempt)
+		assert.Equal(t, statusCode, events[1].StatusCode)
+
+		srv.Close()
+	}
+}
+
+// TestRetryGivesUp tests that the last error is returned once the attempts are exhausted
+func TestRetryGivesUp(t *testing.T) {
+	srv, calls := newFlakyServer(t, 10, http.StatusInternalServerError, nil)
+	defer srv.Close()
+
+	c := New("username", "password")
+	c.SetRetryPolicy(fastRetryPolicy())
+
+	_, err := c.PerformJSONGetRequest(srv.URL)
+	require.Error(t, err)
+	clientError, ok := err.(*Error)
+	require.True(t, ok)
+	assert.Equal(t, http.StatusInternalServerError, clientError.ErrorCode)
+	assert.EqualValues(t, 3, atomic.LoadInt32(calls))
+}
+
+// TestRetryNonIdempotent tests that POST requests are only retried when explicitly allowed
+func TestRetryNonIdempotent(t *testing.T) {
+	srv, calls := newFlakyServer(t, 1, http.StatusServiceUnavailable, nil)
+	defer srv.Close()
+
+	c := New("username", "password")
+	c.SetRetryPolicy(fastRetryPolicy())
+
+	_, err := c.PerformJSONPostRequest(srv.URL, []byte(`{"retry":"me"}`))
+	require.Error(t, err)
+	assert.EqualValues(t, 1, atomic.LoadInt32(calls))
+
+	policy := fastRetryPolicy()
+	policy.RetryNonIdempotent = true
+	c.SetRetryPolicy(policy)
+
+	response, err := c.PerformJSONPostRequest(srv.URL, []byte(`{"retry":"me"}`))
+	require.NoError(t, err)
+	assert.Equal(t, `{"ok":true}`, string(response))
+}
+
+// TestRetryNotRetryable tests that client errors are not retried
+func TestRetryNotRetryable(t *testing.T) {
+	srv, calls := newFlakyServer(t, 1, http.StatusNotFound, nil)
+	defer srv.Close()
+
+	c := New("username", "password")
+	c.SetRetryPolicy(fastRetryPolicy())
+
+	_, err := c.PerformJSONGetRequest(srv.URL)
+	require.Error(t, err)
+	assert.EqualValues(t, 1, atomic.LoadInt32(calls))
+	assert.Zero(t, c.RetryCount())
+}
+
+// TestRetryAfter tests that the delay requested by the API is respected
+func TestRetryAfter(t *testing.T) {
+	srv, _ := newFlakyServer(t, 1, http.StatusTooManyRequests, http.Header{"Retry-After": []string{"1"}})
+	defer srv.Close()
+
+	var delay time.Duration
+	policy := fastRetryPolicy()
+	policy.MaxBackoff = 2 * time.Second
+	policy.OnRetry = func(e RetryEvent) {
+		delay = e.Delay
+	}
+
+	c := New("username", "password")
+	c.SetRetryPolicy(policy)
+
+	start := time.Now()
+	_, err := c.PerformJSONGetRequest(srv.URL)
+	require.NoError(t, err)
+	assert.Equal(t, time.Second, delay)
+	assert.True(t, time.Since(start) >= time.Second)
+}
+
+// TestRetryContextCancelled tests that a cancelled context stops waiting for the next attempt
+func TestRetryContextCancelled(t *testing.T) {
+	srv, calls := newFlakyServer(t, 10, http.StatusServiceUnavailable, nil)
+	defer srv.Close()
+
+	policy := fastRetryPolicy()
+	policy.MinBackoff = time.Minute
+	policy.MaxBackoff = time.Minute
+
+	c := New("username", "password")
+	c.SetRetryPolicy(policy)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	_, err := c.PerformJSONGetRequestWithContext(ctx, srv.URL)
+	assert.True(t, errors.Is(err, context.DeadlineExceeded))
+	assert.EqualValues(t, 1, atomic.LoadInt32(calls))
+}
+
+// TestRetryBackoff tests that the backoff grows exponentially and stays within its bounds
+func TestRetryBackoff(t *testing.T) {
+	policy := &RetryPolicy{
+		MaxAttempts: 10,
+		MinBackoff:  100 * time.Millisecond,
+		MaxBackoff:  time.Second,
+	}
+
+	for i := 0; i < 100; i++ {
+		d := policy.backoff(1)
+		assert.True(t, d >= 50*time.Millisecond && d <= 100*time.Millisecond, d)
+		d = policy.backoff(3)
+		assert.True(t, d >= 200*time.Millisecond && d <= 400*time.Millisecond, d)
+		d = policy.backoff(9)
+		assert.True(t, d >= 500*time.Millisecond && d <= time.Second, d)
+	}
+}
+
+// TestParseRetryAfter tests both forms of the Retry-After header
+func TestParseRetryAfter(t *testing.T) {
+	d, ok := parseRetryAfter("120")
+	assert.True(t, ok)
+	assert.Equal(t, 2*time.Minute, d)
+
+	d, ok = parseRetryAfter(time.Now().Add(time.Hour).UTC().Format(http.TimeFormat))
+	assert.True(t, ok)
+	assert.True(t, d > 59*time.Minute && d <= time.Hour, d)
+
+	_, ok = parseRetryAfter("soon")
+	assert.False(t, ok)
+
+	_, ok = parseRetryAfter("")
+	assert.False(t, ok)
+}
@@ -0,0 +1,453 @@
+// Package emulator provides a stateful, in-memory implementation of the UpCloud API for tests and local
+// development. It serves the server, storage, IP address, firewall, tag, network, router, host and Object Storage
+// endpoints along with the read-only account, zone, plan, server size and timezone endpoints.
+//
+// Resources go through the same state transitions as in the API, e.g. a new server starts out in "maintenance" and
+// enters "started" once it has been provisioned. Each transition takes the delay set with WithTransitionDelay.
+//
+// An Emulator is an http.Handler. NewServer starts one on a local address, ready to be used by a client:
+//
+//	srv := emulator.NewServer()
+//	defer srv.Close()
+//
+//	svc := service.New(srv.Client())
+package emulator
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/UpCloudLtd/upcloud-go-api/upcloud"
+	"github.com/UpCloudLtd/upcloud-go-api/upcloud/client"
+)
+
+// Prefixes of the UUIDs of the different resource types
+const (
+	uuidPrefixServer        = "00"
+	uuidPrefixStorage       = "01"
+	uuidPrefixNetwork       = "03"
+	uuidPrefixRouter        = "04"
+	uuidPrefixObjectStorage = "06"
+)
+
+// Option configures an emulator created with New
+type Option func(*Emulator)
+
+// WithCredentials makes the emulator reject requests that don't authenticate with the specified username and
+// password. By default any credentials are accepted.
+func WithCredentials(username, password string) Option {
+	return func(e *Emulator) {
+		e.username = username
+		e.password = password
+	}
+}
+
+// WithTransitionDelay sets the time each state transition takes, e.g. from "maintenance" to "started". With the
+// default of zero, a resource enters its next state by the time the following request is handled.
+func WithTransitionDelay(delay time.Duration) Option {
+	return func(e *Emulator) {
+		e.transitionDelay = delay
+	}
+}
+
+// WithHosts adds private cloud hosts to the emulated account. Servers started in the zone of a host are placed on
+// it.
+func WithHosts(hosts ...upcloud.Host) Option {
+	return func(e *Emulator) {
+		for _, host := range hosts {
+			host := host
+			e.hosts = append(e.hosts, &host)
+		}
+	}
+}
+
+// Emulator is an in-memory implementation of the UpCloud API. It's safe for concurrent use.
+type Emulator struct {
+	username        string
+	password        string
+	transitionDelay time.Duration
+	routes          []route
+
+	mu             sync.Mutex
+	now            func() time.Time
+	pending        []transition
+	servers        []*server
+	storages       []*storage
+	imports        map[string]*storageImport
+	ipAddresses    []*upcloud.IPAddress
+	tags           []*upcloud.Tag
+	networks       []*upcloud.Network
+	routers        []*upcloud.Router
+	hosts          []*upcloud.Host
+	objectStorages []*upcloud.ObjectStorageDetails
+	macs           int
+}
+
+// New returns an emulator with an empty account. The public templates, CD-ROMs and networks of all zones are in
+// place.
+func New(opts ...Option) *Emulator {
+	e := &Emulator{
+		now:     time.Now,
+		imports: make(map[string]*storageImport),
+	}
+
+	for _, opt := range opts {
+		opt(e)
+	}
+
+	e.addPublicStorages()
+	e.addPublicNetworks()
+	e.addRoutes()
+
+	return e
+}
+
+// Server is an emulator serving the API on a local address
+type Server struct {
+	*Emulator
+
+	// URL is the base URL of the emulator, for use with client.WithBaseURL
+	URL string
+
+	httpServer *httptest.Server
+}
+
+// NewServer starts an emulator on a local address. The server should be closed when it's no longer needed.
+func NewServer(opts ...Option) *Server {
+	e := New(opts...)
+	httpServer := httptest.NewServer(e)
+
+	return &Server{
+		Emulator:   e,
+		URL:        httpServer.URL,
+		httpServer: httpServer,
+	}
+}
+
+// Close shuts down the server
+func (s *Server) Close() {
+	s.httpServer.Close()
+}
+
+// Client returns a client talking to the server, authenticated with the credentials the emulator expects. Client
+// options are applied after the base URL is set.
+func (s *Server) Client(opts ...client.Option) *client.Client {
+	username, password := s.username, s.password
+	if username == "" {
+		username, password = "emulator", "emulator"
+	}
+
+	return client.NewWithOptions(username, password, append([]client.Option{client.WithBaseURL(s.URL)}, opts...)...)
+}
+
+// object is a JSON object in a response
+type object map[string]interface{}
+
+// Returns the list wrapped in an object with the specified key, the way the API nests its lists, e.g.
+// {"server": [...]}
+func list(key string, items []object) object {
+	if items == nil {
+		items = []object{}
+	}
+
+	return object{key: items}
+}
+
+// params holds the values of the placeholders in the pattern of a route
+type params map[string]string
+
+// handlerFunc handles a request matched by a route. It's called with the lock of the emulator held.
+type handlerFunc func(w http.ResponseWriter, r *http.Request, p params)
+
+// route maps the requests with a method and a path pattern, e.g. "/server/{uuid}/start", to a handler
+type route struct {
+	method   string
+	segments []string
+	handle   handlerFunc
+}
+
+// Returns the placeholder values if the route matches the path segments
+func (r *route) match(segments []string) (params, bool) {
+	if len(segments) != len(r.segments) {
+		return nil, false
+	}
+
+	p := params{}
+	for i, segment := range r.segments {
+		if strings.HasPrefix(segment, "{") {
+			p[strings.Trim(segment, "{}")] = segments[i]
+		} else if segment != segments[i] {
+			return nil, false
+		}
+	}
+
+	return p, true
+}
+
+// Registers a handler for the requests with the specified method and path pattern
+func (e *Emulator) handle(method, pattern string, h handlerFunc) {
+	e.routes = append(e.routes, route{
+		method:   method,
+		segments: splitPath(pattern),
+		handle:   h,
+	})
+}
+
+// Registers the handlers of all endpoints
+func (e *Emulator) addRoutes() {
+	e.addStaticRoutes()
+	e.addServerRoutes()
+	e.addStorageRoutes()
+	e.addIPAddressRoutes()
+	e.addFirewallRoutes()
+	e.addTagRoutes()
+	e.addNetworkRoutes()
+	e.addHostRoutes()
+	e.addObjectStorageRoutes()
+}
+
+// Splits a path into its segments, ignoring leading and trailing slashes
+func splitPath(path string) []string {
+	path = strings.Trim(path, "/")
+	if path == "" {
+		return nil
+	}
+
+	return strings.Split(path, "/")
+}
+
+var apiVersionPattern = regexp.MustCompile(`^\d+\.\d+$`)
+
+// ServeHTTP implements the http.Handler interface
+func (e *Emulator) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	segments := splitPath(r.URL.Path)
+
+	// Direct uploads go to a separate host in the API and don't need authentication
+	if len(segments) == 3 && segments[0] == "uploader" && segments[1] == "session" && r.Method == http.MethodPut {
+		e.handleUpload(w, r, segments[2])
+		return
+	}
+
+	if !e.authenticated(r) {
+		writeError(w, http.StatusUnauthorized, "AUTHENTICATION_FAILED",
+			"Authentication failed using the given username and password.")
+		return
+	}
+
+	if len(segments) == 0 || !apiVersionPattern.MatchString(segments[0]) {
+		writeError(w, http.StatusNotFound, "NOT_FOUND", "The requested resource doesn't exist.")
+		return
+	}
+	segments = segments[1:]
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.advance()
+
+	methodAllowed := true
+	for i := range e.routes {
+		p, ok := e.routes[i].match(segments)
+		if !ok {
+			continue
+		}
+		if e.routes[i].method != r.Method {
+			methodAllowed = false
+			continue
+		}
+
+		e.routes[i].handle(w, r, p)
+		return
+	}
+
+	if !methodAllowed {
+		writeError(w, http.StatusMethodNotAllowed, "METHOD_NOT_ALLOWED", "The request method isn't allowed.")
+		return
+	}
+
+	writeError(w, http.StatusNotFound, "NOT_FOUND", "The requested resource doesn't exist.")
+}
+
+// Returns whether the request carries the credentials the emulator expects
+func (e *Emulator) authenticated(r *http.Request) bool {
+	username, password, ok := r.BasicAuth()
+	if !ok {
+		return false
+	}
+
+	return e.username == "" || (username == e.username && password == e.password)
+}
+
+// transition is a state change that takes effect once its time has come
+type transition struct {
+	at    time.Time
+	apply func()
+}
+
+// Schedules a state change to take effect after the specified number of transition delays
+func (e *Emulator) later(steps int, apply func()) {
+	e.pending = append(e.pending, transition{
+		at:    e.now().Add(time.Duration(steps) * e.transitionDelay),
+		apply: apply,
+	})
+}
+
+// Applies the state changes whose time has come, in the order they were scheduled in
+func (e *Emulator) advance() {
+	sort.SliceStable(e.pending, func(i, j int) bool {
+		return e.pending[i].at.Before(e.pending[j].at)
+	})
+
+	now := e.now()
+	for len(e.pending) > 0 && !e.pending[0].at.After(now) {
+		t := e.pending[0]
+		e.pending = e.pending[1:]
+		t.apply()
+	}
+}
+
+// Writes the specified value as a JSON response
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json; charset=UTF-8")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+// Writes an error response in the format of the API
+func writeError(w http.ResponseWriter, status int, code, message string) {
+	writeJSON(w, status, object{
+		"error": object{
+			"error_code":    code,
+			"error_message": message,
+		},
+	})
+}
+
+// Decodes the JSON body of the request. An error response is written if the body can't be decoded.
+func decodeBody(w http.ResponseWriter, r *http.Request, v interface{}) bool {
+	if err := json.NewDecoder(r.Body).Decode(v); err != nil {
+		writeError(w, http.StatusBadRequest, "JSON_MALFORMED", fmt.Sprintf("The request body is malformed: %s", err))
+		return false
+	}
+
+	return true
+}
+
+// Decodes the JSON body of a request that may also be sent without one. An error response is written if the body
+// can't be decoded.
+func decodeOptionalBody(w http.ResponseWriter, r *http.Request, v interface{}) bool {
+	err := json.NewDecoder(r.Body).Decode(v)
+	if err != nil && err != io.EOF {
+		writeError(w, http.StatusBadRequest, "JSON_MALFORMED", fmt.Sprintf("The request body is malformed: %s", err))
+		return false
+	}
+
+	return true
+}
+
+// Writes the error of a resource that doesn't exist, e.g. SERVER_NOT_FOUND
+func writeNotFound(w http.ResponseWriter, resource, id string) {
+	code := strings.ToUpper(strings.Replace(resource, " ", "_", -1)) + "_NOT_FOUND"
+	writeError(w, http.StatusNotFound, code, fmt.Sprintf("The %s %s does not exist.", resource, id))
+}
+
+var uuidPattern = regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{12}$`)
+
+// Returns whether the string is a well-formed UUID
+func validUUID(uuid string) bool {
+	return uuidPattern.MatchString(uuid)
+}
+
+// flexInt is an integer that some requests send as a JSON number and others as a string
+type flexInt int
+
+// UnmarshalJSON implements the json.Unmarshaler interface
+func (i *flexInt) UnmarshalJSON(b []byte) error {
+	s := strings.Trim(string(b), `"`)
+	if s == "" || s == "null" {
+		*i = 0
+		return nil
+	}
+
+	v, err := strconv.Atoi(s)
+	if err != nil {
+		return fmt.Errorf("invalid integer %s", b)
+	}
+	*i = flexInt(v)
+
+	return nil
+}
+
+// Returns a random UUID that starts with the prefix the API uses for the resource type
+func newUUID(prefix string) string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+	b[6] = b[6]&0x0f | 0x40
+	b[8] = b[8]&0x3f | 0x80
+
+	s := prefix + hex.EncodeToString(b)[len(prefix):]
+
+	return fmt.Sprintf("%s-%s-%s-%s-%s", s[0:8], s[8:12], s[12:16], s[16:20], s[20:32])
+}
+
+// Returns a new unique MAC address
+func (e *Emulator) newMAC() string {
+	e.macs++
+
+	return fmt.Sprintf("ee:1b:db:%02x:%02x:%02x", (e.macs>>16)&0xff, (e.macs>>8)&0xff, e.macs&0xff)
+}
+
+// Returns a random password for remote access
+func randomPassword() string {
+	const letters = "abcdefghijkmnopqrstuvwxyzABCDEFGHJKLMNPQRSTUVWXYZ23456789"
+
+	b := make([]byte, 8)
+	_, _ = rand.Read(b)
+	for i := range b {
+		b[i] = letters[int(b[i])%len(letters)]
+	}
+
+	return string(b)
+}
+
+// Returns a Boolean that is upcloud.False when the specified value is empty
+func booleanOrFalse(b upcloud.Boolean) upcloud.Boolean {
+	if b.Empty() {
+		return upcloud.False
+	}
+
+	return b
+}
+
+// Returns the first non-empty string
+func firstNonEmpty(values ...string) string {
+	for _, value := range values {
+		if value != "" {
+			return value
+		}
+	}
+
+	return ""
+}
+
+// Returns whether the list contains the value
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,185 @@
+package emulator
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/UpCloudLtd/upcloud-go-api/upcloud"
+	"github.com/UpCloudLtd/upcloud-go-api/upcloud/client"
+	"github.com/UpCloudLtd/upcloud-go-api/upcloud/request"
+	"github.com/UpCloudLtd/upcloud-go-api/upcloud/service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Returns a service talking to a new emulator. Responses with fields unknown to the models fail the test.
+func newTestService(t *testing.T, opts ...Option) (*service.Service, *Server) {
+	srv := NewServer(opts...)
+	t.Cleanup(srv.Close)
+
+	svc := service.New(srv.Client(client.WithRetryPolicy(nil)))
+	svc.SetWaitPolicy(&service.WaitPolicy{
+		MinInterval: time.Millisecond,
+		MaxInterval: time.Millisecond,
+	})
+	svc.SetSchemaCheck(&service.SchemaCheck{
+		UnknownFields: true,
+		Report: func(err *service.SchemaError) {
+			t.Errorf("unexpected schema mismatch: %v", err)
+		},
+	})
+
+	return svc, srv
+}
+
+// Creates a server with a public IPv4 address and a cloned template in the specified zone and waits for it to start
+func createTestServer(t *testing.T, svc *service.Service, zone string) *upcloud.ServerDetails {
+	serverDetails, err := svc.CreateServer(&request.CreateServerRequest{
+		Zone:     zone,
+		Title:    "emulator-test",
+		Hostname: "emulator-test.example.com",
+		Plan:     "1xCPU-2GB",
+		StorageDevices: []request.CreateServerStorageDevice{
+			{
+				Action:  request.CreateServerStorageDeviceActionClone,
+				Storage: "01000000-0000-4000-8000-000030060200",
+				Title:   "disk1",
+				Size:    30,
+				Tier:    upcloud.StorageTierMaxIOPS,
+			},
+		},
+		Networking: &request.CreateServerNetworking{
+			Interfaces: []request.CreateServerInterface{
+				{
+					IPAddresses: []request.CreateServerIPAddress{{Family: upcloud.IPAddressFamilyIPv4}},
+					Type:        upcloud.NetworkTypePublic,
+				},
+			},
+		},
+	})
+	require.NoError(t, err)
+
+	serverDetails, err = svc.WaitForServerState(&request.WaitForServerStateRequest{
+		UUID:         serverDetails.UUID,
+		DesiredState: upcloud.ServerStateStarted,
+		Timeout:      time.Second,
+	})
+	require.NoError(t, err)
+
+	return serverDetails
+}
+
+// Stops the server and waits for it to enter the "stopped" state
+func stopTestServer(t *testing.T, svc *service.Service, uuid string) {
+	_, err := svc.StopServer(&request.StopServerRequest{UUID: uuid})
+	require.NoError(t, err)
+
+	_, err = svc.WaitForServerState(&request.WaitForServerStateRequest{
+		UUID:         uuid,
+		DesiredState: upcloud.ServerStateStopped,
+		Timeout:      time.Second,
+	})
+	require.NoError(t, err)
+}
+
+// TestStaticEndpoints tests that the account, zone, plan, price, server size and timezone endpoints respond with
+// content the models can decode
+func TestStaticEndpoints(t *testing.T) {
+	svc, _ := newTestService(t)
+
+	account, err := svc.GetAccount()
+	require.NoError(t, err)
+	assert.Equal(t, "emulator", account.UserName)
+
+	zones, err := svc.GetZones()
+	require.NoError(t, err)
+	assert.Contains(t, zones.Zones, upcloud.Zone{ID: "fi-hel1", Description: "Helsinki #1", Public: upcloud.True})
+
+	plans, err := svc.GetPlans()
+	require.NoError(t, err)
+	assert.NotEmpty(t, plans.Plans)
+
+	priceZones, err := svc.GetPriceZones()
+	require.NoError(t, err)
+	assert.Len(t, priceZones.PriceZones, len(zones.Zones))
+
+	configurations, err := svc.GetServerConfigurations()
+	require.NoError(t, err)
+	assert.NotEmpty(t, configurations.ServerConfigurations)
+
+	timeZones, err := svc.GetTimeZones()
+	require.NoError(t, err)
+	assert.Contains(t, timeZones.TimeZones, "UTC")
+}
+
+// TestAuthentication tests that the configured credentials are required
+func TestAuthentication(t *testing.T) {
+	srv := NewServer(WithCredentials("user", "secret"))
+	defer srv.Close()
+
+	_, err := service.New(srv.Client()).GetAccount()
+	require.NoError(t, err)
+
+	c := client.NewWithOptions("user", "wrong", client.WithBaseURL(srv.URL), client.WithRetryPolicy(nil))
+	_, err = service.New(c).GetAccount()
+	require.Error(t, err)
+	assert.True(t, errors.Is(err, upcloud.ErrUnauthorized))
+}
+
+// TestRouting tests that unknown paths and methods are rejected
+func TestRouting(t *testing.T) {
+	srv := NewServer()
+	defer srv.Close()
+
+	tests := []struct {
+		method string
+		path   string
+		status int
+	}{
+		{http.MethodGet, "/1.3/account", http.StatusOK},
+		{http.MethodGet, "/1.3/nonexistent", http.StatusNotFound},
+		{http.MethodGet, "/account", http.StatusNotFound},
+		{http.MethodDelete, "/1.3/zone", http.StatusMethodNotAllowed},
+	}
+
+	for _, test := range tests {
+		req, err := http.NewRequest(test.method, srv.URL+test.path, nil)
+		require.NoError(t, err)
+		req.SetBasicAuth("emulator", "emulator")
+
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		resp.Body.Close()
+		assert.Equal(t, test.status, resp.StatusCode, "%s %s", test.method, test.path)
+	}
+}
+
+// TestTransitionDelay tests that state transitions take the configured time
+func TestTransitionDelay(t *testing.T) {
+	now := time.Now()
+	svc, srv := newTestService(t, WithTransitionDelay(time.Minute))
+	srv.now = func() time.Time { return now }
+
+	serverDetails, err := svc.CreateServer(&request.CreateServerRequest{
+		Zone:     "fi-hel1",
+		Title:    "emulator-test",
+		Hostname: "emulator-test.example.com",
+		StorageDevices: []request.CreateServerStorageDevice{
+			{Action: request.CreateServerStorageDeviceActionCreate, Title: "disk1", Size: 10},
+		},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, upcloud.ServerStateMaintenance, serverDetails.State)
+
+	now = now.Add(59 * time.Second)
+	serverDetails, err = svc.GetServerDetails(&request.GetServerDetailsRequest{UUID: serverDetails.UUID})
+	require.NoError(t, err)
+	assert.Equal(t, upcloud.ServerStateMaintenance, serverDetails.State)
+
+	now = now.Add(time.Second)
+	serverDetails, err = svc.GetServerDetails(&request.GetServerDetailsRequest{UUID: serverDetails.UUID})
+	require.NoError(t, err)
+	assert.Equal(t, upcloud.ServerStateStarted, serverDetails.State)
+}
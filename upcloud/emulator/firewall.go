@@ -0,0 +1,166 @@
+package emulator
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/UpCloudLtd/upcloud-go-api/upcloud"
+)
+
+// firewallRule decodes a firewall rule in a request. upcloud.FirewallRule can't be used as it expects the rule to be
+// wrapped in an object.
+type firewallRule upcloud.FirewallRule
+
+// Registers the handlers of the firewall endpoints
+func (e *Emulator) addFirewallRoutes() {
+	e.handle(http.MethodGet, "/server/{uuid}/firewall_rule", e.getFirewallRules)
+	e.handle(http.MethodGet, "/server/{uuid}/firewall_rule/{position}", e.getFirewallRule)
+	e.handle(http.MethodPost, "/server/{uuid}/firewall_rule", e.createFirewallRule)
+	e.handle(http.MethodPut, "/server/{uuid}/firewall_rule", e.replaceFirewallRules)
+	e.handle(http.MethodDelete, "/server/{uuid}/firewall_rule/{position}", e.deleteFirewallRule)
+}
+
+// Returns the index of the rule at the position in the firewall of the server. An error response is written if
+// there's no such rule.
+func lookupFirewallRule(w http.ResponseWriter, srv *server, position string) (int, bool) {
+	p, err := strconv.Atoi(position)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "FIREWALL_RULE_INVALID", fmt.Sprintf("The rule position %s is invalid.", position))
+		return 0, false
+	}
+
+	if p < 1 || p > len(srv.firewallRules) {
+		writeNotFound(w, "firewall rule", position)
+		return 0, false
+	}
+
+	return p - 1, true
+}
+
+// Validates a firewall rule. An error response is written if the rule is invalid.
+func validateFirewallRule(w http.ResponseWriter, rule firewallRule) bool {
+	fail := func(field, value string) bool {
+		writeError(w, http.StatusBadRequest, field+"_INVALID", fmt.Sprintf("The value %q of %s is invalid.", value, field))
+		return false
+	}
+
+	switch {
+	case rule.Direction != upcloud.FirewallRuleDirectionIn && rule.Direction != upcloud.FirewallRuleDirectionOut:
+		return fail("DIRECTION", rule.Direction)
+	case rule.Action != upcloud.FirewallRuleActionAccept && rule.Action != upcloud.FirewallRuleActionReject &&
+		rule.Action != upcloud.FirewallRuleActionDrop:
+		return fail("ACTION", rule.Action)
+	case rule.Family != upcloud.IPAddressFamilyIPv4 && rule.Family != upcloud.IPAddressFamilyIPv6:
+		return fail("FAMILY", rule.Family)
+	case rule.Protocol != "" && rule.Protocol != upcloud.FirewallRuleProtocolTCP &&
+		rule.Protocol != upcloud.FirewallRuleProtocolUDP && rule.Protocol != upcloud.FirewallRuleProtocolICMP:
+		return fail("PROTOCOL", rule.Protocol)
+	}
+
+	return true
+}
+
+// Sets the positions of the rules from their order
+func renumberFirewallRules(rules []upcloud.FirewallRule) {
+	for i := range rules {
+		rules[i].Position = i + 1
+	}
+}
+
+func (e *Emulator) getFirewallRules(w http.ResponseWriter, r *http.Request, p params) {
+	srv, ok := e.lookupServer(w, p["uuid"])
+	if !ok {
+		return
+	}
+
+	rules := append([]upcloud.FirewallRule{}, srv.firewallRules...)
+	writeJSON(w, http.StatusOK, object{"firewall_rules": object{"firewall_rule": rules}})
+}
+
+func (e *Emulator) getFirewallRule(w http.ResponseWriter, r *http.Request, p params) {
+	srv, ok := e.lookupServer(w, p["uuid"])
+	if !ok {
+		return
+	}
+
+	i, ok := lookupFirewallRule(w, srv, p["position"])
+	if !ok {
+		return
+	}
+
+	writeJSON(w, http.StatusOK, object{"firewall_rule": srv.firewallRules[i]})
+}
+
+func (e *Emulator) createFirewallRule(w http.ResponseWriter, r *http.Request, p params) {
+	srv, ok := e.lookupServer(w, p["uuid"])
+	if !ok {
+		return
+	}
+
+	var body struct {
+		FirewallRule firewallRule `json:"firewall_rule"`
+	}
+	if !decodeBody(w, r, &body) || !validateFirewallRule(w, body.FirewallRule) {
+		return
+	}
+
+	// Rules without a position, or with one past the end, are appended
+	i := body.FirewallRule.Position - 1
+	if i < 0 || i > len(srv.firewallRules) {
+		i = len(srv.firewallRules)
+	}
+
+	rules := append([]upcloud.FirewallRule{}, srv.firewallRules[:i]...)
+	rules = append(rules, upcloud.FirewallRule(body.FirewallRule))
+	srv.firewallRules = append(rules, srv.firewallRules[i:]...)
+	renumberFirewallRules(srv.firewallRules)
+
+	writeJSON(w, http.StatusCreated, object{"firewall_rule": srv.firewallRules[i]})
+}
+
+func (e *Emulator) replaceFirewallRules(w http.ResponseWriter, r *http.Request, p params) {
+	srv, ok := e.lookupServer(w, p["uuid"])
+	if !ok {
+		return
+	}
+
+	var body struct {
+		FirewallRules struct {
+			FirewallRule []firewallRule `json:"firewall_rule"`
+		} `json:"firewall_rules"`
+	}
+	if !decodeBody(w, r, &body) {
+		return
+	}
+
+	rules := []upcloud.FirewallRule{}
+	for _, rule := range body.FirewallRules.FirewallRule {
+		if !validateFirewallRule(w, rule) {
+			return
+		}
+		rules = append(rules, upcloud.FirewallRule(rule))
+	}
+
+	renumberFirewallRules(rules)
+	srv.firewallRules = rules
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (e *Emulator) deleteFirewallRule(w http.ResponseWriter, r *http.Request, p params) {
+	srv, ok := e.lookupServer(w, p["uuid"])
+	if !ok {
+		return
+	}
+
+	i, ok := lookupFirewallRule(w, srv, p["position"])
+	if !ok {
+		return
+	}
+
+	srv.firewallRules = append(srv.firewallRules[:i], srv.firewallRules[i+1:]...)
+	renumberFirewallRules(srv.firewallRules)
+
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,77 @@
+package emulator
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/UpCloudLtd/upcloud-go-api/upcloud"
+	"github.com/UpCloudLtd/upcloud-go-api/upcloud/request"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestFirewallRules tests that rules are inserted, replaced and deleted keeping their positions contiguous
+func TestFirewallRules(t *testing.T) {
+	svc, _ := newTestService(t)
+	serverDetails := createTestServer(t, svc, "fi-hel1")
+
+	rule := func(port string) upcloud.FirewallRule {
+		return upcloud.FirewallRule{
+			Direction:            upcloud.FirewallRuleDirectionIn,
+			Action:               upcloud.FirewallRuleActionAccept,
+			Family:               upcloud.IPAddressFamilyIPv4,
+			Protocol:             upcloud.FirewallRuleProtocolTCP,
+			DestinationPortStart: port,
+			DestinationPortEnd:   port,
+		}
+	}
+
+	err := svc.CreateFirewallRules(&request.CreateFirewallRulesRequest{
+		ServerUUID:    serverDetails.UUID,
+		FirewallRules: []upcloud.FirewallRule{rule("22"), rule("443")},
+	})
+	require.NoError(t, err)
+
+	inserted := rule("80")
+	inserted.Position = 2
+	created, err := svc.CreateFirewallRule(&request.CreateFirewallRuleRequest{
+		ServerUUID:   serverDetails.UUID,
+		FirewallRule: inserted,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 2, created.Position)
+
+	rules, err := svc.GetFirewallRules(&request.GetFirewallRulesRequest{ServerUUID: serverDetails.UUID})
+	require.NoError(t, err)
+	require.Len(t, rules.FirewallRules, 3)
+	for i, port := range []string{"22", "80", "443"} {
+		assert.Equal(t, i+1, rules.FirewallRules[i].Position)
+		assert.Equal(t, port, rules.FirewallRules[i].DestinationPortStart)
+	}
+
+	err = svc.DeleteFirewallRule(&request.DeleteFirewallRuleRequest{ServerUUID: serverDetails.UUID, Position: 1})
+	require.NoError(t, err)
+
+	details, err := svc.GetFirewallRuleDetails(&request.GetFirewallRuleDetailsRequest{ServerUUID: serverDetails.UUID, Position: 1})
+	require.NoError(t, err)
+	assert.Equal(t, "80", details.DestinationPortStart)
+
+	_, err = svc.GetFirewallRuleDetails(&request.GetFirewallRuleDetailsRequest{ServerUUID: serverDetails.UUID, Position: 3})
+	assert.True(t, errors.Is(err, upcloud.ErrNotFound))
+}
+
+// TestInvalidFirewallRule tests that rules with an invalid direction are rejected
+func TestInvalidFirewallRule(t *testing.T) {
+	svc, _ := newTestService(t)
+	serverDetails := createTestServer(t, svc, "fi-hel1")
+
+	_, err := svc.CreateFirewallRule(&request.CreateFirewallRuleRequest{
+		ServerUUID: serverDetails.UUID,
+		FirewallRule: upcloud.FirewallRule{
+			Direction: "sideways",
+			Action:    upcloud.FirewallRuleActionAccept,
+			Family:    upcloud.IPAddressFamilyIPv4,
+		},
+	})
+	assert.True(t, errors.Is(err, upcloud.ErrBadRequest))
+}
@@ -0,0 +1,106 @@
+package emulator
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/UpCloudLtd/upcloud-go-api/upcloud"
+)
+
+// Registers the handlers of the host endpoints
+func (e *Emulator) addHostRoutes() {
+	e.handle(http.MethodGet, "/host", e.getHosts)
+	e.handle(http.MethodGet, "/host/{id}", e.getHost)
+	e.handle(http.MethodPatch, "/host/{id}", e.modifyHost)
+}
+
+// Returns the host with the specified ID. An error response is written if there's no such host.
+func (e *Emulator) lookupHost(w http.ResponseWriter, id string) (*upcloud.Host, bool) {
+	n, err := strconv.Atoi(id)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "HOST_INVALID", fmt.Sprintf("The host ID %s is invalid.", id))
+		return nil, false
+	}
+
+	for _, host := range e.hosts {
+		if host.ID == n {
+			return host, true
+		}
+	}
+
+	writeNotFound(w, "host", id)
+	return nil, false
+}
+
+// Returns the host the way it appears in responses, with the current statistics of the host
+func (e *Emulator) hostObject(host *upcloud.Host) object {
+	now := e.now().UTC().Truncate(time.Second)
+
+	cores, memory := 0, 0
+	for _, srv := range e.servers {
+		if srv.Host == host.ID {
+			cores += srv.CoreNumber
+			memory += srv.MemoryAmount
+		}
+	}
+
+	return object{
+		"id":              host.ID,
+		"description":     host.Description,
+		"zone":            host.Zone,
+		"windows_enabled": booleanOrFalse(host.WindowsEnabled),
+		"stats": list("stat", []object{
+			{"name": "cpu_idle", "timestamp": now, "value": 100 - float64(minInt(cores, 20))*5},
+			{"name": "memory_free", "timestamp": now, "value": maxInt(131072-memory, 0)},
+		}),
+	}
+}
+
+func (e *Emulator) getHosts(w http.ResponseWriter, r *http.Request, p params) {
+	items := []object{}
+	for _, host := range e.hosts {
+		items = append(items, e.hostObject(host))
+	}
+
+	writeJSON(w, http.StatusOK, object{"hosts": list("host", items)})
+}
+
+func (e *Emulator) getHost(w http.ResponseWriter, r *http.Request, p params) {
+	host, ok := e.lookupHost(w, p["id"])
+	if !ok {
+		return
+	}
+
+	writeJSON(w, http.StatusOK, object{"host": e.hostObject(host)})
+}
+
+func (e *Emulator) modifyHost(w http.ResponseWriter, r *http.Request, p params) {
+	host, ok := e.lookupHost(w, p["id"])
+	if !ok {
+		return
+	}
+
+	var body struct {
+		Host struct {
+			Description string `json:"description"`
+		} `json:"host"`
+	}
+	if !decodeBody(w, r, &body) {
+		return
+	}
+
+	host.Description = body.Host.Description
+
+	writeJSON(w, http.StatusOK, object{"host": e.hostObject(host)})
+}
+
+// Returns the smaller of the two integers
+func minInt(a, b int) int {
+	if a < b {
+		return a
+	}
+
+	return b
+}
@@ -0,0 +1,36 @@
+package emulator
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/UpCloudLtd/upcloud-go-api/upcloud"
+	"github.com/UpCloudLtd/upcloud-go-api/upcloud/request"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestHosts tests that private hosts are listed, can be modified and receive the servers of their zone
+func TestHosts(t *testing.T) {
+	svc, _ := newTestService(t, WithHosts(upcloud.Host{
+		ID:             7653311107,
+		Description:    "My Host #1",
+		Zone:           "private-zone-id",
+		WindowsEnabled: upcloud.False,
+	}))
+
+	hosts, err := svc.GetHosts()
+	require.NoError(t, err)
+	require.Len(t, hosts.Hosts, 1)
+	assert.Len(t, hosts.Hosts[0].Stats, 2)
+
+	host, err := svc.ModifyHost(&request.ModifyHostRequest{ID: 7653311107, Description: "renamed"})
+	require.NoError(t, err)
+	assert.Equal(t, "renamed", host.Description)
+
+	serverDetails := createTestServer(t, svc, "private-zone-id")
+	assert.Equal(t, 7653311107, serverDetails.Host)
+
+	_, err = svc.GetHostDetails(&request.GetHostDetailsRequest{ID: 1})
+	assert.True(t, errors.Is(err, upcloud.ErrNotFound))
+}
@@ -0,0 +1,283 @@
+package emulator
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/UpCloudLtd/upcloud-go-api/upcloud"
+)
+
+// Registers the handlers of the IP address endpoints
+func (e *Emulator) addIPAddressRoutes() {
+	e.handle(http.MethodGet, "/ip_address", e.getIPAddresses)
+	e.handle(http.MethodGet, "/ip_address/{address}", e.getIPAddress)
+	e.handle(http.MethodPost, "/ip_address", e.assignIPAddress)
+	e.handle(http.MethodPatch, "/ip_address/{address}", e.modifyIPAddress)
+	e.handle(http.MethodDelete, "/ip_address/{address}", e.releaseIPAddress)
+}
+
+// Returns the server with the interface that has the MAC address, or nil if there's no such server
+func (e *Emulator) serverWithMAC(mac string) (*server, *upcloud.ServerInterface) {
+	if mac == "" {
+		return nil, nil
+	}
+
+	for _, srv := range e.servers {
+		for i := range srv.Networking.Interfaces {
+			if srv.Networking.Interfaces[i].MAC == mac {
+				return srv, &srv.Networking.Interfaces[i]
+			}
+		}
+	}
+
+	return nil, nil
+}
+
+// Returns the public and utility IP addresses of the server
+func (e *Emulator) serverIPAddresses(srv *server) []*upcloud.IPAddress {
+	var addresses []*upcloud.IPAddress
+	for _, iface := range srv.Networking.Interfaces {
+		for _, ip := range e.ipAddresses {
+			if ip.MAC == iface.MAC && ip.Access != upcloud.IPAddressAccessPrivate {
+				addresses = append(addresses, ip)
+			}
+		}
+	}
+
+	return addresses
+}
+
+// Removes the addresses of a deleted interface. Floating addresses stay in the account, unassigned.
+func (e *Emulator) releaseInterfaceAddresses(mac string) {
+	kept := e.ipAddresses[:0]
+	for _, ip := range e.ipAddresses {
+		switch {
+		case ip.MAC != mac:
+			kept = append(kept, ip)
+		case ip.Floating.Bool():
+			ip.MAC = ""
+			kept = append(kept, ip)
+		}
+	}
+
+	e.ipAddresses = kept
+}
+
+// Returns the public or utility IP address. An error response is written if there's no such address.
+func (e *Emulator) lookupIPAddress(w http.ResponseWriter, address string) (*upcloud.IPAddress, bool) {
+	for _, ip := range e.ipAddresses {
+		if ip.Address == address && ip.Access != upcloud.IPAddressAccessPrivate {
+			return ip, true
+		}
+	}
+
+	writeNotFound(w, "ip address", address)
+	return nil, false
+}
+
+// Returns the IP address the way it appears in responses
+func (e *Emulator) ipAddressObject(ip *upcloud.IPAddress) object {
+	o := object{
+		"access":       ip.Access,
+		"address":      ip.Address,
+		"family":       ip.Family,
+		"floating":     booleanOrFalse(ip.Floating),
+		"part_of_plan": booleanOrFalse(ip.PartOfPlan),
+		"ptr_record":   ip.PTRRecord,
+		"zone":         ip.Zone,
+	}
+
+	if srv, _ := e.serverWithMAC(ip.MAC); srv != nil {
+		o["mac"] = ip.MAC
+		o["server"] = srv.UUID
+	}
+
+	return o
+}
+
+// Returns the default reverse DNS name of the address
+func defaultPTRRecord(address, zone string) string {
+	return strings.NewReplacer(".", "-", ":", "-").Replace(address) + "." + zone + ".upcloud.host"
+}
+
+func (e *Emulator) getIPAddresses(w http.ResponseWriter, r *http.Request, p params) {
+	items := []object{}
+	for _, ip := range e.ipAddresses {
+		if ip.Access != upcloud.IPAddressAccessPrivate {
+			items = append(items, e.ipAddressObject(ip))
+		}
+	}
+
+	writeJSON(w, http.StatusOK, object{"ip_addresses": list("ip_address", items)})
+}
+
+func (e *Emulator) getIPAddress(w http.ResponseWriter, r *http.Request, p params) {
+	ip, ok := e.lookupIPAddress(w, p["address"])
+	if !ok {
+		return
+	}
+
+	writeJSON(w, http.StatusOK, object{"ip_address": e.ipAddressObject(ip)})
+}
+
+func (e *Emulator) assignIPAddress(w http.ResponseWriter, r *http.Request, p params) {
+	var body struct {
+		IPAddress struct {
+			Access   string          `json:"access"`
+			Family   string          `json:"family"`
+			Server   string          `json:"server"`
+			Floating upcloud.Boolean `json:"floating"`
+			MAC      string          `json:"mac"`
+			Zone     string          `json:"zone"`
+		} `json:"ip_address"`
+	}
+	if !decodeBody(w, r, &body) {
+		return
+	}
+
+	req := body.IPAddress
+	access := firstNonEmpty(req.Access, upcloud.IPAddressAccessPublic)
+	family := firstNonEmpty(req.Family, upcloud.IPAddressFamilyIPv4)
+
+	if access != upcloud.IPAddressAccessPublic && access != upcloud.IPAddressAccessUtility {
+		writeError(w, http.StatusBadRequest, "ACCESS_INVALID", fmt.Sprintf("The access %s is invalid.", access))
+		return
+	}
+	if family != upcloud.IPAddressFamilyIPv4 && family != upcloud.IPAddressFamilyIPv6 {
+		writeError(w, http.StatusBadRequest, "FAMILY_INVALID", fmt.Sprintf("The address family %s is invalid.", family))
+		return
+	}
+
+	var srv *server
+	var iface *upcloud.ServerInterface
+	zone := req.Zone
+
+	if req.Floating.Bool() {
+		if family != upcloud.IPAddressFamilyIPv4 || access != upcloud.IPAddressAccessPublic {
+			writeError(w, http.StatusBadRequest, "FLOATING_INVALID", "Only public IPv4 addresses can be floating.")
+			return
+		}
+
+		if req.MAC != "" {
+			if srv, iface = e.serverWithMAC(req.MAC); srv == nil {
+				writeNotFound(w, "mac", req.MAC)
+				return
+			}
+			zone = srv.Zone
+		}
+	} else {
+		var ok bool
+		if srv, ok = e.lookupServer(w, req.Server); !ok {
+			return
+		}
+		zone = srv.Zone
+
+		// The address goes to the first interface of the right type
+		for i := range srv.Networking.Interfaces {
+			if srv.Networking.Interfaces[i].Type == access {
+				iface = &srv.Networking.Interfaces[i]
+				break
+			}
+		}
+	}
+
+	if srv != nil && (iface == nil || iface.Type != access) {
+		writeError(w, http.StatusBadRequest, "INTERFACE_NOT_FOUND", fmt.Sprintf("The server has no %s interface.", access))
+		return
+	}
+
+	if _, ok := e.zone(zone); !ok {
+		writeError(w, http.StatusBadRequest, "ZONE_INVALID", fmt.Sprintf("The zone %s does not exist.", zone))
+		return
+	}
+
+	ip, ok := e.newInterfaceAddress(w, e.zoneNetwork(zone, access, family), access, family, "", nil)
+	if !ok {
+		return
+	}
+
+	ip.Zone = zone
+	ip.Floating = booleanOrFalse(req.Floating)
+	ip.PTRRecord = defaultPTRRecord(ip.Address, zone)
+	if iface != nil {
+		ip.MAC = iface.MAC
+	}
+	e.ipAddresses = append(e.ipAddresses, ip)
+
+	writeJSON(w, http.StatusCreated, object{"ip_address": e.ipAddressObject(ip)})
+}
+
+func (e *Emulator) modifyIPAddress(w http.ResponseWriter, r *http.Request, p params) {
+	ip, ok := e.lookupIPAddress(w, p["address"])
+	if !ok {
+		return
+	}
+
+	// The presence of the MAC address matters, null detaches a floating address
+	var body struct {
+		IPAddress map[string]json.RawMessage `json:"ip_address"`
+	}
+	if !decodeBody(w, r, &body) {
+		return
+	}
+
+	var ptrRecord, mac string
+	_, hasMAC := body.IPAddress["mac"]
+	if raw, ok := body.IPAddress["ptr_record"]; ok {
+		_ = json.Unmarshal(raw, &ptrRecord)
+	}
+	if hasMAC {
+		_ = json.Unmarshal(body.IPAddress["mac"], &mac)
+	}
+
+	if hasMAC {
+		if !ip.Floating.Bool() {
+			writeError(w, http.StatusBadRequest, "FLOATING_INVALID", "Only floating addresses can be moved.")
+			return
+		}
+
+		if mac != "" {
+			srv, iface := e.serverWithMAC(mac)
+			if srv == nil {
+				writeNotFound(w, "mac", mac)
+				return
+			}
+			if srv.Zone != ip.Zone || iface.Type != upcloud.NetworkTypePublic {
+				writeError(w, http.StatusBadRequest, "MAC_INVALID",
+					fmt.Sprintf("The interface %s isn't a public interface in zone %s.", mac, ip.Zone))
+				return
+			}
+		}
+
+		ip.MAC = mac
+	}
+
+	if ptrRecord != "" {
+		ip.PTRRecord = ptrRecord
+	}
+
+	writeJSON(w, http.StatusAccepted, object{"ip_address": e.ipAddressObject(ip)})
+}
+
+func (e *Emulator) releaseIPAddress(w http.ResponseWriter, r *http.Request, p params) {
+	ip, ok := e.lookupIPAddress(w, p["address"])
+	if !ok {
+		return
+	}
+
+	if srv, _ := e.serverWithMAC(ip.MAC); srv != nil && !ip.Floating.Bool() && srv.State != upcloud.ServerStateStopped {
+		writeServerStateIllegal(w, srv)
+		return
+	}
+
+	for i, candidate := range e.ipAddresses {
+		if candidate == ip {
+			e.ipAddresses = append(e.ipAddresses[:i], e.ipAddresses[i+1:]...)
+			break
+		}
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,86 @@
+package emulator
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/UpCloudLtd/upcloud-go-api/upcloud"
+	"github.com/UpCloudLtd/upcloud-go-api/upcloud/request"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestAssignModifyReleaseIPAddress tests that an address can be added to a server and released
+func TestAssignModifyReleaseIPAddress(t *testing.T) {
+	svc, _ := newTestService(t)
+	serverDetails := createTestServer(t, svc, "fi-hel1")
+	stopTestServer(t, svc, serverDetails.UUID)
+
+	ipAddress, err := svc.AssignIPAddress(&request.AssignIPAddressRequest{
+		Access:     upcloud.IPAddressAccessPublic,
+		Family:     upcloud.IPAddressFamilyIPv4,
+		ServerUUID: serverDetails.UUID,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, serverDetails.UUID, ipAddress.ServerUUID)
+	assert.Equal(t, "fi-hel1", ipAddress.Zone)
+
+	ipAddress, err = svc.ModifyIPAddress(&request.ModifyIPAddressRequest{
+		IPAddress: ipAddress.Address,
+		PTRRecord: "example.com",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "example.com", ipAddress.PTRRecord)
+
+	serverDetails, err = svc.GetServerDetails(&request.GetServerDetailsRequest{UUID: serverDetails.UUID})
+	require.NoError(t, err)
+	assert.Len(t, serverDetails.IPAddresses, 2)
+
+	err = svc.ReleaseIPAddress(&request.ReleaseIPAddressRequest{IPAddress: ipAddress.Address})
+	require.NoError(t, err)
+
+	_, err = svc.GetIPAddressDetails(&request.GetIPAddressDetailsRequest{Address: ipAddress.Address})
+	assert.True(t, errors.Is(err, upcloud.ErrNotFound))
+}
+
+// TestFloatingIPAddress tests that a floating address can be moved between servers and left unassigned
+func TestFloatingIPAddress(t *testing.T) {
+	svc, _ := newTestService(t)
+	first := createTestServer(t, svc, "fi-hel1")
+	second := createTestServer(t, svc, "fi-hel1")
+
+	ipAddress, err := svc.AssignIPAddress(&request.AssignIPAddressRequest{
+		Family:   upcloud.IPAddressFamilyIPv4,
+		Floating: upcloud.True,
+		Zone:     "fi-hel1",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, upcloud.True, ipAddress.Floating)
+	assert.Empty(t, ipAddress.ServerUUID)
+
+	ipAddress, err = svc.ModifyIPAddress(&request.ModifyIPAddressRequest{
+		IPAddress: ipAddress.Address,
+		MAC:       first.Networking.Interfaces[0].MAC,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, first.UUID, ipAddress.ServerUUID)
+
+	ipAddress, err = svc.ModifyIPAddress(&request.ModifyIPAddressRequest{
+		IPAddress: ipAddress.Address,
+		MAC:       second.Networking.Interfaces[0].MAC,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, second.UUID, ipAddress.ServerUUID)
+
+	// Deleting the server keeps the floating address
+	stopTestServer(t, svc, second.UUID)
+	err = svc.DeleteServer(&request.DeleteServerRequest{UUID: second.UUID})
+	require.NoError(t, err)
+
+	ipAddress, err = svc.GetIPAddressDetails(&request.GetIPAddressDetailsRequest{Address: ipAddress.Address})
+	require.NoError(t, err)
+	assert.Empty(t, ipAddress.ServerUUID)
+
+	err = svc.ReleaseIPAddress(&request.ReleaseIPAddressRequest{IPAddress: ipAddress.Address})
+	require.NoError(t, err)
+}
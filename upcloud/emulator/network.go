@@ -0,0 +1,809 @@
+package emulator
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"strconv"
+
+	"github.com/UpCloudLtd/upcloud-go-api/upcloud"
+)
+
+// ipAddressRequest is an IP address requested for an interface
+type ipAddressRequest struct {
+	Family  string `json:"family"`
+	Address string `json:"address"`
+}
+
+// interfaceRequest is the body of a create or modify interface request, and an interface in a create server request
+type interfaceRequest struct {
+	Type        string  `json:"type"`
+	Network     string  `json:"network"`
+	Index       flexInt `json:"index"`
+	IPAddresses struct {
+		IPAddress []ipAddressRequest `json:"ip_address"`
+	} `json:"ip_addresses"`
+	SourceIPFiltering upcloud.Boolean `json:"source_ip_filtering"`
+	Bootable          upcloud.Boolean `json:"bootable"`
+}
+
+// networkRequest is the body of a create or modify network request
+type networkRequest struct {
+	Name       string                 `json:"name"`
+	Zone       string                 `json:"zone"`
+	Router     string                 `json:"router"`
+	IPNetworks upcloud.IPNetworkSlice `json:"ip_networks"`
+}
+
+// Registers the handlers of the network, interface and router endpoints
+func (e *Emulator) addNetworkRoutes() {
+	e.handle(http.MethodGet, "/network", e.getNetworks)
+	e.handle(http.MethodGet, "/network/{uuid}", e.getNetwork)
+	e.handle(http.MethodPost, "/network", e.createNetwork)
+	e.handle(http.MethodPut, "/network/{uuid}", e.modifyNetwork)
+	e.handle(http.MethodDelete, "/network/{uuid}", e.deleteNetwork)
+	e.handle(http.MethodGet, "/server/{uuid}/networking", e.getServerNetworking)
+	e.handle(http.MethodPost, "/server/{uuid}/networking/interface", e.createInterface)
+	e.handle(http.MethodPut, "/server/{uuid}/networking/interface/{index}", e.modifyInterface)
+	e.handle(http.MethodDelete, "/server/{uuid}/networking/interface/{index}", e.deleteInterface)
+	e.handle(http.MethodGet, "/router", e.getRouters)
+	e.handle(http.MethodGet, "/router/{uuid}", e.getRouter)
+	e.handle(http.MethodPost, "/router", e.createRouter)
+	e.handle(http.MethodPatch, "/router/{uuid}", e.modifyRouter)
+	e.handle(http.MethodDelete, "/router/{uuid}", e.deleteRouter)
+}
+
+// Returns the network with the specified UUID, or nil if there's no such network
+func (e *Emulator) network(uuid string) *upcloud.Network {
+	for _, n := range e.networks {
+		if n.UUID == uuid {
+			return n
+		}
+	}
+
+	return nil
+}
+
+// Returns the network with the specified UUID. An error response is written if there's no such network.
+func (e *Emulator) lookupNetwork(w http.ResponseWriter, uuid string) (*upcloud.Network, bool) {
+	if !validUUID(uuid) {
+		writeError(w, http.StatusBadRequest, "NETWORK_INVALID", "The network UUID is invalid.")
+		return nil, false
+	}
+
+	n := e.network(uuid)
+	if n == nil {
+		writeNotFound(w, "network", uuid)
+		return nil, false
+	}
+
+	return n, true
+}
+
+// Returns the public or utility network of the zone for the specified address family
+func (e *Emulator) zoneNetwork(zone, networkType, family string) *upcloud.Network {
+	for _, n := range e.networks {
+		if n.Zone == zone && n.Type == networkType && len(n.IPNetworks) > 0 && n.IPNetworks[0].Family == family {
+			return n
+		}
+	}
+
+	return nil
+}
+
+// Returns the network the way it appears in responses
+func (e *Emulator) networkObject(n *upcloud.Network, withServers bool) object {
+	o := object{
+		"ip_networks": n.IPNetworks,
+		"name":        n.Name,
+		"type":        n.Type,
+		"uuid":        n.UUID,
+		"zone":        n.Zone,
+	}
+
+	if n.Router != "" {
+		o["router"] = n.Router
+	}
+
+	if withServers {
+		servers := []object{}
+		for _, srv := range e.servers {
+			for _, iface := range srv.Networking.Interfaces {
+				if iface.Network == n.UUID {
+					servers = append(servers, object{"uuid": srv.UUID, "title": srv.Title})
+					break
+				}
+			}
+		}
+		o["servers"] = list("server", servers)
+	}
+
+	return o
+}
+
+func (e *Emulator) getNetworks(w http.ResponseWriter, r *http.Request, p params) {
+	zone := r.URL.Query().Get("zone")
+
+	items := []object{}
+	for _, n := range e.networks {
+		if zone == "" || n.Zone == zone {
+			items = append(items, e.networkObject(n, false))
+		}
+	}
+
+	writeJSON(w, http.StatusOK, object{"networks": list("network", items)})
+}
+
+func (e *Emulator) getNetwork(w http.ResponseWriter, r *http.Request, p params) {
+	n, ok := e.lookupNetwork(w, p["uuid"])
+	if !ok {
+		return
+	}
+
+	writeJSON(w, http.StatusOK, object{"network": e.networkObject(n, true)})
+}
+
+// Validates the IP networks of a private network. An error response is written if they are invalid.
+func validateIPNetworks(w http.ResponseWriter, ipNetworks upcloud.IPNetworkSlice) bool {
+	if len(ipNetworks) == 0 {
+		writeError(w, http.StatusBadRequest, "IP_NETWORKS_MISSING", "The network has no IP networks.")
+		return false
+	}
+
+	for _, ipNetwork := range ipNetworks {
+		ip, _, err := net.ParseCIDR(ipNetwork.Address)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, "ADDRESS_INVALID", fmt.Sprintf("The address %s is invalid.", ipNetwork.Address))
+			return false
+		}
+
+		if family := addressFamily(ip); family != ipNetwork.Family {
+			writeError(w, http.StatusBadRequest, "FAMILY_INVALID",
+				fmt.Sprintf("The address %s doesn't belong to the %s family.", ipNetwork.Address, ipNetwork.Family))
+			return false
+		}
+	}
+
+	return true
+}
+
+// Returns the family of the IP address
+func addressFamily(ip net.IP) string {
+	if ip.To4() != nil {
+		return upcloud.IPAddressFamilyIPv4
+	}
+
+	return upcloud.IPAddressFamilyIPv6
+}
+
+// Sets the router of the network. An error response is written if there's no such router.
+func (e *Emulator) setNetworkRouter(w http.ResponseWriter, n *upcloud.Network, routerUUID string) bool {
+	if routerUUID == "" {
+		return true
+	}
+
+	if _, ok := e.lookupRouter(w, routerUUID); !ok {
+		return false
+	}
+
+	n.Router = routerUUID
+	return true
+}
+
+func (e *Emulator) createNetwork(w http.ResponseWriter, r *http.Request, p params) {
+	var body struct {
+		Network networkRequest `json:"network"`
+	}
+	if !decodeBody(w, r, &body) {
+		return
+	}
+
+	req := body.Network
+	if req.Name == "" {
+		writeError(w, http.StatusBadRequest, "NAME_MISSING", "The network name is missing.")
+		return
+	}
+	if _, ok := e.zone(req.Zone); !ok {
+		writeError(w, http.StatusBadRequest, "ZONE_INVALID", fmt.Sprintf("The zone %s does not exist.", req.Zone))
+		return
+	}
+	if !validateIPNetworks(w, req.IPNetworks) {
+		return
+	}
+
+	n := &upcloud.Network{
+		IPNetworks: req.IPNetworks,
+		Name:       req.Name,
+		Type:       upcloud.NetworkTypePrivate,
+		UUID:       newUUID(uuidPrefixNetwork),
+		Zone:       req.Zone,
+	}
+	if !e.setNetworkRouter(w, n, req.Router) {
+		return
+	}
+
+	e.networks = append(e.networks, n)
+
+	writeJSON(w, http.StatusCreated, object{"network": e.networkObject(n, true)})
+}
+
+func (e *Emulator) modifyNetwork(w http.ResponseWriter, r *http.Request, p params) {
+	n, ok := e.lookupNetwork(w, p["uuid"])
+	if !ok {
+		return
+	}
+
+	var body struct {
+		Network networkRequest `json:"network"`
+	}
+	if !decodeBody(w, r, &body) {
+		return
+	}
+
+	if n.Type != upcloud.NetworkTypePrivate {
+		writeError(w, http.StatusForbidden, "NETWORK_FORBIDDEN", "Only private networks can be modified.")
+		return
+	}
+
+	req := body.Network
+	if len(req.IPNetworks) > 0 {
+		if !validateIPNetworks(w, req.IPNetworks) {
+			return
+		}
+		n.IPNetworks = req.IPNetworks
+	}
+
+	if !e.setNetworkRouter(w, n, req.Router) {
+		return
+	}
+
+	n.Name = firstNonEmpty(req.Name, n.Name)
+
+	writeJSON(w, http.StatusOK, object{"network": e.networkObject(n, true)})
+}
+
+func (e *Emulator) deleteNetwork(w http.ResponseWriter, r *http.Request, p params) {
+	n, ok := e.lookupNetwork(w, p["uuid"])
+	if !ok {
+		return
+	}
+
+	if n.Type != upcloud.NetworkTypePrivate {
+		writeError(w, http.StatusForbidden, "NETWORK_FORBIDDEN", "Only private networks can be deleted.")
+		return
+	}
+
+	for _, srv := range e.servers {
+		for _, iface := range srv.Networking.Interfaces {
+			if iface.Network == n.UUID {
+				writeError(w, http.StatusConflict, "NETWORK_IN_USE",
+					fmt.Sprintf("The network %s has servers attached to it.", n.UUID))
+				return
+			}
+		}
+	}
+
+	for i, candidate := range e.networks {
+		if candidate == n {
+			e.networks = append(e.networks[:i], e.networks[i+1:]...)
+			break
+		}
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// Returns the interface the way it appears in responses
+func (e *Emulator) interfaceObject(iface *upcloud.ServerInterface) object {
+	addresses := []object{}
+	for _, ip := range e.ipAddresses {
+		if ip.MAC == iface.MAC {
+			addresses = append(addresses, object{
+				"address":  ip.Address,
+				"family":   ip.Family,
+				"floating": booleanOrFalse(ip.Floating),
+			})
+		}
+	}
+
+	return object{
+		"bootable":            iface.Bootable,
+		"index":               iface.Index,
+		"ip_addresses":        list("ip_address", addresses),
+		"mac":                 iface.MAC,
+		"network":             iface.Network,
+		"source_ip_filtering": iface.SourceIPFiltering,
+		"type":                iface.Type,
+	}
+}
+
+// Returns the networking of the server
+func (e *Emulator) networkingObject(srv *server) object {
+	interfaces := []object{}
+	for i := range srv.Networking.Interfaces {
+		interfaces = append(interfaces, e.interfaceObject(&srv.Networking.Interfaces[i]))
+	}
+
+	return object{"interfaces": list("interface", interfaces)}
+}
+
+// Returns the interface of the server with the specified index. An error response is written if there's no such
+// interface.
+func lookupInterface(w http.ResponseWriter, srv *server, index string) (*upcloud.ServerInterface, bool) {
+	i, err := strconv.Atoi(index)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "INTERFACE_INDEX_INVALID", fmt.Sprintf("The interface index %s is invalid.", index))
+		return nil, false
+	}
+
+	for j := range srv.Networking.Interfaces {
+		if srv.Networking.Interfaces[j].Index == i {
+			return &srv.Networking.Interfaces[j], true
+		}
+	}
+
+	writeNotFound(w, "interface", index)
+	return nil, false
+}
+
+// Returns the lowest interface index not used by the server
+func nextInterfaceIndex(srv *server) int {
+	for index := 1; ; index++ {
+		used := false
+		for _, iface := range srv.Networking.Interfaces {
+			used = used || iface.Index == index
+		}
+
+		if !used {
+			return index
+		}
+	}
+}
+
+// Adds the requested interfaces to the server. The IP addresses of the interfaces are returned so that they can be
+// added to the account once the whole request has been validated. An error response is written if an interface is
+// invalid.
+func (e *Emulator) createInterfaces(w http.ResponseWriter, srv *server, reqs []interfaceRequest) ([]*upcloud.IPAddress, bool) {
+	var addresses []*upcloud.IPAddress
+
+	for _, req := range reqs {
+		var n *upcloud.Network
+		switch req.Type {
+		case upcloud.NetworkTypePrivate:
+			var ok bool
+			if n, ok = e.lookupNetwork(w, req.Network); !ok {
+				return nil, false
+			}
+			if n.Type != upcloud.NetworkTypePrivate || n.Zone != srv.Zone {
+				writeError(w, http.StatusBadRequest, "NETWORK_INVALID",
+					fmt.Sprintf("The network %s is not a private network in zone %s.", n.UUID, srv.Zone))
+				return nil, false
+			}
+		case upcloud.NetworkTypePublic, upcloud.NetworkTypeUtility:
+		default:
+			writeError(w, http.StatusBadRequest, "TYPE_INVALID", fmt.Sprintf("The interface type %s is invalid.", req.Type))
+			return nil, false
+		}
+
+		if len(req.IPAddresses.IPAddress) == 0 {
+			writeError(w, http.StatusBadRequest, "IP_ADDRESSES_MISSING", "The interface has no IP addresses.")
+			return nil, false
+		}
+
+		index := int(req.Index)
+		if index == 0 {
+			index = nextInterfaceIndex(srv)
+		}
+		for _, existing := range srv.Networking.Interfaces {
+			if existing.Index == index {
+				writeError(w, http.StatusConflict, "INTERFACE_EXISTS", fmt.Sprintf("The interface %d already exists.", index))
+				return nil, false
+			}
+		}
+
+		iface := upcloud.ServerInterface{
+			Index:             index,
+			MAC:               e.newMAC(),
+			Type:              req.Type,
+			Bootable:          booleanOrFalse(req.Bootable),
+			SourceIPFiltering: req.SourceIPFiltering,
+		}
+		if iface.SourceIPFiltering.Empty() {
+			iface.SourceIPFiltering = upcloud.True
+		}
+
+		for _, ipReq := range req.IPAddresses.IPAddress {
+			family := firstNonEmpty(ipReq.Family, upcloud.IPAddressFamilyIPv4)
+			network := n
+			if network == nil {
+				network = e.zoneNetwork(srv.Zone, req.Type, family)
+			}
+
+			ip, ok := e.newInterfaceAddress(w, network, req.Type, family, ipReq.Address, addresses)
+			if !ok {
+				return nil, false
+			}
+
+			ip.MAC = iface.MAC
+			ip.Zone = srv.Zone
+			if ip.Access != upcloud.IPAddressAccessPrivate {
+				ip.PTRRecord = defaultPTRRecord(ip.Address, srv.Zone)
+			}
+			addresses = append(addresses, ip)
+
+			if iface.Network == "" {
+				iface.Network = network.UUID
+			}
+		}
+
+		srv.Networking.Interfaces = append(srv.Networking.Interfaces, iface)
+	}
+
+	return addresses, true
+}
+
+// Returns a new address on the network for an interface of the specified type. An error response is written if
+// the address can't be allocated.
+func (e *Emulator) newInterfaceAddress(w http.ResponseWriter, n *upcloud.Network, interfaceType, family, address string, pending []*upcloud.IPAddress) (*upcloud.IPAddress, bool) {
+	if n == nil {
+		writeError(w, http.StatusBadRequest, "FAMILY_INVALID",
+			fmt.Sprintf("The %s interface can't have %s addresses.", interfaceType, family))
+		return nil, false
+	}
+
+	access := interfaceType
+	if interfaceType == upcloud.NetworkTypePrivate {
+		access = upcloud.IPAddressAccessPrivate
+	}
+
+	if address != "" {
+		if interfaceType != upcloud.NetworkTypePrivate {
+			writeError(w, http.StatusBadRequest, "ADDRESS_INVALID", "Only private addresses can be chosen.")
+			return nil, false
+		}
+
+		ip := net.ParseIP(address)
+		if ip == nil || !networkContains(n, ip) {
+			writeError(w, http.StatusBadRequest, "ADDRESS_INVALID",
+				fmt.Sprintf("The address %s is not in network %s.", address, n.UUID))
+			return nil, false
+		}
+		if e.addressInUse(ip.String(), pending) {
+			writeError(w, http.StatusConflict, "ADDRESS_IN_USE", fmt.Sprintf("The address %s is already in use.", address))
+			return nil, false
+		}
+
+		return newIPAddress(access, ip.String(), family), true
+	}
+
+	allocated, ok := e.allocateAddress(n, family, pending)
+	if !ok {
+		writeError(w, http.StatusConflict, "ADDRESS_POOL_EXHAUSTED",
+			fmt.Sprintf("The network %s has no free %s addresses.", n.UUID, family))
+		return nil, false
+	}
+
+	return newIPAddress(access, allocated, family), true
+}
+
+// Returns a new IP address that isn't assigned to any interface
+func newIPAddress(access, address, family string) *upcloud.IPAddress {
+	return &upcloud.IPAddress{
+		Access:     access,
+		Address:    address,
+		Family:     family,
+		PartOfPlan: upcloud.False,
+		Floating:   upcloud.False,
+	}
+}
+
+// Returns whether the IP address belongs to one of the IP networks of the network
+func networkContains(n *upcloud.Network, ip net.IP) bool {
+	for _, ipNetwork := range n.IPNetworks {
+		if _, subnet, err := net.ParseCIDR(ipNetwork.Address); err == nil && subnet.Contains(ip) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Returns whether the address is in use, either in the account or among the pending addresses
+func (e *Emulator) addressInUse(address string, pending []*upcloud.IPAddress) bool {
+	for _, ip := range append(append([]*upcloud.IPAddress{}, e.ipAddresses...), pending...) {
+		if ip.Address == address {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Returns the first free address of the specified family on the network. The first ten addresses of every IP network
+// are reserved for gateways and DHCP servers.
+func (e *Emulator) allocateAddress(n *upcloud.Network, family string, pending []*upcloud.IPAddress) (string, bool) {
+	for _, ipNetwork := range n.IPNetworks {
+		if ipNetwork.Family != family {
+			continue
+		}
+
+		base, subnet, err := net.ParseCIDR(ipNetwork.Address)
+		if err != nil {
+			continue
+		}
+
+		base = base.Mask(subnet.Mask)
+		for offset := 10; offset < 65536; offset++ {
+			ip := addToIP(base, offset)
+			if !subnet.Contains(ip) {
+				break
+			}
+
+			if !e.addressInUse(ip.String(), pending) {
+				return ip.String(), true
+			}
+		}
+	}
+
+	return "", false
+}
+
+// Returns the IP address that is the specified number of addresses after the base address
+func addToIP(base net.IP, offset int) net.IP {
+	ip := make(net.IP, len(base))
+	copy(ip, base)
+
+	if v4 := ip.To4(); v4 != nil {
+		ip = v4
+	}
+
+	carry := offset
+	for i := len(ip) - 1; i >= 0 && carry > 0; i-- {
+		sum := int(ip[i]) + carry
+		ip[i] = byte(sum & 0xff)
+		carry = sum >> 8
+	}
+
+	return ip
+}
+
+func (e *Emulator) getServerNetworking(w http.ResponseWriter, r *http.Request, p params) {
+	srv, ok := e.lookupServer(w, p["uuid"])
+	if !ok {
+		return
+	}
+
+	writeJSON(w, http.StatusOK, object{"networking": e.networkingObject(srv)})
+}
+
+func (e *Emulator) createInterface(w http.ResponseWriter, r *http.Request, p params) {
+	srv, ok := e.lookupServer(w, p["uuid"])
+	if !ok {
+		return
+	}
+
+	var body struct {
+		Interface interfaceRequest `json:"interface"`
+	}
+	if !decodeBody(w, r, &body) {
+		return
+	}
+
+	if srv.State != upcloud.ServerStateStopped {
+		writeServerStateIllegal(w, srv)
+		return
+	}
+
+	addresses, ok := e.createInterfaces(w, srv, []interfaceRequest{body.Interface})
+	if !ok {
+		return
+	}
+	e.ipAddresses = append(e.ipAddresses, addresses...)
+
+	iface := &srv.Networking.Interfaces[len(srv.Networking.Interfaces)-1]
+	writeJSON(w, http.StatusCreated, object{"interface": e.interfaceObject(iface)})
+}
+
+func (e *Emulator) modifyInterface(w http.ResponseWriter, r *http.Request, p params) {
+	srv, ok := e.lookupServer(w, p["uuid"])
+	if !ok {
+		return
+	}
+
+	iface, ok := lookupInterface(w, srv, p["index"])
+	if !ok {
+		return
+	}
+
+	var body struct {
+		Interface interfaceRequest `json:"interface"`
+	}
+	if !decodeBody(w, r, &body) {
+		return
+	}
+
+	if srv.State != upcloud.ServerStateStopped {
+		writeServerStateIllegal(w, srv)
+		return
+	}
+
+	req := body.Interface
+	if req.Type != "" && req.Type != iface.Type {
+		writeError(w, http.StatusBadRequest, "TYPE_INVALID", "The type of an interface can't be changed.")
+		return
+	}
+
+	if index := int(req.Index); index != 0 && index != iface.Index {
+		for _, existing := range srv.Networking.Interfaces {
+			if existing.Index == index {
+				writeError(w, http.StatusConflict, "INTERFACE_EXISTS", fmt.Sprintf("The interface %d already exists.", index))
+				return
+			}
+		}
+		iface.Index = index
+	}
+
+	if !req.Bootable.Empty() {
+		iface.Bootable = req.Bootable
+	}
+	if !req.SourceIPFiltering.Empty() {
+		iface.SourceIPFiltering = req.SourceIPFiltering
+	}
+
+	writeJSON(w, http.StatusOK, object{"interface": e.interfaceObject(iface)})
+}
+
+func (e *Emulator) deleteInterface(w http.ResponseWriter, r *http.Request, p params) {
+	srv, ok := e.lookupServer(w, p["uuid"])
+	if !ok {
+		return
+	}
+
+	iface, ok := lookupInterface(w, srv, p["index"])
+	if !ok {
+		return
+	}
+
+	if srv.State != upcloud.ServerStateStopped {
+		writeServerStateIllegal(w, srv)
+		return
+	}
+
+	e.releaseInterfaceAddresses(iface.MAC)
+
+	for i := range srv.Networking.Interfaces {
+		if &srv.Networking.Interfaces[i] == iface {
+			srv.Networking.Interfaces = append(srv.Networking.Interfaces[:i], srv.Networking.Interfaces[i+1:]...)
+			break
+		}
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// Returns the router with the specified UUID. An error response is written if there's no such router.
+func (e *Emulator) lookupRouter(w http.ResponseWriter, uuid string) (*upcloud.Router, bool) {
+	if !validUUID(uuid) {
+		writeError(w, http.StatusBadRequest, "ROUTER_INVALID", "The router UUID is invalid.")
+		return nil, false
+	}
+
+	for _, router := range e.routers {
+		if router.UUID == uuid {
+			return router, true
+		}
+	}
+
+	writeNotFound(w, "router", uuid)
+	return nil, false
+}
+
+// Returns the router the way it appears in responses
+func (e *Emulator) routerObject(router *upcloud.Router) object {
+	attached := []object{}
+	for _, n := range e.networks {
+		if n.Router == router.UUID {
+			attached = append(attached, object{"uuid": n.UUID})
+		}
+	}
+
+	return object{
+		"attached_networks": list("network", attached),
+		"name":              router.Name,
+		"type":              router.Type,
+		"uuid":              router.UUID,
+	}
+}
+
+func (e *Emulator) getRouters(w http.ResponseWriter, r *http.Request, p params) {
+	items := []object{}
+	for _, router := range e.routers {
+		items = append(items, e.routerObject(router))
+	}
+
+	writeJSON(w, http.StatusOK, object{"routers": list("router", items)})
+}
+
+func (e *Emulator) getRouter(w http.ResponseWriter, r *http.Request, p params) {
+	router, ok := e.lookupRouter(w, p["uuid"])
+	if !ok {
+		return
+	}
+
+	writeJSON(w, http.StatusOK, object{"router": e.routerObject(router)})
+}
+
+// Decodes the body of a create or modify router request. An error response is written if it's invalid.
+func decodeRouterRequest(w http.ResponseWriter, r *http.Request) (string, bool) {
+	var body struct {
+		Router struct {
+			Name string `json:"name"`
+		} `json:"router"`
+	}
+	if !decodeBody(w, r, &body) {
+		return "", false
+	}
+
+	if body.Router.Name == "" {
+		writeError(w, http.StatusBadRequest, "NAME_MISSING", "The router name is missing.")
+		return "", false
+	}
+
+	return body.Router.Name, true
+}
+
+func (e *Emulator) createRouter(w http.ResponseWriter, r *http.Request, p params) {
+	name, ok := decodeRouterRequest(w, r)
+	if !ok {
+		return
+	}
+
+	router := &upcloud.Router{
+		Name: name,
+		Type: "normal",
+		UUID: newUUID(uuidPrefixRouter),
+	}
+	e.routers = append(e.routers, router)
+
+	writeJSON(w, http.StatusCreated, object{"router": e.routerObject(router)})
+}
+
+func (e *Emulator) modifyRouter(w http.ResponseWriter, r *http.Request, p params) {
+	router, ok := e.lookupRouter(w, p["uuid"])
+	if !ok {
+		return
+	}
+
+	name, ok := decodeRouterRequest(w, r)
+	if !ok {
+		return
+	}
+
+	router.Name = name
+
+	writeJSON(w, http.StatusOK, object{"router": e.routerObject(router)})
+}
+
+func (e *Emulator) deleteRouter(w http.ResponseWriter, r *http.Request, p params) {
+	router, ok := e.lookupRouter(w, p["uuid"])
+	if !ok {
+		return
+	}
+
+	for _, n := range e.networks {
+		if n.Router == router.UUID {
+			writeError(w, http.StatusConflict, "ROUTER_IN_USE",
+				fmt.Sprintf("The router %s has networks attached to it.", router.UUID))
+			return
+		}
+	}
+
+	for i, candidate := range e.routers {
+		if candidate == router {
+			e.routers = append(e.routers[:i], e.routers[i+1:]...)
+			break
+		}
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,165 @@
+package emulator
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/UpCloudLtd/upcloud-go-api/upcloud"
+	"github.com/UpCloudLtd/upcloud-go-api/upcloud/request"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestCreateModifyDeleteNetwork tests the lifecycle of a private network
+func TestCreateModifyDeleteNetwork(t *testing.T) {
+	svc, _ := newTestService(t)
+
+	network, err := svc.CreateNetwork(&request.CreateNetworkRequest{
+		Name: "emulator-test",
+		Zone: "fi-hel1",
+		IPNetworks: []upcloud.IPNetwork{
+			{
+				Address: "172.16.0.0/22",
+				DHCP:    upcloud.True,
+				Family:  upcloud.IPAddressFamilyIPv4,
+				Gateway: "172.16.0.1",
+			},
+		},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, upcloud.NetworkTypePrivate, network.Type)
+	assert.Equal(t, "fi-hel1", network.Zone)
+
+	networks, err := svc.GetNetworksInZone(&request.GetNetworksInZoneRequest{Zone: "fi-hel1"})
+	require.NoError(t, err)
+	assert.Len(t, networks.Networks, 4, "the public, utility and private networks are listed")
+
+	network, err = svc.ModifyNetwork(&request.ModifyNetworkRequest{UUID: network.UUID, Name: "renamed"})
+	require.NoError(t, err)
+	assert.Equal(t, "renamed", network.Name)
+
+	err = svc.DeleteNetwork(&request.DeleteNetworkRequest{UUID: network.UUID})
+	require.NoError(t, err)
+
+	_, err = svc.GetNetworkDetails(&request.GetNetworkDetailsRequest{UUID: network.UUID})
+	assert.True(t, errors.Is(err, upcloud.ErrNotFound))
+}
+
+// TestModifyPublicNetwork tests that only private networks can be modified
+func TestModifyPublicNetwork(t *testing.T) {
+	svc, _ := newTestService(t)
+
+	networks, err := svc.GetNetworksInZone(&request.GetNetworksInZoneRequest{Zone: "fi-hel1"})
+	require.NoError(t, err)
+	require.NotEmpty(t, networks.Networks)
+
+	_, err = svc.ModifyNetwork(&request.ModifyNetworkRequest{UUID: networks.Networks[0].UUID, Name: "renamed"})
+	assert.True(t, errors.Is(err, upcloud.ErrForbidden))
+}
+
+// TestNetworkInterfaces tests that interfaces can be added to and removed from a stopped server
+func TestNetworkInterfaces(t *testing.T) {
+	svc, _ := newTestService(t)
+	serverDetails := createTestServer(t, svc, "fi-hel1")
+
+	network, err := svc.CreateNetwork(&request.CreateNetworkRequest{
+		Name: "emulator-test",
+		Zone: "fi-hel1",
+		IPNetworks: []upcloud.IPNetwork{
+			{Address: "172.16.0.0/22", Family: upcloud.IPAddressFamilyIPv4},
+		},
+	})
+	require.NoError(t, err)
+
+	createInterface := &request.CreateNetworkInterfaceRequest{
+		ServerUUID:  serverDetails.UUID,
+		Type:        upcloud.NetworkTypePrivate,
+		NetworkUUID: network.UUID,
+		IPAddresses: []request.CreateNetworkInterfaceIPAddress{
+			{Family: upcloud.IPAddressFamilyIPv4, Address: "172.16.0.20"},
+		},
+	}
+
+	_, err = svc.CreateNetworkInterface(createInterface)
+	assert.True(t, errors.Is(err, upcloud.ErrConflict), "interfaces can only be added to stopped servers")
+
+	stopTestServer(t, svc, serverDetails.UUID)
+
+	iface, err := svc.CreateNetworkInterface(createInterface)
+	require.NoError(t, err)
+	assert.Equal(t, 2, iface.Index)
+	require.Len(t, iface.IPAddresses, 1)
+	assert.Equal(t, "172.16.0.20", iface.IPAddresses[0].Address)
+
+	// The address can't be used twice in the same network
+	_, err = svc.CreateNetworkInterface(createInterface)
+	assert.True(t, errors.Is(err, upcloud.ErrConflict))
+
+	iface, err = svc.ModifyNetworkInterface(&request.ModifyNetworkInterfaceRequest{
+		ServerUUID:   serverDetails.UUID,
+		CurrentIndex: iface.Index,
+		NewIndex:     5,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 5, iface.Index)
+
+	network, err = svc.GetNetworkDetails(&request.GetNetworkDetailsRequest{UUID: network.UUID})
+	require.NoError(t, err)
+	require.Len(t, network.Servers, 1)
+	assert.Equal(t, serverDetails.UUID, network.Servers[0].ServerUUID)
+
+	// A network with servers attached can't be deleted
+	err = svc.DeleteNetwork(&request.DeleteNetworkRequest{UUID: network.UUID})
+	assert.True(t, errors.Is(err, upcloud.ErrConflict))
+
+	err = svc.DeleteNetworkInterface(&request.DeleteNetworkInterfaceRequest{ServerUUID: serverDetails.UUID, Index: 5})
+	require.NoError(t, err)
+
+	networking, err := svc.GetServerNetworks(&request.GetServerNetworksRequest{ServerUUID: serverDetails.UUID})
+	require.NoError(t, err)
+	assert.Len(t, networking.Interfaces, 1)
+
+	err = svc.DeleteNetwork(&request.DeleteNetworkRequest{UUID: network.UUID})
+	require.NoError(t, err)
+}
+
+// TestRouters tests that routers can be attached to networks and deleted once detached
+func TestRouters(t *testing.T) {
+	svc, _ := newTestService(t)
+
+	router, err := svc.CreateRouter(&request.CreateRouterRequest{Name: "emulator-test"})
+	require.NoError(t, err)
+	assert.Empty(t, router.AttachedNetworks)
+
+	network, err := svc.CreateNetwork(&request.CreateNetworkRequest{
+		Name:   "emulator-test",
+		Zone:   "fi-hel1",
+		Router: router.UUID,
+		IPNetworks: []upcloud.IPNetwork{
+			{Address: "172.16.0.0/22", Family: upcloud.IPAddressFamilyIPv4},
+		},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, router.UUID, network.Router)
+
+	router, err = svc.GetRouterDetails(&request.GetRouterDetailsRequest{UUID: router.UUID})
+	require.NoError(t, err)
+	assert.Equal(t, upcloud.RouterNetworkSlice{{NetworkUUID: network.UUID}}, router.AttachedNetworks)
+
+	err = svc.DeleteRouter(&request.DeleteRouterRequest{UUID: router.UUID})
+	assert.True(t, errors.Is(err, upcloud.ErrConflict))
+
+	router, err = svc.ModifyRouter(&request.ModifyRouterRequest{UUID: router.UUID, Name: "renamed"})
+	require.NoError(t, err)
+	assert.Equal(t, "renamed", router.Name)
+
+	err = svc.DeleteNetwork(&request.DeleteNetworkRequest{UUID: network.UUID})
+	require.NoError(t, err)
+
+	err = svc.DeleteRouter(&request.DeleteRouterRequest{UUID: router.UUID})
+	require.NoError(t, err)
+
+	routers, err := svc.GetRouters()
+	require.NoError(t, err)
+	assert.Empty(t, routers.Routers)
+}
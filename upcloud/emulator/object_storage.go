@@ -0,0 +1,194 @@
+package emulator
+
+import (
+	"fmt"
+	"net/http"
+	"regexp"
+	"time"
+
+	"github.com/UpCloudLtd/upcloud-go-api/upcloud"
+)
+
+// The sizes of Object Storage instances, in GiB
+var objectStorageSizes = []int{250, 500, 1000}
+
+var objectStorageNamePattern = regexp.MustCompile(`^[a-z0-9][a-z0-9-]{2,63}$`)
+
+// Registers the handlers of the Object Storage endpoints
+func (e *Emulator) addObjectStorageRoutes() {
+	e.handle(http.MethodGet, "/object-storage", e.getObjectStorages)
+	e.handle(http.MethodGet, "/object-storage/{uuid}", e.getObjectStorage)
+	e.handle(http.MethodPost, "/object-storage", e.createObjectStorage)
+	e.handle(http.MethodPatch, "/object-storage/{uuid}", e.modifyObjectStorage)
+	e.handle(http.MethodDelete, "/object-storage/{uuid}", e.deleteObjectStorage)
+}
+
+// Returns the Object Storage with the specified UUID. An error response is written if there's no such Object
+// Storage.
+func (e *Emulator) lookupObjectStorage(w http.ResponseWriter, uuid string) (*upcloud.ObjectStorageDetails, bool) {
+	if !validUUID(uuid) {
+		writeError(w, http.StatusBadRequest, "OBJECT_STORAGE_INVALID", "The Object Storage UUID is invalid.")
+		return nil, false
+	}
+
+	for _, o := range e.objectStorages {
+		if o.UUID == uuid {
+			return o, true
+		}
+	}
+
+	writeNotFound(w, "object storage", uuid)
+	return nil, false
+}
+
+// Returns the Object Storage the way it appears in responses
+func objectStorageObject(o *upcloud.ObjectStorageDetails, withUsedSpace bool) object {
+	obj := object{
+		"created":     o.Created,
+		"description": o.Description,
+		"name":        o.Name,
+		"size":        o.Size,
+		"state":       o.State,
+		"url":         o.URL,
+		"uuid":        o.UUID,
+		"zone":        o.Zone,
+	}
+
+	if withUsedSpace {
+		obj["used_space"] = o.UsedSpace
+	}
+
+	return obj
+}
+
+// Validates the size of an Object Storage. An error response is written if it's invalid.
+func validateObjectStorageSize(w http.ResponseWriter, size int) bool {
+	for _, valid := range objectStorageSizes {
+		if size == valid {
+			return true
+		}
+	}
+
+	writeError(w, http.StatusBadRequest, "SIZE_INVALID", fmt.Sprintf("The size must be one of %v GiB.", objectStorageSizes))
+	return false
+}
+
+func (e *Emulator) getObjectStorages(w http.ResponseWriter, r *http.Request, p params) {
+	items := []object{}
+	for _, o := range e.objectStorages {
+		items = append(items, objectStorageObject(o, false))
+	}
+
+	writeJSON(w, http.StatusOK, object{"object_storages": list("object_storage", items)})
+}
+
+func (e *Emulator) getObjectStorage(w http.ResponseWriter, r *http.Request, p params) {
+	o, ok := e.lookupObjectStorage(w, p["uuid"])
+	if !ok {
+		return
+	}
+
+	writeJSON(w, http.StatusOK, object{"object_storage": objectStorageObject(o, true)})
+}
+
+func (e *Emulator) createObjectStorage(w http.ResponseWriter, r *http.Request, p params) {
+	var body struct {
+		ObjectStorage struct {
+			Name        string `json:"name"`
+			Description string `json:"description"`
+			Zone        string `json:"zone"`
+			AccessKey   string `json:"access_key"`
+			SecretKey   string `json:"secret_key"`
+			Size        int    `json:"size"`
+		} `json:"object_storage"`
+	}
+	if !decodeBody(w, r, &body) {
+		return
+	}
+
+	req := body.ObjectStorage
+	switch {
+	case !objectStorageNamePattern.MatchString(req.Name):
+		writeError(w, http.StatusBadRequest, "NAME_INVALID", fmt.Sprintf("The name %q is invalid.", req.Name))
+		return
+	case req.AccessKey == "":
+		writeError(w, http.StatusBadRequest, "ACCESS_KEY_MISSING", "The access key is missing.")
+		return
+	case req.SecretKey == "":
+		writeError(w, http.StatusBadRequest, "SECRET_KEY_MISSING", "The secret key is missing.")
+		return
+	}
+
+	if _, ok := e.zone(req.Zone); !ok {
+		writeError(w, http.StatusBadRequest, "ZONE_INVALID", fmt.Sprintf("The zone %s does not exist.", req.Zone))
+		return
+	}
+	if !validateObjectStorageSize(w, req.Size) {
+		return
+	}
+
+	for _, existing := range e.objectStorages {
+		if existing.Name == req.Name {
+			writeError(w, http.StatusConflict, "NAME_ALREADY_IN_USE", fmt.Sprintf("The name %s is already in use.", req.Name))
+			return
+		}
+	}
+
+	o := &upcloud.ObjectStorageDetails{}
+	o.Created = e.now().UTC().Format(time.RFC3339)
+	o.Description = req.Description
+	o.Name = req.Name
+	o.Size = req.Size
+	o.State = upcloud.ObjectStorageStateStarted
+	o.URL = fmt.Sprintf("https://%s.%s.upcloudobjects.com/", req.Name, req.Zone)
+	o.UUID = newUUID(uuidPrefixObjectStorage)
+	o.Zone = req.Zone
+	e.objectStorages = append(e.objectStorages, o)
+
+	writeJSON(w, http.StatusCreated, object{"object_storage": objectStorageObject(o, true)})
+}
+
+func (e *Emulator) modifyObjectStorage(w http.ResponseWriter, r *http.Request, p params) {
+	o, ok := e.lookupObjectStorage(w, p["uuid"])
+	if !ok {
+		return
+	}
+
+	var body struct {
+		ObjectStorage struct {
+			Description string `json:"description"`
+			Size        int    `json:"size"`
+		} `json:"object_storage"`
+	}
+	if !decodeBody(w, r, &body) {
+		return
+	}
+
+	req := body.ObjectStorage
+	if req.Size != 0 {
+		if !validateObjectStorageSize(w, req.Size) {
+			return
+		}
+		o.Size = req.Size
+	}
+
+	o.Description = firstNonEmpty(req.Description, o.Description)
+
+	writeJSON(w, http.StatusOK, object{"object_storage": objectStorageObject(o, true)})
+}
+
+func (e *Emulator) deleteObjectStorage(w http.ResponseWriter, r *http.Request, p params) {
+	o, ok := e.lookupObjectStorage(w, p["uuid"])
+	if !ok {
+		return
+	}
+
+	for i, candidate := range e.objectStorages {
+		if candidate == o {
+			e.objectStorages = append(e.objectStorages[:i], e.objectStorages[i+1:]...)
+			break
+		}
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,53 @@
+package emulator
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/UpCloudLtd/upcloud-go-api/upcloud"
+	"github.com/UpCloudLtd/upcloud-go-api/upcloud/request"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestCreateModifyDeleteObjectStorage tests the lifecycle of an Object Storage
+func TestCreateModifyDeleteObjectStorage(t *testing.T) {
+	svc, _ := newTestService(t)
+
+	create := &request.CreateObjectStorageRequest{
+		Name:      "emulator-test",
+		Zone:      "fi-hel2",
+		AccessKey: "access",
+		SecretKey: "secretsecret",
+		Size:      250,
+	}
+	objectStorage, err := svc.CreateObjectStorage(create)
+	require.NoError(t, err)
+	assert.Equal(t, upcloud.ObjectStorageStateStarted, objectStorage.State)
+	assert.Equal(t, "https://emulator-test.fi-hel2.upcloudobjects.com/", objectStorage.URL)
+
+	_, err = svc.CreateObjectStorage(create)
+	assert.True(t, errors.Is(err, upcloud.ErrConflict))
+
+	objectStorage, err = svc.ModifyObjectStorage(&request.ModifyObjectStorageRequest{
+		UUID:        objectStorage.UUID,
+		Description: "renamed",
+		Size:        500,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "renamed", objectStorage.Description)
+	assert.Equal(t, 500, objectStorage.Size)
+
+	_, err = svc.ModifyObjectStorage(&request.ModifyObjectStorageRequest{UUID: objectStorage.UUID, Size: 300})
+	assert.True(t, errors.Is(err, upcloud.ErrBadRequest))
+
+	objectStorages, err := svc.GetObjectStorages()
+	require.NoError(t, err)
+	assert.Len(t, objectStorages.ObjectStorages, 1)
+
+	err = svc.DeleteObjectStorage(&request.DeleteObjectStorageRequest{UUID: objectStorage.UUID})
+	require.NoError(t, err)
+
+	_, err = svc.GetObjectStorageDetails(&request.GetObjectStorageDetailsRequest{UUID: objectStorage.UUID})
+	assert.True(t, errors.Is(err, upcloud.ErrNotFound))
+}
@@ -0,0 +1,721 @@
+package emulator
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/UpCloudLtd/upcloud-go-api/upcloud"
+)
+
+// IDs of the hosts servers run on in zones without private cloud hosts
+var publicHostIDs = []int{5201216131, 5873645932, 6707495555}
+
+// server is a server along with its firewall rules. The IP addresses and tags of the server are kept elsewhere.
+type server struct {
+	upcloud.ServerDetails
+
+	firewallRules []upcloud.FirewallRule
+}
+
+// Registers the handlers of the server endpoints
+func (e *Emulator) addServerRoutes() {
+	e.handle(http.MethodGet, "/server", e.getServers)
+	e.handle(http.MethodGet, "/server/{uuid}", e.getServer)
+	e.handle(http.MethodPost, "/server", e.createServer)
+	e.handle(http.MethodPut, "/server/{uuid}", e.modifyServer)
+	e.handle(http.MethodDelete, "/server/{uuid}", e.deleteServer)
+	e.handle(http.MethodPost, "/server/{uuid}/start", e.startServer)
+	e.handle(http.MethodPost, "/server/{uuid}/stop", e.stopServer)
+	e.handle(http.MethodPost, "/server/{uuid}/restart", e.restartServer)
+	e.handle(http.MethodPost, "/server/{uuid}/tag/{tags}", e.tagServer)
+	e.handle(http.MethodPost, "/server/{uuid}/untag/{tags}", e.untagServer)
+}
+
+// Returns the server with the specified UUID, or nil if there's no such server
+func (e *Emulator) server(uuid string) *server {
+	for _, srv := range e.servers {
+		if srv.UUID == uuid {
+			return srv
+		}
+	}
+
+	return nil
+}
+
+// Returns the server with the specified UUID. An error response is written if there's no such server.
+func (e *Emulator) lookupServer(w http.ResponseWriter, uuid string) (*server, bool) {
+	if !validUUID(uuid) {
+		writeError(w, http.StatusBadRequest, "SERVER_INVALID", "The server UUID is invalid.")
+		return nil, false
+	}
+
+	srv := e.server(uuid)
+	if srv == nil {
+		writeNotFound(w, "server", uuid)
+		return nil, false
+	}
+
+	return srv, true
+}
+
+// Writes the error of an operation that isn't allowed in the current state of the server
+func writeServerStateIllegal(w http.ResponseWriter, srv *server) {
+	writeError(w, http.StatusConflict, "SERVER_STATE_ILLEGAL",
+		fmt.Sprintf("The server %s is in state %s.", srv.UUID, srv.State))
+}
+
+// Returns the names of the tags of the server
+func (e *Emulator) serverTags(uuid string) []string {
+	names := []string{}
+	for _, tag := range e.tags {
+		if contains(tag.Servers, uuid) {
+			names = append(names, tag.Name)
+		}
+	}
+
+	return names
+}
+
+// Returns the server the way it appears in lists
+func (e *Emulator) serverObject(srv *server) object {
+	return object{
+		"core_number":   fmt.Sprintf("%d", srv.CoreNumber),
+		"hostname":      srv.Hostname,
+		"license":       srv.License,
+		"memory_amount": fmt.Sprintf("%d", srv.MemoryAmount),
+		"plan":          srv.Plan,
+		"progress":      "0",
+		"state":         srv.State,
+		"tags":          object{"tag": e.serverTags(srv.UUID)},
+		"title":         srv.Title,
+		"uuid":          srv.UUID,
+		"zone":          srv.Zone,
+	}
+}
+
+// Returns the details of the server
+func (e *Emulator) serverDetails(srv *server) object {
+	o := e.serverObject(srv)
+	o["boot_order"] = srv.BootOrder
+	o["firewall"] = srv.Firewall
+	o["metadata"] = srv.Metadata
+	o["nic_model"] = srv.NICModel
+	o["simple_backup"] = srv.SimpleBackup
+	o["timezone"] = srv.Timezone
+	o["video_model"] = srv.VideoModel
+	o["remote_access_enabled"] = srv.RemoteAccessEnabled
+	o["remote_access_type"] = srv.RemoteAccessType
+	o["remote_access_password"] = srv.RemoteAccessPassword
+
+	if srv.RemoteAccessEnabled.Bool() {
+		o["remote_access_host"] = srv.RemoteAccessHost
+		o["remote_access_port"] = fmt.Sprintf("%d", srv.RemoteAccessPort)
+	}
+
+	if srv.Host != 0 {
+		o["host"] = srv.Host
+	}
+
+	addresses := []object{}
+	for _, ip := range e.serverIPAddresses(srv) {
+		addresses = append(addresses, object{
+			"access":  ip.Access,
+			"address": ip.Address,
+			"family":  ip.Family,
+		})
+	}
+	o["ip_addresses"] = list("ip_address", addresses)
+	o["networking"] = e.networkingObject(srv)
+
+	devices := []object{}
+	for _, device := range srv.StorageDevices {
+		d := object{
+			"address":       device.Address,
+			"boot_disk":     fmt.Sprintf("%d", device.BootDisk),
+			"storage":       device.UUID,
+			"storage_size":  0,
+			"storage_title": "",
+			"type":          device.Type,
+		}
+
+		if s := e.storage(device.UUID); s != nil {
+			d["storage_size"] = s.Size
+			d["storage_title"] = s.Title
+		}
+
+		devices = append(devices, d)
+	}
+	o["storage_devices"] = list("storage_device", devices)
+
+	return object{"server": o}
+}
+
+func (e *Emulator) getServers(w http.ResponseWriter, r *http.Request, p params) {
+	items := []object{}
+	for _, srv := range e.servers {
+		items = append(items, e.serverObject(srv))
+	}
+
+	writeJSON(w, http.StatusOK, object{"servers": list("server", items)})
+}
+
+func (e *Emulator) getServer(w http.ResponseWriter, r *http.Request, p params) {
+	srv, ok := e.lookupServer(w, p["uuid"])
+	if !ok {
+		return
+	}
+
+	writeJSON(w, http.StatusOK, e.serverDetails(srv))
+}
+
+// createServerStorageDevice is a storage device in a create server request
+type createServerStorageDevice struct {
+	Action  string  `json:"action"`
+	Address string  `json:"address"`
+	Storage string  `json:"storage"`
+	Title   string  `json:"title"`
+	Size    flexInt `json:"size"`
+	Tier    string  `json:"tier"`
+	Type    string  `json:"type"`
+}
+
+// createServerRequest is the body of a create server request
+type createServerRequest struct {
+	AvoidHost            flexInt         `json:"avoid_host"`
+	Host                 flexInt         `json:"host"`
+	BootOrder            string          `json:"boot_order"`
+	CoreNumber           flexInt         `json:"core_number"`
+	Firewall             string          `json:"firewall"`
+	Hostname             string          `json:"hostname"`
+	MemoryAmount         flexInt         `json:"memory_amount"`
+	Metadata             upcloud.Boolean `json:"metadata"`
+	Plan                 string          `json:"plan"`
+	SimpleBackup         string          `json:"simple_backup"`
+	TimeZone             string          `json:"timezone"`
+	Title                string          `json:"title"`
+	VideoModel           string          `json:"video_model"`
+	RemoteAccessEnabled  upcloud.Boolean `json:"remote_access_enabled"`
+	RemoteAccessType     string          `json:"remote_access_type"`
+	RemoteAccessPassword string          `json:"remote_access_password"`
+	Zone                 string          `json:"zone"`
+	Networking           *struct {
+		Interfaces struct {
+			Interface []interfaceRequest `json:"interface"`
+		} `json:"interfaces"`
+	} `json:"networking"`
+	StorageDevices struct {
+		StorageDevice []createServerStorageDevice `json:"storage_device"`
+	} `json:"storage_devices"`
+}
+
+// Returns the plan with the specified name
+func findPlan(name string) (upcloud.Plan, bool) {
+	for _, plan := range plans {
+		if plan.Name == name {
+			return plan, true
+		}
+	}
+
+	return upcloud.Plan{}, false
+}
+
+func (e *Emulator) createServer(w http.ResponseWriter, r *http.Request, p params) {
+	var body struct {
+		Server createServerRequest `json:"server"`
+	}
+	if !decodeBody(w, r, &body) {
+		return
+	}
+
+	req := body.Server
+	switch {
+	case req.Title == "":
+		writeError(w, http.StatusBadRequest, "TITLE_MISSING", "The server title is missing.")
+		return
+	case req.Hostname == "":
+		writeError(w, http.StatusBadRequest, "HOSTNAME_MISSING", "The server hostname is missing.")
+		return
+	case len(req.StorageDevices.StorageDevice) == 0:
+		writeError(w, http.StatusBadRequest, "STORAGE_DEVICES_MISSING", "The server has no storage devices.")
+		return
+	}
+
+	if _, ok := e.zone(req.Zone); !ok {
+		writeError(w, http.StatusBadRequest, "ZONE_INVALID", fmt.Sprintf("The zone %s does not exist.", req.Zone))
+		return
+	}
+
+	srv := &server{}
+	srv.UUID = newUUID(uuidPrefixServer)
+	srv.Title = req.Title
+	srv.Hostname = req.Hostname
+	srv.Zone = req.Zone
+	srv.State = upcloud.ServerStateMaintenance
+	srv.BootOrder = firstNonEmpty(req.BootOrder, "disk")
+	srv.Firewall = firstNonEmpty(req.Firewall, "off")
+	srv.Metadata = booleanOrFalse(req.Metadata)
+	srv.NICModel = "virtio"
+	srv.SimpleBackup = firstNonEmpty(req.SimpleBackup, "no")
+	srv.Timezone = firstNonEmpty(req.TimeZone, "UTC")
+	srv.VideoModel = firstNonEmpty(req.VideoModel, upcloud.VideoModelCirrus)
+	srv.RemoteAccessEnabled = booleanOrFalse(req.RemoteAccessEnabled)
+	srv.RemoteAccessType = firstNonEmpty(req.RemoteAccessType, upcloud.RemoteAccessTypeVNC)
+	srv.RemoteAccessPassword = firstNonEmpty(req.RemoteAccessPassword, randomPassword())
+	srv.RemoteAccessHost = fmt.Sprintf("%s.console.upcloud.com", srv.Zone)
+	srv.RemoteAccessPort = 3000 + len(e.servers)
+
+	if !e.setServerSize(w, srv, req.Plan, int(req.CoreNumber), int(req.MemoryAmount)) {
+		return
+	}
+
+	host, ok := e.pickHost(w, srv.Zone, int(req.Host), int(req.AvoidHost))
+	if !ok {
+		return
+	}
+
+	created, ok := e.createServerStorages(w, srv, req.StorageDevices.StorageDevice)
+	if !ok {
+		return
+	}
+
+	interfaces := defaultInterfaces()
+	if req.Networking != nil && len(req.Networking.Interfaces.Interface) > 0 {
+		interfaces = req.Networking.Interfaces.Interface
+	}
+
+	addresses, ok := e.createInterfaces(w, srv, interfaces)
+	if !ok {
+		return
+	}
+
+	e.storages = append(e.storages, created...)
+	e.ipAddresses = append(e.ipAddresses, addresses...)
+	e.servers = append(e.servers, srv)
+
+	// The server is started once its storages have been prepared
+	e.later(1, func() {
+		for _, s := range created {
+			s.State = upcloud.StorageStateOnline
+		}
+		srv.State = upcloud.ServerStateStarted
+		srv.Host = host
+	})
+
+	writeJSON(w, http.StatusAccepted, e.serverDetails(srv))
+}
+
+// Returns the interfaces of a server created without networking settings
+func defaultInterfaces() []interfaceRequest {
+	newInterface := func(networkType, family string) interfaceRequest {
+		i := interfaceRequest{Type: networkType}
+		i.IPAddresses.IPAddress = []ipAddressRequest{{Family: family}}
+		return i
+	}
+
+	return []interfaceRequest{
+		newInterface(upcloud.NetworkTypeUtility, upcloud.IPAddressFamilyIPv4),
+		newInterface(upcloud.NetworkTypePublic, upcloud.IPAddressFamilyIPv4),
+		newInterface(upcloud.NetworkTypePublic, upcloud.IPAddressFamilyIPv6),
+	}
+}
+
+// Sets the CPU and memory of the server from a plan or from the custom values. An error response is written if they
+// are invalid.
+func (e *Emulator) setServerSize(w http.ResponseWriter, srv *server, planName string, cores, memory int) bool {
+	if planName != "" && planName != "custom" {
+		plan, ok := findPlan(planName)
+		if !ok {
+			writeError(w, http.StatusBadRequest, "PLAN_INVALID", fmt.Sprintf("The plan %s does not exist.", planName))
+			return false
+		}
+
+		srv.Plan = plan.Name
+		srv.CoreNumber = plan.CoreNumber
+		srv.MemoryAmount = plan.MemoryAmount
+		return true
+	}
+
+	srv.Plan = "custom"
+	if srv.CoreNumber == 0 {
+		srv.CoreNumber = 1
+	}
+	if srv.MemoryAmount == 0 {
+		srv.MemoryAmount = 1024
+	}
+
+	if cores != 0 {
+		if cores < 1 || cores > 20 {
+			writeError(w, http.StatusBadRequest, "CORE_NUMBER_INVALID", "The number of CPU cores must be between 1 and 20.")
+			return false
+		}
+		srv.CoreNumber = cores
+	}
+
+	if memory != 0 {
+		if memory < 1024 || memory%1024 != 0 || memory > 131072 {
+			writeError(w, http.StatusBadRequest, "MEMORY_AMOUNT_INVALID",
+				"The amount of memory must be a multiple of 1024 between 1024 and 131072 MiB.")
+			return false
+		}
+		srv.MemoryAmount = memory
+	}
+
+	return true
+}
+
+// Creates the storages requested for a new server and attaches them to it. The created storages are returned so
+// that they can be added to the account once the whole request has been validated. An error response is written
+// if a device is invalid.
+func (e *Emulator) createServerStorages(w http.ResponseWriter, srv *server, devices []createServerStorageDevice) ([]*storage, bool) {
+	var created []*storage
+
+	for i, device := range devices {
+		title := firstNonEmpty(device.Title, fmt.Sprintf("%s Device %d", srv.Title, i+1))
+		deviceType := firstNonEmpty(device.Type, upcloud.StorageTypeDisk)
+
+		var s *storage
+		switch device.Action {
+		case "create":
+			if !e.validateStorage(w, srv.Zone, device.Tier, int(device.Size)) {
+				return nil, false
+			}
+			s = e.newStorage(srv.Zone, upcloud.StorageTypeNormal, title, device.Tier, int(device.Size))
+			s.State = upcloud.StorageStateMaintenance
+			created = append(created, s)
+		case "clone":
+			source, ok := e.lookupStorage(w, device.Storage)
+			if !ok {
+				return nil, false
+			}
+			if source.Type != upcloud.StorageTypeTemplate && source.Type != upcloud.StorageTypeNormal {
+				writeError(w, http.StatusBadRequest, "STORAGE_TYPE_ILLEGAL",
+					fmt.Sprintf("The storage %s of type %s can't be cloned.", source.UUID, source.Type))
+				return nil, false
+			}
+			if source.State != upcloud.StorageStateOnline {
+				writeStorageStateIllegal(w, source)
+				return nil, false
+			}
+			s = e.newStorage(srv.Zone, upcloud.StorageTypeNormal, title, device.Tier, maxInt(int(device.Size), source.Size))
+			s.State = upcloud.StorageStateMaintenance
+			created = append(created, s)
+		case "attach":
+			if device.Storage == "" && deviceType == upcloud.StorageTypeCDROM {
+				break
+			}
+			var ok bool
+			if s, ok = e.lookupStorage(w, device.Storage); !ok {
+				return nil, false
+			}
+			if !e.canAttach(w, srv, s, deviceType) {
+				return nil, false
+			}
+		default:
+			writeError(w, http.StatusBadRequest, "ACTION_INVALID",
+				fmt.Sprintf("The storage device action %s is invalid.", device.Action))
+			return nil, false
+		}
+
+		attached := upcloud.ServerStorageDevice{
+			Address: deviceAddress(srv, device.Address, deviceType),
+			Type:    deviceType,
+		}
+		if s != nil {
+			attached.UUID = s.UUID
+		}
+
+		srv.StorageDevices = append(srv.StorageDevices, attached)
+	}
+
+	return created, true
+}
+
+// Returns the host a server is started on. When no host is requested, the first host of the zone other than the
+// one to avoid is used. An error response is written if the requested host doesn't exist.
+func (e *Emulator) pickHost(w http.ResponseWriter, zone string, requested, avoid int) (int, bool) {
+	var candidates []int
+	for _, host := range e.hosts {
+		if host.Zone == zone {
+			candidates = append(candidates, host.ID)
+		}
+	}
+
+	if requested != 0 {
+		for _, id := range candidates {
+			if id == requested {
+				return id, true
+			}
+		}
+
+		writeNotFound(w, "host", fmt.Sprintf("%d", requested))
+		return 0, false
+	}
+
+	if len(candidates) == 0 {
+		candidates = publicHostIDs
+	}
+
+	for _, id := range candidates {
+		if id != avoid {
+			return id, true
+		}
+	}
+
+	writeError(w, http.StatusConflict, "NO_HOST_AVAILABLE", "No host other than the avoided one is available.")
+	return 0, false
+}
+
+func (e *Emulator) startServer(w http.ResponseWriter, r *http.Request, p params) {
+	srv, ok := e.lookupServer(w, p["uuid"])
+	if !ok {
+		return
+	}
+
+	var body struct {
+		Server struct {
+			AvoidHost flexInt `json:"avoid_host"`
+			Host      flexInt `json:"host"`
+		} `json:"server"`
+	}
+	if !decodeOptionalBody(w, r, &body) {
+		return
+	}
+
+	if srv.State != upcloud.ServerStateStopped {
+		writeServerStateIllegal(w, srv)
+		return
+	}
+
+	host, ok := e.pickHost(w, srv.Zone, int(body.Server.Host), int(body.Server.AvoidHost))
+	if !ok {
+		return
+	}
+
+	srv.State = upcloud.ServerStateMaintenance
+	e.later(1, func() {
+		srv.State = upcloud.ServerStateStarted
+		srv.Host = host
+	})
+
+	writeJSON(w, http.StatusOK, e.serverDetails(srv))
+}
+
+func (e *Emulator) stopServer(w http.ResponseWriter, r *http.Request, p params) {
+	srv, ok := e.lookupServer(w, p["uuid"])
+	if !ok {
+		return
+	}
+
+	var body struct {
+		StopServer struct {
+			StopType string `json:"stop_type"`
+		} `json:"stop_server"`
+	}
+	if !decodeOptionalBody(w, r, &body) {
+		return
+	}
+
+	stopType := body.StopServer.StopType
+	if stopType != "" && stopType != upcloud.StopTypeSoft && stopType != upcloud.StopTypeHard {
+		writeError(w, http.StatusBadRequest, "STOP_TYPE_INVALID", fmt.Sprintf("The stop type %s is invalid.", stopType))
+		return
+	}
+
+	if srv.State != upcloud.ServerStateStarted {
+		writeServerStateIllegal(w, srv)
+		return
+	}
+
+	// The server keeps running until it has shut down
+	e.later(1, func() {
+		srv.State = upcloud.ServerStateStopped
+		srv.Host = 0
+	})
+
+	writeJSON(w, http.StatusOK, e.serverDetails(srv))
+}
+
+func (e *Emulator) restartServer(w http.ResponseWriter, r *http.Request, p params) {
+	srv, ok := e.lookupServer(w, p["uuid"])
+	if !ok {
+		return
+	}
+
+	var body struct {
+		RestartServer struct {
+			Host flexInt `json:"host"`
+		} `json:"restart_server"`
+	}
+	if !decodeOptionalBody(w, r, &body) {
+		return
+	}
+
+	if srv.State != upcloud.ServerStateStarted {
+		writeServerStateIllegal(w, srv)
+		return
+	}
+
+	host := srv.Host
+	if body.RestartServer.Host != 0 {
+		if host, ok = e.pickHost(w, srv.Zone, int(body.RestartServer.Host), 0); !ok {
+			return
+		}
+	}
+
+	srv.State = upcloud.ServerStateMaintenance
+	e.later(1, func() {
+		srv.State = upcloud.ServerStateStarted
+		srv.Host = host
+	})
+
+	writeJSON(w, http.StatusOK, e.serverDetails(srv))
+}
+
+func (e *Emulator) modifyServer(w http.ResponseWriter, r *http.Request, p params) {
+	srv, ok := e.lookupServer(w, p["uuid"])
+	if !ok {
+		return
+	}
+
+	var body struct {
+		Server createServerRequest `json:"server"`
+	}
+	if !decodeBody(w, r, &body) {
+		return
+	}
+
+	req := body.Server
+	if req.Plan != "" || req.CoreNumber != 0 || req.MemoryAmount != 0 {
+		if srv.State != upcloud.ServerStateStopped {
+			writeServerStateIllegal(w, srv)
+			return
+		}
+
+		// Validate the new size on a copy so that a failure leaves the server untouched. Setting the CPU or memory
+		// without a plan turns the server into a custom one.
+		resized := *srv
+		if !e.setServerSize(w, &resized, firstNonEmpty(req.Plan, "custom"), int(req.CoreNumber), int(req.MemoryAmount)) {
+			return
+		}
+		srv.Plan, srv.CoreNumber, srv.MemoryAmount = resized.Plan, resized.CoreNumber, resized.MemoryAmount
+	}
+
+	if req.VideoModel != "" && req.VideoModel != upcloud.VideoModelVGA && req.VideoModel != upcloud.VideoModelCirrus {
+		writeError(w, http.StatusBadRequest, "VIDEO_MODEL_INVALID", fmt.Sprintf("The video model %s is invalid.", req.VideoModel))
+		return
+	}
+
+	srv.Title = firstNonEmpty(req.Title, srv.Title)
+	srv.Hostname = firstNonEmpty(req.Hostname, srv.Hostname)
+	srv.BootOrder = firstNonEmpty(req.BootOrder, srv.BootOrder)
+	srv.Firewall = firstNonEmpty(req.Firewall, srv.Firewall)
+	srv.SimpleBackup = firstNonEmpty(req.SimpleBackup, srv.SimpleBackup)
+	srv.Timezone = firstNonEmpty(req.TimeZone, srv.Timezone)
+	srv.VideoModel = firstNonEmpty(req.VideoModel, srv.VideoModel)
+	srv.RemoteAccessType = firstNonEmpty(req.RemoteAccessType, srv.RemoteAccessType)
+	srv.RemoteAccessPassword = firstNonEmpty(req.RemoteAccessPassword, srv.RemoteAccessPassword)
+
+	if !req.Metadata.Empty() {
+		srv.Metadata = req.Metadata
+	}
+	if !req.RemoteAccessEnabled.Empty() {
+		srv.RemoteAccessEnabled = req.RemoteAccessEnabled
+	}
+
+	writeJSON(w, http.StatusAccepted, e.serverDetails(srv))
+}
+
+func (e *Emulator) deleteServer(w http.ResponseWriter, r *http.Request, p params) {
+	srv, ok := e.lookupServer(w, p["uuid"])
+	if !ok {
+		return
+	}
+
+	if srv.State != upcloud.ServerStateStopped {
+		writeServerStateIllegal(w, srv)
+		return
+	}
+
+	for i, candidate := range e.servers {
+		if candidate == srv {
+			e.servers = append(e.servers[:i], e.servers[i+1:]...)
+			break
+		}
+	}
+
+	for _, tag := range e.tags {
+		tag.Servers = removeString(tag.Servers, srv.UUID)
+	}
+
+	for _, iface := range srv.Networking.Interfaces {
+		e.releaseInterfaceAddresses(iface.MAC)
+	}
+
+	if r.URL.Query().Get("storages") == "1" {
+		for _, device := range srv.StorageDevices {
+			if s := e.storage(device.UUID); s != nil && s.Type == upcloud.StorageTypeNormal {
+				e.removeStorage(s.UUID)
+			}
+		}
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (e *Emulator) tagServer(w http.ResponseWriter, r *http.Request, p params) {
+	srv, ok := e.lookupServer(w, p["uuid"])
+	if !ok {
+		return
+	}
+
+	for _, name := range strings.Split(p["tags"], ",") {
+		tag := e.tag(name)
+		if tag == nil {
+			if !validTagName(name) {
+				writeError(w, http.StatusBadRequest, "TAG_INVALID", fmt.Sprintf("The tag name %s is invalid.", name))
+				return
+			}
+
+			tag = &upcloud.Tag{Name: name}
+			e.tags = append(e.tags, tag)
+		}
+
+		if !contains(tag.Servers, srv.UUID) {
+			tag.Servers = append(tag.Servers, srv.UUID)
+		}
+	}
+
+	writeJSON(w, http.StatusOK, e.serverDetails(srv))
+}
+
+func (e *Emulator) untagServer(w http.ResponseWriter, r *http.Request, p params) {
+	srv, ok := e.lookupServer(w, p["uuid"])
+	if !ok {
+		return
+	}
+
+	for _, name := range strings.Split(p["tags"], ",") {
+		tag := e.tag(name)
+		if tag == nil {
+			writeNotFound(w, "tag", name)
+			return
+		}
+
+		tag.Servers = removeString(tag.Servers, srv.UUID)
+	}
+
+	writeJSON(w, http.StatusOK, e.serverDetails(srv))
+}
+
+// Returns the list without the specified value
+func removeString(values []string, value string) []string {
+	result := values[:0]
+	for _, v := range values {
+		if v != value {
+			result = append(result, v)
+		}
+	}
+
+	return result
+}
@@ -0,0 +1,164 @@
+package emulator
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/UpCloudLtd/upcloud-go-api/upcloud"
+	"github.com/UpCloudLtd/upcloud-go-api/upcloud/request"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestCreateStartStopServer tests that a server goes through the maintenance, started and stopped states
+func TestCreateStartStopServer(t *testing.T) {
+	svc, _ := newTestService(t)
+
+	serverDetails, err := svc.CreateServer(&request.CreateServerRequest{
+		Zone:     "fi-hel2",
+		Title:    "emulator-test",
+		Hostname: "emulator-test.example.com",
+		StorageDevices: []request.CreateServerStorageDevice{
+			{
+				Action:  request.CreateServerStorageDeviceActionClone,
+				Storage: "01000000-0000-4000-8000-000030060200",
+				Title:   "disk1",
+				Size:    30,
+			},
+		},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, upcloud.ServerStateMaintenance, serverDetails.State)
+	assert.Equal(t, "custom", serverDetails.Plan)
+	assert.Equal(t, 1, serverDetails.CoreNumber)
+	assert.Equal(t, 1024, serverDetails.MemoryAmount)
+	require.Len(t, serverDetails.StorageDevices, 1)
+	assert.Equal(t, 30, serverDetails.StorageDevices[0].Size)
+	assert.NotEmpty(t, serverDetails.IPAddresses)
+
+	serverDetails, err = svc.WaitForServerState(&request.WaitForServerStateRequest{
+		UUID:         serverDetails.UUID,
+		DesiredState: upcloud.ServerStateStarted,
+		Timeout:      time.Second,
+	})
+	require.NoError(t, err)
+	assert.NotZero(t, serverDetails.Host)
+
+	// A started server can't be started again
+	_, err = svc.StartServer(&request.StartServerRequest{UUID: serverDetails.UUID})
+	assert.True(t, errors.Is(err, upcloud.ErrConflict))
+
+	stopTestServer(t, svc, serverDetails.UUID)
+
+	serverDetails, err = svc.StartServer(&request.StartServerRequest{UUID: serverDetails.UUID})
+	require.NoError(t, err)
+	assert.Equal(t, upcloud.ServerStateMaintenance, serverDetails.State)
+
+	serverDetails, err = svc.WaitForServerState(&request.WaitForServerStateRequest{
+		UUID:         serverDetails.UUID,
+		DesiredState: upcloud.ServerStateStarted,
+		Timeout:      time.Second,
+	})
+	require.NoError(t, err)
+
+	servers, err := svc.GetServers()
+	require.NoError(t, err)
+	require.Len(t, servers.Servers, 1)
+	assert.Equal(t, serverDetails.UUID, servers.Servers[0].UUID)
+}
+
+// TestRestartServer tests that a restarted server passes through maintenance
+func TestRestartServer(t *testing.T) {
+	svc, _ := newTestService(t)
+	serverDetails := createTestServer(t, svc, "fi-hel1")
+
+	serverDetails, err := svc.RestartServer(&request.RestartServerRequest{
+		UUID:          serverDetails.UUID,
+		StopType:      request.ServerStopTypeSoft,
+		Timeout:       time.Minute,
+		TimeoutAction: request.RestartTimeoutActionIgnore,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, upcloud.ServerStateMaintenance, serverDetails.State)
+
+	_, err = svc.WaitForServerState(&request.WaitForServerStateRequest{
+		UUID:         serverDetails.UUID,
+		DesiredState: upcloud.ServerStateStarted,
+		Timeout:      time.Second,
+	})
+	require.NoError(t, err)
+}
+
+// TestModifyDeleteServer tests that a stopped server can be resized and deleted along with its storages
+func TestModifyDeleteServer(t *testing.T) {
+	svc, _ := newTestService(t)
+	serverDetails := createTestServer(t, svc, "fi-hel1")
+
+	// Resizing requires the server to be stopped
+	_, err := svc.ModifyServer(&request.ModifyServerRequest{UUID: serverDetails.UUID, Plan: "2xCPU-4GB"})
+	assert.True(t, errors.Is(err, upcloud.ErrConflict))
+
+	serverDetails, err = svc.ModifyServer(&request.ModifyServerRequest{UUID: serverDetails.UUID, Title: "renamed"})
+	require.NoError(t, err)
+	assert.Equal(t, "renamed", serverDetails.Title)
+	assert.Equal(t, "1xCPU-2GB", serverDetails.Plan)
+
+	stopTestServer(t, svc, serverDetails.UUID)
+
+	serverDetails, err = svc.ModifyServer(&request.ModifyServerRequest{UUID: serverDetails.UUID, Plan: "2xCPU-4GB"})
+	require.NoError(t, err)
+	assert.Equal(t, 2, serverDetails.CoreNumber)
+	assert.Equal(t, 4096, serverDetails.MemoryAmount)
+
+	serverDetails, err = svc.ModifyServer(&request.ModifyServerRequest{UUID: serverDetails.UUID, CoreNumber: 3})
+	require.NoError(t, err)
+	assert.Equal(t, "custom", serverDetails.Plan)
+	assert.Equal(t, 3, serverDetails.CoreNumber)
+	assert.Equal(t, 4096, serverDetails.MemoryAmount)
+
+	err = svc.DeleteServerAndStorages(&request.DeleteServerAndStoragesRequest{UUID: serverDetails.UUID})
+	require.NoError(t, err)
+
+	_, err = svc.GetServerDetails(&request.GetServerDetailsRequest{UUID: serverDetails.UUID})
+	assert.True(t, errors.Is(err, upcloud.ErrNotFound))
+
+	storages, err := svc.GetStorages(&request.GetStoragesRequest{Access: upcloud.StorageAccessPrivate})
+	require.NoError(t, err)
+	assert.Empty(t, storages.Storages)
+
+	// The public address of the server is released
+	ipAddresses, err := svc.GetIPAddresses()
+	require.NoError(t, err)
+	assert.Empty(t, ipAddresses.IPAddresses)
+}
+
+// TestTagUntagServer tests that tagging creates missing tags and untagging removes the server from them
+func TestTagUntagServer(t *testing.T) {
+	svc, _ := newTestService(t)
+	serverDetails := createTestServer(t, svc, "fi-hel1")
+
+	serverDetails, err := svc.TagServer(&request.TagServerRequest{UUID: serverDetails.UUID, Tags: []string{"web", "prod"}})
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{"web", "prod"}, serverDetails.Tags)
+
+	serverDetails, err = svc.UntagServer(&request.UntagServerRequest{UUID: serverDetails.UUID, Tags: []string{"prod"}})
+	require.NoError(t, err)
+	assert.Equal(t, upcloud.ServerTagSlice{"web"}, serverDetails.Tags)
+
+	_, err = svc.UntagServer(&request.UntagServerRequest{UUID: serverDetails.UUID, Tags: []string{"nonexistent"}})
+	assert.True(t, errors.Is(err, upcloud.ErrNotFound))
+}
+
+// TestInvalidServerUUID tests that malformed UUIDs are rejected the way the API rejects them
+func TestInvalidServerUUID(t *testing.T) {
+	svc, _ := newTestService(t)
+
+	_, err := svc.GetServerDetails(&request.GetServerDetailsRequest{UUID: "invalid"})
+	require.Error(t, err)
+	assert.True(t, errors.Is(err, upcloud.ErrBadRequest))
+
+	var serviceError *upcloud.Error
+	require.True(t, errors.As(err, &serviceError))
+	assert.Equal(t, "SERVER_INVALID", serviceError.ErrorCode)
+}
@@ -0,0 +1,256 @@
+package emulator
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/UpCloudLtd/upcloud-go-api/upcloud"
+)
+
+// The public zones of the API
+var zones = []upcloud.Zone{
+	{ID: "de-fra1", Description: "Frankfurt #1", Public: upcloud.True},
+	{ID: "es-mad1", Description: "Madrid #1", Public: upcloud.True},
+	{ID: "fi-hel1", Description: "Helsinki #1", Public: upcloud.True},
+	{ID: "fi-hel2", Description: "Helsinki #2", Public: upcloud.True},
+	{ID: "nl-ams1", Description: "Amsterdam #1", Public: upcloud.True},
+	{ID: "pl-waw1", Description: "Warsaw #1", Public: upcloud.True},
+	{ID: "sg-sin1", Description: "Singapore #1", Public: upcloud.True},
+	{ID: "uk-lon1", Description: "London #1", Public: upcloud.True},
+	{ID: "us-chi1", Description: "Chicago #1", Public: upcloud.True},
+	{ID: "us-nyc1", Description: "New York #1", Public: upcloud.True},
+	{ID: "us-sjo1", Description: "San Jose #1", Public: upcloud.True},
+}
+
+// The preconfigured server plans
+var plans = []upcloud.Plan{
+	{CoreNumber: 1, MemoryAmount: 1024, Name: "1xCPU-1GB", PublicTrafficOut: 1024, StorageSize: 25, StorageTier: upcloud.StorageTierMaxIOPS},
+	{CoreNumber: 1, MemoryAmount: 2048, Name: "1xCPU-2GB", PublicTrafficOut: 2048, StorageSize: 50, StorageTier: upcloud.StorageTierMaxIOPS},
+	{CoreNumber: 2, MemoryAmount: 4096, Name: "2xCPU-4GB", PublicTrafficOut: 4096, StorageSize: 80, StorageTier: upcloud.StorageTierMaxIOPS},
+	{CoreNumber: 4, MemoryAmount: 8192, Name: "4xCPU-8GB", PublicTrafficOut: 5120, StorageSize: 160, StorageTier: upcloud.StorageTierMaxIOPS},
+	{CoreNumber: 6, MemoryAmount: 16384, Name: "6xCPU-16GB", PublicTrafficOut: 6144, StorageSize: 320, StorageTier: upcloud.StorageTierMaxIOPS},
+	{CoreNumber: 8, MemoryAmount: 32768, Name: "8xCPU-32GB", PublicTrafficOut: 7168, StorageSize: 640, StorageTier: upcloud.StorageTierMaxIOPS},
+	{CoreNumber: 12, MemoryAmount: 49152, Name: "12xCPU-48GB", PublicTrafficOut: 9216, StorageSize: 960, StorageTier: upcloud.StorageTierMaxIOPS},
+	{CoreNumber: 16, MemoryAmount: 65536, Name: "16xCPU-64GB", PublicTrafficOut: 10240, StorageSize: 1280, StorageTier: upcloud.StorageTierMaxIOPS},
+	{CoreNumber: 20, MemoryAmount: 98304, Name: "20xCPU-96GB", PublicTrafficOut: 12288, StorageSize: 1920, StorageTier: upcloud.StorageTierMaxIOPS},
+	{CoreNumber: 20, MemoryAmount: 131072, Name: "20xCPU-128GB", PublicTrafficOut: 24576, StorageSize: 2048, StorageTier: upcloud.StorageTierMaxIOPS},
+}
+
+// A selection of the time zones supported by the API
+var timeZones = []string{
+	"Africa/Cairo",
+	"Africa/Johannesburg",
+	"America/Chicago",
+	"America/Los_Angeles",
+	"America/New_York",
+	"America/Sao_Paulo",
+	"Asia/Singapore",
+	"Asia/Tokyo",
+	"Australia/Sydney",
+	"Europe/Amsterdam",
+	"Europe/Berlin",
+	"Europe/Helsinki",
+	"Europe/London",
+	"Europe/Madrid",
+	"Europe/Warsaw",
+	"UTC",
+}
+
+// The public templates and CD-ROMs available in all zones
+var publicStorages = []upcloud.Storage{
+	{UUID: "01000000-0000-4000-8000-000030060200", Title: "Ubuntu Server 20.04 LTS (Focal Fossa)", Type: upcloud.StorageTypeTemplate, Size: 3},
+	{UUID: "01000000-0000-4000-8000-000030080200", Title: "Ubuntu Server 18.04 LTS (Bionic Beaver)", Type: upcloud.StorageTypeTemplate, Size: 3},
+	{UUID: "01000000-0000-4000-8000-000020050100", Title: "Debian GNU/Linux 10 (Buster)", Type: upcloud.StorageTypeTemplate, Size: 3},
+	{UUID: "01000000-0000-4000-8000-000050010300", Title: "CentOS 8", Type: upcloud.StorageTypeTemplate, Size: 4},
+	{UUID: "01000000-0000-4000-8000-000030060101", Title: "Ubuntu Server 20.04 LTS (Focal Fossa) installation CD", Type: upcloud.StorageTypeCDROM, Size: 1},
+	{UUID: "01000000-0000-4000-8000-000020050101", Title: "Debian GNU/Linux 10 (Buster) installation CD", Type: upcloud.StorageTypeCDROM, Size: 1},
+}
+
+// Returns the zone with the specified ID, taking the zones of private cloud hosts into account
+func (e *Emulator) zone(id string) (upcloud.Zone, bool) {
+	for _, z := range e.zones() {
+		if z.ID == id {
+			return z, true
+		}
+	}
+
+	return upcloud.Zone{}, false
+}
+
+// Returns the public zones and the zones of the private cloud hosts
+func (e *Emulator) zones() []upcloud.Zone {
+	all := append([]upcloud.Zone{}, zones...)
+
+	for _, host := range e.hosts {
+		found := false
+		for _, z := range all {
+			found = found || z.ID == host.Zone
+		}
+
+		if !found {
+			all = append(all, upcloud.Zone{ID: host.Zone, Description: "Private cloud " + host.Zone, Public: upcloud.False})
+		}
+	}
+
+	return all
+}
+
+// Adds the public templates and CD-ROMs
+func (e *Emulator) addPublicStorages() {
+	for _, s := range publicStorages {
+		s.Access = upcloud.StorageAccessPublic
+		s.State = upcloud.StorageStateOnline
+		s.Tier = upcloud.StorageTierMaxIOPS
+		s.Zone = ""
+		s.Created = time.Date(2020, 4, 23, 0, 0, 0, 0, time.UTC)
+
+		e.storages = append(e.storages, &storage{Storage: s})
+	}
+}
+
+// Adds the public IPv4, public IPv6 and utility networks of every zone
+func (e *Emulator) addPublicNetworks() {
+	for i, z := range e.zones() {
+		n := i + 1
+
+		e.networks = append(e.networks,
+			&upcloud.Network{
+				UUID: fmt.Sprintf("03000000-0000-4000-8%03d-000000000001", n),
+				Name: fmt.Sprintf("Public 94.237.%d.0/22", n*4),
+				Type: upcloud.NetworkTypePublic,
+				Zone: z.ID,
+				IPNetworks: upcloud.IPNetworkSlice{{
+					Address:          fmt.Sprintf("94.237.%d.0/22", n*4),
+					DHCP:             upcloud.True,
+					DHCPDefaultRoute: upcloud.True,
+					DHCPDns:          []string{"94.237.127.9", "94.237.40.9"},
+					Family:           upcloud.IPAddressFamilyIPv4,
+					Gateway:          fmt.Sprintf("94.237.%d.1", n*4),
+				}},
+			},
+			&upcloud.Network{
+				UUID: fmt.Sprintf("03000000-0000-4000-8%03d-000000000002", n),
+				Name: fmt.Sprintf("Public 2a04:3545:%x::/64", n),
+				Type: upcloud.NetworkTypePublic,
+				Zone: z.ID,
+				IPNetworks: upcloud.IPNetworkSlice{{
+					Address:          fmt.Sprintf("2a04:3545:%x::/64", n),
+					DHCP:             upcloud.True,
+					DHCPDefaultRoute: upcloud.True,
+					DHCPDns:          []string{"2a04:3540:53::1", "2a04:3544:53::1"},
+					Family:           upcloud.IPAddressFamilyIPv6,
+					Gateway:          fmt.Sprintf("2a04:3545:%x::1", n),
+				}},
+			},
+			&upcloud.Network{
+				UUID: fmt.Sprintf("03000000-0000-4000-8%03d-000000000003", n),
+				Name: fmt.Sprintf("Private 10.%d.0.0/22", n),
+				Type: upcloud.NetworkTypeUtility,
+				Zone: z.ID,
+				IPNetworks: upcloud.IPNetworkSlice{{
+					Address:          fmt.Sprintf("10.%d.0.0/22", n),
+					DHCP:             upcloud.True,
+					DHCPDefaultRoute: upcloud.False,
+					Family:           upcloud.IPAddressFamilyIPv4,
+					Gateway:          fmt.Sprintf("10.%d.0.1", n),
+				}},
+			},
+		)
+	}
+}
+
+// Registers the handlers of the read-only endpoints
+func (e *Emulator) addStaticRoutes() {
+	e.handle(http.MethodGet, "/account", e.getAccount)
+	e.handle(http.MethodGet, "/zone", e.getZones)
+	e.handle(http.MethodGet, "/plan", e.getPlans)
+	e.handle(http.MethodGet, "/price", e.getPrices)
+	e.handle(http.MethodGet, "/server_size", e.getServerSizes)
+	e.handle(http.MethodGet, "/timezone", e.getTimeZones)
+}
+
+func (e *Emulator) getAccount(w http.ResponseWriter, r *http.Request, p params) {
+	username, _, _ := r.BasicAuth()
+
+	writeJSON(w, http.StatusOK, object{
+		"account": upcloud.Account{
+			Credits:  10000,
+			UserName: username,
+			ResourceLimits: upcloud.ResourceLimits{
+				Cores:               100,
+				DetachedFloatingIps: 10,
+				Memory:              307200,
+				Networks:            100,
+				PublicIPv4:          100,
+				PublicIPv6:          100,
+				StorageHDD:          10240,
+				StorageSSD:          10240,
+			},
+		},
+	})
+}
+
+func (e *Emulator) getZones(w http.ResponseWriter, r *http.Request, p params) {
+	items := []object{}
+	for _, z := range e.zones() {
+		items = append(items, object{
+			"id":          z.ID,
+			"description": z.Description,
+			"public":      z.Public,
+		})
+	}
+
+	writeJSON(w, http.StatusOK, object{"zones": list("zone", items)})
+}
+
+func (e *Emulator) getPlans(w http.ResponseWriter, r *http.Request, p params) {
+	writeJSON(w, http.StatusOK, object{"plans": object{"plan": plans}})
+}
+
+func (e *Emulator) getPrices(w http.ResponseWriter, r *http.Request, p params) {
+	items := []upcloud.PriceZone{}
+	for _, z := range e.zones() {
+		items = append(items, upcloud.PriceZone{
+			Name:                   z.ID,
+			Firewall:               &upcloud.Price{Amount: 1, Price: 0.56},
+			IORequestBackup:        &upcloud.Price{Amount: 1000000, Price: 0},
+			IORequestMaxIOPS:       &upcloud.Price{Amount: 1000000, Price: 0},
+			IPv4Address:            &upcloud.Price{Amount: 1, Price: 0.336},
+			IPv6Address:            &upcloud.Price{Amount: 1, Price: 0},
+			PublicIPv4BandwidthIn:  &upcloud.Price{Amount: 1, Price: 0},
+			PublicIPv4BandwidthOut: &upcloud.Price{Amount: 1, Price: 5},
+			PublicIPv6BandwidthIn:  &upcloud.Price{Amount: 1, Price: 0},
+			PublicIPv6BandwidthOut: &upcloud.Price{Amount: 1, Price: 5},
+			ServerCore:             &upcloud.Price{Amount: 1, Price: 1.3},
+			ServerMemory:           &upcloud.Price{Amount: 256, Price: 0.45},
+			ServerPlan1xCPU1GB:     &upcloud.Price{Amount: 1, Price: 0.744},
+			ServerPlan2xCPU2GB:     &upcloud.Price{Amount: 1, Price: 1.488},
+			ServerPlan4xCPU4GB:     &upcloud.Price{Amount: 1, Price: 5.952},
+			ServerPlan6xCPU8GB:     &upcloud.Price{Amount: 1, Price: 11.905},
+			StorageBackup:          &upcloud.Price{Amount: 1, Price: 0.007},
+			StorageMaxIOPS:         &upcloud.Price{Amount: 1, Price: 0.031},
+			StorageTemplate:        &upcloud.Price{Amount: 1, Price: 0.031},
+		})
+	}
+
+	writeJSON(w, http.StatusOK, object{"prices": object{"zone": items}})
+}
+
+func (e *Emulator) getServerSizes(w http.ResponseWriter, r *http.Request, p params) {
+	items := []object{}
+	for cores := 1; cores <= 20; cores++ {
+		for memory := cores * 1024; memory <= cores*8192 && memory <= 131072; memory += 1024 {
+			items = append(items, object{
+				"core_number":   fmt.Sprintf("%d", cores),
+				"memory_amount": fmt.Sprintf("%d", memory),
+			})
+		}
+	}
+
+	writeJSON(w, http.StatusOK, object{"server_sizes": list("server_size", items)})
+}
+
+func (e *Emulator) getTimeZones(w http.ResponseWriter, r *http.Request, p params) {
+	writeJSON(w, http.StatusOK, object{"timezones": object{"timezone": timeZones}})
+}
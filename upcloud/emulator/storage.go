@@ -0,0 +1,940 @@
+package emulator
+
+import (
+	"crypto/md5"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/UpCloudLtd/upcloud-go-api/upcloud"
+)
+
+// Prefix of the UUIDs of storage imports
+const uuidPrefixStorageImport = "07"
+
+// storage is a storage device along with the settings that aren't part of upcloud.Storage
+type storage struct {
+	upcloud.Storage
+
+	backupRule *upcloud.BackupRule
+}
+
+// storageImport is an import into a storage device
+type storageImport struct {
+	upcloud.StorageImportDetails
+
+	storageUUID string
+}
+
+// Registers the handlers of the storage endpoints
+func (e *Emulator) addStorageRoutes() {
+	e.handle(http.MethodGet, "/storage", e.getStorages)
+	e.handle(http.MethodGet, "/storage/{uuid}", e.getStorage)
+	e.handle(http.MethodPost, "/storage", e.createStorage)
+	e.handle(http.MethodPut, "/storage/{uuid}", e.modifyStorage)
+	e.handle(http.MethodDelete, "/storage/{uuid}", e.deleteStorage)
+	e.handle(http.MethodPost, "/storage/{uuid}/clone", e.cloneStorage)
+	e.handle(http.MethodPost, "/storage/{uuid}/templatize", e.templatizeStorage)
+	e.handle(http.MethodPost, "/storage/{uuid}/backup", e.createBackup)
+	e.handle(http.MethodPost, "/storage/{uuid}/restore", e.restoreBackup)
+	e.handle(http.MethodPost, "/storage/{uuid}/import", e.createStorageImport)
+	e.handle(http.MethodGet, "/storage/{uuid}/import", e.getStorageImport)
+	e.handle(http.MethodPost, "/server/{uuid}/storage/attach", e.attachStorage)
+	e.handle(http.MethodPost, "/server/{uuid}/storage/detach", e.detachStorage)
+	e.handle(http.MethodPost, "/server/{uuid}/cdrom/load", e.loadCDROM)
+	e.handle(http.MethodPost, "/server/{uuid}/cdrom/eject", e.ejectCDROM)
+}
+
+// Returns the storage with the specified UUID. An error response is written if there's no such storage.
+func (e *Emulator) lookupStorage(w http.ResponseWriter, uuid string) (*storage, bool) {
+	if !validUUID(uuid) {
+		writeError(w, http.StatusBadRequest, "STORAGE_INVALID", "The storage UUID is invalid.")
+		return nil, false
+	}
+
+	for _, s := range e.storages {
+		if s.UUID == uuid {
+			return s, true
+		}
+	}
+
+	writeNotFound(w, "storage", uuid)
+	return nil, false
+}
+
+// Returns the UUIDs of the servers the storage is attached to
+func (e *Emulator) storageServers(uuid string) []string {
+	uuids := []string{}
+	for _, srv := range e.servers {
+		for _, device := range srv.StorageDevices {
+			if device.UUID == uuid {
+				uuids = append(uuids, srv.UUID)
+				break
+			}
+		}
+	}
+
+	return uuids
+}
+
+// Returns the UUIDs of the backups of the storage
+func (e *Emulator) storageBackups(uuid string) []string {
+	uuids := []string{}
+	for _, s := range e.storages {
+		if s.Type == upcloud.StorageTypeBackup && s.Origin == uuid {
+			uuids = append(uuids, s.UUID)
+		}
+	}
+
+	return uuids
+}
+
+// Returns whether the storage is attached to a server that isn't stopped
+func (e *Emulator) storageInUse(uuid string) bool {
+	for _, serverUUID := range e.storageServers(uuid) {
+		if srv := e.server(serverUUID); srv != nil && srv.State != upcloud.ServerStateStopped {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Returns the storage the way it appears in lists
+func (e *Emulator) storageObject(s *storage) object {
+	o := object{
+		"access":  s.Access,
+		"license": s.License,
+		"size":    s.Size,
+		"state":   s.State,
+		"tier":    s.Tier,
+		"title":   s.Title,
+		"type":    s.Type,
+		"uuid":    s.UUID,
+		"zone":    s.Zone,
+	}
+
+	if s.Type == upcloud.StorageTypeBackup {
+		o["origin"] = s.Origin
+		o["created"] = s.Created.Format(time.RFC3339)
+	}
+
+	return o
+}
+
+// Returns the details of the storage
+func (e *Emulator) storageDetails(s *storage) object {
+	o := e.storageObject(s)
+	o["servers"] = object{"server": e.storageServers(s.UUID)}
+
+	if s.Type == upcloud.StorageTypeNormal {
+		o["backups"] = object{"backup": e.storageBackups(s.UUID)}
+		o["backup_rule"] = s.backupRule
+	}
+
+	return object{"storage": o}
+}
+
+func (e *Emulator) getStorages(w http.ResponseWriter, r *http.Request, p params) {
+	e.writeStorages(w, func(s *storage) bool { return true })
+}
+
+// Writes the storages matching the filter as a list
+func (e *Emulator) writeStorages(w http.ResponseWriter, filter func(s *storage) bool) {
+	items := []object{}
+	for _, s := range e.storages {
+		if filter(s) {
+			items = append(items, e.storageObject(s))
+		}
+	}
+
+	writeJSON(w, http.StatusOK, object{"storages": list("storage", items)})
+}
+
+func (e *Emulator) getStorage(w http.ResponseWriter, r *http.Request, p params) {
+	// The storage lists filtered by access or type share the URL of the storage details
+	switch filter := p["uuid"]; filter {
+	case upcloud.StorageAccessPublic, upcloud.StorageAccessPrivate:
+		e.writeStorages(w, func(s *storage) bool { return s.Access == filter })
+		return
+	case upcloud.StorageTypeBackup, upcloud.StorageTypeCDROM, upcloud.StorageTypeNormal, upcloud.StorageTypeTemplate:
+		e.writeStorages(w, func(s *storage) bool { return s.Type == filter })
+		return
+	case "favorite":
+		e.writeStorages(w, func(s *storage) bool { return false })
+		return
+	}
+
+	s, ok := e.lookupStorage(w, p["uuid"])
+	if !ok {
+		return
+	}
+
+	writeJSON(w, http.StatusOK, e.storageDetails(s))
+}
+
+// Returns a new private storage in the specified zone. The storage isn't added to the account.
+func (e *Emulator) newStorage(zone, storageType, title, tier string, size int) *storage {
+	return &storage{
+		Storage: upcloud.Storage{
+			Access:  upcloud.StorageAccessPrivate,
+			Created: e.now().UTC().Truncate(time.Second),
+			Size:    size,
+			State:   upcloud.StorageStateOnline,
+			Tier:    firstNonEmpty(tier, upcloud.StorageTierMaxIOPS),
+			Title:   title,
+			Type:    storageType,
+			UUID:    newUUID(uuidPrefixStorage),
+			Zone:    zone,
+		},
+	}
+}
+
+// Validates the settings of a new storage. An error response is written if they are invalid.
+func (e *Emulator) validateStorage(w http.ResponseWriter, zone, tier string, size int) bool {
+	if _, ok := e.zone(zone); !ok {
+		writeError(w, http.StatusBadRequest, "ZONE_INVALID", fmt.Sprintf("The zone %s does not exist.", zone))
+		return false
+	}
+
+	if tier != "" && tier != upcloud.StorageTierHDD && tier != upcloud.StorageTierMaxIOPS {
+		writeError(w, http.StatusBadRequest, "TIER_INVALID", fmt.Sprintf("The storage tier %s is invalid.", tier))
+		return false
+	}
+
+	if size < 10 || size > 4096 {
+		writeError(w, http.StatusBadRequest, "SIZE_INVALID", "The storage size must be between 10 and 4096 GiB.")
+		return false
+	}
+
+	return true
+}
+
+func (e *Emulator) createStorage(w http.ResponseWriter, r *http.Request, p params) {
+	var body struct {
+		Storage struct {
+			Size       flexInt             `json:"size"`
+			Tier       string              `json:"tier"`
+			Title      string              `json:"title"`
+			Zone       string              `json:"zone"`
+			BackupRule *upcloud.BackupRule `json:"backup_rule"`
+		} `json:"storage"`
+	}
+	if !decodeBody(w, r, &body) {
+		return
+	}
+
+	req := body.Storage
+	if req.Title == "" {
+		writeError(w, http.StatusBadRequest, "TITLE_MISSING", "The storage title is missing.")
+		return
+	}
+	if !e.validateStorage(w, req.Zone, req.Tier, int(req.Size)) {
+		return
+	}
+
+	s := e.newStorage(req.Zone, upcloud.StorageTypeNormal, req.Title, req.Tier, int(req.Size))
+	s.backupRule = req.BackupRule
+	e.storages = append(e.storages, s)
+
+	writeJSON(w, http.StatusCreated, e.storageDetails(s))
+}
+
+func (e *Emulator) modifyStorage(w http.ResponseWriter, r *http.Request, p params) {
+	s, ok := e.lookupStorage(w, p["uuid"])
+	if !ok {
+		return
+	}
+
+	var body struct {
+		Storage struct {
+			Size       flexInt             `json:"size"`
+			Title      string              `json:"title"`
+			BackupRule *upcloud.BackupRule `json:"backup_rule"`
+		} `json:"storage"`
+	}
+	if !decodeBody(w, r, &body) {
+		return
+	}
+
+	if s.Access != upcloud.StorageAccessPrivate {
+		writeError(w, http.StatusForbidden, "STORAGE_FORBIDDEN", "The storage can't be modified.")
+		return
+	}
+
+	req := body.Storage
+	if req.Size != 0 && int(req.Size) != s.Size {
+		if int(req.Size) < s.Size {
+			writeError(w, http.StatusBadRequest, "SIZE_INVALID", "The size of a storage can't be decreased.")
+			return
+		}
+		if e.storageInUse(s.UUID) {
+			writeError(w, http.StatusConflict, "SERVER_STATE_ILLEGAL",
+				"The storage must be detached or its server stopped to change its size.")
+			return
+		}
+		s.Size = int(req.Size)
+	}
+
+	if req.Title != "" {
+		s.Title = req.Title
+	}
+
+	if req.BackupRule != nil {
+		// An empty interval removes the backup rule
+		if req.BackupRule.Interval == "" {
+			s.backupRule = nil
+		} else {
+			rule := *req.BackupRule
+			s.backupRule = &rule
+		}
+	}
+
+	writeJSON(w, http.StatusAccepted, e.storageDetails(s))
+}
+
+func (e *Emulator) deleteStorage(w http.ResponseWriter, r *http.Request, p params) {
+	s, ok := e.lookupStorage(w, p["uuid"])
+	if !ok {
+		return
+	}
+
+	if s.Access != upcloud.StorageAccessPrivate {
+		writeError(w, http.StatusForbidden, "STORAGE_FORBIDDEN", "Public storages can't be deleted.")
+		return
+	}
+
+	if len(e.storageServers(s.UUID)) > 0 {
+		writeError(w, http.StatusConflict, "STORAGE_ATTACHED",
+			fmt.Sprintf("The storage %s is attached to a server.", s.UUID))
+		return
+	}
+
+	if s.State != upcloud.StorageStateOnline {
+		writeStorageStateIllegal(w, s)
+		return
+	}
+
+	e.removeStorage(s.UUID)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// Removes the storage and its import from the account
+func (e *Emulator) removeStorage(uuid string) {
+	for i, s := range e.storages {
+		if s.UUID == uuid {
+			e.storages = append(e.storages[:i], e.storages[i+1:]...)
+			break
+		}
+	}
+
+	delete(e.imports, uuid)
+}
+
+// Writes the error of an operation that isn't allowed in the current state of the storage
+func writeStorageStateIllegal(w http.ResponseWriter, s *storage) {
+	writeError(w, http.StatusConflict, "STORAGE_STATE_ILLEGAL",
+		fmt.Sprintf("The storage %s is in state %s.", s.UUID, s.State))
+}
+
+// Puts the source into the specified state and the copy into maintenance until the copy is complete
+func (e *Emulator) copyStorage(source, copied *storage, sourceState string) {
+	copied.State = upcloud.StorageStateMaintenance
+	e.storages = append(e.storages, copied)
+
+	if source.Access == upcloud.StorageAccessPrivate {
+		source.State = sourceState
+	}
+
+	e.later(1, func() {
+		copied.State = upcloud.StorageStateOnline
+		if source.Access == upcloud.StorageAccessPrivate {
+			source.State = upcloud.StorageStateOnline
+		}
+	})
+}
+
+func (e *Emulator) cloneStorage(w http.ResponseWriter, r *http.Request, p params) {
+	s, ok := e.lookupStorage(w, p["uuid"])
+	if !ok {
+		return
+	}
+
+	var body struct {
+		Storage struct {
+			Zone  string `json:"zone"`
+			Tier  string `json:"tier"`
+			Title string `json:"title"`
+		} `json:"storage"`
+	}
+	if !decodeBody(w, r, &body) {
+		return
+	}
+
+	req := body.Storage
+	if req.Title == "" {
+		writeError(w, http.StatusBadRequest, "TITLE_MISSING", "The storage title is missing.")
+		return
+	}
+	if !e.validateStorage(w, req.Zone, req.Tier, 10) {
+		return
+	}
+	if s.State != upcloud.StorageStateOnline {
+		writeStorageStateIllegal(w, s)
+		return
+	}
+	if s.Type == upcloud.StorageTypeCDROM || s.Type == upcloud.StorageTypeBackup {
+		writeError(w, http.StatusBadRequest, "STORAGE_TYPE_ILLEGAL",
+			fmt.Sprintf("Storages of type %s can't be cloned.", s.Type))
+		return
+	}
+
+	clone := e.newStorage(req.Zone, upcloud.StorageTypeNormal, req.Title, req.Tier, maxInt(s.Size, 10))
+	e.copyStorage(s, clone, upcloud.StorageStateCloning)
+
+	writeJSON(w, http.StatusCreated, e.storageDetails(clone))
+}
+
+func (e *Emulator) templatizeStorage(w http.ResponseWriter, r *http.Request, p params) {
+	s, ok := e.lookupStorage(w, p["uuid"])
+	if !ok {
+		return
+	}
+
+	var body struct {
+		Storage struct {
+			Title string `json:"title"`
+		} `json:"storage"`
+	}
+	if !decodeBody(w, r, &body) {
+		return
+	}
+
+	if body.Storage.Title == "" {
+		writeError(w, http.StatusBadRequest, "TITLE_MISSING", "The storage title is missing.")
+		return
+	}
+	if s.Type != upcloud.StorageTypeNormal || s.Access != upcloud.StorageAccessPrivate {
+		writeError(w, http.StatusBadRequest, "STORAGE_TYPE_ILLEGAL", "Only private normal storages can be templatized.")
+		return
+	}
+	if s.State != upcloud.StorageStateOnline {
+		writeStorageStateIllegal(w, s)
+		return
+	}
+	if e.storageInUse(s.UUID) {
+		writeError(w, http.StatusConflict, "SERVER_STATE_ILLEGAL",
+			"The storage must be detached or its server stopped to templatize it.")
+		return
+	}
+
+	template := e.newStorage(s.Zone, upcloud.StorageTypeTemplate, body.Storage.Title, s.Tier, s.Size)
+	e.copyStorage(s, template, upcloud.StorageStateCloning)
+
+	writeJSON(w, http.StatusCreated, e.storageDetails(template))
+}
+
+func (e *Emulator) createBackup(w http.ResponseWriter, r *http.Request, p params) {
+	s, ok := e.lookupStorage(w, p["uuid"])
+	if !ok {
+		return
+	}
+
+	var body struct {
+		Storage struct {
+			Title string `json:"title"`
+		} `json:"storage"`
+	}
+	if !decodeBody(w, r, &body) {
+		return
+	}
+
+	if body.Storage.Title == "" {
+		writeError(w, http.StatusBadRequest, "TITLE_MISSING", "The backup title is missing.")
+		return
+	}
+	if s.Type != upcloud.StorageTypeNormal || s.Access != upcloud.StorageAccessPrivate {
+		writeError(w, http.StatusBadRequest, "STORAGE_TYPE_ILLEGAL", "Only private normal storages can be backed up.")
+		return
+	}
+	if s.State != upcloud.StorageStateOnline {
+		writeStorageStateIllegal(w, s)
+		return
+	}
+
+	backup := e.newStorage(s.Zone, upcloud.StorageTypeBackup, body.Storage.Title, s.Tier, s.Size)
+	backup.Origin = s.UUID
+	e.copyStorage(s, backup, upcloud.StorageStateBackuping)
+
+	writeJSON(w, http.StatusCreated, e.storageDetails(backup))
+}
+
+func (e *Emulator) restoreBackup(w http.ResponseWriter, r *http.Request, p params) {
+	backup, ok := e.lookupStorage(w, p["uuid"])
+	if !ok {
+		return
+	}
+
+	if backup.Type != upcloud.StorageTypeBackup {
+		writeError(w, http.StatusBadRequest, "STORAGE_TYPE_ILLEGAL", fmt.Sprintf("The storage %s is not a backup.", backup.UUID))
+		return
+	}
+
+	origin := e.storage(backup.Origin)
+	if origin == nil {
+		writeNotFound(w, "storage", backup.Origin)
+		return
+	}
+	if origin.State != upcloud.StorageStateOnline {
+		writeStorageStateIllegal(w, origin)
+		return
+	}
+	if e.storageInUse(origin.UUID) {
+		writeError(w, http.StatusConflict, "SERVER_STATE_ILLEGAL",
+			"The storage must be detached or its server stopped to restore a backup.")
+		return
+	}
+
+	origin.State = upcloud.StorageStateMaintenance
+	e.later(1, func() {
+		origin.State = upcloud.StorageStateOnline
+	})
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// Returns the storage with the specified UUID, or nil if there's no such storage
+func (e *Emulator) storage(uuid string) *storage {
+	for _, s := range e.storages {
+		if s.UUID == uuid {
+			return s
+		}
+	}
+
+	return nil
+}
+
+// Returns the storage import the way it appears in responses
+func storageImportObject(i *storageImport) object {
+	completed := ""
+	if !i.Completed.IsZero() {
+		completed = i.Completed.Format(time.RFC3339)
+	}
+
+	return object{
+		"storage_import": object{
+			"client_content_length": i.ClientContentLength,
+			"client_content_type":   i.ClientContentType,
+			"completed":             completed,
+			"created":               i.Created.Format(time.RFC3339),
+			"direct_upload_url":     i.DirectUploadURL,
+			"error_code":            i.ErrorCode,
+			"error_message":         i.ErrorMessage,
+			"md5sum":                i.MD5Sum,
+			"read_bytes":            i.ReadBytes,
+			"sha256sum":             i.SHA256Sum,
+			"source":                i.Source,
+			"source_location":       i.SourceLocation,
+			"state":                 i.State,
+			"uuid":                  i.UUID,
+			"written_bytes":         i.WrittenBytes,
+		},
+	}
+}
+
+func (e *Emulator) createStorageImport(w http.ResponseWriter, r *http.Request, p params) {
+	s, ok := e.lookupStorage(w, p["uuid"])
+	if !ok {
+		return
+	}
+
+	var body struct {
+		StorageImport struct {
+			Source         string `json:"source"`
+			SourceLocation string `json:"source_location"`
+		} `json:"storage_import"`
+	}
+	if !decodeBody(w, r, &body) {
+		return
+	}
+
+	req := body.StorageImport
+	switch req.Source {
+	case upcloud.StorageImportSourceDirectUpload:
+	case upcloud.StorageImportSourceHTTPImport:
+		if !strings.HasPrefix(req.SourceLocation, "http://") && !strings.HasPrefix(req.SourceLocation, "https://") {
+			writeError(w, http.StatusBadRequest, "SOURCE_LOCATION_INVALID", "The source location must be an HTTP(S) URL.")
+			return
+		}
+	default:
+		writeError(w, http.StatusBadRequest, "SOURCE_INVALID", fmt.Sprintf("The import source %s is invalid.", req.Source))
+		return
+	}
+
+	if s.Type != upcloud.StorageTypeNormal || s.Access != upcloud.StorageAccessPrivate {
+		writeError(w, http.StatusBadRequest, "STORAGE_TYPE_ILLEGAL", "Only private normal storages can be imported into.")
+		return
+	}
+	if s.State != upcloud.StorageStateOnline {
+		writeStorageStateIllegal(w, s)
+		return
+	}
+	if len(e.storageServers(s.UUID)) > 0 {
+		writeError(w, http.StatusConflict, "STORAGE_ATTACHED", fmt.Sprintf("The storage %s is attached to a server.", s.UUID))
+		return
+	}
+
+	i := &storageImport{storageUUID: s.UUID}
+	i.UUID = newUUID(uuidPrefixStorageImport)
+	i.Created = e.now().UTC().Truncate(time.Second)
+	i.Source = req.Source
+	i.SourceLocation = req.SourceLocation
+	e.imports[s.UUID] = i
+	s.State = upcloud.StorageStateMaintenance
+
+	if req.Source == upcloud.StorageImportSourceDirectUpload {
+		scheme := "http"
+		if r.TLS != nil {
+			scheme = "https"
+		}
+
+		i.State = upcloud.StorageImportStatePrepared
+		i.DirectUploadURL = fmt.Sprintf("%s://%s/uploader/session/%s", scheme, r.Host, i.UUID)
+	} else {
+		// The emulator doesn't download anything, the import completes as if the source were 1 GiB
+		i.State = upcloud.StorageImportStatePending
+		e.later(1, func() {
+			i.State = upcloud.StorageImportStateImporting
+		})
+		e.later(2, func() {
+			i.ReadBytes = 1 << 30
+			i.WrittenBytes = 1 << 30
+			e.completeStorageImport(i)
+		})
+	}
+
+	writeJSON(w, http.StatusCreated, storageImportObject(i))
+}
+
+// Marks the import completed and brings its storage back online
+func (e *Emulator) completeStorageImport(i *storageImport) {
+	if i.State == upcloud.StorageImportStateCancelled || i.State == upcloud.StorageImportStateFailed {
+		return
+	}
+
+	i.State = upcloud.StorageImportStateCompleted
+	i.Completed = e.now().UTC().Truncate(time.Second)
+
+	if s := e.storage(i.storageUUID); s != nil {
+		s.State = upcloud.StorageStateOnline
+	}
+}
+
+func (e *Emulator) getStorageImport(w http.ResponseWriter, r *http.Request, p params) {
+	s, ok := e.lookupStorage(w, p["uuid"])
+	if !ok {
+		return
+	}
+
+	i, ok := e.imports[s.UUID]
+	if !ok {
+		writeError(w, http.StatusNotFound, "STORAGE_IMPORT_NOT_FOUND",
+			fmt.Sprintf("The storage %s has no import.", s.UUID))
+		return
+	}
+
+	writeJSON(w, http.StatusOK, storageImportObject(i))
+}
+
+// Handles the upload of the content of a direct upload import. The upload isn't bound to the API version and
+// doesn't need authentication, like in the API.
+func (e *Emulator) handleUpload(w http.ResponseWriter, r *http.Request, uuid string) {
+	md5Hash := md5.New()
+	sha256Hash := sha256.New()
+	n, err := io.Copy(io.MultiWriter(md5Hash, sha256Hash), r.Body)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "UPLOAD_FAILED", fmt.Sprintf("Reading the upload failed: %s", err))
+		return
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.advance()
+
+	var i *storageImport
+	for _, candidate := range e.imports {
+		if candidate.UUID == uuid {
+			i = candidate
+		}
+	}
+
+	if i == nil || i.Source != upcloud.StorageImportSourceDirectUpload {
+		writeError(w, http.StatusNotFound, "UPLOAD_SESSION_NOT_FOUND", fmt.Sprintf("The upload session %s does not exist.", uuid))
+		return
+	}
+	if i.State != upcloud.StorageImportStatePrepared {
+		writeError(w, http.StatusConflict, "UPLOAD_SESSION_STATE_ILLEGAL",
+			fmt.Sprintf("The upload session %s is in state %s.", uuid, i.State))
+		return
+	}
+
+	i.ClientContentLength = int(r.ContentLength)
+	if r.ContentLength < 0 {
+		i.ClientContentLength = 0
+	}
+	i.ClientContentType = r.Header.Get("Content-Type")
+	i.ReadBytes = int(n)
+	i.WrittenBytes = int(n)
+	i.MD5Sum = hex.EncodeToString(md5Hash.Sum(nil))
+	i.SHA256Sum = hex.EncodeToString(sha256Hash.Sum(nil))
+	i.State = upcloud.StorageImportStateImporting
+	e.later(1, func() {
+		e.completeStorageImport(i)
+	})
+
+	writeJSON(w, http.StatusOK, object{
+		"written_bytes": i.WrittenBytes,
+		"md5sum":        i.MD5Sum,
+		"sha256sum":     i.SHA256Sum,
+	})
+}
+
+// Returns the lowest free address on the bus of the server, e.g. "virtio:1"
+func nextDeviceAddress(srv *server, bus string) string {
+	for n := 0; ; n++ {
+		address := fmt.Sprintf("%s:%d", bus, n)
+		if bus == "ide" || bus == "scsi" {
+			address = fmt.Sprintf("%s:0:%d", bus, n)
+		}
+
+		used := false
+		for _, device := range srv.StorageDevices {
+			used = used || device.Address == address
+		}
+
+		if !used {
+			return address
+		}
+	}
+}
+
+// Returns the address of a new device of the server. A bare bus name, e.g. "virtio", or no address at all picks the
+// next free address on the bus.
+func deviceAddress(srv *server, address, deviceType string) string {
+	switch address {
+	case "":
+		if deviceType == upcloud.StorageTypeCDROM {
+			return nextDeviceAddress(srv, "ide")
+		}
+		return nextDeviceAddress(srv, "virtio")
+	case "virtio", "ide", "scsi":
+		return nextDeviceAddress(srv, address)
+	}
+
+	return address
+}
+
+func (e *Emulator) attachStorage(w http.ResponseWriter, r *http.Request, p params) {
+	srv, ok := e.lookupServer(w, p["uuid"])
+	if !ok {
+		return
+	}
+
+	var body struct {
+		StorageDevice struct {
+			Type     string  `json:"type"`
+			Address  string  `json:"address"`
+			Storage  string  `json:"storage"`
+			BootDisk flexInt `json:"boot_disk"`
+		} `json:"storage_device"`
+	}
+	if !decodeBody(w, r, &body) {
+		return
+	}
+
+	req := body.StorageDevice
+	deviceType := firstNonEmpty(req.Type, upcloud.StorageTypeDisk)
+	if deviceType != upcloud.StorageTypeDisk && deviceType != upcloud.StorageTypeCDROM {
+		writeError(w, http.StatusBadRequest, "TYPE_INVALID", fmt.Sprintf("The device type %s is invalid.", deviceType))
+		return
+	}
+
+	if srv.State != upcloud.ServerStateStopped {
+		writeServerStateIllegal(w, srv)
+		return
+	}
+
+	device := upcloud.ServerStorageDevice{
+		Type:     deviceType,
+		BootDisk: int(req.BootDisk),
+	}
+
+	// A CD-ROM device can be attached without loading a CD-ROM into it
+	if req.Storage != "" || deviceType == upcloud.StorageTypeDisk {
+		s, ok := e.lookupStorage(w, req.Storage)
+		if !ok {
+			return
+		}
+		if !e.canAttach(w, srv, s, deviceType) {
+			return
+		}
+		device.UUID = s.UUID
+	}
+
+	device.Address = deviceAddress(srv, req.Address, deviceType)
+
+	for _, existing := range srv.StorageDevices {
+		if existing.Address == device.Address {
+			writeError(w, http.StatusConflict, "ADDRESS_IN_USE",
+				fmt.Sprintf("The address %s is already in use.", device.Address))
+			return
+		}
+	}
+
+	srv.StorageDevices = append(srv.StorageDevices, device)
+
+	writeJSON(w, http.StatusOK, e.serverDetails(srv))
+}
+
+// Returns whether the storage can be attached to the server as a device of the specified type. An error response is
+// written if it can't.
+func (e *Emulator) canAttach(w http.ResponseWriter, srv *server, s *storage, deviceType string) bool {
+	if deviceType == upcloud.StorageTypeCDROM && s.Type != upcloud.StorageTypeCDROM ||
+		deviceType == upcloud.StorageTypeDisk && s.Type != upcloud.StorageTypeNormal {
+		writeError(w, http.StatusBadRequest, "STORAGE_TYPE_ILLEGAL",
+			fmt.Sprintf("The storage %s of type %s can't be attached as a %s device.", s.UUID, s.Type, deviceType))
+		return false
+	}
+
+	if s.Access == upcloud.StorageAccessPrivate && s.Zone != srv.Zone {
+		writeError(w, http.StatusBadRequest, "STORAGE_ZONE_MISMATCH",
+			fmt.Sprintf("The storage %s is in a different zone than the server.", s.UUID))
+		return false
+	}
+
+	if s.Type == upcloud.StorageTypeNormal && len(e.storageServers(s.UUID)) > 0 {
+		writeError(w, http.StatusConflict, "STORAGE_ATTACHED", fmt.Sprintf("The storage %s is attached to a server.", s.UUID))
+		return false
+	}
+
+	if s.State != upcloud.StorageStateOnline {
+		writeStorageStateIllegal(w, s)
+		return false
+	}
+
+	return true
+}
+
+func (e *Emulator) detachStorage(w http.ResponseWriter, r *http.Request, p params) {
+	srv, ok := e.lookupServer(w, p["uuid"])
+	if !ok {
+		return
+	}
+
+	var body struct {
+		StorageDevice struct {
+			Address string `json:"address"`
+		} `json:"storage_device"`
+	}
+	if !decodeBody(w, r, &body) {
+		return
+	}
+
+	if srv.State != upcloud.ServerStateStopped {
+		writeServerStateIllegal(w, srv)
+		return
+	}
+
+	for i, device := range srv.StorageDevices {
+		if device.Address == body.StorageDevice.Address {
+			srv.StorageDevices = append(srv.StorageDevices[:i], srv.StorageDevices[i+1:]...)
+			writeJSON(w, http.StatusOK, e.serverDetails(srv))
+			return
+		}
+	}
+
+	writeError(w, http.StatusNotFound, "STORAGE_DEVICE_NOT_FOUND",
+		fmt.Sprintf("The server has no storage device at address %s.", body.StorageDevice.Address))
+}
+
+// Returns the CD-ROM device of the server, or nil if it has none
+func cdromDevice(srv *server) *upcloud.ServerStorageDevice {
+	for i := range srv.StorageDevices {
+		if srv.StorageDevices[i].Type == upcloud.StorageTypeCDROM {
+			return &srv.StorageDevices[i]
+		}
+	}
+
+	return nil
+}
+
+func (e *Emulator) loadCDROM(w http.ResponseWriter, r *http.Request, p params) {
+	srv, ok := e.lookupServer(w, p["uuid"])
+	if !ok {
+		return
+	}
+
+	var body struct {
+		StorageDevice struct {
+			Storage string `json:"storage"`
+		} `json:"storage_device"`
+	}
+	if !decodeBody(w, r, &body) {
+		return
+	}
+
+	s, ok := e.lookupStorage(w, body.StorageDevice.Storage)
+	if !ok {
+		return
+	}
+
+	device := cdromDevice(srv)
+	if device == nil {
+		writeError(w, http.StatusBadRequest, "CDROM_DEVICE_NOT_FOUND", "The server has no CD-ROM device.")
+		return
+	}
+
+	if !e.canAttach(w, srv, s, upcloud.StorageTypeCDROM) {
+		return
+	}
+
+	device.UUID = s.UUID
+
+	writeJSON(w, http.StatusOK, e.serverDetails(srv))
+}
+
+func (e *Emulator) ejectCDROM(w http.ResponseWriter, r *http.Request, p params) {
+	srv, ok := e.lookupServer(w, p["uuid"])
+	if !ok {
+		return
+	}
+
+	device := cdromDevice(srv)
+	if device == nil {
+		writeError(w, http.StatusBadRequest, "CDROM_DEVICE_NOT_FOUND", "The server has no CD-ROM device.")
+		return
+	}
+
+	if device.UUID == "" {
+		writeError(w, http.StatusBadRequest, "CDROM_EJECT_FAILED", "The CD-ROM device is empty.")
+		return
+	}
+
+	device.UUID = ""
+
+	writeJSON(w, http.StatusOK, e.serverDetails(srv))
+}
+
+// Returns the larger of the two integers
+func maxInt(a, b int) int {
+	if a > b {
+		return a
+	}
+
+	return b
+}
@@ -0,0 +1,251 @@
+package emulator
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/UpCloudLtd/upcloud-go-api/upcloud"
+	"github.com/UpCloudLtd/upcloud-go-api/upcloud/request"
+	"github.com/UpCloudLtd/upcloud-go-api/upcloud/service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Creates a 10 GiB storage in fi-hel1
+func createTestStorage(t *testing.T, svc *service.Service) *upcloud.StorageDetails {
+	storageDetails, err := svc.CreateStorage(&request.CreateStorageRequest{
+		Tier:  upcloud.StorageTierMaxIOPS,
+		Title: "emulator-test",
+		Size:  10,
+		Zone:  "fi-hel1",
+		BackupRule: &upcloud.BackupRule{
+			Interval:  upcloud.BackupRuleIntervalDaily,
+			Time:      "0430",
+			Retention: 30,
+		},
+	})
+	require.NoError(t, err)
+
+	return storageDetails
+}
+
+// TestGetStorages tests that the public templates and CD-ROMs are listed and can be filtered
+func TestGetStorages(t *testing.T) {
+	svc, _ := newTestService(t)
+	createTestStorage(t, svc)
+
+	storages, err := svc.GetStorages(&request.GetStoragesRequest{})
+	require.NoError(t, err)
+	assert.Len(t, storages.Storages, len(publicStorages)+1)
+
+	storages, err = svc.GetStorages(&request.GetStoragesRequest{Access: upcloud.StorageAccessPrivate})
+	require.NoError(t, err)
+	require.Len(t, storages.Storages, 1)
+	assert.Equal(t, "emulator-test", storages.Storages[0].Title)
+
+	storages, err = svc.GetStorages(&request.GetStoragesRequest{Type: upcloud.StorageTypeCDROM})
+	require.NoError(t, err)
+	require.NotEmpty(t, storages.Storages)
+	for _, s := range storages.Storages {
+		assert.Equal(t, upcloud.StorageTypeCDROM, s.Type)
+	}
+}
+
+// TestCreateModifyDeleteStorage tests the lifecycle of a storage
+func TestCreateModifyDeleteStorage(t *testing.T) {
+	svc, _ := newTestService(t)
+	storageDetails := createTestStorage(t, svc)
+	assert.Equal(t, upcloud.StorageStateOnline, storageDetails.State)
+	require.NotNil(t, storageDetails.BackupRule)
+	assert.Equal(t, 30, storageDetails.BackupRule.Retention)
+
+	storageDetails, err := svc.ModifyStorage(&request.ModifyStorageRequest{
+		UUID:  storageDetails.UUID,
+		Title: "renamed",
+		Size:  20,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "renamed", storageDetails.Title)
+	assert.Equal(t, 20, storageDetails.Size)
+
+	// Storages can't shrink
+	_, err = svc.ModifyStorage(&request.ModifyStorageRequest{UUID: storageDetails.UUID, Size: 10})
+	assert.True(t, errors.Is(err, upcloud.ErrBadRequest))
+
+	err = svc.DeleteStorage(&request.DeleteStorageRequest{UUID: storageDetails.UUID})
+	require.NoError(t, err)
+
+	_, err = svc.GetStorageDetails(&request.GetStorageDetailsRequest{UUID: storageDetails.UUID})
+	assert.True(t, errors.Is(err, upcloud.ErrNotFound))
+}
+
+// TestCloneTemplatizeStorage tests that clones and templates pass through maintenance while the source is busy
+func TestCloneTemplatizeStorage(t *testing.T) {
+	svc, _ := newTestService(t)
+	storageDetails := createTestStorage(t, svc)
+
+	clone, err := svc.CloneStorage(&request.CloneStorageRequest{
+		UUID:  storageDetails.UUID,
+		Zone:  "fi-hel1",
+		Title: "clone",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, upcloud.StorageStateMaintenance, clone.State)
+	assert.Equal(t, storageDetails.Size, clone.Size)
+
+	source, err := svc.GetStorageDetails(&request.GetStorageDetailsRequest{UUID: storageDetails.UUID})
+	require.NoError(t, err)
+	assert.Equal(t, upcloud.StorageStateOnline, source.State)
+
+	_, err = svc.WaitForStorageState(&request.WaitForStorageStateRequest{
+		UUID:         clone.UUID,
+		DesiredState: upcloud.StorageStateOnline,
+		Timeout:      time.Second,
+	})
+	require.NoError(t, err)
+
+	template, err := svc.TemplatizeStorage(&request.TemplatizeStorageRequest{UUID: clone.UUID, Title: "template"})
+	require.NoError(t, err)
+	assert.Equal(t, upcloud.StorageTypeTemplate, template.Type)
+
+	template, err = svc.WaitForStorageState(&request.WaitForStorageStateRequest{
+		UUID:         template.UUID,
+		DesiredState: upcloud.StorageStateOnline,
+		Timeout:      time.Second,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, upcloud.StorageAccessPrivate, template.Access)
+}
+
+// TestCreateRestoreBackup tests that a backup can be taken and restored
+func TestCreateRestoreBackup(t *testing.T) {
+	svc, _ := newTestService(t)
+	storageDetails := createTestStorage(t, svc)
+
+	backup, err := svc.CreateBackup(&request.CreateBackupRequest{UUID: storageDetails.UUID, Title: "backup"})
+	require.NoError(t, err)
+	assert.Equal(t, upcloud.StorageTypeBackup, backup.Type)
+	assert.Equal(t, storageDetails.UUID, backup.Origin)
+
+	_, err = svc.WaitForBackupCreation(&request.WaitForBackupCreationRequest{UUID: storageDetails.UUID, Timeout: time.Second})
+	require.NoError(t, err)
+
+	storageDetails, err = svc.GetStorageDetails(&request.GetStorageDetailsRequest{UUID: storageDetails.UUID})
+	require.NoError(t, err)
+	assert.Equal(t, upcloud.BackupUUIDSlice{backup.UUID}, storageDetails.BackupUUIDs)
+
+	err = svc.RestoreBackup(&request.RestoreBackupRequest{UUID: backup.UUID})
+	require.NoError(t, err)
+
+	_, err = svc.WaitForStorageState(&request.WaitForStorageStateRequest{
+		UUID:         storageDetails.UUID,
+		DesiredState: upcloud.StorageStateOnline,
+		Timeout:      time.Second,
+	})
+	require.NoError(t, err)
+}
+
+// TestAttachDetachStorage tests that storages and CD-ROMs can be attached to a stopped server
+func TestAttachDetachStorage(t *testing.T) {
+	svc, _ := newTestService(t)
+	serverDetails := createTestServer(t, svc, "fi-hel1")
+	storageDetails := createTestStorage(t, svc)
+
+	// Attaching requires the server to be stopped
+	_, err := svc.AttachStorage(&request.AttachStorageRequest{
+		ServerUUID:  serverDetails.UUID,
+		StorageUUID: storageDetails.UUID,
+		Type:        upcloud.StorageTypeDisk,
+	})
+	assert.True(t, errors.Is(err, upcloud.ErrConflict))
+
+	stopTestServer(t, svc, serverDetails.UUID)
+
+	serverDetails, err = svc.AttachStorage(&request.AttachStorageRequest{
+		ServerUUID:  serverDetails.UUID,
+		StorageUUID: storageDetails.UUID,
+		Type:        upcloud.StorageTypeDisk,
+		Address:     "virtio",
+	})
+	require.NoError(t, err)
+	require.Len(t, serverDetails.StorageDevices, 2)
+	assert.Equal(t, "virtio:1", serverDetails.StorageDevices[1].Address)
+
+	storageDetails, err = svc.GetStorageDetails(&request.GetStorageDetailsRequest{UUID: storageDetails.UUID})
+	require.NoError(t, err)
+	assert.Equal(t, upcloud.ServerUUIDSlice{serverDetails.UUID}, storageDetails.ServerUUIDs)
+
+	_, err = svc.LoadCDROM(&request.LoadCDROMRequest{
+		ServerUUID:  serverDetails.UUID,
+		StorageUUID: "01000000-0000-4000-8000-000030060101",
+	})
+	assert.True(t, errors.Is(err, upcloud.ErrNotFound), "a server without a CD-ROM device can't load one")
+
+	serverDetails, err = svc.AttachStorage(&request.AttachStorageRequest{
+		ServerUUID: serverDetails.UUID,
+		Type:       upcloud.StorageTypeCDROM,
+		Address:    "ide",
+	})
+	require.NoError(t, err)
+
+	serverDetails, err = svc.LoadCDROM(&request.LoadCDROMRequest{
+		ServerUUID:  serverDetails.UUID,
+		StorageUUID: "01000000-0000-4000-8000-000030060101",
+	})
+	require.NoError(t, err)
+	require.Len(t, serverDetails.StorageDevices, 3)
+	assert.Equal(t, "01000000-0000-4000-8000-000030060101", serverDetails.StorageDevices[2].UUID)
+
+	serverDetails, err = svc.EjectCDROM(&request.EjectCDROMRequest{ServerUUID: serverDetails.UUID})
+	require.NoError(t, err)
+	assert.Empty(t, serverDetails.StorageDevices[2].UUID)
+
+	serverDetails, err = svc.DetachStorage(&request.DetachStorageRequest{ServerUUID: serverDetails.UUID, Address: "virtio:1"})
+	require.NoError(t, err)
+	assert.Len(t, serverDetails.StorageDevices, 2)
+}
+
+// TestStorageImport tests HTTP imports and direct uploads
+func TestStorageImport(t *testing.T) {
+	svc, _ := newTestService(t)
+
+	storageDetails := createTestStorage(t, svc)
+	storageImport, err := svc.CreateStorageImport(&request.CreateStorageImportRequest{
+		StorageUUID:    storageDetails.UUID,
+		Source:         upcloud.StorageImportSourceHTTPImport,
+		SourceLocation: "https://example.com/image.img",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, upcloud.StorageImportStatePending, storageImport.State)
+
+	storageImport, err = svc.WaitForStorageImportCompletion(&request.WaitForStorageImportCompletionRequest{
+		StorageUUID: storageDetails.UUID,
+		Timeout:     time.Second,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, upcloud.StorageImportStateCompleted, storageImport.State)
+
+	content := []byte("emulated disk image")
+	sum := sha256.Sum256(content)
+
+	storageDetails = createTestStorage(t, svc)
+	storageImport, err = svc.CreateStorageImport(&request.CreateStorageImportRequest{
+		StorageUUID:    storageDetails.UUID,
+		Source:         upcloud.StorageImportSourceDirectUpload,
+		SourceLocation: bytes.NewReader(content),
+	})
+	require.NoError(t, err)
+	assert.Equal(t, len(content), storageImport.WrittenBytes)
+	assert.Equal(t, hex.EncodeToString(sum[:]), storageImport.SHA256Sum)
+
+	storageImport, err = svc.WaitForStorageImportCompletion(&request.WaitForStorageImportCompletionRequest{
+		StorageUUID: storageDetails.UUID,
+		Timeout:     time.Second,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, upcloud.StorageImportStateCompleted, storageImport.State)
+}
@@ -0,0 +1,142 @@
+package emulator
+
+import (
+	"fmt"
+	"net/http"
+	"regexp"
+
+	"github.com/UpCloudLtd/upcloud-go-api/upcloud"
+)
+
+// Registers the handlers of the tag endpoints
+func (e *Emulator) addTagRoutes() {
+	e.handle(http.MethodGet, "/tag", e.getTags)
+	e.handle(http.MethodPost, "/tag", e.createTag)
+	e.handle(http.MethodPut, "/tag/{name}", e.modifyTag)
+	e.handle(http.MethodDelete, "/tag/{name}", e.deleteTag)
+}
+
+var tagNamePattern = regexp.MustCompile(`^[a-zA-Z0-9_-]{1,32}$`)
+
+// Returns whether the tag name is valid
+func validTagName(name string) bool {
+	return tagNamePattern.MatchString(name)
+}
+
+// Returns the tag with the specified name, or nil if there's no such tag
+func (e *Emulator) tag(name string) *upcloud.Tag {
+	for _, tag := range e.tags {
+		if tag.Name == name {
+			return tag
+		}
+	}
+
+	return nil
+}
+
+// Returns the tag the way it appears in responses
+func tagObject(tag *upcloud.Tag) object {
+	return object{
+		"name":        tag.Name,
+		"description": tag.Description,
+		"servers":     object{"server": append([]string{}, tag.Servers...)},
+	}
+}
+
+// Checks that the servers of a tag exist. An error response is written if one doesn't.
+func (e *Emulator) validateTagServers(w http.ResponseWriter, servers []string) bool {
+	for _, uuid := range servers {
+		if _, ok := e.lookupServer(w, uuid); !ok {
+			return false
+		}
+	}
+
+	return true
+}
+
+func (e *Emulator) getTags(w http.ResponseWriter, r *http.Request, p params) {
+	items := []object{}
+	for _, tag := range e.tags {
+		items = append(items, tagObject(tag))
+	}
+
+	writeJSON(w, http.StatusOK, object{"tags": list("tag", items)})
+}
+
+func (e *Emulator) createTag(w http.ResponseWriter, r *http.Request, p params) {
+	// upcloud.Tag decodes the tag from the {"tag": {...}} wrapper
+	var req upcloud.Tag
+	if !decodeBody(w, r, &req) {
+		return
+	}
+
+	if !validTagName(req.Name) {
+		writeError(w, http.StatusBadRequest, "NAME_INVALID", fmt.Sprintf("The tag name %q is invalid.", req.Name))
+		return
+	}
+	if e.tag(req.Name) != nil {
+		writeError(w, http.StatusConflict, "TAG_EXISTS", fmt.Sprintf("The tag %s already exists.", req.Name))
+		return
+	}
+	if !e.validateTagServers(w, req.Servers) {
+		return
+	}
+
+	tag := &upcloud.Tag{
+		Name:        req.Name,
+		Description: req.Description,
+		Servers:     append(upcloud.TagServerSlice{}, req.Servers...),
+	}
+	e.tags = append(e.tags, tag)
+
+	writeJSON(w, http.StatusOK, object{"tag": tagObject(tag)})
+}
+
+func (e *Emulator) modifyTag(w http.ResponseWriter, r *http.Request, p params) {
+	tag := e.tag(p["name"])
+	if tag == nil {
+		writeNotFound(w, "tag", p["name"])
+		return
+	}
+
+	var req upcloud.Tag
+	if !decodeBody(w, r, &req) {
+		return
+	}
+
+	if req.Name != "" && req.Name != tag.Name {
+		if !validTagName(req.Name) {
+			writeError(w, http.StatusBadRequest, "NAME_INVALID", fmt.Sprintf("The tag name %q is invalid.", req.Name))
+			return
+		}
+		if e.tag(req.Name) != nil {
+			writeError(w, http.StatusConflict, "TAG_EXISTS", fmt.Sprintf("The tag %s already exists.", req.Name))
+			return
+		}
+	}
+
+	// The servers are always sent, an empty list leaves them unchanged
+	if len(req.Servers) > 0 {
+		if !e.validateTagServers(w, req.Servers) {
+			return
+		}
+		tag.Servers = append(upcloud.TagServerSlice{}, req.Servers...)
+	}
+
+	tag.Name = firstNonEmpty(req.Name, tag.Name)
+	tag.Description = firstNonEmpty(req.Description, tag.Description)
+
+	writeJSON(w, http.StatusOK, object{"tag": tagObject(tag)})
+}
+
+func (e *Emulator) deleteTag(w http.ResponseWriter, r *http.Request, p params) {
+	for i, tag := range e.tags {
+		if tag.Name == p["name"] {
+			e.tags = append(e.tags[:i], e.tags[i+1:]...)
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+	}
+
+	writeNotFound(w, "tag", p["name"])
+}
@@ -0,0 +1,64 @@
+package emulator
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/UpCloudLtd/upcloud-go-api/upcloud"
+	"github.com/UpCloudLtd/upcloud-go-api/upcloud/request"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestCreateModifyDeleteTag tests the lifecycle of a tag
+func TestCreateModifyDeleteTag(t *testing.T) {
+	svc, _ := newTestService(t)
+	serverDetails := createTestServer(t, svc, "fi-hel1")
+
+	tag, err := svc.CreateTag(&request.CreateTagRequest{
+		Tag: upcloud.Tag{
+			Name:        "web",
+			Description: "Web servers",
+			Servers:     []string{serverDetails.UUID},
+		},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, upcloud.TagServerSlice{serverDetails.UUID}, tag.Servers)
+
+	_, err = svc.CreateTag(&request.CreateTagRequest{Tag: upcloud.Tag{Name: "web"}})
+	assert.True(t, errors.Is(err, upcloud.ErrConflict))
+
+	tag, err = svc.ModifyTag(&request.ModifyTagRequest{Name: "web", Tag: upcloud.Tag{Name: "frontend"}})
+	require.NoError(t, err)
+	assert.Equal(t, "frontend", tag.Name)
+	assert.Equal(t, "Web servers", tag.Description)
+	assert.Equal(t, upcloud.TagServerSlice{serverDetails.UUID}, tag.Servers)
+
+	serverDetails, err = svc.GetServerDetails(&request.GetServerDetailsRequest{UUID: serverDetails.UUID})
+	require.NoError(t, err)
+	assert.Equal(t, upcloud.ServerTagSlice{"frontend"}, serverDetails.Tags)
+
+	err = svc.DeleteTag(&request.DeleteTagRequest{Name: "frontend"})
+	require.NoError(t, err)
+
+	err = svc.DeleteTag(&request.DeleteTagRequest{Name: "frontend"})
+	assert.True(t, errors.Is(err, upcloud.ErrNotFound))
+}
+
+// TestGetTags tests that tags are listed
+func TestGetTags(t *testing.T) {
+	svc, _ := newTestService(t)
+	// upcloud.Tags has no JSON tags, so its schema can't be checked
+	svc.SetSchemaCheck(nil)
+
+	for _, name := range []string{"web", "db"} {
+		_, err := svc.CreateTag(&request.CreateTagRequest{Tag: upcloud.Tag{Name: name}})
+		require.NoError(t, err)
+	}
+
+	tags, err := svc.GetTags()
+	require.NoError(t, err)
+	require.Len(t, tags.Tags, 2)
+	assert.Equal(t, "web", tags.Tags[0].Name)
+	assert.Equal(t, "db", tags.Tags[1].Name)
+}
@@ -2,13 +2,47 @@ package upcloud
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
+	"net/http"
+	"strings"
+)
+
+// Sentinel errors that an Error can be matched against with errors.Is. An Error matches at most one of them,
+// based on its error code and HTTP status.
+var (
+	// ErrBadRequest means that the API rejected the request as invalid
+	ErrBadRequest = errors.New("bad request")
+	// ErrUnauthorized means that the credentials were missing or invalid
+	ErrUnauthorized = errors.New("unauthorized")
+	// ErrForbidden means that the account isn't allowed to perform the request
+	ErrForbidden = errors.New("forbidden")
+	// ErrNotFound means that the requested resource doesn't exist
+	ErrNotFound = errors.New("not found")
+	// ErrConflict means that the resource is busy or in a state that doesn't allow the request, e.g. a server that
+	// is in maintenance or a storage that is attached to a running server
+	ErrConflict = errors.New("conflict")
+	// ErrRateLimited means that the account has sent too many requests
+	ErrRateLimited = errors.New("rate limited")
+	// ErrQuotaExceeded means that the request would exceed the resource limits or credits of the account
+	ErrQuotaExceeded = errors.New("quota exceeded")
+	// ErrServerError means that the API failed to handle the request
+	ErrServerError = errors.New("server error")
 )
 
 // Error represents an error
 type Error struct {
 	ErrorCode    string `json:"error_code"`
 	ErrorMessage string `json:"error_message"`
+
+	// The fields below describe the request and response the error originates from. They aren't part of the
+	// error returned by the API and are empty if the error wasn't caused by an API response.
+	Status       int    `json:"-"`
+	Method       string `json:"-"`
+	URL          string `json:"-"`
+	ResponseBody []byte `json:"-"`
+	// Err is the underlying error, if any
+	Err error `json:"-"`
 }
 
 // UnmarshalJSON is a custom unmarshaller that deals with
@@ -30,5 +64,67 @@ func (e *Error) UnmarshalJSON(b []byte) error {
 
 // Error implements the Error interface
 func (e *Error) Error() string {
+	if e.ErrorMessage == "" && e.ErrorCode == "" && e.Status != 0 {
+		return fmt.Sprintf("%s %s: unexpected response %d %s: %s",
+			e.Method, e.URL, e.Status, http.StatusText(e.Status), strings.TrimSpace(string(e.ResponseBody)))
+	}
+
 	return fmt.Sprintf("%s (%s)", e.ErrorMessage, e.ErrorCode)
 }
+
+// Unwrap returns the underlying error
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// Is reports whether the error matches the specified sentinel error, e.g. ErrNotFound
+func (e *Error) Is(target error) bool {
+	kind := e.kind()
+
+	return kind != nil && kind == target
+}
+
+// Returns the sentinel error matching the error code or, if the code is unknown, the HTTP status. Rate limited
+// requests are recognised by the status alone, as their codes may look like those of exceeded quotas.
+func (e *Error) kind() error {
+	code := e.ErrorCode
+
+	if e.Status == http.StatusTooManyRequests {
+		return ErrRateLimited
+	}
+
+	switch {
+	case code == "AUTHENTICATION_FAILED":
+		return ErrUnauthorized
+	case strings.HasSuffix(code, "_NOT_FOUND"):
+		return ErrNotFound
+	case code == "INSUFFICIENT_CREDITS",
+		strings.HasSuffix(code, "_LIMIT_REACHED"),
+		strings.HasSuffix(code, "_LIMIT_EXCEEDED"):
+		return ErrQuotaExceeded
+	case code == "RESOURCE_BUSY",
+		strings.HasSuffix(code, "_STATE_ILLEGAL"),
+		strings.HasSuffix(code, "_IN_USE"),
+		strings.HasSuffix(code, "_BUSY"):
+		return ErrConflict
+	}
+
+	switch {
+	case e.Status == http.StatusBadRequest:
+		return ErrBadRequest
+	case e.Status == http.StatusUnauthorized:
+		return ErrUnauthorized
+	case e.Status == http.StatusPaymentRequired:
+		return ErrQuotaExceeded
+	case e.Status == http.StatusForbidden:
+		return ErrForbidden
+	case e.Status == http.StatusNotFound:
+		return ErrNotFound
+	case e.Status == http.StatusConflict:
+		return ErrConflict
+	case e.Status >= 500:
+		return ErrServerError
+	}
+
+	return nil
+}
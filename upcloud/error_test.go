@@ -2,6 +2,8 @@ package upcloud
 
 import (
 	"encoding/json"
+	"errors"
+	"fmt"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -24,3 +26,68 @@ func TestError(t *testing.T) {
 	assert.Equal(t, "The server 00af0f73-7082-4283-b925-811d1585774b does not exist.", e.ErrorMessage)
 	assert.Equal(t, "SERVER_NOT_FOUND", e.ErrorCode)
 }
+
+// TestErrorIs tests that errors match the sentinel errors by error code and HTTP status
+func TestErrorIs(t *testing.T) {
+	sentinels := []error{
+		ErrBadRequest,
+		ErrUnauthorized,
+		ErrForbidden,
+		ErrNotFound,
+		ErrConflict,
+		ErrRateLimited,
+		ErrQuotaExceeded,
+		ErrServerError,
+	}
+
+	testData := []struct {
+		code     string
+		status   int
+		expected error
+	}{
+		{code: "SERVER_NOT_FOUND", status: 404, expected: ErrNotFound},
+		{code: "TAG_NOT_FOUND", status: 400, expected: ErrNotFound},
+		{code: "", status: 404, expected: ErrNotFound},
+		{code: "AUTHENTICATION_FAILED", status: 401, expected: ErrUnauthorized},
+		{code: "", status: 403, expected: ErrForbidden},
+		{code: "SERVER_INVALID", status: 400, expected: ErrBadRequest},
+		{code: "SERVER_STATE_ILLEGAL", status: 409, expected: ErrConflict},
+		{code: "STORAGE_IN_USE", status: 400, expected: ErrConflict},
+		{code: "", status: 409, expected: ErrConflict},
+		{code: "", status: 429, expected: ErrRateLimited},
+		{code: "REQUEST_LIMIT_EXCEEDED", status: 429, expected: ErrRateLimited},
+		{code: "SERVER_LIMIT_EXCEEDED", status: 409, expected: ErrQuotaExceeded},
+		{code: "INSUFFICIENT_CREDITS", status: 402, expected: ErrQuotaExceeded},
+		{code: "STORAGE_LIMIT_REACHED", status: 409, expected: ErrQuotaExceeded},
+		{code: "", status: 503, expected: ErrServerError},
+		{code: "SOMETHING_ELSE", status: 0, expected: nil},
+	}
+
+	for _, data := range testData {
+		e := &Error{ErrorCode: data.code, Status: data.status}
+		var wrapped error = fmt.Errorf("wrapped: %w", e)
+
+		for _, sentinel := range sentinels {
+			assert.Equal(t, sentinel == data.expected, errors.Is(wrapped, sentinel), "%s %d %s", data.code, data.status, sentinel)
+		}
+	}
+}
+
+// TestErrorWithoutJSON tests the error message of an error without an API error body
+func TestErrorWithoutJSON(t *testing.T) {
+	cause := errors.New("cause")
+	e := &Error{
+		Status:       502,
+		Method:       "GET",
+		URL:          "https://api.upcloud.com/1.3/server",
+		ResponseBody: []byte("<html>Bad Gateway</html>\n"),
+		Err:          cause,
+	}
+
+	assert.Equal(t, "GET https://api.upcloud.com/1.3/server: unexpected response 502 Bad Gateway: <html>Bad Gateway</html>", e.Error())
+	assert.True(t, errors.Is(e, cause))
+	assert.True(t, errors.Is(e, ErrServerError))
+
+	e = &Error{ErrorCode: "SERVER_NOT_FOUND", ErrorMessage: "The server does not exist.", Status: 404}
+	assert.Equal(t, "The server does not exist. (SERVER_NOT_FOUND)", e.Error())
+}
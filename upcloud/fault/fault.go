@@ -0,0 +1,202 @@
+// Package fault provides an http.RoundTripper that injects faults into the traffic between a client.Client and the
+// API, for testing that code built on the library survives API misbehaviour.
+//
+// Faults are injected by rules that match requests by their method and path, e.g. POST /server/{uuid}/start. A rule
+// either injects its fault at random with a probability or follows a script of faults, one per matching request:
+//
+//	transport := fault.NewTransport(cleanhttp.DefaultTransport(),
+//		fault.WithRule(fault.Rule{
+//			Method: http.MethodPost,
+//			Path:   "/server/{uuid}/start",
+//			Script: []fault.Fault{fault.Status(http.StatusServiceUnavailable), fault.Reset(), {}},
+//		}),
+//		fault.WithRule(fault.Rule{Fault: fault.Latency(2 * time.Second), Probability: 0.1}),
+//	)
+//	c := client.NewWithHTTPClient(username, password, &http.Client{Transport: transport})
+package fault
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+)
+
+// Fault describes the misbehaviour injected into a request. The zero value passes the request through unchanged.
+// Latency can be combined with any other fault.
+type Fault struct {
+	// Latency delays the request by the specified time
+	Latency time.Duration
+	// StatusCode makes the request fail with the specified status, e.g. 503 or 429, without forwarding it. The
+	// response has an error body in the format of the API.
+	StatusCode int
+	// RetryAfter sets the Retry-After header of the response of a StatusCode fault
+	RetryAfter time.Duration
+	// Reset makes the request fail with a connection reset without forwarding it
+	Reset bool
+	// ResetAfterForward makes the request fail with a connection reset after it has been forwarded, so the API acts
+	// on the request but the response is lost
+	ResetAfterForward bool
+	// TruncateBody cuts the response body in half. Reading past the cut fails with io.ErrUnexpectedEOF.
+	TruncateBody bool
+	// MalformedJSON replaces the response body with one that can be read but isn't valid JSON
+	MalformedJSON bool
+}
+
+// Latency returns a fault delaying the request by the specified time
+func Latency(latency time.Duration) Fault {
+	return Fault{Latency: latency}
+}
+
+// Status returns a fault failing the request with the specified HTTP status
+func Status(statusCode int) Fault {
+	return Fault{StatusCode: statusCode}
+}
+
+// TooManyRequests returns a fault failing the request with 429 Too Many Requests and the specified Retry-After
+// delay. A zero delay omits the header.
+func TooManyRequests(retryAfter time.Duration) Fault {
+	return Fault{StatusCode: http.StatusTooManyRequests, RetryAfter: retryAfter}
+}
+
+// Reset returns a fault failing the request with a connection reset before it's sent to the API
+func Reset() Fault {
+	return Fault{Reset: true}
+}
+
+// ResetAfterForward returns a fault failing the request with a connection reset after the API has handled it
+func ResetAfterForward() Fault {
+	return Fault{ResetAfterForward: true}
+}
+
+// TruncateBody returns a fault cutting the response body in half
+func TruncateBody() Fault {
+	return Fault{TruncateBody: true}
+}
+
+// MalformedJSON returns a fault replacing the response body with invalid JSON
+func MalformedJSON() Fault {
+	return Fault{MalformedJSON: true}
+}
+
+// String implements the Stringer interface
+func (f Fault) String() string {
+	var parts []string
+	if f.Latency > 0 {
+		parts = append(parts, fmt.Sprintf("latency %s", f.Latency))
+	}
+	if f.StatusCode != 0 {
+		parts = append(parts, fmt.Sprintf("status %d", f.StatusCode))
+	}
+	if f.Reset {
+		parts = append(parts, "reset")
+	}
+	if f.ResetAfterForward {
+		parts = append(parts, "reset after forward")
+	}
+	if f.TruncateBody {
+		parts = append(parts, "truncated body")
+	}
+	if f.MalformedJSON {
+		parts = append(parts, "malformed JSON")
+	}
+	if len(parts) == 0 {
+		return "none"
+	}
+
+	return strings.Join(parts, ", ")
+}
+
+// Returns the error of a reset connection, the way the net package reports it
+func connectionReset(req *http.Request) error {
+	return &net.OpError{
+		Op:   "read",
+		Net:  "tcp",
+		Addr: fakeAddr(req.URL.Host),
+		Err:  os.NewSyscallError("read", syscall.ECONNRESET),
+	}
+}
+
+// fakeAddr is the address of the API in injected network errors
+type fakeAddr string
+
+func (a fakeAddr) Network() string { return "tcp" }
+func (a fakeAddr) String() string  { return string(a) }
+
+// Returns a response with the specified status and an error body in the format of the API
+func errorResponse(req *http.Request, f Fault) *http.Response {
+	code := strings.ToUpper(strings.ReplaceAll(http.StatusText(f.StatusCode), " ", "_"))
+	body, _ := json.Marshal(map[string]interface{}{
+		"error": map[string]string{
+			"error_code":    code,
+			"error_message": fmt.Sprintf("Injected fault: %s", f),
+		},
+	})
+
+	header := http.Header{"Content-Type": {"application/json; charset=UTF-8"}}
+	if f.RetryAfter > 0 {
+		header.Set("Retry-After", strconv.Itoa(int((f.RetryAfter+time.Second-1)/time.Second)))
+	}
+
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", f.StatusCode, http.StatusText(f.StatusCode)),
+		StatusCode:    f.StatusCode,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          ioutil.NopCloser(bytes.NewReader(body)),
+		ContentLength: int64(len(body)),
+		Request:       req,
+	}
+}
+
+// truncatedBody returns part of a response body and then fails the way a dropped connection does
+type truncatedBody struct {
+	r io.Reader
+}
+
+// Read implements the io.Reader interface
+func (b *truncatedBody) Read(p []byte) (int, error) {
+	n, err := b.r.Read(p)
+	if err == io.EOF {
+		err = io.ErrUnexpectedEOF
+	}
+	return n, err
+}
+
+// Close implements the io.Closer interface
+func (b *truncatedBody) Close() error {
+	return nil
+}
+
+// Replaces the body of the response according to the fault. The original body is read and closed.
+func rewriteBody(resp *http.Response, f Fault) error {
+	body, err := ioutil.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case f.TruncateBody:
+		resp.Body = &truncatedBody{r: bytes.NewReader(body[:len(body)/2])}
+		// The header keeps announcing the full length, like a response cut short by the network
+		return nil
+	case f.MalformedJSON:
+		body = append(body[:len(body)/2:len(body)/2], "<html>"...)
+	}
+
+	resp.Body = ioutil.NopCloser(bytes.NewReader(body))
+	resp.ContentLength = int64(len(body))
+	resp.Header.Set("Content-Length", strconv.Itoa(len(body)))
+	return nil
+}
@@ -0,0 +1,46 @@
+package fault
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestFaultString tests the descriptions of faults
+func TestFaultString(t *testing.T) {
+	assert.Equal(t, "none", Fault{}.String())
+	assert.Equal(t, "status 429", TooManyRequests(time.Second).String())
+	assert.Equal(t, "latency 2s, reset", Fault{Latency: 2 * time.Second, Reset: true}.String())
+	assert.Equal(t, "truncated body", TruncateBody().String())
+}
+
+// TestErrorResponse tests that injected error responses look like the ones of the API
+func TestErrorResponse(t *testing.T) {
+	req, err := http.NewRequest(http.MethodGet, "https://api.upcloud.com/1.3/zone", nil)
+	require.NoError(t, err)
+
+	resp := errorResponse(req, TooManyRequests(1500*time.Millisecond))
+	assert.Equal(t, http.StatusTooManyRequests, resp.StatusCode)
+	assert.Equal(t, "2", resp.Header.Get("Retry-After"))
+
+	body, err := ioutil.ReadAll(resp.Body)
+	require.NoError(t, err)
+
+	var apiError struct {
+		Error struct {
+			ErrorCode    string `json:"error_code"`
+			ErrorMessage string `json:"error_message"`
+		} `json:"error"`
+	}
+	require.NoError(t, json.Unmarshal(body, &apiError))
+	assert.Equal(t, "TOO_MANY_REQUESTS", apiError.Error.ErrorCode)
+	assert.Equal(t, "Injected fault: status 429", apiError.Error.ErrorMessage)
+
+	resp = errorResponse(req, Status(http.StatusServiceUnavailable))
+	assert.Empty(t, resp.Header.Get("Retry-After"))
+}
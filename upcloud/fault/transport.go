@@ -0,0 +1,228 @@
+package fault
+
+import (
+	"math/rand"
+	"net/http"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Rule selects the requests a fault is injected into
+type Rule struct {
+	// Method is the HTTP method of the requests, empty for any method
+	Method string
+	// Path is the path of the requests relative to the API version, e.g. "/server/{uuid}/start". A segment in
+	// braces matches any single segment and a final "*" matches any remaining segments. An empty path matches any
+	// request.
+	Path string
+	// Fault is injected into the matching requests with the probability below
+	Fault Fault
+	// Probability is the chance of injecting Fault into a matching request, between 0 and 1. Zero injects it into
+	// every matching request.
+	Probability float64
+	// Script, when set, is followed instead of Fault and Probability: the nth matching request gets the nth fault
+	// of the script. A zero Fault passes the request through. Once the script is exhausted the rule no longer
+	// matches.
+	Script []Fault
+}
+
+// Injection is a fault injected into a request
+type Injection struct {
+	Method string
+	URL    string
+	Fault  Fault
+}
+
+// Option configures a Transport
+type Option func(*Transport)
+
+// WithRule adds a rule to the transport. A request is handled by the first rule that matches it.
+func WithRule(rule Rule) Option {
+	return func(t *Transport) {
+		t.rules = append(t.rules, &rule)
+	}
+}
+
+// WithSeed seeds the random number generator deciding whether faults with a probability are injected, so that the
+// same faults are injected into the same sequence of requests on every run
+func WithSeed(seed int64) Option {
+	return func(t *Transport) {
+		t.rand = rand.New(rand.NewSource(seed))
+	}
+}
+
+// Transport is an http.RoundTripper that injects faults into the requests matching its rules and forwards the
+// requests to another RoundTripper. It's safe for concurrent use.
+type Transport struct {
+	next  http.RoundTripper
+	rules []*Rule
+
+	mu         sync.Mutex
+	rand       *rand.Rand
+	steps      map[*Rule]int
+	injections []Injection
+}
+
+// NewTransport returns a transport forwarding requests to next, or http.DefaultTransport if next is nil
+func NewTransport(next http.RoundTripper, opts ...Option) *Transport {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+
+	t := Transport{
+		next:  next,
+		steps: make(map[*Rule]int),
+	}
+	for _, opt := range opts {
+		opt(&t)
+	}
+	if t.rand == nil {
+		t.rand = rand.New(rand.NewSource(time.Now().UnixNano()))
+	}
+
+	return &t
+}
+
+// Injections returns the faults injected so far, in the order they were injected
+func (t *Transport) Injections() []Injection {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	return append([]Injection(nil), t.injections...)
+}
+
+// Reset forgets the injected faults and restarts the scripts
+func (t *Transport) Reset() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.injections = nil
+	t.steps = make(map[*Rule]int)
+}
+
+// RoundTrip implements the http.RoundTripper interface
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	f := t.fault(req)
+
+	if f.Latency > 0 {
+		timer := time.NewTimer(f.Latency)
+		select {
+		case <-timer.C:
+		case <-req.Context().Done():
+			timer.Stop()
+			closeBody(req)
+			return nil, req.Context().Err()
+		}
+	}
+
+	switch {
+	case f.Reset:
+		closeBody(req)
+		return nil, connectionReset(req)
+	case f.StatusCode != 0:
+		closeBody(req)
+		return errorResponse(req, f), nil
+	}
+
+	resp, err := t.next.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case f.ResetAfterForward:
+		resp.Body.Close()
+		return nil, connectionReset(req)
+	case f.TruncateBody || f.MalformedJSON:
+		if resp.Header == nil {
+			resp.Header = http.Header{}
+		}
+		if err := rewriteBody(resp, f); err != nil {
+			return nil, err
+		}
+	}
+
+	return resp, nil
+}
+
+// Closes the body of a request that isn't forwarded, as a RoundTripper must
+func closeBody(req *http.Request) {
+	if req.Body != nil {
+		req.Body.Close()
+	}
+}
+
+// Returns the fault to inject into the request and records it
+func (t *Transport) fault(req *http.Request) Fault {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	path := apiPath(req.URL.Path)
+	for _, rule := range t.rules {
+		if !rule.matches(req.Method, path) {
+			continue
+		}
+
+		var f Fault
+		if rule.Script != nil {
+			step := t.steps[rule]
+			if step >= len(rule.Script) {
+				continue
+			}
+			t.steps[rule] = step + 1
+			f = rule.Script[step]
+		} else if rule.Probability == 0 || t.rand.Float64() < rule.Probability {
+			f = rule.Fault
+		}
+
+		if f != (Fault{}) {
+			t.injections = append(t.injections, Injection{Method: req.Method, URL: req.URL.String(), Fault: f})
+		}
+		return f
+	}
+
+	return Fault{}
+}
+
+// Matches the API version at the start of a request path, e.g. "/1.3"
+var apiVersionPath = regexp.MustCompile(`^/[0-9]+\.[0-9]+(/|$)`)
+
+// Returns the path of a request relative to the API version
+func apiPath(path string) string {
+	if loc := apiVersionPath.FindStringIndex(path); loc != nil {
+		return "/" + path[loc[1]:]
+	}
+
+	return path
+}
+
+// Returns whether the rule matches a request
+func (r *Rule) matches(method, path string) bool {
+	if r.Method != "" && !strings.EqualFold(r.Method, method) {
+		return false
+	}
+	if r.Path == "" {
+		return true
+	}
+
+	pattern := strings.Split(strings.Trim(r.Path, "/"), "/")
+	segments := strings.Split(strings.Trim(path, "/"), "/")
+	for i, p := range pattern {
+		if p == "*" && i == len(pattern)-1 {
+			return true
+		}
+		if i >= len(segments) {
+			return false
+		}
+		if strings.HasPrefix(p, "{") && strings.HasSuffix(p, "}") {
+			continue
+		}
+		if p != segments[i] {
+			return false
+		}
+	}
+
+	return len(pattern) == len(segments)
+}
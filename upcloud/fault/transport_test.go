@@ -0,0 +1,219 @@
+package fault
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/UpCloudLtd/upcloud-go-api/upcloud"
+	"github.com/UpCloudLtd/upcloud-go-api/upcloud/client"
+	"github.com/UpCloudLtd/upcloud-go-api/upcloud/emulator"
+	"github.com/UpCloudLtd/upcloud-go-api/upcloud/request"
+	"github.com/UpCloudLtd/upcloud-go-api/upcloud/service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Returns a service talking to an emulator through a fault injecting transport
+func newTestService(t *testing.T, retryPolicy *client.RetryPolicy, opts ...Option) (*service.Service, *Transport) {
+	srv := emulator.NewServer()
+	t.Cleanup(srv.Close)
+
+	transport := NewTransport(nil, opts...)
+	svc := service.New(srv.Client(
+		client.WithHTTPClient(&http.Client{Transport: transport}),
+		client.WithRetryPolicy(retryPolicy),
+	))
+
+	return svc, transport
+}
+
+// Creates a stopped server to start in the tests
+func createStoppedServer(t *testing.T, svc *service.Service) string {
+	serverDetails, err := svc.CreateServer(&request.CreateServerRequest{
+		Zone:     "fi-hel2",
+		Title:    "fault-test",
+		Hostname: "fault-test.example.com",
+		Plan:     "1xCPU-1GB",
+		StorageDevices: request.CreateServerStorageDeviceSlice{{
+			Action:  request.CreateServerStorageDeviceActionClone,
+			Storage: "01000000-0000-4000-8000-000030060200",
+			Title:   "disk",
+			Size:    10,
+		}},
+	})
+	require.NoError(t, err)
+
+	_, err = svc.StopServer(&request.StopServerRequest{UUID: serverDetails.UUID, StopType: request.ServerStopTypeHard})
+	require.NoError(t, err)
+
+	return serverDetails.UUID
+}
+
+// TestScript tests that a script injects its faults into the matching requests in order
+func TestScript(t *testing.T) {
+	svc, transport := newTestService(t, nil, WithRule(Rule{
+		Method: http.MethodPost,
+		Path:   "/server/{uuid}/start",
+		Script: []Fault{Status(http.StatusServiceUnavailable), {}, TooManyRequests(time.Second), Reset()},
+	}))
+	uuid := createStoppedServer(t, svc)
+	start := &request.StartServerRequest{UUID: uuid}
+
+	_, err := svc.StartServer(start)
+	assert.True(t, errors.Is(err, upcloud.ErrServerError))
+
+	_, err = svc.StartServer(start)
+	require.NoError(t, err)
+
+	_, err = svc.StartServer(start)
+	assert.True(t, errors.Is(err, upcloud.ErrRateLimited))
+
+	_, err = svc.StartServer(start)
+	assert.True(t, errors.Is(err, syscall.ECONNRESET))
+
+	// The script is exhausted, so the API answers
+	_, err = svc.StartServer(start)
+	assert.True(t, errors.Is(err, upcloud.ErrConflict))
+
+	injections := transport.Injections()
+	require.Len(t, injections, 3)
+	assert.Equal(t, http.MethodPost, injections[0].Method)
+	assert.Contains(t, injections[0].URL, "/server/"+uuid+"/start")
+	assert.Equal(t, Status(http.StatusServiceUnavailable), injections[0].Fault)
+
+	// Other requests are never affected
+	_, err = svc.GetServerDetails(&request.GetServerDetailsRequest{UUID: uuid})
+	require.NoError(t, err)
+	assert.Len(t, transport.Injections(), 3)
+
+	transport.Reset()
+	assert.Empty(t, transport.Injections())
+	_, err = svc.StopServer(&request.StopServerRequest{UUID: uuid, StopType: request.ServerStopTypeHard})
+	require.NoError(t, err)
+	_, err = svc.StartServer(start)
+	assert.True(t, errors.Is(err, upcloud.ErrServerError))
+}
+
+// TestRetriedFaults tests that the retry policy of the client recovers from transient faults
+func TestRetriedFaults(t *testing.T) {
+	var retries int
+	policy := &client.RetryPolicy{
+		MaxAttempts: 4,
+		MinBackoff:  time.Millisecond,
+		OnRetry:     func(client.RetryEvent) { retries++ },
+	}
+	svc, _ := newTestService(t, policy, WithRule(Rule{
+		Path:   "/zone",
+		Script: []Fault{Status(http.StatusBadGateway), Reset(), Status(http.StatusServiceUnavailable)},
+	}))
+
+	zones, err := svc.GetZones()
+	require.NoError(t, err)
+	assert.NotEmpty(t, zones.Zones)
+	assert.Equal(t, 3, retries)
+}
+
+// TestResponseFaults tests that truncated and malformed response bodies and lost responses fail the request
+func TestResponseFaults(t *testing.T) {
+	svc, transport := newTestService(t, nil,
+		WithRule(Rule{Path: "/zone", Script: []Fault{TruncateBody(), MalformedJSON()}}),
+		WithRule(Rule{Method: http.MethodPost, Path: "/server/{uuid}/start", Fault: ResetAfterForward()}),
+	)
+
+	_, err := svc.GetZones()
+	assert.True(t, errors.Is(err, io.ErrUnexpectedEOF), err)
+
+	_, err = svc.GetZones()
+	assert.Error(t, err)
+
+	_, err = svc.GetZones()
+	assert.NoError(t, err)
+
+	// The API acted on the request even though the response was lost
+	uuid := createStoppedServer(t, svc)
+	_, err = svc.StartServer(&request.StartServerRequest{UUID: uuid})
+	assert.True(t, errors.Is(err, syscall.ECONNRESET))
+
+	serverDetails, err := svc.GetServerDetails(&request.GetServerDetailsRequest{UUID: uuid})
+	require.NoError(t, err)
+	assert.NotEqual(t, upcloud.ServerStateStopped, serverDetails.State)
+	assert.Len(t, transport.Injections(), 3)
+}
+
+// TestLatency tests that injected latency delays requests and honours the deadline of the request
+func TestLatency(t *testing.T) {
+	svc, _ := newTestService(t, nil, WithRule(Rule{Fault: Latency(50 * time.Millisecond)}))
+
+	started := time.Now()
+	_, err := svc.GetZones()
+	require.NoError(t, err)
+	assert.True(t, time.Since(started) >= 50*time.Millisecond)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	_, err = svc.GetZonesWithContext(ctx)
+	assert.True(t, errors.Is(err, context.DeadlineExceeded))
+}
+
+// TestProbability tests that faults with a probability are injected into about that share of the requests and
+// reproducibly with the same seed
+func TestProbability(t *testing.T) {
+	injected := func(seed int64) []bool {
+		svc, _ := newTestService(t, nil, WithSeed(seed), WithRule(Rule{
+			Method:      http.MethodGet,
+			Path:        "/zone",
+			Fault:       Status(http.StatusInternalServerError),
+			Probability: 0.3,
+		}))
+
+		result := make([]bool, 200)
+		for i := range result {
+			_, err := svc.GetZones()
+			result[i] = err != nil
+		}
+		return result
+	}
+
+	first := injected(42)
+	var count int
+	for _, failed := range first {
+		if failed {
+			count++
+		}
+	}
+	assert.InDelta(t, 60, count, 25)
+	assert.Equal(t, first, injected(42))
+}
+
+// TestRuleMatches tests matching requests to the paths of rules
+func TestRuleMatches(t *testing.T) {
+	tests := []struct {
+		rule   Rule
+		method string
+		path   string
+		match  bool
+	}{
+		{Rule{}, http.MethodGet, "/1.3/zone", true},
+		{Rule{Method: "post"}, http.MethodPost, "/1.3/server", true},
+		{Rule{Method: http.MethodPost}, http.MethodGet, "/1.3/server", false},
+		{Rule{Path: "/server"}, http.MethodGet, "/1.3/server", true},
+		{Rule{Path: "/server"}, http.MethodGet, "/1.3/server/0077fa3d", false},
+		{Rule{Path: "/server/{uuid}/start"}, http.MethodPost, "/1.3/server/0077fa3d/start", true},
+		{Rule{Path: "/server/{uuid}/start"}, http.MethodPost, "/1.3/server/0077fa3d/stop", false},
+		{Rule{Path: "/server/{uuid}/start"}, http.MethodPost, "/1.3/server/0077fa3d", false},
+		{Rule{Path: "/server/*"}, http.MethodPost, "/1.3/server/0077fa3d/start", true},
+		{Rule{Path: "/server/*"}, http.MethodPost, "/1.3/storage/0077fa3d", false},
+		{Rule{Path: "/server/*"}, http.MethodGet, "/1.3/server", true},
+		{Rule{Path: "/uploader/*"}, http.MethodPut, "/uploader/session/0", true},
+	}
+
+	for _, test := range tests {
+		assert.Equal(t, test.match, test.rule.matches(test.method, apiPath(test.path)), "%+v %s %s",
+			test.rule, test.method, test.path)
+	}
+}
@@ -14,6 +14,9 @@ const (
 	FirewallRuleProtocolTCP  = "tcp"
 	FirewallRuleProtocolUDP  = "udp"
 	FirewallRuleProtocolICMP = "icmp"
+
+	FirewallRuleChangeCreate = "create"
+	FirewallRuleChangeDelete = "delete"
 )
 
 // FirewallRules represents a list of firewall rules
@@ -80,3 +83,18 @@ func (s *FirewallRule) UnmarshalJSON(b []byte) error {
 
 	return nil
 }
+
+// FirewallRuleChange represents a single change made when syncing the firewall rules of a server. Position is the
+// position the rule was created at or deleted from at the time of the change
+type FirewallRuleChange struct {
+	Action   string
+	Position int
+	Rule     FirewallRule
+}
+
+// FirewallRuleSync represents the changes made when syncing the firewall rules of a server. Replaced tells whether
+// the whole rule set was replaced instead of the changes being applied one by one
+type FirewallRuleSync struct {
+	Changes  []FirewallRuleChange
+	Replaced bool
+}
@@ -0,0 +1,222 @@
+// Package firewall works with firewall rule sets offline, without calling the API.
+//
+// Rules can be written in a compact syntax that expands into one or more upcloud.FirewallRule values:
+//
+//	rules, err := firewall.Parse("in accept tcp from 10.0.0.0/8 to port 22,80-443")
+//	fmt.Print(firewall.FormatRules(rules)) // e.g. "in accept ipv4 tcp from 10.0.0.0/8 to port 22"
+//
+// A rule is the direction, the action, then optionally the family, the protocol, the source, the destination and a
+// comment:
+//
+//	in|out accept|reject|drop [ipv4|ipv6] [tcp|udp|icmp [type N]]
+//		[from ADDRESSES [port PORTS]] [to ADDRESSES [port PORTS]] [comment "TEXT"]
+//
+// Addresses are single addresses, CIDR blocks or ranges such as 192.0.2.10-192.0.2.20, separated by commas, or any.
+// Ports are single ports or ranges such as 80-443, separated by commas. Without the family or any addresses, a rule
+// applies to both IPv4 and IPv6.
+//
+// A Simulator tells what the firewall does to a packet, following the rules in the order of their positions until
+// one matches:
+//
+//	s, err := firewall.NewSimulator(rules)
+//	packet, err := firewall.ParsePacket("in tcp from 203.0.113.5 port 5000 to port 22")
+//	verdict, err := s.Evaluate(packet)
+//	fmt.Println(verdict) // e.g. "accept by rule 2"
+//
+// Lint finds rules that never match, duplicates, invalid ranges and rules that open sensitive ports to the whole
+// internet:
+//
+//	for _, finding := range firewall.Lint(rules) {
+//		fmt.Println(finding) // e.g. "rule 3: warning: duplicates rule 1"
+//	}
+package firewall
+
+import (
+	"bytes"
+	"fmt"
+	"net"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/UpCloudLtd/upcloud-go-api/upcloud"
+)
+
+// address is an address range of a rule, a single address having the same start and end
+type address struct {
+	start, end net.IP
+	family     string
+}
+
+// portRange is a port range of a rule, a single port having the same start and end
+type portRange struct {
+	start, end int
+}
+
+// Returns an IP address in its 4 byte form for IPv4 and its 16 byte form for IPv6, or nil if it's invalid
+func parseIP(s string) net.IP {
+	ip := net.ParseIP(s)
+	if ip4 := ip.To4(); ip4 != nil {
+		return ip4
+	}
+
+	return ip
+}
+
+// Returns the last address of the network starting at an address
+func lastAddress(ip net.IP, mask net.IPMask) net.IP {
+	last := make(net.IP, len(ip))
+	for i := range ip {
+		last[i] = ip[i] | ^mask[i]
+	}
+
+	return last
+}
+
+// Returns the family of an address
+func ipFamily(ip net.IP) string {
+	if len(ip) == net.IPv4len {
+		return upcloud.IPAddressFamilyIPv4
+	}
+
+	return upcloud.IPAddressFamilyIPv6
+}
+
+// Returns the family in the case the API uses, e.g. "IPv4" for "ipv4". Unknown families are returned as they are.
+func normalizeFamily(family string) string {
+	for _, f := range []string{upcloud.IPAddressFamilyIPv4, upcloud.IPAddressFamilyIPv6} {
+		if strings.EqualFold(family, f) {
+			return f
+		}
+	}
+
+	return family
+}
+
+// Returns the start and the end of an address range, empty for any address
+func (a address) strings() (string, string) {
+	if a.start == nil {
+		return "", ""
+	}
+
+	return a.start.String(), a.end.String()
+}
+
+// Returns the start and the end of a port range, empty for any port
+func (r portRange) strings() (string, string) {
+	if r.start == 0 {
+		return "", ""
+	}
+
+	return strconv.Itoa(r.start), strconv.Itoa(r.end)
+}
+
+// parsedRule is a rule with its ranges parsed
+type parsedRule struct {
+	rule                          upcloud.FirewallRule
+	position                      int
+	source, destination           address
+	sourcePorts, destinationPorts portRange
+}
+
+// Parses the ranges of a rule at an index. A rule without a position is at the position of its index.
+func parseRule(rule upcloud.FirewallRule, index int) (parsedRule, error) {
+	r := parsedRule{rule: rule, position: rule.Position}
+	if r.position == 0 {
+		r.position = index + 1
+	}
+	r.rule.Family = normalizeFamily(rule.Family)
+
+	var err error
+	if r.source, err = parseAddressRange(rule.SourceAddressStart, rule.SourceAddressEnd); err != nil {
+		return r, fmt.Errorf("source address: %w", err)
+	}
+	if r.destination, err = parseAddressRange(rule.DestinationAddressStart, rule.DestinationAddressEnd); err != nil {
+		return r, fmt.Errorf("destination address: %w", err)
+	}
+	if r.sourcePorts, err = parsePorts(rule.SourcePortStart, rule.SourcePortEnd); err != nil {
+		return r, fmt.Errorf("source port: %w", err)
+	}
+	if r.destinationPorts, err = parsePorts(rule.DestinationPortStart, rule.DestinationPortEnd); err != nil {
+		return r, fmt.Errorf("destination port: %w", err)
+	}
+
+	return r, nil
+}
+
+// Sorts rules by their positions, keeping the order of the rules at the same position
+func sortRules(rules []parsedRule) {
+	sort.SliceStable(rules, func(i, j int) bool {
+		return rules[i].position < rules[j].position
+	})
+}
+
+// Parses the address range of a rule. A missing start or end is the same as the other, and a missing range is any
+// address.
+func parseAddressRange(start, end string) (address, error) {
+	if start == "" {
+		start = end
+	}
+	if end == "" {
+		end = start
+	}
+	if start == "" {
+		return address{}, nil
+	}
+
+	startIP, endIP := parseIP(start), parseIP(end)
+	switch {
+	case startIP == nil:
+		return address{}, fmt.Errorf("invalid address %q", start)
+	case endIP == nil:
+		return address{}, fmt.Errorf("invalid address %q", end)
+	case len(startIP) != len(endIP):
+		return address{}, fmt.Errorf("address range %s-%s mixes IPv4 and IPv6", start, end)
+	}
+
+	return address{start: startIP, end: endIP, family: ipFamily(startIP)}, nil
+}
+
+// Parses the port range of a rule. A missing start or end is the same as the other, and a missing range is any port.
+func parsePorts(start, end string) (portRange, error) {
+	if start == "" {
+		start = end
+	}
+	if end == "" {
+		end = start
+	}
+	if start == "" {
+		return portRange{}, nil
+	}
+
+	var r portRange
+	var err error
+	if r.start, err = strconv.Atoi(start); err != nil || r.start < 1 || r.start > 65535 {
+		return portRange{}, fmt.Errorf("invalid port %q", start)
+	}
+	if r.end, err = strconv.Atoi(end); err != nil || r.end < 1 || r.end > 65535 {
+		return portRange{}, fmt.Errorf("invalid port %q", end)
+	}
+
+	return r, nil
+}
+
+// Returns whether the range matches any address
+func (a address) any() bool {
+	return a.start == nil
+}
+
+// Returns whether the range contains an address
+func (a address) contains(ip net.IP) bool {
+	return len(ip) == len(a.start) && bytes.Compare(a.start, ip) <= 0 && bytes.Compare(ip, a.end) <= 0
+}
+
+// Returns whether the range matches any port
+func (r portRange) any() bool {
+	return r.start == 0
+}
+
+// Returns whether the range contains a port
+func (r portRange) contains(port int) bool {
+	return r.start <= port && port <= r.end
+}
@@ -0,0 +1,265 @@
+package firewall
+
+import (
+	"bytes"
+	"fmt"
+	"net"
+	"sort"
+	"strings"
+
+	"github.com/UpCloudLtd/upcloud-go-api/upcloud"
+)
+
+// Severity is how serious a finding is
+type Severity string
+
+// The severities of the findings
+const (
+	// SeverityError is a rule that doesn't work as written
+	SeverityError Severity = "error"
+	// SeverityWarning is a rule that works but is likely a mistake
+	SeverityWarning Severity = "warning"
+)
+
+// Check is the kind of problem a finding is about
+type Check string
+
+// The checks of the linter
+const (
+	// CheckInvalid is an address or a port that can't be parsed
+	CheckInvalid Check = "invalid"
+	// CheckRange is a range that ends before it starts
+	CheckRange Check = "range"
+	// CheckFamily is an address of another family than the rule
+	CheckFamily Check = "family"
+	// CheckDuplicate is a rule that matches the same traffic as an earlier one and takes the same action
+	CheckDuplicate Check = "duplicate"
+	// CheckShadowed is a rule that never matches as an earlier one matches all its traffic
+	CheckShadowed Check = "shadowed"
+	// CheckExposed is a rule that opens sensitive ports to the whole internet
+	CheckExposed Check = "exposed"
+)
+
+// DefaultSensitivePorts are the ports the linter warns about opening to the whole internet unless
+// WithSensitivePorts says otherwise: remote access and databases
+var DefaultSensitivePorts = map[int]string{
+	22:    "SSH",
+	1433:  "SQL Server",
+	1521:  "Oracle",
+	3306:  "MySQL",
+	3389:  "RDP",
+	5432:  "PostgreSQL",
+	6379:  "Redis",
+	9200:  "Elasticsearch",
+	11211: "Memcached",
+	27017: "MongoDB",
+}
+
+// Finding is a problem the linter found in a rule
+type Finding struct {
+	Position int
+	Severity Severity
+	Check    Check
+	Message  string
+}
+
+// String returns the finding as e.g. "rule 3: warning: duplicates rule 1"
+func (f Finding) String() string {
+	return fmt.Sprintf("rule %d: %s: %s", f.Position, f.Severity, f.Message)
+}
+
+// LintOption configures Lint
+type LintOption func(l *linter)
+
+// WithSensitivePorts sets the ports, with their names, that Lint warns about opening to the whole internet
+func WithSensitivePorts(ports map[int]string) LintOption {
+	return func(l *linter) {
+		l.sensitivePorts = ports
+	}
+}
+
+// linter collects the findings about rules
+type linter struct {
+	sensitivePorts map[int]string
+	findings       []Finding
+}
+
+// Lint checks firewall rules for mistakes, in the order of their positions. Rules without a position are at the
+// position of their index in the slice. The findings are ordered by the positions of the rules.
+//
+// Rules with invalid addresses or ports, ranges that end before they start or addresses of another family than the
+// rule are errors. Rules that never match because an earlier rule matches all their traffic are errors when the
+// earlier rule takes another action and warnings when it takes the same one, or duplicates when both match the same
+// traffic. Incoming rules that accept traffic to sensitive ports from the whole internet are warnings.
+func Lint(rules []upcloud.FirewallRule, opts ...LintOption) []Finding {
+	l := &linter{sensitivePorts: DefaultSensitivePorts}
+	for _, opt := range opts {
+		opt(l)
+	}
+
+	parsed := make([]parsedRule, 0, len(rules))
+	for i, rule := range rules {
+		r, err := parseRule(rule, i)
+		if err != nil {
+			l.add(r.position, SeverityError, CheckInvalid, err.Error())
+			continue
+		}
+		if l.checkRanges(&r) {
+			parsed = append(parsed, r)
+		}
+	}
+	sortRules(parsed)
+
+	for i := range parsed {
+		r := &parsed[i]
+		if l.checkEarlier(r, parsed[:i]) {
+			l.checkExposed(r)
+		}
+	}
+
+	sort.SliceStable(l.findings, func(i, j int) bool {
+		return l.findings[i].Position < l.findings[j].Position
+	})
+
+	return l.findings
+}
+
+// Adds a finding
+func (l *linter) add(position int, severity Severity, check Check, format string, args ...interface{}) {
+	l.findings = append(l.findings, Finding{
+		Position: position,
+		Severity: severity,
+		Check:    check,
+		Message:  fmt.Sprintf(format, args...),
+	})
+}
+
+// Checks the order and the families of the ranges of a rule, returning whether they're fine
+func (l *linter) checkRanges(r *parsedRule) bool {
+	ok := true
+	for _, a := range []struct {
+		name  string
+		value address
+	}{{"source address", r.source}, {"destination address", r.destination}} {
+		if a.value.any() {
+			continue
+		}
+		if bytes.Compare(a.value.start, a.value.end) > 0 {
+			l.add(r.position, SeverityError, CheckRange, "%s range %s-%s ends before it starts", a.name, a.value.start, a.value.end)
+			ok = false
+		}
+		if a.value.family != r.rule.Family {
+			l.add(r.position, SeverityError, CheckFamily, "%s %s isn't an %s address",
+				a.name, formatAddress(a.value.start.String(), a.value.end.String()), r.rule.Family)
+			ok = false
+		}
+	}
+
+	for _, p := range []struct {
+		name  string
+		value portRange
+	}{{"source port", r.sourcePorts}, {"destination port", r.destinationPorts}} {
+		if p.value.start > p.value.end {
+			l.add(r.position, SeverityError, CheckRange, "%s range %d-%d ends before it starts", p.name, p.value.start, p.value.end)
+			ok = false
+		}
+	}
+
+	return ok
+}
+
+// Checks whether a rule duplicates or is shadowed by an earlier rule, returning whether it can match at all
+func (l *linter) checkEarlier(r *parsedRule, earlier []parsedRule) bool {
+	for i := range earlier {
+		e := &earlier[i]
+		switch {
+		case !e.covers(r):
+			continue
+		case e.rule.Action != r.rule.Action:
+			l.add(r.position, SeverityError, CheckShadowed,
+				"never matches, rule %d matches all its traffic first and %ss it instead", e.position, e.rule.Action)
+		case r.covers(e):
+			l.add(r.position, SeverityWarning, CheckDuplicate, "duplicates rule %d", e.position)
+		default:
+			l.add(r.position, SeverityWarning, CheckShadowed,
+				"never matches, rule %d matches all its traffic first and also %ss it", e.position, e.rule.Action)
+		}
+
+		return false
+	}
+
+	return true
+}
+
+// Checks whether an incoming rule accepts traffic to sensitive ports from the whole internet
+func (l *linter) checkExposed(r *parsedRule) {
+	if r.rule.Direction != upcloud.FirewallRuleDirectionIn || r.rule.Action != upcloud.FirewallRuleActionAccept ||
+		r.rule.Protocol == upcloud.FirewallRuleProtocolICMP || !r.source.wholeInternet() {
+		return
+	}
+
+	if r.destinationPorts.any() {
+		l.add(r.position, SeverityWarning, CheckExposed, "opens all ports to the whole internet")
+		return
+	}
+
+	var ports []int
+	for port := range l.sensitivePorts {
+		if r.destinationPorts.contains(port) {
+			ports = append(ports, port)
+		}
+	}
+	if len(ports) == 0 {
+		return
+	}
+	sort.Ints(ports)
+
+	names := make([]string, len(ports))
+	for i, port := range ports {
+		names[i] = fmt.Sprintf("%s (%d)", l.sensitivePorts[port], port)
+	}
+	l.add(r.position, SeverityWarning, CheckExposed, "opens %s to the whole internet", strings.Join(names, ", "))
+}
+
+// Returns whether a rule matches all the traffic another rule does
+func (r *parsedRule) covers(other *parsedRule) bool {
+	switch {
+	case r.rule.Direction != other.rule.Direction || r.rule.Family != other.rule.Family:
+		return false
+	case r.rule.Protocol != "" && r.rule.Protocol != other.rule.Protocol:
+		return false
+	case r.rule.ICMPType != "" && r.rule.ICMPType != other.rule.ICMPType:
+		return false
+	}
+
+	return r.source.covers(other.source) && r.destination.covers(other.destination) &&
+		r.sourcePorts.covers(other.sourcePorts) && r.destinationPorts.covers(other.destinationPorts)
+}
+
+// Returns whether an address range contains all the addresses of another
+func (a address) covers(other address) bool {
+	if a.any() {
+		return true
+	}
+
+	return !other.any() && a.contains(other.start) && a.contains(other.end)
+}
+
+// Returns whether the range is any address or all the addresses of its family
+func (a address) wholeInternet() bool {
+	if a.any() {
+		return true
+	}
+
+	zero := make(net.IP, len(a.start))
+	return a.start.Equal(zero) && a.end.Equal(lastAddress(zero, net.CIDRMask(0, len(zero)*8)))
+}
+
+// Returns whether a port range contains all the ports of another
+func (r portRange) covers(other portRange) bool {
+	if r.any() {
+		return true
+	}
+
+	return !other.any() && r.contains(other.start) && r.contains(other.end)
+}
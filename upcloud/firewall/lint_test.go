@@ -0,0 +1,143 @@
+package firewall
+
+import (
+	"testing"
+
+	"github.com/UpCloudLtd/upcloud-go-api/upcloud"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Returns the findings as strings
+func findingStrings(findings []Finding) []string {
+	var s []string
+	for _, f := range findings {
+		s = append(s, f.String())
+	}
+
+	return s
+}
+
+// TestLint tests that shadowed, duplicate and exposing rules are found in the order of the positions of the rules
+func TestLint(t *testing.T) {
+	rules, err := ParseRules(`
+in accept ipv4 tcp from 192.0.2.0/24 to port 22
+in accept ipv4 tcp from 192.0.2.10 to port 22
+in drop ipv4 tcp from 192.0.2.0-192.0.2.255 port 1-65535 to port 22
+in accept ipv4 tcp to port 3300-3400
+in accept ipv4 tcp from 192.0.2.0/24 to port 3306
+in accept ipv4 tcp to port 443
+in accept ipv4 tcp to port 443 comment "HTTPS"
+in accept ipv6 tcp from ::/0 to port 5432
+in accept ipv4 udp to port 53
+in accept ipv4 icmp
+out accept ipv4
+in drop ipv4
+in accept ipv4
+`)
+	require.NoError(t, err)
+
+	findings := Lint(rules)
+	assert.Equal(t, []string{
+		"rule 2: warning: never matches, rule 1 matches all its traffic first and also accepts it",
+		"rule 3: error: never matches, rule 1 matches all its traffic first and accepts it instead",
+		"rule 4: warning: opens MySQL (3306), RDP (3389) to the whole internet",
+		"rule 5: warning: never matches, rule 4 matches all its traffic first and also accepts it",
+		"rule 7: warning: duplicates rule 6",
+		"rule 8: warning: opens PostgreSQL (5432) to the whole internet",
+		"rule 13: error: never matches, rule 12 matches all its traffic first and drops it instead",
+	}, findingStrings(findings))
+	assert.Equal(t, CheckDuplicate, findings[4].Check)
+	assert.Equal(t, CheckExposed, findings[5].Check)
+
+	findings = Lint(rules[:4], WithSensitivePorts(map[int]string{3333: "Custom"}))
+	assert.Equal(t, "rule 4: warning: opens Custom (3333) to the whole internet", findings[len(findings)-1].String())
+
+	findings = Lint([]upcloud.FirewallRule{{
+		Direction: upcloud.FirewallRuleDirectionIn,
+		Action:    upcloud.FirewallRuleActionAccept,
+		Family:    upcloud.IPAddressFamilyIPv6,
+	}})
+	assert.Equal(t, []Finding{{
+		Position: 1,
+		Severity: SeverityWarning,
+		Check:    CheckExposed,
+		Message:  "opens all ports to the whole internet",
+	}}, findings)
+}
+
+// TestLintPositions tests that the rules are checked in the order of their positions
+func TestLintPositions(t *testing.T) {
+	rules, err := ParseRules("in drop ipv4\nin accept ipv4 tcp from 192.0.2.1 to port 80")
+	require.NoError(t, err)
+	rules[0].Position = 2
+	rules[1].Position = 1
+
+	assert.Empty(t, Lint(rules))
+}
+
+// TestLintFamilyCase tests that the families of the rules are compared whatever their case
+func TestLintFamilyCase(t *testing.T) {
+	rules := []upcloud.FirewallRule{
+		{
+			Direction:          upcloud.FirewallRuleDirectionIn,
+			Action:             upcloud.FirewallRuleActionDrop,
+			Family:             "ipv4",
+			SourceAddressStart: "192.0.2.1",
+		},
+		{
+			Direction:          upcloud.FirewallRuleDirectionIn,
+			Action:             upcloud.FirewallRuleActionDrop,
+			Family:             upcloud.IPAddressFamilyIPv4,
+			SourceAddressStart: "192.0.2.1",
+		},
+	}
+
+	assert.Equal(t, []string{"rule 2: warning: duplicates rule 1"}, findingStrings(Lint(rules)))
+}
+
+// TestLintInvalid tests that rules with invalid or reversed ranges and addresses of the wrong family are errors
+func TestLintInvalid(t *testing.T) {
+	rules := []upcloud.FirewallRule{
+		{
+			Direction:          upcloud.FirewallRuleDirectionIn,
+			Action:             upcloud.FirewallRuleActionAccept,
+			Family:             upcloud.IPAddressFamilyIPv4,
+			SourceAddressStart: "192.0.2.300",
+		},
+		{
+			Direction:               upcloud.FirewallRuleDirectionIn,
+			Action:                  upcloud.FirewallRuleActionAccept,
+			Family:                  upcloud.IPAddressFamilyIPv4,
+			Protocol:                upcloud.FirewallRuleProtocolTCP,
+			SourceAddressStart:      "192.0.2.20",
+			SourceAddressEnd:        "192.0.2.10",
+			DestinationAddressStart: "2001:db8::1",
+			DestinationPortStart:    "443",
+			DestinationPortEnd:      "80",
+		},
+		{
+			Direction:          upcloud.FirewallRuleDirectionIn,
+			Action:             upcloud.FirewallRuleActionDrop,
+			Family:             upcloud.IPAddressFamilyIPv6,
+			SourceAddressStart: "2001:db8::1",
+			SourceAddressEnd:   "192.0.2.1",
+		},
+		{
+			Direction:       upcloud.FirewallRuleDirectionOut,
+			Action:          upcloud.FirewallRuleActionAccept,
+			Family:          upcloud.IPAddressFamilyIPv4,
+			Protocol:        upcloud.FirewallRuleProtocolUDP,
+			SourcePortStart: "http",
+		},
+	}
+
+	assert.Equal(t, []string{
+		`rule 1: error: source address: invalid address "192.0.2.300"`,
+		"rule 2: error: source address range 192.0.2.20-192.0.2.10 ends before it starts",
+		"rule 2: error: destination address 2001:db8::1 isn't an IPv4 address",
+		"rule 2: error: destination port range 443-80 ends before it starts",
+		"rule 3: error: source address: address range 2001:db8::1-192.0.2.1 mixes IPv4 and IPv6",
+		`rule 4: error: source port: invalid port "http"`,
+	}, findingStrings(Lint(rules)))
+}
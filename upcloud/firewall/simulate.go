@@ -0,0 +1,238 @@
+package firewall
+
+import (
+	"fmt"
+	"net"
+	"strconv"
+
+	"github.com/UpCloudLtd/upcloud-go-api/upcloud"
+)
+
+// DefaultAction is the action taken on packets that match none of the rules unless WithDefaultAction says otherwise.
+// Like the API, the firewall accepts them.
+const DefaultAction = upcloud.FirewallRuleActionAccept
+
+// Packet is a packet to evaluate against firewall rules. The family may be left out when it follows from the
+// addresses. The addresses and the ports may be left out too, but a packet without them matches only the rules that
+// don't restrict them.
+type Packet struct {
+	Direction          string
+	Family             string
+	Protocol           string
+	ICMPType           string
+	SourceAddress      string
+	SourcePort         int
+	DestinationAddress string
+	DestinationPort    int
+}
+
+// Verdict is the result of evaluating a packet
+type Verdict struct {
+	Action string
+	// Rule is the rule that matched, nil if none did and the default action was taken
+	Rule *upcloud.FirewallRule
+	// Position is the position of the rule that matched, 0 if none did
+	Position int
+}
+
+// String returns the verdict as e.g. "accept by rule 2" or "drop by default"
+func (v Verdict) String() string {
+	if v.Rule == nil {
+		return v.Action + " by default"
+	}
+
+	return fmt.Sprintf("%s by rule %d", v.Action, v.Position)
+}
+
+// SimulatorOption configures a Simulator
+type SimulatorOption func(s *Simulator)
+
+// WithDefaultAction sets the action taken on packets that match none of the rules
+func WithDefaultAction(action string) SimulatorOption {
+	return func(s *Simulator) {
+		s.defaultAction = action
+	}
+}
+
+// Simulator evaluates packets against firewall rules the way the firewall of a server does: the rules are tried in
+// the order of their positions and the first one that matches decides the action
+type Simulator struct {
+	rules         []parsedRule
+	defaultAction string
+}
+
+// NewSimulator returns a simulator for the rules, e.g. those returned by GetFirewallRules. Rules without a position
+// are at the position of their index in the slice. An error is returned for rules whose addresses or ports are
+// invalid.
+func NewSimulator(rules []upcloud.FirewallRule, opts ...SimulatorOption) (*Simulator, error) {
+	s := &Simulator{defaultAction: DefaultAction}
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	for i, rule := range rules {
+		r, err := parseRule(rule, i)
+		if err != nil {
+			return nil, fmt.Errorf("rule %d: %w", r.position, err)
+		}
+		s.rules = append(s.rules, r)
+	}
+
+	sortRules(s.rules)
+
+	return s, nil
+}
+
+// Evaluate returns the verdict of the firewall on a packet. An error is returned if the packet is invalid or its
+// family is unknown.
+func (s *Simulator) Evaluate(p Packet) (Verdict, error) {
+	if p.Direction != upcloud.FirewallRuleDirectionIn && p.Direction != upcloud.FirewallRuleDirectionOut {
+		return Verdict{}, fmt.Errorf("invalid direction %q", p.Direction)
+	}
+	if p.ICMPType != "" && p.Protocol != upcloud.FirewallRuleProtocolICMP {
+		return Verdict{}, fmt.Errorf("ICMP type requires the icmp protocol")
+	}
+
+	family := normalizeFamily(p.Family)
+	var source, destination net.IP
+	for _, a := range []struct {
+		address string
+		ip      *net.IP
+	}{{p.SourceAddress, &source}, {p.DestinationAddress, &destination}} {
+		if a.address == "" {
+			continue
+		}
+		ip := parseIP(a.address)
+		if ip == nil {
+			return Verdict{}, fmt.Errorf("invalid address %q", a.address)
+		}
+		if family != "" && ipFamily(ip) != family {
+			return Verdict{}, fmt.Errorf("address %s isn't an %s address", a.address, family)
+		}
+		family = ipFamily(ip)
+		*a.ip = ip
+	}
+	if family == "" {
+		return Verdict{}, fmt.Errorf("family is required when the packet has no addresses")
+	}
+
+	for i := range s.rules {
+		r := &s.rules[i]
+		if r.matches(p, family, source, destination) {
+			rule := r.rule
+			return Verdict{Action: rule.Action, Rule: &rule, Position: r.position}, nil
+		}
+	}
+
+	return Verdict{Action: s.defaultAction}, nil
+}
+
+// Returns whether a rule matches a packet
+func (r *parsedRule) matches(p Packet, family string, source, destination net.IP) bool {
+	switch {
+	case r.rule.Direction != p.Direction || r.rule.Family != family:
+		return false
+	case r.rule.Protocol != "" && r.rule.Protocol != p.Protocol:
+		return false
+	case r.rule.ICMPType != "" && r.rule.ICMPType != p.ICMPType:
+		return false
+	case !r.source.any() && !r.source.contains(source):
+		return false
+	case !r.destination.any() && !r.destination.contains(destination):
+		return false
+	case !r.sourcePorts.any() && !r.sourcePorts.contains(p.SourcePort):
+		return false
+	case !r.destinationPorts.any() && !r.destinationPorts.contains(p.DestinationPort):
+		return false
+	}
+
+	return true
+}
+
+// ParsePacket parses a packet written like a rule without the action, with single addresses and ports, e.g.
+// "in tcp from 203.0.113.5 port 5000 to port 22"
+func ParsePacket(s string) (Packet, error) {
+	tokens, err := tokenize(s)
+	if err != nil {
+		return Packet{}, err
+	}
+
+	p := &parser{tokens: tokens}
+	return p.parsePacket()
+}
+
+// Parses a packet: direction, family, protocol with an ICMP type, source and destination
+func (p *parser) parsePacket() (Packet, error) {
+	var packet Packet
+	var ok bool
+
+	if packet.Direction, ok = p.oneOf(upcloud.FirewallRuleDirectionIn, upcloud.FirewallRuleDirectionOut); !ok {
+		return Packet{}, p.unexpected("direction in or out")
+	}
+
+	switch {
+	case p.accept("ipv4"):
+		packet.Family = upcloud.IPAddressFamilyIPv4
+	case p.accept("ipv6"):
+		packet.Family = upcloud.IPAddressFamilyIPv6
+	}
+
+	packet.Protocol, _ = p.oneOf(upcloud.FirewallRuleProtocolTCP, upcloud.FirewallRuleProtocolUDP,
+		upcloud.FirewallRuleProtocolICMP)
+	if p.accept("type") {
+		if packet.Protocol != upcloud.FirewallRuleProtocolICMP {
+			return Packet{}, fmt.Errorf("type requires the icmp protocol")
+		}
+		icmpType, err := p.next("ICMP type")
+		if err != nil {
+			return Packet{}, err
+		}
+		packet.ICMPType = icmpType
+	}
+
+	if p.accept("from") {
+		if err := p.packetEndpoint("from", &packet.SourceAddress, &packet.SourcePort); err != nil {
+			return Packet{}, err
+		}
+	}
+	if p.accept("to") {
+		if err := p.packetEndpoint("to", &packet.DestinationAddress, &packet.DestinationPort); err != nil {
+			return Packet{}, err
+		}
+	}
+
+	if p.pos < len(p.tokens) {
+		return Packet{}, p.unexpected("end of the packet")
+	}
+
+	return packet, nil
+}
+
+// Parses the address and the port following from or to in a packet
+func (p *parser) packetEndpoint(keyword string, addr *string, port *int) error {
+	switch word := p.peek(); word {
+	case "", "port", "to":
+	default:
+		p.pos++
+		if parseIP(word) == nil {
+			return fmt.Errorf("invalid address %q", word)
+		}
+		*addr = word
+	}
+
+	if p.accept("port") {
+		s, err := p.next("port")
+		if err != nil {
+			return err
+		}
+		if *port, err = strconv.Atoi(s); err != nil || *port < 1 || *port > 65535 {
+			return fmt.Errorf("invalid port %q", s)
+		}
+	}
+
+	if *addr == "" && *port == 0 {
+		return p.unexpected("address or port after " + keyword)
+	}
+
+	return nil
+}
@@ -0,0 +1,159 @@
+package firewall
+
+import (
+	"testing"
+
+	"github.com/UpCloudLtd/upcloud-go-api/upcloud"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestSimulator tests that the first rule matching a packet decides its fate
+func TestSimulator(t *testing.T) {
+	rules, err := ParseRules(`
+in accept tcp from 192.0.2.0/24 to port 22
+in reject tcp to port 22
+in accept tcp to port 80,443
+in accept icmp type 8
+in accept udp from port 53
+out drop ipv4 to 198.51.100.10-198.51.100.20
+in drop
+`)
+	require.NoError(t, err)
+	require.Len(t, rules, 14)
+
+	s, err := NewSimulator(rules)
+	require.NoError(t, err)
+
+	tests := []struct {
+		packet  string
+		verdict string
+	}{
+		{"in tcp from 192.0.2.7 port 5000 to port 22", "accept by rule 1"},
+		{"in tcp from 203.0.113.5 port 5000 to port 22", "reject by rule 2"},
+		{"in tcp from 2001:db8::5 to port 22", "reject by rule 3"},
+		{"in tcp from 203.0.113.5 to port 443", "accept by rule 5"},
+		{"in tcp from 2001:db8::5 to port 80", "accept by rule 6"},
+		{"in ipv4 udp to port 80", "drop by rule 13"},
+		{"in icmp type 8 from 203.0.113.5", "accept by rule 8"},
+		{"in icmp type 0 from 203.0.113.5", "drop by rule 13"},
+		{"in udp from 203.0.113.5 port 53 to port 40000", "accept by rule 10"},
+		{"in udp from 2001:db8::53 port 53", "accept by rule 11"},
+		{"in ipv6", "drop by rule 14"},
+		{"out tcp to 198.51.100.15 port 443", "drop by rule 12"},
+		{"out tcp to 198.51.100.21 port 443", "accept by default"},
+		{"out ipv6 tcp to port 443", "accept by default"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.packet, func(t *testing.T) {
+			packet, err := ParsePacket(test.packet)
+			require.NoError(t, err)
+			verdict, err := s.Evaluate(packet)
+			require.NoError(t, err)
+			assert.Equal(t, test.verdict, verdict.String())
+		})
+	}
+}
+
+// TestSimulatorPositions tests that the rules are evaluated in the order of their positions and that the verdict
+// refers to the rule that matched
+func TestSimulatorPositions(t *testing.T) {
+	drop := upcloud.FirewallRule{
+		Direction: upcloud.FirewallRuleDirectionIn,
+		Action:    upcloud.FirewallRuleActionDrop,
+		Family:    upcloud.IPAddressFamilyIPv4,
+		Position:  2,
+	}
+	ssh := upcloud.FirewallRule{
+		Direction:            upcloud.FirewallRuleDirectionIn,
+		Action:               upcloud.FirewallRuleActionAccept,
+		Family:               upcloud.IPAddressFamilyIPv4,
+		Protocol:             upcloud.FirewallRuleProtocolTCP,
+		DestinationPortStart: "22",
+		Position:             1,
+	}
+
+	s, err := NewSimulator([]upcloud.FirewallRule{drop, ssh}, WithDefaultAction(upcloud.FirewallRuleActionDrop))
+	require.NoError(t, err)
+
+	verdict, err := s.Evaluate(Packet{
+		Direction:       upcloud.FirewallRuleDirectionIn,
+		Family:          upcloud.IPAddressFamilyIPv4,
+		Protocol:        upcloud.FirewallRuleProtocolTCP,
+		DestinationPort: 22,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, Verdict{Action: upcloud.FirewallRuleActionAccept, Rule: &ssh, Position: 1}, verdict)
+
+	verdict, err = s.Evaluate(Packet{Direction: upcloud.FirewallRuleDirectionIn, SourceAddress: "192.0.2.1"})
+	require.NoError(t, err)
+	assert.Equal(t, Verdict{Action: upcloud.FirewallRuleActionDrop, Rule: &drop, Position: 2}, verdict)
+
+	verdict, err = s.Evaluate(Packet{Direction: upcloud.FirewallRuleDirectionOut, DestinationAddress: "2001:db8::1"})
+	require.NoError(t, err)
+	assert.Equal(t, Verdict{Action: upcloud.FirewallRuleActionDrop}, verdict)
+	assert.Equal(t, "drop by default", verdict.String())
+}
+
+// TestSimulatorFamilyCase tests that the families of the rules and the packets match whatever their case
+func TestSimulatorFamilyCase(t *testing.T) {
+	s, err := NewSimulator([]upcloud.FirewallRule{{
+		Direction:            upcloud.FirewallRuleDirectionIn,
+		Action:               upcloud.FirewallRuleActionAccept,
+		Family:               "ipv4",
+		Protocol:             upcloud.FirewallRuleProtocolTCP,
+		DestinationPortStart: "22",
+	}}, WithDefaultAction(upcloud.FirewallRuleActionDrop))
+	require.NoError(t, err)
+
+	for _, packet := range []Packet{
+		{Direction: upcloud.FirewallRuleDirectionIn, SourceAddress: "192.0.2.1", Protocol: upcloud.FirewallRuleProtocolTCP, DestinationPort: 22},
+		{Direction: upcloud.FirewallRuleDirectionIn, Family: "IPV4", Protocol: upcloud.FirewallRuleProtocolTCP, DestinationPort: 22},
+	} {
+		verdict, err := s.Evaluate(packet)
+		require.NoError(t, err)
+		assert.Equal(t, "accept by rule 1", verdict.String())
+	}
+}
+
+// TestSimulatorErrors tests that invalid rules and packets are rejected
+func TestSimulatorErrors(t *testing.T) {
+	_, err := NewSimulator([]upcloud.FirewallRule{{
+		Direction:          upcloud.FirewallRuleDirectionIn,
+		Action:             upcloud.FirewallRuleActionAccept,
+		Family:             upcloud.IPAddressFamilyIPv4,
+		SourceAddressStart: "192.0.2.300",
+	}})
+	assert.EqualError(t, err, `rule 1: source address: invalid address "192.0.2.300"`)
+
+	s, err := NewSimulator(nil)
+	require.NoError(t, err)
+
+	tests := []struct {
+		packet Packet
+		err    string
+	}{
+		{Packet{Direction: "inbound"}, `invalid direction "inbound"`},
+		{Packet{Direction: upcloud.FirewallRuleDirectionIn}, "family is required when the packet has no addresses"},
+		{
+			Packet{Direction: upcloud.FirewallRuleDirectionIn, SourceAddress: "192.0.2.1", DestinationAddress: "2001:db8::1"},
+			"address 2001:db8::1 isn't an IPv4 address",
+		},
+		{
+			Packet{Direction: upcloud.FirewallRuleDirectionIn, Family: upcloud.IPAddressFamilyIPv4, ICMPType: "8"},
+			"ICMP type requires the icmp protocol",
+		},
+	}
+	for _, test := range tests {
+		_, err := s.Evaluate(test.packet)
+		assert.EqualError(t, err, test.err)
+	}
+
+	_, err = ParsePacket("in tcp to port 22,80")
+	assert.EqualError(t, err, `invalid port "22,80"`)
+	_, err = ParsePacket("in tcp from 10.0.0.0/8")
+	assert.EqualError(t, err, `invalid address "10.0.0.0/8"`)
+	_, err = ParsePacket("in accept tcp")
+	assert.EqualError(t, err, `unexpected "accept", expected end of the packet`)
+}
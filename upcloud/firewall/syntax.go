@@ -0,0 +1,460 @@
+package firewall
+
+import (
+	"bytes"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+
+	"github.com/UpCloudLtd/upcloud-go-api/upcloud"
+)
+
+// endpoint is the source or the destination of a rule. No addresses or ports matches any.
+type endpoint struct {
+	addresses []address
+	ports     []portRange
+}
+
+// Parse parses a rule in the rule syntax into the firewall rules it expands to. A rule is expanded into a rule for
+// each combination of its source and destination addresses and ports, and for both IPv4 and IPv6 when neither the
+// family nor any addresses are given. The positions of the rules are left unset.
+func Parse(s string) ([]upcloud.FirewallRule, error) {
+	tokens, err := tokenize(s)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &parser{tokens: tokens}
+	return p.parse()
+}
+
+// ParseRules parses rules in the rule syntax, one per line, into the firewall rules they expand to, in order. Empty
+// lines and lines starting with # are skipped.
+func ParseRules(s string) ([]upcloud.FirewallRule, error) {
+	var rules []upcloud.FirewallRule
+	for i, line := range strings.Split(s, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		parsed, err := Parse(line)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", i+1, err)
+		}
+		rules = append(rules, parsed...)
+	}
+
+	return rules, nil
+}
+
+// Format renders a firewall rule in the rule syntax. Address ranges that are CIDR blocks are rendered as such. The
+// position of the rule isn't rendered.
+func Format(rule upcloud.FirewallRule) string {
+	words := []string{rule.Direction, rule.Action}
+	if rule.Family != "" {
+		words = append(words, strings.ToLower(rule.Family))
+	}
+	if rule.Protocol != "" {
+		words = append(words, rule.Protocol)
+	}
+	if rule.ICMPType != "" {
+		words = append(words, "type", rule.ICMPType)
+	}
+
+	words = appendEndpoint(words, "from", rule.SourceAddressStart, rule.SourceAddressEnd,
+		rule.SourcePortStart, rule.SourcePortEnd)
+	words = appendEndpoint(words, "to", rule.DestinationAddressStart, rule.DestinationAddressEnd,
+		rule.DestinationPortStart, rule.DestinationPortEnd)
+
+	if rule.Comment != "" {
+		words = append(words, "comment", strconv.Quote(rule.Comment))
+	}
+
+	return strings.Join(words, " ")
+}
+
+// FormatRules renders firewall rules in the rule syntax, one per line
+func FormatRules(rules []upcloud.FirewallRule) string {
+	var b bytes.Buffer
+	for _, rule := range rules {
+		b.WriteString(Format(rule))
+		b.WriteByte('\n')
+	}
+
+	return b.String()
+}
+
+// Appends the source or the destination of a rule unless it matches any
+func appendEndpoint(words []string, keyword, addressStart, addressEnd, portStart, portEnd string) []string {
+	if addressStart == "" && addressEnd == "" && portStart == "" && portEnd == "" {
+		return words
+	}
+
+	words = append(words, keyword)
+	if addressStart != "" || addressEnd != "" {
+		words = append(words, formatAddress(addressStart, addressEnd))
+	}
+	if portStart != "" || portEnd != "" {
+		words = append(words, "port", formatRange(portStart, portEnd))
+	}
+
+	return words
+}
+
+// Returns an address range as a single address, a CIDR block or a range
+func formatAddress(start, end string) string {
+	startIP, endIP := parseIP(start), parseIP(end)
+	if startIP == nil || endIP == nil || len(startIP) != len(endIP) {
+		return formatRange(start, end)
+	}
+	if startIP.Equal(endIP) {
+		return startIP.String()
+	}
+
+	bits := len(startIP) * 8
+	for ones := 0; ones < bits; ones++ {
+		mask := net.CIDRMask(ones, bits)
+		if startIP.Mask(mask).Equal(startIP) && lastAddress(startIP, mask).Equal(endIP) {
+			return fmt.Sprintf("%s/%d", startIP, ones)
+		}
+	}
+
+	return formatRange(start, end)
+}
+
+// Returns a range as a single value when the start and the end are the same or either is missing
+func formatRange(start, end string) string {
+	switch {
+	case start == end || end == "":
+		return start
+	case start == "":
+		return end
+	default:
+		return start + "-" + end
+	}
+}
+
+// Splits a rule into words. Commas join the words around them, so lists may have spaces in them, and quoted
+// strings are single words.
+func tokenize(s string) ([]string, error) {
+	var tokens []string
+	join := false
+	for {
+		s = strings.TrimLeft(s, " \t")
+		if s == "" {
+			break
+		}
+
+		var token string
+		if s[0] == '"' {
+			end := 1
+			for end < len(s) && s[end] != '"' {
+				if s[end] == '\\' {
+					end++
+				}
+				end++
+			}
+			if end >= len(s) {
+				return nil, fmt.Errorf("unterminated string %s", s)
+			}
+			token, s = s[:end+1], s[end+1:]
+		} else {
+			end := strings.IndexAny(s, " \t")
+			if end < 0 {
+				end = len(s)
+			}
+			token, s = s[:end], s[end:]
+		}
+
+		if len(tokens) > 0 && (join || strings.HasPrefix(token, ",")) {
+			tokens[len(tokens)-1] += token
+		} else {
+			tokens = append(tokens, token)
+		}
+		join = strings.HasSuffix(token, ",")
+	}
+
+	return tokens, nil
+}
+
+// parser parses a rule from its words
+type parser struct {
+	tokens []string
+	pos    int
+}
+
+// Returns the next word in lower case without consuming it, or an empty string at the end
+func (p *parser) peek() string {
+	if p.pos >= len(p.tokens) {
+		return ""
+	}
+
+	return strings.ToLower(p.tokens[p.pos])
+}
+
+// Consumes the next word if it's the keyword
+func (p *parser) accept(keyword string) bool {
+	if p.peek() != keyword {
+		return false
+	}
+	p.pos++
+
+	return true
+}
+
+// Consumes and returns the next word, failing at the end
+func (p *parser) next(what string) (string, error) {
+	if p.pos >= len(p.tokens) {
+		return "", fmt.Errorf("expected %s at the end of the rule", what)
+	}
+	p.pos++
+
+	return p.tokens[p.pos-1], nil
+}
+
+// Consumes the next word if it's one of the values
+func (p *parser) oneOf(values ...string) (string, bool) {
+	for _, v := range values {
+		if p.accept(v) {
+			return v, true
+		}
+	}
+
+	return "", false
+}
+
+// Parses a rule: direction, action, family, protocol with an ICMP type, source, destination and comment
+func (p *parser) parse() ([]upcloud.FirewallRule, error) {
+	var rule upcloud.FirewallRule
+	var ok bool
+
+	if rule.Direction, ok = p.oneOf(upcloud.FirewallRuleDirectionIn, upcloud.FirewallRuleDirectionOut); !ok {
+		return nil, p.unexpected("direction in or out")
+	}
+	if rule.Action, ok = p.oneOf(upcloud.FirewallRuleActionAccept, upcloud.FirewallRuleActionReject,
+		upcloud.FirewallRuleActionDrop); !ok {
+		return nil, p.unexpected("action accept, reject or drop")
+	}
+
+	switch {
+	case p.accept("ipv4"):
+		rule.Family = upcloud.IPAddressFamilyIPv4
+	case p.accept("ipv6"):
+		rule.Family = upcloud.IPAddressFamilyIPv6
+	}
+
+	rule.Protocol, _ = p.oneOf(upcloud.FirewallRuleProtocolTCP, upcloud.FirewallRuleProtocolUDP,
+		upcloud.FirewallRuleProtocolICMP)
+	if p.accept("type") {
+		if rule.Protocol != upcloud.FirewallRuleProtocolICMP {
+			return nil, fmt.Errorf("type requires the icmp protocol")
+		}
+		icmpType, err := p.next("ICMP type")
+		if err != nil {
+			return nil, err
+		}
+		if n, err := strconv.Atoi(icmpType); err != nil || n < 0 || n > 255 {
+			return nil, fmt.Errorf("invalid ICMP type %q", icmpType)
+		}
+		rule.ICMPType = icmpType
+	}
+
+	var source, destination endpoint
+	if p.accept("from") {
+		if err := p.endpoint("from", &source); err != nil {
+			return nil, err
+		}
+	}
+	if p.accept("to") {
+		if err := p.endpoint("to", &destination); err != nil {
+			return nil, err
+		}
+	}
+
+	if p.accept("comment") {
+		comment, err := p.next("comment")
+		if err != nil {
+			return nil, err
+		}
+		if rule.Comment, err = strconv.Unquote(comment); err != nil {
+			rule.Comment = comment
+		}
+	}
+
+	if p.pos < len(p.tokens) {
+		return nil, p.unexpected("end of the rule")
+	}
+
+	if (len(source.ports) > 0 || len(destination.ports) > 0) &&
+		rule.Protocol != upcloud.FirewallRuleProtocolTCP && rule.Protocol != upcloud.FirewallRuleProtocolUDP {
+		return nil, fmt.Errorf("ports require the tcp or udp protocol")
+	}
+
+	return expand(rule, source, destination)
+}
+
+// Parses the addresses and the ports following from or to
+func (p *parser) endpoint(keyword string, e *endpoint) error {
+	matchesAny := false
+	switch word := p.peek(); word {
+	case "", "port", "to", "comment":
+	case "any":
+		p.pos++
+		matchesAny = true
+	default:
+		p.pos++
+		for _, s := range strings.Split(word, ",") {
+			a, err := parseAddress(s)
+			if err != nil {
+				return err
+			}
+			e.addresses = append(e.addresses, a)
+		}
+	}
+
+	if p.accept("port") {
+		list, err := p.next("port")
+		if err != nil {
+			return err
+		}
+		for _, s := range strings.Split(list, ",") {
+			r, err := parsePortRange(s)
+			if err != nil {
+				return err
+			}
+			e.ports = append(e.ports, r)
+		}
+	}
+
+	if len(e.addresses) == 0 && len(e.ports) == 0 && !matchesAny {
+		return p.unexpected("addresses or port after " + keyword)
+	}
+
+	return nil
+}
+
+// Returns an error about the next word not being what was expected
+func (p *parser) unexpected(expected string) error {
+	if p.pos >= len(p.tokens) {
+		return fmt.Errorf("expected %s at the end of the rule", expected)
+	}
+
+	return fmt.Errorf("unexpected %q, expected %s", p.tokens[p.pos], expected)
+}
+
+// Parses a single address, a CIDR block or a range of addresses
+func parseAddress(s string) (address, error) {
+	if strings.Contains(s, "/") {
+		_, network, err := net.ParseCIDR(s)
+		if err != nil {
+			return address{}, fmt.Errorf("invalid CIDR block %q", s)
+		}
+		start := parseIP(network.IP.String())
+		return address{start: start, end: lastAddress(start, network.Mask), family: ipFamily(start)}, nil
+	}
+
+	start, end := s, s
+	if i := strings.Index(s, "-"); i >= 0 {
+		start, end = s[:i], s[i+1:]
+	}
+	startIP, endIP := parseIP(start), parseIP(end)
+	if startIP == nil || endIP == nil {
+		return address{}, fmt.Errorf("invalid address %q", s)
+	}
+	if len(startIP) != len(endIP) {
+		return address{}, fmt.Errorf("address range %q mixes IPv4 and IPv6", s)
+	}
+	if bytes.Compare(startIP, endIP) > 0 {
+		return address{}, fmt.Errorf("address range %q ends before it starts", s)
+	}
+
+	return address{start: startIP, end: endIP, family: ipFamily(startIP)}, nil
+}
+
+// Parses a single port or a range of ports
+func parsePortRange(s string) (portRange, error) {
+	start, end := s, s
+	if i := strings.Index(s, "-"); i >= 0 {
+		start, end = s[:i], s[i+1:]
+	}
+
+	var r portRange
+	var err error
+	if r.start, err = strconv.Atoi(start); err != nil || r.start < 1 || r.start > 65535 {
+		return r, fmt.Errorf("invalid port %q", s)
+	}
+	if r.end, err = strconv.Atoi(end); err != nil || r.end < 1 || r.end > 65535 {
+		return r, fmt.Errorf("invalid port %q", s)
+	}
+	if r.start > r.end {
+		return r, fmt.Errorf("port range %q ends before it starts", s)
+	}
+
+	return r, nil
+}
+
+// Expands a rule into a rule for each combination of the source and destination addresses and ports. Addresses of
+// different families aren't combined.
+func expand(rule upcloud.FirewallRule, source, destination endpoint) ([]upcloud.FirewallRule, error) {
+	for _, a := range append(append([]address(nil), source.addresses...), destination.addresses...) {
+		if rule.Family != "" && a.family != rule.Family {
+			return nil, fmt.Errorf("address %s isn't an %s address", formatAddress(a.start.String(), a.end.String()), rule.Family)
+		}
+	}
+
+	sourceAddresses, destinationAddresses := source.addresses, destination.addresses
+	if len(sourceAddresses) == 0 {
+		sourceAddresses = []address{{}}
+	}
+	if len(destinationAddresses) == 0 {
+		destinationAddresses = []address{{}}
+	}
+	sourcePorts, destinationPorts := source.ports, destination.ports
+	if len(sourcePorts) == 0 {
+		sourcePorts = []portRange{{}}
+	}
+	if len(destinationPorts) == 0 {
+		destinationPorts = []portRange{{}}
+	}
+
+	var rules []upcloud.FirewallRule
+	for _, src := range sourceAddresses {
+		for _, dst := range destinationAddresses {
+			if src.family != "" && dst.family != "" && src.family != dst.family {
+				continue
+			}
+
+			families := []string{rule.Family}
+			switch {
+			case src.family != "":
+				families = []string{src.family}
+			case dst.family != "":
+				families = []string{dst.family}
+			case rule.Family == "":
+				families = []string{upcloud.IPAddressFamilyIPv4, upcloud.IPAddressFamilyIPv6}
+			}
+
+			for _, family := range families {
+				for _, sp := range sourcePorts {
+					for _, dp := range destinationPorts {
+						r := rule
+						r.Family = family
+						r.SourceAddressStart, r.SourceAddressEnd = src.strings()
+						r.DestinationAddressStart, r.DestinationAddressEnd = dst.strings()
+						r.SourcePortStart, r.SourcePortEnd = sp.strings()
+						r.DestinationPortStart, r.DestinationPortEnd = dp.strings()
+						rules = append(rules, r)
+					}
+				}
+			}
+		}
+	}
+
+	if len(rules) == 0 {
+		return nil, fmt.Errorf("the source and destination addresses have no family in common")
+	}
+
+	return rules, nil
+}
@@ -0,0 +1,240 @@
+package firewall
+
+import (
+	"testing"
+
+	"github.com/UpCloudLtd/upcloud-go-api/upcloud"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestParse tests that rules are expanded into a firewall rule for each combination of their addresses, ports and
+// families
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name  string
+		rule  string
+		rules []upcloud.FirewallRule
+	}{
+		{
+			name: "CIDR block and port list",
+			rule: "in accept tcp from 10.0.0.0/8 to port 22,80-443",
+			rules: []upcloud.FirewallRule{
+				{
+					Direction:            upcloud.FirewallRuleDirectionIn,
+					Action:               upcloud.FirewallRuleActionAccept,
+					Family:               upcloud.IPAddressFamilyIPv4,
+					Protocol:             upcloud.FirewallRuleProtocolTCP,
+					SourceAddressStart:   "10.0.0.0",
+					SourceAddressEnd:     "10.255.255.255",
+					DestinationPortStart: "22",
+					DestinationPortEnd:   "22",
+				},
+				{
+					Direction:            upcloud.FirewallRuleDirectionIn,
+					Action:               upcloud.FirewallRuleActionAccept,
+					Family:               upcloud.IPAddressFamilyIPv4,
+					Protocol:             upcloud.FirewallRuleProtocolTCP,
+					SourceAddressStart:   "10.0.0.0",
+					SourceAddressEnd:     "10.255.255.255",
+					DestinationPortStart: "80",
+					DestinationPortEnd:   "443",
+				},
+			},
+		},
+		{
+			name: "both families",
+			rule: "In DROP",
+			rules: []upcloud.FirewallRule{
+				{Direction: upcloud.FirewallRuleDirectionIn, Action: upcloud.FirewallRuleActionDrop, Family: upcloud.IPAddressFamilyIPv4},
+				{Direction: upcloud.FirewallRuleDirectionIn, Action: upcloud.FirewallRuleActionDrop, Family: upcloud.IPAddressFamilyIPv6},
+			},
+		},
+		{
+			name: "addresses of both families",
+			rule: `out reject udp to 192.0.2.1, 2001:db8::/32 port 53 comment "No \"DNS\""`,
+			rules: []upcloud.FirewallRule{
+				{
+					Direction:               upcloud.FirewallRuleDirectionOut,
+					Action:                  upcloud.FirewallRuleActionReject,
+					Family:                  upcloud.IPAddressFamilyIPv4,
+					Protocol:                upcloud.FirewallRuleProtocolUDP,
+					DestinationAddressStart: "192.0.2.1",
+					DestinationAddressEnd:   "192.0.2.1",
+					DestinationPortStart:    "53",
+					DestinationPortEnd:      "53",
+					Comment:                 `No "DNS"`,
+				},
+				{
+					Direction:               upcloud.FirewallRuleDirectionOut,
+					Action:                  upcloud.FirewallRuleActionReject,
+					Family:                  upcloud.IPAddressFamilyIPv6,
+					Protocol:                upcloud.FirewallRuleProtocolUDP,
+					DestinationAddressStart: "2001:db8::",
+					DestinationAddressEnd:   "2001:db8:ffff:ffff:ffff:ffff:ffff:ffff",
+					DestinationPortStart:    "53",
+					DestinationPortEnd:      "53",
+					Comment:                 `No "DNS"`,
+				},
+			},
+		},
+		{
+			name: "source and destination of different families aren't combined",
+			rule: "in accept from 192.0.2.0/24,2001:db8::1 to 198.51.100.1-198.51.100.9",
+			rules: []upcloud.FirewallRule{
+				{
+					Direction:               upcloud.FirewallRuleDirectionIn,
+					Action:                  upcloud.FirewallRuleActionAccept,
+					Family:                  upcloud.IPAddressFamilyIPv4,
+					SourceAddressStart:      "192.0.2.0",
+					SourceAddressEnd:        "192.0.2.255",
+					DestinationAddressStart: "198.51.100.1",
+					DestinationAddressEnd:   "198.51.100.9",
+				},
+			},
+		},
+		{
+			name: "ICMP type",
+			rule: "in accept ipv6 icmp type 128 from any",
+			rules: []upcloud.FirewallRule{
+				{
+					Direction: upcloud.FirewallRuleDirectionIn,
+					Action:    upcloud.FirewallRuleActionAccept,
+					Family:    upcloud.IPAddressFamilyIPv6,
+					Protocol:  upcloud.FirewallRuleProtocolICMP,
+					ICMPType:  "128",
+				},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			rules, err := Parse(test.rule)
+			require.NoError(t, err)
+			assert.Equal(t, test.rules, rules)
+		})
+	}
+}
+
+// TestParseErrors tests that invalid rules are rejected with an error pointing at the problem
+func TestParseErrors(t *testing.T) {
+	tests := []struct {
+		rule string
+		err  string
+	}{
+		{"", "expected direction in or out at the end of the rule"},
+		{"inbound accept", `unexpected "inbound", expected direction in or out`},
+		{"in allow", `unexpected "allow", expected action accept, reject or drop`},
+		{"in accept tcp from", "expected addresses or port after from at the end of the rule"},
+		{"in accept tcp to port", "expected port at the end of the rule"},
+		{"in accept to port 22", "ports require the tcp or udp protocol"},
+		{"in accept tcp to port 0", `invalid port "0"`},
+		{"in accept tcp to port 443-80", `port range "443-80" ends before it starts`},
+		{"in accept from 10.0.0.0/33", `invalid CIDR block "10.0.0.0/33"`},
+		{"in accept from 10.0.0.9-10.0.0.1", `address range "10.0.0.9-10.0.0.1" ends before it starts`},
+		{"in accept from 10.0.0.1-2001:db8::1", `address range "10.0.0.1-2001:db8::1" mixes IPv4 and IPv6`},
+		{"in accept from example.com", `invalid address "example.com"`},
+		{"in accept ipv6 from 10.0.0.0/8", "address 10.0.0.0/8 isn't an IPv6 address"},
+		{"in accept from 10.0.0.1 to 2001:db8::1", "the source and destination addresses have no family in common"},
+		{"in accept tcp type 8", "type requires the icmp protocol"},
+		{"in accept icmp type echo", `invalid ICMP type "echo"`},
+		{`in accept comment "SSH`, `unterminated string "SSH`},
+		{"in accept tcp port 22", `unexpected "port", expected end of the rule`},
+	}
+
+	for _, test := range tests {
+		t.Run(test.rule, func(t *testing.T) {
+			_, err := Parse(test.rule)
+			assert.EqualError(t, err, test.err)
+		})
+	}
+}
+
+// TestParseRules tests that rules are parsed line by line and that errors mention the line
+func TestParseRules(t *testing.T) {
+	rules, err := ParseRules(`
+# SSH from the office only
+in accept tcp from 192.0.2.0/24 to port 22
+
+in drop
+`)
+	require.NoError(t, err)
+	require.Len(t, rules, 3)
+	assert.Equal(t, "22", rules[0].DestinationPortStart)
+	assert.Equal(t, upcloud.IPAddressFamilyIPv6, rules[2].Family)
+
+	_, err = ParseRules("in drop\nin accept udp to port 99999\n")
+	assert.EqualError(t, err, `line 2: invalid port "99999"`)
+}
+
+// TestFormat tests that rules are rendered in the rule syntax and parse back into the same rules
+func TestFormat(t *testing.T) {
+	tests := []struct {
+		rule   upcloud.FirewallRule
+		format string
+	}{
+		{
+			rule: upcloud.FirewallRule{
+				Direction:            upcloud.FirewallRuleDirectionIn,
+				Action:               upcloud.FirewallRuleActionAccept,
+				Family:               upcloud.IPAddressFamilyIPv4,
+				Protocol:             upcloud.FirewallRuleProtocolTCP,
+				SourceAddressStart:   "10.0.0.0",
+				SourceAddressEnd:     "10.255.255.255",
+				DestinationPortStart: "80",
+				DestinationPortEnd:   "443",
+			},
+			format: "in accept ipv4 tcp from 10.0.0.0/8 to port 80-443",
+		},
+		{
+			rule: upcloud.FirewallRule{
+				Direction:               upcloud.FirewallRuleDirectionOut,
+				Action:                  upcloud.FirewallRuleActionDrop,
+				Family:                  upcloud.IPAddressFamilyIPv6,
+				Protocol:                upcloud.FirewallRuleProtocolUDP,
+				SourcePortStart:         "1024",
+				SourcePortEnd:           "65535",
+				DestinationAddressStart: "2001:db8::1",
+				DestinationAddressEnd:   "2001:db8::1",
+				Comment:                 "Block the resolver",
+			},
+			format: `out drop ipv6 udp from port 1024-65535 to 2001:db8::1 comment "Block the resolver"`,
+		},
+		{
+			rule: upcloud.FirewallRule{
+				Direction:          upcloud.FirewallRuleDirectionIn,
+				Action:             upcloud.FirewallRuleActionReject,
+				Family:             upcloud.IPAddressFamilyIPv4,
+				Protocol:           upcloud.FirewallRuleProtocolICMP,
+				ICMPType:           "8",
+				SourceAddressStart: "192.0.2.10",
+				SourceAddressEnd:   "192.0.2.20",
+			},
+			format: "in reject ipv4 icmp type 8 from 192.0.2.10-192.0.2.20",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.format, func(t *testing.T) {
+			assert.Equal(t, test.format, Format(test.rule))
+
+			rules, err := Parse(test.format)
+			require.NoError(t, err)
+			assert.Equal(t, []upcloud.FirewallRule{test.rule}, rules)
+		})
+	}
+
+	assert.Equal(t, "in accept ipv4 tcp to port 22\nin drop ipv4\n", FormatRules([]upcloud.FirewallRule{
+		{
+			Direction:            upcloud.FirewallRuleDirectionIn,
+			Action:               upcloud.FirewallRuleActionAccept,
+			Family:               upcloud.IPAddressFamilyIPv4,
+			Protocol:             upcloud.FirewallRuleProtocolTCP,
+			DestinationPortStart: "22",
+			DestinationPortEnd:   "22",
+			Position:             1,
+		},
+		{Direction: upcloud.FirewallRuleDirectionIn, Action: upcloud.FirewallRuleActionDrop, Family: upcloud.IPAddressFamilyIPv4},
+	}))
+}
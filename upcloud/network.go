@@ -87,8 +87,8 @@ func (s *Interface) UnmarshalJSON(b []byte) error {
 // IPNetwork represents an IP network in a response.
 type IPNetwork struct {
 	Address          string   `json:"address,omitempty"`
-	DHCP             Boolean  `json:"dhcp"`
-	DHCPDefaultRoute Boolean  `json:"dhcp_default_route"`
+	DHCP             Boolean  `json:"dhcp,omitempty"`
+	DHCPDefaultRoute Boolean  `json:"dhcp_default_route,omitempty"`
 	DHCPDns          []string `json:"dhcp_dns,omitempty"`
 	Family           string   `json:"family,omitempty"`
 	Gateway          string   `json:"gateway,omitempty"`
@@ -2,6 +2,12 @@ package upcloud
 
 import "encoding/json"
 
+// Constants
+const (
+	ObjectStorageStateStarted = "started"
+	ObjectStorageStateStopped = "stopped"
+)
+
 // ObjectStorage represents a Object Storage
 type ObjectStorage struct {
 	Created     string `json:"created"`
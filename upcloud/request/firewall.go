@@ -17,6 +17,13 @@ func (r *GetFirewallRulesRequest) RequestURL() string {
 	return fmt.Sprintf("/server/%s/firewall_rule", r.ServerUUID)
 }
 
+// Validate implements the Validator interface
+func (r *GetFirewallRulesRequest) Validate() error {
+	v := validator{}
+	v.required("ServerUUID", r.ServerUUID)
+	return v.result("GetFirewallRulesRequest")
+}
+
 // GetFirewallRuleDetailsRequest represents a request to get details about a specific firewall rule
 type GetFirewallRuleDetailsRequest struct {
 	ServerUUID string
@@ -28,6 +35,14 @@ func (r *GetFirewallRuleDetailsRequest) RequestURL() string {
 	return fmt.Sprintf("/server/%s/firewall_rule/%d", r.ServerUUID, r.Position)
 }
 
+// Validate implements the Validator interface
+func (r *GetFirewallRuleDetailsRequest) Validate() error {
+	v := validator{}
+	v.required("ServerUUID", r.ServerUUID)
+	v.positive("Position", r.Position)
+	return v.result("GetFirewallRuleDetailsRequest")
+}
+
 // CreateFirewallRuleRequest represents a request to create a new firewall rule for a specific server
 type CreateFirewallRuleRequest struct {
 	upcloud.FirewallRule
@@ -40,6 +55,14 @@ func (r *CreateFirewallRuleRequest) RequestURL() string {
 	return fmt.Sprintf("/server/%s/firewall_rule", r.ServerUUID)
 }
 
+// Validate implements the Validator interface
+func (r *CreateFirewallRuleRequest) Validate() error {
+	v := validator{}
+	v.required("ServerUUID", r.ServerUUID)
+	v.firewallRule("", &r.FirewallRule)
+	return v.result("CreateFirewallRuleRequest")
+}
+
 // MarshalJSON is a custom marshaller that deals with
 // deeply embedded values.
 func (r CreateFirewallRuleRequest) MarshalJSON() ([]byte, error) {
@@ -63,6 +86,14 @@ func (r *DeleteFirewallRuleRequest) RequestURL() string {
 	return fmt.Sprintf("/server/%s/firewall_rule/%d", r.ServerUUID, r.Position)
 }
 
+// Validate implements the Validator interface
+func (r *DeleteFirewallRuleRequest) Validate() error {
+	v := validator{}
+	v.required("ServerUUID", r.ServerUUID)
+	v.positive("Position", r.Position)
+	return v.result("DeleteFirewallRuleRequest")
+}
+
 // FirewallRuleSlice is a slice of firewall rules
 // It exists to allow for a custom JSON marshaller.
 type FirewallRuleSlice []upcloud.FirewallRule
@@ -90,3 +121,47 @@ type CreateFirewallRulesRequest struct {
 func (r *CreateFirewallRulesRequest) RequestURL() string {
 	return fmt.Sprintf("/server/%s/firewall_rule", r.ServerUUID)
 }
+
+// Validate implements the Validator interface
+func (r *CreateFirewallRulesRequest) Validate() error {
+	v := validator{}
+	v.required("ServerUUID", r.ServerUUID)
+
+	for i := range r.FirewallRules {
+		v.firewallRule(fmt.Sprintf("FirewallRules[%d]", i), &r.FirewallRules[i])
+	}
+	return v.result("CreateFirewallRulesRequest")
+}
+
+// Constants
+const (
+	FirewallRulesSyncModeIncremental = "incremental"
+	FirewallRulesSyncModeReplace     = "replace"
+)
+
+// SyncFirewallRulesRequest represents a request to bring the firewall rules of a server to the given rules.
+// The positions of the rules are ignored, the rules are applied in the order they're given.
+type SyncFirewallRulesRequest struct {
+	ServerUUID    string
+	FirewallRules []upcloud.FirewallRule
+	// Mode is either FirewallRulesSyncModeIncremental, the default, which creates and deletes only the rules that
+	// differ, or FirewallRulesSyncModeReplace, which replaces the whole rule set when any of the rules differ
+	Mode string
+}
+
+// RequestURL implements the Request interface
+func (r *SyncFirewallRulesRequest) RequestURL() string {
+	return fmt.Sprintf("/server/%s/firewall_rule", r.ServerUUID)
+}
+
+// Validate implements the Validator interface
+func (r *SyncFirewallRulesRequest) Validate() error {
+	v := validator{}
+	v.required("ServerUUID", r.ServerUUID)
+	v.oneOf("Mode", r.Mode, FirewallRulesSyncModeIncremental, FirewallRulesSyncModeReplace)
+
+	for i := range r.FirewallRules {
+		v.firewallRule(fmt.Sprintf("FirewallRules[%d]", i), &r.FirewallRules[i])
+	}
+	return v.result("SyncFirewallRulesRequest")
+}
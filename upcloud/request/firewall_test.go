@@ -140,3 +140,88 @@ func TestCreateFirewallRulesRequest(t *testing.T) {
 	assert.JSONEq(t, expectedJSON, string(actualJSON))
 	assert.Equal(t, "/server/foo/firewall_rule", request.RequestURL())
 }
+
+// TestCreateFirewallRuleRequest_Validate tests that the enums, addresses and port ranges of a firewall rule are
+// validated
+func TestCreateFirewallRuleRequest_Validate(t *testing.T) {
+	request := CreateFirewallRuleRequest{
+		FirewallRule: upcloud.FirewallRule{
+			Direction:            upcloud.FirewallRuleDirectionIn,
+			Action:               upcloud.FirewallRuleActionAccept,
+			Family:               upcloud.IPAddressFamilyIPv4,
+			Protocol:             upcloud.FirewallRuleProtocolTCP,
+			SourceAddressStart:   "10.0.0.0",
+			SourceAddressEnd:     "10.255.255.255",
+			DestinationPortStart: "22",
+			DestinationPortEnd:   "22",
+		},
+		ServerUUID: "00798b85-efdc-41ca-8021-f6ef457b8531",
+	}
+	assert.NoError(t, request.Validate())
+
+	request.Action = "allow"
+	request.SourceAddressEnd = "fe80::1"
+	request.DestinationPortStart = "443"
+	request.DestinationPortEnd = "80"
+
+	assert.EqualError(t, request.Validate(), "invalid CreateFirewallRuleRequest: "+
+		`Action must be one of accept, reject, drop, got "allow"; `+
+		`SourceAddressEnd is not an IPv4 address: "fe80::1"; `+
+		"DestinationPortEnd must not be lower than DestinationPortStart")
+
+	request.Action = upcloud.FirewallRuleActionDrop
+	request.SourceAddressEnd = ""
+	request.Protocol = upcloud.FirewallRuleProtocolICMP
+	request.DestinationPortStart = "0"
+	request.DestinationPortEnd = "70000"
+
+	assert.EqualError(t, request.Validate(), "invalid CreateFirewallRuleRequest: "+
+		"Protocol must be tcp or udp when ports are specified; "+
+		`DestinationPortStart must be a port between 1 and 65535, got "0"; `+
+		`DestinationPortEnd must be a port between 1 and 65535, got "70000"`)
+}
+
+// TestCreateFirewallRulesRequest_Validate tests that errors in a rule set point at the offending rule
+func TestCreateFirewallRulesRequest_Validate(t *testing.T) {
+	request := CreateFirewallRulesRequest{
+		ServerUUID: "00798b85-efdc-41ca-8021-f6ef457b8531",
+		FirewallRules: []upcloud.FirewallRule{
+			{
+				Direction: upcloud.FirewallRuleDirectionIn,
+				Action:    upcloud.FirewallRuleActionAccept,
+				Family:    upcloud.IPAddressFamilyIPv6,
+			},
+			{
+				Direction: upcloud.FirewallRuleDirectionOut,
+				Action:    upcloud.FirewallRuleActionDrop,
+			},
+		},
+	}
+
+	assert.EqualError(t, request.Validate(), "invalid CreateFirewallRulesRequest: FirewallRules[1].Family is required")
+}
+
+// TestSyncFirewallRulesRequest tests that SyncFirewallRulesRequest behaves correctly
+func TestSyncFirewallRulesRequest(t *testing.T) {
+	request := SyncFirewallRulesRequest{
+		ServerUUID: "00798b85-efdc-41ca-8021-f6ef457b8531",
+		FirewallRules: []upcloud.FirewallRule{
+			{
+				Direction: upcloud.FirewallRuleDirectionIn,
+				Action:    upcloud.FirewallRuleActionAccept,
+				Family:    upcloud.IPAddressFamilyIPv4,
+			},
+		},
+	}
+
+	assert.Equal(t, "/server/00798b85-efdc-41ca-8021-f6ef457b8531/firewall_rule", request.RequestURL())
+	assert.NoError(t, request.Validate())
+
+	request.Mode = FirewallRulesSyncModeReplace
+	assert.NoError(t, request.Validate())
+
+	request.Mode = "append"
+	request.FirewallRules[0].Family = ""
+	assert.EqualError(t, request.Validate(), "invalid SyncFirewallRulesRequest: "+
+		`Mode must be one of incremental, replace, got "append"; FirewallRules[0].Family is required`)
+}
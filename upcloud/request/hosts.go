@@ -16,6 +16,13 @@ func (r *GetHostDetailsRequest) RequestURL() string {
 	return fmt.Sprintf("/host/%d", r.ID)
 }
 
+// Validate implements the Validator interface
+func (r *GetHostDetailsRequest) Validate() error {
+	v := validator{}
+	v.positive("ID", r.ID)
+	return v.result("GetHostDetailsRequest")
+}
+
 // ModifyHostRequest represents the request to modify a private host
 type ModifyHostRequest struct {
 	ID          int    `json:"-"`
@@ -27,6 +34,13 @@ func (r *ModifyHostRequest) RequestURL() string {
 	return fmt.Sprintf("/host/%d", r.ID)
 }
 
+// Validate implements the Validator interface
+func (r *ModifyHostRequest) Validate() error {
+	v := validator{}
+	v.positive("ID", r.ID)
+	return v.result("ModifyHostRequest")
+}
+
 // MarshalJSON is a custom marshaller that deals with
 // deeply embedded values.
 func (r ModifyHostRequest) MarshalJSON() ([]byte, error) {
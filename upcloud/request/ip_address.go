@@ -17,6 +17,13 @@ func (r *GetIPAddressDetailsRequest) RequestURL() string {
 	return fmt.Sprintf("/ip_address/%s", r.Address)
 }
 
+// Validate implements the Validator interface
+func (r *GetIPAddressDetailsRequest) Validate() error {
+	v := validator{}
+	v.required("Address", r.Address)
+	return v.result("GetIPAddressDetailsRequest")
+}
+
 // AssignIPAddressRequest represents a request to assign a new IP address to a server
 type AssignIPAddressRequest struct {
 	Access     string          `json:"access,omitempty"`
@@ -32,6 +39,20 @@ func (r *AssignIPAddressRequest) RequestURL() string {
 	return "/ip_address"
 }
 
+// Validate implements the Validator interface
+func (r *AssignIPAddressRequest) Validate() error {
+	v := validator{}
+	v.oneOf("Access", r.Access, upcloud.IPAddressAccessPrivate, upcloud.IPAddressAccessPublic)
+	v.oneOf("Family", r.Family, upcloud.IPAddressFamilyIPv4, upcloud.IPAddressFamilyIPv6)
+
+	if r.Floating.Bool() {
+		v.required("Zone", r.Zone)
+	} else {
+		v.required("ServerUUID", r.ServerUUID)
+	}
+	return v.result("AssignIPAddressRequest")
+}
+
 // MarshalJSON is a custom marshaller that deals with
 // deeply embedded values.
 func (r AssignIPAddressRequest) MarshalJSON() ([]byte, error) {
@@ -44,7 +65,8 @@ func (r AssignIPAddressRequest) MarshalJSON() ([]byte, error) {
 	return json.Marshal(&v)
 }
 
-// ModifyIPAddressRequest represents a request to modify the PTR DNS record of a specific IP address
+// ModifyIPAddressRequest represents a request to modify the PTR DNS record of a specific IP address. Only the fields
+// that are set are sent. A request without a PTR record and a MAC address unassigns a floating IP address.
 type ModifyIPAddressRequest struct {
 	IPAddress string `json:"-"`
 
@@ -57,6 +79,14 @@ func (r *ModifyIPAddressRequest) RequestURL() string {
 	return fmt.Sprintf("/ip_address/%s", r.IPAddress)
 }
 
+// Validate implements the Validator interface
+func (r *ModifyIPAddressRequest) Validate() error {
+	v := validator{}
+	v.required("IPAddress", r.IPAddress)
+	v.hostname("PTRRecord", r.PTRRecord)
+	return v.result("ModifyIPAddressRequest")
+}
+
 // MarshalJSON is a custom marshaller that deals with
 // deeply embedded values.
 func (r ModifyIPAddressRequest) MarshalJSON() ([]byte, error) {
@@ -88,3 +118,10 @@ type ReleaseIPAddressRequest struct {
 func (r *ReleaseIPAddressRequest) RequestURL() string {
 	return fmt.Sprintf("/ip_address/%s", r.IPAddress)
 }
+
+// Validate implements the Validator interface
+func (r *ReleaseIPAddressRequest) Validate() error {
+	v := validator{}
+	v.required("IPAddress", r.IPAddress)
+	return v.result("ReleaseIPAddressRequest")
+}
@@ -18,6 +18,13 @@ func (r *GetNetworksInZoneRequest) RequestURL() string {
 	return fmt.Sprintf("/network/?zone=%s", r.Zone)
 }
 
+// Validate implements the Validator interface
+func (r *GetNetworksInZoneRequest) Validate() error {
+	v := validator{}
+	v.required("Zone", r.Zone)
+	return v.result("GetNetworksInZoneRequest")
+}
+
 // GetNetworkDetailsRequest represents a request to the the details of
 // a single network.
 type GetNetworkDetailsRequest struct {
@@ -29,6 +36,13 @@ func (r *GetNetworkDetailsRequest) RequestURL() string {
 	return fmt.Sprintf("/network/%s", r.UUID)
 }
 
+// Validate implements the Validator interface
+func (r *GetNetworkDetailsRequest) Validate() error {
+	v := validator{}
+	v.required("UUID", r.UUID)
+	return v.result("GetNetworkDetailsRequest")
+}
+
 // CreateNetworkRequest represents a request to create a new network.
 type CreateNetworkRequest struct {
 	Name       string                 `json:"name,omitempty"`
@@ -42,6 +56,21 @@ func (r *CreateNetworkRequest) RequestURL() string {
 	return "/network/"
 }
 
+// Validate implements the Validator interface
+func (r *CreateNetworkRequest) Validate() error {
+	v := validator{}
+	v.required("Name", r.Name)
+	v.required("Zone", r.Zone)
+
+	if len(r.IPNetworks) == 0 {
+		v.add("IPNetworks", "must contain at least one IP network")
+	}
+	for i := range r.IPNetworks {
+		v.ipNetwork(fmt.Sprintf("IPNetworks[%d]", i), &r.IPNetworks[i])
+	}
+	return v.result("CreateNetworkRequest")
+}
+
 // MarshalJSON is a custom marshaller that deals with
 // deeply embedded values.
 func (r CreateNetworkRequest) MarshalJSON() ([]byte, error) {
@@ -69,6 +98,17 @@ func (r *ModifyNetworkRequest) RequestURL() string {
 	return fmt.Sprintf("/network/%s", r.UUID)
 }
 
+// Validate implements the Validator interface
+func (r *ModifyNetworkRequest) Validate() error {
+	v := validator{}
+	v.required("UUID", r.UUID)
+
+	for i := range r.IPNetworks {
+		v.ipNetwork(fmt.Sprintf("IPNetworks[%d]", i), &r.IPNetworks[i])
+	}
+	return v.result("ModifyNetworkRequest")
+}
+
 // MarshalJSON is a custom marshaller that deals with
 // deeply embedded values.
 func (r ModifyNetworkRequest) MarshalJSON() ([]byte, error) {
@@ -91,6 +131,13 @@ func (r *DeleteNetworkRequest) RequestURL() string {
 	return fmt.Sprintf("/network/%s", r.UUID)
 }
 
+// Validate implements the Validator interface
+func (r *DeleteNetworkRequest) Validate() error {
+	v := validator{}
+	v.required("UUID", r.UUID)
+	return v.result("DeleteNetworkRequest")
+}
+
 // GetServerNetworksRequest represents a request to get the networks
 // a server is part of.
 type GetServerNetworksRequest struct {
@@ -102,6 +149,13 @@ func (r *GetServerNetworksRequest) RequestURL() string {
 	return fmt.Sprintf("/server/%s/networking", r.ServerUUID)
 }
 
+// Validate implements the Validator interface
+func (r *GetServerNetworksRequest) Validate() error {
+	v := validator{}
+	v.required("ServerUUID", r.ServerUUID)
+	return v.result("GetServerNetworksRequest")
+}
+
 // CreateNetworkInterfaceIPAddress represents an IP Address object
 // that is needed to create a network interface.
 type CreateNetworkInterfaceIPAddress struct {
@@ -143,6 +197,32 @@ func (r *CreateNetworkInterfaceRequest) RequestURL() string {
 	return fmt.Sprintf("/server/%s/networking/interface", r.ServerUUID)
 }
 
+// Validate implements the Validator interface
+func (r *CreateNetworkInterfaceRequest) Validate() error {
+	v := validator{}
+	v.required("ServerUUID", r.ServerUUID)
+	v.required("Type", r.Type)
+	v.oneOf("Type", r.Type, upcloud.NetworkTypePrivate, upcloud.NetworkTypePublic, upcloud.NetworkTypeUtility)
+
+	if r.Type == upcloud.NetworkTypePrivate {
+		v.required("NetworkUUID", r.NetworkUUID)
+	}
+	if r.Index < 0 {
+		v.add("Index", "must not be negative, got %d", r.Index)
+	}
+
+	if len(r.IPAddresses) == 0 {
+		v.add("IPAddresses", "must contain at least one IP address")
+	}
+	for i, ip := range r.IPAddresses {
+		field := fmt.Sprintf("IPAddresses[%d]", i)
+		v.required(field+".Family", ip.Family)
+		v.oneOf(field+".Family", ip.Family, upcloud.IPAddressFamilyIPv4, upcloud.IPAddressFamilyIPv6)
+		v.ipAddress(field+".Address", ip.Address, ip.Family)
+	}
+	return v.result("CreateNetworkInterfaceRequest")
+}
+
 // MarshalJSON is a custom marshaller that deals with
 // deeply embedded values.
 func (r CreateNetworkInterfaceRequest) MarshalJSON() ([]byte, error) {
@@ -166,6 +246,14 @@ func (r *DeleteNetworkInterfaceRequest) RequestURL() string {
 	return fmt.Sprintf("/server/%s/networking/interface/%d", r.ServerUUID, r.Index)
 }
 
+// Validate implements the Validator interface
+func (r *DeleteNetworkInterfaceRequest) Validate() error {
+	v := validator{}
+	v.required("ServerUUID", r.ServerUUID)
+	v.positive("Index", r.Index)
+	return v.result("DeleteNetworkInterfaceRequest")
+}
+
 // ModifyNetworkInterfaceRequest represents a request to modify a network interface on a server.
 type ModifyNetworkInterfaceRequest struct {
 	ServerUUID   string `json:"-"`
@@ -184,6 +272,26 @@ func (r *ModifyNetworkInterfaceRequest) RequestURL() string {
 	return fmt.Sprintf("/server/%s/networking/interface/%d", r.ServerUUID, r.CurrentIndex)
 }
 
+// Validate implements the Validator interface
+func (r *ModifyNetworkInterfaceRequest) Validate() error {
+	v := validator{}
+	v.required("ServerUUID", r.ServerUUID)
+	v.positive("CurrentIndex", r.CurrentIndex)
+	v.oneOf("Type", r.Type, upcloud.NetworkTypePrivate, upcloud.NetworkTypePublic, upcloud.NetworkTypeUtility)
+
+	if r.NewIndex < 0 {
+		v.add("NewIndex", "must not be negative, got %d", r.NewIndex)
+	}
+
+	for i, ip := range r.IPAddresses {
+		field := fmt.Sprintf("IPAddresses[%d]", i)
+		v.required(field+".Family", ip.Family)
+		v.oneOf(field+".Family", ip.Family, upcloud.IPAddressFamilyIPv4, upcloud.IPAddressFamilyIPv6)
+		v.ipAddress(field+".Address", ip.Address, ip.Family)
+	}
+	return v.result("ModifyNetworkInterfaceRequest")
+}
+
 // MarshalJSON is a custom marshaller that deals with
 // deeply embedded values.
 func (r ModifyNetworkInterfaceRequest) MarshalJSON() ([]byte, error) {
@@ -206,6 +314,13 @@ func (r *GetRouterDetailsRequest) RequestURL() string {
 	return fmt.Sprintf("/router/%s", r.UUID)
 }
 
+// Validate implements the Validator interface
+func (r *GetRouterDetailsRequest) Validate() error {
+	v := validator{}
+	v.required("UUID", r.UUID)
+	return v.result("GetRouterDetailsRequest")
+}
+
 // CreateRouterRequest represents a request to create a new router.
 type CreateRouterRequest struct {
 	Name string `json:"name"`
@@ -216,6 +331,13 @@ func (r *CreateRouterRequest) RequestURL() string {
 	return "/router"
 }
 
+// Validate implements the Validator interface
+func (r *CreateRouterRequest) Validate() error {
+	v := validator{}
+	v.required("Name", r.Name)
+	return v.result("CreateRouterRequest")
+}
+
 // MarshalJSON is a custom marshaller that deals with
 // deeply embedded values.
 func (r CreateRouterRequest) MarshalJSON() ([]byte, error) {
@@ -240,6 +362,14 @@ func (r *ModifyRouterRequest) RequestURL() string {
 	return fmt.Sprintf("/router/%s", r.UUID)
 }
 
+// Validate implements the Validator interface
+func (r *ModifyRouterRequest) Validate() error {
+	v := validator{}
+	v.required("UUID", r.UUID)
+	v.required("Name", r.Name)
+	return v.result("ModifyRouterRequest")
+}
+
 // MarshalJSON is a custom marshaller that deals with
 // deeply embedded values.
 func (r ModifyRouterRequest) MarshalJSON() ([]byte, error) {
@@ -261,3 +391,10 @@ type DeleteRouterRequest struct {
 func (r *DeleteRouterRequest) RequestURL() string {
 	return fmt.Sprintf("/router/%s", r.UUID)
 }
+
+// Validate implements the Validator interface
+func (r *DeleteRouterRequest) Validate() error {
+	v := validator{}
+	v.required("UUID", r.UUID)
+	return v.result("DeleteRouterRequest")
+}
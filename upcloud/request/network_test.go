@@ -100,7 +100,6 @@ func TestMarshalModifyNetworkRequest(t *testing.T) {
 			  "ip_network": [
 				{
 				  "dhcp": "no",
-				  "dhcp_default_route": "no",
 				  "family" : "IPv4"
 				}
 			  ]
@@ -115,6 +114,33 @@ func TestMarshalModifyNetworkRequest(t *testing.T) {
 	assert.JSONEq(t, expectedJSON, string(actualJSON))
 }
 
+// TestMarshalModifyNetworkRequest_OnlySetFields tests that a ModifyNetworkRequest only sends the fields that are set
+func TestMarshalModifyNetworkRequest_OnlySetFields(t *testing.T) {
+	request := ModifyNetworkRequest{
+		UUID: "foo",
+		Name: "Renamed network",
+	}
+
+	actualJSON, err := json.Marshal(&request)
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"network": {"name": "Renamed network"}}`, string(actualJSON))
+
+	request = ModifyNetworkRequest{
+		UUID: "foo",
+		IPNetworks: []upcloud.IPNetwork{
+			{
+				DHCPDefaultRoute: upcloud.True,
+				Family:           upcloud.IPAddressFamilyIPv4,
+			},
+		},
+	}
+
+	actualJSON, err = json.Marshal(&request)
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"network": {"ip_networks": {"ip_network": [{"dhcp_default_route": "yes", "family": "IPv4"}]}}}`,
+		string(actualJSON))
+}
+
 // TestMarshalDeleteNetwork tests the DeleteNetworkRequest behaves correctly
 func TestMarshalDeleteNetwork(t *testing.T) {
 	request := DeleteNetworkRequest{
@@ -226,6 +252,20 @@ func TestMarshalModifyNetworkInterfaceRequest(t *testing.T) {
 	assert.Equal(t, "/server/foo/networking/interface/99", request.RequestURL())
 }
 
+// TestMarshalModifyNetworkInterfaceRequest_OnlySetFields tests that a ModifyNetworkInterfaceRequest only sends the
+// fields that are set
+func TestMarshalModifyNetworkInterfaceRequest_OnlySetFields(t *testing.T) {
+	request := ModifyNetworkInterfaceRequest{
+		ServerUUID:   "foo",
+		CurrentIndex: 2,
+		Bootable:     upcloud.False,
+	}
+
+	actualJSON, err := json.Marshal(&request)
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"interface": {"bootable": "no"}}`, string(actualJSON))
+}
+
 // TestMarshalGetRouterDetailsRequest tests that GetRouterDetailsRequest behaves correctly.
 func TestMarshalGetRouterDetailsRequest(t *testing.T) {
 	request := GetRouterDetailsRequest{
@@ -279,3 +319,49 @@ func TestMarshalModifyRouterRequest(t *testing.T) {
 
 	assert.Equal(t, "/router/foo", request.RequestURL())
 }
+
+// TestCreateNetworkRequest_Validate tests that the IP networks of a CreateNetworkRequest are validated
+func TestCreateNetworkRequest_Validate(t *testing.T) {
+	request := CreateNetworkRequest{
+		Name: "Test private net",
+		Zone: "fi-hel1",
+		IPNetworks: []upcloud.IPNetwork{
+			{
+				Address: "172.16.0.0/22",
+				DHCP:    upcloud.True,
+				DHCPDns: []string{"172.16.0.10", "172.16.1.10"},
+				Family:  upcloud.IPAddressFamilyIPv4,
+				Gateway: "172.16.0.1",
+			},
+		},
+	}
+	assert.NoError(t, request.Validate())
+
+	request.IPNetworks[0].Address = "172.16.0.0"
+	request.IPNetworks[0].Gateway = "fe80::1"
+	request.IPNetworks[0].DHCPDns = []string{"dns.example.com"}
+
+	assert.EqualError(t, request.Validate(), "invalid CreateNetworkRequest: "+
+		`IPNetworks[0].Address is not a valid CIDR network: "172.16.0.0"; `+
+		`IPNetworks[0].Gateway is not an IPv4 address: "fe80::1"; `+
+		`IPNetworks[0].DHCPDns[0] is not a valid IP address: "dns.example.com"`)
+}
+
+// TestCreateNetworkInterfaceRequest_Validate tests that a private interface requires a network
+func TestCreateNetworkInterfaceRequest_Validate(t *testing.T) {
+	request := CreateNetworkInterfaceRequest{
+		ServerUUID: "foo",
+		Type:       upcloud.NetworkTypePrivate,
+		IPAddresses: []CreateNetworkInterfaceIPAddress{
+			{
+				Family:  upcloud.IPAddressFamilyIPv4,
+				Address: "10.0.0.20",
+			},
+		},
+	}
+
+	assert.EqualError(t, request.Validate(), "invalid CreateNetworkInterfaceRequest: NetworkUUID is required")
+
+	request.NetworkUUID = "03e4970d-7791-4b80-a892-682ae0faf46b"
+	assert.NoError(t, request.Validate())
+}
@@ -3,6 +3,9 @@ package request
 import (
 	"encoding/json"
 	"fmt"
+	"time"
+
+	"github.com/UpCloudLtd/upcloud-go-api/upcloud"
 )
 
 // GetObjectStorageDetailsRequest represents a request for retrieving details about a Object Storage device
@@ -15,6 +18,13 @@ func (r *GetObjectStorageDetailsRequest) RequestURL() string {
 	return fmt.Sprintf("/object-storage/%s", r.UUID)
 }
 
+// Validate implements the Validator interface
+func (r *GetObjectStorageDetailsRequest) Validate() error {
+	v := validator{}
+	v.required("UUID", r.UUID)
+	return v.result("GetObjectStorageDetailsRequest")
+}
+
 // CreateObjectStorageRequest represents a request for creating a new Object Storage device
 type CreateObjectStorageRequest struct {
 	Name        string `json:"name,omitempty"`
@@ -42,6 +52,17 @@ func (r *CreateObjectStorageRequest) RequestURL() string {
 	return "/object-storage"
 }
 
+// Validate implements the Validator interface
+func (r *CreateObjectStorageRequest) Validate() error {
+	v := validator{}
+	v.required("Name", r.Name)
+	v.required("Zone", r.Zone)
+	v.required("AccessKey", r.AccessKey)
+	v.required("SecretKey", r.SecretKey)
+	v.intRange("Size", r.Size, MinObjectStorageSize, MaxObjectStorageSize)
+	return v.result("CreateObjectStorageRequest")
+}
+
 // ModifyObjectStorageRequest represents a request to modify a Object Storage
 type ModifyObjectStorageRequest struct {
 	UUID        string `json:"-"`
@@ -68,6 +89,17 @@ func (r *ModifyObjectStorageRequest) RequestURL() string {
 	return fmt.Sprintf("/object-storage/%s", r.UUID)
 }
 
+// Validate implements the Validator interface
+func (r *ModifyObjectStorageRequest) Validate() error {
+	v := validator{}
+	v.required("UUID", r.UUID)
+
+	if r.Size != 0 {
+		v.intRange("Size", r.Size, MinObjectStorageSize, MaxObjectStorageSize)
+	}
+	return v.result("ModifyObjectStorageRequest")
+}
+
 // DeleteObjectStorageRequest represents a request to delete a Object Storage
 type DeleteObjectStorageRequest struct {
 	UUID string
@@ -77,3 +109,33 @@ type DeleteObjectStorageRequest struct {
 func (r *DeleteObjectStorageRequest) RequestURL() string {
 	return fmt.Sprintf("/object-storage/%s", r.UUID)
 }
+
+// Validate implements the Validator interface
+func (r *DeleteObjectStorageRequest) Validate() error {
+	v := validator{}
+	v.required("UUID", r.UUID)
+	return v.result("DeleteObjectStorageRequest")
+}
+
+// WaitForObjectStorageStateRequest represents a request to wait for a Object Storage to enter a specific state
+type WaitForObjectStorageStateRequest struct {
+	UUID         string
+	DesiredState string
+	Timeout      time.Duration
+}
+
+// Validate implements the Validator interface
+func (r *WaitForObjectStorageStateRequest) Validate() error {
+	v := validator{}
+	v.required("UUID", r.UUID)
+	v.required("DesiredState", r.DesiredState)
+	v.oneOf("DesiredState", r.DesiredState,
+		upcloud.ObjectStorageStateStarted,
+		upcloud.ObjectStorageStateStopped,
+	)
+
+	if r.Timeout <= 0 {
+		v.add("Timeout", "must be positive, got %s", r.Timeout)
+	}
+	return v.result("WaitForObjectStorageStateRequest")
+}
@@ -71,6 +71,19 @@ func TestModifyObjectStorageRequest(t *testing.T) {
 	assert.Equal(t, "/object-storage/foo", request.RequestURL())
 }
 
+// TestModifyObjectStorageRequest_OnlySetFields tests that a ModifyObjectStorageRequest only sends the fields that
+// are set, leaving the keys untouched
+func TestModifyObjectStorageRequest_OnlySetFields(t *testing.T) {
+	request := ModifyObjectStorageRequest{
+		UUID: "foo",
+		Size: 500,
+	}
+
+	actualJSON, err := json.Marshal(&request)
+	assert.Nil(t, err)
+	assert.JSONEq(t, `{"object_storage": {"size": 500}}`, string(actualJSON))
+}
+
 // TestDeleteObjectStorageRequest tests that DeleteObjectStorageRequest objects behave correctly
 func TestDeleteObjectStorageRequest(t *testing.T) {
 	request := DeleteObjectStorageRequest{
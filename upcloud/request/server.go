@@ -36,6 +36,13 @@ func (r *GetServerDetailsRequest) RequestURL() string {
 	return fmt.Sprintf("/server/%s", r.UUID)
 }
 
+// Validate implements the Validator interface
+func (r *GetServerDetailsRequest) Validate() error {
+	v := validator{}
+	v.required("UUID", r.UUID)
+	return v.result("GetServerDetailsRequest")
+}
+
 // CreateServerIPAddressSlice is a slice of strings
 // It exists to allow for a custom JSON marshaller.
 type CreateServerIPAddressSlice []CreateServerIPAddress
@@ -145,6 +152,14 @@ func (r CreateServerRequest) MarshalJSON() ([]byte, error) {
 	}{}
 	v.Server = localCreateServerRequest(r)
 
+	// Unset booleans are sent as "no" when creating a server, as they've always been
+	if v.Server.Metadata.Empty() {
+		v.Server.Metadata = upcloud.False
+	}
+	if v.Server.RemoteAccessEnabled.Empty() {
+		v.Server.RemoteAccessEnabled = upcloud.False
+	}
+
 	return json.Marshal(&v)
 }
 
@@ -153,6 +168,72 @@ func (r *CreateServerRequest) RequestURL() string {
 	return "/server"
 }
 
+// Validate implements the Validator interface
+func (r *CreateServerRequest) Validate() error {
+	v := validator{}
+	v.required("Zone", r.Zone)
+	v.required("Title", r.Title)
+	v.maxLength("Title", r.Title, MaxTitleLength)
+	v.required("Hostname", r.Hostname)
+	v.hostname("Hostname", r.Hostname)
+	v.oneOf("PasswordDelivery", r.PasswordDelivery, PasswordDeliveryNone, PasswordDeliveryEmail, PasswordDeliverySMS)
+	v.oneOf("VideoModel", r.VideoModel, upcloud.VideoModelVGA, upcloud.VideoModelCirrus)
+	v.oneOf("RemoteAccessType", r.RemoteAccessType, upcloud.RemoteAccessTypeVNC, upcloud.RemoteAccessTypeSPICE)
+
+	if r.CoreNumber < 0 {
+		v.add("CoreNumber", "must not be negative, got %d", r.CoreNumber)
+	}
+	if r.MemoryAmount < 0 {
+		v.add("MemoryAmount", "must not be negative, got %d", r.MemoryAmount)
+	}
+
+	if len(r.StorageDevices) == 0 {
+		v.add("StorageDevices", "must contain at least one storage device")
+	}
+	for i, device := range r.StorageDevices {
+		field := fmt.Sprintf("StorageDevices[%d]", i)
+		v.required(field+".Action", device.Action)
+		v.oneOf(field+".Action", device.Action,
+			CreateServerStorageDeviceActionCreate,
+			CreateServerStorageDeviceActionClone,
+			CreateServerStorageDeviceActionAttach,
+		)
+		v.oneOf(field+".Tier", device.Tier, upcloud.StorageTierHDD, upcloud.StorageTierMaxIOPS)
+		v.oneOf(field+".Type", device.Type, upcloud.StorageTypeDisk, upcloud.StorageTypeCDROM)
+
+		switch device.Action {
+		case CreateServerStorageDeviceActionCreate:
+			v.intRange(field+".Size", device.Size, MinStorageSize, MaxStorageSize)
+		case CreateServerStorageDeviceActionClone, CreateServerStorageDeviceActionAttach:
+			v.required(field+".Storage", device.Storage)
+		}
+	}
+
+	if r.Networking != nil {
+		for i, iface := range r.Networking.Interfaces {
+			field := fmt.Sprintf("Networking.Interfaces[%d]", i)
+			v.required(field+".Type", iface.Type)
+			v.oneOf(field+".Type", iface.Type,
+				upcloud.NetworkTypePrivate, upcloud.NetworkTypePublic, upcloud.NetworkTypeUtility)
+			if iface.Type == upcloud.NetworkTypePrivate {
+				v.required(field+".Network", iface.Network)
+			}
+			if len(iface.IPAddresses) == 0 {
+				v.add(field+".IPAddresses", "must contain at least one IP address")
+			}
+			for j, ip := range iface.IPAddresses {
+				v.oneOf(fmt.Sprintf("%s.IPAddresses[%d].Family", field, j), ip.Family,
+					upcloud.IPAddressFamilyIPv4, upcloud.IPAddressFamilyIPv6)
+			}
+		}
+	}
+
+	if r.LoginUser != nil && r.LoginUser.CreatePassword != "" {
+		v.oneOf("LoginUser.CreatePassword", r.LoginUser.CreatePassword, "yes", "no")
+	}
+	return v.result("CreateServerRequest")
+}
+
 // SSHKeySlice is a slice of strings
 // It exists to allow for a custom JSON unmarshaller.
 type SSHKeySlice []string
@@ -205,6 +286,30 @@ type WaitForServerStateRequest struct {
 	Timeout        time.Duration
 }
 
+// Validate implements the Validator interface
+func (r *WaitForServerStateRequest) Validate() error {
+	v := validator{}
+	v.required("UUID", r.UUID)
+
+	if r.DesiredState == "" && r.UndesiredState == "" {
+		v.add("DesiredState", "or UndesiredState is required")
+	}
+
+	states := []string{
+		upcloud.ServerStateStarted,
+		upcloud.ServerStateStopped,
+		upcloud.ServerStateMaintenance,
+		upcloud.ServerStateError,
+	}
+	v.oneOf("DesiredState", r.DesiredState, states...)
+	v.oneOf("UndesiredState", r.UndesiredState, states...)
+
+	if r.Timeout <= 0 {
+		v.add("Timeout", "must be positive, got %s", r.Timeout)
+	}
+	return v.result("WaitForServerStateRequest")
+}
+
 // StartServerRequest represents a request to start a server
 type StartServerRequest struct {
 	UUID string `json:"-"`
@@ -221,6 +326,17 @@ func (r *StartServerRequest) RequestURL() string {
 	return fmt.Sprintf("/server/%s/start", r.UUID)
 }
 
+// Validate implements the Validator interface
+func (r *StartServerRequest) Validate() error {
+	v := validator{}
+	v.required("UUID", r.UUID)
+
+	if r.Timeout < 0 {
+		v.add("Timeout", "must not be negative, got %s", r.Timeout)
+	}
+	return v.result("StartServerRequest")
+}
+
 // MarshalJSON is a custom marshaller that deals with
 // deeply embedded values.
 func (r StartServerRequest) MarshalJSON() ([]byte, error) {
@@ -246,6 +362,18 @@ func (r *StopServerRequest) RequestURL() string {
 	return fmt.Sprintf("/server/%s/stop", r.UUID)
 }
 
+// Validate implements the Validator interface
+func (r *StopServerRequest) Validate() error {
+	v := validator{}
+	v.required("UUID", r.UUID)
+	v.oneOf("StopType", r.StopType, upcloud.StopTypeSoft, upcloud.StopTypeHard)
+
+	if r.Timeout < 0 {
+		v.add("Timeout", "must not be negative, got %s", r.Timeout)
+	}
+	return v.result("StopServerRequest")
+}
+
 // MarshalJSON is a custom marshaller that deals with
 // deeply embedded values.
 func (r StopServerRequest) MarshalJSON() ([]byte, error) {
@@ -274,6 +402,19 @@ func (r *RestartServerRequest) RequestURL() string {
 	return fmt.Sprintf("/server/%s/restart", r.UUID)
 }
 
+// Validate implements the Validator interface
+func (r *RestartServerRequest) Validate() error {
+	v := validator{}
+	v.required("UUID", r.UUID)
+	v.oneOf("StopType", r.StopType, upcloud.StopTypeSoft, upcloud.StopTypeHard)
+	v.oneOf("TimeoutAction", r.TimeoutAction, RestartTimeoutActionDestroy, RestartTimeoutActionIgnore)
+
+	if r.Timeout < 0 {
+		v.add("Timeout", "must not be negative, got %s", r.Timeout)
+	}
+	return v.result("RestartServerRequest")
+}
+
 // MarshalJSON is a custom marshaller that deals with
 // deeply embedded values.
 func (r RestartServerRequest) MarshalJSON() ([]byte, error) {
@@ -287,7 +428,8 @@ func (r RestartServerRequest) MarshalJSON() ([]byte, error) {
 	return json.Marshal(&v)
 }
 
-// ModifyServerRequest represents a request to modify a server
+// ModifyServerRequest represents a request to modify a server. Only the fields that are set are sent, boolean fields
+// left as upcloud.Empty keep their current value.
 type ModifyServerRequest struct {
 	UUID string `json:"-"`
 
@@ -297,13 +439,13 @@ type ModifyServerRequest struct {
 	Firewall             string          `json:"firewall,omitempty"`
 	Hostname             string          `json:"hostname,omitempty"`
 	MemoryAmount         int             `json:"memory_amount,omitempty,string"`
-	Metadata             upcloud.Boolean `json:"metadata"`
+	Metadata             upcloud.Boolean `json:"metadata,omitempty"`
 	Plan                 string          `json:"plan,omitempty"`
 	SimpleBackup         string          `json:"simple_backup,omitempty"`
 	TimeZone             string          `json:"timezone,omitempty"`
 	Title                string          `json:"title,omitempty"`
 	VideoModel           string          `json:"video_model,omitempty"`
-	RemoteAccessEnabled  upcloud.Boolean `json:"remote_access_enabled"`
+	RemoteAccessEnabled  upcloud.Boolean `json:"remote_access_enabled,omitempty"`
 	RemoteAccessType     string          `json:"remote_access_type,omitempty"`
 	RemoteAccessPassword string          `json:"remote_access_password,omitempty"`
 	Zone                 string          `json:"zone,omitempty"`
@@ -326,6 +468,24 @@ func (r *ModifyServerRequest) RequestURL() string {
 	return fmt.Sprintf("/server/%s", r.UUID)
 }
 
+// Validate implements the Validator interface
+func (r *ModifyServerRequest) Validate() error {
+	v := validator{}
+	v.required("UUID", r.UUID)
+	v.hostname("Hostname", r.Hostname)
+	v.maxLength("Title", r.Title, MaxTitleLength)
+	v.oneOf("VideoModel", r.VideoModel, upcloud.VideoModelVGA, upcloud.VideoModelCirrus)
+	v.oneOf("RemoteAccessType", r.RemoteAccessType, upcloud.RemoteAccessTypeVNC, upcloud.RemoteAccessTypeSPICE)
+
+	if r.CoreNumber < 0 {
+		v.add("CoreNumber", "must not be negative, got %d", r.CoreNumber)
+	}
+	if r.MemoryAmount < 0 {
+		v.add("MemoryAmount", "must not be negative, got %d", r.MemoryAmount)
+	}
+	return v.result("ModifyServerRequest")
+}
+
 // DeleteServerRequest represents a request to delete a server
 type DeleteServerRequest struct {
 	UUID string
@@ -336,6 +496,13 @@ func (r *DeleteServerRequest) RequestURL() string {
 	return fmt.Sprintf("/server/%s", r.UUID)
 }
 
+// Validate implements the Validator interface
+func (r *DeleteServerRequest) Validate() error {
+	v := validator{}
+	v.required("UUID", r.UUID)
+	return v.result("DeleteServerRequest")
+}
+
 // DeleteServerAndStoragesRequest represents a request to delete a server and all attached storages
 type DeleteServerAndStoragesRequest struct {
 	UUID string
@@ -346,6 +513,13 @@ func (r *DeleteServerAndStoragesRequest) RequestURL() string {
 	return fmt.Sprintf("/server/%s/?storages=1", r.UUID)
 }
 
+// Validate implements the Validator interface
+func (r *DeleteServerAndStoragesRequest) Validate() error {
+	v := validator{}
+	v.required("UUID", r.UUID)
+	return v.result("DeleteServerAndStoragesRequest")
+}
+
 // TagServerRequest represents a request to tag a server with one or more tags
 type TagServerRequest struct {
 	UUID string
@@ -357,6 +531,14 @@ func (r *TagServerRequest) RequestURL() string {
 	return fmt.Sprintf("/server/%s/tag/%s", r.UUID, strings.Join(r.Tags, ","))
 }
 
+// Validate implements the Validator interface
+func (r *TagServerRequest) Validate() error {
+	v := validator{}
+	v.required("UUID", r.UUID)
+	v.tags("Tags", r.Tags)
+	return v.result("TagServerRequest")
+}
+
 // UntagServerRequest represents a request to remove one or more tags from a server
 type UntagServerRequest struct {
 	UUID string
@@ -367,3 +549,11 @@ type UntagServerRequest struct {
 func (r *UntagServerRequest) RequestURL() string {
 	return fmt.Sprintf("/server/%s/untag/%s", r.UUID, strings.Join(r.Tags, ","))
 }
+
+// Validate implements the Validator interface
+func (r *UntagServerRequest) Validate() error {
+	v := validator{}
+	v.required("UUID", r.UUID)
+	v.tags("Tags", r.Tags)
+	return v.result("UntagServerRequest")
+}
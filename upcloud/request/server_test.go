@@ -2,6 +2,7 @@ package request
 
 import (
 	"encoding/json"
+	"errors"
 	"testing"
 	"time"
 
@@ -133,6 +134,7 @@ func TestCreateServerRequest(t *testing.T) {
 	assert.NoError(t, err)
 	assert.JSONEq(t, expectedJSON, string(actualJSON))
 	assert.Equal(t, "/server", request.RequestURL())
+	assert.NoError(t, request.Validate())
 }
 
 // TestStartServerRequest_OmitValues tests that StartServerRequest objects behave correctly
@@ -273,8 +275,7 @@ func TestModifyServerRequest(t *testing.T) {
 		  "core_number": "8",
 		  "memory_amount": "16384",
 		  "plan" : "custom",
-		  "metadata": "yes",
-		  "remote_access_enabled": "no"
+		  "metadata": "yes"
 		}
 	  }
 	`
@@ -284,6 +285,50 @@ func TestModifyServerRequest(t *testing.T) {
 	assert.Equal(t, "/server/foo", request.RequestURL())
 }
 
+// TestModifyServerRequest_OnlySetFields tests that a ModifyServerRequest only sends the fields that are set, so
+// that e.g. renaming a server doesn't turn off its metadata service
+func TestModifyServerRequest_OnlySetFields(t *testing.T) {
+	request := ModifyServerRequest{
+		UUID:  "foo",
+		Title: "Renamed server",
+	}
+
+	actualJSON, err := json.Marshal(&request)
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"server": {"title": "Renamed server"}}`, string(actualJSON))
+
+	request = ModifyServerRequest{
+		UUID:                "foo",
+		Metadata:            upcloud.False,
+		RemoteAccessEnabled: upcloud.False,
+	}
+
+	actualJSON, err = json.Marshal(&request)
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"server": {"metadata": "no", "remote_access_enabled": "no"}}`, string(actualJSON))
+}
+
+// TestCreateServerRequest_UnsetBooleans tests that a CreateServerRequest sends unset boolean fields as "no"
+func TestCreateServerRequest_UnsetBooleans(t *testing.T) {
+	request := CreateServerRequest{
+		Zone:     "fi-hel2",
+		Title:    "Test server",
+		Hostname: "test.example.com",
+	}
+
+	actualJSON, err := json.Marshal(&request)
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"server": {
+		"hostname": "test.example.com",
+		"metadata": "no",
+		"networking": null,
+		"storage_devices": {"storage_device": null},
+		"title": "Test server",
+		"remote_access_enabled": "no",
+		"zone": "fi-hel2"
+	}}`, string(actualJSON))
+}
+
 // TestDeleteServerRequest tests that DeleteServerRequest objects behave correctly
 func TestDeleteServerRequest(t *testing.T) {
 	request := DeleteServerRequest{
@@ -350,3 +395,62 @@ func TestUntagServerRequest(t *testing.T) {
 
 	assert.Equal(t, "/server/foo/untag/tag1", request.RequestURL())
 }
+
+// TestCreateServerRequest_Validate tests that all the problems of an invalid CreateServerRequest are reported
+func TestCreateServerRequest_Validate(t *testing.T) {
+	request := CreateServerRequest{
+		Title:    "Invalid server",
+		Hostname: "-invalid-.example.com",
+		StorageDevices: []CreateServerStorageDevice{
+			{
+				Action: CreateServerStorageDeviceActionCreate,
+				Size:   5,
+				Tier:   "ssd",
+			},
+			{
+				Action: CreateServerStorageDeviceActionClone,
+			},
+		},
+		Networking: &CreateServerNetworking{
+			Interfaces: []CreateServerInterface{
+				{
+					IPAddresses: []CreateServerIPAddress{{Family: "IPv5"}},
+					Type:        upcloud.NetworkTypePrivate,
+				},
+			},
+		},
+	}
+
+	err := request.Validate()
+
+	var validationErr *ValidationError
+	assert.True(t, errors.As(err, &validationErr))
+	assert.Equal(t, "CreateServerRequest", validationErr.Request)
+
+	var fields []string
+	for _, e := range validationErr.Errors {
+		fields = append(fields, e.Field)
+	}
+	assert.Equal(t, []string{
+		"Zone",
+		"Hostname",
+		"StorageDevices[0].Tier",
+		"StorageDevices[0].Size",
+		"StorageDevices[1].Storage",
+		"Networking.Interfaces[0].Network",
+		"Networking.Interfaces[0].IPAddresses[0].Family",
+	}, fields)
+}
+
+// TestStopServerRequest_Validate tests that the stop type of a StopServerRequest is validated
+func TestStopServerRequest_Validate(t *testing.T) {
+	request := StopServerRequest{
+		UUID:     "foo",
+		StopType: upcloud.StopTypeSoft,
+		Timeout:  time.Minute,
+	}
+	assert.NoError(t, request.Validate())
+
+	request.StopType = "gentle"
+	assert.EqualError(t, request.Validate(), `invalid StopServerRequest: StopType must be one of soft, hard, got "gentle"`)
+}
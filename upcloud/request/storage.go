@@ -41,6 +41,20 @@ func (r *GetStoragesRequest) RequestURL() string {
 	return "/storage"
 }
 
+// Validate implements the Validator interface
+func (r *GetStoragesRequest) Validate() error {
+	v := validator{}
+	v.oneOf("Access", r.Access, upcloud.StorageAccessPublic, upcloud.StorageAccessPrivate)
+	v.oneOf("Type", r.Type,
+		upcloud.StorageTypeBackup,
+		upcloud.StorageTypeCDROM,
+		upcloud.StorageTypeDisk,
+		upcloud.StorageTypeNormal,
+		upcloud.StorageTypeTemplate,
+	)
+	return v.result("GetStoragesRequest")
+}
+
 // GetStorageDetailsRequest represents a request for retrieving details about a piece of storage
 type GetStorageDetailsRequest struct {
 	UUID string
@@ -51,6 +65,13 @@ func (r *GetStorageDetailsRequest) RequestURL() string {
 	return fmt.Sprintf("/storage/%s", r.UUID)
 }
 
+// Validate implements the Validator interface
+func (r *GetStorageDetailsRequest) Validate() error {
+	v := validator{}
+	v.required("UUID", r.UUID)
+	return v.result("GetStorageDetailsRequest")
+}
+
 // CreateStorageRequest represents a request to create a storage device
 type CreateStorageRequest struct {
 	Size       int                 `json:"size,string"`
@@ -65,6 +86,17 @@ func (r *CreateStorageRequest) RequestURL() string {
 	return "/storage"
 }
 
+// Validate implements the Validator interface
+func (r *CreateStorageRequest) Validate() error {
+	v := validator{}
+	v.required("Zone", r.Zone)
+	v.maxLength("Title", r.Title, MaxTitleLength)
+	v.intRange("Size", r.Size, MinStorageSize, MaxStorageSize)
+	v.oneOf("Tier", r.Tier, upcloud.StorageTierHDD, upcloud.StorageTierMaxIOPS)
+	v.backupRule("BackupRule", r.BackupRule)
+	return v.result("CreateStorageRequest")
+}
+
 // MarshalJSON is a custom marshaller that deals with
 // deeply embedded values.
 func (r CreateStorageRequest) MarshalJSON() ([]byte, error) {
@@ -103,6 +135,18 @@ func (r *ModifyStorageRequest) RequestURL() string {
 	return fmt.Sprintf("/storage/%s", r.UUID)
 }
 
+// Validate implements the Validator interface
+func (r *ModifyStorageRequest) Validate() error {
+	v := validator{}
+	v.required("UUID", r.UUID)
+	v.maxLength("Title", r.Title, MaxTitleLength)
+	if r.Size != 0 {
+		v.intRange("Size", r.Size, MinStorageSize, MaxStorageSize)
+	}
+	v.backupRule("BackupRule", r.BackupRule)
+	return v.result("ModifyStorageRequest")
+}
+
 // AttachStorageRequest represents a request to attach a storage device to a server
 type AttachStorageRequest struct {
 	ServerUUID string `json:"-"`
@@ -118,6 +162,19 @@ func (r *AttachStorageRequest) RequestURL() string {
 	return fmt.Sprintf("/server/%s/storage/attach", r.ServerUUID)
 }
 
+// Validate implements the Validator interface
+func (r *AttachStorageRequest) Validate() error {
+	v := validator{}
+	v.required("ServerUUID", r.ServerUUID)
+	v.oneOf("Type", r.Type, upcloud.StorageTypeDisk, upcloud.StorageTypeCDROM)
+
+	// An empty CD-ROM device can be attached without a storage
+	if r.Type != upcloud.StorageTypeCDROM {
+		v.required("StorageUUID", r.StorageUUID)
+	}
+	return v.result("AttachStorageRequest")
+}
+
 // MarshalJSON is a custom marshaller that deals with
 // deeply embedded values.
 func (r AttachStorageRequest) MarshalJSON() ([]byte, error) {
@@ -142,6 +199,14 @@ func (r *DetachStorageRequest) RequestURL() string {
 	return fmt.Sprintf("/server/%s/storage/detach", r.ServerUUID)
 }
 
+// Validate implements the Validator interface
+func (r *DetachStorageRequest) Validate() error {
+	v := validator{}
+	v.required("ServerUUID", r.ServerUUID)
+	v.required("Address", r.Address)
+	return v.result("DetachStorageRequest")
+}
+
 // MarshalJSON is a custom marshaller that deals with
 // deeply embedded values.
 func (r DetachStorageRequest) MarshalJSON() ([]byte, error) {
@@ -164,6 +229,13 @@ func (r *DeleteStorageRequest) RequestURL() string {
 	return fmt.Sprintf("/storage/%s", r.UUID)
 }
 
+// Validate implements the Validator interface
+func (r *DeleteStorageRequest) Validate() error {
+	v := validator{}
+	v.required("UUID", r.UUID)
+	return v.result("DeleteStorageRequest")
+}
+
 // CloneStorageRequest represents a requests to clone a storage device
 type CloneStorageRequest struct {
 	UUID string `json:"-"`
@@ -178,6 +250,17 @@ func (r *CloneStorageRequest) RequestURL() string {
 	return fmt.Sprintf("/storage/%s/clone", r.UUID)
 }
 
+// Validate implements the Validator interface
+func (r *CloneStorageRequest) Validate() error {
+	v := validator{}
+	v.required("UUID", r.UUID)
+	v.required("Zone", r.Zone)
+	v.required("Title", r.Title)
+	v.maxLength("Title", r.Title, MaxTitleLength)
+	v.oneOf("Tier", r.Tier, upcloud.StorageTierHDD, upcloud.StorageTierMaxIOPS)
+	return v.result("CloneStorageRequest")
+}
+
 // MarshalJSON is a custom marshaller that deals with
 // deeply embedded values.
 func (r CloneStorageRequest) MarshalJSON() ([]byte, error) {
@@ -202,6 +285,15 @@ func (r *TemplatizeStorageRequest) RequestURL() string {
 	return fmt.Sprintf("/storage/%s/templatize", r.UUID)
 }
 
+// Validate implements the Validator interface
+func (r *TemplatizeStorageRequest) Validate() error {
+	v := validator{}
+	v.required("UUID", r.UUID)
+	v.required("Title", r.Title)
+	v.maxLength("Title", r.Title, MaxTitleLength)
+	return v.result("TemplatizeStorageRequest")
+}
+
 // MarshalJSON is a custom marshaller that deals with
 // deeply embedded values.
 func (r TemplatizeStorageRequest) MarshalJSON() ([]byte, error) {
@@ -221,6 +313,62 @@ type WaitForStorageStateRequest struct {
 	Timeout      time.Duration
 }
 
+// Validate implements the Validator interface
+func (r *WaitForStorageStateRequest) Validate() error {
+	v := validator{}
+	v.required("UUID", r.UUID)
+	v.required("DesiredState", r.DesiredState)
+	v.oneOf("DesiredState", r.DesiredState,
+		upcloud.StorageStateOnline,
+		upcloud.StorageStateMaintenance,
+		upcloud.StorageStateCloning,
+		upcloud.StorageStateBackuping,
+		upcloud.StorageStateError,
+		upcloud.StorageStateSyncing,
+	)
+
+	if r.Timeout <= 0 {
+		v.add("Timeout", "must be positive, got %s", r.Timeout)
+	}
+	return v.result("WaitForStorageStateRequest")
+}
+
+// WaitForBackupCreationRequest represents a request to wait for a manually created backup to be completed. UUID is
+// the UUID of the backup, as returned by CreateBackup
+type WaitForBackupCreationRequest struct {
+	UUID    string
+	Timeout time.Duration
+}
+
+// Validate implements the Validator interface
+func (r *WaitForBackupCreationRequest) Validate() error {
+	v := validator{}
+	v.required("UUID", r.UUID)
+
+	if r.Timeout <= 0 {
+		v.add("Timeout", "must be positive, got %s", r.Timeout)
+	}
+	return v.result("WaitForBackupCreationRequest")
+}
+
+// WaitForStorageCloneCompletionRequest represents a request to wait for a storage clone to be completed. UUID is the
+// UUID of the clone, as returned by CloneStorage
+type WaitForStorageCloneCompletionRequest struct {
+	UUID    string
+	Timeout time.Duration
+}
+
+// Validate implements the Validator interface
+func (r *WaitForStorageCloneCompletionRequest) Validate() error {
+	v := validator{}
+	v.required("UUID", r.UUID)
+
+	if r.Timeout <= 0 {
+		v.add("Timeout", "must be positive, got %s", r.Timeout)
+	}
+	return v.result("WaitForStorageCloneCompletionRequest")
+}
+
 // LoadCDROMRequest represents a request to load a storage as a CD-ROM in the CD-ROM device of a server
 type LoadCDROMRequest struct {
 	ServerUUID string `json:"-"`
@@ -233,6 +381,14 @@ func (r *LoadCDROMRequest) RequestURL() string {
 	return fmt.Sprintf("/server/%s/cdrom/load", r.ServerUUID)
 }
 
+// Validate implements the Validator interface
+func (r *LoadCDROMRequest) Validate() error {
+	v := validator{}
+	v.required("ServerUUID", r.ServerUUID)
+	v.required("StorageUUID", r.StorageUUID)
+	return v.result("LoadCDROMRequest")
+}
+
 // MarshalJSON is a custom marshaller that deals with
 // deeply embedded values.
 func (r LoadCDROMRequest) MarshalJSON() ([]byte, error) {
@@ -255,6 +411,13 @@ func (r *EjectCDROMRequest) RequestURL() string {
 	return fmt.Sprintf("/server/%s/cdrom/eject", r.ServerUUID)
 }
 
+// Validate implements the Validator interface
+func (r *EjectCDROMRequest) Validate() error {
+	v := validator{}
+	v.required("ServerUUID", r.ServerUUID)
+	return v.result("EjectCDROMRequest")
+}
+
 // CreateBackupRequest represents a request to create a backup of a storage device
 type CreateBackupRequest struct {
 	UUID string `json:"-"`
@@ -267,6 +430,15 @@ func (r *CreateBackupRequest) RequestURL() string {
 	return fmt.Sprintf("/storage/%s/backup", r.UUID)
 }
 
+// Validate implements the Validator interface
+func (r *CreateBackupRequest) Validate() error {
+	v := validator{}
+	v.required("UUID", r.UUID)
+	v.required("Title", r.Title)
+	v.maxLength("Title", r.Title, MaxTitleLength)
+	return v.result("CreateBackupRequest")
+}
+
 // MarshalJSON is a custom marshaller that deals with
 // deeply embedded values.
 func (r CreateBackupRequest) MarshalJSON() ([]byte, error) {
@@ -289,6 +461,13 @@ func (r *RestoreBackupRequest) RequestURL() string {
 	return fmt.Sprintf("/storage/%s/restore", r.UUID)
 }
 
+// Validate implements the Validator interface
+func (r *RestoreBackupRequest) Validate() error {
+	v := validator{}
+	v.required("UUID", r.UUID)
+	return v.result("RestoreBackupRequest")
+}
+
 // ImportSourceLocation can be a string to a file or io.Reader in StorageImportSourceDirectUpload mode or a URL
 // in StorageImportSourceHTTPImport mode
 type ImportSourceLocation interface{}
@@ -320,6 +499,21 @@ func (r *CreateStorageImportRequest) RequestURL() string {
 	return fmt.Sprintf("/storage/%s/import", r.StorageUUID)
 }
 
+// Validate implements the Validator interface
+func (r *CreateStorageImportRequest) Validate() error {
+	v := validator{}
+	v.required("StorageUUID", r.StorageUUID)
+	v.required("Source", r.Source)
+	v.oneOf("Source", r.Source, StorageImportSourceDirectUpload, StorageImportSourceHTTPImport)
+
+	if r.SourceLocation == nil || r.SourceLocation == "" {
+		v.add("SourceLocation", "is required")
+	} else if _, ok := r.SourceLocation.(string); !ok && r.Source == StorageImportSourceHTTPImport {
+		v.add("SourceLocation", "must be a URL when Source is %s", StorageImportSourceHTTPImport)
+	}
+	return v.result("CreateStorageImportRequest")
+}
+
 // GetStorageImportDetailsRequest represents a request to get details
 // about an import
 type GetStorageImportDetailsRequest struct {
@@ -331,9 +525,27 @@ func (r *GetStorageImportDetailsRequest) RequestURL() string {
 	return fmt.Sprintf("/storage/%s/import", r.UUID)
 }
 
+// Validate implements the Validator interface
+func (r *GetStorageImportDetailsRequest) Validate() error {
+	v := validator{}
+	v.required("UUID", r.UUID)
+	return v.result("GetStorageImportDetailsRequest")
+}
+
 // WaitForStorageImportCompletionRequest represents a request to wait
 // for storage import to complete.
 type WaitForStorageImportCompletionRequest struct {
 	StorageUUID string
 	Timeout     time.Duration
 }
+
+// Validate implements the Validator interface
+func (r *WaitForStorageImportCompletionRequest) Validate() error {
+	v := validator{}
+	v.required("StorageUUID", r.StorageUUID)
+
+	if r.Timeout <= 0 {
+		v.add("Timeout", "must be positive, got %s", r.Timeout)
+	}
+	return v.result("WaitForStorageImportCompletionRequest")
+}
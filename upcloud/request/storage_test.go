@@ -89,6 +89,33 @@ func TestModifyStorageRequest(t *testing.T) {
 	assert.Equal(t, "/storage/foo", request.RequestURL())
 }
 
+// TestModifyStorageRequest_OnlySetFields tests that a ModifyStorageRequest only sends the fields that are set
+func TestModifyStorageRequest_OnlySetFields(t *testing.T) {
+	request := ModifyStorageRequest{
+		UUID: "foo",
+		BackupRule: &upcloud.BackupRule{
+			Interval:  upcloud.BackupRuleIntervalDaily,
+			Time:      "0430",
+			Retention: 30,
+		},
+	}
+
+	expectedJSON := `
+	  {
+        "storage": {
+          "backup_rule": {
+            "interval": "daily",
+            "time": "0430",
+            "retention": "30"
+          }
+        }
+      }
+	`
+	actualJSON, err := json.Marshal(&request)
+	assert.NoError(t, err)
+	assert.JSONEq(t, expectedJSON, string(actualJSON))
+}
+
 // TestAttachStorageRequest tests that AttachStorageRequest objects behave correctly
 func TestAttachStorageRequest(t *testing.T) {
 	request := AttachStorageRequest{
@@ -280,3 +307,44 @@ func TestGetStorageImportDetails(t *testing.T) {
 
 	assert.Equal(t, "/storage/foo/import", request.RequestURL())
 }
+
+// TestCreateStorageRequest_Validate tests that the size, tier and backup rule of a CreateStorageRequest are validated
+func TestCreateStorageRequest_Validate(t *testing.T) {
+	request := CreateStorageRequest{
+		Tier:  upcloud.StorageTierMaxIOPS,
+		Title: "Test storage",
+		Size:  10,
+		Zone:  "fi-hel2",
+		BackupRule: &upcloud.BackupRule{
+			Interval:  upcloud.BackupRuleIntervalDaily,
+			Time:      "0430",
+			Retention: 30,
+		},
+	}
+	assert.NoError(t, request.Validate())
+
+	request.Tier = "tape"
+	request.Size = 5000
+	request.BackupRule.Time = "2460"
+	request.BackupRule.Retention = 0
+
+	assert.EqualError(t, request.Validate(), "invalid CreateStorageRequest: "+
+		"Size must be between 10 and 4096, got 5000; "+
+		`Tier must be one of hdd, maxiops, got "tape"; `+
+		`BackupRule.Time must be in the format hhmm, got "2460"; `+
+		"BackupRule.Retention must be between 1 and 1095, got 0")
+}
+
+// TestModifyStorageRequest_Validate tests that only the fields set in a ModifyStorageRequest are validated
+func TestModifyStorageRequest_Validate(t *testing.T) {
+	request := ModifyStorageRequest{
+		UUID:  "foo",
+		Title: "New title",
+	}
+	assert.NoError(t, request.Validate())
+
+	request.UUID = ""
+	request.Size = 1
+	assert.EqualError(t, request.Validate(),
+		"invalid ModifyStorageRequest: UUID is required; Size must be between 10 and 4096, got 1")
+}
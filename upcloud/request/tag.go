@@ -17,6 +17,13 @@ func (r *CreateTagRequest) RequestURL() string {
 	return "/tag"
 }
 
+// Validate implements the Validator interface
+func (r *CreateTagRequest) Validate() error {
+	v := validator{}
+	v.tagName("Name", r.Name)
+	return v.result("CreateTagRequest")
+}
+
 // MarshalJSON is a custom marshaller that deals with
 // deeply embedded values.
 func (r CreateTagRequest) MarshalJSON() ([]byte, error) {
@@ -54,6 +61,14 @@ func (r *ModifyTagRequest) RequestURL() string {
 	return fmt.Sprintf("/tag/%s", r.Name)
 }
 
+// Validate implements the Validator interface
+func (r *ModifyTagRequest) Validate() error {
+	v := validator{}
+	v.tagName("Name", r.Name)
+	v.tagName("Tag.Name", r.Tag.Name)
+	return v.result("ModifyTagRequest")
+}
+
 // DeleteTagRequest represents a request to delete a tag
 type DeleteTagRequest struct {
 	Name string
@@ -63,3 +78,10 @@ type DeleteTagRequest struct {
 func (r *DeleteTagRequest) RequestURL() string {
 	return fmt.Sprintf("/tag/%s", r.Name)
 }
+
+// Validate implements the Validator interface
+func (r *DeleteTagRequest) Validate() error {
+	v := validator{}
+	v.tagName("Name", r.Name)
+	return v.result("DeleteTagRequest")
+}
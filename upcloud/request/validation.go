@@ -0,0 +1,330 @@
+package request
+
+import (
+	"fmt"
+	"net"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/UpCloudLtd/upcloud-go-api/upcloud"
+)
+
+// Limits checked by the Validate methods
+const (
+	MinStorageSize = 10
+	MaxStorageSize = 4096
+
+	MinObjectStorageSize = 250
+	MaxObjectStorageSize = 1000
+
+	MinBackupRuleRetention = 1
+	MaxBackupRuleRetention = 1095
+
+	MaxHostnameLength = 253
+	MaxTitleLength    = 255
+)
+
+// Validator is implemented by requests that can check themselves before they are sent
+type Validator interface {
+	// Validate returns a *ValidationError describing every invalid field, or nil if the request is valid
+	Validate() error
+}
+
+// FieldError describes a single invalid field of a request
+type FieldError struct {
+	// Field is the name of the field, e.g. "StorageDevices[0].Size"
+	Field   string
+	Message string
+}
+
+// Error implements the error interface
+func (e *FieldError) Error() string {
+	return fmt.Sprintf("%s %s", e.Field, e.Message)
+}
+
+// ValidationError is returned by the Validate methods and lists all the problems found in a request
+type ValidationError struct {
+	// Request is the name of the request type, e.g. "CreateServerRequest"
+	Request string
+	Errors  []*FieldError
+}
+
+// Error implements the error interface
+func (e *ValidationError) Error() string {
+	messages := make([]string, len(e.Errors))
+	for i, err := range e.Errors {
+		messages[i] = err.Error()
+	}
+
+	return fmt.Sprintf("invalid %s: %s", e.Request, strings.Join(messages, "; "))
+}
+
+// Is reports whether the error matches the specified sentinel error. A validation error matches
+// upcloud.ErrBadRequest, just like the errors the API returns for invalid requests.
+func (e *ValidationError) Is(target error) bool {
+	return target == upcloud.ErrBadRequest
+}
+
+// validator collects the errors found while validating a request
+type validator struct {
+	errors []*FieldError
+}
+
+func (v *validator) add(field, format string, args ...interface{}) {
+	v.errors = append(v.errors, &FieldError{Field: field, Message: fmt.Sprintf(format, args...)})
+}
+
+// Returns the collected errors as a *ValidationError, or nil if there are none
+func (v *validator) result(request string) error {
+	if len(v.errors) == 0 {
+		return nil
+	}
+
+	return &ValidationError{Request: request, Errors: v.errors}
+}
+
+func (v *validator) required(field, value string) {
+	if value == "" {
+		v.add(field, "is required")
+	}
+}
+
+// Checks that the value, if set, is one of the allowed values
+func (v *validator) oneOf(field, value string, allowed ...string) {
+	if value == "" {
+		return
+	}
+
+	for _, a := range allowed {
+		if value == a {
+			return
+		}
+	}
+
+	v.add(field, "must be one of %s, got %q", strings.Join(allowed, ", "), value)
+}
+
+func (v *validator) intRange(field string, value, min, max int) {
+	if value < min || value > max {
+		v.add(field, "must be between %d and %d, got %d", min, max, value)
+	}
+}
+
+func (v *validator) positive(field string, value int) {
+	if value < 1 {
+		v.add(field, "must be at least 1, got %d", value)
+	}
+}
+
+func (v *validator) maxLength(field, value string, max int) {
+	if len(value) > max {
+		v.add(field, "must be at most %d characters long", max)
+	}
+}
+
+// The label length limit of DNS isn't enforced since the API accepts longer labels
+var hostnameLabel = regexp.MustCompile(`^[a-zA-Z0-9]([a-zA-Z0-9-]*[a-zA-Z0-9])?$`)
+
+// Checks that the value, if set, is a hostname as defined by RFC 1123
+func (v *validator) hostname(field, value string) {
+	if value == "" {
+		return
+	}
+
+	if len(value) > MaxHostnameLength {
+		v.add(field, "must be at most %d characters long", MaxHostnameLength)
+		return
+	}
+
+	for _, label := range strings.Split(strings.TrimSuffix(value, "."), ".") {
+		if !hostnameLabel.MatchString(label) {
+			v.add(field, "is not a valid hostname: %q", value)
+			return
+		}
+	}
+}
+
+// Checks that the value, if set, is an IP address of the specified family, which may be empty
+func (v *validator) ipAddress(field, value, family string) {
+	if value == "" {
+		return
+	}
+
+	ip := net.ParseIP(value)
+	if ip == nil {
+		v.add(field, "is not a valid IP address: %q", value)
+		return
+	}
+
+	v.ipFamily(field, ip, family)
+}
+
+// Checks that the value, if set, is a network in CIDR notation of the specified family, which may be empty
+func (v *validator) cidr(field, value, family string) {
+	if value == "" {
+		return
+	}
+
+	ip, _, err := net.ParseCIDR(value)
+	if err != nil {
+		v.add(field, "is not a valid CIDR network: %q", value)
+		return
+	}
+
+	v.ipFamily(field, ip, family)
+}
+
+func (v *validator) ipFamily(field string, ip net.IP, family string) {
+	switch {
+	case family == upcloud.IPAddressFamilyIPv4 && ip.To4() == nil:
+		v.add(field, "is not an IPv4 address: %q", ip)
+	case family == upcloud.IPAddressFamilyIPv6 && ip.To4() != nil:
+		v.add(field, "is not an IPv6 address: %q", ip)
+	}
+}
+
+// Checks that the value, if set, is a port number
+func (v *validator) port(field, value string) (int, bool) {
+	if value == "" {
+		return 0, false
+	}
+
+	port, err := strconv.Atoi(value)
+	if err != nil || port < 1 || port > 65535 {
+		v.add(field, "must be a port between 1 and 65535, got %q", value)
+		return 0, false
+	}
+
+	return port, true
+}
+
+// Checks that both ends of a port range are valid and in the right order
+func (v *validator) portRange(startField, start, endField, end string) {
+	s, okStart := v.port(startField, start)
+	e, okEnd := v.port(endField, end)
+
+	if okStart && okEnd && s > e {
+		v.add(endField, "must not be lower than %s", startField)
+	}
+}
+
+// Checks the fields of a backup rule
+func (v *validator) backupRule(field string, rule *upcloud.BackupRule) {
+	if rule == nil {
+		return
+	}
+
+	v.required(field+".Interval", rule.Interval)
+	v.oneOf(field+".Interval", rule.Interval,
+		upcloud.BackupRuleIntervalDaily,
+		upcloud.BackupRuleIntervalMonday,
+		upcloud.BackupRuleIntervalTuesday,
+		upcloud.BackupRuleIntervalWednesday,
+		upcloud.BackupRuleIntervalThursday,
+		upcloud.BackupRuleIntervalFriday,
+		upcloud.BackupRuleIntervalSaturday,
+		upcloud.BackupRuleIntervalSunday,
+	)
+
+	if !isBackupTime(rule.Time) {
+		v.add(field+".Time", "must be in the format hhmm, got %q", rule.Time)
+	}
+
+	v.intRange(field+".Retention", rule.Retention, MinBackupRuleRetention, MaxBackupRuleRetention)
+}
+
+// Returns whether the value is a time of day in the format "hhmm"
+func isBackupTime(value string) bool {
+	if len(value) != 4 {
+		return false
+	}
+
+	hours, err := strconv.Atoi(value[:2])
+	if err != nil || hours < 0 || hours > 23 {
+		return false
+	}
+
+	minutes, err := strconv.Atoi(value[2:])
+	if err != nil || minutes < 0 || minutes > 59 {
+		return false
+	}
+
+	return true
+}
+
+// Checks the fields of a firewall rule
+func (v *validator) firewallRule(field string, rule *upcloud.FirewallRule) {
+	prefix := ""
+	if field != "" {
+		prefix = field + "."
+	}
+
+	v.required(prefix+"Direction", rule.Direction)
+	v.oneOf(prefix+"Direction", rule.Direction, upcloud.FirewallRuleDirectionIn, upcloud.FirewallRuleDirectionOut)
+	v.required(prefix+"Action", rule.Action)
+	v.oneOf(prefix+"Action", rule.Action,
+		upcloud.FirewallRuleActionAccept, upcloud.FirewallRuleActionReject, upcloud.FirewallRuleActionDrop)
+	v.required(prefix+"Family", rule.Family)
+	v.oneOf(prefix+"Family", rule.Family, upcloud.IPAddressFamilyIPv4, upcloud.IPAddressFamilyIPv6)
+	v.oneOf(prefix+"Protocol", rule.Protocol,
+		upcloud.FirewallRuleProtocolTCP, upcloud.FirewallRuleProtocolUDP, upcloud.FirewallRuleProtocolICMP)
+
+	if rule.Position < 0 {
+		v.add(prefix+"Position", "must not be negative, got %d", rule.Position)
+	}
+
+	v.ipAddress(prefix+"SourceAddressStart", rule.SourceAddressStart, rule.Family)
+	v.ipAddress(prefix+"SourceAddressEnd", rule.SourceAddressEnd, rule.Family)
+	v.ipAddress(prefix+"DestinationAddressStart", rule.DestinationAddressStart, rule.Family)
+	v.ipAddress(prefix+"DestinationAddressEnd", rule.DestinationAddressEnd, rule.Family)
+
+	hasPorts := rule.SourcePortStart != "" || rule.SourcePortEnd != "" ||
+		rule.DestinationPortStart != "" || rule.DestinationPortEnd != ""
+	if hasPorts && rule.Protocol != upcloud.FirewallRuleProtocolTCP && rule.Protocol != upcloud.FirewallRuleProtocolUDP {
+		v.add(prefix+"Protocol", "must be tcp or udp when ports are specified")
+	}
+
+	v.portRange(prefix+"SourcePortStart", rule.SourcePortStart, prefix+"SourcePortEnd", rule.SourcePortEnd)
+	v.portRange(prefix+"DestinationPortStart", rule.DestinationPortStart,
+		prefix+"DestinationPortEnd", rule.DestinationPortEnd)
+
+	if rule.ICMPType != "" && rule.Protocol != upcloud.FirewallRuleProtocolICMP {
+		v.add(prefix+"ICMPType", "requires the icmp protocol")
+	}
+}
+
+// Checks the fields of an IP network
+func (v *validator) ipNetwork(field string, network *upcloud.IPNetwork) {
+	v.required(field+".Address", network.Address)
+	v.required(field+".Family", network.Family)
+	v.oneOf(field+".Family", network.Family, upcloud.IPAddressFamilyIPv4, upcloud.IPAddressFamilyIPv6)
+	v.cidr(field+".Address", network.Address, network.Family)
+	v.ipAddress(field+".Gateway", network.Gateway, network.Family)
+
+	for i, dns := range network.DHCPDns {
+		v.ipAddress(fmt.Sprintf("%s.DHCPDns[%d]", field, i), dns, "")
+	}
+}
+
+// Checks a list of tag names that ends up in the request URL
+func (v *validator) tags(field string, tags []string) {
+	if len(tags) == 0 {
+		v.add(field, "must contain at least one tag")
+	}
+
+	for i, tag := range tags {
+		v.tagName(fmt.Sprintf("%s[%d]", field, i), tag)
+	}
+}
+
+func (v *validator) tagName(field, name string) {
+	if name == "" {
+		v.add(field, "is required")
+		return
+	}
+
+	if strings.ContainsAny(name, ",/?#% ") {
+		v.add(field, "must not contain commas, slashes or whitespace, got %q", name)
+	}
+}
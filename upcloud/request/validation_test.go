@@ -0,0 +1,89 @@
+package request
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/UpCloudLtd/upcloud-go-api/upcloud"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestValidationError tests that validation errors can be inspected and matched like API errors
+func TestValidationError(t *testing.T) {
+	request := DeleteServerRequest{}
+	err := request.Validate()
+
+	assert.EqualError(t, err, "invalid DeleteServerRequest: UUID is required")
+	assert.True(t, errors.Is(err, upcloud.ErrBadRequest))
+	assert.False(t, errors.Is(err, upcloud.ErrNotFound))
+
+	var validationErr *ValidationError
+	assert.True(t, errors.As(err, &validationErr))
+	assert.Equal(t, []*FieldError{{Field: "UUID", Message: "is required"}}, validationErr.Errors)
+}
+
+// TestValidateHostname tests the hostname rules of RFC 1123
+func TestValidateHostname(t *testing.T) {
+	valid := []string{
+		"debian.example.com",
+		"web-1",
+		"1and1.example.com",
+		"example.com.",
+		"uploud-go-sdk-integration-test-testattachmodifyreleaseipaddress1.example.com",
+	}
+	for _, hostname := range valid {
+		v := validator{}
+		v.hostname("Hostname", hostname)
+		assert.Empty(t, v.errors, hostname)
+	}
+
+	invalid := []string{
+		"-web.example.com",
+		"web-.example.com",
+		"web..example.com",
+		"web_1.example.com",
+		"web 1",
+		"web.example.com/",
+		strings.Repeat("a.", 127) + "aa",
+	}
+	for _, hostname := range invalid {
+		v := validator{}
+		v.hostname("Hostname", hostname)
+		assert.Len(t, v.errors, 1, hostname)
+	}
+}
+
+// TestIsBackupTime tests the hhmm format of backup rules
+func TestIsBackupTime(t *testing.T) {
+	for _, value := range []string{"0000", "0430", "2359"} {
+		assert.True(t, isBackupTime(value), value)
+	}
+
+	for _, value := range []string{"", "430", "04:30", "2400", "0060", "abcd", "-100"} {
+		assert.False(t, isBackupTime(value), value)
+	}
+}
+
+// TestRequestsImplementValidator tests that the Validate methods of the request types have the expected signature
+func TestRequestsImplementValidator(t *testing.T) {
+	requests := []Validator{
+		&CreateServerRequest{},
+		&ModifyServerRequest{},
+		&CreateStorageRequest{},
+		&CreateFirewallRuleRequest{},
+		&CreateNetworkRequest{},
+		&AssignIPAddressRequest{},
+		&CreateObjectStorageRequest{},
+		&CreateTagRequest{},
+		&ModifyHostRequest{},
+		&WaitForServerStateRequest{},
+		&WaitForObjectStorageStateRequest{},
+		&WaitForBackupCreationRequest{},
+		&WaitForStorageCloneCompletionRequest{},
+	}
+
+	for _, r := range requests {
+		assert.Error(t, r.Validate())
+	}
+}
@@ -0,0 +1,408 @@
+// Package cache provides a read-through cache for the API endpoints that list what the API offers rather than the
+// resources of the account, e.g. the zones and the plans. These rarely change, so fetching them on every use is
+// wasteful:
+//
+//	svc := cache.New(service.New(c), cache.WithPersistence(dir))
+//
+//	zones, err := svc.GetZones() // fetched from the API
+//	zones, err = svc.GetZones()  // served from the cache
+//
+// The cached endpoints are GetZones, GetPlans, GetPriceZones, GetTimeZones, GetServerConfigurations and GetStorages
+// listing the public storages, the templates or the CD-ROMs. Every other method is passed to the wrapped service.
+//
+// Each call returns a copy of the cached result, so callers may modify it. Concurrent calls for an endpoint that
+// isn't cached share a single request to the API. Failed requests aren't cached.
+package cache
+
+import (
+	"bytes"
+	"context"
+	"encoding/gob"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/UpCloudLtd/upcloud-go-api/upcloud"
+	"github.com/UpCloudLtd/upcloud-go-api/upcloud/request"
+	"github.com/UpCloudLtd/upcloud-go-api/upcloud/service"
+)
+
+// Endpoint identifies a cached endpoint
+type Endpoint string
+
+// The cached endpoints
+const (
+	Zones                Endpoint = "zones"
+	Plans                Endpoint = "plans"
+	PriceZones           Endpoint = "price_zones"
+	TimeZones            Endpoint = "timezones"
+	ServerConfigurations Endpoint = "server_configurations"
+	Storages             Endpoint = "storages"
+)
+
+// Endpoints are all the cached endpoints
+var Endpoints = []Endpoint{Zones, Plans, PriceZones, TimeZones, ServerConfigurations, Storages}
+
+// DefaultTTLs are the times the results of the endpoints are cached unless others are specified with WithTTL. The
+// prices and the storages, as the templates get security updates, are refreshed more often than the rest.
+var DefaultTTLs = map[Endpoint]time.Duration{
+	Zones:                24 * time.Hour,
+	Plans:                24 * time.Hour,
+	PriceZones:           time.Hour,
+	TimeZones:            24 * time.Hour,
+	ServerConfigurations: 24 * time.Hour,
+	Storages:             time.Hour,
+}
+
+// Option configures a Service
+type Option func(s *Service)
+
+// WithTTL sets the time the results of an endpoint are cached. A ttl of zero or less disables caching the endpoint.
+func WithTTL(endpoint Endpoint, ttl time.Duration) Option {
+	return func(s *Service) {
+		s.ttls[endpoint] = ttl
+	}
+}
+
+// WithPersistence keeps the cached results in files in a directory as well as in memory, so that they survive
+// restarts and can be shared by processes. The directory is created if it doesn't exist. The zones and the plans
+// available depend on the account, so processes using different accounts shouldn't share a directory.
+//
+// Persisting is best-effort: results that can't be written or read are fetched from the API instead.
+func WithPersistence(dir string) Option {
+	return func(s *Service) {
+		s.dir = dir
+	}
+}
+
+// Service is a service.API caching the results of the catalog endpoints, see New. It's safe for concurrent use.
+type Service struct {
+	service.API
+
+	ttls map[Endpoint]time.Duration
+	dir  string
+	now  func() time.Time
+
+	mu          sync.Mutex
+	entries     map[key]*entry
+	calls       map[key]*call
+	generations map[Endpoint]uint64
+
+	// disk serializes writing and removing the persisted results, which is done without holding mu
+	disk sync.Mutex
+}
+
+var _ service.API = (*Service)(nil)
+
+// key identifies a cached result: the endpoint and, for endpoints returning different results for different
+// requests, the URL of the request
+type key struct {
+	endpoint Endpoint
+	url      string
+}
+
+// entry is a cached result
+type entry struct {
+	// Data is the result encoded with encoding/gob
+	Data    []byte
+	Fetched time.Time
+}
+
+// call is a request to the API in progress that's shared by the concurrent callers
+type call struct {
+	done  chan struct{}
+	entry *entry
+	err   error
+}
+
+// New returns a service caching the results of the catalog endpoints of api, which is usually a *service.Service
+func New(api service.API, opts ...Option) *Service {
+	s := &Service{
+		API:         api,
+		ttls:        make(map[Endpoint]time.Duration),
+		now:         time.Now,
+		entries:     make(map[key]*entry),
+		calls:       make(map[key]*call),
+		generations: make(map[Endpoint]uint64),
+	}
+	for endpoint, ttl := range DefaultTTLs {
+		s.ttls[endpoint] = ttl
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	return s
+}
+
+// Invalidate removes the cached results of the endpoints, so that they're fetched from the API on their next use.
+// Requests to the API already in progress don't cache their results.
+func (s *Service) Invalidate(endpoints ...Endpoint) {
+	s.mu.Lock()
+	for _, endpoint := range endpoints {
+		for k := range s.entries {
+			if k.endpoint == endpoint {
+				delete(s.entries, k)
+			}
+		}
+		for k := range s.calls {
+			if k.endpoint == endpoint {
+				delete(s.calls, k)
+			}
+		}
+		s.generations[endpoint]++
+	}
+	s.mu.Unlock()
+
+	s.disk.Lock()
+	defer s.disk.Unlock()
+
+	for _, endpoint := range endpoints {
+		s.removePersisted(endpoint)
+	}
+}
+
+// InvalidateAll removes the cached results of all the endpoints
+func (s *Service) InvalidateAll() {
+	s.Invalidate(Endpoints...)
+}
+
+// GetZones returns the available zones from the cache
+func (s *Service) GetZones() (*upcloud.Zones, error) {
+	return s.GetZonesWithContext(context.Background())
+}
+
+// GetZonesWithContext is the same as GetZones but binds the API request(s) to the specified context
+func (s *Service) GetZonesWithContext(ctx context.Context) (*upcloud.Zones, error) {
+	if !s.cached(Zones) {
+		return s.API.GetZonesWithContext(ctx)
+	}
+
+	zones := upcloud.Zones{}
+	err := s.get(ctx, key{endpoint: Zones}, &zones, func(ctx context.Context) (interface{}, error) {
+		return s.API.GetZonesWithContext(ctx)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &zones, nil
+}
+
+// GetPlans returns the available service plans from the cache
+func (s *Service) GetPlans() (*upcloud.Plans, error) {
+	return s.GetPlansWithContext(context.Background())
+}
+
+// GetPlansWithContext is the same as GetPlans but binds the API request(s) to the specified context
+func (s *Service) GetPlansWithContext(ctx context.Context) (*upcloud.Plans, error) {
+	if !s.cached(Plans) {
+		return s.API.GetPlansWithContext(ctx)
+	}
+
+	plans := upcloud.Plans{}
+	err := s.get(ctx, key{endpoint: Plans}, &plans, func(ctx context.Context) (interface{}, error) {
+		return s.API.GetPlansWithContext(ctx)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &plans, nil
+}
+
+// GetPriceZones returns the available price zones and their corresponding prices from the cache
+func (s *Service) GetPriceZones() (*upcloud.PriceZones, error) {
+	return s.GetPriceZonesWithContext(context.Background())
+}
+
+// GetPriceZonesWithContext is the same as GetPriceZones but binds the API request(s) to the specified context
+func (s *Service) GetPriceZonesWithContext(ctx context.Context) (*upcloud.PriceZones, error) {
+	if !s.cached(PriceZones) {
+		return s.API.GetPriceZonesWithContext(ctx)
+	}
+
+	priceZones := upcloud.PriceZones{}
+	err := s.get(ctx, key{endpoint: PriceZones}, &priceZones, func(ctx context.Context) (interface{}, error) {
+		return s.API.GetPriceZonesWithContext(ctx)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &priceZones, nil
+}
+
+// GetTimeZones returns the available timezones from the cache
+func (s *Service) GetTimeZones() (*upcloud.TimeZones, error) {
+	return s.GetTimeZonesWithContext(context.Background())
+}
+
+// GetTimeZonesWithContext is the same as GetTimeZones but binds the API request(s) to the specified context
+func (s *Service) GetTimeZonesWithContext(ctx context.Context) (*upcloud.TimeZones, error) {
+	if !s.cached(TimeZones) {
+		return s.API.GetTimeZonesWithContext(ctx)
+	}
+
+	timeZones := upcloud.TimeZones{}
+	err := s.get(ctx, key{endpoint: TimeZones}, &timeZones, func(ctx context.Context) (interface{}, error) {
+		return s.API.GetTimeZonesWithContext(ctx)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &timeZones, nil
+}
+
+// GetServerConfigurations returns the available pre-configured server configurations from the cache
+func (s *Service) GetServerConfigurations() (*upcloud.ServerConfigurations, error) {
+	return s.GetServerConfigurationsWithContext(context.Background())
+}
+
+// GetServerConfigurationsWithContext is the same as GetServerConfigurations but binds the API request(s) to the
+// specified context
+func (s *Service) GetServerConfigurationsWithContext(ctx context.Context) (*upcloud.ServerConfigurations, error) {
+	if !s.cached(ServerConfigurations) {
+		return s.API.GetServerConfigurationsWithContext(ctx)
+	}
+
+	serverConfigurations := upcloud.ServerConfigurations{}
+	err := s.get(ctx, key{endpoint: ServerConfigurations}, &serverConfigurations, func(ctx context.Context) (interface{}, error) {
+		return s.API.GetServerConfigurationsWithContext(ctx)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &serverConfigurations, nil
+}
+
+// GetStorages returns the public storages, the templates or the CD-ROMs from the cache when the request asks for
+// them, and passes other requests to the wrapped service
+func (s *Service) GetStorages(r *request.GetStoragesRequest) (*upcloud.Storages, error) {
+	return s.GetStoragesWithContext(context.Background(), r)
+}
+
+// GetStoragesWithContext is the same as GetStorages but binds the API request(s) to the specified context
+func (s *Service) GetStoragesWithContext(ctx context.Context, r *request.GetStoragesRequest) (*upcloud.Storages, error) {
+	if r == nil || !cachedStorages[r.RequestURL()] || !s.cached(Storages) {
+		return s.API.GetStoragesWithContext(ctx, r)
+	}
+
+	storages := upcloud.Storages{}
+	err := s.get(ctx, key{endpoint: Storages, url: r.RequestURL()}, &storages, func(ctx context.Context) (interface{}, error) {
+		return s.API.GetStoragesWithContext(ctx, r)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &storages, nil
+}
+
+// The storage listings that are cached, by the URL of their request
+var cachedStorages = map[string]bool{
+	(&request.GetStoragesRequest{Access: upcloud.StorageAccessPublic}).RequestURL(): true,
+	(&request.GetStoragesRequest{Type: upcloud.StorageTypeTemplate}).RequestURL():   true,
+	(&request.GetStoragesRequest{Type: upcloud.StorageTypeCDROM}).RequestURL():      true,
+}
+
+// Returns whether the results of the endpoint are cached
+func (s *Service) cached(endpoint Endpoint) bool {
+	return s.ttls[endpoint] > 0
+}
+
+// Decodes the cached result into out, fetching it first if it isn't cached or has expired
+func (s *Service) get(ctx context.Context, k key, out interface{}, fetch func(ctx context.Context) (interface{}, error)) error {
+	for {
+		s.mu.Lock()
+		if e, ok := s.entries[k]; ok && s.fresh(k.endpoint, e) {
+			s.mu.Unlock()
+			return gob.NewDecoder(bytes.NewReader(e.Data)).Decode(out)
+		}
+
+		c, ok := s.calls[k]
+		if !ok {
+			c = &call{done: make(chan struct{})}
+			s.calls[k] = c
+			generation := s.generations[k.endpoint]
+			s.mu.Unlock()
+
+			s.fill(ctx, k, c, generation, fetch)
+		} else {
+			s.mu.Unlock()
+		}
+
+		select {
+		case <-c.done:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+
+		// The request is bound to the context of the caller that made it, so the callers waiting for it try again
+		// if that caller gave up
+		if c.err != nil && ctx.Err() == nil && (errors.Is(c.err, context.Canceled) || errors.Is(c.err, context.DeadlineExceeded)) {
+			continue
+		}
+		if c.err != nil {
+			return c.err
+		}
+
+		return gob.NewDecoder(bytes.NewReader(c.entry.Data)).Decode(out)
+	}
+}
+
+// Completes the call by reading the result from the disk or fetching it from the API, and caches the
+// result unless its endpoint has been invalidated in the meantime
+func (s *Service) fill(ctx context.Context, k key, c *call, generation uint64, fetch func(ctx context.Context) (interface{}, error)) {
+	defer close(c.done)
+
+	e, fetched := s.readPersisted(k), false
+	if e == nil {
+		result, err := fetch(ctx)
+		if err == nil {
+			e, err = s.encode(result)
+		}
+		if err != nil {
+			c.err = err
+			s.mu.Lock()
+			if s.calls[k] == c {
+				delete(s.calls, k)
+			}
+			s.mu.Unlock()
+			return
+		}
+		fetched = true
+	}
+	c.entry = e
+
+	s.mu.Lock()
+	if s.calls[k] == c {
+		delete(s.calls, k)
+	}
+	current := s.generations[k.endpoint] == generation
+	if current {
+		s.entries[k] = e
+	}
+	s.mu.Unlock()
+
+	if current && fetched {
+		s.persist(k, e, generation)
+	}
+}
+
+// Encodes a result fetched from the API into a cache entry
+func (s *Service) encode(result interface{}) (*entry, error) {
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(result); err != nil {
+		return nil, err
+	}
+
+	return &entry{Data: buf.Bytes(), Fetched: s.now()}, nil
+}
+
+// Returns whether the entry of the endpoint hasn't expired
+func (s *Service) fresh(endpoint Endpoint, e *entry) bool {
+	return s.now().Before(e.Fetched.Add(s.ttls[endpoint]))
+}
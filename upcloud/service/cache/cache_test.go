@@ -0,0 +1,366 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/UpCloudLtd/upcloud-go-api/upcloud"
+	"github.com/UpCloudLtd/upcloud-go-api/upcloud/emulator"
+	"github.com/UpCloudLtd/upcloud-go-api/upcloud/request"
+	"github.com/UpCloudLtd/upcloud-go-api/upcloud/service"
+	"github.com/UpCloudLtd/upcloud-go-api/upcloud/service/mock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testClock is a clock moved forward by the tests
+type testClock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+func (c *testClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.now
+}
+
+func (c *testClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.now = c.now.Add(d)
+}
+
+// Returns a cache of a mock counting the calls to GetZones
+func newCountingCache(opts ...Option) (*Service, *mock.Mock, *testClock) {
+	m := &mock.Mock{
+		GetZonesFunc: func(ctx context.Context) (*upcloud.Zones, error) {
+			return &upcloud.Zones{Zones: []upcloud.Zone{{ID: "fi-hel1", Description: "Helsinki #1", Public: upcloud.True}}}, nil
+		},
+	}
+	clock := &testClock{now: time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)}
+
+	s := New(m, opts...)
+	s.now = clock.Now
+	return s, m, clock
+}
+
+// Creates a temporary directory that's removed when the test completes
+func tempDir(t *testing.T) string {
+	dir, err := ioutil.TempDir("", "cache")
+	require.NoError(t, err)
+	t.Cleanup(func() { os.RemoveAll(dir) })
+
+	return dir
+}
+
+// TestCache tests that the results of the catalog endpoints are served from the cache
+func TestCache(t *testing.T) {
+	srv := emulator.NewServer()
+	defer srv.Close()
+
+	s := New(service.New(srv.Client()))
+	expected, err := service.New(srv.Client()).GetPlans()
+	require.NoError(t, err)
+
+	for i := 0; i < 2; i++ {
+		zones, err := s.GetZones()
+		require.NoError(t, err)
+		assert.NotEmpty(t, zones.Zones)
+		plans, err := s.GetPlans()
+		require.NoError(t, err)
+		assert.Equal(t, expected, plans)
+		priceZones, err := s.GetPriceZones()
+		require.NoError(t, err)
+		assert.NotEmpty(t, priceZones.PriceZones)
+		timeZones, err := s.GetTimeZones()
+		require.NoError(t, err)
+		assert.NotEmpty(t, timeZones.TimeZones)
+		configurations, err := s.GetServerConfigurations()
+		require.NoError(t, err)
+		assert.NotEmpty(t, configurations.ServerConfigurations)
+		storages, err := s.GetStorages(&request.GetStoragesRequest{Access: upcloud.StorageAccessPublic})
+		require.NoError(t, err)
+		assert.NotEmpty(t, storages.Storages)
+	}
+
+	// The results are copies, so modifying them doesn't affect the cache
+	plans, err := s.GetPlans()
+	require.NoError(t, err)
+	plans.Plans[0].Name = "modified"
+	plans, err = s.GetPlans()
+	require.NoError(t, err)
+	assert.Equal(t, expected, plans)
+}
+
+// TestCacheTTL tests that results are fetched again once they expire and that endpoints can be excluded
+func TestCacheTTL(t *testing.T) {
+	s, m, clock := newCountingCache(WithTTL(Zones, time.Minute))
+
+	for i := 0; i < 3; i++ {
+		_, err := s.GetZones()
+		require.NoError(t, err)
+	}
+	assert.Len(t, m.CallsTo("GetZones"), 1)
+
+	clock.Advance(59 * time.Second)
+	_, err := s.GetZonesWithContext(context.Background())
+	require.NoError(t, err)
+	assert.Len(t, m.CallsTo("GetZones"), 1)
+
+	clock.Advance(time.Second)
+	zones, err := s.GetZones()
+	require.NoError(t, err)
+	assert.Equal(t, "fi-hel1", zones.Zones[0].ID)
+	assert.Len(t, m.CallsTo("GetZones"), 2)
+
+	s, m, _ = newCountingCache(WithTTL(Zones, 0))
+	for i := 0; i < 3; i++ {
+		_, err := s.GetZones()
+		require.NoError(t, err)
+	}
+	assert.Len(t, m.CallsTo("GetZones"), 3)
+}
+
+// TestCacheStorages tests that the public storages, the templates and the CD-ROMs are cached separately and that
+// the other storage listings aren't cached
+func TestCacheStorages(t *testing.T) {
+	m := &mock.Mock{
+		GetStoragesFunc: func(ctx context.Context, r *request.GetStoragesRequest) (*upcloud.Storages, error) {
+			return &upcloud.Storages{Storages: []upcloud.Storage{{
+				UUID:   "01000000-0000-4000-8000-000030200200",
+				Access: r.Access,
+				Type:   r.Type,
+			}}}, nil
+		},
+	}
+	s := New(m, WithPersistence(filepath.Join(tempDir(t), "upcloud")))
+
+	for i := 0; i < 2; i++ {
+		storages, err := s.GetStorages(&request.GetStoragesRequest{Access: upcloud.StorageAccessPublic})
+		require.NoError(t, err)
+		assert.Equal(t, upcloud.StorageAccessPublic, storages.Storages[0].Access)
+		storages, err = s.GetStorages(&request.GetStoragesRequest{Type: upcloud.StorageTypeTemplate})
+		require.NoError(t, err)
+		assert.Equal(t, upcloud.StorageTypeTemplate, storages.Storages[0].Type)
+		storages, err = s.GetStorages(&request.GetStoragesRequest{Type: upcloud.StorageTypeCDROM})
+		require.NoError(t, err)
+		assert.Equal(t, upcloud.StorageTypeCDROM, storages.Storages[0].Type)
+		_, err = s.GetStorages(&request.GetStoragesRequest{Access: upcloud.StorageAccessPrivate})
+		require.NoError(t, err)
+		_, err = s.GetStorages(&request.GetStoragesRequest{Type: upcloud.StorageTypeBackup})
+		require.NoError(t, err)
+	}
+	assert.Len(t, m.CallsTo("GetStorages"), 7)
+
+	// Invalidating the endpoint removes all the listings
+	s.Invalidate(Storages)
+	_, err := s.GetStorages(&request.GetStoragesRequest{Type: upcloud.StorageTypeTemplate})
+	require.NoError(t, err)
+	assert.Len(t, m.CallsTo("GetStorages"), 8)
+	files, err := filepath.Glob(filepath.Join(s.dir, "storages-*.gob"))
+	require.NoError(t, err)
+	assert.Equal(t, []string{filepath.Join(s.dir, "storages-storage-template.gob")}, files)
+}
+
+// TestCacheInvalidate tests that invalidated results are fetched again
+func TestCacheInvalidate(t *testing.T) {
+	s, m, _ := newCountingCache()
+
+	_, err := s.GetZones()
+	require.NoError(t, err)
+	s.Invalidate(Plans)
+	_, err = s.GetZones()
+	require.NoError(t, err)
+	assert.Len(t, m.CallsTo("GetZones"), 1)
+
+	s.Invalidate(Zones)
+	_, err = s.GetZones()
+	require.NoError(t, err)
+	assert.Len(t, m.CallsTo("GetZones"), 2)
+
+	s.InvalidateAll()
+	_, err = s.GetZones()
+	require.NoError(t, err)
+	assert.Len(t, m.CallsTo("GetZones"), 3)
+}
+
+// TestCacheErrors tests that failed requests aren't cached
+func TestCacheErrors(t *testing.T) {
+	s, m, _ := newCountingCache()
+	zones := m.GetZonesFunc
+	m.GetZonesFunc = func(ctx context.Context) (*upcloud.Zones, error) {
+		return nil, &upcloud.Error{Status: 503, ErrorCode: "SERVICE_UNAVAILABLE"}
+	}
+
+	_, err := s.GetZones()
+	assert.True(t, errors.Is(err, upcloud.ErrServerError))
+
+	m.GetZonesFunc = zones
+	_, err = s.GetZones()
+	require.NoError(t, err)
+	assert.Len(t, m.CallsTo("GetZones"), 2)
+}
+
+// TestCacheConcurrent tests that concurrent calls share a single request to the API
+func TestCacheConcurrent(t *testing.T) {
+	s, m, _ := newCountingCache()
+	zones := m.GetZonesFunc
+	release := make(chan struct{})
+	var fetches int32
+	m.GetZonesFunc = func(ctx context.Context) (*upcloud.Zones, error) {
+		atomic.AddInt32(&fetches, 1)
+		<-release
+		return zones(ctx)
+	}
+
+	var wg sync.WaitGroup
+	results := make([]*upcloud.Zones, 10)
+	for i := range results {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			results[i], _ = s.GetZones()
+		}(i)
+	}
+
+	// Wait for the first call to reach the API, give the rest time to queue up behind it and let it complete
+	for atomic.LoadInt32(&fetches) == 0 {
+		time.Sleep(time.Millisecond)
+	}
+	time.Sleep(20 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	assert.Equal(t, int32(1), atomic.LoadInt32(&fetches))
+	for _, result := range results {
+		require.NotNil(t, result)
+		assert.Equal(t, "fi-hel1", result.Zones[0].ID)
+	}
+}
+
+// TestCacheCanceled tests that the callers waiting for a request try again when the caller that made it gives up
+func TestCacheCanceled(t *testing.T) {
+	s, m, _ := newCountingCache()
+	zones := m.GetZonesFunc
+	started := make(chan struct{})
+	m.GetZonesFunc = func(ctx context.Context) (*upcloud.Zones, error) {
+		if len(m.CallsTo("GetZones")) == 1 {
+			close(started)
+			<-ctx.Done()
+			return nil, ctx.Err()
+		}
+		return zones(ctx)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	errs := make(chan error)
+	go func() {
+		_, err := s.GetZonesWithContext(ctx)
+		errs <- err
+	}()
+	<-started
+
+	waiting := make(chan *upcloud.Zones)
+	go func() {
+		result, _ := s.GetZones()
+		waiting <- result
+	}()
+	time.Sleep(20 * time.Millisecond)
+	cancel()
+
+	assert.True(t, errors.Is(<-errs, context.Canceled))
+	result := <-waiting
+	require.NotNil(t, result)
+	assert.Equal(t, "fi-hel1", result.Zones[0].ID)
+	assert.Len(t, m.CallsTo("GetZones"), 2)
+}
+
+// TestCachePersistence tests that persisted results are shared by caches using the same directory
+func TestCachePersistence(t *testing.T) {
+	dir := filepath.Join(tempDir(t), "upcloud")
+
+	first, firstMock, clock := newCountingCache(WithPersistence(dir))
+	_, err := first.GetZones()
+	require.NoError(t, err)
+	assert.FileExists(t, filepath.Join(dir, "zones.gob"))
+
+	second, secondMock, _ := newCountingCache(WithPersistence(dir), WithTTL(Zones, time.Hour))
+	second.now = clock.Now
+	zones, err := second.GetZones()
+	require.NoError(t, err)
+	assert.Equal(t, "fi-hel1", zones.Zones[0].ID)
+	assert.Len(t, firstMock.CallsTo("GetZones"), 1)
+	assert.Len(t, secondMock.CallsTo("GetZones"), 0)
+
+	// The persisted result has expired with the shorter ttl of the third cache
+	clock.Advance(2 * time.Hour)
+	third, thirdMock, _ := newCountingCache(WithPersistence(dir), WithTTL(Zones, time.Hour))
+	third.now = clock.Now
+	_, err = third.GetZones()
+	require.NoError(t, err)
+	assert.Len(t, thirdMock.CallsTo("GetZones"), 1)
+
+	third.Invalidate(Zones)
+	_, err = os.Stat(filepath.Join(dir, "zones.gob"))
+	assert.True(t, os.IsNotExist(err))
+	// The other caches keep the results in memory
+	_, err = first.GetZones()
+	require.NoError(t, err)
+	assert.Len(t, firstMock.CallsTo("GetZones"), 1)
+
+	// A corrupt file is ignored
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "plans.gob"), []byte("corrupt"), 0600))
+	firstMock.GetPlansFunc = func(ctx context.Context) (*upcloud.Plans, error) {
+		return &upcloud.Plans{Plans: []upcloud.Plan{{Name: "1xCPU-1GB"}}}, nil
+	}
+	plans, err := first.GetPlans()
+	require.NoError(t, err)
+	assert.Equal(t, "1xCPU-1GB", plans.Plans[0].Name)
+}
+
+// TestCachePersistenceUnlocked tests that the cache can be used while the persisted results are written or removed
+func TestCachePersistenceUnlocked(t *testing.T) {
+	s, m, _ := newCountingCache(WithPersistence(tempDir(t)))
+	_, err := s.GetZones()
+	require.NoError(t, err)
+
+	// Hold the files while the plans are invalidated, as if removing them were slow
+	s.disk.Lock()
+	invalidated := make(chan struct{})
+	go func() {
+		s.Invalidate(Plans)
+		close(invalidated)
+	}()
+	for generation := uint64(0); generation == 0; {
+		time.Sleep(time.Millisecond)
+		s.mu.Lock()
+		generation = s.generations[Plans]
+		s.mu.Unlock()
+	}
+
+	done := make(chan struct{})
+	go func() {
+		_, err = s.GetZones()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("the cache was blocked by the files being removed")
+	}
+	s.disk.Unlock()
+	<-invalidated
+
+	require.NoError(t, err)
+	assert.Len(t, m.CallsTo("GetZones"), 1)
+}
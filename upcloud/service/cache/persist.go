@@ -0,0 +1,109 @@
+package cache
+
+import (
+	"encoding/gob"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Returns the name the files of the result are named after, e.g. "zones" or "storages-storage-template" for results
+// that depend on the URL of the request
+func (k key) name() string {
+	if k.url == "" {
+		return string(k.endpoint)
+	}
+
+	return string(k.endpoint) + "-" + strings.ReplaceAll(strings.Trim(k.url, "/"), "/", "-")
+}
+
+// Returns the path of the file the result is persisted in
+func (s *Service) persistedFile(k key) string {
+	return filepath.Join(s.dir, k.name()+".gob")
+}
+
+// Reads the persisted result. It returns nil if persisting is disabled or there's no result that hasn't expired.
+func (s *Service) readPersisted(k key) *entry {
+	if s.dir == "" {
+		return nil
+	}
+
+	f, err := os.Open(s.persistedFile(k))
+	if err != nil {
+		return nil
+	}
+	defer f.Close()
+
+	var e entry
+	if err := gob.NewDecoder(f).Decode(&e); err != nil {
+		return nil
+	}
+
+	if !s.fresh(k.endpoint, &e) {
+		return nil
+	}
+
+	return &e
+}
+
+// Persists the result unless its endpoint has been invalidated since the result was fetched. Invalidate changes the
+// generation before it takes disk to remove the files, so checking the generation while holding disk ensures that a
+// result is never persisted after its endpoint has been invalidated.
+func (s *Service) persist(k key, e *entry, generation uint64) {
+	if s.dir == "" {
+		return
+	}
+
+	s.disk.Lock()
+	defer s.disk.Unlock()
+
+	s.mu.Lock()
+	current := s.generations[k.endpoint] == generation
+	s.mu.Unlock()
+
+	if current {
+		s.writePersisted(k, e)
+	}
+}
+
+// Persists the result. The file is replaced atomically, so concurrent readers never see a partially
+// written result.
+func (s *Service) writePersisted(k key, e *entry) {
+	if s.dir == "" {
+		return
+	}
+
+	if err := os.MkdirAll(s.dir, 0700); err != nil {
+		return
+	}
+
+	f, err := ioutil.TempFile(s.dir, k.name()+".*.tmp")
+	if err != nil {
+		return
+	}
+	defer os.Remove(f.Name())
+
+	err = gob.NewEncoder(f).Encode(e)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return
+	}
+
+	_ = os.Rename(f.Name(), s.persistedFile(k))
+}
+
+// Removes the persisted results of the endpoint, whatever the URLs of their requests
+func (s *Service) removePersisted(endpoint Endpoint) {
+	if s.dir == "" {
+		return
+	}
+
+	_ = os.Remove(s.persistedFile(key{endpoint: endpoint}))
+	files, _ := filepath.Glob(filepath.Join(s.dir, string(endpoint)+"-*.gob"))
+	for _, file := range files {
+		_ = os.Remove(file)
+	}
+}
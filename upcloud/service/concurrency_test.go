@@ -0,0 +1,122 @@
+package service
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/UpCloudLtd/upcloud-go-api/upcloud"
+	"github.com/UpCloudLtd/upcloud-go-api/upcloud/client"
+	"github.com/UpCloudLtd/upcloud-go-api/upcloud/request"
+	"github.com/stretchr/testify/assert"
+)
+
+// The tests in this file use a single service from many goroutines. Run them with the race detector enabled
+// to verify that neither the service nor the client share mutable state between calls:
+//
+//   go test -race -run Concurrent ./...
+
+// Returns a service talking to a fake API where server actions take longer than the client timeout
+func newSlowActionService(t *testing.T) *Service {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPost {
+			time.Sleep(150 * time.Millisecond)
+		}
+		if strings.HasSuffix(r.URL.Path, "/import") && r.Method == http.MethodPost {
+			w.Write([]byte(`{"storage_import":{"state":"prepared","direct_upload_url":"` + "http://" + r.Host + `/upload"}}`))
+			return
+		}
+		if r.URL.Path == "/upload" {
+			assert.Equal(t, "application/octet-stream", r.Header.Get("Content-Type"))
+			w.Write([]byte(`{}`))
+			return
+		}
+		if strings.HasSuffix(r.URL.Path, "/import") {
+			assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+			w.Write([]byte(`{"storage_import":{"state":"pending"}}`))
+			return
+		}
+		w.Write([]byte(`{"server":{"uuid":"0077fa3d-32db-4b09-9f5f-30d9e9afb565","state":"started"}}`))
+	}))
+	t.Cleanup(srv.Close)
+
+	c := client.NewWithOptions("username", "password", client.WithBaseURL(srv.URL))
+	c.SetTimeout(50 * time.Millisecond)
+
+	return New(c)
+}
+
+// TestConcurrentServerActionTimeouts tests that the timeouts of server actions don't leak into other requests
+func TestConcurrentServerActionTimeouts(t *testing.T) {
+	svc := newSlowActionService(t)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(3)
+		go func() {
+			defer wg.Done()
+			serverDetails, err := svc.StartServer(&request.StartServerRequest{
+				UUID:    "0077fa3d-32db-4b09-9f5f-30d9e9afb565",
+				Timeout: time.Second,
+			})
+			if assert.NoError(t, err) {
+				assert.Equal(t, upcloud.ServerStateStarted, serverDetails.State)
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			_, err := svc.StopServer(&request.StopServerRequest{
+				UUID:    "0077fa3d-32db-4b09-9f5f-30d9e9afb565",
+				Timeout: time.Second,
+			})
+			assert.NoError(t, err)
+		}()
+		go func() {
+			defer wg.Done()
+			// Tagging is a POST request too, but it is bound by the client timeout
+			_, err := svc.TagServer(&request.TagServerRequest{
+				UUID: "0077fa3d-32db-4b09-9f5f-30d9e9afb565",
+				Tags: []string{"slow"},
+			})
+			assert.Error(t, err)
+		}()
+	}
+	wg.Wait()
+
+	assert.Equal(t, 50*time.Millisecond, svc.client.GetTimeout())
+}
+
+// TestConcurrentStorageImportContentType tests that the content type of a direct upload doesn't leak into other
+// requests
+func TestConcurrentStorageImportContentType(t *testing.T) {
+	svc := newSlowActionService(t)
+	svc.client.SetTimeout(time.Second)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			_, err := svc.CreateStorageImport(&request.CreateStorageImportRequest{
+				StorageUUID:    "0140c4d3-a9a3-4e5c-b2f5-50e3da8bc2ca",
+				Source:         request.StorageImportSourceDirectUpload,
+				SourceLocation: strings.NewReader("image"),
+				ContentType:    "application/octet-stream",
+			})
+			assert.NoError(t, err)
+		}()
+		go func() {
+			defer wg.Done()
+			_, err := svc.GetStorageImportDetails(&request.GetStorageImportDetailsRequest{
+				UUID: "0140c4d3-a9a3-4e5c-b2f5-50e3da8bc2ca",
+			})
+			assert.NoError(t, err)
+		}()
+	}
+	wg.Wait()
+
+	assert.Equal(t, "application/json", svc.client.GetContentType())
+}
@@ -0,0 +1,294 @@
+package service
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Kinds of schema mismatches
+const (
+	SchemaMismatchUnknownField = "unknown"
+	SchemaMismatchMissingField = "missing"
+)
+
+// SchemaCheck configures the optional comparison of API responses against the models in package upcloud. It is
+// meant for noticing early when the API changes shape, e.g. in tests or in a canary deployment.
+type SchemaCheck struct {
+	// UnknownFields reports fields that are present in a response but not in the model it is decoded into
+	UnknownFields bool
+	// MissingFields reports fields of the model that are absent from a response. Fields tagged with omitempty
+	// are never reported. Some API endpoints return abbreviated objects, so this is noisier than UnknownFields.
+	MissingFields bool
+	// Report, when set, is called with the mismatches found in a response and the response is returned normally.
+	// When Report is nil, a response with mismatches fails with a *SchemaError.
+	Report func(err *SchemaError)
+}
+
+// SchemaMismatch describes a single difference between a response and its model
+type SchemaMismatch struct {
+	// Path is the location of the field in the response, e.g. "storage.backup_rule.interval" or
+	// "servers.server[0].title"
+	Path string
+	// Kind is either SchemaMismatchUnknownField or SchemaMismatchMissingField
+	Kind string
+}
+
+// SchemaError lists the differences found between a response and the model it was decoded into
+type SchemaError struct {
+	// Type is the name of the model, e.g. "upcloud.StorageDetails"
+	Type       string
+	Mismatches []SchemaMismatch
+}
+
+// Error implements the error interface
+func (e *SchemaError) Error() string {
+	var unknown, missing []string
+	for _, m := range e.Mismatches {
+		if m.Kind == SchemaMismatchUnknownField {
+			unknown = append(unknown, m.Path)
+		} else {
+			missing = append(missing, m.Path)
+		}
+	}
+
+	var parts []string
+	if len(unknown) > 0 {
+		parts = append(parts, fmt.Sprintf("unknown fields %s", strings.Join(unknown, ", ")))
+	}
+	if len(missing) > 0 {
+		parts = append(parts, fmt.Sprintf("missing fields %s", strings.Join(missing, ", ")))
+	}
+
+	return fmt.Sprintf("response doesn't match %s: %s", e.Type, strings.Join(parts, "; "))
+}
+
+// SetSchemaCheck enables checking API responses against the models in package upcloud. Passing nil disables the
+// check, which is the default.
+func (s *Service) SetSchemaCheck(check *SchemaCheck) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.schemaCheck = check
+}
+
+// Unmarshals a response into the specified model and, if enabled, checks the response against the model
+func (s *Service) unmarshal(response []byte, v interface{}) error {
+	err := json.Unmarshal(response, v)
+	if err != nil {
+		return fmt.Errorf("unable to unmarshal JSON: %s, %w", string(response), err)
+	}
+
+	s.mu.RLock()
+	check := s.schemaCheck
+	s.mu.RUnlock()
+
+	if check == nil || (!check.UnknownFields && !check.MissingFields) {
+		return nil
+	}
+
+	schemaErr := checkSchema(response, v, check)
+	if schemaErr == nil {
+		return nil
+	}
+
+	if check.Report != nil {
+		check.Report(schemaErr)
+		return nil
+	}
+
+	return schemaErr
+}
+
+// Compares the response with the type of v and returns the mismatches found, or nil if there are none
+func checkSchema(response []byte, v interface{}, check *SchemaCheck) *SchemaError {
+	var raw interface{}
+	if err := json.Unmarshal(response, &raw); err != nil {
+		return nil
+	}
+
+	t := reflect.TypeOf(v)
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	w := schemaWalker{check: check}
+	w.walk(raw, t, "")
+
+	if len(w.mismatches) == 0 {
+		return nil
+	}
+
+	return &SchemaError{
+		Type:       t.String(),
+		Mismatches: w.mismatches,
+	}
+}
+
+var (
+	timeType        = reflect.TypeOf(time.Time{})
+	unmarshalerType = reflect.TypeOf((*json.Unmarshaler)(nil)).Elem()
+)
+
+type schemaWalker struct {
+	check      *SchemaCheck
+	mismatches []SchemaMismatch
+}
+
+func (w *schemaWalker) add(path, kind string) {
+	w.mismatches = append(w.mismatches, SchemaMismatch{Path: path, Kind: kind})
+}
+
+// Walks the raw JSON value and the type it was decoded into side by side
+func (w *schemaWalker) walk(raw interface{}, t reflect.Type, path string) {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	if raw == nil || t == timeType {
+		return
+	}
+
+	custom := reflect.PtrTo(t).Implements(unmarshalerType)
+
+	switch t.Kind() {
+	case reflect.Struct:
+		fields := jsonFields(t)
+		obj, ok := raw.(map[string]interface{})
+		if !ok {
+			return
+		}
+
+		// The custom unmarshallers of the models strip envelopes such as {"storage": {...}}, follow them
+		for custom && len(obj) == 1 {
+			key, value := singleKey(obj)
+			if _, known := fields[key]; known {
+				break
+			}
+			inner, ok := value.(map[string]interface{})
+			if !ok {
+				break
+			}
+			path = joinPath(path, key)
+			obj = inner
+		}
+
+		w.walkObject(obj, fields, path)
+	case reflect.Slice, reflect.Array:
+		// Lists are wrapped in an object, e.g. {"storage_device": [...]}
+		if obj, ok := raw.(map[string]interface{}); ok && len(obj) == 1 {
+			key, value := singleKey(obj)
+			path = joinPath(path, key)
+			raw = value
+		}
+
+		items, ok := raw.([]interface{})
+		if !ok {
+			return
+		}
+
+		for i, item := range items {
+			w.walk(item, t.Elem(), fmt.Sprintf("%s[%d]", path, i))
+		}
+	}
+}
+
+// Compares the keys of a JSON object with the fields of a struct
+func (w *schemaWalker) walkObject(obj map[string]interface{}, fields map[string]reflect.StructField, path string) {
+	keys := make([]string, 0, len(obj))
+	for key := range obj {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		field, ok := fields[key]
+		if !ok {
+			if w.check.UnknownFields {
+				w.add(joinPath(path, key), SchemaMismatchUnknownField)
+			}
+			continue
+		}
+		w.walk(obj[key], field.Type, joinPath(path, key))
+	}
+
+	if !w.check.MissingFields {
+		return
+	}
+
+	names := make([]string, 0, len(fields))
+	for name := range fields {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		if _, ok := obj[name]; ok {
+			continue
+		}
+		if strings.Contains(fields[name].Tag.Get("json"), ",omitempty") {
+			continue
+		}
+		w.add(joinPath(path, name), SchemaMismatchMissingField)
+	}
+}
+
+// Returns the fields of a struct by their JSON name, including the fields of embedded structs
+func jsonFields(t reflect.Type) map[string]reflect.StructField {
+	fields := make(map[string]reflect.StructField)
+
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		tag := field.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+
+		name := strings.Split(tag, ",")[0]
+
+		if field.Anonymous && name == "" {
+			ft := field.Type
+			if ft.Kind() == reflect.Ptr {
+				ft = ft.Elem()
+			}
+			if ft.Kind() == reflect.Struct {
+				for n, f := range jsonFields(ft) {
+					if _, ok := fields[n]; !ok {
+						fields[n] = f
+					}
+				}
+				continue
+			}
+		}
+
+		if field.PkgPath != "" {
+			continue
+		}
+
+		if name == "" {
+			name = field.Name
+		}
+		fields[name] = field
+	}
+
+	return fields
+}
+
+func singleKey(obj map[string]interface{}) (string, interface{}) {
+	for key, value := range obj {
+		return key, value
+	}
+
+	return "", nil
+}
+
+func joinPath(path, key string) string {
+	if path == "" {
+		return key
+	}
+
+	return path + "." + key
+}
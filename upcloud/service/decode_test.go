@@ -0,0 +1,131 @@
+package service
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/UpCloudLtd/upcloud-go-api/upcloud"
+	"github.com/UpCloudLtd/upcloud-go-api/upcloud/client"
+	"github.com/UpCloudLtd/upcloud-go-api/upcloud/request"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Returns a service talking to a fake API that always responds with the specified body
+func newStaticResponseService(t *testing.T, body string) *Service {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(body))
+	}))
+	t.Cleanup(srv.Close)
+
+	return New(client.NewWithOptions("username", "password", client.WithBaseURL(srv.URL)))
+}
+
+// TestDecodingErrors tests that responses that can't be decoded result in an error
+func TestDecodingErrors(t *testing.T) {
+	svc := newStaticResponseService(t, `{"zones":{"zone":"not a list"}}`)
+
+	zones, err := svc.GetZones()
+	assert.Nil(t, zones)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "unable to unmarshal JSON")
+
+	svc = newStaticResponseService(t, `<html>oops</html>`)
+
+	ips, err := svc.GetIPAddresses()
+	assert.Nil(t, ips)
+	assert.Error(t, err)
+}
+
+// TestSchemaCheckUnknownFields tests that fields missing from the models are reported with their full path
+func TestSchemaCheckUnknownFields(t *testing.T) {
+	svc := newStaticResponseService(t, `{"storage":{
+		"uuid":"01d4fcd4-e446-433b-8a9c-551a1284952e",
+		"title":"Storage",
+		"encrypted":"yes",
+		"backup_rule":{"interval":"daily","time":"0430","retention":"7","hourly":"yes"},
+		"servers":{"server":["00798b85-efdc-41ca-8021-f6ef457b8531"]}
+	}}`)
+
+	// Decoding is lenient by default
+	storage, err := svc.GetStorageDetails(&request.GetStorageDetailsRequest{UUID: "01d4fcd4-e446-433b-8a9c-551a1284952e"})
+	require.NoError(t, err)
+	assert.Equal(t, "Storage", storage.Title)
+
+	svc.SetSchemaCheck(&SchemaCheck{UnknownFields: true})
+
+	storage, err = svc.GetStorageDetails(&request.GetStorageDetailsRequest{UUID: "01d4fcd4-e446-433b-8a9c-551a1284952e"})
+	assert.Nil(t, storage)
+
+	var schemaErr *SchemaError
+	require.True(t, errors.As(err, &schemaErr))
+	assert.Equal(t, "upcloud.StorageDetails", schemaErr.Type)
+	assert.Equal(t, []SchemaMismatch{
+		{Path: "storage.backup_rule.hourly", Kind: SchemaMismatchUnknownField},
+		{Path: "storage.encrypted", Kind: SchemaMismatchUnknownField},
+	}, schemaErr.Mismatches)
+	assert.Equal(t, "response doesn't match upcloud.StorageDetails: unknown fields storage.backup_rule.hourly, storage.encrypted", err.Error())
+}
+
+// TestSchemaCheckWrappedSlices tests that the envelopes around lists are followed
+func TestSchemaCheckWrappedSlices(t *testing.T) {
+	svc := newStaticResponseService(t, `{"servers":{"server":[
+		{"uuid":"00798b85-efdc-41ca-8021-f6ef457b8531","tags":{"tag":["DEV"]}},
+		{"uuid":"009d64ef-31d1-4684-a26b-c86c955cbf46","tags":{"tag":[]},"labels":{"label":[]}}
+	]}}`)
+	svc.SetSchemaCheck(&SchemaCheck{UnknownFields: true})
+
+	_, err := svc.GetServers()
+
+	var schemaErr *SchemaError
+	require.True(t, errors.As(err, &schemaErr))
+	assert.Equal(t, []SchemaMismatch{
+		{Path: "servers.server[1].labels", Kind: SchemaMismatchUnknownField},
+	}, schemaErr.Mismatches)
+}
+
+// TestSchemaCheckMissingFields tests that absent fields are reported unless they are optional
+func TestSchemaCheckMissingFields(t *testing.T) {
+	svc := newStaticResponseService(t, `{"zones":{"zone":[{"id":"fi-hel1","description":"Helsinki #1"}]}}`)
+	svc.SetSchemaCheck(&SchemaCheck{MissingFields: true})
+
+	_, err := svc.GetZones()
+
+	var schemaErr *SchemaError
+	require.True(t, errors.As(err, &schemaErr))
+	assert.Equal(t, []SchemaMismatch{
+		{Path: "zones.zone[0].public", Kind: SchemaMismatchMissingField},
+	}, schemaErr.Mismatches)
+}
+
+// TestSchemaCheckReport tests that mismatches are passed to the report function instead of failing the request
+func TestSchemaCheckReport(t *testing.T) {
+	svc := newStaticResponseService(t, `{"zones":{"zone":[{"id":"fi-hel1","description":"Helsinki #1","public":"yes","parent_zone":""}]}}`)
+
+	var reported []*SchemaError
+	svc.SetSchemaCheck(&SchemaCheck{
+		UnknownFields: true,
+		MissingFields: true,
+		Report: func(err *SchemaError) {
+			reported = append(reported, err)
+		},
+	})
+
+	zones, err := svc.GetZones()
+	require.NoError(t, err)
+	assert.Equal(t, []upcloud.Zone{{ID: "fi-hel1", Description: "Helsinki #1", Public: upcloud.True}}, zones.Zones)
+
+	require.Len(t, reported, 1)
+	assert.Equal(t, "upcloud.Zones", reported[0].Type)
+	assert.Equal(t, []SchemaMismatch{
+		{Path: "zones.zone[0].parent_zone", Kind: SchemaMismatchUnknownField},
+	}, reported[0].Mismatches)
+
+	// Disabling the check stops the reports
+	svc.SetSchemaCheck(nil)
+	_, err = svc.GetZones()
+	require.NoError(t, err)
+	assert.Len(t, reported, 1)
+}
@@ -0,0 +1,96 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/UpCloudLtd/upcloud-go-api/upcloud/client"
+	"github.com/UpCloudLtd/upcloud-go-api/upcloud/request"
+)
+
+// Do performs a request to an endpoint the service has no method for. The request's RequestURL method gives the
+// endpoint and the method is one of GET, POST, PUT, PATCH and DELETE.
+//
+// The body is encoded as JSON, so the request types of the request package, whose MarshalJSON methods wrap the
+// fields in the envelope the API expects, can be passed as the body as is. A []byte or json.RawMessage body is sent
+// unchanged and a nil body sends none. GET and DELETE requests can't have a body.
+//
+// The response is decoded into out, a pointer to a type of the upcloud package or any other type json.Unmarshal can
+// decode into, unless out is nil. The raw response is stored in out when it's a *[]byte. Responses without a body,
+// such as those of DELETE requests, leave out untouched, except for a *[]byte, which is set to nil.
+//
+// Errors are returned as with the other methods of the service, e.g. as an *upcloud.Error when the API responds with
+// an error, and the request is retried, logged, validated and planned in dry-run mode according to the settings of
+// the client and the service.
+func (s *Service) Do(method string, r request.Request, body, out interface{}) error {
+	return s.DoWithContext(context.Background(), method, r, body, out)
+}
+
+// DoWithContext is the same as Do but binds the API request(s) to the specified context and applies the options to
+// the request
+func (s *Service) DoWithContext(ctx context.Context, method string, r request.Request, body, out interface{}, opts ...client.RequestOption) error {
+	method = strings.ToUpper(method)
+	switch method {
+	case http.MethodGet, http.MethodDelete:
+		if body != nil {
+			return fmt.Errorf("%s requests can't have a body", method)
+		}
+	case http.MethodPost, http.MethodPut, http.MethodPatch:
+	default:
+		return fmt.Errorf("unsupported method %s", method)
+	}
+
+	if v, ok := r.(request.Validator); ok {
+		if err := s.validate(v); err != nil {
+			return err
+		}
+	}
+
+	var requestBody []byte
+	switch b := body.(type) {
+	case nil:
+	case []byte:
+		requestBody = b
+	case json.RawMessage:
+		requestBody = b
+	default:
+		var err error
+		requestBody, err = json.Marshal(body)
+		if err != nil {
+			return fmt.Errorf("unable to marshal request body: %w", err)
+		}
+	}
+
+	// The API responds to DELETE requests without a body
+	if method == http.MethodDelete {
+		out = nil
+	}
+
+	if out == nil || (s.client.GetDryRun() != nil && method != http.MethodGet) {
+		return s.perform(ctx, method, r, requestBody, out, opts...)
+	}
+
+	response, err := s.send(ctx, method, s.client.CreateRequestURL(r.RequestURL()), requestBody, opts...)
+	if err != nil {
+		return err
+	}
+
+	// Successful responses may have no body, e.g. 204 No Content, in which case there's nothing to decode
+	if raw, ok := out.(*[]byte); ok {
+		if len(response) == 0 {
+			*raw = nil
+		} else {
+			*raw = response
+		}
+		return nil
+	}
+
+	if len(response) == 0 {
+		return nil
+	}
+
+	return s.unmarshal(response, out)
+}
@@ -0,0 +1,113 @@
+package service
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/UpCloudLtd/upcloud-go-api/upcloud"
+	"github.com/UpCloudLtd/upcloud-go-api/upcloud/client"
+	"github.com/UpCloudLtd/upcloud-go-api/upcloud/emulator"
+	"github.com/UpCloudLtd/upcloud-go-api/upcloud/request"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// endpointRequest is a request to an arbitrary endpoint
+type endpointRequest string
+
+func (r endpointRequest) RequestURL() string {
+	return string(r)
+}
+
+// TestDo tests that requests to arbitrary endpoints are performed like those of the other methods
+func TestDo(t *testing.T) {
+	srv := emulator.NewServer()
+	defer srv.Close()
+
+	svc := New(srv.Client())
+
+	var zones upcloud.Zones
+	require.NoError(t, svc.Do(http.MethodGet, endpointRequest("/zone"), nil, &zones))
+	assert.NotEmpty(t, zones.Zones)
+
+	var raw []byte
+	require.NoError(t, svc.Do("get", endpointRequest("/zone"), nil, &raw))
+	assert.Contains(t, string(raw), `"zones"`)
+
+	// The request types wrap the body in the envelope the API expects
+	var tag upcloud.Tag
+	createTag := &request.CreateTagRequest{Tag: upcloud.Tag{Name: "DEV", Description: "Development"}}
+	require.NoError(t, svc.Do(http.MethodPost, createTag, createTag, &tag))
+	assert.Equal(t, "DEV", tag.Name)
+	assert.Equal(t, "Development", tag.Description)
+
+	require.NoError(t, svc.Do(http.MethodPut, endpointRequest("/tag/DEV"), []byte(`{"tag":{"description":"Dev"}}`), nil))
+	tags, err := svc.GetTags()
+	require.NoError(t, err)
+	require.Len(t, tags.Tags, 1)
+	assert.Equal(t, "Dev", tags.Tags[0].Description)
+
+	require.NoError(t, svc.Do(http.MethodDelete, &request.DeleteTagRequest{Name: "DEV"}, nil, &tag))
+	tags, err = svc.GetTags()
+	require.NoError(t, err)
+	assert.Empty(t, tags.Tags)
+
+	err = svc.Do(http.MethodGet, endpointRequest("/server/0077fa3d-32db-4b09-9f5f-30d9e9afb565"), nil, &upcloud.ServerDetails{})
+	var serviceError *upcloud.Error
+	require.True(t, errors.As(err, &serviceError))
+	assert.True(t, errors.Is(err, upcloud.ErrNotFound))
+	assert.Equal(t, http.MethodGet, serviceError.Method)
+
+	assert.EqualError(t, svc.Do(http.MethodDelete, endpointRequest("/tag/DEV"), createTag, nil), "DELETE requests can't have a body")
+	assert.EqualError(t, svc.Do("TRACE", endpointRequest("/tag"), nil, nil), "unsupported method TRACE")
+
+	svc.SetRequestValidation(true)
+	err = svc.Do(http.MethodPost, &request.CreateTagRequest{}, nil, nil)
+	var validationError *request.ValidationError
+	assert.True(t, errors.As(err, &validationError))
+}
+
+// TestDoNoContent tests that responses without a body aren't decoded
+func TestDoNoContent(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer srv.Close()
+
+	svc := New(client.NewWithOptions("username", "password", client.WithBaseURL(srv.URL)))
+
+	tag := upcloud.Tag{Name: "DEV"}
+	require.NoError(t, svc.Do(http.MethodPut, endpointRequest("/tag/DEV"), []byte(`{"tag":{"description":"Dev"}}`), &tag))
+	assert.Equal(t, upcloud.Tag{Name: "DEV"}, tag)
+
+	raw := []byte("stale")
+	require.NoError(t, svc.Do(http.MethodPost, endpointRequest("/tag"), []byte(`{}`), &raw))
+	assert.Nil(t, raw)
+
+	require.NoError(t, svc.Do(http.MethodDelete, endpointRequest("/tag/DEV"), nil, &raw))
+}
+
+// TestDoDryRun tests that requests performed with Do are planned in dry-run mode
+func TestDoDryRun(t *testing.T) {
+	srv := emulator.NewServer()
+	defer srv.Close()
+
+	plan := &client.Plan{}
+	svc := New(srv.Client(client.WithDryRun(plan)))
+
+	var zones upcloud.Zones
+	require.NoError(t, svc.Do(http.MethodGet, endpointRequest("/zone"), nil, &zones))
+	assert.NotEmpty(t, zones.Zones)
+
+	var serverDetails upcloud.ServerDetails
+	require.NoError(t, svc.Do(http.MethodPost, &request.CreateServerRequest{Title: "web-1"}, map[string]interface{}{"server": nil}, &serverDetails))
+	assert.Equal(t, "web-1", serverDetails.Title)
+	assert.True(t, IsPlaceholderUUID(serverDetails.UUID))
+
+	requests := plan.Requests()
+	require.Len(t, requests, 1)
+	assert.Equal(t, "request.CreateServerRequest", requests[0].Type)
+	assert.JSONEq(t, `{"server":null}`, string(requests[0].Body))
+}
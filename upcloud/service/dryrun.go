@@ -0,0 +1,95 @@
+package service
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/UpCloudLtd/upcloud-go-api/upcloud"
+	"github.com/UpCloudLtd/upcloud-go-api/upcloud/request"
+)
+
+// placeholderUUIDPrefix starts the UUIDs of the resources that are created in dry-run mode. The rest of the UUID is
+// the number of the request in the plan.
+const placeholderUUIDPrefix = "00000000-0000-4000-8000-"
+
+// IsPlaceholderUUID returns whether the UUID belongs to a placeholder result returned in dry-run mode, i.e. to a
+// resource that doesn't exist
+func IsPlaceholderUUID(uuid string) bool {
+	return strings.HasPrefix(uuid, placeholderUUIDPrefix)
+}
+
+// Returns the placeholder UUID of the resource created by the nth request of a plan
+func placeholderUUID(n int) string {
+	return fmt.Sprintf("%s%012d", placeholderUUIDPrefix, n)
+}
+
+// Requests that create a new resource from the one identified by their UUID. Their placeholder results get a new UUID
+// rather than the one of the request.
+var derivingRequests = map[reflect.Type]bool{
+	reflect.TypeOf(request.CloneStorageRequest{}):      true,
+	reflect.TypeOf(request.TemplatizeStorageRequest{}): true,
+	reflect.TypeOf(request.CreateBackupRequest{}):      true,
+}
+
+// Fills v, a pointer to a result struct, with a placeholder for the result of the nth request of a plan. The fields
+// the result has in common with the request are copied from the request, e.g. the title of a server. Results
+// without a UUID, or that are new resources, get a placeholder UUID. IP addresses get a placeholder address from the
+// ranges reserved for documentation.
+func fillPlaceholder(v interface{}, r request.Request, n int) {
+	ptr := reflect.ValueOf(v)
+	if ptr.Kind() != reflect.Ptr || ptr.IsNil() {
+		return
+	}
+	result := ptr.Elem()
+	req := reflect.Indirect(reflect.ValueOf(r))
+	if result.Kind() != reflect.Struct || req.Kind() != reflect.Struct {
+		return
+	}
+
+	// Requests like CreateTagRequest wrap the whole result
+	for i := 0; i < req.NumField(); i++ {
+		if req.Type().Field(i).PkgPath == "" && req.Field(i).Type() == result.Type() {
+			result.Set(req.Field(i))
+		}
+	}
+	copyFields(result, req)
+
+	if uuid := result.FieldByName("UUID"); uuid.IsValid() && uuid.Kind() == reflect.String {
+		// The UUID of a server is in the ServerUUID field of the requests acting on it, e.g. AttachStorageRequest
+		kind := strings.TrimSuffix(result.Type().Name(), "Details") + "UUID"
+		if other := req.FieldByName(kind); uuid.String() == "" && other.IsValid() && other.Kind() == reflect.String {
+			uuid.SetString(other.String())
+		}
+		if uuid.String() == "" || derivingRequests[req.Type()] {
+			uuid.SetString(placeholderUUID(n))
+		}
+	}
+
+	if ip, ok := v.(*upcloud.IPAddress); ok && ip.Address == "" {
+		ip.Address = fmt.Sprintf("192.0.2.%d", n%256)
+		if ip.Family == upcloud.IPAddressFamilyIPv6 {
+			ip.Address = fmt.Sprintf("2001:db8::%x", n)
+		}
+	}
+}
+
+// Copies the exported fields of the request, including those of embedded structs, to the fields of the result with
+// the same name and type
+func copyFields(result, req reflect.Value) {
+	for i := 0; i < req.NumField(); i++ {
+		field := req.Type().Field(i)
+		if field.PkgPath != "" {
+			continue
+		}
+		if field.Anonymous && field.Type.Kind() == reflect.Struct {
+			copyFields(result, req.Field(i))
+			continue
+		}
+
+		target := result.FieldByName(field.Name)
+		if target.IsValid() && target.CanSet() && field.Type.AssignableTo(target.Type()) && !req.Field(i).IsZero() {
+			target.Set(req.Field(i))
+		}
+	}
+}
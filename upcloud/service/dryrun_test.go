@@ -0,0 +1,126 @@
+package service
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/UpCloudLtd/upcloud-go-api/upcloud"
+	"github.com/UpCloudLtd/upcloud-go-api/upcloud/client"
+	"github.com/UpCloudLtd/upcloud-go-api/upcloud/emulator"
+	"github.com/UpCloudLtd/upcloud-go-api/upcloud/request"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestDryRun tests that the requests of multi-step code are planned with placeholder results in dry-run mode
+func TestDryRun(t *testing.T) {
+	srv := emulator.NewServer()
+	defer srv.Close()
+
+	plan := &client.Plan{}
+	svc := New(srv.Client(client.WithDryRun(plan)))
+
+	zones, err := svc.GetZones()
+	require.NoError(t, err)
+	assert.NotEmpty(t, zones.Zones)
+
+	serverDetails, err := svc.CreateServer(&request.CreateServerRequest{
+		Zone:             "fi-hel2",
+		Title:            "web-1",
+		Hostname:         "web-1.example.com",
+		Plan:             "1xCPU-2GB",
+		PasswordDelivery: request.PasswordDeliveryNone,
+		StorageDevices: []request.CreateServerStorageDevice{
+			{Action: request.CreateServerStorageDeviceActionClone, Storage: "01000000-0000-4000-8000-000030200200", Title: "disk1", Size: 10},
+		},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "00000000-0000-4000-8000-000000000001", serverDetails.UUID)
+	assert.True(t, IsPlaceholderUUID(serverDetails.UUID))
+	assert.Equal(t, "web-1", serverDetails.Title)
+	assert.Equal(t, "fi-hel2", serverDetails.Zone)
+	assert.Equal(t, "1xCPU-2GB", serverDetails.Plan)
+
+	// Waiting returns right away as nothing changes
+	started := time.Now()
+	serverDetails, err = svc.WaitForServerState(&request.WaitForServerStateRequest{
+		UUID:         serverDetails.UUID,
+		DesiredState: upcloud.ServerStateStarted,
+		Timeout:      time.Minute,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, upcloud.ServerStateStarted, serverDetails.State)
+	assert.True(t, time.Since(started) < time.Second)
+
+	serverDetails, err = svc.AttachStorage(&request.AttachStorageRequest{
+		ServerUUID:  serverDetails.UUID,
+		StorageUUID: "01c8df16-7fbf-4b0c-8f3e-f4e4d1b2e3a6",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "00000000-0000-4000-8000-000000000001", serverDetails.UUID)
+
+	storageDetails, err := svc.CloneStorage(&request.CloneStorageRequest{UUID: "01c8df16-7fbf-4b0c-8f3e-f4e4d1b2e3a6", Title: "copy"})
+	require.NoError(t, err)
+	assert.Equal(t, "00000000-0000-4000-8000-000000000003", storageDetails.UUID)
+	assert.Equal(t, "copy", storageDetails.Title)
+
+	ipAddress, err := svc.AssignIPAddress(&request.AssignIPAddressRequest{
+		ServerUUID: serverDetails.UUID,
+		Family:     upcloud.IPAddressFamilyIPv6,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "2001:db8::4", ipAddress.Address)
+	assert.Equal(t, serverDetails.UUID, ipAddress.ServerUUID)
+
+	tag, err := svc.CreateTag(&request.CreateTagRequest{Tag: upcloud.Tag{Name: "WEB", Servers: []string{serverDetails.UUID}}})
+	require.NoError(t, err)
+	assert.Equal(t, upcloud.Tag{Name: "WEB", Servers: []string{serverDetails.UUID}}, *tag)
+
+	require.NoError(t, svc.DeleteServer(&request.DeleteServerRequest{UUID: serverDetails.UUID}))
+
+	// Nothing was created
+	servers, err := svc.GetServers()
+	require.NoError(t, err)
+	assert.Empty(t, servers.Servers)
+
+	requests := plan.Requests()
+	require.Len(t, requests, 6)
+	assert.Equal(t, http.MethodPost, requests[0].Method)
+	assert.Equal(t, srv.URL+"/1.3/server", requests[0].URL)
+	assert.Equal(t, "request.CreateServerRequest", requests[0].Type)
+	assert.Contains(t, string(requests[0].Body), `"title":"web-1"`)
+	assert.IsType(t, &request.CreateServerRequest{}, requests[0].Request)
+	assert.IsType(t, &upcloud.ServerDetails{}, requests[0].Result)
+	assert.Equal(t, "request.AttachStorageRequest", requests[1].Type)
+	assert.Equal(t, srv.URL+"/1.3/server/00000000-0000-4000-8000-000000000001/storage/attach", requests[1].URL)
+	assert.Equal(t, http.MethodDelete, requests[5].Method)
+	assert.Equal(t, "request.DeleteServerRequest", requests[5].Type)
+	assert.Nil(t, requests[5].Body)
+	assert.Nil(t, requests[5].Result)
+}
+
+// TestDryRunWait tests that waiting for a custom condition returns its dry-run value without polling in dry-run mode
+func TestDryRunWait(t *testing.T) {
+	srv := emulator.NewServer()
+	defer srv.Close()
+
+	svc := New(srv.Client(client.WithDryRun(&client.Plan{})))
+
+	value, err := svc.Wait(&WaitCondition{
+		Resource: "network 03e4970d-7791-4b80-a892-682ae0faf46b",
+		Target:   "be renamed",
+		Timeout:  time.Minute,
+		Poll: func(ctx context.Context) (string, interface{}, error) {
+			t.Fatal("polled in dry-run mode")
+			return "", nil, nil
+		},
+		Done: func(state string) bool {
+			return state == "New name"
+		},
+		DryRunValue: &upcloud.Network{Name: "New name"},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, &upcloud.Network{Name: "New name"}, value)
+}
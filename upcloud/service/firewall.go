@@ -0,0 +1,164 @@
+package service
+
+import (
+	"context"
+	"strings"
+
+	"github.com/UpCloudLtd/upcloud-go-api/upcloud"
+	"github.com/UpCloudLtd/upcloud-go-api/upcloud/request"
+)
+
+// SyncFirewallRules brings the firewall rules of a server to the specified rules. In the incremental mode only the
+// rules that differ are deleted and created, so the rules that stay are never missing, while in the replace mode the
+// whole rule set is replaced in one request. Nothing is changed when the rules are already the same.
+func (s *Service) SyncFirewallRules(r *request.SyncFirewallRulesRequest) (*upcloud.FirewallRuleSync, error) {
+	return s.SyncFirewallRulesWithContext(context.Background(), r)
+}
+
+// SyncFirewallRulesWithContext is the same as SyncFirewallRules but binds the API request(s) to the specified context
+func (s *Service) SyncFirewallRulesWithContext(ctx context.Context, r *request.SyncFirewallRulesRequest) (*upcloud.FirewallRuleSync, error) {
+	if err := s.validate(r); err != nil {
+		return nil, err
+	}
+
+	current, err := s.GetFirewallRulesWithContext(ctx, &request.GetFirewallRulesRequest{ServerUUID: r.ServerUUID})
+	if err != nil {
+		return nil, err
+	}
+
+	sync := &upcloud.FirewallRuleSync{Changes: DiffFirewallRules(current.FirewallRules, r.FirewallRules)}
+	if len(sync.Changes) == 0 {
+		return sync, nil
+	}
+
+	if r.Mode == request.FirewallRulesSyncModeReplace {
+		rules := make(request.FirewallRuleSlice, len(r.FirewallRules))
+		for i, rule := range r.FirewallRules {
+			rule.Position = 0
+			rules[i] = rule
+		}
+		err := s.CreateFirewallRulesWithContext(ctx, &request.CreateFirewallRulesRequest{
+			ServerUUID:    r.ServerUUID,
+			FirewallRules: rules,
+		})
+		if err != nil {
+			return nil, err
+		}
+		sync.Replaced = true
+		return sync, nil
+	}
+
+	for _, change := range sync.Changes {
+		switch change.Action {
+		case upcloud.FirewallRuleChangeDelete:
+			err = s.DeleteFirewallRuleWithContext(ctx, &request.DeleteFirewallRuleRequest{
+				ServerUUID: r.ServerUUID,
+				Position:   change.Position,
+			})
+		case upcloud.FirewallRuleChangeCreate:
+			_, err = s.CreateFirewallRuleWithContext(ctx, &request.CreateFirewallRuleRequest{
+				ServerUUID:   r.ServerUUID,
+				FirewallRule: change.Rule,
+			})
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return sync, nil
+}
+
+// DiffFirewallRules returns the fewest changes that turn the current firewall rules into the desired ones, ignoring
+// the positions of the rules, the case of their families and whether single port and address ranges have an end. The
+// rules common to both, in the same order, are kept. The deletions come first, from the last position to the first,
+// followed by the creations from the first position to the last, so the positions of the changes are valid when
+// they're applied in order.
+func DiffFirewallRules(current, desired []upcloud.FirewallRule) []upcloud.FirewallRuleChange {
+	// lcs[i][j] is the length of the longest common subsequence of current[i:] and desired[j:]
+	lcs := make([][]int, len(current)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(desired)+1)
+	}
+	for i := len(current) - 1; i >= 0; i-- {
+		for j := len(desired) - 1; j >= 0; j-- {
+			switch {
+			case sameFirewallRule(current[i], desired[j]):
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			case lcs[i+1][j] >= lcs[i][j+1]:
+				lcs[i][j] = lcs[i+1][j]
+			default:
+				lcs[i][j] = lcs[i][j+1]
+			}
+		}
+	}
+
+	keptCurrent := make([]bool, len(current))
+	keptDesired := make([]bool, len(desired))
+	for i, j := 0, 0; i < len(current) && j < len(desired); {
+		switch {
+		case sameFirewallRule(current[i], desired[j]):
+			keptCurrent[i] = true
+			keptDesired[j] = true
+			i++
+			j++
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			i++
+		default:
+			j++
+		}
+	}
+
+	var changes []upcloud.FirewallRuleChange
+	for i := len(current) - 1; i >= 0; i-- {
+		if keptCurrent[i] {
+			continue
+		}
+		rule := current[i]
+		rule.Position = i + 1
+		changes = append(changes, upcloud.FirewallRuleChange{
+			Action:   upcloud.FirewallRuleChangeDelete,
+			Position: i + 1,
+			Rule:     rule,
+		})
+	}
+	for j, rule := range desired {
+		if keptDesired[j] {
+			continue
+		}
+		rule.Position = j + 1
+		changes = append(changes, upcloud.FirewallRuleChange{
+			Action:   upcloud.FirewallRuleChangeCreate,
+			Position: j + 1,
+			Rule:     rule,
+		})
+	}
+
+	return changes
+}
+
+// Returns whether two firewall rules are the same apart from their positions. A range without an end is the same as
+// a range ending where it starts, like the API treats it, and the families are compared regardless of case.
+func sameFirewallRule(a, b upcloud.FirewallRule) bool {
+	return normalizeFirewallRule(a) == normalizeFirewallRule(b)
+}
+
+// Returns the rule without its position, with the family in lower case and the ranges without an end ending where
+// they start
+func normalizeFirewallRule(rule upcloud.FirewallRule) upcloud.FirewallRule {
+	rule.Position = 0
+	rule.Family = strings.ToLower(rule.Family)
+
+	for _, r := range []struct{ start, end *string }{
+		{&rule.SourceAddressStart, &rule.SourceAddressEnd},
+		{&rule.SourcePortStart, &rule.SourcePortEnd},
+		{&rule.DestinationAddressStart, &rule.DestinationAddressEnd},
+		{&rule.DestinationPortStart, &rule.DestinationPortEnd},
+	} {
+		if *r.end == "" {
+			*r.end = *r.start
+		}
+	}
+
+	return rule
+}
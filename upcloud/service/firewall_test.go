@@ -0,0 +1,185 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/UpCloudLtd/upcloud-go-api/upcloud"
+	"github.com/UpCloudLtd/upcloud-go-api/upcloud/emulator"
+	"github.com/UpCloudLtd/upcloud-go-api/upcloud/request"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Returns a firewall rule accepting incoming TCP traffic to a port
+func acceptPort(port string) upcloud.FirewallRule {
+	return upcloud.FirewallRule{
+		Direction:            upcloud.FirewallRuleDirectionIn,
+		Action:               upcloud.FirewallRuleActionAccept,
+		Family:               upcloud.IPAddressFamilyIPv4,
+		Protocol:             upcloud.FirewallRuleProtocolTCP,
+		DestinationPortStart: port,
+		DestinationPortEnd:   port,
+	}
+}
+
+// Returns the rule at a position
+func atPosition(rule upcloud.FirewallRule, position int) upcloud.FirewallRule {
+	rule.Position = position
+	return rule
+}
+
+// TestDiffFirewallRules tests that the rules common to both rule sets are kept and that the positions of the changes
+// are valid in the order they're applied
+func TestDiffFirewallRules(t *testing.T) {
+	ssh, http, https, dns := acceptPort("22"), acceptPort("80"), acceptPort("443"), acceptPort("53")
+
+	// The same rules written without the ends of the single port and address ranges and with the family in lower case
+	httpFrom := http
+	httpFrom.SourceAddressStart, httpFrom.SourceAddressEnd = "192.0.2.1", "192.0.2.1"
+	sshShort, httpFromShort := ssh, httpFrom
+	sshShort.Family, sshShort.DestinationPortEnd = "ipv4", ""
+	httpFromShort.SourceAddressEnd, httpFromShort.DestinationPortEnd = "", ""
+
+	deleteAt := func(rule upcloud.FirewallRule, position int) upcloud.FirewallRuleChange {
+		return upcloud.FirewallRuleChange{Action: upcloud.FirewallRuleChangeDelete, Position: position, Rule: atPosition(rule, position)}
+	}
+	createAt := func(rule upcloud.FirewallRule, position int) upcloud.FirewallRuleChange {
+		return upcloud.FirewallRuleChange{Action: upcloud.FirewallRuleChangeCreate, Position: position, Rule: atPosition(rule, position)}
+	}
+
+	tests := []struct {
+		name    string
+		current []upcloud.FirewallRule
+		desired []upcloud.FirewallRule
+		changes []upcloud.FirewallRuleChange
+	}{
+		{
+			name:    "same rules at other positions",
+			current: []upcloud.FirewallRule{atPosition(ssh, 1), atPosition(http, 2)},
+			desired: []upcloud.FirewallRule{ssh, atPosition(http, 5)},
+		},
+		{
+			name:    "one-sided ranges and family case",
+			current: []upcloud.FirewallRule{ssh, httpFrom},
+			desired: []upcloud.FirewallRule{sshShort, httpFromShort},
+		},
+		{
+			name:    "empty",
+			desired: []upcloud.FirewallRule{ssh, http},
+			changes: []upcloud.FirewallRuleChange{createAt(ssh, 1), createAt(http, 2)},
+		},
+		{
+			name:    "insert in the middle",
+			current: []upcloud.FirewallRule{ssh, https},
+			desired: []upcloud.FirewallRule{ssh, http, https},
+			changes: []upcloud.FirewallRuleChange{createAt(http, 2)},
+		},
+		{
+			name:    "delete and insert",
+			current: []upcloud.FirewallRule{ssh, dns, http, https},
+			desired: []upcloud.FirewallRule{dns, ssh, https, http},
+			changes: []upcloud.FirewallRuleChange{
+				deleteAt(http, 3),
+				deleteAt(ssh, 1),
+				createAt(ssh, 2),
+				createAt(http, 4),
+			},
+		},
+		{
+			name:    "changed rule",
+			current: []upcloud.FirewallRule{ssh, http},
+			desired: []upcloud.FirewallRule{ssh, func() upcloud.FirewallRule { r := http; r.Comment = "web"; return r }()},
+			changes: []upcloud.FirewallRuleChange{
+				deleteAt(http, 2),
+				createAt(upcloud.FirewallRule{
+					Direction:            upcloud.FirewallRuleDirectionIn,
+					Action:               upcloud.FirewallRuleActionAccept,
+					Family:               upcloud.IPAddressFamilyIPv4,
+					Protocol:             upcloud.FirewallRuleProtocolTCP,
+					DestinationPortStart: "80",
+					DestinationPortEnd:   "80",
+					Comment:              "web",
+				}, 2),
+			},
+		},
+		{
+			name:    "delete all",
+			current: []upcloud.FirewallRule{ssh, http},
+			changes: []upcloud.FirewallRuleChange{deleteAt(http, 2), deleteAt(ssh, 1)},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.changes, DiffFirewallRules(test.current, test.desired))
+		})
+	}
+}
+
+// TestSyncFirewallRules tests that syncing the firewall rules of a server applies only the changes in the
+// incremental mode and replaces the rule set in the replace mode
+func TestSyncFirewallRules(t *testing.T) {
+	srv := emulator.NewServer()
+	defer srv.Close()
+
+	svc := New(srv.Client())
+	server, err := svc.CreateServer(&request.CreateServerRequest{
+		Zone:             "fi-hel2",
+		Title:            "web-1",
+		Hostname:         "web-1.example.com",
+		PasswordDelivery: request.PasswordDeliveryNone,
+		StorageDevices: request.CreateServerStorageDeviceSlice{{
+			Action:  request.CreateServerStorageDeviceActionClone,
+			Storage: "01000000-0000-4000-8000-000030060200",
+			Title:   "web-1 OS",
+		}},
+	})
+	require.NoError(t, err)
+
+	rules := func() []upcloud.FirewallRule {
+		rules, err := svc.GetFirewallRules(&request.GetFirewallRulesRequest{ServerUUID: server.UUID})
+		require.NoError(t, err)
+		return rules.FirewallRules
+	}
+
+	ssh, http, https := acceptPort("22"), acceptPort("80"), acceptPort("443")
+	sync, err := svc.SyncFirewallRules(&request.SyncFirewallRulesRequest{
+		ServerUUID:    server.UUID,
+		FirewallRules: []upcloud.FirewallRule{ssh, https},
+	})
+	require.NoError(t, err)
+	assert.Len(t, sync.Changes, 2)
+	assert.False(t, sync.Replaced)
+	assert.Equal(t, []upcloud.FirewallRule{atPosition(ssh, 1), atPosition(https, 2)}, rules())
+
+	sync, err = svc.SyncFirewallRules(&request.SyncFirewallRulesRequest{
+		ServerUUID:    server.UUID,
+		FirewallRules: []upcloud.FirewallRule{http, https, ssh},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []upcloud.FirewallRuleChange{
+		{Action: upcloud.FirewallRuleChangeDelete, Position: 1, Rule: atPosition(ssh, 1)},
+		{Action: upcloud.FirewallRuleChangeCreate, Position: 1, Rule: atPosition(http, 1)},
+		{Action: upcloud.FirewallRuleChangeCreate, Position: 3, Rule: atPosition(ssh, 3)},
+	}, sync.Changes)
+	assert.Equal(t, []upcloud.FirewallRule{atPosition(http, 1), atPosition(https, 2), atPosition(ssh, 3)}, rules())
+
+	sync, err = svc.SyncFirewallRules(&request.SyncFirewallRulesRequest{
+		ServerUUID:    server.UUID,
+		FirewallRules: []upcloud.FirewallRule{http, https, ssh},
+		Mode:          request.FirewallRulesSyncModeReplace,
+	})
+	require.NoError(t, err)
+	assert.Empty(t, sync.Changes)
+	assert.False(t, sync.Replaced)
+
+	sync, err = svc.SyncFirewallRules(&request.SyncFirewallRulesRequest{
+		ServerUUID:    server.UUID,
+		FirewallRules: []upcloud.FirewallRule{ssh},
+		Mode:          request.FirewallRulesSyncModeReplace,
+	})
+	require.NoError(t, err)
+	assert.Len(t, sync.Changes, 2)
+	assert.True(t, sync.Replaced)
+	assert.Equal(t, []upcloud.FirewallRule{atPosition(ssh, 1)}, rules())
+}
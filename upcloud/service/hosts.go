@@ -1,8 +1,9 @@
 package service
 
 import (
+	"context"
 	"encoding/json"
-	"fmt"
+	"net/http"
 
 	"github.com/UpCloudLtd/upcloud-go-api/upcloud"
 	"github.com/UpCloudLtd/upcloud-go-api/upcloud/request"
@@ -10,24 +11,32 @@ import (
 
 type Host interface {
 	GetHosts() (*upcloud.Hosts, error)
+	GetHostsWithContext(ctx context.Context) (*upcloud.Hosts, error)
 	GetHostDetails(r *request.GetHostDetailsRequest) (*upcloud.Host, error)
+	GetHostDetailsWithContext(ctx context.Context, r *request.GetHostDetailsRequest) (*upcloud.Host, error)
 	ModifyHost(r *request.ModifyHostRequest) (*upcloud.Host, error)
+	ModifyHostWithContext(ctx context.Context, r *request.ModifyHostRequest) (*upcloud.Host, error)
 }
 
 var _ Host = (*Service)(nil)
 
 // GetHosts returns the all the available private hosts
 func (s *Service) GetHosts() (*upcloud.Hosts, error) {
+	return s.GetHostsWithContext(context.Background())
+}
+
+// GetHostsWithContext is the same as GetHosts but binds the API request(s) to the specified context
+func (s *Service) GetHostsWithContext(ctx context.Context) (*upcloud.Hosts, error) {
 	hosts := upcloud.Hosts{}
-	response, err := s.basicGetRequest("/host")
+	response, err := s.basicGetRequest(ctx, "/host")
 
 	if err != nil {
 		return nil, err
 	}
 
-	err = json.Unmarshal(response, &hosts)
+	err = s.unmarshal(response, &hosts)
 	if err != nil {
-		return nil, fmt.Errorf("unable to unmarshal JSON: %s, %w", string(response), err)
+		return nil, err
 	}
 
 	return &hosts, nil
@@ -35,16 +44,25 @@ func (s *Service) GetHosts() (*upcloud.Hosts, error) {
 
 // GetHostDetails returns the details for a single private host
 func (s *Service) GetHostDetails(r *request.GetHostDetailsRequest) (*upcloud.Host, error) {
+	return s.GetHostDetailsWithContext(context.Background(), r)
+}
+
+// GetHostDetailsWithContext is the same as GetHostDetails but binds the API request(s) to the specified context
+func (s *Service) GetHostDetailsWithContext(ctx context.Context, r *request.GetHostDetailsRequest) (*upcloud.Host, error) {
+	if err := s.validate(r); err != nil {
+		return nil, err
+	}
+
 	host := upcloud.Host{}
-	response, err := s.basicGetRequest(r.RequestURL())
+	response, err := s.basicGetRequest(ctx, r.RequestURL())
 
 	if err != nil {
 		return nil, err
 	}
 
-	err = json.Unmarshal(response, &host)
+	err = s.unmarshal(response, &host)
 	if err != nil {
-		return nil, fmt.Errorf("unable to unmarshal JSON: %s, %w", string(response), err)
+		return nil, err
 	}
 
 	return &host, nil
@@ -52,17 +70,20 @@ func (s *Service) GetHostDetails(r *request.GetHostDetailsRequest) (*upcloud.Hos
 
 // ModifyHost modifies the configuration of an existing host.
 func (s *Service) ModifyHost(r *request.ModifyHostRequest) (*upcloud.Host, error) {
-	host := upcloud.Host{}
-	requestBody, _ := json.Marshal(r)
-	response, err := s.client.PerformJSONPatchRequest(s.client.CreateRequestURL(r.RequestURL()), requestBody)
+	return s.ModifyHostWithContext(context.Background(), r)
+}
 
-	if err != nil {
-		return nil, parseJSONServiceError(err)
+// ModifyHostWithContext is the same as ModifyHost but binds the API request(s) to the specified context
+func (s *Service) ModifyHostWithContext(ctx context.Context, r *request.ModifyHostRequest) (*upcloud.Host, error) {
+	if err := s.validate(r); err != nil {
+		return nil, err
 	}
 
-	err = json.Unmarshal(response, &host)
+	host := upcloud.Host{}
+	requestBody, _ := json.Marshal(r)
+	err := s.perform(ctx, http.MethodPatch, r, requestBody, &host)
 	if err != nil {
-		return nil, fmt.Errorf("unable to unmarshal JSON: %s, %w", string(response), err)
+		return nil, err
 	}
 
 	return &host, nil
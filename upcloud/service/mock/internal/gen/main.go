@@ -0,0 +1,179 @@
+// Command gen generates the Mock and Stub implementations of service.API from the methods of the interface.
+package main
+
+import (
+	"bytes"
+	"context"
+	"flag"
+	"go/format"
+	"io/ioutil"
+	"log"
+	"reflect"
+	"strings"
+	"text/template"
+
+	"github.com/UpCloudLtd/upcloud-go-api/upcloud/service"
+)
+
+// method is a method of service.API along with its WithContext variant
+type method struct {
+	// Name is the name of the method without the WithContext suffix
+	Name string
+	// Request is the type of the request parameter, empty if the method has none
+	Request string
+	// Result is the type of the result returned along with an error, empty if the method only returns an error
+	Result string
+	// Empty is the expression a Stub returns when no response has been set
+	Empty string
+}
+
+// Returns the Go syntax of a type in the generated file
+func typeName(t reflect.Type) string {
+	if t.Kind() == reflect.Interface && t.NumMethod() == 0 {
+		return "interface{}"
+	}
+
+	return t.String()
+}
+
+// Returns the methods of service.API. The plain methods are derived from their WithContext variant, which is
+// checked to exist for every method.
+func methods() []method {
+	api := reflect.TypeOf((*service.API)(nil)).Elem()
+	contextType := reflect.TypeOf((*context.Context)(nil)).Elem()
+	errorType := reflect.TypeOf((*error)(nil)).Elem()
+
+	var result []method
+	for i := 0; i < api.NumMethod(); i++ {
+		m := api.Method(i)
+		if strings.HasSuffix(m.Name, "WithContext") {
+			continue
+		}
+
+		withContext, ok := api.MethodByName(m.Name + "WithContext")
+		if !ok {
+			log.Fatalf("%s has no WithContext variant", m.Name)
+		}
+
+		t := withContext.Type
+		if t.NumIn() < 1 || t.NumIn() > 2 || t.In(0) != contextType {
+			log.Fatalf("%s has unsupported parameters", withContext.Name)
+		}
+		if t.NumOut() < 1 || t.NumOut() > 2 || t.Out(t.NumOut()-1) != errorType {
+			log.Fatalf("%s has unsupported results", withContext.Name)
+		}
+
+		gm := method{Name: m.Name}
+		if t.NumIn() == 2 {
+			gm.Request = typeName(t.In(1))
+		}
+
+		if t.NumOut() == 2 {
+			out := t.Out(0)
+			gm.Result = typeName(out)
+
+			switch out.Kind() {
+			case reflect.Ptr:
+				gm.Empty = "&" + typeName(out.Elem()) + "{}"
+			case reflect.Interface:
+				gm.Empty = "nil"
+			default:
+				log.Fatalf("%s has an unsupported result type %s", withContext.Name, out)
+			}
+		}
+
+		result = append(result, gm)
+	}
+
+	return result
+}
+
+var source = template.Must(template.New("source").Parse(`// Code generated by go run ./internal/gen. DO NOT EDIT.
+
+package mock
+
+import (
+	"context"
+
+	"github.com/UpCloudLtd/upcloud-go-api/upcloud"
+	"github.com/UpCloudLtd/upcloud-go-api/upcloud/request"
+	"github.com/UpCloudLtd/upcloud-go-api/upcloud/service"
+)
+
+var _ service.API = (*Mock)(nil)
+var _ service.API = (*Stub)(nil)
+
+// Mock is an implementation of service.API that delegates calls to the functions set in its fields. Methods
+// without a function fail with ErrNotMocked. The zero value is ready to use and a Mock is safe for concurrent use as
+// long as its fields aren't modified while it's in use.
+type Mock struct {
+	recorder
+{{range .}}
+	// {{.Name}}Func is called by {{.Name}} and {{.Name}}WithContext
+	{{.Name}}Func func(ctx context.Context{{if .Request}}, r {{.Request}}{{end}}) {{template "results" .}}
+{{- end}}
+}
+{{range .}}
+// {{.Name}} implements service.API
+func (m *Mock) {{.Name}}({{if .Request}}r {{.Request}}{{end}}) {{template "results" .}} {
+	return m.{{.Name}}WithContext(context.Background(){{if .Request}}, r{{end}})
+}
+
+// {{.Name}}WithContext implements service.API
+func (m *Mock) {{.Name}}WithContext(ctx context.Context{{if .Request}}, r {{.Request}}{{end}}) {{template "results" .}} {
+	m.record("{{.Name}}", ctx, {{if .Request}}r{{else}}nil{{end}})
+	if m.{{.Name}}Func == nil {
+		return {{if .Result}}nil, {{end}}notMocked("{{.Name}}")
+	}
+
+	return m.{{.Name}}Func(ctx{{if .Request}}, r{{end}})
+}
+{{end}}
+{{- range .}}
+// {{.Name}}Returns sets the response of {{.Name}} and {{.Name}}WithContext
+func (s *Stub) {{.Name}}Returns({{if .Result}}result {{.Result}}, {{end}}err error) {
+	s.set("{{.Name}}", {{if .Result}}result{{else}}nil{{end}}, err)
+}
+
+// {{.Name}} implements service.API
+func (s *Stub) {{.Name}}({{if .Request}}r {{.Request}}{{end}}) {{template "results" .}} {
+	return s.{{.Name}}WithContext(context.Background(){{if .Request}}, r{{end}})
+}
+
+// {{.Name}}WithContext implements service.API
+func (s *Stub) {{.Name}}WithContext(ctx context.Context{{if .Request}}, r {{.Request}}{{end}}) {{template "results" .}} {
+	s.record("{{.Name}}", ctx, {{if .Request}}r{{else}}nil{{end}})
+	if resp, ok := s.get("{{.Name}}"); ok {
+{{- if eq .Result "interface{}"}}
+		return resp.result, resp.err
+{{- else if .Result}}
+		result, _ := resp.result.({{.Result}})
+		return result, resp.err
+{{- else}}
+		return resp.err
+{{- end}}
+	}
+
+	return {{if .Result}}{{.Empty}}, {{end}}nil
+}
+{{end}}
+{{- define "results"}}{{if .Result}}({{.Result}}, error){{else}}error{{end}}{{end}}`))
+
+func main() {
+	output := flag.String("o", "mock_gen.go", "the file to write")
+	flag.Parse()
+
+	var buf bytes.Buffer
+	if err := source.Execute(&buf, methods()); err != nil {
+		log.Fatal(err)
+	}
+
+	formatted, err := format.Source(buf.Bytes())
+	if err != nil {
+		log.Fatalf("formatting the generated source: %v", err)
+	}
+
+	if err := ioutil.WriteFile(*output, formatted, 0644); err != nil {
+		log.Fatal(err)
+	}
+}
@@ -0,0 +1,128 @@
+// Package mock provides implementations of service.API for unit testing code that uses the API without making HTTP
+// requests.
+//
+// Mock delegates every call to a function set by the test, e.g. GetServerDetailsFunc, and fails calls without one
+// with ErrNotMocked. Stub returns canned responses set with the ...Returns methods, e.g. GetServerDetailsReturns, and
+// an empty result for calls without one. Both record the calls made to them:
+//
+//	stub := &mock.Stub{}
+//	stub.GetServerDetailsReturns(&upcloud.ServerDetails{Server: upcloud.Server{State: upcloud.ServerStateStarted}}, nil)
+//
+//	runCodeUnderTest(stub)
+//
+//	calls := stub.CallsTo("GetServerDetails")
+//
+// The methods taking a context.Context record the call under the name of the method without one, so a call to
+// GetServerDetailsWithContext is recorded as "GetServerDetails".
+package mock
+
+//go:generate go run ./internal/gen -o mock_gen.go
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// ErrNotMocked is returned by a Mock for calls to methods it has no function for
+var ErrNotMocked = errors.New("method not mocked")
+
+// Returns the error of a call to a method without a function in a Mock
+func notMocked(method string) error {
+	return fmt.Errorf("%s: %w", method, ErrNotMocked)
+}
+
+// Call is a recorded call to a Mock or a Stub
+type Call struct {
+	// Method is the name of the method without the WithContext suffix, e.g. "GetServerDetails"
+	Method string
+	// Context is the context the method was called with, context.Background() for the methods without one
+	Context context.Context
+	// Request is the request or wait condition passed to the method, nil for the methods without one
+	Request interface{}
+}
+
+// recorder keeps track of the calls made to a Mock or a Stub. It's safe for concurrent use.
+type recorder struct {
+	mu    sync.Mutex
+	calls []Call
+}
+
+// Records a call
+func (r *recorder) record(method string, ctx context.Context, request interface{}) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.calls = append(r.calls, Call{Method: method, Context: ctx, Request: request})
+}
+
+// Calls returns the calls made so far, in the order they were made
+func (r *recorder) Calls() []Call {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return append([]Call(nil), r.calls...)
+}
+
+// CallsTo returns the calls made so far to the specified method, e.g. "GetServerDetails"
+func (r *recorder) CallsTo(method string) []Call {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var calls []Call
+	for _, call := range r.calls {
+		if call.Method == method {
+			calls = append(calls, call)
+		}
+	}
+
+	return calls
+}
+
+// Reset forgets the calls made so far
+func (r *recorder) Reset() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.calls = nil
+}
+
+// response is a canned response of a Stub
+type response struct {
+	result interface{}
+	err    error
+}
+
+// cannedResponses holds the canned responses of a Stub. It's safe for concurrent use.
+type cannedResponses struct {
+	mu        sync.Mutex
+	responses map[string]response
+}
+
+// Sets the response of a method
+func (r *cannedResponses) set(method string, result interface{}, err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.responses == nil {
+		r.responses = make(map[string]response)
+	}
+	r.responses[method] = response{result: result, err: err}
+}
+
+// Returns the response of a method, if one has been set
+func (r *cannedResponses) get(method string) (response, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	resp, ok := r.responses[method]
+	return resp, ok
+}
+
+// Stub is an implementation of service.API that returns canned responses. Methods without a response return an
+// empty result and no error. The zero value is ready to use and a Stub is safe for concurrent use.
+type Stub struct {
+	recorder
+	cannedResponses
+}
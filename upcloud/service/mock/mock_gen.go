@@ -0,0 +1,2935 @@
+// Code generated by go run ./internal/gen. DO NOT EDIT.
+
+package mock
+
+import (
+	"context"
+
+	"github.com/UpCloudLtd/upcloud-go-api/upcloud"
+	"github.com/UpCloudLtd/upcloud-go-api/upcloud/request"
+	"github.com/UpCloudLtd/upcloud-go-api/upcloud/service"
+)
+
+var _ service.API = (*Mock)(nil)
+var _ service.API = (*Stub)(nil)
+
+// Mock is an implementation of service.API that delegates calls to the functions set in its fields. Methods
+// without a function fail with ErrNotMocked. The zero value is ready to use and a Mock is safe for concurrent use as
+// long as its fields aren't modified while it's in use.
+type Mock struct {
+	recorder
+
+	// AssignIPAddressFunc is called by AssignIPAddress and AssignIPAddressWithContext
+	AssignIPAddressFunc func(ctx context.Context, r *request.AssignIPAddressRequest) (*upcloud.IPAddress, error)
+	// AttachStorageFunc is called by AttachStorage and AttachStorageWithContext
+	AttachStorageFunc func(ctx context.Context, r *request.AttachStorageRequest) (*upcloud.ServerDetails, error)
+	// CloneStorageFunc is called by CloneStorage and CloneStorageWithContext
+	CloneStorageFunc func(ctx context.Context, r *request.CloneStorageRequest) (*upcloud.StorageDetails, error)
+	// CreateBackupFunc is called by CreateBackup and CreateBackupWithContext
+	CreateBackupFunc func(ctx context.Context, r *request.CreateBackupRequest) (*upcloud.StorageDetails, error)
+	// CreateFirewallRuleFunc is called by CreateFirewallRule and CreateFirewallRuleWithContext
+	CreateFirewallRuleFunc func(ctx context.Context, r *request.CreateFirewallRuleRequest) (*upcloud.FirewallRule, error)
+	// CreateFirewallRulesFunc is called by CreateFirewallRules and CreateFirewallRulesWithContext
+	CreateFirewallRulesFunc func(ctx context.Context, r *request.CreateFirewallRulesRequest) error
+	// CreateNetworkFunc is called by CreateNetwork and CreateNetworkWithContext
+	CreateNetworkFunc func(ctx context.Context, r *request.CreateNetworkRequest) (*upcloud.Network, error)
+	// CreateNetworkInterfaceFunc is called by CreateNetworkInterface and CreateNetworkInterfaceWithContext
+	CreateNetworkInterfaceFunc func(ctx context.Context, r *request.CreateNetworkInterfaceRequest) (*upcloud.Interface, error)
+	// CreateObjectStorageFunc is called by CreateObjectStorage and CreateObjectStorageWithContext
+	CreateObjectStorageFunc func(ctx context.Context, r *request.CreateObjectStorageRequest) (*upcloud.ObjectStorageDetails, error)
+	// CreateRouterFunc is called by CreateRouter and CreateRouterWithContext
+	CreateRouterFunc func(ctx context.Context, r *request.CreateRouterRequest) (*upcloud.Router, error)
+	// CreateServerFunc is called by CreateServer and CreateServerWithContext
+	CreateServerFunc func(ctx context.Context, r *request.CreateServerRequest) (*upcloud.ServerDetails, error)
+	// CreateStorageFunc is called by CreateStorage and CreateStorageWithContext
+	CreateStorageFunc func(ctx context.Context, r *request.CreateStorageRequest) (*upcloud.StorageDetails, error)
+	// CreateStorageImportFunc is called by CreateStorageImport and CreateStorageImportWithContext
+	CreateStorageImportFunc func(ctx context.Context, r *request.CreateStorageImportRequest) (*upcloud.StorageImportDetails, error)
+	// CreateTagFunc is called by CreateTag and CreateTagWithContext
+	CreateTagFunc func(ctx context.Context, r *request.CreateTagRequest) (*upcloud.Tag, error)
+	// DeleteFirewallRuleFunc is called by DeleteFirewallRule and DeleteFirewallRuleWithContext
+	DeleteFirewallRuleFunc func(ctx context.Context, r *request.DeleteFirewallRuleRequest) error
+	// DeleteNetworkFunc is called by DeleteNetwork and DeleteNetworkWithContext
+	DeleteNetworkFunc func(ctx context.Context, r *request.DeleteNetworkRequest) error
+	// DeleteNetworkInterfaceFunc is called by DeleteNetworkInterface and DeleteNetworkInterfaceWithContext
+	DeleteNetworkInterfaceFunc func(ctx context.Context, r *request.DeleteNetworkInterfaceRequest) error
+	// DeleteObjectStorageFunc is called by DeleteObjectStorage and DeleteObjectStorageWithContext
+	DeleteObjectStorageFunc func(ctx context.Context, r *request.DeleteObjectStorageRequest) error
+	// DeleteRouterFunc is called by DeleteRouter and DeleteRouterWithContext
+	DeleteRouterFunc func(ctx context.Context, r *request.DeleteRouterRequest) error
+	// DeleteServerFunc is called by DeleteServer and DeleteServerWithContext
+	DeleteServerFunc func(ctx context.Context, r *request.DeleteServerRequest) error
+	// DeleteServerAndStoragesFunc is called by DeleteServerAndStorages and DeleteServerAndStoragesWithContext
+	DeleteServerAndStoragesFunc func(ctx context.Context, r *request.DeleteServerAndStoragesRequest) error
+	// DeleteStorageFunc is called by DeleteStorage and DeleteStorageWithContext
+	DeleteStorageFunc func(ctx context.Context, r *request.DeleteStorageRequest) error
+	// DeleteTagFunc is called by DeleteTag and DeleteTagWithContext
+	DeleteTagFunc func(ctx context.Context, r *request.DeleteTagRequest) error
+	// DetachStorageFunc is called by DetachStorage and DetachStorageWithContext
+	DetachStorageFunc func(ctx context.Context, r *request.DetachStorageRequest) (*upcloud.ServerDetails, error)
+	// EjectCDROMFunc is called by EjectCDROM and EjectCDROMWithContext
+	EjectCDROMFunc func(ctx context.Context, r *request.EjectCDROMRequest) (*upcloud.ServerDetails, error)
+	// GetAccountFunc is called by GetAccount and GetAccountWithContext
+	GetAccountFunc func(ctx context.Context) (*upcloud.Account, error)
+	// GetFirewallRuleDetailsFunc is called by GetFirewallRuleDetails and GetFirewallRuleDetailsWithContext
+	GetFirewallRuleDetailsFunc func(ctx context.Context, r *request.GetFirewallRuleDetailsRequest) (*upcloud.FirewallRule, error)
+	// GetFirewallRulesFunc is called by GetFirewallRules and GetFirewallRulesWithContext
+	GetFirewallRulesFunc func(ctx context.Context, r *request.GetFirewallRulesRequest) (*upcloud.FirewallRules, error)
+	// GetHostDetailsFunc is called by GetHostDetails and GetHostDetailsWithContext
+	GetHostDetailsFunc func(ctx context.Context, r *request.GetHostDetailsRequest) (*upcloud.Host, error)
+	// GetHostsFunc is called by GetHosts and GetHostsWithContext
+	GetHostsFunc func(ctx context.Context) (*upcloud.Hosts, error)
+	// GetIPAddressDetailsFunc is called by GetIPAddressDetails and GetIPAddressDetailsWithContext
+	GetIPAddressDetailsFunc func(ctx context.Context, r *request.GetIPAddressDetailsRequest) (*upcloud.IPAddress, error)
+	// GetIPAddressesFunc is called by GetIPAddresses and GetIPAddressesWithContext
+	GetIPAddressesFunc func(ctx context.Context) (*upcloud.IPAddresses, error)
+	// GetNetworkDetailsFunc is called by GetNetworkDetails and GetNetworkDetailsWithContext
+	GetNetworkDetailsFunc func(ctx context.Context, r *request.GetNetworkDetailsRequest) (*upcloud.Network, error)
+	// GetNetworksFunc is called by GetNetworks and GetNetworksWithContext
+	GetNetworksFunc func(ctx context.Context) (*upcloud.Networks, error)
+	// GetNetworksInZoneFunc is called by GetNetworksInZone and GetNetworksInZoneWithContext
+	GetNetworksInZoneFunc func(ctx context.Context, r *request.GetNetworksInZoneRequest) (*upcloud.Networks, error)
+	// GetObjectStorageDetailsFunc is called by GetObjectStorageDetails and GetObjectStorageDetailsWithContext
+	GetObjectStorageDetailsFunc func(ctx context.Context, r *request.GetObjectStorageDetailsRequest) (*upcloud.ObjectStorageDetails, error)
+	// GetObjectStoragesFunc is called by GetObjectStorages and GetObjectStoragesWithContext
+	GetObjectStoragesFunc func(ctx context.Context) (*upcloud.ObjectStorages, error)
+	// GetPlansFunc is called by GetPlans and GetPlansWithContext
+	GetPlansFunc func(ctx context.Context) (*upcloud.Plans, error)
+	// GetPriceZonesFunc is called by GetPriceZones and GetPriceZonesWithContext
+	GetPriceZonesFunc func(ctx context.Context) (*upcloud.PriceZones, error)
+	// GetRouterDetailsFunc is called by GetRouterDetails and GetRouterDetailsWithContext
+	GetRouterDetailsFunc func(ctx context.Context, r *request.GetRouterDetailsRequest) (*upcloud.Router, error)
+	// GetRoutersFunc is called by GetRouters and GetRoutersWithContext
+	GetRoutersFunc func(ctx context.Context) (*upcloud.Routers, error)
+	// GetServerConfigurationsFunc is called by GetServerConfigurations and GetServerConfigurationsWithContext
+	GetServerConfigurationsFunc func(ctx context.Context) (*upcloud.ServerConfigurations, error)
+	// GetServerDetailsFunc is called by GetServerDetails and GetServerDetailsWithContext
+	GetServerDetailsFunc func(ctx context.Context, r *request.GetServerDetailsRequest) (*upcloud.ServerDetails, error)
+	// GetServerNetworksFunc is called by GetServerNetworks and GetServerNetworksWithContext
+	GetServerNetworksFunc func(ctx context.Context, r *request.GetServerNetworksRequest) (*upcloud.Networking, error)
+	// GetServersFunc is called by GetServers and GetServersWithContext
+	GetServersFunc func(ctx context.Context) (*upcloud.Servers, error)
+	// GetStorageDetailsFunc is called by GetStorageDetails and GetStorageDetailsWithContext
+	GetStorageDetailsFunc func(ctx context.Context, r *request.GetStorageDetailsRequest) (*upcloud.StorageDetails, error)
+	// GetStorageImportDetailsFunc is called by GetStorageImportDetails and GetStorageImportDetailsWithContext
+	GetStorageImportDetailsFunc func(ctx context.Context, r *request.GetStorageImportDetailsRequest) (*upcloud.StorageImportDetails, error)
+	// GetStoragesFunc is called by GetStorages and GetStoragesWithContext
+	GetStoragesFunc func(ctx context.Context, r *request.GetStoragesRequest) (*upcloud.Storages, error)
+	// GetTagsFunc is called by GetTags and GetTagsWithContext
+	GetTagsFunc func(ctx context.Context) (*upcloud.Tags, error)
+	// GetTimeZonesFunc is called by GetTimeZones and GetTimeZonesWithContext
+	GetTimeZonesFunc func(ctx context.Context) (*upcloud.TimeZones, error)
+	// GetZonesFunc is called by GetZones and GetZonesWithContext
+	GetZonesFunc func(ctx context.Context) (*upcloud.Zones, error)
+	// LoadCDROMFunc is called by LoadCDROM and LoadCDROMWithContext
+	LoadCDROMFunc func(ctx context.Context, r *request.LoadCDROMRequest) (*upcloud.ServerDetails, error)
+	// ModifyHostFunc is called by ModifyHost and ModifyHostWithContext
+	ModifyHostFunc func(ctx context.Context, r *request.ModifyHostRequest) (*upcloud.Host, error)
+	// ModifyIPAddressFunc is called by ModifyIPAddress and ModifyIPAddressWithContext
+	ModifyIPAddressFunc func(ctx context.Context, r *request.ModifyIPAddressRequest) (*upcloud.IPAddress, error)
+	// ModifyNetworkFunc is called by ModifyNetwork and ModifyNetworkWithContext
+	ModifyNetworkFunc func(ctx context.Context, r *request.ModifyNetworkRequest) (*upcloud.Network, error)
+	// ModifyNetworkInterfaceFunc is called by ModifyNetworkInterface and ModifyNetworkInterfaceWithContext
+	ModifyNetworkInterfaceFunc func(ctx context.Context, r *request.ModifyNetworkInterfaceRequest) (*upcloud.Interface, error)
+	// ModifyObjectStorageFunc is called by ModifyObjectStorage and ModifyObjectStorageWithContext
+	ModifyObjectStorageFunc func(ctx context.Context, r *request.ModifyObjectStorageRequest) (*upcloud.ObjectStorageDetails, error)
+	// ModifyRouterFunc is called by ModifyRouter and ModifyRouterWithContext
+	ModifyRouterFunc func(ctx context.Context, r *request.ModifyRouterRequest) (*upcloud.Router, error)
+	// ModifyServerFunc is called by ModifyServer and ModifyServerWithContext
+	ModifyServerFunc func(ctx context.Context, r *request.ModifyServerRequest) (*upcloud.ServerDetails, error)
+	// ModifyStorageFunc is called by ModifyStorage and ModifyStorageWithContext
+	ModifyStorageFunc func(ctx context.Context, r *request.ModifyStorageRequest) (*upcloud.StorageDetails, error)
+	// ModifyTagFunc is called by ModifyTag and ModifyTagWithContext
+	ModifyTagFunc func(ctx context.Context, r *request.ModifyTagRequest) (*upcloud.Tag, error)
+	// ReleaseIPAddressFunc is called by ReleaseIPAddress and ReleaseIPAddressWithContext
+	ReleaseIPAddressFunc func(ctx context.Context, r *request.ReleaseIPAddressRequest) error
+	// RestartServerFunc is called by RestartServer and RestartServerWithContext
+	RestartServerFunc func(ctx context.Context, r *request.RestartServerRequest) (*upcloud.ServerDetails, error)
+	// RestoreBackupFunc is called by RestoreBackup and RestoreBackupWithContext
+	RestoreBackupFunc func(ctx context.Context, r *request.RestoreBackupRequest) error
+	// StartServerFunc is called by StartServer and StartServerWithContext
+	StartServerFunc func(ctx context.Context, r *request.StartServerRequest) (*upcloud.ServerDetails, error)
+	// StopServerFunc is called by StopServer and StopServerWithContext
+	StopServerFunc func(ctx context.Context, r *request.StopServerRequest) (*upcloud.ServerDetails, error)
+	// SyncFirewallRulesFunc is called by SyncFirewallRules and SyncFirewallRulesWithContext
+	SyncFirewallRulesFunc func(ctx context.Context, r *request.SyncFirewallRulesRequest) (*upcloud.FirewallRuleSync, error)
+	// TagServerFunc is called by TagServer and TagServerWithContext
+	TagServerFunc func(ctx context.Context, r *request.TagServerRequest) (*upcloud.ServerDetails, error)
+	// TemplatizeStorageFunc is called by TemplatizeStorage and TemplatizeStorageWithContext
+	TemplatizeStorageFunc func(ctx context.Context, r *request.TemplatizeStorageRequest) (*upcloud.StorageDetails, error)
+	// UntagServerFunc is called by UntagServer and UntagServerWithContext
+	UntagServerFunc func(ctx context.Context, r *request.UntagServerRequest) (*upcloud.ServerDetails, error)
+	// WaitFunc is called by Wait and WaitWithContext
+	WaitFunc func(ctx context.Context, r *service.WaitCondition) (interface{}, error)
+	// WaitForBackupCreationFunc is called by WaitForBackupCreation and WaitForBackupCreationWithContext
+	WaitForBackupCreationFunc func(ctx context.Context, r *request.WaitForBackupCreationRequest) (*upcloud.StorageDetails, error)
+	// WaitForObjectStorageStateFunc is called by WaitForObjectStorageState and WaitForObjectStorageStateWithContext
+	WaitForObjectStorageStateFunc func(ctx context.Context, r *request.WaitForObjectStorageStateRequest) (*upcloud.ObjectStorageDetails, error)
+	// WaitForServerStateFunc is called by WaitForServerState and WaitForServerStateWithContext
+	WaitForServerStateFunc func(ctx context.Context, r *request.WaitForServerStateRequest) (*upcloud.ServerDetails, error)
+	// WaitForStorageCloneCompletionFunc is called by WaitForStorageCloneCompletion and WaitForStorageCloneCompletionWithContext
+	WaitForStorageCloneCompletionFunc func(ctx context.Context, r *request.WaitForStorageCloneCompletionRequest) (*upcloud.StorageDetails, error)
+	// WaitForStorageImportCompletionFunc is called by WaitForStorageImportCompletion and WaitForStorageImportCompletionWithContext
+	WaitForStorageImportCompletionFunc func(ctx context.Context, r *request.WaitForStorageImportCompletionRequest) (*upcloud.StorageImportDetails, error)
+	// WaitForStorageStateFunc is called by WaitForStorageState and WaitForStorageStateWithContext
+	WaitForStorageStateFunc func(ctx context.Context, r *request.WaitForStorageStateRequest) (*upcloud.StorageDetails, error)
+}
+
+// AssignIPAddress implements service.API
+func (m *Mock) AssignIPAddress(r *request.AssignIPAddressRequest) (*upcloud.IPAddress, error) {
+	return m.AssignIPAddressWithContext(context.Background(), r)
+}
+
+// AssignIPAddressWithContext implements service.API
+func (m *Mock) AssignIPAddressWithContext(ctx context.Context, r *request.AssignIPAddressRequest) (*upcloud.IPAddress, error) {
+	m.record("AssignIPAddress", ctx, r)
+	if m.AssignIPAddressFunc == nil {
+		return nil, notMocked("AssignIPAddress")
+	}
+
+	return m.AssignIPAddressFunc(ctx, r)
+}
+
+// AttachStorage implements service.API
+func (m *Mock) AttachStorage(r *request.AttachStorageRequest) (*upcloud.ServerDetails, error) {
+	return m.AttachStorageWithContext(context.Background(), r)
+}
+
+// AttachStorageWithContext implements service.API
+func (m *Mock) AttachStorageWithContext(ctx context.Context, r *request.AttachStorageRequest) (*upcloud.ServerDetails, error) {
+	m.record("AttachStorage", ctx, r)
+	if m.AttachStorageFunc == nil {
+		return nil, notMocked("AttachStorage")
+	}
+
+	return m.AttachStorageFunc(ctx, r)
+}
+
+// CloneStorage implements service.API
+func (m *Mock) CloneStorage(r *request.CloneStorageRequest) (*upcloud.StorageDetails, error) {
+	return m.CloneStorageWithContext(context.Background(), r)
+}
+
+// CloneStorageWithContext implements service.API
+func (m *Mock) CloneStorageWithContext(ctx context.Context, r *request.CloneStorageRequest) (*upcloud.StorageDetails, error) {
+	m.record("CloneStorage", ctx, r)
+	if m.CloneStorageFunc == nil {
+		return nil, notMocked("CloneStorage")
+	}
+
+	return m.CloneStorageFunc(ctx, r)
+}
+
+// CreateBackup implements service.API
+func (m *Mock) CreateBackup(r *request.CreateBackupRequest) (*upcloud.StorageDetails, error) {
+	return m.CreateBackupWithContext(context.Background(), r)
+}
+
+// CreateBackupWithContext implements service.API
+func (m *Mock) CreateBackupWithContext(ctx context.Context, r *request.CreateBackupRequest) (*upcloud.StorageDetails, error) {
+	m.record("CreateBackup", ctx, r)
+	if m.CreateBackupFunc == nil {
+		return nil, notMocked("CreateBackup")
+	}
+
+	return m.CreateBackupFunc(ctx, r)
+}
+
+// CreateFirewallRule implements service.API
+func (m *Mock) CreateFirewallRule(r *request.CreateFirewallRuleRequest) (*upcloud.FirewallRule, error) {
+	return m.CreateFirewallRuleWithContext(context.Background(), r)
+}
+
+// CreateFirewallRuleWithContext implements service.API
+func (m *Mock) CreateFirewallRuleWithContext(ctx context.Context, r *request.CreateFirewallRuleRequest) (*upcloud.FirewallRule, error) {
+	m.record("CreateFirewallRule", ctx, r)
+	if m.CreateFirewallRuleFunc == nil {
+		return nil, notMocked("CreateFirewallRule")
+	}
+
+	return m.CreateFirewallRuleFunc(ctx, r)
+}
+
+// CreateFirewallRules implements service.API
+func (m *Mock) CreateFirewallRules(r *request.CreateFirewallRulesRequest) error {
+	return m.CreateFirewallRulesWithContext(context.Background(), r)
+}
+
+// CreateFirewallRulesWithContext implements service.API
+func (m *Mock) CreateFirewallRulesWithContext(ctx context.Context, r *request.CreateFirewallRulesRequest) error {
+	m.record("CreateFirewallRules", ctx, r)
+	if m.CreateFirewallRulesFunc == nil {
+		return notMocked("CreateFirewallRules")
+	}
+
+	return m.CreateFirewallRulesFunc(ctx, r)
+}
+
+// CreateNetwork implements service.API
+func (m *Mock) CreateNetwork(r *request.CreateNetworkRequest) (*upcloud.Network, error) {
+	return m.CreateNetworkWithContext(context.Background(), r)
+}
+
+// CreateNetworkWithContext implements service.API
+func (m *Mock) CreateNetworkWithContext(ctx context.Context, r *request.CreateNetworkRequest) (*upcloud.Network, error) {
+	m.record("CreateNetwork", ctx, r)
+	if m.CreateNetworkFunc == nil {
+		return nil, notMocked("CreateNetwork")
+	}
+
+	return m.CreateNetworkFunc(ctx, r)
+}
+
+// CreateNetworkInterface implements service.API
+func (m *Mock) CreateNetworkInterface(r *request.CreateNetworkInterfaceRequest) (*upcloud.Interface, error) {
+	return m.CreateNetworkInterfaceWithContext(context.Background(), r)
+}
+
+// CreateNetworkInterfaceWithContext implements service.API
+func (m *Mock) CreateNetworkInterfaceWithContext(ctx context.Context, r *request.CreateNetworkInterfaceRequest) (*upcloud.Interface, error) {
+	m.record("CreateNetworkInterface", ctx, r)
+	if m.CreateNetworkInterfaceFunc == nil {
+		return nil, notMocked("CreateNetworkInterface")
+	}
+
+	return m.CreateNetworkInterfaceFunc(ctx, r)
+}
+
+// CreateObjectStorage implements service.API
+func (m *Mock) CreateObjectStorage(r *request.CreateObjectStorageRequest) (*upcloud.ObjectStorageDetails, error) {
+	return m.CreateObjectStorageWithContext(context.Background(), r)
+}
+
+// CreateObjectStorageWithContext implements service.API
+func (m *Mock) CreateObjectStorageWithContext(ctx context.Context, r *request.CreateObjectStorageRequest) (*upcloud.ObjectStorageDetails, error) {
+	m.record("CreateObjectStorage", ctx, r)
+	if m.CreateObjectStorageFunc == nil {
+		return nil, notMocked("CreateObjectStorage")
+	}
+
+	return m.CreateObjectStorageFunc(ctx, r)
+}
+
+// CreateRouter implements service.API
+func (m *Mock) CreateRouter(r *request.CreateRouterRequest) (*upcloud.Router, error) {
+	return m.CreateRouterWithContext(context.Background(), r)
+}
+
+// CreateRouterWithContext implements service.API
+func (m *Mock) CreateRouterWithContext(ctx context.Context, r *request.CreateRouterRequest) (*upcloud.Router, error) {
+	m.record("CreateRouter", ctx, r)
+	if m.CreateRouterFunc == nil {
+		return nil, notMocked("CreateRouter")
+	}
+
+	return m.CreateRouterFunc(ctx, r)
+}
+
+// CreateServer implements service.API
+func (m *Mock) CreateServer(r *request.CreateServerRequest) (*upcloud.ServerDetails, error) {
+	return m.CreateServerWithContext(context.Background(), r)
+}
+
+// CreateServerWithContext implements service.API
+func (m *Mock) CreateServerWithContext(ctx context.Context, r *request.CreateServerRequest) (*upcloud.ServerDetails, error) {
+	m.record("CreateServer", ctx, r)
+	if m.CreateServerFunc == nil {
+		return nil, notMocked("CreateServer")
+	}
+
+	return m.CreateServerFunc(ctx, r)
+}
+
+// CreateStorage implements service.API
+func (m *Mock) CreateStorage(r *request.CreateStorageRequest) (*upcloud.StorageDetails, error) {
+	return m.CreateStorageWithContext(context.Background(), r)
+}
+
+// CreateStorageWithContext implements service.API
+func (m *Mock) CreateStorageWithContext(ctx context.Context, r *request.CreateStorageRequest) (*upcloud.StorageDetails, error) {
+	m.record("CreateStorage", ctx, r)
+	if m.CreateStorageFunc == nil {
+		return nil, notMocked("CreateStorage")
+	}
+
+	return m.CreateStorageFunc(ctx, r)
+}
+
+// CreateStorageImport implements service.API
+func (m *Mock) CreateStorageImport(r *request.CreateStorageImportRequest) (*upcloud.StorageImportDetails, error) {
+	return m.CreateStorageImportWithContext(context.Background(), r)
+}
+
+// CreateStorageImportWithContext implements service.API
+func (m *Mock) CreateStorageImportWithContext(ctx context.Context, r *request.CreateStorageImportRequest) (*upcloud.StorageImportDetails, error) {
+	m.record("CreateStorageImport", ctx, r)
+	if m.CreateStorageImportFunc == nil {
+		return nil, notMocked("CreateStorageImport")
+	}
+
+	return m.CreateStorageImportFunc(ctx, r)
+}
+
+// CreateTag implements service.API
+func (m *Mock) CreateTag(r *request.CreateTagRequest) (*upcloud.Tag, error) {
+	return m.CreateTagWithContext(context.Background(), r)
+}
+
+// CreateTagWithContext implements service.API
+func (m *Mock) CreateTagWithContext(ctx context.Context, r *request.CreateTagRequest) (*upcloud.Tag, error) {
+	m.record("CreateTag", ctx, r)
+	if m.CreateTagFunc == nil {
+		return nil, notMocked("CreateTag")
+	}
+
+	return m.CreateTagFunc(ctx, r)
+}
+
+// DeleteFirewallRule implements service.API
+func (m *Mock) DeleteFirewallRule(r *request.DeleteFirewallRuleRequest) error {
+	return m.DeleteFirewallRuleWithContext(context.Background(), r)
+}
+
+// DeleteFirewallRuleWithContext implements service.API
+func (m *Mock) DeleteFirewallRuleWithContext(ctx context.Context, r *request.DeleteFirewallRuleRequest) error {
+	m.record("DeleteFirewallRule", ctx, r)
+	if m.DeleteFirewallRuleFunc == nil {
+		return notMocked("DeleteFirewallRule")
+	}
+
+	return m.DeleteFirewallRuleFunc(ctx, r)
+}
+
+// DeleteNetwork implements service.API
+func (m *Mock) DeleteNetwork(r *request.DeleteNetworkRequest) error {
+	return m.DeleteNetworkWithContext(context.Background(), r)
+}
+
+// DeleteNetworkWithContext implements service.API
+func (m *Mock) DeleteNetworkWithContext(ctx context.Context, r *request.DeleteNetworkRequest) error {
+	m.record("DeleteNetwork", ctx, r)
+	if m.DeleteNetworkFunc == nil {
+		return notMocked("DeleteNetwork")
+	}
+
+	return m.DeleteNetworkFunc(ctx, r)
+}
+
+// DeleteNetworkInterface implements service.API
+func (m *Mock) DeleteNetworkInterface(r *request.DeleteNetworkInterfaceRequest) error {
+	return m.DeleteNetworkInterfaceWithContext(context.Background(), r)
+}
+
+// DeleteNetworkInterfaceWithContext implements service.API
+func (m *Mock) DeleteNetworkInterfaceWithContext(ctx context.Context, r *request.DeleteNetworkInterfaceRequest) error {
+	m.record("DeleteNetworkInterface", ctx, r)
+	if m.DeleteNetworkInterfaceFunc == nil {
+		return notMocked("DeleteNetworkInterface")
+	}
+
+	return m.DeleteNetworkInterfaceFunc(ctx, r)
+}
+
+// DeleteObjectStorage implements service.API
+func (m *Mock) DeleteObjectStorage(r *request.DeleteObjectStorageRequest) error {
+	return m.DeleteObjectStorageWithContext(context.Background(), r)
+}
+
+// DeleteObjectStorageWithContext implements service.API
+func (m *Mock) DeleteObjectStorageWithContext(ctx context.Context, r *request.DeleteObjectStorageRequest) error {
+	m.record("DeleteObjectStorage", ctx, r)
+	if m.DeleteObjectStorageFunc == nil {
+		return notMocked("DeleteObjectStorage")
+	}
+
+	return m.DeleteObjectStorageFunc(ctx, r)
+}
+
+// DeleteRouter implements service.API
+func (m *Mock) DeleteRouter(r *request.DeleteRouterRequest) error {
+	return m.DeleteRouterWithContext(context.Background(), r)
+}
+
+// DeleteRouterWithContext implements service.API
+func (m *Mock) DeleteRouterWithContext(ctx context.Context, r *request.DeleteRouterRequest) error {
+	m.record("DeleteRouter", ctx, r)
+	if m.DeleteRouterFunc == nil {
+		return notMocked("DeleteRouter")
+	}
+
+	return m.DeleteRouterFunc(ctx, r)
+}
+
+// DeleteServer implements service.API
+func (m *Mock) DeleteServer(r *request.DeleteServerRequest) error {
+	return m.DeleteServerWithContext(context.Background(), r)
+}
+
+// DeleteServerWithContext implements service.API
+func (m *Mock) DeleteServerWithContext(ctx context.Context, r *request.DeleteServerRequest) error {
+	m.record("DeleteServer", ctx, r)
+	if m.DeleteServerFunc == nil {
+		return notMocked("DeleteServer")
+	}
+
+	return m.DeleteServerFunc(ctx, r)
+}
+
+// DeleteServerAndStorages implements service.API
+func (m *Mock) DeleteServerAndStorages(r *request.DeleteServerAndStoragesRequest) error {
+	return m.DeleteServerAndStoragesWithContext(context.Background(), r)
+}
+
+// DeleteServerAndStoragesWithContext implements service.API
+func (m *Mock) DeleteServerAndStoragesWithContext(ctx context.Context, r *request.DeleteServerAndStoragesRequest) error {
+	m.record("DeleteServerAndStorages", ctx, r)
+	if m.DeleteServerAndStoragesFunc == nil {
+		return notMocked("DeleteServerAndStorages")
+	}
+
+	return m.DeleteServerAndStoragesFunc(ctx, r)
+}
+
+// DeleteStorage implements service.API
+func (m *Mock) DeleteStorage(r *request.DeleteStorageRequest) error {
+	return m.DeleteStorageWithContext(context.Background(), r)
+}
+
+// DeleteStorageWithContext implements service.API
+func (m *Mock) DeleteStorageWithContext(ctx context.Context, r *request.DeleteStorageRequest) error {
+	m.record("DeleteStorage", ctx, r)
+	if m.DeleteStorageFunc == nil {
+		return notMocked("DeleteStorage")
+	}
+
+	return m.DeleteStorageFunc(ctx, r)
+}
+
+// DeleteTag implements service.API
+func (m *Mock) DeleteTag(r *request.DeleteTagRequest) error {
+	return m.DeleteTagWithContext(context.Background(), r)
+}
+
+// DeleteTagWithContext implements service.API
+func (m *Mock) DeleteTagWithContext(ctx context.Context, r *request.DeleteTagRequest) error {
+	m.record("DeleteTag", ctx, r)
+	if m.DeleteTagFunc == nil {
+		return notMocked("DeleteTag")
+	}
+
+	return m.DeleteTagFunc(ctx, r)
+}
+
+// DetachStorage implements service.API
+func (m *Mock) DetachStorage(r *request.DetachStorageRequest) (*upcloud.ServerDetails, error) {
+	return m.DetachStorageWithContext(context.Background(), r)
+}
+
+// DetachStorageWithContext implements service.API
+func (m *Mock) DetachStorageWithContext(ctx context.Context, r *request.DetachStorageRequest) (*upcloud.ServerDetails, error) {
+	m.record("DetachStorage", ctx, r)
+	if m.DetachStorageFunc == nil {
+		return nil, notMocked("DetachStorage")
+	}
+
+	return m.DetachStorageFunc(ctx, r)
+}
+
+// EjectCDROM implements service.API
+func (m *Mock) EjectCDROM(r *request.EjectCDROMRequest) (*upcloud.ServerDetails, error) {
+	return m.EjectCDROMWithContext(context.Background(), r)
+}
+
+// EjectCDROMWithContext implements service.API
+func (m *Mock) EjectCDROMWithContext(ctx context.Context, r *request.EjectCDROMRequest) (*upcloud.ServerDetails, error) {
+	m.record("EjectCDROM", ctx, r)
+	if m.EjectCDROMFunc == nil {
+		return nil, notMocked("EjectCDROM")
+	}
+
+	return m.EjectCDROMFunc(ctx, r)
+}
+
+// GetAccount implements service.API
+func (m *Mock) GetAccount() (*upcloud.Account, error) {
+	return m.GetAccountWithContext(context.Background())
+}
+
+// GetAccountWithContext implements service.API
+func (m *Mock) GetAccountWithContext(ctx context.Context) (*upcloud.Account, error) {
+	m.record("GetAccount", ctx, nil)
+	if m.GetAccountFunc == nil {
+		return nil, notMocked("GetAccount")
+	}
+
+	return m.GetAccountFunc(ctx)
+}
+
+// GetFirewallRuleDetails implements service.API
+func (m *Mock) GetFirewallRuleDetails(r *request.GetFirewallRuleDetailsRequest) (*upcloud.FirewallRule, error) {
+	return m.GetFirewallRuleDetailsWithContext(context.Background(), r)
+}
+
+// GetFirewallRuleDetailsWithContext implements service.API
+func (m *Mock) GetFirewallRuleDetailsWithContext(ctx context.Context, r *request.GetFirewallRuleDetailsRequest) (*upcloud.FirewallRule, error) {
+	m.record("GetFirewallRuleDetails", ctx, r)
+	if m.GetFirewallRuleDetailsFunc == nil {
+		return nil, notMocked("GetFirewallRuleDetails")
+	}
+
+	return m.GetFirewallRuleDetailsFunc(ctx, r)
+}
+
+// GetFirewallRules implements service.API
+func (m *Mock) GetFirewallRules(r *request.GetFirewallRulesRequest) (*upcloud.FirewallRules, error) {
+	return m.GetFirewallRulesWithContext(context.Background(), r)
+}
+
+// GetFirewallRulesWithContext implements service.API
+func (m *Mock) GetFirewallRulesWithContext(ctx context.Context, r *request.GetFirewallRulesRequest) (*upcloud.FirewallRules, error) {
+	m.record("GetFirewallRules", ctx, r)
+	if m.GetFirewallRulesFunc == nil {
+		return nil, notMocked("GetFirewallRules")
+	}
+
+	return m.GetFirewallRulesFunc(ctx, r)
+}
+
+// GetHostDetails implements service.API
+func (m *Mock) GetHostDetails(r *request.GetHostDetailsRequest) (*upcloud.Host, error) {
+	return m.GetHostDetailsWithContext(context.Background(), r)
+}
+
+// GetHostDetailsWithContext implements service.API
+func (m *Mock) GetHostDetailsWithContext(ctx context.Context, r *request.GetHostDetailsRequest) (*upcloud.Host, error) {
+	m.record("GetHostDetails", ctx, r)
+	if m.GetHostDetailsFunc == nil {
+		return nil, notMocked("GetHostDetails")
+	}
+
+	return m.GetHostDetailsFunc(ctx, r)
+}
+
+// GetHosts implements service.API
+func (m *Mock) GetHosts() (*upcloud.Hosts, error) {
+	return m.GetHostsWithContext(context.Background())
+}
+
+// GetHostsWithContext implements service.API
+func (m *Mock) GetHostsWithContext(ctx context.Context) (*upcloud.Hosts, error) {
+	m.record("GetHosts", ctx, nil)
+	if m.GetHostsFunc == nil {
+		return nil, notMocked("GetHosts")
+	}
+
+	return m.GetHostsFunc(ctx)
+}
+
+// GetIPAddressDetails implements service.API
+func (m *Mock) GetIPAddressDetails(r *request.GetIPAddressDetailsRequest) (*upcloud.IPAddress, error) {
+	return m.GetIPAddressDetailsWithContext(context.Background(), r)
+}
+
+// GetIPAddressDetailsWithContext implements service.API
+func (m *Mock) GetIPAddressDetailsWithContext(ctx context.Context, r *request.GetIPAddressDetailsRequest) (*upcloud.IPAddress, error) {
+	m.record("GetIPAddressDetails", ctx, r)
+	if m.GetIPAddressDetailsFunc == nil {
+		return nil, notMocked("GetIPAddressDetails")
+	}
+
+	return m.GetIPAddressDetailsFunc(ctx, r)
+}
+
+// GetIPAddresses implements service.API
+func (m *Mock) GetIPAddresses() (*upcloud.IPAddresses, error) {
+	return m.GetIPAddressesWithContext(context.Background())
+}
+
+// GetIPAddressesWithContext implements service.API
+func (m *Mock) GetIPAddressesWithContext(ctx context.Context) (*upcloud.IPAddresses, error) {
+	m.record("GetIPAddresses", ctx, nil)
+	if m.GetIPAddressesFunc == nil {
+		return nil, notMocked("GetIPAddresses")
+	}
+
+	return m.GetIPAddressesFunc(ctx)
+}
+
+// GetNetworkDetails implements service.API
+func (m *Mock) GetNetworkDetails(r *request.GetNetworkDetailsRequest) (*upcloud.Network, error) {
+	return m.GetNetworkDetailsWithContext(context.Background(), r)
+}
+
+// GetNetworkDetailsWithContext implements service.API
+func (m *Mock) GetNetworkDetailsWithContext(ctx context.Context, r *request.GetNetworkDetailsRequest) (*upcloud.Network, error) {
+	m.record("GetNetworkDetails", ctx, r)
+	if m.GetNetworkDetailsFunc == nil {
+		return nil, notMocked("GetNetworkDetails")
+	}
+
+	return m.GetNetworkDetailsFunc(ctx, r)
+}
+
+// GetNetworks implements service.API
+func (m *Mock) GetNetworks() (*upcloud.Networks, error) {
+	return m.GetNetworksWithContext(context.Background())
+}
+
+// GetNetworksWithContext implements service.API
+func (m *Mock) GetNetworksWithContext(ctx context.Context) (*upcloud.Networks, error) {
+	m.record("GetNetworks", ctx, nil)
+	if m.GetNetworksFunc == nil {
+		return nil, notMocked("GetNetworks")
+	}
+
+	return m.GetNetworksFunc(ctx)
+}
+
+// GetNetworksInZone implements service.API
+func (m *Mock) GetNetworksInZone(r *request.GetNetworksInZoneRequest) (*upcloud.Networks, error) {
+	return m.GetNetworksInZoneWithContext(context.Background(), r)
+}
+
+// GetNetworksInZoneWithContext implements service.API
+func (m *Mock) GetNetworksInZoneWithContext(ctx context.Context, r *request.GetNetworksInZoneRequest) (*upcloud.Networks, error) {
+	m.record("GetNetworksInZone", ctx, r)
+	if m.GetNetworksInZoneFunc == nil {
+		return nil, notMocked("GetNetworksInZone")
+	}
+
+	return m.GetNetworksInZoneFunc(ctx, r)
+}
+
+// GetObjectStorageDetails implements service.API
+func (m *Mock) GetObjectStorageDetails(r *request.GetObjectStorageDetailsRequest) (*upcloud.ObjectStorageDetails, error) {
+	return m.GetObjectStorageDetailsWithContext(context.Background(), r)
+}
+
+// GetObjectStorageDetailsWithContext implements service.API
+func (m *Mock) GetObjectStorageDetailsWithContext(ctx context.Context, r *request.GetObjectStorageDetailsRequest) (*upcloud.ObjectStorageDetails, error) {
+	m.record("GetObjectStorageDetails", ctx, r)
+	if m.GetObjectStorageDetailsFunc == nil {
+		return nil, notMocked("GetObjectStorageDetails")
+	}
+
+	return m.GetObjectStorageDetailsFunc(ctx, r)
+}
+
+// GetObjectStorages implements service.API
+func (m *Mock) GetObjectStorages() (*upcloud.ObjectStorages, error) {
+	return m.GetObjectStoragesWithContext(context.Background())
+}
+
+// GetObjectStoragesWithContext implements service.API
+func (m *Mock) GetObjectStoragesWithContext(ctx context.Context) (*upcloud.ObjectStorages, error) {
+	m.record("GetObjectStorages", ctx, nil)
+	if m.GetObjectStoragesFunc == nil {
+		return nil, notMocked("GetObjectStorages")
+	}
+
+	return m.GetObjectStoragesFunc(ctx)
+}
+
+// GetPlans implements service.API
+func (m *Mock) GetPlans() (*upcloud.Plans, error) {
+	return m.GetPlansWithContext(context.Background())
+}
+
+// GetPlansWithContext implements service.API
+func (m *Mock) GetPlansWithContext(ctx context.Context) (*upcloud.Plans, error) {
+	m.record("GetPlans", ctx, nil)
+	if m.GetPlansFunc == nil {
+		return nil, notMocked("GetPlans")
+	}
+
+	return m.GetPlansFunc(ctx)
+}
+
+// GetPriceZones implements service.API
+func (m *Mock) GetPriceZones() (*upcloud.PriceZones, error) {
+	return m.GetPriceZonesWithContext(context.Background())
+}
+
+// GetPriceZonesWithContext implements service.API
+func (m *Mock) GetPriceZonesWithContext(ctx context.Context) (*upcloud.PriceZones, error) {
+	m.record("GetPriceZones", ctx, nil)
+	if m.GetPriceZonesFunc == nil {
+		return nil, notMocked("GetPriceZones")
+	}
+
+	return m.GetPriceZonesFunc(ctx)
+}
+
+// GetRouterDetails implements service.API
+func (m *Mock) GetRouterDetails(r *request.GetRouterDetailsRequest) (*upcloud.Router, error) {
+	return m.GetRouterDetailsWithContext(context.Background(), r)
+}
+
+// GetRouterDetailsWithContext implements service.API
+func (m *Mock) GetRouterDetailsWithContext(ctx context.Context, r *request.GetRouterDetailsRequest) (*upcloud.Router, error) {
+	m.record("GetRouterDetails", ctx, r)
+	if m.GetRouterDetailsFunc == nil {
+		return nil, notMocked("GetRouterDetails")
+	}
+
+	return m.GetRouterDetailsFunc(ctx, r)
+}
+
+// GetRouters implements service.API
+func (m *Mock) GetRouters() (*upcloud.Routers, error) {
+	return m.GetRoutersWithContext(context.Background())
+}
+
+// GetRoutersWithContext implements service.API
+func (m *Mock) GetRoutersWithContext(ctx context.Context) (*upcloud.Routers, error) {
+	m.record("GetRouters", ctx, nil)
+	if m.GetRoutersFunc == nil {
+		return nil, notMocked("GetRouters")
+	}
+
+	return m.GetRoutersFunc(ctx)
+}
+
+// GetServerConfigurations implements service.API
+func (m *Mock) GetServerConfigurations() (*upcloud.ServerConfigurations, error) {
+	return m.GetServerConfigurationsWithContext(context.Background())
+}
+
+// GetServerConfigurationsWithContext implements service.API
+func (m *Mock) GetServerConfigurationsWithContext(ctx context.Context) (*upcloud.ServerConfigurations, error) {
+	m.record("GetServerConfigurations", ctx, nil)
+	if m.GetServerConfigurationsFunc == nil {
+		return nil, notMocked("GetServerConfigurations")
+	}
+
+	return m.GetServerConfigurationsFunc(ctx)
+}
+
+// GetServerDetails implements service.API
+func (m *Mock) GetServerDetails(r *request.GetServerDetailsRequest) (*upcloud.ServerDetails, error) {
+	return m.GetServerDetailsWithContext(context.Background(), r)
+}
+
+// GetServerDetailsWithContext implements service.API
+func (m *Mock) GetServerDetailsWithContext(ctx context.Context, r *request.GetServerDetailsRequest) (*upcloud.ServerDetails, error) {
+	m.record("GetServerDetails", ctx, r)
+	if m.GetServerDetailsFunc == nil {
+		return nil, notMocked("GetServerDetails")
+	}
+
+	return m.GetServerDetailsFunc(ctx, r)
+}
+
+// GetServerNetworks implements service.API
+func (m *Mock) GetServerNetworks(r *request.GetServerNetworksRequest) (*upcloud.Networking, error) {
+	return m.GetServerNetworksWithContext(context.Background(), r)
+}
+
+// GetServerNetworksWithContext implements service.API
+func (m *Mock) GetServerNetworksWithContext(ctx context.Context, r *request.GetServerNetworksRequest) (*upcloud.Networking, error) {
+	m.record("GetServerNetworks", ctx, r)
+	if m.GetServerNetworksFunc == nil {
+		return nil, notMocked("GetServerNetworks")
+	}
+
+	return m.GetServerNetworksFunc(ctx, r)
+}
+
+// GetServers implements service.API
+func (m *Mock) GetServers() (*upcloud.Servers, error) {
+	return m.GetServersWithContext(context.Background())
+}
+
+// GetServersWithContext implements service.API
+func (m *Mock) GetServersWithContext(ctx context.Context) (*upcloud.Servers, error) {
+	m.record("GetServers", ctx, nil)
+	if m.GetServersFunc == nil {
+		return nil, notMocked("GetServers")
+	}
+
+	return m.GetServersFunc(ctx)
+}
+
+// GetStorageDetails implements service.API
+func (m *Mock) GetStorageDetails(r *request.GetStorageDetailsRequest) (*upcloud.StorageDetails, error) {
+	return m.GetStorageDetailsWithContext(context.Background(), r)
+}
+
+// GetStorageDetailsWithContext implements service.API
+func (m *Mock) GetStorageDetailsWithContext(ctx context.Context, r *request.GetStorageDetailsRequest) (*upcloud.StorageDetails, error) {
+	m.record("GetStorageDetails", ctx, r)
+	if m.GetStorageDetailsFunc == nil {
+		return nil, notMocked("GetStorageDetails")
+	}
+
+	return m.GetStorageDetailsFunc(ctx, r)
+}
+
+// GetStorageImportDetails implements service.API
+func (m *Mock) GetStorageImportDetails(r *request.GetStorageImportDetailsRequest) (*upcloud.StorageImportDetails, error) {
+	return m.GetStorageImportDetailsWithContext(context.Background(), r)
+}
+
+// GetStorageImportDetailsWithContext implements service.API
+func (m *Mock) GetStorageImportDetailsWithContext(ctx context.Context, r *request.GetStorageImportDetailsRequest) (*upcloud.StorageImportDetails, error) {
+	m.record("GetStorageImportDetails", ctx, r)
+	if m.GetStorageImportDetailsFunc == nil {
+		return nil, notMocked("GetStorageImportDetails")
+	}
+
+	return m.GetStorageImportDetailsFunc(ctx, r)
+}
+
+// GetStorages implements service.API
+func (m *Mock) GetStorages(r *request.GetStoragesRequest) (*upcloud.Storages, error) {
+	return m.GetStoragesWithContext(context.Background(), r)
+}
+
+// GetStoragesWithContext implements service.API
+func (m *Mock) GetStoragesWithContext(ctx context.Context, r *request.GetStoragesRequest) (*upcloud.Storages, error) {
+	m.record("GetStorages", ctx, r)
+	if m.GetStoragesFunc == nil {
+		return nil, notMocked("GetStorages")
+	}
+
+	return m.GetStoragesFunc(ctx, r)
+}
+
+// GetTags implements service.API
+func (m *Mock) GetTags() (*upcloud.Tags, error) {
+	return m.GetTagsWithContext(context.Background())
+}
+
+// GetTagsWithContext implements service.API
+func (m *Mock) GetTagsWithContext(ctx context.Context) (*upcloud.Tags, error) {
+	m.record("GetTags", ctx, nil)
+	if m.GetTagsFunc == nil {
+		return nil, notMocked("GetTags")
+	}
+
+	return m.GetTagsFunc(ctx)
+}
+
+// GetTimeZones implements service.API
+func (m *Mock) GetTimeZones() (*upcloud.TimeZones, error) {
+	return m.GetTimeZonesWithContext(context.Background())
+}
+
+// GetTimeZonesWithContext implements service.API
+func (m *Mock) GetTimeZonesWithContext(ctx context.Context) (*upcloud.TimeZones, error) {
+	m.record("GetTimeZones", ctx, nil)
+	if m.GetTimeZonesFunc == nil {
+		return nil, notMocked("GetTimeZones")
+	}
+
+	return m.GetTimeZonesFunc(ctx)
+}
+
+// GetZones implements service.API
+func (m *Mock) GetZones() (*upcloud.Zones, error) {
+	return m.GetZonesWithContext(context.Background())
+}
+
+// GetZonesWithContext implements service.API
+func (m *Mock) GetZonesWithContext(ctx context.Context) (*upcloud.Zones, error) {
+	m.record("GetZones", ctx, nil)
+	if m.GetZonesFunc == nil {
+		return nil, notMocked("GetZones")
+	}
+
+	return m.GetZonesFunc(ctx)
+}
+
+// LoadCDROM implements service.API
+func (m *Mock) LoadCDROM(r *request.LoadCDROMRequest) (*upcloud.ServerDetails, error) {
+	return m.LoadCDROMWithContext(context.Background(), r)
+}
+
+// LoadCDROMWithContext implements service.API
+func (m *Mock) LoadCDROMWithContext(ctx context.Context, r *request.LoadCDROMRequest) (*upcloud.ServerDetails, error) {
+	m.record("LoadCDROM", ctx, r)
+	if m.LoadCDROMFunc == nil {
+		return nil, notMocked("LoadCDROM")
+	}
+
+	return m.LoadCDROMFunc(ctx, r)
+}
+
+// ModifyHost implements service.API
+func (m *Mock) ModifyHost(r *request.ModifyHostRequest) (*upcloud.Host, error) {
+	return m.ModifyHostWithContext(context.Background(), r)
+}
+
+// ModifyHostWithContext implements service.API
+func (m *Mock) ModifyHostWithContext(ctx context.Context, r *request.ModifyHostRequest) (*upcloud.Host, error) {
+	m.record("ModifyHost", ctx, r)
+	if m.ModifyHostFunc == nil {
+		return nil, notMocked("ModifyHost")
+	}
+
+	return m.ModifyHostFunc(ctx, r)
+}
+
+// ModifyIPAddress implements service.API
+func (m *Mock) ModifyIPAddress(r *request.ModifyIPAddressRequest) (*upcloud.IPAddress, error) {
+	return m.ModifyIPAddressWithContext(context.Background(), r)
+}
+
+// ModifyIPAddressWithContext implements service.API
+func (m *Mock) ModifyIPAddressWithContext(ctx context.Context, r *request.ModifyIPAddressRequest) (*upcloud.IPAddress, error) {
+	m.record("ModifyIPAddress", ctx, r)
+	if m.ModifyIPAddressFunc == nil {
+		return nil, notMocked("ModifyIPAddress")
+	}
+
+	return m.ModifyIPAddressFunc(ctx, r)
+}
+
+// ModifyNetwork implements service.API
+func (m *Mock) ModifyNetwork(r *request.ModifyNetworkRequest) (*upcloud.Network, error) {
+	return m.ModifyNetworkWithContext(context.Background(), r)
+}
+
+// ModifyNetworkWithContext implements service.API
+func (m *Mock) ModifyNetworkWithContext(ctx context.Context, r *request.ModifyNetworkRequest) (*upcloud.Network, error) {
+	m.record("ModifyNetwork", ctx, r)
+	if m.ModifyNetworkFunc == nil {
+		return nil, notMocked("ModifyNetwork")
+	}
+
+	return m.ModifyNetworkFunc(ctx, r)
+}
+
+// ModifyNetworkInterface implements service.API
+func (m *Mock) ModifyNetworkInterface(r *request.ModifyNetworkInterfaceRequest) (*upcloud.Interface, error) {
+	return m.ModifyNetworkInterfaceWithContext(context.Background(), r)
+}
+
+// ModifyNetworkInterfaceWithContext implements service.API
+func (m *Mock) ModifyNetworkInterfaceWithContext(ctx context.Context, r *request.ModifyNetworkInterfaceRequest) (*upcloud.Interface, error) {
+	m.record("ModifyNetworkInterface", ctx, r)
+	if m.ModifyNetworkInterfaceFunc == nil {
+		return nil, notMocked("ModifyNetworkInterface")
+	}
+
+	return m.ModifyNetworkInterfaceFunc(ctx, r)
+}
+
+// ModifyObjectStorage implements service.API
+func (m *Mock) ModifyObjectStorage(r *request.ModifyObjectStorageRequest) (*upcloud.ObjectStorageDetails, error) {
+	return m.ModifyObjectStorageWithContext(context.Background(), r)
+}
+
+// ModifyObjectStorageWithContext implements service.API
+func (m *Mock) ModifyObjectStorageWithContext(ctx context.Context, r *request.ModifyObjectStorageRequest) (*upcloud.ObjectStorageDetails, error) {
+	m.record("ModifyObjectStorage", ctx, r)
+	if m.ModifyObjectStorageFunc == nil {
+		return nil, notMocked("ModifyObjectStorage")
+	}
+
+	return m.ModifyObjectStorageFunc(ctx, r)
+}
+
+// ModifyRouter implements service.API
+func (m *Mock) ModifyRouter(r *request.ModifyRouterRequest) (*upcloud.Router, error) {
+	return m.ModifyRouterWithContext(context.Background(), r)
+}
+
+// ModifyRouterWithContext implements service.API
+func (m *Mock) ModifyRouterWithContext(ctx context.Context, r *request.ModifyRouterRequest) (*upcloud.Router, error) {
+	m.record("ModifyRouter", ctx, r)
+	if m.ModifyRouterFunc == nil {
+		return nil, notMocked("ModifyRouter")
+	}
+
+	return m.ModifyRouterFunc(ctx, r)
+}
+
+// ModifyServer implements service.API
+func (m *Mock) ModifyServer(r *request.ModifyServerRequest) (*upcloud.ServerDetails, error) {
+	return m.ModifyServerWithContext(context.Background(), r)
+}
+
+// ModifyServerWithContext implements service.API
+func (m *Mock) ModifyServerWithContext(ctx context.Context, r *request.ModifyServerRequest) (*upcloud.ServerDetails, error) {
+	m.record("ModifyServer", ctx, r)
+	if m.ModifyServerFunc == nil {
+		return nil, notMocked("ModifyServer")
+	}
+
+	return m.ModifyServerFunc(ctx, r)
+}
+
+// ModifyStorage implements service.API
+func (m *Mock) ModifyStorage(r *request.ModifyStorageRequest) (*upcloud.StorageDetails, error) {
+	return m.ModifyStorageWithContext(context.Background(), r)
+}
+
+// ModifyStorageWithContext implements service.API
+func (m *Mock) ModifyStorageWithContext(ctx context.Context, r *request.ModifyStorageRequest) (*upcloud.StorageDetails, error) {
+	m.record("ModifyStorage", ctx, r)
+	if m.ModifyStorageFunc == nil {
+		return nil, notMocked("ModifyStorage")
+	}
+
+	return m.ModifyStorageFunc(ctx, r)
+}
+
+// ModifyTag implements service.API
+func (m *Mock) ModifyTag(r *request.ModifyTagRequest) (*upcloud.Tag, error) {
+	return m.ModifyTagWithContext(context.Background(), r)
+}
+
+// ModifyTagWithContext implements service.API
+func (m *Mock) ModifyTagWithContext(ctx context.Context, r *request.ModifyTagRequest) (*upcloud.Tag, error) {
+	m.record("ModifyTag", ctx, r)
+	if m.ModifyTagFunc == nil {
+		return nil, notMocked("ModifyTag")
+	}
+
+	return m.ModifyTagFunc(ctx, r)
+}
+
+// ReleaseIPAddress implements service.API
+func (m *Mock) ReleaseIPAddress(r *request.ReleaseIPAddressRequest) error {
+	return m.ReleaseIPAddressWithContext(context.Background(), r)
+}
+
+// ReleaseIPAddressWithContext implements service.API
+func (m *Mock) ReleaseIPAddressWithContext(ctx context.Context, r *request.ReleaseIPAddressRequest) error {
+	m.record("ReleaseIPAddress", ctx, r)
+	if m.ReleaseIPAddressFunc == nil {
+		return notMocked("ReleaseIPAddress")
+	}
+
+	return m.ReleaseIPAddressFunc(ctx, r)
+}
+
+// RestartServer implements service.API
+func (m *Mock) RestartServer(r *request.RestartServerRequest) (*upcloud.ServerDetails, error) {
+	return m.RestartServerWithContext(context.Background(), r)
+}
+
+// RestartServerWithContext implements service.API
+func (m *Mock) RestartServerWithContext(ctx context.Context, r *request.RestartServerRequest) (*upcloud.ServerDetails, error) {
+	m.record("RestartServer", ctx, r)
+	if m.RestartServerFunc == nil {
+		return nil, notMocked("RestartServer")
+	}
+
+	return m.RestartServerFunc(ctx, r)
+}
+
+// RestoreBackup implements service.API
+func (m *Mock) RestoreBackup(r *request.RestoreBackupRequest) error {
+	return m.RestoreBackupWithContext(context.Background(), r)
+}
+
+// RestoreBackupWithContext implements service.API
+func (m *Mock) RestoreBackupWithContext(ctx context.Context, r *request.RestoreBackupRequest) error {
+	m.record("RestoreBackup", ctx, r)
+	if m.RestoreBackupFunc == nil {
+		return notMocked("RestoreBackup")
+	}
+
+	return m.RestoreBackupFunc(ctx, r)
+}
+
+// StartServer implements service.API
+func (m *Mock) StartServer(r *request.StartServerRequest) (*upcloud.ServerDetails, error) {
+	return m.StartServerWithContext(context.Background(), r)
+}
+
+// StartServerWithContext implements service.API
+func (m *Mock) StartServerWithContext(ctx context.Context, r *request.StartServerRequest) (*upcloud.ServerDetails, error) {
+	m.record("StartServer", ctx, r)
+	if m.StartServerFunc == nil {
+		return nil, notMocked("StartServer")
+	}
+
+	return m.StartServerFunc(ctx, r)
+}
+
+// StopServer implements service.API
+func (m *Mock) StopServer(r *request.StopServerRequest) (*upcloud.ServerDetails, error) {
+	return m.StopServerWithContext(context.Background(), r)
+}
+
+// StopServerWithContext implements service.API
+func (m *Mock) StopServerWithContext(ctx context.Context, r *request.StopServerRequest) (*upcloud.ServerDetails, error) {
+	m.record("StopServer", ctx, r)
+	if m.StopServerFunc == nil {
+		return nil, notMocked("StopServer")
+	}
+
+	return m.StopServerFunc(ctx, r)
+}
+
+// SyncFirewallRules implements service.API
+func (m *Mock) SyncFirewallRules(r *request.SyncFirewallRulesRequest) (*upcloud.FirewallRuleSync, error) {
+	return m.SyncFirewallRulesWithContext(context.Background(), r)
+}
+
+// SyncFirewallRulesWithContext implements service.API
+func (m *Mock) SyncFirewallRulesWithContext(ctx context.Context, r *request.SyncFirewallRulesRequest) (*upcloud.FirewallRuleSync, error) {
+	m.record("SyncFirewallRules", ctx, r)
+	if m.SyncFirewallRulesFunc == nil {
+		return nil, notMocked("SyncFirewallRules")
+	}
+
+	return m.SyncFirewallRulesFunc(ctx, r)
+}
+
+// TagServer implements service.API
+func (m *Mock) TagServer(r *request.TagServerRequest) (*upcloud.ServerDetails, error) {
+	return m.TagServerWithContext(context.Background(), r)
+}
+
+// TagServerWithContext implements service.API
+func (m *Mock) TagServerWithContext(ctx context.Context, r *request.TagServerRequest) (*upcloud.ServerDetails, error) {
+	m.record("TagServer", ctx, r)
+	if m.TagServerFunc == nil {
+		return nil, notMocked("TagServer")
+	}
+
+	return m.TagServerFunc(ctx, r)
+}
+
+// TemplatizeStorage implements service.API
+func (m *Mock) TemplatizeStorage(r *request.TemplatizeStorageRequest) (*upcloud.StorageDetails, error) {
+	return m.TemplatizeStorageWithContext(context.Background(), r)
+}
+
+// TemplatizeStorageWithContext implements service.API
+func (m *Mock) TemplatizeStorageWithContext(ctx context.Context, r *request.TemplatizeStorageRequest) (*upcloud.StorageDetails, error) {
+	m.record("TemplatizeStorage", ctx, r)
+	if m.TemplatizeStorageFunc == nil {
+		return nil, notMocked("TemplatizeStorage")
+	}
+
+	return m.TemplatizeStorageFunc(ctx, r)
+}
+
+// UntagServer implements service.API
+func (m *Mock) UntagServer(r *request.UntagServerRequest) (*upcloud.ServerDetails, error) {
+	return m.UntagServerWithContext(context.Background(), r)
+}
+
+// UntagServerWithContext implements service.API
+func (m *Mock) UntagServerWithContext(ctx context.Context, r *request.UntagServerRequest) (*upcloud.ServerDetails, error) {
+	m.record("UntagServer", ctx, r)
+	if m.UntagServerFunc == nil {
+		return nil, notMocked("UntagServer")
+	}
+
+	return m.UntagServerFunc(ctx, r)
+}
+
+// Wait implements service.API
+func (m *Mock) Wait(r *service.WaitCondition) (interface{}, error) {
+	return m.WaitWithContext(context.Background(), r)
+}
+
+// WaitWithContext implements service.API
+func (m *Mock) WaitWithContext(ctx context.Context, r *service.WaitCondition) (interface{}, error) {
+	m.record("Wait", ctx, r)
+	if m.WaitFunc == nil {
+		return nil, notMocked("Wait")
+	}
+
+	return m.WaitFunc(ctx, r)
+}
+
+// WaitForBackupCreation implements service.API
+func (m *Mock) WaitForBackupCreation(r *request.WaitForBackupCreationRequest) (*upcloud.StorageDetails, error) {
+	return m.WaitForBackupCreationWithContext(context.Background(), r)
+}
+
+// WaitForBackupCreationWithContext implements service.API
+func (m *Mock) WaitForBackupCreationWithContext(ctx context.Context, r *request.WaitForBackupCreationRequest) (*upcloud.StorageDetails, error) {
+	m.record("WaitForBackupCreation", ctx, r)
+	if m.WaitForBackupCreationFunc == nil {
+		return nil, notMocked("WaitForBackupCreation")
+	}
+
+	return m.WaitForBackupCreationFunc(ctx, r)
+}
+
+// WaitForObjectStorageState implements service.API
+func (m *Mock) WaitForObjectStorageState(r *request.WaitForObjectStorageStateRequest) (*upcloud.ObjectStorageDetails, error) {
+	return m.WaitForObjectStorageStateWithContext(context.Background(), r)
+}
+
+// WaitForObjectStorageStateWithContext implements service.API
+func (m *Mock) WaitForObjectStorageStateWithContext(ctx context.Context, r *request.WaitForObjectStorageStateRequest) (*upcloud.ObjectStorageDetails, error) {
+	m.record("WaitForObjectStorageState", ctx, r)
+	if m.WaitForObjectStorageStateFunc == nil {
+		return nil, notMocked("WaitForObjectStorageState")
+	}
+
+	return m.WaitForObjectStorageStateFunc(ctx, r)
+}
+
+// WaitForServerState implements service.API
+func (m *Mock) WaitForServerState(r *request.WaitForServerStateRequest) (*upcloud.ServerDetails, error) {
+	return m.WaitForServerStateWithContext(context.Background(), r)
+}
+
+// WaitForServerStateWithContext implements service.API
+func (m *Mock) WaitForServerStateWithContext(ctx context.Context, r *request.WaitForServerStateRequest) (*upcloud.ServerDetails, error) {
+	m.record("WaitForServerState", ctx, r)
+	if m.WaitForServerStateFunc == nil {
+		return nil, notMocked("WaitForServerState")
+	}
+
+	return m.WaitForServerStateFunc(ctx, r)
+}
+
+// WaitForStorageCloneCompletion implements service.API
+func (m *Mock) WaitForStorageCloneCompletion(r *request.WaitForStorageCloneCompletionRequest) (*upcloud.StorageDetails, error) {
+	return m.WaitForStorageCloneCompletionWithContext(context.Background(), r)
+}
+
+// WaitForStorageCloneCompletionWithContext implements service.API
+func (m *Mock) WaitForStorageCloneCompletionWithContext(ctx context.Context, r *request.WaitForStorageCloneCompletionRequest) (*upcloud.StorageDetails, error) {
+	m.record("WaitForStorageCloneCompletion", ctx, r)
+	if m.WaitForStorageCloneCompletionFunc == nil {
+		return nil, notMocked("WaitForStorageCloneCompletion")
+	}
+
+	return m.WaitForStorageCloneCompletionFunc(ctx, r)
+}
+
+// WaitForStorageImportCompletion implements service.API
+func (m *Mock) WaitForStorageImportCompletion(r *request.WaitForStorageImportCompletionRequest) (*upcloud.StorageImportDetails, error) {
+	return m.WaitForStorageImportCompletionWithContext(context.Background(), r)
+}
+
+// WaitForStorageImportCompletionWithContext implements service.API
+func (m *Mock) WaitForStorageImportCompletionWithContext(ctx context.Context, r *request.WaitForStorageImportCompletionRequest) (*upcloud.StorageImportDetails, error) {
+	m.record("WaitForStorageImportCompletion", ctx, r)
+	if m.WaitForStorageImportCompletionFunc == nil {
+		return nil, notMocked("WaitForStorageImportCompletion")
+	}
+
+	return m.WaitForStorageImportCompletionFunc(ctx, r)
+}
+
+// WaitForStorageState implements service.API
+func (m *Mock) WaitForStorageState(r *request.WaitForStorageStateRequest) (*upcloud.StorageDetails, error) {
+	return m.WaitForStorageStateWithContext(context.Background(), r)
+}
+
+// WaitForStorageStateWithContext implements service.API
+func (m *Mock) WaitForStorageStateWithContext(ctx context.Context, r *request.WaitForStorageStateRequest) (*upcloud.StorageDetails, error) {
+	m.record("WaitForStorageState", ctx, r)
+	if m.WaitForStorageStateFunc == nil {
+		return nil, notMocked("WaitForStorageState")
+	}
+
+	return m.WaitForStorageStateFunc(ctx, r)
+}
+
+// AssignIPAddressReturns sets the response of AssignIPAddress and AssignIPAddressWithContext
+func (s *Stub) AssignIPAddressReturns(result *upcloud.IPAddress, err error) {
+	s.set("AssignIPAddress", result, err)
+}
+
+// AssignIPAddress implements service.API
+func (s *Stub) AssignIPAddress(r *request.AssignIPAddressRequest) (*upcloud.IPAddress, error) {
+	return s.AssignIPAddressWithContext(context.Background(), r)
+}
+
+// AssignIPAddressWithContext implements service.API
+func (s *Stub) AssignIPAddressWithContext(ctx context.Context, r *request.AssignIPAddressRequest) (*upcloud.IPAddress, error) {
+	s.record("AssignIPAddress", ctx, r)
+	if resp, ok := s.get("AssignIPAddress"); ok {
+		result, _ := resp.result.(*upcloud.IPAddress)
+		return result, resp.err
+	}
+
+	return &upcloud.IPAddress{}, nil
+}
+
+// AttachStorageReturns sets the response of AttachStorage and AttachStorageWithContext
+func (s *Stub) AttachStorageReturns(result *upcloud.ServerDetails, err error) {
+	s.set("AttachStorage", result, err)
+}
+
+// AttachStorage implements service.API
+func (s *Stub) AttachStorage(r *request.AttachStorageRequest) (*upcloud.ServerDetails, error) {
+	return s.AttachStorageWithContext(context.Background(), r)
+}
+
+// AttachStorageWithContext implements service.API
+func (s *Stub) AttachStorageWithContext(ctx context.Context, r *request.AttachStorageRequest) (*upcloud.ServerDetails, error) {
+	s.record("AttachStorage", ctx, r)
+	if resp, ok := s.get("AttachStorage"); ok {
+		result, _ := resp.result.(*upcloud.ServerDetails)
+		return result, resp.err
+	}
+
+	return &upcloud.ServerDetails{}, nil
+}
+
+// CloneStorageReturns sets the response of CloneStorage and CloneStorageWithContext
+func (s *Stub) CloneStorageReturns(result *upcloud.StorageDetails, err error) {
+	s.set("CloneStorage", result, err)
+}
+
+// CloneStorage implements service.API
+func (s *Stub) CloneStorage(r *request.CloneStorageRequest) (*upcloud.StorageDetails, error) {
+	return s.CloneStorageWithContext(context.Background(), r)
+}
+
+// CloneStorageWithContext implements service.API
+func (s *Stub) CloneStorageWithContext(ctx context.Context, r *request.CloneStorageRequest) (*upcloud.StorageDetails, error) {
+	s.record("CloneStorage", ctx, r)
+	if resp, ok := s.get("CloneStorage"); ok {
+		result, _ := resp.result.(*upcloud.StorageDetails)
+		return result, resp.err
+	}
+
+	return &upcloud.StorageDetails{}, nil
+}
+
+// CreateBackupReturns sets the response of CreateBackup and CreateBackupWithContext
+func (s *Stub) CreateBackupReturns(result *upcloud.StorageDetails, err error) {
+	s.set("CreateBackup", result, err)
+}
+
+// CreateBackup implements service.API
+func (s *Stub) CreateBackup(r *request.CreateBackupRequest) (*upcloud.StorageDetails, error) {
+	return s.CreateBackupWithContext(context.Background(), r)
+}
+
+// CreateBackupWithContext implements service.API
+func (s *Stub) CreateBackupWithContext(ctx context.Context, r *request.CreateBackupRequest) (*upcloud.StorageDetails, error) {
+	s.record("CreateBackup", ctx, r)
+	if resp, ok := s.get("CreateBackup"); ok {
+		result, _ := resp.result.(*upcloud.StorageDetails)
+		return result, resp.err
+	}
+
+	return &upcloud.StorageDetails{}, nil
+}
+
+// CreateFirewallRuleReturns sets the response of CreateFirewallRule and CreateFirewallRuleWithContext
+func (s *Stub) CreateFirewallRuleReturns(result *upcloud.FirewallRule, err error) {
+	s.set("CreateFirewallRule", result, err)
+}
+
+// CreateFirewallRule implements service.API
+func (s *Stub) CreateFirewallRule(r *request.CreateFirewallRuleRequest) (*upcloud.FirewallRule, error) {
+	return s.CreateFirewallRuleWithContext(context.Background(), r)
+}
+
+// CreateFirewallRuleWithContext implements service.API
+func (s *Stub) CreateFirewallRuleWithContext(ctx context.Context, r *request.CreateFirewallRuleRequest) (*upcloud.FirewallRule, error) {
+	s.record("CreateFirewallRule", ctx, r)
+	if resp, ok := s.get("CreateFirewallRule"); ok {
+		result, _ := resp.result.(*upcloud.FirewallRule)
+		return result, resp.err
+	}
+
+	return &upcloud.FirewallRule{}, nil
+}
+
+// CreateFirewallRulesReturns sets the response of CreateFirewallRules and CreateFirewallRulesWithContext
+func (s *Stub) CreateFirewallRulesReturns(err error) {
+	s.set("CreateFirewallRules", nil, err)
+}
+
+// CreateFirewallRules implements service.API
+func (s *Stub) CreateFirewallRules(r *request.CreateFirewallRulesRequest) error {
+	return s.CreateFirewallRulesWithContext(context.Background(), r)
+}
+
+// CreateFirewallRulesWithContext implements service.API
+func (s *Stub) CreateFirewallRulesWithContext(ctx context.Context, r *request.CreateFirewallRulesRequest) error {
+	s.record("CreateFirewallRules", ctx, r)
+	if resp, ok := s.get("CreateFirewallRules"); ok {
+		return resp.err
+	}
+
+	return nil
+}
+
+// CreateNetworkReturns sets the response of CreateNetwork and CreateNetworkWithContext
+func (s *Stub) CreateNetworkReturns(result *upcloud.Network, err error) {
+	s.set("CreateNetwork", result, err)
+}
+
+// CreateNetwork implements service.API
+func (s *Stub) CreateNetwork(r *request.CreateNetworkRequest) (*upcloud.Network, error) {
+	return s.CreateNetworkWithContext(context.Background(), r)
+}
+
+// CreateNetworkWithContext implements service.API
+func (s *Stub) CreateNetworkWithContext(ctx context.Context, r *request.CreateNetworkRequest) (*upcloud.Network, error) {
+	s.record("CreateNetwork", ctx, r)
+	if resp, ok := s.get("CreateNetwork"); ok {
+		result, _ := resp.result.(*upcloud.Network)
+		return result, resp.err
+	}
+
+	return &upcloud.Network{}, nil
+}
+
+// CreateNetworkInterfaceReturns sets the response of CreateNetworkInterface and CreateNetworkInterfaceWithContext
+func (s *Stub) CreateNetworkInterfaceReturns(result *upcloud.Interface, err error) {
+	s.set("CreateNetworkInterface", result, err)
+}
+
+// CreateNetworkInterface implements service.API
+func (s *Stub) CreateNetworkInterface(r *request.CreateNetworkInterfaceRequest) (*upcloud.Interface, error) {
+	return s.CreateNetworkInterfaceWithContext(context.Background(), r)
+}
+
+// CreateNetworkInterfaceWithContext implements service.API
+func (s *Stub) CreateNetworkInterfaceWithContext(ctx context.Context, r *request.CreateNetworkInterfaceRequest) (*upcloud.Interface, error) {
+	s.record("CreateNetworkInterface", ctx, r)
+	if resp, ok := s.get("CreateNetworkInterface"); ok {
+		result, _ := resp.result.(*upcloud.Interface)
+		return result, resp.err
+	}
+
+	return &upcloud.Interface{}, nil
+}
+
+// CreateObjectStorageReturns sets the response of CreateObjectStorage and CreateObjectStorageWithContext
+func (s *Stub) CreateObjectStorageReturns(result *upcloud.ObjectStorageDetails, err error) {
+	s.set("CreateObjectStorage", result, err)
+}
+
+// CreateObjectStorage implements service.API
+func (s *Stub) CreateObjectStorage(r *request.CreateObjectStorageRequest) (*upcloud.ObjectStorageDetails, error) {
+	return s.CreateObjectStorageWithContext(context.Background(), r)
+}
+
+// CreateObjectStorageWithContext implements service.API
+func (s *Stub) CreateObjectStorageWithContext(ctx context.Context, r *request.CreateObjectStorageRequest) (*upcloud.ObjectStorageDetails, error) {
+	s.record("CreateObjectStorage", ctx, r)
+	if resp, ok := s.get("CreateObjectStorage"); ok {
+		result, _ := resp.result.(*upcloud.ObjectStorageDetails)
+		return result, resp.err
+	}
+
+	return &upcloud.ObjectStorageDetails{}, nil
+}
+
+// CreateRouterReturns sets the response of CreateRouter and CreateRouterWithContext
+func (s *Stub) CreateRouterReturns(result *upcloud.Router, err error) {
+	s.set("CreateRouter", result, err)
+}
+
+// CreateRouter implements service.API
+func (s *Stub) CreateRouter(r *request.CreateRouterRequest) (*upcloud.Router, error) {
+	return s.CreateRouterWithContext(context.Background(), r)
+}
+
+// CreateRouterWithContext implements service.API
+func (s *Stub) CreateRouterWithContext(ctx context.Context, r *request.CreateRouterRequest) (*upcloud.Router, error) {
+	s.record("CreateRouter", ctx, r)
+	if resp, ok := s.get("CreateRouter"); ok {
+		result, _ := resp.result.(*upcloud.Router)
+		return result, resp.err
+	}
+
+	return &upcloud.Router{}, nil
+}
+
+// CreateServerReturns sets the response of CreateServer and CreateServerWithContext
+func (s *Stub) CreateServerReturns(result *upcloud.ServerDetails, err error) {
+	s.set("CreateServer", result, err)
+}
+
+// CreateServer implements service.API
+func (s *Stub) CreateServer(r *request.CreateServerRequest) (*upcloud.ServerDetails, error) {
+	return s.CreateServerWithContext(context.Background(), r)
+}
+
+// CreateServerWithContext implements service.API
+func (s *Stub) CreateServerWithContext(ctx context.Context, r *request.CreateServerRequest) (*upcloud.ServerDetails, error) {
+	s.record("CreateServer", ctx, r)
+	if resp, ok := s.get("CreateServer"); ok {
+		result, _ := resp.result.(*upcloud.ServerDetails)
+		return result, resp.err
+	}
+
+	return &upcloud.ServerDetails{}, nil
+}
+
+// CreateStorageReturns sets the response of CreateStorage and CreateStorageWithContext
+func (s *Stub) CreateStorageReturns(result *upcloud.StorageDetails, err error) {
+	s.set("CreateStorage", result, err)
+}
+
+// CreateStorage implements service.API
+func (s *Stub) CreateStorage(r *request.CreateStorageRequest) (*upcloud.StorageDetails, error) {
+	return s.CreateStorageWithContext(context.Background(), r)
+}
+
+// CreateStorageWithContext implements service.API
+func (s *Stub) CreateStorageWithContext(ctx context.Context, r *request.CreateStorageRequest) (*upcloud.StorageDetails, error) {
+	s.record("CreateStorage", ctx, r)
+	if resp, ok := s.get("CreateStorage"); ok {
+		result, _ := resp.result.(*upcloud.StorageDetails)
+		return result, resp.err
+	}
+
+	return &upcloud.StorageDetails{}, nil
+}
+
+// CreateStorageImportReturns sets the response of CreateStorageImport and CreateStorageImportWithContext
+func (s *Stub) CreateStorageImportReturns(result *upcloud.StorageImportDetails, err error) {
+	s.set("CreateStorageImport", result, err)
+}
+
+// CreateStorageImport implements service.API
+func (s *Stub) CreateStorageImport(r *request.CreateStorageImportRequest) (*upcloud.StorageImportDetails, error) {
+	return s.CreateStorageImportWithContext(context.Background(), r)
+}
+
+// CreateStorageImportWithContext implements service.API
+func (s *Stub) CreateStorageImportWithContext(ctx context.Context, r *request.CreateStorageImportRequest) (*upcloud.StorageImportDetails, error) {
+	s.record("CreateStorageImport", ctx, r)
+	if resp, ok := s.get("CreateStorageImport"); ok {
+		result, _ := resp.result.(*upcloud.StorageImportDetails)
+		return result, resp.err
+	}
+
+	return &upcloud.StorageImportDetails{}, nil
+}
+
+// CreateTagReturns sets the response of CreateTag and CreateTagWithContext
+func (s *Stub) CreateTagReturns(result *upcloud.Tag, err error) {
+	s.set("CreateTag", result, err)
+}
+
+// CreateTag implements service.API
+func (s *Stub) CreateTag(r *request.CreateTagRequest) (*upcloud.Tag, error) {
+	return s.CreateTagWithContext(context.Background(), r)
+}
+
+// CreateTagWithContext implements service.API
+func (s *Stub) CreateTagWithContext(ctx context.Context, r *request.CreateTagRequest) (*upcloud.Tag, error) {
+	s.record("CreateTag", ctx, r)
+	if resp, ok := s.get("CreateTag"); ok {
+		result, _ := resp.result.(*upcloud.Tag)
+		return result, resp.err
+	}
+
+	return &upcloud.Tag{}, nil
+}
+
+// DeleteFirewallRuleReturns sets the response of DeleteFirewallRule and DeleteFirewallRuleWithContext
+func (s *Stub) DeleteFirewallRuleReturns(err error) {
+	s.set("DeleteFirewallRule", nil, err)
+}
+
+// DeleteFirewallRule implements service.API
+func (s *Stub) DeleteFirewallRule(r *request.DeleteFirewallRuleRequest) error {
+	return s.DeleteFirewallRuleWithContext(context.Background(), r)
+}
+
+// DeleteFirewallRuleWithContext implements service.API
+func (s *Stub) DeleteFirewallRuleWithContext(ctx context.Context, r *request.DeleteFirewallRuleRequest) error {
+	s.record("DeleteFirewallRule", ctx, r)
+	if resp, ok := s.get("DeleteFirewallRule"); ok {
+		return resp.err
+	}
+
+	return nil
+}
+
+// DeleteNetworkReturns sets the response of DeleteNetwork and DeleteNetworkWithContext
+func (s *Stub) DeleteNetworkReturns(err error) {
+	s.set("DeleteNetwork", nil, err)
+}
+
+// DeleteNetwork implements service.API
+func (s *Stub) DeleteNetwork(r *request.DeleteNetworkRequest) error {
+	return s.DeleteNetworkWithContext(context.Background(), r)
+}
+
+// DeleteNetworkWithContext implements service.API
+func (s *Stub) DeleteNetworkWithContext(ctx context.Context, r *request.DeleteNetworkRequest) error {
+	s.record("DeleteNetwork", ctx, r)
+	if resp, ok := s.get("DeleteNetwork"); ok {
+		return resp.err
+	}
+
+	return nil
+}
+
+// DeleteNetworkInterfaceReturns sets the response of DeleteNetworkInterface and DeleteNetworkInterfaceWithContext
+func (s *Stub) DeleteNetworkInterfaceReturns(err error) {
+	s.set("DeleteNetworkInterface", nil, err)
+}
+
+// DeleteNetworkInterface implements service.API
+func (s *Stub) DeleteNetworkInterface(r *request.DeleteNetworkInterfaceRequest) error {
+	return s.DeleteNetworkInterfaceWithContext(context.Background(), r)
+}
+
+// DeleteNetworkInterfaceWithContext implements service.API
+func (s *Stub) DeleteNetworkInterfaceWithContext(ctx context.Context, r *request.DeleteNetworkInterfaceRequest) error {
+	s.record("DeleteNetworkInterface", ctx, r)
+	if resp, ok := s.get("DeleteNetworkInterface"); ok {
+		return resp.err
+	}
+
+	return nil
+}
+
+// DeleteObjectStorageReturns sets the response of DeleteObjectStorage and DeleteObjectStorageWithContext
+func (s *Stub) DeleteObjectStorageReturns(err error) {
+	s.set("DeleteObjectStorage", nil, err)
+}
+
+// DeleteObjectStorage implements service.API
+func (s *Stub) DeleteObjectStorage(r *request.DeleteObjectStorageRequest) error {
+	return s.DeleteObjectStorageWithContext(context.Background(), r)
+}
+
+// DeleteObjectStorageWithContext implements service.API
+func (s *Stub) DeleteObjectStorageWithContext(ctx context.Context, r *request.DeleteObjectStorageRequest) error {
+	s.record("DeleteObjectStorage", ctx, r)
+	if resp, ok := s.get("DeleteObjectStorage"); ok {
+		return resp.err
+	}
+
+	return nil
+}
+
+// DeleteRouterReturns sets the response of DeleteRouter and DeleteRouterWithContext
+func (s *Stub) DeleteRouterReturns(err error) {
+	s.set("DeleteRouter", nil, err)
+}
+
+// DeleteRouter implements service.API
+func (s *Stub) DeleteRouter(r *request.DeleteRouterRequest) error {
+	return s.DeleteRouterWithContext(context.Background(), r)
+}
+
+// DeleteRouterWithContext implements service.API
+func (s *Stub) DeleteRouterWithContext(ctx context.Context, r *request.DeleteRouterRequest) error {
+	s.record("DeleteRouter", ctx, r)
+	if resp, ok := s.get("DeleteRouter"); ok {
+		return resp.err
+	}
+
+	return nil
+}
+
+// DeleteServerReturns sets the response of DeleteServer and DeleteServerWithContext
+func (s *Stub) DeleteServerReturns(err error) {
+	s.set("DeleteServer", nil, err)
+}
+
+// DeleteServer implements service.API
+func (s *Stub) DeleteServer(r *request.DeleteServerRequest) error {
+	return s.DeleteServerWithContext(context.Background(), r)
+}
+
+// DeleteServerWithContext implements service.API
+func (s *Stub) DeleteServerWithContext(ctx context.Context, r *request.DeleteServerRequest) error {
+	s.record("DeleteServer", ctx, r)
+	if resp, ok := s.get("DeleteServer"); ok {
+		return resp.err
+	}
+
+	return nil
+}
+
+// DeleteServerAndStoragesReturns sets the response of DeleteServerAndStorages and DeleteServerAndStoragesWithContext
+func (s *Stub) DeleteServerAndStoragesReturns(err error) {
+	s.set("DeleteServerAndStorages", nil, err)
+}
+
+// DeleteServerAndStorages implements service.API
+func (s *Stub) DeleteServerAndStorages(r *request.DeleteServerAndStoragesRequest) error {
+	return s.DeleteServerAndStoragesWithContext(context.Background(), r)
+}
+
+// DeleteServerAndStoragesWithContext implements service.API
+func (s *Stub) DeleteServerAndStoragesWithContext(ctx context.Context, r *request.DeleteServerAndStoragesRequest) error {
+	s.record("DeleteServerAndStorages", ctx, r)
+	if resp, ok := s.get("DeleteServerAndStorages"); ok {
+		return resp.err
+	}
+
+	return nil
+}
+
+// DeleteStorageReturns sets the response of DeleteStorage and DeleteStorageWithContext
+func (s *Stub) DeleteStorageReturns(err error) {
+	s.set("DeleteStorage", nil, err)
+}
+
+// DeleteStorage implements service.API
+func (s *Stub) DeleteStorage(r *request.DeleteStorageRequest) error {
+	return s.DeleteStorageWithContext(context.Background(), r)
+}
+
+// DeleteStorageWithContext implements service.API
+func (s *Stub) DeleteStorageWithContext(ctx context.Context, r *request.DeleteStorageRequest) error {
+	s.record("DeleteStorage", ctx, r)
+	if resp, ok := s.get("DeleteStorage"); ok {
+		return resp.err
+	}
+
+	return nil
+}
+
+// DeleteTagReturns sets the response of DeleteTag and DeleteTagWithContext
+func (s *Stub) DeleteTagReturns(err error) {
+	s.set("DeleteTag", nil, err)
+}
+
+// DeleteTag implements service.API
+func (s *Stub) DeleteTag(r *request.DeleteTagRequest) error {
+	return s.DeleteTagWithContext(context.Background(), r)
+}
+
+// DeleteTagWithContext implements service.API
+func (s *Stub) DeleteTagWithContext(ctx context.Context, r *request.DeleteTagRequest) error {
+	s.record("DeleteTag", ctx, r)
+	if resp, ok := s.get("DeleteTag"); ok {
+		return resp.err
+	}
+
+	return nil
+}
+
+// DetachStorageReturns sets the response of DetachStorage and DetachStorageWithContext
+func (s *Stub) DetachStorageReturns(result *upcloud.ServerDetails, err error) {
+	s.set("DetachStorage", result, err)
+}
+
+// DetachStorage implements service.API
+func (s *Stub) DetachStorage(r *request.DetachStorageRequest) (*upcloud.ServerDetails, error) {
+	return s.DetachStorageWithContext(context.Background(), r)
+}
+
+// DetachStorageWithContext implements service.API
+func (s *Stub) DetachStorageWithContext(ctx context.Context, r *request.DetachStorageRequest) (*upcloud.ServerDetails, error) {
+	s.record("DetachStorage", ctx, r)
+	if resp, ok := s.get("DetachStorage"); ok {
+		result, _ := resp.result.(*upcloud.ServerDetails)
+		return result, resp.err
+	}
+
+	return &upcloud.ServerDetails{}, nil
+}
+
+// EjectCDROMReturns sets the response of EjectCDROM and EjectCDROMWithContext
+func (s *Stub) EjectCDROMReturns(result *upcloud.ServerDetails, err error) {
+	s.set("EjectCDROM", result, err)
+}
+
+// EjectCDROM implements service.API
+func (s *Stub) EjectCDROM(r *request.EjectCDROMRequest) (*upcloud.ServerDetails, error) {
+	return s.EjectCDROMWithContext(context.Background(), r)
+}
+
+// EjectCDROMWithContext implements service.API
+func (s *Stub) EjectCDROMWithContext(ctx context.Context, r *request.EjectCDROMRequest) (*upcloud.ServerDetails, error) {
+	s.record("EjectCDROM", ctx, r)
+	if resp, ok := s.get("EjectCDROM"); ok {
+		result, _ := resp.result.(*upcloud.ServerDetails)
+		return result, resp.err
+	}
+
+	return &upcloud.ServerDetails{}, nil
+}
+
+// GetAccountReturns sets the response of GetAccount and GetAccountWithContext
+func (s *Stub) GetAccountReturns(result *upcloud.Account, err error) {
+	s.set("GetAccount", result, err)
+}
+
+// GetAccount implements service.API
+func (s *Stub) GetAccount() (*upcloud.Account, error) {
+	return s.GetAccountWithContext(context.Background())
+}
+
+// GetAccountWithContext implements service.API
+func (s *Stub) GetAccountWithContext(ctx context.Context) (*upcloud.Account, error) {
+	s.record("GetAccount", ctx, nil)
+	if resp, ok := s.get("GetAccount"); ok {
+		result, _ := resp.result.(*upcloud.Account)
+		return result, resp.err
+	}
+
+	return &upcloud.Account{}, nil
+}
+
+// GetFirewallRuleDetailsReturns sets the response of GetFirewallRuleDetails and GetFirewallRuleDetailsWithContext
+func (s *Stub) GetFirewallRuleDetailsReturns(result *upcloud.FirewallRule, err error) {
+	s.set("GetFirewallRuleDetails", result, err)
+}
+
+// GetFirewallRuleDetails implements service.API
+func (s *Stub) GetFirewallRuleDetails(r *request.GetFirewallRuleDetailsRequest) (*upcloud.FirewallRule, error) {
+	return s.GetFirewallRuleDetailsWithContext(context.Background(), r)
+}
+
+// GetFirewallRuleDetailsWithContext implements service.API
+func (s *Stub) GetFirewallRuleDetailsWithContext(ctx context.Context, r *request.GetFirewallRuleDetailsRequest) (*upcloud.FirewallRule, error) {
+	s.record("GetFirewallRuleDetails", ctx, r)
+	if resp, ok := s.get("GetFirewallRuleDetails"); ok {
+		result, _ := resp.result.(*upcloud.FirewallRule)
+		return result, resp.err
+	}
+
+	return &upcloud.FirewallRule{}, nil
+}
+
+// GetFirewallRulesReturns sets the response of GetFirewallRules and GetFirewallRulesWithContext
+func (s *Stub) GetFirewallRulesReturns(result *upcloud.FirewallRules, err error) {
+	s.set("GetFirewallRules", result, err)
+}
+
+// GetFirewallRules implements service.API
+func (s *Stub) GetFirewallRules(r *request.GetFirewallRulesRequest) (*upcloud.FirewallRules, error) {
+	return s.GetFirewallRulesWithContext(context.Background(), r)
+}
+
+// GetFirewallRulesWithContext implements service.API
+func (s *Stub) GetFirewallRulesWithContext(ctx context.Context, r *request.GetFirewallRulesRequest) (*upcloud.FirewallRules, error) {
+	s.record("GetFirewallRules", ctx, r)
+	if resp, ok := s.get("GetFirewallRules"); ok {
+		result, _ := resp.result.(*upcloud.FirewallRules)
+		return result, resp.err
+	}
+
+	return &upcloud.FirewallRules{}, nil
+}
+
+// GetHostDetailsReturns sets the response of GetHostDetails and GetHostDetailsWithContext
+func (s *Stub) GetHostDetailsReturns(result *upcloud.Host, err error) {
+	s.set("GetHostDetails", result, err)
+}
+
+// GetHostDetails implements service.API
+func (s *Stub) GetHostDetails(r *request.GetHostDetailsRequest) (*upcloud.Host, error) {
+	return s.GetHostDetailsWithContext(context.Background(), r)
+}
+
+// GetHostDetailsWithContext implements service.API
+func (s *Stub) GetHostDetailsWithContext(ctx context.Context, r *request.GetHostDetailsRequest) (*upcloud.Host, error) {
+	s.record("GetHostDetails", ctx, r)
+	if resp, ok := s.get("GetHostDetails"); ok {
+		result, _ := resp.result.(*upcloud.Host)
+		return result, resp.err
+	}
+
+	return &upcloud.Host{}, nil
+}
+
+// GetHostsReturns sets the response of GetHosts and GetHostsWithContext
+func (s *Stub) GetHostsReturns(result *upcloud.Hosts, err error) {
+	s.set("GetHosts", result, err)
+}
+
+// GetHosts implements service.API
+func (s *Stub) GetHosts() (*upcloud.Hosts, error) {
+	return s.GetHostsWithContext(context.Background())
+}
+
+// GetHostsWithContext implements service.API
+func (s *Stub) GetHostsWithContext(ctx context.Context) (*upcloud.Hosts, error) {
+	s.record("GetHosts", ctx, nil)
+	if resp, ok := s.get("GetHosts"); ok {
+		result, _ := resp.result.(*upcloud.Hosts)
+		return result, resp.err
+	}
+
+	return &upcloud.Hosts{}, nil
+}
+
+// GetIPAddressDetailsReturns sets the response of GetIPAddressDetails and GetIPAddressDetailsWithContext
+func (s *Stub) GetIPAddressDetailsReturns(result *upcloud.IPAddress, err error) {
+	s.set("GetIPAddressDetails", result, err)
+}
+
+// GetIPAddressDetails implements service.API
+func (s *Stub) GetIPAddressDetails(r *request.GetIPAddressDetailsRequest) (*upcloud.IPAddress, error) {
+	return s.GetIPAddressDetailsWithContext(context.Background(), r)
+}
+
+// GetIPAddressDetailsWithContext implements service.API
+func (s *Stub) GetIPAddressDetailsWithContext(ctx context.Context, r *request.GetIPAddressDetailsRequest) (*upcloud.IPAddress, error) {
+	s.record("GetIPAddressDetails", ctx, r)
+	if resp, ok := s.get("GetIPAddressDetails"); ok {
+		result, _ := resp.result.(*upcloud.IPAddress)
+		return result, resp.err
+	}
+
+	return &upcloud.IPAddress{}, nil
+}
+
+// GetIPAddressesReturns sets the response of GetIPAddresses and GetIPAddressesWithContext
+func (s *Stub) GetIPAddressesReturns(result *upcloud.IPAddresses, err error) {
+	s.set("GetIPAddresses", result, err)
+}
+
+// GetIPAddresses implements service.API
+func (s *Stub) GetIPAddresses() (*upcloud.IPAddresses, error) {
+	return s.GetIPAddressesWithContext(context.Background())
+}
+
+// GetIPAddressesWithContext implements service.API
+func (s *Stub) GetIPAddressesWithContext(ctx context.Context) (*upcloud.IPAddresses, error) {
+	s.record("GetIPAddresses", ctx, nil)
+	if resp, ok := s.get("GetIPAddresses"); ok {
+		result, _ := resp.result.(*upcloud.IPAddresses)
+		return result, resp.err
+	}
+
+	return &upcloud.IPAddresses{}, nil
+}
+
+// GetNetworkDetailsReturns sets the response of GetNetworkDetails and GetNetworkDetailsWithContext
+func (s *Stub) GetNetworkDetailsReturns(result *upcloud.Network, err error) {
+	s.set("GetNetworkDetails", result, err)
+}
+
+// GetNetworkDetails implements service.API
+func (s *Stub) GetNetworkDetails(r *request.GetNetworkDetailsRequest) (*upcloud.Network, error) {
+	return s.GetNetworkDetailsWithContext(context.Background(), r)
+}
+
+// GetNetworkDetailsWithContext implements service.API
+func (s *Stub) GetNetworkDetailsWithContext(ctx context.Context, r *request.GetNetworkDetailsRequest) (*upcloud.Network, error) {
+	s.record("GetNetworkDetails", ctx, r)
+	if resp, ok := s.get("GetNetworkDetails"); ok {
+		result, _ := resp.result.(*upcloud.Network)
+		return result, resp.err
+	}
+
+	return &upcloud.Network{}, nil
+}
+
+// GetNetworksReturns sets the response of GetNetworks and GetNetworksWithContext
+func (s *Stub) GetNetworksReturns(result *upcloud.Networks, err error) {
+	s.set("GetNetworks", result, err)
+}
+
+// GetNetworks implements service.API
+func (s *Stub) GetNetworks() (*upcloud.Networks, error) {
+	return s.GetNetworksWithContext(context.Background())
+}
+
+// GetNetworksWithContext implements service.API
+func (s *Stub) GetNetworksWithContext(ctx context.Context) (*upcloud.Networks, error) {
+	s.record("GetNetworks", ctx, nil)
+	if resp, ok := s.get("GetNetworks"); ok {
+		result, _ := resp.result.(*upcloud.Networks)
+		return result, resp.err
+	}
+
+	return &upcloud.Networks{}, nil
+}
+
+// GetNetworksInZoneReturns sets the response of GetNetworksInZone and GetNetworksInZoneWithContext
+func (s *Stub) GetNetworksInZoneReturns(result *upcloud.Networks, err error) {
+	s.set("GetNetworksInZone", result, err)
+}
+
+// GetNetworksInZone implements service.API
+func (s *Stub) GetNetworksInZone(r *request.GetNetworksInZoneRequest) (*upcloud.Networks, error) {
+	return s.GetNetworksInZoneWithContext(context.Background(), r)
+}
+
+// GetNetworksInZoneWithContext implements service.API
+func (s *Stub) GetNetworksInZoneWithContext(ctx context.Context, r *request.GetNetworksInZoneRequest) (*upcloud.Networks, error) {
+	s.record("GetNetworksInZone", ctx, r)
+	if resp, ok := s.get("GetNetworksInZone"); ok {
+		result, _ := resp.result.(*upcloud.Networks)
+		return result, resp.err
+	}
+
+	return &upcloud.Networks{}, nil
+}
+
+// GetObjectStorageDetailsReturns sets the response of GetObjectStorageDetails and GetObjectStorageDetailsWithContext
+func (s *Stub) GetObjectStorageDetailsReturns(result *upcloud.ObjectStorageDetails, err error) {
+	s.set("GetObjectStorageDetails", result, err)
+}
+
+// GetObjectStorageDetails implements service.API
+func (s *Stub) GetObjectStorageDetails(r *request.GetObjectStorageDetailsRequest) (*upcloud.ObjectStorageDetails, error) {
+	return s.GetObjectStorageDetailsWithContext(context.Background(), r)
+}
+
+// GetObjectStorageDetailsWithContext implements service.API
+func (s *Stub) GetObjectStorageDetailsWithContext(ctx context.Context, r *request.GetObjectStorageDetailsRequest) (*upcloud.ObjectStorageDetails, error) {
+	s.record("GetObjectStorageDetails", ctx, r)
+	if resp, ok := s.get("GetObjectStorageDetails"); ok {
+		result, _ := resp.result.(*upcloud.ObjectStorageDetails)
+		return result, resp.err
+	}
+
+	return &upcloud.ObjectStorageDetails{}, nil
+}
+
+// GetObjectStoragesReturns sets the response of GetObjectStorages and GetObjectStoragesWithContext
+func (s *Stub) GetObjectStoragesReturns(result *upcloud.ObjectStorages, err error) {
+	s.set("GetObjectStorages", result, err)
+}
+
+// GetObjectStorages implements service.API
+func (s *Stub) GetObjectStorages() (*upcloud.ObjectStorages, error) {
+	return s.GetObjectStoragesWithContext(context.Background())
+}
+
+// GetObjectStoragesWithContext implements service.API
+func (s *Stub) GetObjectStoragesWithContext(ctx context.Context) (*upcloud.ObjectStorages, error) {
+	s.record("GetObjectStorages", ctx, nil)
+	if resp, ok := s.get("GetObjectStorages"); ok {
+		result, _ := resp.result.(*upcloud.ObjectStorages)
+		return result, resp.err
+	}
+
+	return &upcloud.ObjectStorages{}, nil
+}
+
+// GetPlansReturns sets the response of GetPlans and GetPlansWithContext
+func (s *Stub) GetPlansReturns(result *upcloud.Plans, err error) {
+	s.set("GetPlans", result, err)
+}
+
+// GetPlans implements service.API
+func (s *Stub) GetPlans() (*upcloud.Plans, error) {
+	return s.GetPlansWithContext(context.Background())
+}
+
+// GetPlansWithContext implements service.API
+func (s *Stub) GetPlansWithContext(ctx context.Context) (*upcloud.Plans, error) {
+	s.record("GetPlans", ctx, nil)
+	if resp, ok := s.get("GetPlans"); ok {
+		result, _ := resp.result.(*upcloud.Plans)
+		return result, resp.err
+	}
+
+	return &upcloud.Plans{}, nil
+}
+
+// GetPriceZonesReturns sets the response of GetPriceZones and GetPriceZonesWithContext
+func (s *Stub) GetPriceZonesReturns(result *upcloud.PriceZones, err error) {
+	s.set("GetPriceZones", result, err)
+}
+
+// GetPriceZones implements service.API
+func (s *Stub) GetPriceZones() (*upcloud.PriceZones, error) {
+	return s.GetPriceZonesWithContext(context.Background())
+}
+
+// GetPriceZonesWithContext implements service.API
+func (s *Stub) GetPriceZonesWithContext(ctx context.Context) (*upcloud.PriceZones, error) {
+	s.record("GetPriceZones", ctx, nil)
+	if resp, ok := s.get("GetPriceZones"); ok {
+		result, _ := resp.result.(*upcloud.PriceZones)
+		return result, resp.err
+	}
+
+	return &upcloud.PriceZones{}, nil
+}
+
+// GetRouterDetailsReturns sets the response of GetRouterDetails and GetRouterDetailsWithContext
+func (s *Stub) GetRouterDetailsReturns(result *upcloud.Router, err error) {
+	s.set("GetRouterDetails", result, err)
+}
+
+// GetRouterDetails implements service.API
+func (s *Stub) GetRouterDetails(r *request.GetRouterDetailsRequest) (*upcloud.Router, error) {
+	return s.GetRouterDetailsWithContext(context.Background(), r)
+}
+
+// GetRouterDetailsWithContext implements service.API
+func (s *Stub) GetRouterDetailsWithContext(ctx context.Context, r *request.GetRouterDetailsRequest) (*upcloud.Router, error) {
+	s.record("GetRouterDetails", ctx, r)
+	if resp, ok := s.get("GetRouterDetails"); ok {
+		result, _ := resp.result.(*upcloud.Router)
+		return result, resp.err
+	}
+
+	return &upcloud.Router{}, nil
+}
+
+// GetRoutersReturns sets the response of GetRouters and GetRoutersWithContext
+func (s *Stub) GetRoutersReturns(result *upcloud.Routers, err error) {
+	s.set("GetRouters", result, err)
+}
+
+// GetRouters implements service.API
+func (s *Stub) GetRouters() (*upcloud.Routers, error) {
+	return s.GetRoutersWithContext(context.Background())
+}
+
+// GetRoutersWithContext implements service.API
+func (s *Stub) GetRoutersWithContext(ctx context.Context) (*upcloud.Routers, error) {
+	s.record("GetRouters", ctx, nil)
+	if resp, ok := s.get("GetRouters"); ok {
+		result, _ := resp.result.(*upcloud.Routers)
+		return result, resp.err
+	}
+
+	return &upcloud.Routers{}, nil
+}
+
+// GetServerConfigurationsReturns sets the response of GetServerConfigurations and GetServerConfigurationsWithContext
+func (s *Stub) GetServerConfigurationsReturns(result *upcloud.ServerConfigurations, err error) {
+	s.set("GetServerConfigurations", result, err)
+}
+
+// GetServerConfigurations implements service.API
+func (s *Stub) GetServerConfigurations() (*upcloud.ServerConfigurations, error) {
+	return s.GetServerConfigurationsWithContext(context.Background())
+}
+
+// GetServerConfigurationsWithContext implements service.API
+func (s *Stub) GetServerConfigurationsWithContext(ctx context.Context) (*upcloud.ServerConfigurations, error) {
+	s.record("GetServerConfigurations", ctx, nil)
+	if resp, ok := s.get("GetServerConfigurations"); ok {
+		result, _ := resp.result.(*upcloud.ServerConfigurations)
+		return result, resp.err
+	}
+
+	return &upcloud.ServerConfigurations{}, nil
+}
+
+// GetServerDetailsReturns sets the response of GetServerDetails and GetServerDetailsWithContext
+func (s *Stub) GetServerDetailsReturns(result *upcloud.ServerDetails, err error) {
+	s.set("GetServerDetails", result, err)
+}
+
+// GetServerDetails implements service.API
+func (s *Stub) GetServerDetails(r *request.GetServerDetailsRequest) (*upcloud.ServerDetails, error) {
+	return s.GetServerDetailsWithContext(context.Background(), r)
+}
+
+// GetServerDetailsWithContext implements service.API
+func (s *Stub) GetServerDetailsWithContext(ctx context.Context, r *request.GetServerDetailsRequest) (*upcloud.ServerDetails, error) {
+	s.record("GetServerDetails", ctx, r)
+	if resp, ok := s.get("GetServerDetails"); ok {
+		result, _ := resp.result.(*upcloud.ServerDetails)
+		return result, resp.err
+	}
+
+	return &upcloud.ServerDetails{}, nil
+}
+
+// GetServerNetworksReturns sets the response of GetServerNetworks and GetServerNetworksWithContext
+func (s *Stub) GetServerNetworksReturns(result *upcloud.Networking, err error) {
+	s.set("GetServerNetworks", result, err)
+}
+
+// GetServerNetworks implements service.API
+func (s *Stub) GetServerNetworks(r *request.GetServerNetworksRequest) (*upcloud.Networking, error) {
+	return s.GetServerNetworksWithContext(context.Background(), r)
+}
+
+// GetServerNetworksWithContext implements service.API
+func (s *Stub) GetServerNetworksWithContext(ctx context.Context, r *request.GetServerNetworksRequest) (*upcloud.Networking, error) {
+	s.record("GetServerNetworks", ctx, r)
+	if resp, ok := s.get("GetServerNetworks"); ok {
+		result, _ := resp.result.(*upcloud.Networking)
+		return result, resp.err
+	}
+
+	return &upcloud.Networking{}, nil
+}
+
+// GetServersReturns sets the response of GetServers and GetServersWithContext
+func (s *Stub) GetServersReturns(result *upcloud.Servers, err error) {
+	s.set("GetServers", result, err)
+}
+
+// GetServers implements service.API
+func (s *Stub) GetServers() (*upcloud.Servers, error) {
+	return s.GetServersWithContext(context.Background())
+}
+
+// GetServersWithContext implements service.API
+func (s *Stub) GetServersWithContext(ctx context.Context) (*upcloud.Servers, error) {
+	s.record("GetServers", ctx, nil)
+	if resp, ok := s.get("GetServers"); ok {
+		result, _ := resp.result.(*upcloud.Servers)
+		return result, resp.err
+	}
+
+	return &upcloud.Servers{}, nil
+}
+
+// GetStorageDetailsReturns sets the response of GetStorageDetails and GetStorageDetailsWithContext
+func (s *Stub) GetStorageDetailsReturns(result *upcloud.StorageDetails, err error) {
+	s.set("GetStorageDetails", result, err)
+}
+
+// GetStorageDetails implements service.API
+func (s *Stub) GetStorageDetails(r *request.GetStorageDetailsRequest) (*upcloud.StorageDetails, error) {
+	return s.GetStorageDetailsWithContext(context.Background(), r)
+}
+
+// GetStorageDetailsWithContext implements service.API
+func (s *Stub) GetStorageDetailsWithContext(ctx context.Context, r *request.GetStorageDetailsRequest) (*upcloud.StorageDetails, error) {
+	s.record("GetStorageDetails", ctx, r)
+	if resp, ok := s.get("GetStorageDetails"); ok {
+		result, _ := resp.result.(*upcloud.StorageDetails)
+		return result, resp.err
+	}
+
+	return &upcloud.StorageDetails{}, nil
+}
+
+// GetStorageImportDetailsReturns sets the response of GetStorageImportDetails and GetStorageImportDetailsWithContext
+func (s *Stub) GetStorageImportDetailsReturns(result *upcloud.StorageImportDetails, err error) {
+	s.set("GetStorageImportDetails", result, err)
+}
+
+// GetStorageImportDetails implements service.API
+func (s *Stub) GetStorageImportDetails(r *request.GetStorageImportDetailsRequest) (*upcloud.StorageImportDetails, error) {
+	return s.GetStorageImportDetailsWithContext(context.Background(), r)
+}
+
+// GetStorageImportDetailsWithContext implements service.API
+func (s *Stub) GetStorageImportDetailsWithContext(ctx context.Context, r *request.GetStorageImportDetailsRequest) (*upcloud.StorageImportDetails, error) {
+	s.record("GetStorageImportDetails", ctx, r)
+	if resp, ok := s.get("GetStorageImportDetails"); ok {
+		result, _ := resp.result.(*upcloud.StorageImportDetails)
+		return result, resp.err
+	}
+
+	return &upcloud.StorageImportDetails{}, nil
+}
+
+// GetStoragesReturns sets the response of GetStorages and GetStoragesWithContext
+func (s *Stub) GetStoragesReturns(result *upcloud.Storages, err error) {
+	s.set("GetStorages", result, err)
+}
+
+// GetStorages implements service.API
+func (s *Stub) GetStorages(r *request.GetStoragesRequest) (*upcloud.Storages, error) {
+	return s.GetStoragesWithContext(context.Background(), r)
+}
+
+// GetStoragesWithContext implements service.API
+func (s *Stub) GetStoragesWithContext(ctx context.Context, r *request.GetStoragesRequest) (*upcloud.Storages, error) {
+	s.record("GetStorages", ctx, r)
+	if resp, ok := s.get("GetStorages"); ok {
+		result, _ := resp.result.(*upcloud.Storages)
+		return result, resp.err
+	}
+
+	return &upcloud.Storages{}, nil
+}
+
+// GetTagsReturns sets the response of GetTags and GetTagsWithContext
+func (s *Stub) GetTagsReturns(result *upcloud.Tags, err error) {
+	s.set("GetTags", result, err)
+}
+
+// GetTags implements service.API
+func (s *Stub) GetTags() (*upcloud.Tags, error) {
+	return s.GetTagsWithContext(context.Background())
+}
+
+// GetTagsWithContext implements service.API
+func (s *Stub) GetTagsWithContext(ctx context.Context) (*upcloud.Tags, error) {
+	s.record("GetTags", ctx, nil)
+	if resp, ok := s.get("GetTags"); ok {
+		result, _ := resp.result.(*upcloud.Tags)
+		return result, resp.err
+	}
+
+	return &upcloud.Tags{}, nil
+}
+
+// GetTimeZonesReturns sets the response of GetTimeZones and GetTimeZonesWithContext
+func (s *Stub) GetTimeZonesReturns(result *upcloud.TimeZones, err error) {
+	s.set("GetTimeZones", result, err)
+}
+
+// GetTimeZones implements service.API
+func (s *Stub) GetTimeZones() (*upcloud.TimeZones, error) {
+	return s.GetTimeZonesWithContext(context.Background())
+}
+
+// GetTimeZonesWithContext implements service.API
+func (s *Stub) GetTimeZonesWithContext(ctx context.Context) (*upcloud.TimeZones, error) {
+	s.record("GetTimeZones", ctx, nil)
+	if resp, ok := s.get("GetTimeZones"); ok {
+		result, _ := resp.result.(*upcloud.TimeZones)
+		return result, resp.err
+	}
+
+	return &upcloud.TimeZones{}, nil
+}
+
+// GetZonesReturns sets the response of GetZones and GetZonesWithContext
+func (s *Stub) GetZonesReturns(result *upcloud.Zones, err error) {
+	s.set("GetZones", result, err)
+}
+
+// GetZones implements service.API
+func (s *Stub) GetZones() (*upcloud.Zones, error) {
+	return s.GetZonesWithContext(context.Background())
+}
+
+// GetZonesWithContext implements service.API
+func (s *Stub) GetZonesWithContext(ctx context.Context) (*upcloud.Zones, error) {
+	s.record("GetZones", ctx, nil)
+	if resp, ok := s.get("GetZones"); ok {
+		result, _ := resp.result.(*upcloud.Zones)
+		return result, resp.err
+	}
+
+	return &upcloud.Zones{}, nil
+}
+
+// LoadCDROMReturns sets the response of LoadCDROM and LoadCDROMWithContext
+func (s *Stub) LoadCDROMReturns(result *upcloud.ServerDetails, err error) {
+	s.set("LoadCDROM", result, err)
+}
+
+// LoadCDROM implements service.API
+func (s *Stub) LoadCDROM(r *request.LoadCDROMRequest) (*upcloud.ServerDetails, error) {
+	return s.LoadCDROMWithContext(context.Background(), r)
+}
+
+// LoadCDROMWithContext implements service.API
+func (s *Stub) LoadCDROMWithContext(ctx context.Context, r *request.LoadCDROMRequest) (*upcloud.ServerDetails, error) {
+	s.record("LoadCDROM", ctx, r)
+	if resp, ok := s.get("LoadCDROM"); ok {
+		result, _ := resp.result.(*upcloud.ServerDetails)
+		return result, resp.err
+	}
+
+	return &upcloud.ServerDetails{}, nil
+}
+
+// ModifyHostReturns sets the response of ModifyHost and ModifyHostWithContext
+func (s *Stub) ModifyHostReturns(result *upcloud.Host, err error) {
+	s.set("ModifyHost", result, err)
+}
+
+// ModifyHost implements service.API
+func (s *Stub) ModifyHost(r *request.ModifyHostRequest) (*upcloud.Host, error) {
+	return s.ModifyHostWithContext(context.Background(), r)
+}
+
+// ModifyHostWithContext implements service.API
+func (s *Stub) ModifyHostWithContext(ctx context.Context, r *request.ModifyHostRequest) (*upcloud.Host, error) {
+	s.record("ModifyHost", ctx, r)
+	if resp, ok := s.get("ModifyHost"); ok {
+		result, _ := resp.result.(*upcloud.Host)
+		return result, resp.err
+	}
+
+	return &upcloud.Host{}, nil
+}
+
+// ModifyIPAddressReturns sets the response of ModifyIPAddress and ModifyIPAddressWithContext
+func (s *Stub) ModifyIPAddressReturns(result *upcloud.IPAddress, err error) {
+	s.set("ModifyIPAddress", result, err)
+}
+
+// ModifyIPAddress implements service.API
+func (s *Stub) ModifyIPAddress(r *request.ModifyIPAddressRequest) (*upcloud.IPAddress, error) {
+	return s.ModifyIPAddressWithContext(context.Background(), r)
+}
+
+// ModifyIPAddressWithContext implements service.API
+func (s *Stub) ModifyIPAddressWithContext(ctx context.Context, r *request.ModifyIPAddressRequest) (*upcloud.IPAddress, error) {
+	s.record("ModifyIPAddress", ctx, r)
+	if resp, ok := s.get("ModifyIPAddress"); ok {
+		result, _ := resp.result.(*upcloud.IPAddress)
+		return result, resp.err
+	}
+
+	return &upcloud.IPAddress{}, nil
+}
+
+// ModifyNetworkReturns sets the response of ModifyNetwork and ModifyNetworkWithContext
+func (s *Stub) ModifyNetworkReturns(result *upcloud.Network, err error) {
+	s.set("ModifyNetwork", result, err)
+}
+
+// ModifyNetwork implements service.API
+func (s *Stub) ModifyNetwork(r *request.ModifyNetworkRequest) (*upcloud.Network, error) {
+	return s.ModifyNetworkWithContext(context.Background(), r)
+}
+
+// ModifyNetworkWithContext implements service.API
+func (s *Stub) ModifyNetworkWithContext(ctx context.Context, r *request.ModifyNetworkRequest) (*upcloud.Network, error) {
+	s.record("ModifyNetwork", ctx, r)
+	if resp, ok := s.get("ModifyNetwork"); ok {
+		result, _ := resp.result.(*upcloud.Network)
+		return result, resp.err
+	}
+
+	return &upcloud.Network{}, nil
+}
+
+// ModifyNetworkInterfaceReturns sets the response of ModifyNetworkInterface and ModifyNetworkInterfaceWithContext
+func (s *Stub) ModifyNetworkInterfaceReturns(result *upcloud.Interface, err error) {
+	s.set("ModifyNetworkInterface", result, err)
+}
+
+// ModifyNetworkInterface implements service.API
+func (s *Stub) ModifyNetworkInterface(r *request.ModifyNetworkInterfaceRequest) (*upcloud.Interface, error) {
+	return s.ModifyNetworkInterfaceWithContext(context.Background(), r)
+}
+
+// ModifyNetworkInterfaceWithContext implements service.API
+func (s *Stub) ModifyNetworkInterfaceWithContext(ctx context.Context, r *request.ModifyNetworkInterfaceRequest) (*upcloud.Interface, error) {
+	s.record("ModifyNetworkInterface", ctx, r)
+	if resp, ok := s.get("ModifyNetworkInterface"); ok {
+		result, _ := resp.result.(*upcloud.Interface)
+		return result, resp.err
+	}
+
+	return &upcloud.Interface{}, nil
+}
+
+// ModifyObjectStorageReturns sets the response of ModifyObjectStorage and ModifyObjectStorageWithContext
+func (s *Stub) ModifyObjectStorageReturns(result *upcloud.ObjectStorageDetails, err error) {
+	s.set("ModifyObjectStorage", result, err)
+}
+
+// ModifyObjectStorage implements service.API
+func (s *Stub) ModifyObjectStorage(r *request.ModifyObjectStorageRequest) (*upcloud.ObjectStorageDetails, error) {
+	return s.ModifyObjectStorageWithContext(context.Background(), r)
+}
+
+// ModifyObjectStorageWithContext implements service.API
+func (s *Stub) ModifyObjectStorageWithContext(ctx context.Context, r *request.ModifyObjectStorageRequest) (*upcloud.ObjectStorageDetails, error) {
+	s.record("ModifyObjectStorage", ctx, r)
+	if resp, ok := s.get("ModifyObjectStorage"); ok {
+		result, _ := resp.result.(*upcloud.ObjectStorageDetails)
+		return result, resp.err
+	}
+
+	return &upcloud.ObjectStorageDetails{}, nil
+}
+
+// ModifyRouterReturns sets the response of ModifyRouter and ModifyRouterWithContext
+func (s *Stub) ModifyRouterReturns(result *upcloud.Router, err error) {
+	s.set("ModifyRouter", result, err)
+}
+
+// ModifyRouter implements service.API
+func (s *Stub) ModifyRouter(r *request.ModifyRouterRequest) (*upcloud.Router, error) {
+	return s.ModifyRouterWithContext(context.Background(), r)
+}
+
+// ModifyRouterWithContext implements service.API
+func (s *Stub) ModifyRouterWithContext(ctx context.Context, r *request.ModifyRouterRequest) (*upcloud.Router, error) {
+	s.record("ModifyRouter", ctx, r)
+	if resp, ok := s.get("ModifyRouter"); ok {
+		result, _ := resp.result.(*upcloud.Router)
+		return result, resp.err
+	}
+
+	return &upcloud.Router{}, nil
+}
+
+// ModifyServerReturns sets the response of ModifyServer and ModifyServerWithContext
+func (s *Stub) ModifyServerReturns(result *upcloud.ServerDetails, err error) {
+	s.set("ModifyServer", result, err)
+}
+
+// ModifyServer implements service.API
+func (s *Stub) ModifyServer(r *request.ModifyServerRequest) (*upcloud.ServerDetails, error) {
+	return s.ModifyServerWithContext(context.Background(), r)
+}
+
+// ModifyServerWithContext implements service.API
+func (s *Stub) ModifyServerWithContext(ctx context.Context, r *request.ModifyServerRequest) (*upcloud.ServerDetails, error) {
+	s.record("ModifyServer", ctx, r)
+	if resp, ok := s.get("ModifyServer"); ok {
+		result, _ := resp.result.(*upcloud.ServerDetails)
+		return result, resp.err
+	}
+
+	return &upcloud.ServerDetails{}, nil
+}
+
+// ModifyStorageReturns sets the response of ModifyStorage and ModifyStorageWithContext
+func (s *Stub) ModifyStorageReturns(result *upcloud.StorageDetails, err error) {
+	s.set("ModifyStorage", result, err)
+}
+
+// ModifyStorage implements service.API
+func (s *Stub) ModifyStorage(r *request.ModifyStorageRequest) (*upcloud.StorageDetails, error) {
+	return s.ModifyStorageWithContext(context.Background(), r)
+}
+
+// ModifyStorageWithContext implements service.API
+func (s *Stub) ModifyStorageWithContext(ctx context.Context, r *request.ModifyStorageRequest) (*upcloud.StorageDetails, error) {
+	s.record("ModifyStorage", ctx, r)
+	if resp, ok := s.get("ModifyStorage"); ok {
+		result, _ := resp.result.(*upcloud.StorageDetails)
+		return result, resp.err
+	}
+
+	return &upcloud.StorageDetails{}, nil
+}
+
+// ModifyTagReturns sets the response of ModifyTag and ModifyTagWithContext
+func (s *Stub) ModifyTagReturns(result *upcloud.Tag, err error) {
+	s.set("ModifyTag", result, err)
+}
+
+// ModifyTag implements service.API
+func (s *Stub) ModifyTag(r *request.ModifyTagRequest) (*upcloud.Tag, error) {
+	return s.ModifyTagWithContext(context.Background(), r)
+}
+
+// ModifyTagWithContext implements service.API
+func (s *Stub) ModifyTagWithContext(ctx context.Context, r *request.ModifyTagRequest) (*upcloud.Tag, error) {
+	s.record("ModifyTag", ctx, r)
+	if resp, ok := s.get("ModifyTag"); ok {
+		result, _ := resp.result.(*upcloud.Tag)
+		return result, resp.err
+	}
+
+	return &upcloud.Tag{}, nil
+}
+
+// ReleaseIPAddressReturns sets the response of ReleaseIPAddress and ReleaseIPAddressWithContext
+func (s *Stub) ReleaseIPAddressReturns(err error) {
+	s.set("ReleaseIPAddress", nil, err)
+}
+
+// ReleaseIPAddress implements service.API
+func (s *Stub) ReleaseIPAddress(r *request.ReleaseIPAddressRequest) error {
+	return s.ReleaseIPAddressWithContext(context.Background(), r)
+}
+
+// ReleaseIPAddressWithContext implements service.API
+func (s *Stub) ReleaseIPAddressWithContext(ctx context.Context, r *request.ReleaseIPAddressRequest) error {
+	s.record("ReleaseIPAddress", ctx, r)
+	if resp, ok := s.get("ReleaseIPAddress"); ok {
+		return resp.err
+	}
+
+	return nil
+}
+
+// RestartServerReturns sets the response of RestartServer and RestartServerWithContext
+func (s *Stub) RestartServerReturns(result *upcloud.ServerDetails, err error) {
+	s.set("RestartServer", result, err)
+}
+
+// RestartServer implements service.API
+func (s *Stub) RestartServer(r *request.RestartServerRequest) (*upcloud.ServerDetails, error) {
+	return s.RestartServerWithContext(context.Background(), r)
+}
+
+// RestartServerWithContext implements service.API
+func (s *Stub) RestartServerWithContext(ctx context.Context, r *request.RestartServerRequest) (*upcloud.ServerDetails, error) {
+	s.record("RestartServer", ctx, r)
+	if resp, ok := s.get("RestartServer"); ok {
+		result, _ := resp.result.(*upcloud.ServerDetails)
+		return result, resp.err
+	}
+
+	return &upcloud.ServerDetails{}, nil
+}
+
+// RestoreBackupReturns sets the response of RestoreBackup and RestoreBackupWithContext
+func (s *Stub) RestoreBackupReturns(err error) {
+	s.set("RestoreBackup", nil, err)
+}
+
+// RestoreBackup implements service.API
+func (s *Stub) RestoreBackup(r *request.RestoreBackupRequest) error {
+	return s.RestoreBackupWithContext(context.Background(), r)
+}
+
+// RestoreBackupWithContext implements service.API
+func (s *Stub) RestoreBackupWithContext(ctx context.Context, r *request.RestoreBackupRequest) error {
+	s.record("RestoreBackup", ctx, r)
+	if resp, ok := s.get("RestoreBackup"); ok {
+		return resp.err
+	}
+
+	return nil
+}
+
+// StartServerReturns sets the response of StartServer and StartServerWithContext
+func (s *Stub) StartServerReturns(result *upcloud.ServerDetails, err error) {
+	s.set("StartServer", result, err)
+}
+
+// StartServer implements service.API
+func (s *Stub) StartServer(r *request.StartServerRequest) (*upcloud.ServerDetails, error) {
+	return s.StartServerWithContext(context.Background(), r)
+}
+
+// StartServerWithContext implements service.API
+func (s *Stub) StartServerWithContext(ctx context.Context, r *request.StartServerRequest) (*upcloud.ServerDetails, error) {
+	s.record("StartServer", ctx, r)
+	if resp, ok := s.get("StartServer"); ok {
+		result, _ := resp.result.(*upcloud.ServerDetails)
+		return result, resp.err
+	}
+
+	return &upcloud.ServerDetails{}, nil
+}
+
+// StopServerReturns sets the response of StopServer and StopServerWithContext
+func (s *Stub) StopServerReturns(result *upcloud.ServerDetails, err error) {
+	s.set("StopServer", result, err)
+}
+
+// StopServer implements service.API
+func (s *Stub) StopServer(r *request.StopServerRequest) (*upcloud.ServerDetails, error) {
+	return s.StopServerWithContext(context.Background(), r)
+}
+
+// StopServerWithContext implements service.API
+func (s *Stub) StopServerWithContext(ctx context.Context, r *request.StopServerRequest) (*upcloud.ServerDetails, error) {
+	s.record("StopServer", ctx, r)
+	if resp, ok := s.get("StopServer"); ok {
+		result, _ := resp.result.(*upcloud.ServerDetails)
+		return result, resp.err
+	}
+
+	return &upcloud.ServerDetails{}, nil
+}
+
+// SyncFirewallRulesReturns sets the response of SyncFirewallRules and SyncFirewallRulesWithContext
+func (s *Stub) SyncFirewallRulesReturns(result *upcloud.FirewallRuleSync, err error) {
+	s.set("SyncFirewallRules", result, err)
+}
+
+// SyncFirewallRules implements service.API
+func (s *Stub) SyncFirewallRules(r *request.SyncFirewallRulesRequest) (*upcloud.FirewallRuleSync, error) {
+	return s.SyncFirewallRulesWithContext(context.Background(), r)
+}
+
+// SyncFirewallRulesWithContext implements service.API
+func (s *Stub) SyncFirewallRulesWithContext(ctx context.Context, r *request.SyncFirewallRulesRequest) (*upcloud.FirewallRuleSync, error) {
+	s.record("SyncFirewallRules", ctx, r)
+	if resp, ok := s.get("SyncFirewallRules"); ok {
+		result, _ := resp.result.(*upcloud.FirewallRuleSync)
+		return result, resp.err
+	}
+
+	return &upcloud.FirewallRuleSync{}, nil
+}
+
+// TagServerReturns sets the response of TagServer and TagServerWithContext
+func (s *Stub) TagServerReturns(result *upcloud.ServerDetails, err error) {
+	s.set("TagServer", result, err)
+}
+
+// TagServer implements service.API
+func (s *Stub) TagServer(r *request.TagServerRequest) (*upcloud.ServerDetails, error) {
+	return s.TagServerWithContext(context.Background(), r)
+}
+
+// TagServerWithContext implements service.API
+func (s *Stub) TagServerWithContext(ctx context.Context, r *request.TagServerRequest) (*upcloud.ServerDetails, error) {
+	s.record("TagServer", ctx, r)
+	if resp, ok := s.get("TagServer"); ok {
+		result, _ := resp.result.(*upcloud.ServerDetails)
+		return result, resp.err
+	}
+
+	return &upcloud.ServerDetails{}, nil
+}
+
+// TemplatizeStorageReturns sets the response of TemplatizeStorage and TemplatizeStorageWithContext
+func (s *Stub) TemplatizeStorageReturns(result *upcloud.StorageDetails, err error) {
+	s.set("TemplatizeStorage", result, err)
+}
+
+// TemplatizeStorage implements service.API
+func (s *Stub) TemplatizeStorage(r *request.TemplatizeStorageRequest) (*upcloud.StorageDetails, error) {
+	return s.TemplatizeStorageWithContext(context.Background(), r)
+}
+
+// TemplatizeStorageWithContext implements service.API
+func (s *Stub) TemplatizeStorageWithContext(ctx context.Context, r *request.TemplatizeStorageRequest) (*upcloud.StorageDetails, error) {
+	s.record("TemplatizeStorage", ctx, r)
+	if resp, ok := s.get("TemplatizeStorage"); ok {
+		result, _ := resp.result.(*upcloud.StorageDetails)
+		return result, resp.err
+	}
+
+	return &upcloud.StorageDetails{}, nil
+}
+
+// UntagServerReturns sets the response of UntagServer and UntagServerWithContext
+func (s *Stub) UntagServerReturns(result *upcloud.ServerDetails, err error) {
+	s.set("UntagServer", result, err)
+}
+
+// UntagServer implements service.API
+func (s *Stub) UntagServer(r *request.UntagServerRequest) (*upcloud.ServerDetails, error) {
+	return s.UntagServerWithContext(context.Background(), r)
+}
+
+// UntagServerWithContext implements service.API
+func (s *Stub) UntagServerWithContext(ctx context.Context, r *request.UntagServerRequest) (*upcloud.ServerDetails, error) {
+	s.record("UntagServer", ctx, r)
+	if resp, ok := s.get("UntagServer"); ok {
+		result, _ := resp.result.(*upcloud.ServerDetails)
+		return result, resp.err
+	}
+
+	return &upcloud.ServerDetails{}, nil
+}
+
+// WaitReturns sets the response of Wait and WaitWithContext
+func (s *Stub) WaitReturns(result interface{}, err error) {
+	s.set("Wait", result, err)
+}
+
+// Wait implements service.API
+func (s *Stub) Wait(r *service.WaitCondition) (interface{}, error) {
+	return s.WaitWithContext(context.Background(), r)
+}
+
+// WaitWithContext implements service.API
+func (s *Stub) WaitWithContext(ctx context.Context, r *service.WaitCondition) (interface{}, error) {
+	s.record("Wait", ctx, r)
+	if resp, ok := s.get("Wait"); ok {
+		return resp.result, resp.err
+	}
+
+	return nil, nil
+}
+
+// WaitForBackupCreationReturns sets the response of WaitForBackupCreation and WaitForBackupCreationWithContext
+func (s *Stub) WaitForBackupCreationReturns(result *upcloud.StorageDetails, err error) {
+	s.set("WaitForBackupCreation", result, err)
+}
+
+// WaitForBackupCreation implements service.API
+func (s *Stub) WaitForBackupCreation(r *request.WaitForBackupCreationRequest) (*upcloud.StorageDetails, error) {
+	return s.WaitForBackupCreationWithContext(context.Background(), r)
+}
+
+// WaitForBackupCreationWithContext implements service.API
+func (s *Stub) WaitForBackupCreationWithContext(ctx context.Context, r *request.WaitForBackupCreationRequest) (*upcloud.StorageDetails, error) {
+	s.record("WaitForBackupCreation", ctx, r)
+	if resp, ok := s.get("WaitForBackupCreation"); ok {
+		result, _ := resp.result.(*upcloud.StorageDetails)
+		return result, resp.err
+	}
+
+	return &upcloud.StorageDetails{}, nil
+}
+
+// WaitForObjectStorageStateReturns sets the response of WaitForObjectStorageState and WaitForObjectStorageStateWithContext
+func (s *Stub) WaitForObjectStorageStateReturns(result *upcloud.ObjectStorageDetails, err error) {
+	s.set("WaitForObjectStorageState", result, err)
+}
+
+// WaitForObjectStorageState implements service.API
+func (s *Stub) WaitForObjectStorageState(r *request.WaitForObjectStorageStateRequest) (*upcloud.ObjectStorageDetails, error) {
+	return s.WaitForObjectStorageStateWithContext(context.Background(), r)
+}
+
+// WaitForObjectStorageStateWithContext implements service.API
+func (s *Stub) WaitForObjectStorageStateWithContext(ctx context.Context, r *request.WaitForObjectStorageStateRequest) (*upcloud.ObjectStorageDetails, error) {
+	s.record("WaitForObjectStorageState", ctx, r)
+	if resp, ok := s.get("WaitForObjectStorageState"); ok {
+		result, _ := resp.result.(*upcloud.ObjectStorageDetails)
+		return result, resp.err
+	}
+
+	return &upcloud.ObjectStorageDetails{}, nil
+}
+
+// WaitForServerStateReturns sets the response of WaitForServerState and WaitForServerStateWithContext
+func (s *Stub) WaitForServerStateReturns(result *upcloud.ServerDetails, err error) {
+	s.set("WaitForServerState", result, err)
+}
+
+// WaitForServerState implements service.API
+func (s *Stub) WaitForServerState(r *request.WaitForServerStateRequest) (*upcloud.ServerDetails, error) {
+	return s.WaitForServerStateWithContext(context.Background(), r)
+}
+
+// WaitForServerStateWithContext implements service.API
+func (s *Stub) WaitForServerStateWithContext(ctx context.Context, r *request.WaitForServerStateRequest) (*upcloud.ServerDetails, error) {
+	s.record("WaitForServerState", ctx, r)
+	if resp, ok := s.get("WaitForServerState"); ok {
+		result, _ := resp.result.(*upcloud.ServerDetails)
+		return result, resp.err
+	}
+
+	return &upcloud.ServerDetails{}, nil
+}
+
+// WaitForStorageCloneCompletionReturns sets the response of WaitForStorageCloneCompletion and WaitForStorageCloneCompletionWithContext
+func (s *Stub) WaitForStorageCloneCompletionReturns(result *upcloud.StorageDetails, err error) {
+	s.set("WaitForStorageCloneCompletion", result, err)
+}
+
+// WaitForStorageCloneCompletion implements service.API
+func (s *Stub) WaitForStorageCloneCompletion(r *request.WaitForStorageCloneCompletionRequest) (*upcloud.StorageDetails, error) {
+	return s.WaitForStorageCloneCompletionWithContext(context.Background(), r)
+}
+
+// WaitForStorageCloneCompletionWithContext implements service.API
+func (s *Stub) WaitForStorageCloneCompletionWithContext(ctx context.Context, r *request.WaitForStorageCloneCompletionRequest) (*upcloud.StorageDetails, error) {
+	s.record("WaitForStorageCloneCompletion", ctx, r)
+	if resp, ok := s.get("WaitForStorageCloneCompletion"); ok {
+		result, _ := resp.result.(*upcloud.StorageDetails)
+		return result, resp.err
+	}
+
+	return &upcloud.StorageDetails{}, nil
+}
+
+// WaitForStorageImportCompletionReturns sets the response of WaitForStorageImportCompletion and WaitForStorageImportCompletionWithContext
+func (s *Stub) WaitForStorageImportCompletionReturns(result *upcloud.StorageImportDetails, err error) {
+	s.set("WaitForStorageImportCompletion", result, err)
+}
+
+// WaitForStorageImportCompletion implements service.API
+func (s *Stub) WaitForStorageImportCompletion(r *request.WaitForStorageImportCompletionRequest) (*upcloud.StorageImportDetails, error) {
+	return s.WaitForStorageImportCompletionWithContext(context.Background(), r)
+}
+
+// WaitForStorageImportCompletionWithContext implements service.API
+func (s *Stub) WaitForStorageImportCompletionWithContext(ctx context.Context, r *request.WaitForStorageImportCompletionRequest) (*upcloud.StorageImportDetails, error) {
+	s.record("WaitForStorageImportCompletion", ctx, r)
+	if resp, ok := s.get("WaitForStorageImportCompletion"); ok {
+		result, _ := resp.result.(*upcloud.StorageImportDetails)
+		return result, resp.err
+	}
+
+	return &upcloud.StorageImportDetails{}, nil
+}
+
+// WaitForStorageStateReturns sets the response of WaitForStorageState and WaitForStorageStateWithContext
+func (s *Stub) WaitForStorageStateReturns(result *upcloud.StorageDetails, err error) {
+	s.set("WaitForStorageState", result, err)
+}
+
+// WaitForStorageState implements service.API
+func (s *Stub) WaitForStorageState(r *request.WaitForStorageStateRequest) (*upcloud.StorageDetails, error) {
+	return s.WaitForStorageStateWithContext(context.Background(), r)
+}
+
+// WaitForStorageStateWithContext implements service.API
+func (s *Stub) WaitForStorageStateWithContext(ctx context.Context, r *request.WaitForStorageStateRequest) (*upcloud.StorageDetails, error) {
+	s.record("WaitForStorageState", ctx, r)
+	if resp, ok := s.get("WaitForStorageState"); ok {
+		result, _ := resp.result.(*upcloud.StorageDetails)
+		return result, resp.err
+	}
+
+	return &upcloud.StorageDetails{}, nil
+}
@@ -0,0 +1,107 @@
+package mock
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/UpCloudLtd/upcloud-go-api/upcloud"
+	"github.com/UpCloudLtd/upcloud-go-api/upcloud/request"
+	"github.com/UpCloudLtd/upcloud-go-api/upcloud/service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Stops the server with the specified UUID, the way code under test would use the API
+func stopServer(api service.API, uuid string) (*upcloud.ServerDetails, error) {
+	return api.StopServer(&request.StopServerRequest{UUID: uuid, StopType: request.ServerStopTypeSoft})
+}
+
+// TestMock tests that a Mock delegates to its functions and records the calls
+func TestMock(t *testing.T) {
+	m := &Mock{}
+	m.StopServerFunc = func(ctx context.Context, r *request.StopServerRequest) (*upcloud.ServerDetails, error) {
+		return &upcloud.ServerDetails{Server: upcloud.Server{UUID: r.UUID, State: upcloud.ServerStateStopped}}, nil
+	}
+
+	serverDetails, err := stopServer(m, "0077fa3d-32db-4b09-9f5f-30d9e9afb565")
+	require.NoError(t, err)
+	assert.Equal(t, upcloud.ServerStateStopped, serverDetails.State)
+
+	_, err = m.GetAccount()
+	assert.True(t, errors.Is(err, ErrNotMocked))
+	assert.EqualError(t, err, "GetAccount: method not mocked")
+
+	calls := m.Calls()
+	require.Len(t, calls, 2)
+	assert.Equal(t, "StopServer", calls[0].Method)
+	assert.Equal(t, context.Background(), calls[0].Context)
+	assert.Equal(t, &request.StopServerRequest{
+		UUID:     "0077fa3d-32db-4b09-9f5f-30d9e9afb565",
+		StopType: request.ServerStopTypeSoft,
+	}, calls[0].Request)
+	assert.Equal(t, Call{Method: "GetAccount", Context: context.Background()}, calls[1])
+
+	m.Reset()
+	assert.Empty(t, m.Calls())
+}
+
+// TestStub tests that a Stub returns its canned responses, or empty results, and records the calls
+func TestStub(t *testing.T) {
+	type key struct{}
+	ctx := context.WithValue(context.Background(), key{}, "value")
+	s := &Stub{}
+
+	// Without a canned response the result is empty
+	serverDetails, err := s.GetServerDetailsWithContext(ctx, &request.GetServerDetailsRequest{UUID: "first"})
+	require.NoError(t, err)
+	assert.Equal(t, &upcloud.ServerDetails{}, serverDetails)
+
+	s.GetServerDetailsReturns(&upcloud.ServerDetails{Server: upcloud.Server{Title: "canned"}}, nil)
+	serverDetails, err = s.GetServerDetails(&request.GetServerDetailsRequest{UUID: "second"})
+	require.NoError(t, err)
+	assert.Equal(t, "canned", serverDetails.Title)
+
+	s.DeleteServerReturns(upcloud.ErrConflict)
+	err = s.DeleteServer(&request.DeleteServerRequest{UUID: "first"})
+	assert.True(t, errors.Is(err, upcloud.ErrConflict))
+
+	s.GetZonesReturns(nil, upcloud.ErrServerError)
+	zones, err := s.GetZones()
+	assert.Nil(t, zones)
+	assert.True(t, errors.Is(err, upcloud.ErrServerError))
+
+	calls := s.CallsTo("GetServerDetails")
+	require.Len(t, calls, 2)
+	assert.Equal(t, ctx, calls[0].Context)
+	assert.Equal(t, &request.GetServerDetailsRequest{UUID: "first"}, calls[0].Request)
+	assert.Equal(t, &request.GetServerDetailsRequest{UUID: "second"}, calls[1].Request)
+	assert.Len(t, s.Calls(), 4)
+}
+
+// TestGeneratedCodeIsUpToDate tests that mock_gen.go matches the output of the generator
+func TestGeneratedCodeIsUpToDate(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping test that runs the generator in short mode")
+	}
+
+	dir, err := ioutil.TempDir("", "mock")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	output := filepath.Join(dir, "mock_gen.go")
+	out, err := exec.Command("go", "run", "./internal/gen", "-o", output).CombinedOutput()
+	require.NoError(t, err, string(out))
+
+	generated, err := ioutil.ReadFile(output)
+	require.NoError(t, err)
+	current, err := ioutil.ReadFile("mock_gen.go")
+	require.NoError(t, err)
+
+	assert.True(t, bytes.Equal(generated, current), "mock_gen.go is out of date, run go generate")
+}
@@ -1,8 +1,9 @@
 package service
 
 import (
+	"context"
 	"encoding/json"
-	"fmt"
+	"net/http"
 
 	"github.com/UpCloudLtd/upcloud-go-api/upcloud"
 	"github.com/UpCloudLtd/upcloud-go-api/upcloud/request"
@@ -10,31 +11,61 @@ import (
 
 type Network interface {
 	GetNetworks() (*upcloud.Networks, error)
+	GetNetworksWithContext(ctx context.Context) (*upcloud.Networks, error)
 	GetNetworksInZone(r *request.GetNetworksInZoneRequest) (*upcloud.Networks, error)
+	GetNetworksInZoneWithContext(ctx context.Context, r *request.GetNetworksInZoneRequest) (*upcloud.Networks, error)
 	CreateNetwork(r *request.CreateNetworkRequest) (*upcloud.Network, error)
+	CreateNetworkWithContext(ctx context.Context, r *request.CreateNetworkRequest) (*upcloud.Network, error)
 	GetNetworkDetails(r *request.GetNetworkDetailsRequest) (*upcloud.Network, error)
+	GetNetworkDetailsWithContext(ctx context.Context, r *request.GetNetworkDetailsRequest) (*upcloud.Network, error)
 	ModifyNetwork(r *request.ModifyNetworkRequest) (*upcloud.Network, error)
+	ModifyNetworkWithContext(ctx context.Context, r *request.ModifyNetworkRequest) (*upcloud.Network, error)
 	DeleteNetwork(r *request.DeleteNetworkRequest) error
+	DeleteNetworkWithContext(ctx context.Context, r *request.DeleteNetworkRequest) error
 	GetServerNetworks(r *request.GetServerNetworksRequest) (*upcloud.Networking, error)
+	GetServerNetworksWithContext(ctx context.Context, r *request.GetServerNetworksRequest) (*upcloud.Networking, error)
 	CreateNetworkInterface(r *request.CreateNetworkInterfaceRequest) (*upcloud.Interface, error)
+	CreateNetworkInterfaceWithContext(ctx context.Context, r *request.CreateNetworkInterfaceRequest) (*upcloud.Interface, error)
 	ModifyNetworkInterface(r *request.ModifyNetworkInterfaceRequest) (*upcloud.Interface, error)
+	ModifyNetworkInterfaceWithContext(ctx context.Context, r *request.ModifyNetworkInterfaceRequest) (*upcloud.Interface, error)
 	DeleteNetworkInterface(r *request.DeleteNetworkInterfaceRequest) error
+	DeleteNetworkInterfaceWithContext(ctx context.Context, r *request.DeleteNetworkInterfaceRequest) error
 }
 
 var _ Network = (*Service)(nil)
 
+type Router interface {
+	GetRouters() (*upcloud.Routers, error)
+	GetRoutersWithContext(ctx context.Context) (*upcloud.Routers, error)
+	GetRouterDetails(r *request.GetRouterDetailsRequest) (*upcloud.Router, error)
+	GetRouterDetailsWithContext(ctx context.Context, r *request.GetRouterDetailsRequest) (*upcloud.Router, error)
+	CreateRouter(r *request.CreateRouterRequest) (*upcloud.Router, error)
+	CreateRouterWithContext(ctx context.Context, r *request.CreateRouterRequest) (*upcloud.Router, error)
+	ModifyRouter(r *request.ModifyRouterRequest) (*upcloud.Router, error)
+	ModifyRouterWithContext(ctx context.Context, r *request.ModifyRouterRequest) (*upcloud.Router, error)
+	DeleteRouter(r *request.DeleteRouterRequest) error
+	DeleteRouterWithContext(ctx context.Context, r *request.DeleteRouterRequest) error
+}
+
+var _ Router = (*Service)(nil)
+
 // GetNetworks returns the all the available networks
 func (s *Service) GetNetworks() (*upcloud.Networks, error) {
+	return s.GetNetworksWithContext(context.Background())
+}
+
+// GetNetworksWithContext is the same as GetNetworks but binds the API request(s) to the specified context
+func (s *Service) GetNetworksWithContext(ctx context.Context) (*upcloud.Networks, error) {
 	networks := upcloud.Networks{}
-	response, err := s.basicGetRequest("/network")
+	response, err := s.basicGetRequest(ctx, "/network")
 
 	if err != nil {
 		return nil, err
 	}
 
-	err = json.Unmarshal(response, &networks)
+	err = s.unmarshal(response, &networks)
 	if err != nil {
-		return nil, fmt.Errorf("unable to unmarshal JSON: %s, %w", string(response), err)
+		return nil, err
 	}
 
 	return &networks, nil
@@ -42,16 +73,25 @@ func (s *Service) GetNetworks() (*upcloud.Networks, error) {
 
 // GetNetworksInZone returns the all the available networks within the specified zone.
 func (s *Service) GetNetworksInZone(r *request.GetNetworksInZoneRequest) (*upcloud.Networks, error) {
+	return s.GetNetworksInZoneWithContext(context.Background(), r)
+}
+
+// GetNetworksInZoneWithContext is the same as GetNetworksInZone but binds the API request(s) to the specified context
+func (s *Service) GetNetworksInZoneWithContext(ctx context.Context, r *request.GetNetworksInZoneRequest) (*upcloud.Networks, error) {
+	if err := s.validate(r); err != nil {
+		return nil, err
+	}
+
 	networks := upcloud.Networks{}
-	response, err := s.basicGetRequest(r.RequestURL())
+	response, err := s.basicGetRequest(ctx, r.RequestURL())
 
 	if err != nil {
 		return nil, err
 	}
 
-	err = json.Unmarshal(response, &networks)
+	err = s.unmarshal(response, &networks)
 	if err != nil {
-		return nil, fmt.Errorf("unable to unmarshal JSON: %s, %w", string(response), err)
+		return nil, err
 	}
 
 	return &networks, nil
@@ -59,17 +99,20 @@ func (s *Service) GetNetworksInZone(r *request.GetNetworksInZoneRequest) (*upclo
 
 // CreateNetwork creates a new network and returns the network details for the new network.
 func (s *Service) CreateNetwork(r *request.CreateNetworkRequest) (*upcloud.Network, error) {
-	network := upcloud.Network{}
-	requestBody, _ := json.Marshal(r)
-	response, err := s.client.PerformJSONPostRequest(s.client.CreateRequestURL(r.RequestURL()), requestBody)
+	return s.CreateNetworkWithContext(context.Background(), r)
+}
 
-	if err != nil {
-		return nil, parseJSONServiceError(err)
+// CreateNetworkWithContext is the same as CreateNetwork but binds the API request(s) to the specified context
+func (s *Service) CreateNetworkWithContext(ctx context.Context, r *request.CreateNetworkRequest) (*upcloud.Network, error) {
+	if err := s.validate(r); err != nil {
+		return nil, err
 	}
 
-	err = json.Unmarshal(response, &network)
+	network := upcloud.Network{}
+	requestBody, _ := json.Marshal(r)
+	err := s.perform(ctx, http.MethodPost, r, requestBody, &network)
 	if err != nil {
-		return nil, fmt.Errorf("unable to unmarshal JSON: %s, %w", string(response), err)
+		return nil, err
 	}
 
 	return &network, nil
@@ -77,16 +120,25 @@ func (s *Service) CreateNetwork(r *request.CreateNetworkRequest) (*upcloud.Netwo
 
 // GetNetworkDetails returns the details for the specified network.
 func (s *Service) GetNetworkDetails(r *request.GetNetworkDetailsRequest) (*upcloud.Network, error) {
+	return s.GetNetworkDetailsWithContext(context.Background(), r)
+}
+
+// GetNetworkDetailsWithContext is the same as GetNetworkDetails but binds the API request(s) to the specified context
+func (s *Service) GetNetworkDetailsWithContext(ctx context.Context, r *request.GetNetworkDetailsRequest) (*upcloud.Network, error) {
+	if err := s.validate(r); err != nil {
+		return nil, err
+	}
+
 	network := upcloud.Network{}
-	response, err := s.basicGetRequest(r.RequestURL())
+	response, err := s.basicGetRequest(ctx, r.RequestURL())
 
 	if err != nil {
 		return nil, err
 	}
 
-	err = json.Unmarshal(response, &network)
+	err = s.unmarshal(response, &network)
 	if err != nil {
-		return nil, fmt.Errorf("unable to unmarshal JSON: %s, %w", string(response), err)
+		return nil, err
 	}
 
 	return &network, nil
@@ -94,17 +146,20 @@ func (s *Service) GetNetworkDetails(r *request.GetNetworkDetailsRequest) (*upclo
 
 // ModifyNetwork modifies the existing specified network.
 func (s *Service) ModifyNetwork(r *request.ModifyNetworkRequest) (*upcloud.Network, error) {
-	network := upcloud.Network{}
-	requestBody, _ := json.Marshal(r)
-	response, err := s.client.PerformJSONPutRequest(s.client.CreateRequestURL(r.RequestURL()), requestBody)
+	return s.ModifyNetworkWithContext(context.Background(), r)
+}
 
-	if err != nil {
-		return nil, parseJSONServiceError(err)
+// ModifyNetworkWithContext is the same as ModifyNetwork but binds the API request(s) to the specified context
+func (s *Service) ModifyNetworkWithContext(ctx context.Context, r *request.ModifyNetworkRequest) (*upcloud.Network, error) {
+	if err := s.validate(r); err != nil {
+		return nil, err
 	}
 
-	err = json.Unmarshal(response, &network)
+	network := upcloud.Network{}
+	requestBody, _ := json.Marshal(r)
+	err := s.perform(ctx, http.MethodPut, r, requestBody, &network)
 	if err != nil {
-		return nil, fmt.Errorf("unable to unmarshal JSON: %s, %w", string(response), err)
+		return nil, err
 	}
 
 	return &network, nil
@@ -112,27 +167,39 @@ func (s *Service) ModifyNetwork(r *request.ModifyNetworkRequest) (*upcloud.Netwo
 
 // DeleteNetwork deletes the specified network.
 func (s *Service) DeleteNetwork(r *request.DeleteNetworkRequest) error {
-	err := s.client.PerformJSONDeleteRequest(s.client.CreateRequestURL(r.RequestURL()))
+	return s.DeleteNetworkWithContext(context.Background(), r)
+}
 
-	if err != nil {
-		return parseJSONServiceError(err)
+// DeleteNetworkWithContext is the same as DeleteNetwork but binds the API request(s) to the specified context
+func (s *Service) DeleteNetworkWithContext(ctx context.Context, r *request.DeleteNetworkRequest) error {
+	if err := s.validate(r); err != nil {
+		return err
 	}
 
-	return nil
+	return s.perform(ctx, http.MethodDelete, r, nil, nil)
 }
 
 // GetServerNetworks returns all the networks associated with the specified server.
 func (s *Service) GetServerNetworks(r *request.GetServerNetworksRequest) (*upcloud.Networking, error) {
+	return s.GetServerNetworksWithContext(context.Background(), r)
+}
+
+// GetServerNetworksWithContext is the same as GetServerNetworks but binds the API request(s) to the specified context
+func (s *Service) GetServerNetworksWithContext(ctx context.Context, r *request.GetServerNetworksRequest) (*upcloud.Networking, error) {
+	if err := s.validate(r); err != nil {
+		return nil, err
+	}
+
 	networking := upcloud.Networking{}
-	response, err := s.basicGetRequest(r.RequestURL())
+	response, err := s.basicGetRequest(ctx, r.RequestURL())
 
 	if err != nil {
 		return nil, err
 	}
 
-	err = json.Unmarshal(response, &networking)
+	err = s.unmarshal(response, &networking)
 	if err != nil {
-		return nil, fmt.Errorf("unable to unmarshal JSON: %s, %w", string(response), err)
+		return nil, err
 	}
 
 	return &networking, nil
@@ -140,17 +207,20 @@ func (s *Service) GetServerNetworks(r *request.GetServerNetworksRequest) (*upclo
 
 // CreateNetworkInterface creates a new network interface on the specified server.
 func (s *Service) CreateNetworkInterface(r *request.CreateNetworkInterfaceRequest) (*upcloud.Interface, error) {
-	iface := upcloud.Interface{}
-	requestBody, _ := json.Marshal(r)
-	response, err := s.client.PerformJSONPostRequest(s.client.CreateRequestURL(r.RequestURL()), requestBody)
+	return s.CreateNetworkInterfaceWithContext(context.Background(), r)
+}
 
-	if err != nil {
-		return nil, parseJSONServiceError(err)
+// CreateNetworkInterfaceWithContext is the same as CreateNetworkInterface but binds the API request(s) to the specified context
+func (s *Service) CreateNetworkInterfaceWithContext(ctx context.Context, r *request.CreateNetworkInterfaceRequest) (*upcloud.Interface, error) {
+	if err := s.validate(r); err != nil {
+		return nil, err
 	}
 
-	err = json.Unmarshal(response, &iface)
+	iface := upcloud.Interface{}
+	requestBody, _ := json.Marshal(r)
+	err := s.perform(ctx, http.MethodPost, r, requestBody, &iface)
 	if err != nil {
-		return nil, fmt.Errorf("unable to unmarshal JSON: %s, %w", string(response), err)
+		return nil, err
 	}
 
 	return &iface, nil
@@ -158,17 +228,20 @@ func (s *Service) CreateNetworkInterface(r *request.CreateNetworkInterfaceReques
 
 // ModifyNetworkInterface modifies the specified network interface on the specified server.
 func (s *Service) ModifyNetworkInterface(r *request.ModifyNetworkInterfaceRequest) (*upcloud.Interface, error) {
-	iface := upcloud.Interface{}
-	requestBody, _ := json.Marshal(r)
-	response, err := s.client.PerformJSONPutRequest(s.client.CreateRequestURL(r.RequestURL()), requestBody)
+	return s.ModifyNetworkInterfaceWithContext(context.Background(), r)
+}
 
-	if err != nil {
-		return nil, parseJSONServiceError(err)
+// ModifyNetworkInterfaceWithContext is the same as ModifyNetworkInterface but binds the API request(s) to the specified context
+func (s *Service) ModifyNetworkInterfaceWithContext(ctx context.Context, r *request.ModifyNetworkInterfaceRequest) (*upcloud.Interface, error) {
+	if err := s.validate(r); err != nil {
+		return nil, err
 	}
 
-	err = json.Unmarshal(response, &iface)
+	iface := upcloud.Interface{}
+	requestBody, _ := json.Marshal(r)
+	err := s.perform(ctx, http.MethodPut, r, requestBody, &iface)
 	if err != nil {
-		return nil, fmt.Errorf("unable to unmarshal JSON: %s, %w", string(response), err)
+		return nil, err
 	}
 
 	return &iface, nil
@@ -176,27 +249,35 @@ func (s *Service) ModifyNetworkInterface(r *request.ModifyNetworkInterfaceReques
 
 // DeleteNetworkInterface removes the specified network interface from the specified server.
 func (s *Service) DeleteNetworkInterface(r *request.DeleteNetworkInterfaceRequest) error {
-	err := s.client.PerformJSONDeleteRequest(s.client.CreateRequestURL(r.RequestURL()))
+	return s.DeleteNetworkInterfaceWithContext(context.Background(), r)
+}
 
-	if err != nil {
-		return parseJSONServiceError(err)
+// DeleteNetworkInterfaceWithContext is the same as DeleteNetworkInterface but binds the API request(s) to the specified context
+func (s *Service) DeleteNetworkInterfaceWithContext(ctx context.Context, r *request.DeleteNetworkInterfaceRequest) error {
+	if err := s.validate(r); err != nil {
+		return err
 	}
 
-	return nil
+	return s.perform(ctx, http.MethodDelete, r, nil, nil)
 }
 
 // GetRouters returns the all the available routers
 func (s *Service) GetRouters() (*upcloud.Routers, error) {
+	return s.GetRoutersWithContext(context.Background())
+}
+
+// GetRoutersWithContext is the same as GetRouters but binds the API request(s) to the specified context
+func (s *Service) GetRoutersWithContext(ctx context.Context) (*upcloud.Routers, error) {
 	routers := upcloud.Routers{}
-	response, err := s.basicGetRequest("/router")
+	response, err := s.basicGetRequest(ctx, "/router")
 
 	if err != nil {
 		return nil, err
 	}
 
-	err = json.Unmarshal(response, &routers)
+	err = s.unmarshal(response, &routers)
 	if err != nil {
-		return nil, fmt.Errorf("unable to unmarshal JSON: %s, %w", string(response), err)
+		return nil, err
 	}
 
 	return &routers, nil
@@ -204,16 +285,25 @@ func (s *Service) GetRouters() (*upcloud.Routers, error) {
 
 // GetRouterDetails returns the details for the specified router.
 func (s *Service) GetRouterDetails(r *request.GetRouterDetailsRequest) (*upcloud.Router, error) {
+	return s.GetRouterDetailsWithContext(context.Background(), r)
+}
+
+// GetRouterDetailsWithContext is the same as GetRouterDetails but binds the API request(s) to the specified context
+func (s *Service) GetRouterDetailsWithContext(ctx context.Context, r *request.GetRouterDetailsRequest) (*upcloud.Router, error) {
+	if err := s.validate(r); err != nil {
+		return nil, err
+	}
+
 	router := upcloud.Router{}
-	response, err := s.basicGetRequest(r.RequestURL())
+	response, err := s.basicGetRequest(ctx, r.RequestURL())
 
 	if err != nil {
 		return nil, err
 	}
 
-	err = json.Unmarshal(response, &router)
+	err = s.unmarshal(response, &router)
 	if err != nil {
-		return nil, fmt.Errorf("unable to unmarshal JSON: %s, %w", string(response), err)
+		return nil, err
 	}
 
 	return &router, nil
@@ -221,17 +311,20 @@ func (s *Service) GetRouterDetails(r *request.GetRouterDetailsRequest) (*upcloud
 
 // CreateRouter creates a new router.
 func (s *Service) CreateRouter(r *request.CreateRouterRequest) (*upcloud.Router, error) {
-	router := upcloud.Router{}
-	requestBody, _ := json.Marshal(r)
-	response, err := s.client.PerformJSONPostRequest(s.client.CreateRequestURL(r.RequestURL()), requestBody)
+	return s.CreateRouterWithContext(context.Background(), r)
+}
 
-	if err != nil {
-		return nil, parseJSONServiceError(err)
+// CreateRouterWithContext is the same as CreateRouter but binds the API request(s) to the specified context
+func (s *Service) CreateRouterWithContext(ctx context.Context, r *request.CreateRouterRequest) (*upcloud.Router, error) {
+	if err := s.validate(r); err != nil {
+		return nil, err
 	}
 
-	err = json.Unmarshal(response, &router)
+	router := upcloud.Router{}
+	requestBody, _ := json.Marshal(r)
+	err := s.perform(ctx, http.MethodPost, r, requestBody, &router)
 	if err != nil {
-		return nil, fmt.Errorf("unable to unmarshal JSON: %s, %w", string(response), err)
+		return nil, err
 	}
 
 	return &router, nil
@@ -239,17 +332,20 @@ func (s *Service) CreateRouter(r *request.CreateRouterRequest) (*upcloud.Router,
 
 // ModifyRouter modifies the configuration of the specified existing router.
 func (s *Service) ModifyRouter(r *request.ModifyRouterRequest) (*upcloud.Router, error) {
-	router := upcloud.Router{}
-	requestBody, _ := json.Marshal(r)
-	response, err := s.client.PerformJSONPatchRequest(s.client.CreateRequestURL(r.RequestURL()), requestBody)
+	return s.ModifyRouterWithContext(context.Background(), r)
+}
 
-	if err != nil {
-		return nil, parseJSONServiceError(err)
+// ModifyRouterWithContext is the same as ModifyRouter but binds the API request(s) to the specified context
+func (s *Service) ModifyRouterWithContext(ctx context.Context, r *request.ModifyRouterRequest) (*upcloud.Router, error) {
+	if err := s.validate(r); err != nil {
+		return nil, err
 	}
 
-	err = json.Unmarshal(response, &router)
+	router := upcloud.Router{}
+	requestBody, _ := json.Marshal(r)
+	err := s.perform(ctx, http.MethodPatch, r, requestBody, &router)
 	if err != nil {
-		return nil, fmt.Errorf("unable to unmarshal JSON: %s, %w", string(response), err)
+		return nil, err
 	}
 
 	return &router, nil
@@ -257,11 +353,14 @@ func (s *Service) ModifyRouter(r *request.ModifyRouterRequest) (*upcloud.Router,
 
 // DeleteRouter deletes the specified router.
 func (s *Service) DeleteRouter(r *request.DeleteRouterRequest) error {
-	err := s.client.PerformJSONDeleteRequest(s.client.CreateRequestURL(r.RequestURL()))
+	return s.DeleteRouterWithContext(context.Background(), r)
+}
 
-	if err != nil {
-		return parseJSONServiceError(err)
+// DeleteRouterWithContext is the same as DeleteRouter but binds the API request(s) to the specified context
+func (s *Service) DeleteRouterWithContext(ctx context.Context, r *request.DeleteRouterRequest) error {
+	if err := s.validate(r); err != nil {
+		return err
 	}
 
-	return nil
+	return s.perform(ctx, http.MethodDelete, r, nil, nil)
 }
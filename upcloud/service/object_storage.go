@@ -1,8 +1,10 @@
 package service
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
+	"net/http"
 
 	"github.com/UpCloudLtd/upcloud-go-api/upcloud"
 	"github.com/UpCloudLtd/upcloud-go-api/upcloud/request"
@@ -10,26 +12,38 @@ import (
 
 type ObjectStorage interface {
 	GetObjectStorages() (*upcloud.ObjectStorages, error)
+	GetObjectStoragesWithContext(ctx context.Context) (*upcloud.ObjectStorages, error)
 	GetObjectStorageDetails(r *request.GetObjectStorageDetailsRequest) (*upcloud.ObjectStorageDetails, error)
+	GetObjectStorageDetailsWithContext(ctx context.Context, r *request.GetObjectStorageDetailsRequest) (*upcloud.ObjectStorageDetails, error)
 	CreateObjectStorage(r *request.CreateObjectStorageRequest) (*upcloud.ObjectStorageDetails, error)
+	CreateObjectStorageWithContext(ctx context.Context, r *request.CreateObjectStorageRequest) (*upcloud.ObjectStorageDetails, error)
 	ModifyObjectStorage(r *request.ModifyObjectStorageRequest) (*upcloud.ObjectStorageDetails, error)
+	ModifyObjectStorageWithContext(ctx context.Context, r *request.ModifyObjectStorageRequest) (*upcloud.ObjectStorageDetails, error)
 	DeleteObjectStorage(r *request.DeleteObjectStorageRequest) error
+	DeleteObjectStorageWithContext(ctx context.Context, r *request.DeleteObjectStorageRequest) error
+	WaitForObjectStorageState(r *request.WaitForObjectStorageStateRequest) (*upcloud.ObjectStorageDetails, error)
+	WaitForObjectStorageStateWithContext(ctx context.Context, r *request.WaitForObjectStorageStateRequest) (*upcloud.ObjectStorageDetails, error)
 }
 
 var _ ObjectStorage = (*Service)(nil)
 
 // GetObjectStorages returns the available objects storages
 func (s *Service) GetObjectStorages() (*upcloud.ObjectStorages, error) {
+	return s.GetObjectStoragesWithContext(context.Background())
+}
+
+// GetObjectStoragesWithContext is the same as GetObjectStorages but binds the API request(s) to the specified context
+func (s *Service) GetObjectStoragesWithContext(ctx context.Context) (*upcloud.ObjectStorages, error) {
 	objectStorages := upcloud.ObjectStorages{}
-	response, err := s.basicGetRequest("/object-storage")
+	response, err := s.basicGetRequest(ctx, "/object-storage")
 
 	if err != nil {
 		return nil, err
 	}
 
-	err = json.Unmarshal(response, &objectStorages)
+	err = s.unmarshal(response, &objectStorages)
 	if err != nil {
-		return nil, fmt.Errorf("unable to unmarshal JSON: %s, %w", string(response), err)
+		return nil, err
 	}
 
 	return &objectStorages, nil
@@ -37,16 +51,25 @@ func (s *Service) GetObjectStorages() (*upcloud.ObjectStorages, error) {
 
 // GetObjectStorageDetails returns extended details about the specified Object Storage
 func (s *Service) GetObjectStorageDetails(r *request.GetObjectStorageDetailsRequest) (*upcloud.ObjectStorageDetails, error) {
+	return s.GetObjectStorageDetailsWithContext(context.Background(), r)
+}
+
+// GetObjectStorageDetailsWithContext is the same as GetObjectStorageDetails but binds the API request(s) to the specified context
+func (s *Service) GetObjectStorageDetailsWithContext(ctx context.Context, r *request.GetObjectStorageDetailsRequest) (*upcloud.ObjectStorageDetails, error) {
+	if err := s.validate(r); err != nil {
+		return nil, err
+	}
+
 	objectStorageDetails := upcloud.ObjectStorageDetails{}
-	response, err := s.basicGetRequest(r.RequestURL())
+	response, err := s.basicGetRequest(ctx, r.RequestURL())
 
 	if err != nil {
 		return nil, err
 	}
 
-	err = json.Unmarshal(response, &objectStorageDetails)
+	err = s.unmarshal(response, &objectStorageDetails)
 	if err != nil {
-		return nil, fmt.Errorf("unable to unmarshal JSON: %s, %w", string(response), err)
+		return nil, err
 	}
 
 	return &objectStorageDetails, nil
@@ -54,17 +77,20 @@ func (s *Service) GetObjectStorageDetails(r *request.GetObjectStorageDetailsRequ
 
 // CreateObjectStorage creates a Object Storage and return the Object Storage details for the newly created device
 func (s *Service) CreateObjectStorage(r *request.CreateObjectStorageRequest) (*upcloud.ObjectStorageDetails, error) {
-	objectStorageDetails := upcloud.ObjectStorageDetails{}
-	requestBody, _ := json.Marshal(r)
-	response, err := s.client.PerformJSONPostRequest(s.client.CreateRequestURL(r.RequestURL()), requestBody)
+	return s.CreateObjectStorageWithContext(context.Background(), r)
+}
 
-	if err != nil {
-		return nil, parseJSONServiceError(err)
+// CreateObjectStorageWithContext is the same as CreateObjectStorage but binds the API request(s) to the specified context
+func (s *Service) CreateObjectStorageWithContext(ctx context.Context, r *request.CreateObjectStorageRequest) (*upcloud.ObjectStorageDetails, error) {
+	if err := s.validate(r); err != nil {
+		return nil, err
 	}
 
-	err = json.Unmarshal(response, &objectStorageDetails)
+	objectStorageDetails := upcloud.ObjectStorageDetails{}
+	requestBody, _ := json.Marshal(r)
+	err := s.perform(ctx, http.MethodPost, r, requestBody, &objectStorageDetails)
 	if err != nil {
-		return nil, fmt.Errorf("unable to unmarshal JSON: %s, %w", string(response), err)
+		return nil, err
 	}
 
 	return &objectStorageDetails, nil
@@ -72,17 +98,20 @@ func (s *Service) CreateObjectStorage(r *request.CreateObjectStorageRequest) (*u
 
 // ModifyObjectStorage modifies the configuration of an existing Object Storage
 func (s *Service) ModifyObjectStorage(r *request.ModifyObjectStorageRequest) (*upcloud.ObjectStorageDetails, error) {
-	objectStorageDetails := upcloud.ObjectStorageDetails{}
-	requestBody, _ := json.Marshal(r)
-	response, err := s.client.PerformJSONPatchRequest(s.client.CreateRequestURL(r.RequestURL()), requestBody)
+	return s.ModifyObjectStorageWithContext(context.Background(), r)
+}
 
-	if err != nil {
-		return nil, parseJSONServiceError(err)
+// ModifyObjectStorageWithContext is the same as ModifyObjectStorage but binds the API request(s) to the specified context
+func (s *Service) ModifyObjectStorageWithContext(ctx context.Context, r *request.ModifyObjectStorageRequest) (*upcloud.ObjectStorageDetails, error) {
+	if err := s.validate(r); err != nil {
+		return nil, err
 	}
 
-	json.Unmarshal(response, &objectStorageDetails)
+	objectStorageDetails := upcloud.ObjectStorageDetails{}
+	requestBody, _ := json.Marshal(r)
+	err := s.perform(ctx, http.MethodPatch, r, requestBody, &objectStorageDetails)
 	if err != nil {
-		return nil, fmt.Errorf("unable to unmarshal JSON: %s, %w", string(response), err)
+		return nil, err
 	}
 
 	return &objectStorageDetails, nil
@@ -90,11 +119,51 @@ func (s *Service) ModifyObjectStorage(r *request.ModifyObjectStorageRequest) (*u
 
 // DeleteObjectStorage deletes the specific Object Storage
 func (s *Service) DeleteObjectStorage(r *request.DeleteObjectStorageRequest) error {
-	err := s.client.PerformJSONDeleteRequest(s.client.CreateRequestURL(r.RequestURL()))
+	return s.DeleteObjectStorageWithContext(context.Background(), r)
+}
 
-	if err != nil {
-		return parseJSONServiceError(err)
+// DeleteObjectStorageWithContext is the same as DeleteObjectStorage but binds the API request(s) to the specified context
+func (s *Service) DeleteObjectStorageWithContext(ctx context.Context, r *request.DeleteObjectStorageRequest) error {
+	if err := s.validate(r); err != nil {
+		return err
+	}
+
+	return s.perform(ctx, http.MethodDelete, r, nil, nil)
+}
+
+// WaitForObjectStorageState blocks execution until the specified Object Storage has entered the specified state. If
+// the state changes favorably, the new Object Storage details are returned. The method will give up after the
+// specified timeout
+func (s *Service) WaitForObjectStorageState(r *request.WaitForObjectStorageStateRequest) (*upcloud.ObjectStorageDetails, error) {
+	return s.WaitForObjectStorageStateWithContext(context.Background(), r)
+}
+
+// WaitForObjectStorageStateWithContext is the same as WaitForObjectStorageState but binds the API request(s) to the specified context
+func (s *Service) WaitForObjectStorageStateWithContext(ctx context.Context, r *request.WaitForObjectStorageStateRequest) (*upcloud.ObjectStorageDetails, error) {
+	if err := s.validate(r); err != nil {
+		return nil, err
 	}
 
-	return nil
+	value, err := s.WaitWithContext(ctx, &WaitCondition{
+		Resource: "object storage " + r.UUID,
+		Target:   fmt.Sprintf("enter state \"%s\"", r.DesiredState),
+		Timeout:  r.Timeout,
+		Poll: func(ctx context.Context) (string, interface{}, error) {
+			objectStorageDetails, err := s.GetObjectStorageDetailsWithContext(ctx, &request.GetObjectStorageDetailsRequest{
+				UUID: r.UUID,
+			})
+			if err != nil {
+				return "", nil, err
+			}
+
+			return objectStorageDetails.State, objectStorageDetails, nil
+		},
+		Done: func(state string) bool {
+			return state == r.DesiredState
+		},
+		DryRunValue: &upcloud.ObjectStorageDetails{ObjectStorage: upcloud.ObjectStorage{UUID: r.UUID, State: r.DesiredState}},
+	})
+
+	objectStorageDetails, _ := value.(*upcloud.ObjectStorageDetails)
+	return objectStorageDetails, err
 }
@@ -1,8 +1,13 @@
 package service
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"net/http"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/UpCloudLtd/upcloud-go-api/upcloud"
@@ -12,54 +17,152 @@ import (
 
 type Firewall interface {
 	GetFirewallRules(r *request.GetFirewallRulesRequest) (*upcloud.FirewallRules, error)
+	GetFirewallRulesWithContext(ctx context.Context, r *request.GetFirewallRulesRequest) (*upcloud.FirewallRules, error)
 	GetFirewallRuleDetails(r *request.GetFirewallRuleDetailsRequest) (*upcloud.FirewallRule, error)
+	GetFirewallRuleDetailsWithContext(ctx context.Context, r *request.GetFirewallRuleDetailsRequest) (*upcloud.FirewallRule, error)
 	CreateFirewallRule(r *request.CreateFirewallRuleRequest) (*upcloud.FirewallRule, error)
+	CreateFirewallRuleWithContext(ctx context.Context, r *request.CreateFirewallRuleRequest) (*upcloud.FirewallRule, error)
 	CreateFirewallRules(r *request.CreateFirewallRulesRequest) error
+	CreateFirewallRulesWithContext(ctx context.Context, r *request.CreateFirewallRulesRequest) error
 	DeleteFirewallRule(r *request.DeleteFirewallRuleRequest) error
+	DeleteFirewallRuleWithContext(ctx context.Context, r *request.DeleteFirewallRuleRequest) error
+	SyncFirewallRules(r *request.SyncFirewallRulesRequest) (*upcloud.FirewallRuleSync, error)
+	SyncFirewallRulesWithContext(ctx context.Context, r *request.SyncFirewallRulesRequest) (*upcloud.FirewallRuleSync, error)
 }
 
 var _ Firewall = (*Service)(nil)
 
 type IpAddress interface {
 	GetIPAddresses() (*upcloud.IPAddresses, error)
+	GetIPAddressesWithContext(ctx context.Context) (*upcloud.IPAddresses, error)
 	GetIPAddressDetails(r *request.GetIPAddressDetailsRequest) (*upcloud.IPAddress, error)
+	GetIPAddressDetailsWithContext(ctx context.Context, r *request.GetIPAddressDetailsRequest) (*upcloud.IPAddress, error)
 	AssignIPAddress(r *request.AssignIPAddressRequest) (*upcloud.IPAddress, error)
+	AssignIPAddressWithContext(ctx context.Context, r *request.AssignIPAddressRequest) (*upcloud.IPAddress, error)
 	ModifyIPAddress(r *request.ModifyIPAddressRequest) (*upcloud.IPAddress, error)
+	ModifyIPAddressWithContext(ctx context.Context, r *request.ModifyIPAddressRequest) (*upcloud.IPAddress, error)
 	ReleaseIPAddress(r *request.ReleaseIPAddressRequest) error
+	ReleaseIPAddressWithContext(ctx context.Context, r *request.ReleaseIPAddressRequest) error
 }
 
 var _ IpAddress = (*Service)(nil)
 
 type Server interface {
 	GetServerConfigurations() (*upcloud.ServerConfigurations, error)
+	GetServerConfigurationsWithContext(ctx context.Context) (*upcloud.ServerConfigurations, error)
 	GetServers() (*upcloud.Servers, error)
+	GetServersWithContext(ctx context.Context) (*upcloud.Servers, error)
 	GetServerDetails(r *request.GetServerDetailsRequest) (*upcloud.ServerDetails, error)
+	GetServerDetailsWithContext(ctx context.Context, r *request.GetServerDetailsRequest) (*upcloud.ServerDetails, error)
 	CreateServer(r *request.CreateServerRequest) (*upcloud.ServerDetails, error)
+	CreateServerWithContext(ctx context.Context, r *request.CreateServerRequest) (*upcloud.ServerDetails, error)
 	WaitForServerState(r *request.WaitForServerStateRequest) (*upcloud.ServerDetails, error)
+	WaitForServerStateWithContext(ctx context.Context, r *request.WaitForServerStateRequest) (*upcloud.ServerDetails, error)
 	StartServer(r *request.StartServerRequest) (*upcloud.ServerDetails, error)
+	StartServerWithContext(ctx context.Context, r *request.StartServerRequest) (*upcloud.ServerDetails, error)
 	StopServer(r *request.StopServerRequest) (*upcloud.ServerDetails, error)
+	StopServerWithContext(ctx context.Context, r *request.StopServerRequest) (*upcloud.ServerDetails, error)
 	RestartServer(r *request.RestartServerRequest) (*upcloud.ServerDetails, error)
+	RestartServerWithContext(ctx context.Context, r *request.RestartServerRequest) (*upcloud.ServerDetails, error)
 	ModifyServer(r *request.ModifyServerRequest) (*upcloud.ServerDetails, error)
+	ModifyServerWithContext(ctx context.Context, r *request.ModifyServerRequest) (*upcloud.ServerDetails, error)
 	DeleteServer(r *request.DeleteServerRequest) error
+	DeleteServerWithContext(ctx context.Context, r *request.DeleteServerRequest) error
 	DeleteServerAndStorages(r *request.DeleteServerAndStoragesRequest) error
+	DeleteServerAndStoragesWithContext(ctx context.Context, r *request.DeleteServerAndStoragesRequest) error
 }
 
 var _ Server = (*Service)(nil)
 
 type Tag interface {
 	GetTags() (*upcloud.Tags, error)
+	GetTagsWithContext(ctx context.Context) (*upcloud.Tags, error)
 	CreateTag(r *request.CreateTagRequest) (*upcloud.Tag, error)
+	CreateTagWithContext(ctx context.Context, r *request.CreateTagRequest) (*upcloud.Tag, error)
 	ModifyTag(r *request.ModifyTagRequest) (*upcloud.Tag, error)
+	ModifyTagWithContext(ctx context.Context, r *request.ModifyTagRequest) (*upcloud.Tag, error)
 	DeleteTag(r *request.DeleteTagRequest) error
+	DeleteTagWithContext(ctx context.Context, r *request.DeleteTagRequest) error
 	TagServer(r *request.TagServerRequest) (*upcloud.ServerDetails, error)
+	TagServerWithContext(ctx context.Context, r *request.TagServerRequest) (*upcloud.ServerDetails, error)
 	UntagServer(r *request.UntagServerRequest) (*upcloud.ServerDetails, error)
+	UntagServerWithContext(ctx context.Context, r *request.UntagServerRequest) (*upcloud.ServerDetails, error)
 }
 
 var _ Tag = (*Service)(nil)
 
+type Account interface {
+	GetAccount() (*upcloud.Account, error)
+	GetAccountWithContext(ctx context.Context) (*upcloud.Account, error)
+}
+
+var _ Account = (*Service)(nil)
+
+type Zone interface {
+	GetZones() (*upcloud.Zones, error)
+	GetZonesWithContext(ctx context.Context) (*upcloud.Zones, error)
+}
+
+var _ Zone = (*Service)(nil)
+
+type Plan interface {
+	GetPlans() (*upcloud.Plans, error)
+	GetPlansWithContext(ctx context.Context) (*upcloud.Plans, error)
+}
+
+var _ Plan = (*Service)(nil)
+
+type Price interface {
+	GetPriceZones() (*upcloud.PriceZones, error)
+	GetPriceZonesWithContext(ctx context.Context) (*upcloud.PriceZones, error)
+}
+
+var _ Price = (*Service)(nil)
+
+type TimeZone interface {
+	GetTimeZones() (*upcloud.TimeZones, error)
+	GetTimeZonesWithContext(ctx context.Context) (*upcloud.TimeZones, error)
+}
+
+var _ TimeZone = (*Service)(nil)
+
+type Waiter interface {
+	Wait(c *WaitCondition) (interface{}, error)
+	WaitWithContext(ctx context.Context, c *WaitCondition) (interface{}, error)
+}
+
+var _ Waiter = (*Service)(nil)
+
+// API is the complete set of operations offered by Service. Code that depends on API rather than on *Service can be
+// tested with the implementations in the mock package.
+type API interface {
+	Account
+	Zone
+	Plan
+	Price
+	TimeZone
+	Server
+	Storage
+	IpAddress
+	Firewall
+	Tag
+	Network
+	Router
+	Host
+	ObjectStorage
+	Waiter
+}
+
+var _ API = (*Service)(nil)
+
 // Service represents the API service. The specified client is used to communicate with the API
 type Service struct {
 	client *client.Client
+
+	mu               sync.RWMutex
+	schemaCheck      *SchemaCheck
+	validateRequests bool
+	waitPolicy       *WaitPolicy
 }
 
 // New constructs and returns a new service object configured with the specified client
@@ -70,16 +173,44 @@ func New(client *client.Client) *Service {
 	return &service
 }
 
+// SetRequestValidation controls whether requests are validated with their Validate method before they are sent.
+// An invalid request then fails with a *request.ValidationError without contacting the API. Validation is disabled
+// by default.
+func (s *Service) SetRequestValidation(enabled bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.validateRequests = enabled
+}
+
+// Validates the specified request if request validation is enabled
+func (s *Service) validate(r request.Validator) error {
+	s.mu.RLock()
+	enabled := s.validateRequests
+	s.mu.RUnlock()
+
+	if !enabled {
+		return nil
+	}
+
+	return r.Validate()
+}
+
 // GetAccount returns the current user's account
 func (s *Service) GetAccount() (*upcloud.Account, error) {
+	return s.GetAccountWithContext(context.Background())
+}
+
+// GetAccountWithContext is the same as GetAccount but binds the API request(s) to the specified context
+func (s *Service) GetAccountWithContext(ctx context.Context) (*upcloud.Account, error) {
 	account := upcloud.Account{}
-	response, err := s.basicGetRequest("/account")
+	response, err := s.basicGetRequest(ctx, "/account")
 
 	if err != nil {
 		return nil, err
 	}
 
-	err = json.Unmarshal(response, &account)
+	err = s.unmarshal(response, &account)
 	if err != nil {
 		return nil, err
 	}
@@ -89,86 +220,131 @@ func (s *Service) GetAccount() (*upcloud.Account, error) {
 
 // GetZones returns the available zones
 func (s *Service) GetZones() (*upcloud.Zones, error) {
+	return s.GetZonesWithContext(context.Background())
+}
+
+// GetZonesWithContext is the same as GetZones but binds the API request(s) to the specified context
+func (s *Service) GetZonesWithContext(ctx context.Context) (*upcloud.Zones, error) {
 	zones := upcloud.Zones{}
-	response, err := s.basicGetRequest("/zone")
+	response, err := s.basicGetRequest(ctx, "/zone")
 
 	if err != nil {
 		return nil, err
 	}
 
-	json.Unmarshal(response, &zones)
+	err = s.unmarshal(response, &zones)
+	if err != nil {
+		return nil, err
+	}
 
 	return &zones, nil
 }
 
 // GetPriceZones returns the available price zones and their corresponding prices
 func (s *Service) GetPriceZones() (*upcloud.PriceZones, error) {
+	return s.GetPriceZonesWithContext(context.Background())
+}
+
+// GetPriceZonesWithContext is the same as GetPriceZones but binds the API request(s) to the specified context
+func (s *Service) GetPriceZonesWithContext(ctx context.Context) (*upcloud.PriceZones, error) {
 	zones := upcloud.PriceZones{}
-	response, err := s.basicGetRequest("/price")
+	response, err := s.basicGetRequest(ctx, "/price")
 
 	if err != nil {
 		return nil, err
 	}
 
-	json.Unmarshal(response, &zones)
+	err = s.unmarshal(response, &zones)
+	if err != nil {
+		return nil, err
+	}
 
 	return &zones, nil
 }
 
 // GetTimeZones returns the available timezones
 func (s *Service) GetTimeZones() (*upcloud.TimeZones, error) {
+	return s.GetTimeZonesWithContext(context.Background())
+}
+
+// GetTimeZonesWithContext is the same as GetTimeZones but binds the API request(s) to the specified context
+func (s *Service) GetTimeZonesWithContext(ctx context.Context) (*upcloud.TimeZones, error) {
 	zones := upcloud.TimeZones{}
-	response, err := s.basicGetRequest("/timezone")
+	response, err := s.basicGetRequest(ctx, "/timezone")
 
 	if err != nil {
 		return nil, err
 	}
 
-	json.Unmarshal(response, &zones)
+	err = s.unmarshal(response, &zones)
+	if err != nil {
+		return nil, err
+	}
 
 	return &zones, nil
 }
 
 // GetPlans returns the available service plans
 func (s *Service) GetPlans() (*upcloud.Plans, error) {
+	return s.GetPlansWithContext(context.Background())
+}
+
+// GetPlansWithContext is the same as GetPlans but binds the API request(s) to the specified context
+func (s *Service) GetPlansWithContext(ctx context.Context) (*upcloud.Plans, error) {
 	plans := upcloud.Plans{}
-	response, err := s.basicGetRequest("/plan")
+	response, err := s.basicGetRequest(ctx, "/plan")
 
 	if err != nil {
 		return nil, err
 	}
 
-	json.Unmarshal(response, &plans)
+	err = s.unmarshal(response, &plans)
+	if err != nil {
+		return nil, err
+	}
 
 	return &plans, nil
 }
 
 // GetServerConfigurations returns the available pre-configured server configurations
 func (s *Service) GetServerConfigurations() (*upcloud.ServerConfigurations, error) {
+	return s.GetServerConfigurationsWithContext(context.Background())
+}
+
+// GetServerConfigurationsWithContext is the same as GetServerConfigurations but binds the API request(s) to the specified context
+func (s *Service) GetServerConfigurationsWithContext(ctx context.Context) (*upcloud.ServerConfigurations, error) {
 	serverConfigurations := upcloud.ServerConfigurations{}
-	response, err := s.basicGetRequest("/server_size")
+	response, err := s.basicGetRequest(ctx, "/server_size")
 
 	if err != nil {
 		return nil, err
 	}
 
-	json.Unmarshal(response, &serverConfigurations)
+	err = s.unmarshal(response, &serverConfigurations)
+	if err != nil {
+		return nil, err
+	}
 
 	return &serverConfigurations, nil
 }
 
 // GetServers returns the available servers
 func (s *Service) GetServers() (*upcloud.Servers, error) {
+	return s.GetServersWithContext(context.Background())
+}
+
+// GetServersWithContext is the same as GetServers but binds the API request(s) to the specified context
+func (s *Service) GetServersWithContext(ctx context.Context) (*upcloud.Servers, error) {
 	servers := upcloud.Servers{}
-	response, err := s.basicGetRequest("/server")
+	response, err := s.basicGetRequest(ctx, "/server")
 
 	if err != nil {
 		return nil, err
 	}
 
-	err = json.Unmarshal(response, &servers)
+	err = s.unmarshal(response, &servers)
 	if err != nil {
-		return nil, fmt.Errorf("unable to unmarshal JSON: %s, %w", string(response), err)
+		return nil, err
 	}
 
 	return &servers, nil
@@ -176,16 +352,25 @@ func (s *Service) GetServers() (*upcloud.Servers, error) {
 
 // GetServerDetails returns extended details about the specified server
 func (s *Service) GetServerDetails(r *request.GetServerDetailsRequest) (*upcloud.ServerDetails, error) {
+	return s.GetServerDetailsWithContext(context.Background(), r)
+}
+
+// GetServerDetailsWithContext is the same as GetServerDetails but binds the API request(s) to the specified context
+func (s *Service) GetServerDetailsWithContext(ctx context.Context, r *request.GetServerDetailsRequest) (*upcloud.ServerDetails, error) {
+	if err := s.validate(r); err != nil {
+		return nil, err
+	}
+
 	serverDetails := upcloud.ServerDetails{}
-	response, err := s.basicGetRequest(r.RequestURL())
+	response, err := s.basicGetRequest(ctx, r.RequestURL())
 
 	if err != nil {
 		return nil, err
 	}
 
-	err = json.Unmarshal(response, &serverDetails)
+	err = s.unmarshal(response, &serverDetails)
 	if err != nil {
-		return nil, fmt.Errorf("unable to unmarshal JSON: %s, %w", string(response), err)
+		return nil, err
 	}
 
 	return &serverDetails, nil
@@ -193,15 +378,18 @@ func (s *Service) GetServerDetails(r *request.GetServerDetailsRequest) (*upcloud
 
 // CreateServer creates a server and returns the server details for the newly created server
 func (s *Service) CreateServer(r *request.CreateServerRequest) (*upcloud.ServerDetails, error) {
-	serverDetails := upcloud.ServerDetails{}
-	requestBody, _ := json.Marshal(r)
-	response, err := s.client.PerformJSONPostRequest(s.client.CreateRequestURL(r.RequestURL()), requestBody)
+	return s.CreateServerWithContext(context.Background(), r)
+}
 
-	if err != nil {
-		return nil, parseJSONServiceError(err)
+// CreateServerWithContext is the same as CreateServer but binds the API request(s) to the specified context
+func (s *Service) CreateServerWithContext(ctx context.Context, r *request.CreateServerRequest) (*upcloud.ServerDetails, error) {
+	if err := s.validate(r); err != nil {
+		return nil, err
 	}
 
-	err = json.Unmarshal(response, &serverDetails)
+	serverDetails := upcloud.ServerDetails{}
+	requestBody, _ := json.Marshal(r)
+	err := s.perform(ctx, http.MethodPost, r, requestBody, &serverDetails)
 	if err != nil {
 		return nil, err
 	}
@@ -212,372 +400,573 @@ func (s *Service) CreateServer(r *request.CreateServerRequest) (*upcloud.ServerD
 // WaitForServerState blocks execution until the specified server has entered the specified state. If the state changes
 // favorably, the new server details are returned. The method will give up after the specified timeout
 func (s *Service) WaitForServerState(r *request.WaitForServerStateRequest) (*upcloud.ServerDetails, error) {
-	attempts := 0
-	sleepDuration := time.Second * 5
-
-	for {
-		// Always wait for one attempt period before querying the state the first time. Newly created servers
-		// may not immediately switch to "maintenance" upon creation, triggering a false positive from this
-		// method
-		attempts++
-		time.Sleep(sleepDuration)
-
-		serverDetails, err := s.GetServerDetails(&request.GetServerDetailsRequest{
-			UUID: r.UUID,
-		})
-
-		if err != nil {
-			return nil, err
-		}
+	return s.WaitForServerStateWithContext(context.Background(), r)
+}
 
-		// Either wait for the server to enter the desired state or wait for it to leave the undesired state
-		if r.DesiredState != "" && serverDetails.State == r.DesiredState {
-			return serverDetails, nil
-		} else if r.UndesiredState != "" && serverDetails.State != r.UndesiredState {
-			return serverDetails, nil
-		}
+// WaitForServerStateWithContext is the same as WaitForServerState but binds the API request(s) to the specified context
+func (s *Service) WaitForServerStateWithContext(ctx context.Context, r *request.WaitForServerStateRequest) (*upcloud.ServerDetails, error) {
+	if err := s.validate(r); err != nil {
+		return nil, err
+	}
 
-		if time.Duration(attempts)*sleepDuration >= r.Timeout {
-			return nil, fmt.Errorf("timeout reached while waiting for server to enter state \"%s\"", r.DesiredState)
-		}
+	// Without either state the wait would be done on the first poll, so this is checked even when requests aren't
+	// validated
+	if r.DesiredState == "" && r.UndesiredState == "" {
+		return nil, errors.New("DesiredState or UndesiredState is required")
+	}
+
+	target := fmt.Sprintf("enter state \"%s\"", r.DesiredState)
+	if r.DesiredState == "" {
+		target = fmt.Sprintf("leave state \"%s\"", r.UndesiredState)
 	}
+
+	value, err := s.WaitWithContext(ctx, &WaitCondition{
+		Resource: "server " + r.UUID,
+		Target:   target,
+		Timeout:  r.Timeout,
+		// Newly created servers may not immediately switch to "maintenance" upon creation, triggering a false
+		// positive from this method
+		DelayFirstPoll: true,
+		Poll: func(ctx context.Context) (string, interface{}, error) {
+			serverDetails, err := s.GetServerDetailsWithContext(ctx, &request.GetServerDetailsRequest{
+				UUID: r.UUID,
+			})
+			if err != nil {
+				return "", nil, err
+			}
+
+			return serverDetails.State, serverDetails, nil
+		},
+		// Either wait for the server to enter the desired state or wait for it to leave the undesired state
+		Done: func(state string) bool {
+			if r.DesiredState != "" {
+				return state == r.DesiredState
+			}
+
+			return state != r.UndesiredState
+		},
+		Failed: func(state string, _ interface{}) error {
+			if state == upcloud.ServerStateError {
+				return &TerminalStateError{Resource: "server " + r.UUID, State: state}
+			}
+
+			return nil
+		},
+		DryRunValue: &upcloud.ServerDetails{Server: upcloud.Server{UUID: r.UUID, State: r.DesiredState}},
+	})
+
+	serverDetails, _ := value.(*upcloud.ServerDetails)
+	return serverDetails, err
 }
 
 // StartServer starts the specified server
 func (s *Service) StartServer(r *request.StartServerRequest) (*upcloud.ServerDetails, error) {
-	// Save previous timeout
-	prevTimeout := s.client.GetTimeout()
+	return s.StartServerWithContext(context.Background(), r)
+}
 
-	// Increase the client timeout to match the request timeout
-	s.client.SetTimeout(r.Timeout)
+// StartServerWithContext is the same as StartServer but binds the API request(s) to the specified context
+func (s *Service) StartServerWithContext(ctx context.Context, r *request.StartServerRequest) (*upcloud.ServerDetails, error) {
+	if err := s.validate(r); err != nil {
+		return nil, err
+	}
 
+	// Increase the timeout of this request to match the request timeout
 	requestBody, _ := json.Marshal(r)
-	response, err := s.client.PerformJSONPostRequest(s.client.CreateRequestURL(r.RequestURL()), requestBody)
-
-	// Restore previous timout
-	s.client.SetTimeout(prevTimeout)
-
+	serverDetails := upcloud.ServerDetails{}
+	err := s.perform(ctx, http.MethodPost, r, requestBody, &serverDetails, client.WithRequestTimeout(r.Timeout))
 	if err != nil {
-		return nil, parseJSONServiceError(err)
+		return nil, err
 	}
 
-	serverDetails := upcloud.ServerDetails{}
-	json.Unmarshal(response, &serverDetails)
-
 	return &serverDetails, nil
 }
 
 // StopServer stops the specified server
 func (s *Service) StopServer(r *request.StopServerRequest) (*upcloud.ServerDetails, error) {
-	// Save previous timeout
-	prevTimeout := s.client.GetTimeout()
+	return s.StopServerWithContext(context.Background(), r)
+}
 
-	// Increase the client timeout to match the request timeout
-	// Allow ten seconds to give the API a chance to respond with an error
-	s.client.SetTimeout(r.Timeout + 10*time.Second)
+// StopServerWithContext is the same as StopServer but binds the API request(s) to the specified context
+func (s *Service) StopServerWithContext(ctx context.Context, r *request.StopServerRequest) (*upcloud.ServerDetails, error) {
+	if err := s.validate(r); err != nil {
+		return nil, err
+	}
 
+	// Increase the timeout of this request to match the request timeout
+	// Allow ten seconds to give the API a chance to respond with an error
 	serverDetails := upcloud.ServerDetails{}
 	requestBody, _ := json.Marshal(r)
-	response, err := s.client.PerformJSONPostRequest(s.client.CreateRequestURL(r.RequestURL()), requestBody)
-
-	// Restore previous timeout
-	s.client.SetTimeout(prevTimeout)
-
+	err := s.perform(ctx, http.MethodPost, r, requestBody, &serverDetails, client.WithRequestTimeout(r.Timeout+10*time.Second))
 	if err != nil {
-		return nil, parseJSONServiceError(err)
+		return nil, err
 	}
 
-	json.Unmarshal(response, &serverDetails)
-
 	return &serverDetails, nil
 }
 
 // RestartServer restarts the specified server
 func (s *Service) RestartServer(r *request.RestartServerRequest) (*upcloud.ServerDetails, error) {
-	// Save previous timeout
-	prevTimeout := s.client.GetTimeout()
+	return s.RestartServerWithContext(context.Background(), r)
+}
 
-	// Increase the client timeout to match the request timeout
-	// Allow ten seconds to give the API a chance to respond with an error
-	s.client.SetTimeout(r.Timeout + 10*time.Second)
+// RestartServerWithContext is the same as RestartServer but binds the API request(s) to the specified context
+func (s *Service) RestartServerWithContext(ctx context.Context, r *request.RestartServerRequest) (*upcloud.ServerDetails, error) {
+	if err := s.validate(r); err != nil {
+		return nil, err
+	}
 
+	// Increase the timeout of this request to match the request timeout
+	// Allow ten seconds to give the API a chance to respond with an error
 	serverDetails := upcloud.ServerDetails{}
 	requestBody, _ := json.Marshal(r)
-	response, err := s.client.PerformJSONPostRequest(s.client.CreateRequestURL(r.RequestURL()), requestBody)
-
-	// Restore previous timeout
-	s.client.SetTimeout(prevTimeout)
-
+	err := s.perform(ctx, http.MethodPost, r, requestBody, &serverDetails, client.WithRequestTimeout(r.Timeout+10*time.Second))
 	if err != nil {
-		return nil, parseJSONServiceError(err)
+		return nil, err
 	}
 
-	json.Unmarshal(response, &serverDetails)
-
 	return &serverDetails, nil
 }
 
 // ModifyServer modifies the configuration of an existing server. Attaching and detaching storages as well as assigning
 // and releasing IP addresses have their own separate operations.
 func (s *Service) ModifyServer(r *request.ModifyServerRequest) (*upcloud.ServerDetails, error) {
+	return s.ModifyServerWithContext(context.Background(), r)
+}
+
+// ModifyServerWithContext is the same as ModifyServer but binds the API request(s) to the specified context
+func (s *Service) ModifyServerWithContext(ctx context.Context, r *request.ModifyServerRequest) (*upcloud.ServerDetails, error) {
+	if err := s.validate(r); err != nil {
+		return nil, err
+	}
+
 	serverDetails := upcloud.ServerDetails{}
 	requestBody, _ := json.Marshal(r)
-	response, err := s.client.PerformJSONPutRequest(s.client.CreateRequestURL(r.RequestURL()), requestBody)
-
+	err := s.perform(ctx, http.MethodPut, r, requestBody, &serverDetails)
 	if err != nil {
-		return nil, parseJSONServiceError(err)
+		return nil, err
 	}
 
-	json.Unmarshal(response, &serverDetails)
-
 	return &serverDetails, nil
 }
 
 // DeleteServer deletes the specified server
 func (s *Service) DeleteServer(r *request.DeleteServerRequest) error {
-	err := s.client.PerformJSONDeleteRequest(s.client.CreateRequestURL(r.RequestURL()))
+	return s.DeleteServerWithContext(context.Background(), r)
+}
 
-	if err != nil {
-		return parseJSONServiceError(err)
+// DeleteServerWithContext is the same as DeleteServer but binds the API request(s) to the specified context
+func (s *Service) DeleteServerWithContext(ctx context.Context, r *request.DeleteServerRequest) error {
+	if err := s.validate(r); err != nil {
+		return err
 	}
 
-	return nil
+	return s.perform(ctx, http.MethodDelete, r, nil, nil)
 }
 
 // DeleteServerAndStorages deletes the specified server and all attached storages
 func (s *Service) DeleteServerAndStorages(r *request.DeleteServerAndStoragesRequest) error {
-	err := s.client.PerformJSONDeleteRequest(s.client.CreateRequestURL(r.RequestURL()))
+	return s.DeleteServerAndStoragesWithContext(context.Background(), r)
+}
 
-	if err != nil {
-		return parseJSONServiceError(err)
+// DeleteServerAndStoragesWithContext is the same as DeleteServerAndStorages but binds the API request(s) to the specified context
+func (s *Service) DeleteServerAndStoragesWithContext(ctx context.Context, r *request.DeleteServerAndStoragesRequest) error {
+	if err := s.validate(r); err != nil {
+		return err
 	}
 
-	return nil
+	return s.perform(ctx, http.MethodDelete, r, nil, nil)
 }
 
 // TagServer tags a server with with one or more tags
 func (s *Service) TagServer(r *request.TagServerRequest) (*upcloud.ServerDetails, error) {
-	serverDetails := upcloud.ServerDetails{}
-	response, err := s.client.PerformJSONPostRequest(s.client.CreateRequestURL(r.RequestURL()), nil)
+	return s.TagServerWithContext(context.Background(), r)
+}
 
-	if err != nil {
-		return nil, parseJSONServiceError(err)
+// TagServerWithContext is the same as TagServer but binds the API request(s) to the specified context
+func (s *Service) TagServerWithContext(ctx context.Context, r *request.TagServerRequest) (*upcloud.ServerDetails, error) {
+	if err := s.validate(r); err != nil {
+		return nil, err
 	}
 
-	json.Unmarshal(response, &serverDetails)
+	serverDetails := upcloud.ServerDetails{}
+	err := s.perform(ctx, http.MethodPost, r, nil, &serverDetails)
+	if err != nil {
+		return nil, err
+	}
 
 	return &serverDetails, nil
 }
 
 // UntagServer removes one or more tags from a server
 func (s *Service) UntagServer(r *request.UntagServerRequest) (*upcloud.ServerDetails, error) {
-	serverDetails := upcloud.ServerDetails{}
-	response, err := s.client.PerformJSONPostRequest(s.client.CreateRequestURL(r.RequestURL()), nil)
+	return s.UntagServerWithContext(context.Background(), r)
+}
 
-	if err != nil {
-		return nil, parseJSONServiceError(err)
+// UntagServerWithContext is the same as UntagServer but binds the API request(s) to the specified context
+func (s *Service) UntagServerWithContext(ctx context.Context, r *request.UntagServerRequest) (*upcloud.ServerDetails, error) {
+	if err := s.validate(r); err != nil {
+		return nil, err
 	}
 
-	json.Unmarshal(response, &serverDetails)
+	serverDetails := upcloud.ServerDetails{}
+	err := s.perform(ctx, http.MethodPost, r, nil, &serverDetails)
+	if err != nil {
+		return nil, err
+	}
 
 	return &serverDetails, nil
 }
 
 // CreateTag creates a new tag, optionally assigning it to one or more servers at the same time
 func (s *Service) CreateTag(r *request.CreateTagRequest) (*upcloud.Tag, error) {
+	return s.CreateTagWithContext(context.Background(), r)
+}
+
+// CreateTagWithContext is the same as CreateTag but binds the API request(s) to the specified context
+func (s *Service) CreateTagWithContext(ctx context.Context, r *request.CreateTagRequest) (*upcloud.Tag, error) {
+	if err := s.validate(r); err != nil {
+		return nil, err
+	}
+
 	tagDetails := upcloud.Tag{}
 	requestBody, _ := json.Marshal(r)
-	response, err := s.client.PerformJSONPostRequest(s.client.CreateRequestURL(r.RequestURL()), requestBody)
-
+	err := s.perform(ctx, http.MethodPost, r, requestBody, &tagDetails)
 	if err != nil {
-		return nil, parseJSONServiceError(err)
+		return nil, err
 	}
 
-	json.Unmarshal(response, &tagDetails)
-
 	return &tagDetails, nil
 }
 
 // ModifyTag modifies a tag (e.g. renaming it)
 func (s *Service) ModifyTag(r *request.ModifyTagRequest) (*upcloud.Tag, error) {
+	return s.ModifyTagWithContext(context.Background(), r)
+}
+
+// ModifyTagWithContext is the same as ModifyTag but binds the API request(s) to the specified context
+func (s *Service) ModifyTagWithContext(ctx context.Context, r *request.ModifyTagRequest) (*upcloud.Tag, error) {
+	if err := s.validate(r); err != nil {
+		return nil, err
+	}
+
 	tagDetails := upcloud.Tag{}
 	requestBody, _ := json.Marshal(r)
-	response, err := s.client.PerformJSONPutRequest(s.client.CreateRequestURL(r.RequestURL()), requestBody)
-
+	err := s.perform(ctx, http.MethodPut, r, requestBody, &tagDetails)
 	if err != nil {
-		return nil, parseJSONServiceError(err)
+		return nil, err
 	}
 
-	json.Unmarshal(response, &tagDetails)
-
 	return &tagDetails, nil
 }
 
 // DeleteTag deletes the specified tag
 func (s *Service) DeleteTag(r *request.DeleteTagRequest) error {
-	err := s.client.PerformJSONDeleteRequest(s.client.CreateRequestURL(r.RequestURL()))
+	return s.DeleteTagWithContext(context.Background(), r)
+}
 
-	if err != nil {
-		return parseJSONServiceError(err)
+// DeleteTagWithContext is the same as DeleteTag but binds the API request(s) to the specified context
+func (s *Service) DeleteTagWithContext(ctx context.Context, r *request.DeleteTagRequest) error {
+	if err := s.validate(r); err != nil {
+		return err
 	}
 
-	return nil
+	return s.perform(ctx, http.MethodDelete, r, nil, nil)
 }
 
 // GetIPAddresses returns all IP addresses associated with the account
 func (s *Service) GetIPAddresses() (*upcloud.IPAddresses, error) {
+	return s.GetIPAddressesWithContext(context.Background())
+}
+
+// GetIPAddressesWithContext is the same as GetIPAddresses but binds the API request(s) to the specified context
+func (s *Service) GetIPAddressesWithContext(ctx context.Context) (*upcloud.IPAddresses, error) {
 	ipAddresses := upcloud.IPAddresses{}
-	response, err := s.basicGetRequest("/ip_address")
+	response, err := s.basicGetRequest(ctx, "/ip_address")
 
 	if err != nil {
 		return nil, err
 	}
 
-	json.Unmarshal(response, &ipAddresses)
+	err = s.unmarshal(response, &ipAddresses)
+	if err != nil {
+		return nil, err
+	}
 
 	return &ipAddresses, nil
 }
 
 // GetIPAddressDetails returns extended details about the specified IP address
 func (s *Service) GetIPAddressDetails(r *request.GetIPAddressDetailsRequest) (*upcloud.IPAddress, error) {
+	return s.GetIPAddressDetailsWithContext(context.Background(), r)
+}
+
+// GetIPAddressDetailsWithContext is the same as GetIPAddressDetails but binds the API request(s) to the specified context
+func (s *Service) GetIPAddressDetailsWithContext(ctx context.Context, r *request.GetIPAddressDetailsRequest) (*upcloud.IPAddress, error) {
+	if err := s.validate(r); err != nil {
+		return nil, err
+	}
+
 	ipAddress := upcloud.IPAddress{}
-	response, err := s.basicGetRequest(r.RequestURL())
+	response, err := s.basicGetRequest(ctx, r.RequestURL())
 
 	if err != nil {
 		return nil, err
 	}
 
-	json.Unmarshal(response, &ipAddress)
+	err = s.unmarshal(response, &ipAddress)
+	if err != nil {
+		return nil, err
+	}
 
 	return &ipAddress, nil
 }
 
 // AssignIPAddress assigns the specified IP address to the specified server
 func (s *Service) AssignIPAddress(r *request.AssignIPAddressRequest) (*upcloud.IPAddress, error) {
+	return s.AssignIPAddressWithContext(context.Background(), r)
+}
+
+// AssignIPAddressWithContext is the same as AssignIPAddress but binds the API request(s) to the specified context
+func (s *Service) AssignIPAddressWithContext(ctx context.Context, r *request.AssignIPAddressRequest) (*upcloud.IPAddress, error) {
+	if err := s.validate(r); err != nil {
+		return nil, err
+	}
+
 	ipAddress := upcloud.IPAddress{}
 	requestBody, _ := json.Marshal(r)
-	response, err := s.client.PerformJSONPostRequest(s.client.CreateRequestURL(r.RequestURL()), requestBody)
-
+	err := s.perform(ctx, http.MethodPost, r, requestBody, &ipAddress)
 	if err != nil {
-		return nil, parseJSONServiceError(err)
+		return nil, err
 	}
 
-	json.Unmarshal(response, &ipAddress)
-
 	return &ipAddress, nil
 }
 
 // ModifyIPAddress modifies the specified IP address
 func (s *Service) ModifyIPAddress(r *request.ModifyIPAddressRequest) (*upcloud.IPAddress, error) {
+	return s.ModifyIPAddressWithContext(context.Background(), r)
+}
+
+// ModifyIPAddressWithContext is the same as ModifyIPAddress but binds the API request(s) to the specified context
+func (s *Service) ModifyIPAddressWithContext(ctx context.Context, r *request.ModifyIPAddressRequest) (*upcloud.IPAddress, error) {
+	if err := s.validate(r); err != nil {
+		return nil, err
+	}
+
 	ipAddress := upcloud.IPAddress{}
 	requestBody, _ := json.Marshal(r)
-	response, err := s.client.PerformJSONPatchRequest(s.client.CreateRequestURL(r.RequestURL()), requestBody)
-
+	err := s.perform(ctx, http.MethodPatch, r, requestBody, &ipAddress)
 	if err != nil {
-		return nil, parseJSONServiceError(err)
+		return nil, err
 	}
 
-	json.Unmarshal(response, &ipAddress)
-
 	return &ipAddress, nil
 }
 
 // ReleaseIPAddress releases the specified IP address from the server it is attached to
 func (s *Service) ReleaseIPAddress(r *request.ReleaseIPAddressRequest) error {
-	err := s.client.PerformJSONDeleteRequest(s.client.CreateRequestURL(r.RequestURL()))
+	return s.ReleaseIPAddressWithContext(context.Background(), r)
+}
 
-	if err != nil {
-		return parseJSONServiceError(err)
+// ReleaseIPAddressWithContext is the same as ReleaseIPAddress but binds the API request(s) to the specified context
+func (s *Service) ReleaseIPAddressWithContext(ctx context.Context, r *request.ReleaseIPAddressRequest) error {
+	if err := s.validate(r); err != nil {
+		return err
 	}
 
-	return nil
+	return s.perform(ctx, http.MethodDelete, r, nil, nil)
 }
 
 // GetFirewallRules returns the firewall rules for the specified server
 func (s *Service) GetFirewallRules(r *request.GetFirewallRulesRequest) (*upcloud.FirewallRules, error) {
+	return s.GetFirewallRulesWithContext(context.Background(), r)
+}
+
+// GetFirewallRulesWithContext is the same as GetFirewallRules but binds the API request(s) to the specified context
+func (s *Service) GetFirewallRulesWithContext(ctx context.Context, r *request.GetFirewallRulesRequest) (*upcloud.FirewallRules, error) {
+	if err := s.validate(r); err != nil {
+		return nil, err
+	}
+
 	firewallRules := upcloud.FirewallRules{}
-	response, err := s.basicGetRequest(r.RequestURL())
+	response, err := s.basicGetRequest(ctx, r.RequestURL())
 
 	if err != nil {
 		return nil, err
 	}
 
-	json.Unmarshal(response, &firewallRules)
+	err = s.unmarshal(response, &firewallRules)
+	if err != nil {
+		return nil, err
+	}
 
 	return &firewallRules, nil
 }
 
 // GetFirewallRuleDetails returns extended details about the specified firewall rule
 func (s *Service) GetFirewallRuleDetails(r *request.GetFirewallRuleDetailsRequest) (*upcloud.FirewallRule, error) {
+	return s.GetFirewallRuleDetailsWithContext(context.Background(), r)
+}
+
+// GetFirewallRuleDetailsWithContext is the same as GetFirewallRuleDetails but binds the API request(s) to the specified context
+func (s *Service) GetFirewallRuleDetailsWithContext(ctx context.Context, r *request.GetFirewallRuleDetailsRequest) (*upcloud.FirewallRule, error) {
+	if err := s.validate(r); err != nil {
+		return nil, err
+	}
+
 	firewallRule := upcloud.FirewallRule{}
-	response, err := s.basicGetRequest(r.RequestURL())
+	response, err := s.basicGetRequest(ctx, r.RequestURL())
 
 	if err != nil {
-		return nil, parseJSONServiceError(err)
+		return nil, err
 	}
 
-	json.Unmarshal(response, &firewallRule)
+	err = s.unmarshal(response, &firewallRule)
+	if err != nil {
+		return nil, err
+	}
 
 	return &firewallRule, nil
 }
 
 // CreateFirewallRule creates the firewall rule
 func (s *Service) CreateFirewallRule(r *request.CreateFirewallRuleRequest) (*upcloud.FirewallRule, error) {
+	return s.CreateFirewallRuleWithContext(context.Background(), r)
+}
+
+// CreateFirewallRuleWithContext is the same as CreateFirewallRule but binds the API request(s) to the specified context
+func (s *Service) CreateFirewallRuleWithContext(ctx context.Context, r *request.CreateFirewallRuleRequest) (*upcloud.FirewallRule, error) {
+	if err := s.validate(r); err != nil {
+		return nil, err
+	}
+
 	firewallRule := upcloud.FirewallRule{}
 	requestBody, _ := json.Marshal(r)
-	response, err := s.client.PerformJSONPostRequest(s.client.CreateRequestURL(r.RequestURL()), requestBody)
-
+	err := s.perform(ctx, http.MethodPost, r, requestBody, &firewallRule)
 	if err != nil {
-		return nil, parseJSONServiceError(err)
+		return nil, err
 	}
 
-	json.Unmarshal(response, &firewallRule)
-
 	return &firewallRule, nil
 }
 
 // CreateFirewallRules creates multiple firewall rules
 func (s *Service) CreateFirewallRules(r *request.CreateFirewallRulesRequest) error {
-	requestBody, _ := json.Marshal(r)
-	_, err := s.client.PerformJSONPutRequest(s.client.CreateRequestURL(r.RequestURL()), requestBody)
+	return s.CreateFirewallRulesWithContext(context.Background(), r)
+}
 
-	if err != nil {
-		return parseJSONServiceError(err)
+// CreateFirewallRulesWithContext is the same as CreateFirewallRules but binds the API request(s) to the specified context
+func (s *Service) CreateFirewallRulesWithContext(ctx context.Context, r *request.CreateFirewallRulesRequest) error {
+	if err := s.validate(r); err != nil {
+		return err
 	}
 
-	return nil
+	requestBody, _ := json.Marshal(r)
+	return s.perform(ctx, http.MethodPut, r, requestBody, nil)
 }
 
 // DeleteFirewallRule deletes the specified firewall rule
 func (s *Service) DeleteFirewallRule(r *request.DeleteFirewallRuleRequest) error {
-	err := s.client.PerformJSONDeleteRequest(s.client.CreateRequestURL(r.RequestURL()))
+	return s.DeleteFirewallRuleWithContext(context.Background(), r)
+}
 
-	if err != nil {
-		return parseJSONServiceError(err)
+// DeleteFirewallRuleWithContext is the same as DeleteFirewallRule but binds the API request(s) to the specified context
+func (s *Service) DeleteFirewallRuleWithContext(ctx context.Context, r *request.DeleteFirewallRuleRequest) error {
+	if err := s.validate(r); err != nil {
+		return err
 	}
 
-	return nil
+	return s.perform(ctx, http.MethodDelete, r, nil, nil)
 }
 
 // GetTags returns all tags
 func (s *Service) GetTags() (*upcloud.Tags, error) {
+	return s.GetTagsWithContext(context.Background())
+}
+
+// GetTagsWithContext is the same as GetTags but binds the API request(s) to the specified context
+func (s *Service) GetTagsWithContext(ctx context.Context) (*upcloud.Tags, error) {
 	tags := upcloud.Tags{}
-	response, err := s.basicGetRequest("/tag")
+	response, err := s.basicGetRequest(ctx, "/tag")
 
 	if err != nil {
 		return nil, err
 	}
 
-	json.Unmarshal(response, &tags)
+	err = s.unmarshal(response, &tags)
+	if err != nil {
+		return nil, err
+	}
 
 	return &tags, nil
 }
 
 // Wrapper that performs a GET request to the specified location and returns the response or a service error
-func (s *Service) basicGetRequest(location string) ([]byte, error) {
+func (s *Service) basicGetRequest(ctx context.Context, location string) ([]byte, error) {
 	requestURL := s.client.CreateRequestURL(location)
 
-	response, err := s.client.PerformJSONGetRequest(requestURL)
+	response, err := s.client.PerformJSONGetRequestWithContext(ctx, requestURL)
+
+	if err != nil {
+		return nil, parseJSONServiceError(err)
+	}
+
+	return response, nil
+}
+
+// Performs a request with the specified method and body and decodes the response into v, unless v is nil. In dry-run
+// mode a request that changes something is added to the plan of the client instead and v is filled with a placeholder
+// result.
+func (s *Service) perform(ctx context.Context, method string, r request.Request, body []byte, v interface{}, opts ...client.RequestOption) error {
+	requestURL := s.client.CreateRequestURL(r.RequestURL())
+
+	if plan := s.client.GetDryRun(); plan != nil && method != http.MethodGet {
+		planned := client.PlannedRequest{
+			Method:  method,
+			URL:     requestURL,
+			Type:    strings.TrimPrefix(fmt.Sprintf("%T", r), "*"),
+			Body:    body,
+			Request: r,
+			Result:  v,
+		}
+		n := plan.Add(planned)
+		if v != nil {
+			fillPlaceholder(v, r, n)
+		}
+		return nil
+	}
+
+	response, err := s.send(ctx, method, requestURL, body, opts...)
+	if err != nil {
+		return err
+	}
+
+	if v == nil {
+		return nil
+	}
+
+	return s.unmarshal(response, v)
+}
+
+// Sends a request with the specified method and body to requestURL and returns the body of the response, which is nil
+// for DELETE requests.
+func (s *Service) send(ctx context.Context, method, requestURL string, body []byte, opts ...client.RequestOption) ([]byte, error) {
+	var response []byte
+	var err error
+	switch method {
+	case http.MethodGet:
+		response, err = s.client.PerformJSONGetRequestWithContext(ctx, requestURL, opts...)
+	case http.MethodPost:
+		response, err = s.client.PerformJSONPostRequestWithContext(ctx, requestURL, body, opts...)
+	case http.MethodPut:
+		response, err = s.client.PerformJSONPutRequestWithContext(ctx, requestURL, body, opts...)
+	case http.MethodPatch:
+		response, err = s.client.PerformJSONPatchRequestWithContext(ctx, requestURL, body, opts...)
+	case http.MethodDelete:
+		err = s.client.PerformJSONDeleteRequestWithContext(ctx, requestURL, opts...)
+	default:
+		return nil, fmt.Errorf("unsupported method %s", method)
+	}
 
 	if err != nil {
 		return nil, parseJSONServiceError(err)
@@ -589,11 +978,20 @@ func (s *Service) basicGetRequest(location string) ([]byte, error) {
 // Parses an error returned from the client into a service error object
 func parseJSONServiceError(err error) error {
 	// Parse service errors
-	if clientError, ok := err.(*client.Error); ok {
+	var clientError *client.Error
+	if errors.As(err, &clientError) {
 		serviceError := upcloud.Error{}
 		responseBody := clientError.ResponseBody
+		// The body isn't necessarily JSON, e.g. when the error comes from a proxy. The HTTP details below are
+		// available regardless.
 		json.Unmarshal(responseBody, &serviceError)
 
+		serviceError.Status = clientError.ErrorCode
+		serviceError.Method = clientError.Method
+		serviceError.URL = clientError.URL
+		serviceError.ResponseBody = responseBody
+		serviceError.Err = clientError
+
 		return &serviceError
 	}
 
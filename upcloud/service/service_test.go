@@ -1,14 +1,19 @@
 package service
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"log"
+	"net/http"
+	"net/http/httptest"
 	"os"
 	"reflect"
 	"testing"
 	"time"
 
 	"github.com/UpCloudLtd/upcloud-go-api/upcloud"
+	"github.com/UpCloudLtd/upcloud-go-api/upcloud/client"
 	"github.com/UpCloudLtd/upcloud-go-api/upcloud/request"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -73,6 +78,31 @@ func TestGetZones(t *testing.T) {
 	})
 }
 
+// TestGetZonesWithContext tests that the context-aware variant returns the same data as GetZones()
+func TestGetZonesWithContext(t *testing.T) {
+	record(t, "getzones", func(t *testing.T, svc *Service) {
+		zones, err := svc.GetZonesWithContext(context.Background())
+		require.NoError(t, err)
+		assert.NotEmpty(t, zones.Zones)
+	})
+}
+
+// TestWaitForServerStateWithCancelledContext tests that waiting stops as soon as the context is cancelled
+func TestWaitForServerStateWithCancelledContext(t *testing.T) {
+	svc := New(client.New("username", "password"))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	serverDetails, err := svc.WaitForServerStateWithContext(ctx, &request.WaitForServerStateRequest{
+		UUID:         "00798b85-efdc-41ca-8021-f6ef457b8531",
+		DesiredState: upcloud.ServerStateStarted,
+		Timeout:      time.Minute,
+	})
+	assert.Nil(t, serverDetails)
+	assert.True(t, errors.Is(err, context.Canceled))
+}
+
 // TestGetPriceZones tests that GetPriceZones() function returns proper data
 func TestGetPriceZones(t *testing.T) {
 	record(t, "getpricezones", func(t *testing.T, svc *Service) {
@@ -309,9 +339,44 @@ func TestErrorHandling(t *testing.T) {
 		if actualErrorType != expectedErrorType {
 			t.Errorf("TestErrorHandling expected %s, got %s", expectedErrorType, actualErrorType)
 		}
+
+		// Check that the HTTP details are preserved
+		serviceError := err.(*upcloud.Error)
+		assert.Equal(t, "SERVER_INVALID", serviceError.ErrorCode)
+		assert.Equal(t, http.StatusBadRequest, serviceError.Status)
+		assert.Equal(t, http.MethodPost, serviceError.Method)
+		assert.Equal(t, "https://api.upcloud.com/1.3/server/invalid/start", serviceError.URL)
+		assert.True(t, errors.Is(err, upcloud.ErrBadRequest))
+
+		var clientError *client.Error
+		assert.True(t, errors.As(err, &clientError))
 	})
 }
 
+// TestErrorHandlingWithoutJSON tests that errors without an API error body keep their HTTP details
+func TestErrorHandlingWithoutJSON(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte("<html>Not Found</html>"))
+	}))
+	defer srv.Close()
+
+	svc := New(client.NewWithOptions("username", "password", client.WithBaseURL(srv.URL)))
+
+	_, err := svc.GetServerDetails(&request.GetServerDetailsRequest{
+		UUID: "00798b85-efdc-41ca-8021-f6ef457b8531",
+	})
+	require.Error(t, err)
+	assert.True(t, errors.Is(err, upcloud.ErrNotFound))
+
+	var serviceError *upcloud.Error
+	require.True(t, errors.As(err, &serviceError))
+	assert.Empty(t, serviceError.ErrorCode)
+	assert.Equal(t, http.StatusNotFound, serviceError.Status)
+	assert.Equal(t, "<html>Not Found</html>", string(serviceError.ResponseBody))
+	assert.Contains(t, err.Error(), "/server/00798b85-efdc-41ca-8021-f6ef457b8531: unexpected response 404 Not Found")
+}
+
 // TestCreateModifyDeleteServer performs the following actions:
 //
 // - creates a server
@@ -944,3 +1009,39 @@ func TestTagging(t *testing.T) {
 		t.Logf("Server %s is now untagged", serverDetails.Title)
 	})
 }
+
+// TestRequestValidation tests that invalid requests never reach the API when request validation is enabled
+func TestRequestValidation(t *testing.T) {
+	calls := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.WriteHeader(http.StatusBadRequest)
+		fmt.Fprint(w, `{"error":{"error_code":"STORAGE_SIZE_INVALID","error_message":"The size of the storage is invalid."}}`)
+	}))
+	defer srv.Close()
+
+	svc := New(client.NewWithOptions("username", "password", client.WithBaseURL(srv.URL)))
+	createStorageRequest := &request.CreateStorageRequest{
+		Size: 5,
+		Zone: "fi-hel2",
+	}
+
+	// Validation is disabled by default
+	_, err := svc.CreateStorage(createStorageRequest)
+	assert.True(t, errors.Is(err, upcloud.ErrBadRequest))
+	assert.Equal(t, 1, calls)
+
+	svc.SetRequestValidation(true)
+
+	_, err = svc.CreateStorage(createStorageRequest)
+	assert.True(t, errors.Is(err, upcloud.ErrBadRequest))
+	assert.EqualError(t, err, "invalid CreateStorageRequest: Size must be between 10 and 4096, got 5")
+	assert.Equal(t, 1, calls)
+
+	var validationErr *request.ValidationError
+	assert.True(t, errors.As(err, &validationErr))
+
+	err = svc.DeleteServer(&request.DeleteServerRequest{})
+	assert.EqualError(t, err, "invalid DeleteServerRequest: UUID is required")
+	assert.Equal(t, 1, calls)
+}
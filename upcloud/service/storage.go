@@ -1,259 +1,426 @@
 package service
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
+	"net/http"
 	"os"
 	"time"
 
 	"github.com/UpCloudLtd/upcloud-go-api/upcloud"
+	"github.com/UpCloudLtd/upcloud-go-api/upcloud/client"
 	"github.com/UpCloudLtd/upcloud-go-api/upcloud/request"
 )
 
 type Storage interface {
 	GetStorages(r *request.GetStoragesRequest) (*upcloud.Storages, error)
+	GetStoragesWithContext(ctx context.Context, r *request.GetStoragesRequest) (*upcloud.Storages, error)
 	GetStorageDetails(r *request.GetStorageDetailsRequest) (*upcloud.StorageDetails, error)
+	GetStorageDetailsWithContext(ctx context.Context, r *request.GetStorageDetailsRequest) (*upcloud.StorageDetails, error)
 	CreateStorage(r *request.CreateStorageRequest) (*upcloud.StorageDetails, error)
+	CreateStorageWithContext(ctx context.Context, r *request.CreateStorageRequest) (*upcloud.StorageDetails, error)
 	ModifyStorage(r *request.ModifyStorageRequest) (*upcloud.StorageDetails, error)
+	ModifyStorageWithContext(ctx context.Context, r *request.ModifyStorageRequest) (*upcloud.StorageDetails, error)
 	AttachStorage(r *request.AttachStorageRequest) (*upcloud.ServerDetails, error)
+	AttachStorageWithContext(ctx context.Context, r *request.AttachStorageRequest) (*upcloud.ServerDetails, error)
 	DetachStorage(r *request.DetachStorageRequest) (*upcloud.ServerDetails, error)
+	DetachStorageWithContext(ctx context.Context, r *request.DetachStorageRequest) (*upcloud.ServerDetails, error)
 	CloneStorage(r *request.CloneStorageRequest) (*upcloud.StorageDetails, error)
+	CloneStorageWithContext(ctx context.Context, r *request.CloneStorageRequest) (*upcloud.StorageDetails, error)
 	TemplatizeStorage(r *request.TemplatizeStorageRequest) (*upcloud.StorageDetails, error)
+	TemplatizeStorageWithContext(ctx context.Context, r *request.TemplatizeStorageRequest) (*upcloud.StorageDetails, error)
 	WaitForStorageState(r *request.WaitForStorageStateRequest) (*upcloud.StorageDetails, error)
+	WaitForStorageStateWithContext(ctx context.Context, r *request.WaitForStorageStateRequest) (*upcloud.StorageDetails, error)
 	LoadCDROM(r *request.LoadCDROMRequest) (*upcloud.ServerDetails, error)
+	LoadCDROMWithContext(ctx context.Context, r *request.LoadCDROMRequest) (*upcloud.ServerDetails, error)
 	EjectCDROM(r *request.EjectCDROMRequest) (*upcloud.ServerDetails, error)
+	EjectCDROMWithContext(ctx context.Context, r *request.EjectCDROMRequest) (*upcloud.ServerDetails, error)
 	CreateBackup(r *request.CreateBackupRequest) (*upcloud.StorageDetails, error)
+	CreateBackupWithContext(ctx context.Context, r *request.CreateBackupRequest) (*upcloud.StorageDetails, error)
 	RestoreBackup(r *request.RestoreBackupRequest) error
+	RestoreBackupWithContext(ctx context.Context, r *request.RestoreBackupRequest) error
 	CreateStorageImport(r *request.CreateStorageImportRequest) (*upcloud.StorageImportDetails, error)
+	CreateStorageImportWithContext(ctx context.Context, r *request.CreateStorageImportRequest) (*upcloud.StorageImportDetails, error)
 	GetStorageImportDetails(r *request.GetStorageImportDetailsRequest) (*upcloud.StorageImportDetails, error)
+	GetStorageImportDetailsWithContext(ctx context.Context, r *request.GetStorageImportDetailsRequest) (*upcloud.StorageImportDetails, error)
 	WaitForStorageImportCompletion(r *request.WaitForStorageImportCompletionRequest) (*upcloud.StorageImportDetails, error)
+	WaitForStorageImportCompletionWithContext(ctx context.Context, r *request.WaitForStorageImportCompletionRequest) (*upcloud.StorageImportDetails, error)
+	WaitForBackupCreation(r *request.WaitForBackupCreationRequest) (*upcloud.StorageDetails, error)
+	WaitForBackupCreationWithContext(ctx context.Context, r *request.WaitForBackupCreationRequest) (*upcloud.StorageDetails, error)
+	WaitForStorageCloneCompletion(r *request.WaitForStorageCloneCompletionRequest) (*upcloud.StorageDetails, error)
+	WaitForStorageCloneCompletionWithContext(ctx context.Context, r *request.WaitForStorageCloneCompletionRequest) (*upcloud.StorageDetails, error)
 	DeleteStorage(*request.DeleteStorageRequest) error
+	DeleteStorageWithContext(ctx context.Context, r *request.DeleteStorageRequest) error
 }
 
 var _ Storage = (*Service)(nil)
 
 // GetStorages returns all available storages
 func (s *Service) GetStorages(r *request.GetStoragesRequest) (*upcloud.Storages, error) {
+	return s.GetStoragesWithContext(context.Background(), r)
+}
+
+// GetStoragesWithContext is the same as GetStorages but binds the API request(s) to the specified context
+func (s *Service) GetStoragesWithContext(ctx context.Context, r *request.GetStoragesRequest) (*upcloud.Storages, error) {
+	if err := s.validate(r); err != nil {
+		return nil, err
+	}
+
 	storages := upcloud.Storages{}
-	response, err := s.basicGetRequest(r.RequestURL())
+	response, err := s.basicGetRequest(ctx, r.RequestURL())
 
 	if err != nil {
 		return nil, err
 	}
 
-	json.Unmarshal(response, &storages)
+	err = s.unmarshal(response, &storages)
+	if err != nil {
+		return nil, err
+	}
 
 	return &storages, nil
 }
 
 // GetStorageDetails returns extended details about the specified piece of storage
 func (s *Service) GetStorageDetails(r *request.GetStorageDetailsRequest) (*upcloud.StorageDetails, error) {
+	return s.GetStorageDetailsWithContext(context.Background(), r)
+}
+
+// GetStorageDetailsWithContext is the same as GetStorageDetails but binds the API request(s) to the specified context
+func (s *Service) GetStorageDetailsWithContext(ctx context.Context, r *request.GetStorageDetailsRequest) (*upcloud.StorageDetails, error) {
+	if err := s.validate(r); err != nil {
+		return nil, err
+	}
+
 	storageDetails := upcloud.StorageDetails{}
-	response, err := s.basicGetRequest(r.RequestURL())
+	response, err := s.basicGetRequest(ctx, r.RequestURL())
 
 	if err != nil {
 		return nil, err
 	}
 
-	json.Unmarshal(response, &storageDetails)
+	err = s.unmarshal(response, &storageDetails)
+	if err != nil {
+		return nil, err
+	}
 
 	return &storageDetails, nil
 }
 
 // CreateStorage creates the specified storage
 func (s *Service) CreateStorage(r *request.CreateStorageRequest) (*upcloud.StorageDetails, error) {
+	return s.CreateStorageWithContext(context.Background(), r)
+}
+
+// CreateStorageWithContext is the same as CreateStorage but binds the API request(s) to the specified context
+func (s *Service) CreateStorageWithContext(ctx context.Context, r *request.CreateStorageRequest) (*upcloud.StorageDetails, error) {
+	if err := s.validate(r); err != nil {
+		return nil, err
+	}
+
 	storageDetails := upcloud.StorageDetails{}
 	requestBody, _ := json.Marshal(r)
-	response, err := s.client.PerformJSONPostRequest(s.client.CreateRequestURL(r.RequestURL()), requestBody)
-
+	err := s.perform(ctx, http.MethodPost, r, requestBody, &storageDetails)
 	if err != nil {
-		return nil, parseJSONServiceError(err)
+		return nil, err
 	}
 
-	json.Unmarshal(response, &storageDetails)
-
 	return &storageDetails, nil
 }
 
 // ModifyStorage modifies the specified storage device
 func (s *Service) ModifyStorage(r *request.ModifyStorageRequest) (*upcloud.StorageDetails, error) {
+	return s.ModifyStorageWithContext(context.Background(), r)
+}
+
+// ModifyStorageWithContext is the same as ModifyStorage but binds the API request(s) to the specified context
+func (s *Service) ModifyStorageWithContext(ctx context.Context, r *request.ModifyStorageRequest) (*upcloud.StorageDetails, error) {
+	if err := s.validate(r); err != nil {
+		return nil, err
+	}
+
 	storageDetails := upcloud.StorageDetails{}
 	requestBody, _ := json.Marshal(r)
-	response, err := s.client.PerformJSONPutRequest(s.client.CreateRequestURL(r.RequestURL()), requestBody)
-
+	err := s.perform(ctx, http.MethodPut, r, requestBody, &storageDetails)
 	if err != nil {
-		return nil, parseJSONServiceError(err)
+		return nil, err
 	}
 
-	json.Unmarshal(response, &storageDetails)
-
 	return &storageDetails, nil
 }
 
 // AttachStorage attaches the specified storage to the specified server
 func (s *Service) AttachStorage(r *request.AttachStorageRequest) (*upcloud.ServerDetails, error) {
+	return s.AttachStorageWithContext(context.Background(), r)
+}
+
+// AttachStorageWithContext is the same as AttachStorage but binds the API request(s) to the specified context
+func (s *Service) AttachStorageWithContext(ctx context.Context, r *request.AttachStorageRequest) (*upcloud.ServerDetails, error) {
+	if err := s.validate(r); err != nil {
+		return nil, err
+	}
+
 	serverDetails := upcloud.ServerDetails{}
 	requestBody, _ := json.Marshal(r)
-	response, err := s.client.PerformJSONPostRequest(s.client.CreateRequestURL(r.RequestURL()), requestBody)
-
+	err := s.perform(ctx, http.MethodPost, r, requestBody, &serverDetails)
 	if err != nil {
-		return nil, parseJSONServiceError(err)
+		return nil, err
 	}
 
-	json.Unmarshal(response, &serverDetails)
-
 	return &serverDetails, nil
 }
 
 // DetachStorage detaches the specified storage from the specified server
 func (s *Service) DetachStorage(r *request.DetachStorageRequest) (*upcloud.ServerDetails, error) {
+	return s.DetachStorageWithContext(context.Background(), r)
+}
+
+// DetachStorageWithContext is the same as DetachStorage but binds the API request(s) to the specified context
+func (s *Service) DetachStorageWithContext(ctx context.Context, r *request.DetachStorageRequest) (*upcloud.ServerDetails, error) {
+	if err := s.validate(r); err != nil {
+		return nil, err
+	}
+
 	serverDetails := upcloud.ServerDetails{}
 	requestBody, _ := json.Marshal(r)
-	response, err := s.client.PerformJSONPostRequest(s.client.CreateRequestURL(r.RequestURL()), requestBody)
-
+	err := s.perform(ctx, http.MethodPost, r, requestBody, &serverDetails)
 	if err != nil {
-		return nil, parseJSONServiceError(err)
+		return nil, err
 	}
 
-	json.Unmarshal(response, &serverDetails)
-
 	return &serverDetails, nil
 }
 
 // DeleteStorage deletes the specified storage device
 func (s *Service) DeleteStorage(r *request.DeleteStorageRequest) error {
-	err := s.client.PerformJSONDeleteRequest(s.client.CreateRequestURL(r.RequestURL()))
+	return s.DeleteStorageWithContext(context.Background(), r)
+}
 
-	if err != nil {
-		return parseJSONServiceError(err)
+// DeleteStorageWithContext is the same as DeleteStorage but binds the API request(s) to the specified context
+func (s *Service) DeleteStorageWithContext(ctx context.Context, r *request.DeleteStorageRequest) error {
+	if err := s.validate(r); err != nil {
+		return err
 	}
 
-	return nil
+	return s.perform(ctx, http.MethodDelete, r, nil, nil)
 }
 
 // CloneStorage detaches the specified storage from the specified server
 func (s *Service) CloneStorage(r *request.CloneStorageRequest) (*upcloud.StorageDetails, error) {
+	return s.CloneStorageWithContext(context.Background(), r)
+}
+
+// CloneStorageWithContext is the same as CloneStorage but binds the API request(s) to the specified context
+func (s *Service) CloneStorageWithContext(ctx context.Context, r *request.CloneStorageRequest) (*upcloud.StorageDetails, error) {
+	if err := s.validate(r); err != nil {
+		return nil, err
+	}
+
 	storageDetails := upcloud.StorageDetails{}
 	requestBody, _ := json.Marshal(r)
-	response, err := s.client.PerformJSONPostRequest(s.client.CreateRequestURL(r.RequestURL()), requestBody)
-
+	err := s.perform(ctx, http.MethodPost, r, requestBody, &storageDetails)
 	if err != nil {
-		return nil, parseJSONServiceError(err)
+		return nil, err
 	}
 
-	json.Unmarshal(response, &storageDetails)
-
 	return &storageDetails, nil
 }
 
 // TemplatizeStorage detaches the specified storage from the specified server
 func (s *Service) TemplatizeStorage(r *request.TemplatizeStorageRequest) (*upcloud.StorageDetails, error) {
+	return s.TemplatizeStorageWithContext(context.Background(), r)
+}
+
+// TemplatizeStorageWithContext is the same as TemplatizeStorage but binds the API request(s) to the specified context
+func (s *Service) TemplatizeStorageWithContext(ctx context.Context, r *request.TemplatizeStorageRequest) (*upcloud.StorageDetails, error) {
+	if err := s.validate(r); err != nil {
+		return nil, err
+	}
+
 	storageDetails := upcloud.StorageDetails{}
 	requestBody, _ := json.Marshal(r)
-	response, err := s.client.PerformJSONPostRequest(s.client.CreateRequestURL(r.RequestURL()), requestBody)
-
+	err := s.perform(ctx, http.MethodPost, r, requestBody, &storageDetails)
 	if err != nil {
-		return nil, parseJSONServiceError(err)
+		return nil, err
 	}
 
-	json.Unmarshal(response, &storageDetails)
-
 	return &storageDetails, nil
 }
 
 // WaitForStorageState blocks execution until the specified storage device has entered the specified state. If the
 // state changes favorably, the new storage details is returned. The method will give up after the specified timeout
 func (s *Service) WaitForStorageState(r *request.WaitForStorageStateRequest) (*upcloud.StorageDetails, error) {
-	attempts := 0
-	sleepDuration := time.Second * 5
+	return s.WaitForStorageStateWithContext(context.Background(), r)
+}
 
-	for {
-		attempts++
+// WaitForStorageStateWithContext is the same as WaitForStorageState but binds the API request(s) to the specified context
+func (s *Service) WaitForStorageStateWithContext(ctx context.Context, r *request.WaitForStorageStateRequest) (*upcloud.StorageDetails, error) {
+	if err := s.validate(r); err != nil {
+		return nil, err
+	}
 
-		storageDetails, err := s.GetStorageDetails(&request.GetStorageDetailsRequest{
-			UUID: r.UUID,
-		})
+	return s.waitForStorageState(ctx, r.UUID, r.DesiredState, r.Timeout)
+}
 
-		if err != nil {
-			return nil, err
-		}
+// Waits for the specified storage device to enter the specified state. The wait fails fast when the storage enters
+// the error state, unless that is the desired state.
+func (s *Service) waitForStorageState(ctx context.Context, uuid, desiredState string, timeout time.Duration) (*upcloud.StorageDetails, error) {
+	value, err := s.WaitWithContext(ctx, &WaitCondition{
+		Resource: "storage " + uuid,
+		Target:   fmt.Sprintf("enter state \"%s\"", desiredState),
+		Timeout:  timeout,
+		Poll: func(ctx context.Context) (string, interface{}, error) {
+			storageDetails, err := s.GetStorageDetailsWithContext(ctx, &request.GetStorageDetailsRequest{
+				UUID: uuid,
+			})
+			if err != nil {
+				return "", nil, err
+			}
 
-		if storageDetails.State == r.DesiredState {
-			return storageDetails, nil
-		}
+			return storageDetails.State, storageDetails, nil
+		},
+		Done: func(state string) bool {
+			return state == desiredState
+		},
+		Failed: func(state string, _ interface{}) error {
+			if state == upcloud.StorageStateError {
+				return &TerminalStateError{Resource: "storage " + uuid, State: state}
+			}
 
-		time.Sleep(sleepDuration)
+			return nil
+		},
+		DryRunValue: &upcloud.StorageDetails{Storage: upcloud.Storage{UUID: uuid, State: desiredState}},
+	})
 
-		if time.Duration(attempts)*sleepDuration >= r.Timeout {
-			return nil, fmt.Errorf("timeout reached while waiting for storage to enter state \"%s\"", r.DesiredState)
-		}
+	storageDetails, _ := value.(*upcloud.StorageDetails)
+	return storageDetails, err
+}
+
+// WaitForBackupCreation blocks execution until the specified backup, as returned by CreateBackup, has been completed.
+// The method will give up after the specified timeout
+func (s *Service) WaitForBackupCreation(r *request.WaitForBackupCreationRequest) (*upcloud.StorageDetails, error) {
+	return s.WaitForBackupCreationWithContext(context.Background(), r)
+}
+
+// WaitForBackupCreationWithContext is the same as WaitForBackupCreation but binds the API request(s) to the specified context
+func (s *Service) WaitForBackupCreationWithContext(ctx context.Context, r *request.WaitForBackupCreationRequest) (*upcloud.StorageDetails, error) {
+	if err := s.validate(r); err != nil {
+		return nil, err
 	}
+
+	return s.waitForStorageState(ctx, r.UUID, upcloud.StorageStateOnline, r.Timeout)
+}
+
+// WaitForStorageCloneCompletion blocks execution until the specified clone, as returned by CloneStorage, has been
+// completed. The method will give up after the specified timeout
+func (s *Service) WaitForStorageCloneCompletion(r *request.WaitForStorageCloneCompletionRequest) (*upcloud.StorageDetails, error) {
+	return s.WaitForStorageCloneCompletionWithContext(context.Background(), r)
+}
+
+// WaitForStorageCloneCompletionWithContext is the same as WaitForStorageCloneCompletion but binds the API request(s) to the specified context
+func (s *Service) WaitForStorageCloneCompletionWithContext(ctx context.Context, r *request.WaitForStorageCloneCompletionRequest) (*upcloud.StorageDetails, error) {
+	if err := s.validate(r); err != nil {
+		return nil, err
+	}
+
+	return s.waitForStorageState(ctx, r.UUID, upcloud.StorageStateOnline, r.Timeout)
 }
 
 // LoadCDROM loads a storage as a CD-ROM in the CD-ROM device of a server
 func (s *Service) LoadCDROM(r *request.LoadCDROMRequest) (*upcloud.ServerDetails, error) {
+	return s.LoadCDROMWithContext(context.Background(), r)
+}
+
+// LoadCDROMWithContext is the same as LoadCDROM but binds the API request(s) to the specified context
+func (s *Service) LoadCDROMWithContext(ctx context.Context, r *request.LoadCDROMRequest) (*upcloud.ServerDetails, error) {
+	if err := s.validate(r); err != nil {
+		return nil, err
+	}
+
 	serverDetails := upcloud.ServerDetails{}
 	requestBody, _ := json.Marshal(r)
-	response, err := s.client.PerformJSONPostRequest(s.client.CreateRequestURL(r.RequestURL()), requestBody)
-
+	err := s.perform(ctx, http.MethodPost, r, requestBody, &serverDetails)
 	if err != nil {
-		return nil, parseJSONServiceError(err)
+		return nil, err
 	}
 
-	json.Unmarshal(response, &serverDetails)
-
 	return &serverDetails, nil
 }
 
 // EjectCDROM ejects the storage from the CD-ROM device of a server
 func (s *Service) EjectCDROM(r *request.EjectCDROMRequest) (*upcloud.ServerDetails, error) {
-	serverDetails := upcloud.ServerDetails{}
-	response, err := s.client.PerformJSONPostRequest(s.client.CreateRequestURL(r.RequestURL()), nil)
+	return s.EjectCDROMWithContext(context.Background(), r)
+}
 
-	if err != nil {
-		return nil, parseJSONServiceError(err)
+// EjectCDROMWithContext is the same as EjectCDROM but binds the API request(s) to the specified context
+func (s *Service) EjectCDROMWithContext(ctx context.Context, r *request.EjectCDROMRequest) (*upcloud.ServerDetails, error) {
+	if err := s.validate(r); err != nil {
+		return nil, err
 	}
 
-	json.Unmarshal(response, &serverDetails)
+	serverDetails := upcloud.ServerDetails{}
+	err := s.perform(ctx, http.MethodPost, r, nil, &serverDetails)
+	if err != nil {
+		return nil, err
+	}
 
 	return &serverDetails, nil
 }
 
 // CreateBackup creates a backup of the specified storage
 func (s *Service) CreateBackup(r *request.CreateBackupRequest) (*upcloud.StorageDetails, error) {
+	return s.CreateBackupWithContext(context.Background(), r)
+}
+
+// CreateBackupWithContext is the same as CreateBackup but binds the API request(s) to the specified context
+func (s *Service) CreateBackupWithContext(ctx context.Context, r *request.CreateBackupRequest) (*upcloud.StorageDetails, error) {
+	if err := s.validate(r); err != nil {
+		return nil, err
+	}
+
 	storageDetails := upcloud.StorageDetails{}
 	requestBody, _ := json.Marshal(r)
-	response, err := s.client.PerformJSONPostRequest(s.client.CreateRequestURL(r.RequestURL()), requestBody)
-
+	err := s.perform(ctx, http.MethodPost, r, requestBody, &storageDetails)
 	if err != nil {
-		return nil, parseJSONServiceError(err)
+		return nil, err
 	}
 
-	json.Unmarshal(response, &storageDetails)
-
 	return &storageDetails, nil
 }
 
 // RestoreBackup creates a backup of the specified storage
 func (s *Service) RestoreBackup(r *request.RestoreBackupRequest) error {
-	_, err := s.client.PerformJSONPostRequest(s.client.CreateRequestURL(r.RequestURL()), nil)
+	return s.RestoreBackupWithContext(context.Background(), r)
+}
 
-	if err != nil {
-		return parseJSONServiceError(err)
+// RestoreBackupWithContext is the same as RestoreBackup but binds the API request(s) to the specified context
+func (s *Service) RestoreBackupWithContext(ctx context.Context, r *request.RestoreBackupRequest) error {
+	if err := s.validate(r); err != nil {
+		return err
 	}
 
-	return nil
+	return s.perform(ctx, http.MethodPost, r, nil, nil)
 }
 
 // CreateStorageImport begins the process of importing an image onto a storage device. A `upcloud.StorageImportSourceHTTPImport` source
 // will import from an HTTP source. `upcloud.StorageImportSourceDirectUpload` will directly upload the file specified in `SourceLocation`.
 func (s *Service) CreateStorageImport(r *request.CreateStorageImportRequest) (*upcloud.StorageImportDetails, error) {
+	return s.CreateStorageImportWithContext(context.Background(), r)
+}
+
+// CreateStorageImportWithContext is the same as CreateStorageImport but binds the API request(s) to the specified context
+func (s *Service) CreateStorageImportWithContext(ctx context.Context, r *request.CreateStorageImportRequest) (*upcloud.StorageImportDetails, error) {
+	if err := s.validate(r); err != nil {
+		return nil, err
+	}
+
 	if r.Source == request.StorageImportSourceDirectUpload {
 		switch r.SourceLocation.(type) {
 		case string, io.Reader:
-			return s.directStorageImport(r)
+			return s.directStorageImport(ctx, r)
 		case nil:
 			return nil, errors.New("SourceLocation must be specified")
 		default:
@@ -264,27 +431,24 @@ func (s *Service) CreateStorageImport(r *request.CreateStorageImportRequest) (*u
 	if _, isString := r.SourceLocation.(string); !isString {
 		return nil, fmt.Errorf("unsupported storage source location type %T", r.Source)
 	}
-	return s.doCreateStorageImport(r)
+	return s.doCreateStorageImport(ctx, r)
 }
 
 // doCreateStorageImport will POST the CreateStorageImport request and handle the error and normal response.
-func (s *Service) doCreateStorageImport(r *request.CreateStorageImportRequest) (*upcloud.StorageImportDetails, error) {
+func (s *Service) doCreateStorageImport(ctx context.Context, r *request.CreateStorageImportRequest) (*upcloud.StorageImportDetails, error) {
 	storageImport := upcloud.StorageImportDetails{}
 	requestBody, _ := json.Marshal(r)
-	response, err := s.client.PerformJSONPostRequest(s.client.CreateRequestURL(r.RequestURL()), requestBody)
-
+	err := s.perform(ctx, http.MethodPost, r, requestBody, &storageImport)
 	if err != nil {
-		return nil, parseJSONServiceError(err)
+		return nil, err
 	}
 
-	json.Unmarshal(response, &storageImport)
-
 	return &storageImport, nil
 }
 
 // directStorageImport handles the direct upload logic including getting the upload URL and PUT the file data
 // to that endpoint.
-func (s *Service) directStorageImport(r *request.CreateStorageImportRequest) (*upcloud.StorageImportDetails, error) {
+func (s *Service) directStorageImport(ctx context.Context, r *request.CreateStorageImportRequest) (*upcloud.StorageImportDetails, error) {
 	var bodyReader io.Reader
 
 	switch v := r.SourceLocation.(type) {
@@ -304,27 +468,30 @@ func (s *Service) directStorageImport(r *request.CreateStorageImportRequest) (*u
 		return nil, fmt.Errorf("unsupported source location type %T", r.SourceLocation)
 	}
 
-	r.SourceLocation = ""
-	storageImport, err := s.doCreateStorageImport(r)
+	// The data is uploaded separately rather than sent in the request, which is copied to leave the caller's as it is
+	importRequest := *r
+	importRequest.SourceLocation = nil
+	storageImport, err := s.doCreateStorageImport(ctx, &importRequest)
 	if err != nil {
 		return nil, err
 	}
 
+	// The upload URL is only known once the import has been created, so the upload can't be planned
+	if s.client.GetDryRun() != nil {
+		return storageImport, nil
+	}
+
 	if storageImport.DirectUploadURL == "" {
 		return nil, errors.New("no DirectUploadURL found in response")
 	}
 
-	curContentType := s.client.GetContentType()
-	if r.ContentType != "" {
-		s.client.SetContentType(r.ContentType)
-	}
-	_, err = s.client.PerformJSONPutUploadRequest(storageImport.DirectUploadURL, bodyReader)
+	_, err = s.client.PerformJSONPutUploadRequestWithContext(ctx, storageImport.DirectUploadURL, bodyReader,
+		client.WithRequestContentType(r.ContentType))
 	if err != nil {
-		return nil, err
+		return nil, parseJSONServiceError(err)
 	}
-	s.client.SetContentType(curContentType)
 
-	storageImport, err = s.GetStorageImportDetails(&request.GetStorageImportDetailsRequest{
+	storageImport, err = s.GetStorageImportDetailsWithContext(ctx, &request.GetStorageImportDetailsRequest{
 		UUID: r.StorageUUID,
 	})
 	if err != nil {
@@ -336,56 +503,81 @@ func (s *Service) directStorageImport(r *request.CreateStorageImportRequest) (*u
 
 // GetStorageImportDetails gets updated details about the specified storage import.
 func (s *Service) GetStorageImportDetails(r *request.GetStorageImportDetailsRequest) (*upcloud.StorageImportDetails, error) {
+	return s.GetStorageImportDetailsWithContext(context.Background(), r)
+}
+
+// GetStorageImportDetailsWithContext is the same as GetStorageImportDetails but binds the API request(s) to the specified context
+func (s *Service) GetStorageImportDetailsWithContext(ctx context.Context, r *request.GetStorageImportDetailsRequest) (*upcloud.StorageImportDetails, error) {
+	if err := s.validate(r); err != nil {
+		return nil, err
+	}
+
 	storageDetails := upcloud.StorageImportDetails{}
-	response, err := s.basicGetRequest(r.RequestURL())
+	response, err := s.basicGetRequest(ctx, r.RequestURL())
 
 	if err != nil {
 		return nil, err
 	}
 
-	json.Unmarshal(response, &storageDetails)
+	err = s.unmarshal(response, &storageDetails)
+	if err != nil {
+		return nil, err
+	}
 
 	return &storageDetails, nil
 }
 
 // WaitForStorageImportCompletion waits for the importing storage to complete.
 func (s *Service) WaitForStorageImportCompletion(r *request.WaitForStorageImportCompletionRequest) (*upcloud.StorageImportDetails, error) {
-	attempts := 0
-	sleepDuration := time.Second * 5
-
-	for {
-		attempts++
+	return s.WaitForStorageImportCompletionWithContext(context.Background(), r)
+}
 
-		storageImportDetails, err := s.GetStorageImportDetails(&request.GetStorageImportDetailsRequest{
-			UUID: r.StorageUUID,
-		})
+// WaitForStorageImportCompletionWithContext is the same as WaitForStorageImportCompletion but binds the API request(s) to the specified context
+func (s *Service) WaitForStorageImportCompletionWithContext(ctx context.Context, r *request.WaitForStorageImportCompletionRequest) (*upcloud.StorageImportDetails, error) {
+	if err := s.validate(r); err != nil {
+		return nil, err
+	}
 
-		if err != nil {
-			return nil, err
-		}
+	value, err := s.WaitWithContext(ctx, &WaitCondition{
+		Resource: "storage import " + r.StorageUUID,
+		Target:   "complete",
+		Timeout:  r.Timeout,
+		Poll: func(ctx context.Context) (string, interface{}, error) {
+			storageImportDetails, err := s.GetStorageImportDetailsWithContext(ctx, &request.GetStorageImportDetailsRequest{
+				UUID: r.StorageUUID,
+			})
+			if err != nil {
+				return "", nil, err
+			}
 
-		switch storageImportDetails.State {
-		case upcloud.StorageImportStateCompleted:
-			return storageImportDetails, nil
-		case upcloud.StorageImportStateCancelled,
-			upcloud.StorageImportStateCancelling,
-			upcloud.StorageImportStateFailed:
-			if storageImportDetails.ErrorCode != "" || storageImportDetails.ErrorMessage != "" {
-				return storageImportDetails, &upcloud.Error{
-					ErrorCode:    storageImportDetails.ErrorCode,
-					ErrorMessage: storageImportDetails.ErrorMessage,
+			return storageImportDetails.State, storageImportDetails, nil
+		},
+		Done: func(state string) bool {
+			return state == upcloud.StorageImportStateCompleted
+		},
+		Failed: func(state string, value interface{}) error {
+			switch state {
+			case upcloud.StorageImportStateCancelled,
+				upcloud.StorageImportStateCancelling,
+				upcloud.StorageImportStateFailed:
+				storageImportDetails := value.(*upcloud.StorageImportDetails)
+				if storageImportDetails.ErrorCode != "" || storageImportDetails.ErrorMessage != "" {
+					return &upcloud.Error{
+						ErrorCode:    storageImportDetails.ErrorCode,
+						ErrorMessage: storageImportDetails.ErrorMessage,
+					}
+				}
+				return &upcloud.Error{
+					ErrorCode:    state,
+					ErrorMessage: "Storage Import Failed",
 				}
-			}
-			return storageImportDetails, &upcloud.Error{
-				ErrorCode:    storageImportDetails.State,
-				ErrorMessage: "Storage Import Failed",
-			}
-		default:
-			if time.Duration(attempts)*sleepDuration >= r.Timeout {
-				return nil, errors.New("timeout reached while waiting for import to complete")
 			}
 
-			time.Sleep(sleepDuration)
-		}
-	}
+			return nil
+		},
+		DryRunValue: &upcloud.StorageImportDetails{State: upcloud.StorageImportStateCompleted},
+	})
+
+	storageImportDetails, _ := value.(*upcloud.StorageImportDetails)
+	return storageImportDetails, err
 }
@@ -5,12 +5,16 @@ import (
 	"encoding/hex"
 	"fmt"
 	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
 	"os"
 	"path"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/UpCloudLtd/upcloud-go-api/upcloud"
+	"github.com/UpCloudLtd/upcloud-go-api/upcloud/client"
 	"github.com/UpCloudLtd/upcloud-go-api/upcloud/request"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -392,3 +396,34 @@ func TestDirectUploadStorageImport(t *testing.T) {
 		require.Equal(t, sha256sum, afterStorageImportDetails.SHA256Sum)
 	})
 }
+
+// TestDirectUploadStorageImportBody tests that the request creating a direct upload import doesn't send the source
+// location, which is the data to upload rather than something the API knows about, and that the request of the caller
+// isn't modified
+func TestDirectUploadStorageImportBody(t *testing.T) {
+	var body []byte
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodPost:
+			body, _ = ioutil.ReadAll(r.Body)
+			w.Write([]byte(`{"storage_import":{"state":"prepared","direct_upload_url":"http://` + r.Host + `/upload"}}`))
+		case r.URL.Path == "/upload":
+			w.Write([]byte(`{}`))
+		default:
+			w.Write([]byte(`{"storage_import":{"state":"pending"}}`))
+		}
+	}))
+	defer srv.Close()
+
+	svc := New(client.NewWithOptions("username", "password", client.WithBaseURL(srv.URL)))
+	source := strings.NewReader("image")
+	r := &request.CreateStorageImportRequest{
+		StorageUUID:    "0140c4d3-a9a3-4e5c-b2f5-50e3da8bc2ca",
+		Source:         request.StorageImportSourceDirectUpload,
+		SourceLocation: source,
+	}
+	_, err := svc.CreateStorageImport(r)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"storage_import":{"source":"direct_upload"}}`, string(body))
+	assert.Equal(t, source, r.SourceLocation)
+}
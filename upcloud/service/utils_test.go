@@ -2,7 +2,6 @@ package service
 
 import (
 	"log"
-	"net/http"
 	"os"
 	"strings"
 	"testing"
@@ -11,10 +10,8 @@ import (
 	"github.com/UpCloudLtd/upcloud-go-api/upcloud"
 	"github.com/UpCloudLtd/upcloud-go-api/upcloud/client"
 	"github.com/UpCloudLtd/upcloud-go-api/upcloud/request"
-	"github.com/dnaeon/go-vcr/cassette"
-	"github.com/dnaeon/go-vcr/recorder"
+	"github.com/UpCloudLtd/upcloud-go-api/upcloud/upcloudtest"
 	"github.com/hashicorp/go-cleanhttp"
-	"github.com/stretchr/testify/require"
 )
 
 // Configures the test environment
@@ -29,32 +26,24 @@ func getService() *Service {
 
 // records the API interactions of the test
 func record(t *testing.T, fixture string, f func(*testing.T, *Service)) {
-	r, err := recorder.New("fixtures/" + fixture)
-	require.NoError(t, err)
-
-	r.AddFilter(func(i *cassette.Interaction) error {
-		delete(i.Request.Headers, "Authorization")
-		if i.Request.Method == http.MethodPut && strings.Contains(i.Request.URL, "uploader") {
-			// We will remove the body from the upload to reduce fixture size
-			i.Request.Body = ""
-		}
-		return nil
-	})
-
-	defer func() {
-		err := r.Stop()
-		require.NoError(t, err)
-	}()
-
 	user, password := getCredentials()
+	r := upcloudtest.Start(t, "fixtures/"+fixture,
+		upcloudtest.WithCredentials(user, password),
+		upcloudtest.WithTransport(cleanhttp.DefaultTransport()),
+	)
+
+	svc := New(r.Client(client.WithTimeout(time.Second * 300)))
+
+	// Replayed interactions return immediately, so there is no need to wait between polls
+	if r.Replaying() {
+		svc.SetWaitPolicy(&WaitPolicy{
+			MinInterval:        time.Millisecond,
+			MaxInterval:        time.Millisecond,
+			MaxTransientErrors: 3,
+		})
+	}
 
-	httpClient := cleanhttp.DefaultClient()
-	httpClient.Transport = r
-
-	c := client.NewWithHTTPClient(user, password, httpClient)
-	c.SetTimeout(time.Second * 300)
-
-	f(t, New(c))
+	f(t, svc)
 }
 
 // Tears down the test environment by removing all resources
@@ -0,0 +1,258 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"time"
+
+	"github.com/UpCloudLtd/upcloud-go-api/upcloud"
+)
+
+// WaitPolicy describes how often the state of a resource is polled while waiting for it to change. The interval
+// between two polls starts at MinInterval and is multiplied by Multiplier after every poll, up to MaxInterval.
+type WaitPolicy struct {
+	// MinInterval is the delay between the first and the second poll. Zero or a negative value uses the interval of
+	// the default policy rather than polling in a tight loop.
+	MinInterval time.Duration
+	// MaxInterval caps the delay between two polls. Values below MinInterval disable the cap.
+	MaxInterval time.Duration
+	// Multiplier is the factor the interval grows by after every poll. Values below 1 keep the interval constant.
+	Multiplier float64
+	// MaxTransientErrors is the number of consecutive transient errors (transport errors, 429 Too Many Requests and
+	// 5xx responses) that are tolerated before giving up. Any other error ends the wait immediately.
+	MaxTransientErrors int
+	// OnProgress, when set, is called after every poll
+	OnProgress func(WaitProgress)
+}
+
+// WaitProgress describes the outcome of a single poll
+type WaitProgress struct {
+	// Resource describes the resource that is waited for, e.g. "server 0077fa3d-32db-4b09-9f5f-30d9e9afb565"
+	Resource string
+	// Attempt is the number of the poll, starting from 1
+	Attempt int
+	// Elapsed is the time since the wait started
+	Elapsed time.Duration
+	// State is the state of the resource, or empty if the poll failed
+	State string
+	// Err is the error of a failed poll
+	Err error
+}
+
+// DefaultWaitPolicy returns the wait policy used when none has been set. It polls every five seconds at first and
+// backs off to every 30 seconds for long running operations.
+func DefaultWaitPolicy() *WaitPolicy {
+	return &WaitPolicy{
+		MinInterval:        5 * time.Second,
+		MaxInterval:        30 * time.Second,
+		Multiplier:         1.5,
+		MaxTransientErrors: 3,
+	}
+}
+
+// Returns the delay between the first and the second poll
+func (p *WaitPolicy) firstInterval() time.Duration {
+	if p.MinInterval <= 0 {
+		return DefaultWaitPolicy().MinInterval
+	}
+
+	return p.MinInterval
+}
+
+// Returns the delay that follows the specified delay
+func (p *WaitPolicy) nextInterval(interval time.Duration) time.Duration {
+	if p.Multiplier > 1 {
+		interval = time.Duration(float64(interval) * p.Multiplier)
+	}
+
+	if p.MaxInterval >= p.firstInterval() && interval > p.MaxInterval {
+		return p.MaxInterval
+	}
+
+	return interval
+}
+
+// WaitCondition describes a resource to wait for and the state it's expected to reach
+type WaitCondition struct {
+	// Resource describes the resource in progress reports and errors
+	Resource string
+	// Target describes the expected outcome in errors, e.g. `enter state "started"`
+	Target string
+	// Timeout is the maximum time to wait
+	Timeout time.Duration
+	// DelayFirstPoll postpones the first poll by one interval. This is useful when the resource might not have
+	// left its current state yet.
+	DelayFirstPoll bool
+	// Poll returns the current state of the resource along with the value returned when the wait ends
+	Poll func(ctx context.Context) (state string, value interface{}, err error)
+	// Done returns whether the wait has succeeded
+	Done func(state string) bool
+	// Failed, when set, returns a non-nil error if the resource is in a state it won't recover from. It's only
+	// called for states that aren't done.
+	Failed func(state string, value interface{}) error
+	// DryRunValue is returned right away when the client is in dry-run mode, typically a placeholder of the resource
+	// in the expected state
+	DryRunValue interface{}
+}
+
+// TerminalStateError is returned when a resource enters a state it won't recover from while it's waited for
+type TerminalStateError struct {
+	Resource string
+	State    string
+}
+
+// Error implements the Error interface
+func (e *TerminalStateError) Error() string {
+	return fmt.Sprintf("%s entered state \"%s\"", e.Resource, e.State)
+}
+
+// WaitTimeoutError is returned when a resource doesn't reach the expected state in time
+type WaitTimeoutError struct {
+	Resource string
+	Target   string
+	Timeout  time.Duration
+	// State is the last state that was seen, if any
+	State string
+}
+
+// Error implements the Error interface
+func (e *WaitTimeoutError) Error() string {
+	if e.State == "" {
+		return fmt.Sprintf("timeout reached while waiting for %s to %s", e.Resource, e.Target)
+	}
+
+	return fmt.Sprintf("timeout reached while waiting for %s to %s, last state \"%s\"", e.Resource, e.Target, e.State)
+}
+
+// SetWaitPolicy sets the policy used by Wait and the WaitFor methods. Passing nil restores the default policy.
+func (s *Service) SetWaitPolicy(policy *WaitPolicy) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.waitPolicy = policy
+}
+
+// Returns the wait policy in use
+func (s *Service) getWaitPolicy() *WaitPolicy {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if s.waitPolicy == nil {
+		return DefaultWaitPolicy()
+	}
+
+	return s.waitPolicy
+}
+
+// Wait polls a resource until the specified condition is met, the resource enters a terminal state or the timeout
+// is reached. The value of the last poll is returned when the condition is met. When the resource enters a terminal
+// state the value is returned along with the error of the condition.
+func (s *Service) Wait(c *WaitCondition) (interface{}, error) {
+	return s.WaitWithContext(context.Background(), c)
+}
+
+// WaitWithContext is the same as Wait but binds the API request(s) to the specified context
+func (s *Service) WaitWithContext(ctx context.Context, c *WaitCondition) (interface{}, error) {
+	// Nothing changes in dry-run mode, so waiting would only run into the timeout
+	if s.client.GetDryRun() != nil {
+		return c.DryRunValue, nil
+	}
+
+	policy := s.getWaitPolicy()
+	start := time.Now()
+	interval := policy.firstInterval()
+	transientErrors := 0
+	lastState := ""
+
+	if c.DelayFirstPoll {
+		if err := sleepWithContext(ctx, interval); err != nil {
+			return nil, err
+		}
+		interval = policy.nextInterval(interval)
+	}
+
+	for attempt := 1; ; attempt++ {
+		state, value, err := c.Poll(ctx)
+
+		if policy.OnProgress != nil {
+			policy.OnProgress(WaitProgress{
+				Resource: c.Resource,
+				Attempt:  attempt,
+				Elapsed:  time.Since(start),
+				State:    state,
+				Err:      err,
+			})
+		}
+
+		if err != nil {
+			if ctx.Err() != nil || !isTransientError(err) {
+				return nil, err
+			}
+
+			transientErrors++
+			if transientErrors > policy.MaxTransientErrors {
+				return nil, err
+			}
+		} else {
+			transientErrors = 0
+			lastState = state
+
+			if c.Done(state) {
+				return value, nil
+			}
+
+			if c.Failed != nil {
+				if err := c.Failed(state, value); err != nil {
+					return value, err
+				}
+			}
+		}
+
+		remaining := c.Timeout - time.Since(start)
+		if remaining <= 0 {
+			return nil, &WaitTimeoutError{
+				Resource: c.Resource,
+				Target:   c.Target,
+				Timeout:  c.Timeout,
+				State:    lastState,
+			}
+		}
+
+		// Poll one last time when the timeout is reached rather than giving up early
+		delay := interval
+		if delay > remaining {
+			delay = remaining
+		}
+
+		if err := sleepWithContext(ctx, delay); err != nil {
+			return nil, err
+		}
+		interval = policy.nextInterval(interval)
+	}
+}
+
+// Returns whether the error is likely to go away by itself
+func isTransientError(err error) bool {
+	if errors.Is(err, upcloud.ErrServerError) || errors.Is(err, upcloud.ErrRateLimited) {
+		return true
+	}
+
+	var netErr net.Error
+
+	return errors.As(err, &netErr)
+}
+
+// Pauses for the specified duration or until the context is done, whichever happens first
+func sleepWithContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
@@ -0,0 +1,366 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/UpCloudLtd/upcloud-go-api/upcloud"
+	"github.com/UpCloudLtd/upcloud-go-api/upcloud/client"
+	"github.com/UpCloudLtd/upcloud-go-api/upcloud/request"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Returns a service talking to a fake API that responds with the specified responses in order, repeating the last
+// one once they run out. A response is either a body or an HTTP status code.
+func newScriptedService(t *testing.T, responses ...interface{}) *Service {
+	var mu sync.Mutex
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		response := responses[0]
+		if len(responses) > 1 {
+			responses = responses[1:]
+		}
+		mu.Unlock()
+
+		switch v := response.(type) {
+		case int:
+			w.WriteHeader(v)
+		case string:
+			w.Write([]byte(v))
+		}
+	}))
+	t.Cleanup(srv.Close)
+
+	c := client.NewWithOptions("username", "password", client.WithBaseURL(srv.URL), client.WithRetryPolicy(nil))
+	svc := New(c)
+	svc.SetWaitPolicy(fastWaitPolicy())
+
+	return svc
+}
+
+// Returns a wait policy suitable for tests
+func fastWaitPolicy() *WaitPolicy {
+	return &WaitPolicy{
+		MinInterval:        time.Millisecond,
+		MaxInterval:        5 * time.Millisecond,
+		Multiplier:         2,
+		MaxTransientErrors: 2,
+	}
+}
+
+const (
+	serverInMaintenance = `{"server":{"uuid":"0077fa3d-32db-4b09-9f5f-30d9e9afb565","state":"maintenance"}}`
+	serverStarted       = `{"server":{"uuid":"0077fa3d-32db-4b09-9f5f-30d9e9afb565","state":"started"}}`
+	serverInError       = `{"server":{"uuid":"0077fa3d-32db-4b09-9f5f-30d9e9afb565","state":"error"}}`
+)
+
+// TestWaitPolicyBackoff tests that the interval between polls grows up to the maximum
+func TestWaitPolicyBackoff(t *testing.T) {
+	policy := &WaitPolicy{
+		MinInterval: time.Second,
+		MaxInterval: 5 * time.Second,
+		Multiplier:  2,
+	}
+
+	var intervals []time.Duration
+	for interval := policy.MinInterval; len(intervals) < 5; interval = policy.nextInterval(interval) {
+		intervals = append(intervals, interval)
+	}
+	assert.Equal(t, []time.Duration{time.Second, 2 * time.Second, 4 * time.Second, 5 * time.Second, 5 * time.Second},
+		intervals)
+
+	policy.Multiplier = 0
+	assert.Equal(t, time.Second, policy.nextInterval(time.Second))
+}
+
+// TestWaitPolicyZeroMinInterval tests that a policy without a minimum interval doesn't poll in a tight loop
+func TestWaitPolicyZeroMinInterval(t *testing.T) {
+	for _, interval := range []time.Duration{0, -time.Second} {
+		policy := &WaitPolicy{MinInterval: interval, MaxInterval: time.Minute, Multiplier: 2}
+		assert.Equal(t, DefaultWaitPolicy().MinInterval, policy.firstInterval())
+		assert.Equal(t, 2*DefaultWaitPolicy().MinInterval, policy.nextInterval(policy.firstInterval()))
+	}
+
+	svc := newScriptedService(t, serverInMaintenance)
+	svc.SetWaitPolicy(&WaitPolicy{})
+
+	var polls int
+	_, err := svc.Wait(&WaitCondition{
+		Resource: "server 0077fa3d-32db-4b09-9f5f-30d9e9afb565",
+		Target:   `enter state "started"`,
+		Timeout:  100 * time.Millisecond,
+		Poll: func(ctx context.Context) (string, interface{}, error) {
+			polls++
+			return upcloud.ServerStateMaintenance, nil, nil
+		},
+		Done: func(state string) bool {
+			return state == upcloud.ServerStateStarted
+		},
+	})
+	var timeoutError *WaitTimeoutError
+	require.True(t, errors.As(err, &timeoutError))
+	// The first poll, and the last one when the timeout is reached
+	assert.Equal(t, 2, polls)
+}
+
+// TestWaitForServerState tests that waiting for a server polls until the desired state is reached and reports
+// the progress
+func TestWaitForServerState(t *testing.T) {
+	svc := newScriptedService(t, serverInMaintenance, serverInMaintenance, serverStarted)
+
+	var progress []WaitProgress
+	policy := fastWaitPolicy()
+	policy.OnProgress = func(p WaitProgress) {
+		progress = append(progress, p)
+	}
+	svc.SetWaitPolicy(policy)
+
+	serverDetails, err := svc.WaitForServerState(&request.WaitForServerStateRequest{
+		UUID:         "0077fa3d-32db-4b09-9f5f-30d9e9afb565",
+		DesiredState: upcloud.ServerStateStarted,
+		Timeout:      time.Second,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, upcloud.ServerStateStarted, serverDetails.State)
+
+	require.Len(t, progress, 3)
+	for i, state := range []string{"maintenance", "maintenance", "started"} {
+		assert.Equal(t, "server 0077fa3d-32db-4b09-9f5f-30d9e9afb565", progress[i].Resource)
+		assert.Equal(t, i+1, progress[i].Attempt)
+		assert.Equal(t, state, progress[i].State)
+		assert.NoError(t, progress[i].Err)
+	}
+}
+
+// TestWaitForServerStateUndesiredState tests that waiting for a server to leave a state succeeds as soon as the
+// state changes
+func TestWaitForServerStateUndesiredState(t *testing.T) {
+	svc := newScriptedService(t, serverInMaintenance, serverInError)
+
+	serverDetails, err := svc.WaitForServerState(&request.WaitForServerStateRequest{
+		UUID:           "0077fa3d-32db-4b09-9f5f-30d9e9afb565",
+		UndesiredState: upcloud.ServerStateMaintenance,
+		Timeout:        time.Second,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, upcloud.ServerStateError, serverDetails.State)
+}
+
+// TestWaitForServerStateNoState tests that waiting for a server without a desired or an undesired state fails
+// without polling the server, whether requests are validated or not
+func TestWaitForServerStateNoState(t *testing.T) {
+	svc := newScriptedService(t, serverStarted)
+
+	for _, validate := range []bool{false, true} {
+		svc.SetRequestValidation(validate)
+		serverDetails, err := svc.WaitForServerState(&request.WaitForServerStateRequest{
+			UUID:    "0077fa3d-32db-4b09-9f5f-30d9e9afb565",
+			Timeout: time.Second,
+		})
+		assert.Nil(t, serverDetails)
+		assert.Error(t, err)
+	}
+}
+
+// TestWaitForServerStateTerminalState tests that waiting for a server fails fast when the server enters the error
+// state
+func TestWaitForServerStateTerminalState(t *testing.T) {
+	svc := newScriptedService(t, serverInMaintenance, serverInError, serverStarted)
+
+	serverDetails, err := svc.WaitForServerState(&request.WaitForServerStateRequest{
+		UUID:         "0077fa3d-32db-4b09-9f5f-30d9e9afb565",
+		DesiredState: upcloud.ServerStateStarted,
+		Timeout:      time.Second,
+	})
+	require.Error(t, err)
+
+	var terminalStateError *TerminalStateError
+	require.True(t, errors.As(err, &terminalStateError))
+	assert.Equal(t, upcloud.ServerStateError, terminalStateError.State)
+	assert.EqualError(t, err, `server 0077fa3d-32db-4b09-9f5f-30d9e9afb565 entered state "error"`)
+	assert.Equal(t, upcloud.ServerStateError, serverDetails.State)
+}
+
+// TestWaitForServerStateTimeout tests that waiting for a server gives up after the timeout
+func TestWaitForServerStateTimeout(t *testing.T) {
+	svc := newScriptedService(t, serverInMaintenance)
+
+	start := time.Now()
+	serverDetails, err := svc.WaitForServerState(&request.WaitForServerStateRequest{
+		UUID:         "0077fa3d-32db-4b09-9f5f-30d9e9afb565",
+		DesiredState: upcloud.ServerStateStarted,
+		Timeout:      50 * time.Millisecond,
+	})
+	assert.Nil(t, serverDetails)
+	assert.True(t, time.Since(start) >= 50*time.Millisecond)
+
+	var timeoutError *WaitTimeoutError
+	require.True(t, errors.As(err, &timeoutError))
+	assert.EqualError(t, err, `timeout reached while waiting for server 0077fa3d-32db-4b09-9f5f-30d9e9afb565 `+
+		`to enter state "started", last state "maintenance"`)
+}
+
+// TestWaitTransientErrors tests that transient errors are tolerated up to the limit of the wait policy
+func TestWaitTransientErrors(t *testing.T) {
+	svc := newScriptedService(t, serverInMaintenance, http.StatusServiceUnavailable, http.StatusTooManyRequests,
+		serverStarted)
+
+	serverDetails, err := svc.WaitForServerState(&request.WaitForServerStateRequest{
+		UUID:         "0077fa3d-32db-4b09-9f5f-30d9e9afb565",
+		DesiredState: upcloud.ServerStateStarted,
+		Timeout:      time.Second,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, upcloud.ServerStateStarted, serverDetails.State)
+
+	svc = newScriptedService(t, serverInMaintenance, http.StatusBadGateway, http.StatusBadGateway,
+		http.StatusBadGateway, serverStarted)
+
+	_, err = svc.WaitForServerState(&request.WaitForServerStateRequest{
+		UUID:         "0077fa3d-32db-4b09-9f5f-30d9e9afb565",
+		DesiredState: upcloud.ServerStateStarted,
+		Timeout:      time.Second,
+	})
+	assert.True(t, errors.Is(err, upcloud.ErrServerError))
+}
+
+// TestWaitPermanentErrors tests that errors that aren't transient end the wait immediately
+func TestWaitPermanentErrors(t *testing.T) {
+	svc := newScriptedService(t, http.StatusNotFound, serverStarted)
+
+	_, err := svc.WaitForServerState(&request.WaitForServerStateRequest{
+		UUID:         "0077fa3d-32db-4b09-9f5f-30d9e9afb565",
+		DesiredState: upcloud.ServerStateStarted,
+		Timeout:      time.Second,
+	})
+	assert.True(t, errors.Is(err, upcloud.ErrNotFound))
+}
+
+// TestWaitCancelledContext tests that waiting stops when the context is cancelled
+func TestWaitCancelledContext(t *testing.T) {
+	svc := newScriptedService(t, serverInMaintenance)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	_, err := svc.WaitForServerStateWithContext(ctx, &request.WaitForServerStateRequest{
+		UUID:         "0077fa3d-32db-4b09-9f5f-30d9e9afb565",
+		DesiredState: upcloud.ServerStateStarted,
+		Timeout:      time.Minute,
+	})
+	assert.True(t, errors.Is(err, context.DeadlineExceeded))
+}
+
+// TestWaitForStorageStateTerminalState tests that waiting for a storage fails fast when the storage enters the
+// error state
+func TestWaitForStorageStateTerminalState(t *testing.T) {
+	svc := newScriptedService(t,
+		`{"storage":{"uuid":"01d4fcd4-e446-433b-8a9c-551a1284952e","state":"maintenance"}}`,
+		`{"storage":{"uuid":"01d4fcd4-e446-433b-8a9c-551a1284952e","state":"error"}}`,
+	)
+
+	_, err := svc.WaitForStorageState(&request.WaitForStorageStateRequest{
+		UUID:         "01d4fcd4-e446-433b-8a9c-551a1284952e",
+		DesiredState: upcloud.StorageStateOnline,
+		Timeout:      time.Second,
+	})
+	assert.EqualError(t, err, `storage 01d4fcd4-e446-433b-8a9c-551a1284952e entered state "error"`)
+}
+
+// TestWaitForStorageImportCompletionFailed tests that a failed import is returned along with its error
+func TestWaitForStorageImportCompletionFailed(t *testing.T) {
+	svc := newScriptedService(t,
+		`{"storage_import":{"state":"pending"}}`,
+		`{"storage_import":{"state":"failed","error_code":"IMPORT_FAILED","error_message":"Download failed"}}`,
+	)
+
+	storageImportDetails, err := svc.WaitForStorageImportCompletion(&request.WaitForStorageImportCompletionRequest{
+		StorageUUID: "01d4fcd4-e446-433b-8a9c-551a1284952e",
+		Timeout:     time.Second,
+	})
+	assert.EqualError(t, err, "Download failed (IMPORT_FAILED)")
+	require.NotNil(t, storageImportDetails)
+	assert.Equal(t, upcloud.StorageImportStateFailed, storageImportDetails.State)
+}
+
+// TestWaitForBackupCreation tests that waiting for a backup returns once the backup is online
+func TestWaitForBackupCreation(t *testing.T) {
+	svc := newScriptedService(t,
+		`{"storage":{"uuid":"01d4fcd4-e446-433b-8a9c-551a1284952e","type":"backup","state":"maintenance"}}`,
+		`{"storage":{"uuid":"01d4fcd4-e446-433b-8a9c-551a1284952e","type":"backup","state":"online"}}`,
+	)
+
+	storageDetails, err := svc.WaitForBackupCreation(&request.WaitForBackupCreationRequest{
+		UUID:    "01d4fcd4-e446-433b-8a9c-551a1284952e",
+		Timeout: time.Second,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, upcloud.StorageStateOnline, storageDetails.State)
+}
+
+// TestWaitForStorageCloneCompletion tests that waiting for a clone returns once the clone is online
+func TestWaitForStorageCloneCompletion(t *testing.T) {
+	svc := newScriptedService(t,
+		`{"storage":{"uuid":"01d4fcd4-e446-433b-8a9c-551a1284952e","state":"maintenance"}}`,
+		`{"storage":{"uuid":"01d4fcd4-e446-433b-8a9c-551a1284952e","state":"cloning"}}`,
+		`{"storage":{"uuid":"01d4fcd4-e446-433b-8a9c-551a1284952e","state":"online"}}`,
+	)
+
+	storageDetails, err := svc.WaitForStorageCloneCompletion(&request.WaitForStorageCloneCompletionRequest{
+		UUID:    "01d4fcd4-e446-433b-8a9c-551a1284952e",
+		Timeout: time.Second,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, upcloud.StorageStateOnline, storageDetails.State)
+}
+
+// TestWaitForObjectStorageState tests that waiting for an Object Storage returns once the desired state is reached
+func TestWaitForObjectStorageState(t *testing.T) {
+	svc := newScriptedService(t,
+		`{"object_storage":{"uuid":"06b0e4fc-d74b-455e-a373-60cd6ca84022","state":"stopped"}}`,
+		`{"object_storage":{"uuid":"06b0e4fc-d74b-455e-a373-60cd6ca84022","state":"started"}}`,
+	)
+
+	objectStorageDetails, err := svc.WaitForObjectStorageState(&request.WaitForObjectStorageStateRequest{
+		UUID:         "06b0e4fc-d74b-455e-a373-60cd6ca84022",
+		DesiredState: upcloud.ObjectStorageStateStarted,
+		Timeout:      time.Second,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, upcloud.ObjectStorageStateStarted, objectStorageDetails.State)
+}
+
+// TestWaitCustomCondition tests that Wait can be used to wait for resources that have no dedicated method
+func TestWaitCustomCondition(t *testing.T) {
+	svc := newScriptedService(t,
+		`{"network":{"uuid":"03e4970d-7791-4b80-a892-682ae0faf46b","name":"Old name"}}`,
+		`{"network":{"uuid":"03e4970d-7791-4b80-a892-682ae0faf46b","name":"New name"}}`,
+	)
+
+	value, err := svc.Wait(&WaitCondition{
+		Resource: "network 03e4970d-7791-4b80-a892-682ae0faf46b",
+		Target:   "be renamed",
+		Timeout:  time.Second,
+		Poll: func(ctx context.Context) (string, interface{}, error) {
+			network, err := svc.GetNetworkDetailsWithContext(ctx, &request.GetNetworkDetailsRequest{
+				UUID: "03e4970d-7791-4b80-a892-682ae0faf46b",
+			})
+			if err != nil {
+				return "", nil, err
+			}
+
+			return network.Name, network, nil
+		},
+		Done: func(state string) bool {
+			return state == "New name"
+		},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "New name", value.(*upcloud.Network).Name)
+}
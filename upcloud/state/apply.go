@@ -0,0 +1,486 @@
+package state
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/UpCloudLtd/upcloud-go-api/upcloud"
+	"github.com/UpCloudLtd/upcloud-go-api/upcloud/request"
+	"github.com/UpCloudLtd/upcloud-go-api/upcloud/service"
+)
+
+// stopTimeout is how long a server is given to shut down before it's stopped forcibly
+const stopTimeout = time.Minute
+
+// applier applies the changes of a plan, keeping track of the UUIDs of the resources as they're created and deleted
+type applier struct {
+	ctx     context.Context
+	svc     service.API
+	timeout time.Duration
+	spec    *Spec
+	// networks and storages are the UUIDs of the networks and the storages by name and title
+	networks map[string]string
+	storages map[string]string
+	// managed are the UUIDs of the storages of the spec, including those that are deleted
+	managed map[string]bool
+}
+
+// Returns an applier starting from the current resources of a plan
+func newApplier(ctx context.Context, e *Engine, plan *Plan) *applier {
+	a := &applier{
+		ctx:      ctx,
+		svc:      e.svc,
+		timeout:  e.timeout,
+		spec:     plan.spec,
+		networks: make(map[string]string),
+		storages: make(map[string]string),
+		managed:  make(map[string]bool),
+	}
+	for name, uuid := range plan.networks {
+		a.networks[name] = uuid
+	}
+	for title, uuid := range plan.storages {
+		a.storages[title] = uuid
+		a.managed[uuid] = true
+	}
+
+	return a
+}
+
+// Applies the part of a change that belongs to a step
+func (a *applier) apply(c *Change, step int) error {
+	deleting := step <= stepDeleteNetworks
+
+	switch c.Kind {
+	case KindNetwork:
+		switch {
+		case deleting:
+			return a.deleteNetwork(c)
+		case c.Action == Modify:
+			return a.modifyNetwork(c)
+		default:
+			return a.createNetwork(c)
+		}
+	case KindStorage:
+		switch {
+		case deleting:
+			return a.deleteStorage(c)
+		case c.Action == Modify:
+			return a.modifyStorage(c)
+		default:
+			return a.createStorage(c)
+		}
+	case KindServer:
+		switch {
+		case deleting:
+			return a.deleteServer(c)
+		case c.Action == Modify:
+			return a.modifyServer(c)
+		default:
+			return a.createServer(c)
+		}
+	}
+
+	return fmt.Errorf("unknown kind %s", c.Kind)
+}
+
+func (a *applier) createNetwork(c *Change) error {
+	network, err := a.svc.CreateNetworkWithContext(a.ctx, &request.CreateNetworkRequest{
+		Name:       c.network.Name,
+		Zone:       a.spec.zone(c.network.Zone),
+		IPNetworks: ipNetworks(c.network),
+	})
+	if err != nil {
+		return err
+	}
+
+	a.networks[c.Name] = network.UUID
+	return nil
+}
+
+func (a *applier) modifyNetwork(c *Change) error {
+	_, err := a.svc.ModifyNetworkWithContext(a.ctx, &request.ModifyNetworkRequest{
+		UUID:       c.UUID,
+		IPNetworks: ipNetworks(c.network),
+	})
+
+	return err
+}
+
+func (a *applier) deleteNetwork(c *Change) error {
+	err := a.svc.DeleteNetworkWithContext(a.ctx, &request.DeleteNetworkRequest{UUID: c.UUID})
+	if err != nil {
+		return err
+	}
+
+	delete(a.networks, c.Name)
+	return nil
+}
+
+func (a *applier) createStorage(c *Change) error {
+	storage, err := a.svc.CreateStorageWithContext(a.ctx, &request.CreateStorageRequest{
+		Title: c.storage.Title,
+		Zone:  a.spec.zone(c.storage.Zone),
+		Size:  c.storage.Size,
+		Tier:  c.storage.Tier,
+	})
+	if err != nil {
+		return err
+	}
+
+	a.storages[c.Name] = storage.UUID
+	a.managed[storage.UUID] = true
+	return a.waitForStorage(storage.UUID)
+}
+
+// Grows a storage, stopping the server it's attached to for the duration
+func (a *applier) modifyStorage(c *Change) error {
+	storage, err := a.svc.GetStorageDetailsWithContext(a.ctx, &request.GetStorageDetailsRequest{UUID: c.UUID})
+	if err != nil {
+		return err
+	}
+
+	modify := func() error {
+		_, err := a.svc.ModifyStorageWithContext(a.ctx, &request.ModifyStorageRequest{UUID: c.UUID, Size: c.storage.Size})
+		if err != nil {
+			return err
+		}
+		return a.waitForStorage(c.UUID)
+	}
+
+	for _, uuid := range storage.ServerUUIDs {
+		server, err := a.svc.GetServerDetailsWithContext(a.ctx, &request.GetServerDetailsRequest{UUID: uuid})
+		if err != nil {
+			return err
+		}
+		inner := modify
+		modify = func() error { return a.whileStopped(server, inner) }
+	}
+
+	return modify()
+}
+
+// Deletes a storage, detaching it from the server it's attached to first
+func (a *applier) deleteStorage(c *Change) error {
+	storage, err := a.svc.GetStorageDetailsWithContext(a.ctx, &request.GetStorageDetailsRequest{UUID: c.UUID})
+	if err != nil {
+		return err
+	}
+
+	for _, uuid := range storage.ServerUUIDs {
+		server, err := a.svc.GetServerDetailsWithContext(a.ctx, &request.GetServerDetailsRequest{UUID: uuid})
+		if err != nil {
+			return err
+		}
+		err = a.whileStopped(server, func() error {
+			return a.detach(server, map[string]bool{c.UUID: true})
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	if err := a.svc.DeleteStorageWithContext(a.ctx, &request.DeleteStorageRequest{UUID: c.UUID}); err != nil {
+		return err
+	}
+
+	delete(a.storages, c.Name)
+	return nil
+}
+
+func (a *applier) createServer(c *Change) error {
+	sv := c.server
+	r := &request.CreateServerRequest{
+		Zone:             a.spec.zone(sv.Zone),
+		Title:            sv.Title,
+		Hostname:         sv.hostname(),
+		Plan:             sv.Plan,
+		PasswordDelivery: request.PasswordDeliveryNone,
+		StorageDevices: request.CreateServerStorageDeviceSlice{{
+			Action:  request.CreateServerStorageDeviceActionClone,
+			Storage: c.template,
+			Title:   sv.Title + " OS",
+			Size:    sv.OS.Size,
+			Tier:    sv.OS.Tier,
+		}},
+	}
+	for _, title := range sv.Storages {
+		r.StorageDevices = append(r.StorageDevices, request.CreateServerStorageDevice{
+			Action:  request.CreateServerStorageDeviceActionAttach,
+			Storage: a.storages[title],
+		})
+	}
+	if sv.Networks != nil {
+		r.Networking = &request.CreateServerNetworking{}
+		for _, iface := range sv.Networks {
+			r.Networking.Interfaces = append(r.Networking.Interfaces, request.CreateServerInterface{
+				Type:        iface.Type,
+				Network:     a.networks[iface.Network],
+				IPAddresses: request.CreateServerIPAddressSlice{{Family: iface.family()}},
+			})
+		}
+	}
+	if sv.Firewall != nil {
+		r.Firewall = "on"
+	}
+
+	server, err := a.svc.CreateServerWithContext(a.ctx, r)
+	if err != nil {
+		return err
+	}
+	if err := a.waitForServer(server.UUID, upcloud.ServerStateStarted); err != nil {
+		return err
+	}
+
+	if tags := serverTags(a.spec, sv); len(tags) > 0 {
+		_, err := a.svc.TagServerWithContext(a.ctx, &request.TagServerRequest{UUID: server.UUID, Tags: tags})
+		if err != nil {
+			return err
+		}
+	}
+
+	if sv.Firewall != nil && len(sv.Firewall.Rules) > 0 {
+		return a.syncFirewallRules(server.UUID, sv.Firewall.Rules, request.FirewallRulesSyncModeReplace)
+	}
+
+	return nil
+}
+
+// Modifies a server, stopping it for the duration when its plan changes or storages are attached or detached
+func (a *applier) modifyServer(c *Change) error {
+	sv := c.server
+	server, err := a.svc.GetServerDetailsWithContext(a.ctx, &request.GetServerDetailsRequest{UUID: c.UUID})
+	if err != nil {
+		return err
+	}
+
+	modify := request.ModifyServerRequest{UUID: c.UUID}
+	if server.Hostname != sv.hostname() {
+		modify.Hostname = sv.hostname()
+	}
+	if sv.Firewall != nil && server.Firewall != "on" {
+		modify.Firewall = "on"
+	}
+	resize := sv.Plan != "" && server.Plan != sv.Plan
+	if resize {
+		modify.Plan = sv.Plan
+	}
+
+	desired := make(map[string]bool)
+	for _, title := range sv.Storages {
+		desired[a.storages[title]] = true
+	}
+	detach := make(map[string]bool)
+	for _, device := range server.StorageDevices {
+		if a.managed[device.UUID] && !desired[device.UUID] {
+			detach[device.UUID] = true
+		}
+		delete(desired, device.UUID)
+	}
+
+	update := func() error {
+		if modify != (request.ModifyServerRequest{UUID: c.UUID}) {
+			if _, err := a.svc.ModifyServerWithContext(a.ctx, &modify); err != nil {
+				return err
+			}
+		}
+		if err := a.detach(server, detach); err != nil {
+			return err
+		}
+		for _, title := range sv.Storages {
+			uuid := a.storages[title]
+			if !desired[uuid] {
+				continue
+			}
+			_, err := a.svc.AttachStorageWithContext(a.ctx, &request.AttachStorageRequest{
+				ServerUUID:  c.UUID,
+				StorageUUID: uuid,
+				Type:        upcloud.StorageTypeDisk,
+			})
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	if resize || len(detach) > 0 || len(desired) > 0 {
+		err = a.whileStopped(server, update)
+	} else {
+		err = update()
+	}
+	if err != nil {
+		return err
+	}
+
+	if err := a.updateTags(server, serverTags(a.spec, sv)); err != nil {
+		return err
+	}
+
+	if sv.Firewall == nil {
+		return nil
+	}
+
+	return a.syncFirewallRules(c.UUID, sv.Firewall.Rules, request.FirewallRulesSyncModeIncremental)
+}
+
+// Deletes a server along with the OS disk the engine created for it. The other storages attached to the server are
+// kept, whether they're in the spec or not.
+func (a *applier) deleteServer(c *Change) error {
+	server, err := a.svc.GetServerDetailsWithContext(a.ctx, &request.GetServerDetailsRequest{UUID: c.UUID})
+	if err != nil {
+		return err
+	}
+
+	if server.State != upcloud.ServerStateStopped {
+		if err := a.stopServer(c.UUID); err != nil {
+			return err
+		}
+	}
+
+	if err := a.svc.DeleteServerWithContext(a.ctx, &request.DeleteServerRequest{UUID: c.UUID}); err != nil {
+		return err
+	}
+
+	for _, device := range server.StorageDevices {
+		if device.Type != upcloud.StorageTypeDisk || device.Title != server.Title+" OS" || a.managed[device.UUID] {
+			continue
+		}
+		if err := a.svc.DeleteStorageWithContext(a.ctx, &request.DeleteStorageRequest{UUID: device.UUID}); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Detaches the storages of a stopped server that are in the set
+func (a *applier) detach(server *upcloud.ServerDetails, storages map[string]bool) error {
+	for _, device := range server.StorageDevices {
+		if !storages[device.UUID] {
+			continue
+		}
+		_, err := a.svc.DetachStorageWithContext(a.ctx, &request.DetachStorageRequest{
+			ServerUUID: server.UUID,
+			Address:    device.Address,
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Tags and untags a server so that it has the specified tags
+func (a *applier) updateTags(server *upcloud.ServerDetails, tags []string) error {
+	var add, remove []string
+	for _, tag := range tags {
+		if !hasTag(server.Tags, tag) {
+			add = append(add, tag)
+		}
+	}
+	for _, tag := range server.Tags {
+		if !hasTag(tags, tag) {
+			remove = append(remove, tag)
+		}
+	}
+
+	if len(add) > 0 {
+		if _, err := a.svc.TagServerWithContext(a.ctx, &request.TagServerRequest{UUID: server.UUID, Tags: add}); err != nil {
+			return err
+		}
+	}
+	if len(remove) > 0 {
+		if _, err := a.svc.UntagServerWithContext(a.ctx, &request.UntagServerRequest{UUID: server.UUID, Tags: remove}); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Syncs the firewall rules of a server to the rules of the spec
+func (a *applier) syncFirewallRules(uuid string, rules []FirewallRuleSpec, mode string) error {
+	_, err := a.svc.SyncFirewallRulesWithContext(a.ctx, &request.SyncFirewallRulesRequest{
+		ServerUUID:    uuid,
+		FirewallRules: firewallRules(rules),
+		Mode:          mode,
+	})
+
+	return err
+}
+
+// Runs f with the server stopped and starts the server again if it was running
+func (a *applier) whileStopped(server *upcloud.ServerDetails, f func() error) error {
+	running := server.State != upcloud.ServerStateStopped
+	if running {
+		if err := a.stopServer(server.UUID); err != nil {
+			return err
+		}
+	}
+
+	if err := f(); err != nil {
+		return err
+	}
+
+	if !running {
+		return nil
+	}
+	if _, err := a.svc.StartServerWithContext(a.ctx, &request.StartServerRequest{UUID: server.UUID}); err != nil {
+		return err
+	}
+
+	return a.waitForServer(server.UUID, upcloud.ServerStateStarted)
+}
+
+func (a *applier) stopServer(uuid string) error {
+	_, err := a.svc.StopServerWithContext(a.ctx, &request.StopServerRequest{
+		UUID:     uuid,
+		StopType: upcloud.StopTypeSoft,
+		Timeout:  stopTimeout,
+	})
+	if err != nil {
+		return err
+	}
+
+	return a.waitForServer(uuid, upcloud.ServerStateStopped)
+}
+
+func (a *applier) waitForServer(uuid, state string) error {
+	_, err := a.svc.WaitForServerStateWithContext(a.ctx, &request.WaitForServerStateRequest{
+		UUID:         uuid,
+		DesiredState: state,
+		Timeout:      a.timeout,
+	})
+
+	return err
+}
+
+func (a *applier) waitForStorage(uuid string) error {
+	_, err := a.svc.WaitForStorageStateWithContext(a.ctx, &request.WaitForStorageStateRequest{
+		UUID:         uuid,
+		DesiredState: upcloud.StorageStateOnline,
+		Timeout:      a.timeout,
+	})
+
+	return err
+}
+
+// Returns the IP networks of a network spec
+func ipNetworks(n *NetworkSpec) upcloud.IPNetworkSlice {
+	var networks upcloud.IPNetworkSlice
+	for _, ip := range n.IPNetworks {
+		networks = append(networks, upcloud.IPNetwork{
+			Address:          ip.Address,
+			Family:           ip.Family,
+			DHCP:             upcloud.FromBool(ip.DHCP),
+			DHCPDefaultRoute: upcloud.FromBool(ip.DHCPDefaultRoute),
+			DHCPDns:          ip.DHCPDNS,
+			Gateway:          ip.Gateway,
+		})
+	}
+
+	return networks
+}
@@ -0,0 +1,196 @@
+package state
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/UpCloudLtd/upcloud-go-api/upcloud"
+	"github.com/UpCloudLtd/upcloud-go-api/upcloud/request"
+)
+
+// current is the current state of the resources a spec refers to
+type current struct {
+	// networks are the private networks by name
+	networks map[string]*upcloud.Network
+	// storages are the normal storages by title
+	storages map[string]*upcloud.Storage
+	// servers are the servers in the spec by title
+	servers map[string]*serverState
+	// pruned are the servers with the tag of the spec that aren't in the spec
+	pruned []*serverState
+	// templates are the UUIDs of the templates by title, nil until a template is given by title
+	templates map[string]string
+}
+
+// serverState is the current state of a server
+type serverState struct {
+	*upcloud.ServerDetails
+	// rules are the firewall rules of the server, only read when the spec manages them
+	rules []upcloud.FirewallRule
+}
+
+// Reads the current state of the resources of a spec
+func (e *Engine) readCurrent(ctx context.Context, spec *Spec) (*current, error) {
+	c := &current{
+		networks: make(map[string]*upcloud.Network),
+		storages: make(map[string]*upcloud.Storage),
+		servers:  make(map[string]*serverState),
+	}
+
+	if len(spec.Networks) > 0 {
+		networks, err := e.svc.GetNetworksWithContext(ctx)
+		if err != nil {
+			return nil, err
+		}
+		wanted := make(map[string]bool)
+		for _, n := range spec.Networks {
+			wanted[n.Name] = true
+		}
+		for i := range networks.Networks {
+			n := &networks.Networks[i]
+			if n.Type != upcloud.NetworkTypePrivate || !wanted[n.Name] {
+				continue
+			}
+			if c.networks[n.Name] != nil {
+				return nil, fmt.Errorf("network %q: the name is ambiguous, there are several networks with it", n.Name)
+			}
+			c.networks[n.Name] = n
+		}
+	}
+
+	if len(spec.Storages) > 0 {
+		storages, err := e.svc.GetStoragesWithContext(ctx, &request.GetStoragesRequest{Type: upcloud.StorageTypeNormal})
+		if err != nil {
+			return nil, err
+		}
+		wanted := make(map[string]bool)
+		for _, st := range spec.Storages {
+			wanted[st.Title] = true
+		}
+		for i := range storages.Storages {
+			st := &storages.Storages[i]
+			if st.Access != upcloud.StorageAccessPrivate || !wanted[st.Title] {
+				continue
+			}
+			if c.storages[st.Title] != nil {
+				return nil, fmt.Errorf("storage %q: the title is ambiguous, there are several storages with it", st.Title)
+			}
+			c.storages[st.Title] = st
+		}
+	}
+
+	if err := e.readServers(ctx, spec, c); err != nil {
+		return nil, err
+	}
+
+	return c, nil
+}
+
+// Reads the details of the servers in the spec and of the servers to prune
+func (e *Engine) readServers(ctx context.Context, spec *Spec, c *current) error {
+	if len(spec.Servers) == 0 && spec.Tag == "" {
+		return nil
+	}
+
+	servers, err := e.svc.GetServersWithContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	wanted := make(map[string]*ServerSpec)
+	for i := range spec.Servers {
+		wanted[spec.Servers[i].Title] = &spec.Servers[i]
+	}
+
+	for _, server := range servers.Servers {
+		if spec.Tag != "" && !hasTag(server.Tags, spec.Tag) {
+			continue
+		}
+		sv := wanted[server.Title]
+		if sv == nil && spec.Tag == "" {
+			continue
+		}
+		if sv != nil && c.servers[server.Title] != nil {
+			return fmt.Errorf("server %q: the title is ambiguous, there are several servers with it", server.Title)
+		}
+
+		details, err := e.svc.GetServerDetailsWithContext(ctx, &request.GetServerDetailsRequest{UUID: server.UUID})
+		if err != nil {
+			return err
+		}
+		state := &serverState{ServerDetails: details}
+
+		if sv == nil {
+			c.pruned = append(c.pruned, state)
+			continue
+		}
+
+		if sv.Firewall != nil && !sv.Absent {
+			rules, err := e.svc.GetFirewallRulesWithContext(ctx, &request.GetFirewallRulesRequest{ServerUUID: server.UUID})
+			if err != nil {
+				return err
+			}
+			state.rules = rules.FirewallRules
+		}
+		c.servers[server.Title] = state
+	}
+
+	return nil
+}
+
+// Returns the UUID of a template given by UUID or by title. The templates are listed the first time one is given by
+// title, so that only the servers that are created look them up.
+func (e *Engine) resolveTemplate(ctx context.Context, c *current, template string) (string, error) {
+	if isUUID(template) {
+		return template, nil
+	}
+
+	if c.templates == nil {
+		templates, err := e.svc.GetStoragesWithContext(ctx, &request.GetStoragesRequest{Type: upcloud.StorageTypeTemplate})
+		if err != nil {
+			return "", err
+		}
+		c.templates = make(map[string]string)
+		for _, t := range templates.Storages {
+			c.templates[t.Title] = t.UUID
+		}
+	}
+
+	uuid := c.templates[template]
+	if uuid == "" {
+		return "", fmt.Errorf("template %q doesn't exist", template)
+	}
+
+	return uuid, nil
+}
+
+// Returns whether the tags contain a tag
+func hasTag(tags []string, tag string) bool {
+	for _, t := range tags {
+		if t == tag {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Returns whether a string has the format of a UUID
+func isUUID(s string) bool {
+	if len(s) != 36 {
+		return false
+	}
+
+	for i, r := range s {
+		switch {
+		case i == 8 || i == 13 || i == 18 || i == 23:
+			if r != '-' {
+				return false
+			}
+		case !('0' <= r && r <= '9' || 'a' <= r && r <= 'f' || 'A' <= r && r <= 'F'):
+			return false
+		}
+	}
+
+	return true
+}
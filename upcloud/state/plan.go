@@ -0,0 +1,476 @@
+package state
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/UpCloudLtd/upcloud-go-api/upcloud"
+	"github.com/UpCloudLtd/upcloud-go-api/upcloud/service"
+)
+
+// Action is what a change does to a resource
+type Action string
+
+// The actions of the changes
+const (
+	Create  Action = "create"
+	Modify  Action = "modify"
+	Replace Action = "replace"
+	Delete  Action = "delete"
+)
+
+// Kind is the type of a resource
+type Kind string
+
+// The kinds of the resources
+const (
+	KindNetwork Kind = "network"
+	KindStorage Kind = "storage"
+	KindServer  Kind = "server"
+)
+
+// Change is a change to a resource
+type Change struct {
+	Action Action
+	Kind   Kind
+	// Name is the name of a network or the title of a storage or a server
+	Name string
+	// UUID is the UUID of the existing resource, empty for the resources that are created
+	UUID string
+	// Diffs are the differences between the current and the desired state of a resource that is modified or
+	// replaced
+	Diffs []Diff
+
+	network *NetworkSpec
+	storage *StorageSpec
+	server  *ServerSpec
+	// template is the UUID of the template of a server that is created
+	template string
+}
+
+// String returns the change as a line starting with a symbol of the action followed by a line for each difference
+func (c Change) String() string {
+	symbols := map[Action]string{Create: "+", Modify: "~", Replace: "-/+", Delete: "-"}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s %s %s %s", symbols[c.Action], c.Action, c.Kind, c.Name)
+	for _, d := range c.Diffs {
+		fmt.Fprintf(&b, "\n    %s", d)
+	}
+
+	return b.String()
+}
+
+// Diff is a difference between the current and the desired state of a resource
+type Diff struct {
+	Field   string
+	Current string
+	Desired string
+	// Replace is whether the difference can only be resolved by replacing the resource
+	Replace bool
+}
+
+// String returns the difference as "field: current -> desired", with empty values as "(none)"
+func (d Diff) String() string {
+	none := func(s string) string {
+		if s == "" {
+			return "(none)"
+		}
+		return s
+	}
+
+	s := fmt.Sprintf("%s: %s -> %s", d.Field, none(d.Current), none(d.Desired))
+	if d.Replace {
+		s += " (replaces)"
+	}
+
+	return s
+}
+
+// Plan is the changes that bring the resources to the state of a spec, ordered by their dependencies: servers are
+// deleted before the storages and the networks they use, and networks and storages are created before the servers
+// using them. The resources that are replaced are deleted when the resources of their kind are deleted and created
+// again when those are created.
+type Plan struct {
+	Changes []Change
+
+	spec *Spec
+	// networks and storages are the UUIDs of the current networks and storages by name and title
+	networks map[string]string
+	storages map[string]string
+}
+
+// Empty returns whether the resources are in the state of the spec
+func (p *Plan) Empty() bool {
+	return len(p.Changes) == 0
+}
+
+// Drift returns the changes to the existing resources, i.e. how they differ from the spec
+func (p *Plan) Drift() []Change {
+	var drift []Change
+	for _, c := range p.Changes {
+		if c.UUID != "" {
+			drift = append(drift, c)
+		}
+	}
+
+	return drift
+}
+
+// String returns the changes of the plan in order, one per line
+func (p *Plan) String() string {
+	var b strings.Builder
+	for _, c := range p.Changes {
+		b.WriteString(c.String())
+		b.WriteString("\n")
+	}
+
+	return b.String()
+}
+
+// The steps a plan is applied in. A change is applied in the step of its action and kind, a replacement both in the
+// step deleting and the step creating the resources of its kind.
+const (
+	stepDeleteServers = iota
+	stepDeleteStorages
+	stepDeleteNetworks
+	stepCreateNetworks
+	stepCreateStorages
+	stepCreateServers
+	steps
+)
+
+// Returns whether a change is applied in a step
+func (c *Change) inStep(step int) bool {
+	deleting := map[Kind]int{KindServer: stepDeleteServers, KindStorage: stepDeleteStorages, KindNetwork: stepDeleteNetworks}
+	creating := map[Kind]int{KindServer: stepCreateServers, KindStorage: stepCreateStorages, KindNetwork: stepCreateNetworks}
+
+	switch c.Action {
+	case Delete:
+		return step == deleting[c.Kind]
+	case Replace:
+		return step == deleting[c.Kind] || step == creating[c.Kind]
+	default:
+		return step == creating[c.Kind]
+	}
+}
+
+// Returns the first step a change is applied in
+func (c *Change) firstStep() int {
+	for step := 0; step < steps; step++ {
+		if c.inStep(step) {
+			return step
+		}
+	}
+
+	return steps
+}
+
+// Returns the last step a change is applied in
+func (c *Change) lastStep() int {
+	for step := steps - 1; step >= 0; step-- {
+		if c.inStep(step) {
+			return step
+		}
+	}
+
+	return -1
+}
+
+// Computes the changes that bring the current state to the state of the spec
+func (e *Engine) newPlan(ctx context.Context, spec *Spec, cur *current) (*Plan, error) {
+	p := &Plan{
+		spec:     spec,
+		networks: make(map[string]string),
+		storages: make(map[string]string),
+	}
+	for name, n := range cur.networks {
+		p.networks[name] = n.UUID
+	}
+	for title, st := range cur.storages {
+		p.storages[title] = st.UUID
+	}
+
+	// The networks and the storages that the servers can keep using
+	kept := make(map[string]bool)
+
+	for i := range spec.Networks {
+		n := &spec.Networks[i]
+		existing := cur.networks[n.Name]
+		c := Change{Kind: KindNetwork, Name: n.Name, network: n}
+		switch {
+		case n.Absent && existing == nil:
+			continue
+		case n.Absent:
+			c.Action, c.UUID = Delete, existing.UUID
+		case existing == nil:
+			c.Action = Create
+		default:
+			c.UUID = existing.UUID
+			c.Diffs = networkDiffs(spec, n, existing)
+			c.Action = action(c.Diffs)
+			if c.Action != Replace {
+				kept[existing.UUID] = true
+			}
+		}
+		if c.Action != "" {
+			p.Changes = append(p.Changes, c)
+		}
+	}
+
+	for i := range spec.Storages {
+		st := &spec.Storages[i]
+		existing := cur.storages[st.Title]
+		c := Change{Kind: KindStorage, Name: st.Title, storage: st}
+		switch {
+		case st.Absent && existing == nil:
+			continue
+		case st.Absent:
+			c.Action, c.UUID = Delete, existing.UUID
+		case existing == nil:
+			c.Action = Create
+		default:
+			c.UUID = existing.UUID
+			c.Diffs = storageDiffs(spec, st, existing)
+			c.Action = action(c.Diffs)
+			if c.Action != Replace {
+				kept[existing.UUID] = true
+			}
+		}
+		if c.Action != "" {
+			p.Changes = append(p.Changes, c)
+		}
+	}
+
+	for i := range spec.Servers {
+		sv := &spec.Servers[i]
+		existing := cur.servers[sv.Title]
+		c := Change{Kind: KindServer, Name: sv.Title, server: sv}
+		switch {
+		case sv.Absent && existing == nil:
+			continue
+		case sv.Absent:
+			c.Action, c.UUID = Delete, existing.UUID
+		case existing == nil:
+			c.Action = Create
+		default:
+			c.UUID = existing.UUID
+			c.Diffs = serverDiffs(spec, sv, existing, cur, kept)
+			c.Action = action(c.Diffs)
+		}
+		if c.Action == Create || c.Action == Replace {
+			if sv.OS == nil || sv.OS.Template == "" {
+				return nil, fmt.Errorf("server %q: os.template is required to create the server", sv.Title)
+			}
+			template, err := e.resolveTemplate(ctx, cur, sv.OS.Template)
+			if err != nil {
+				return nil, err
+			}
+			c.template = template
+		}
+		if c.Action != "" {
+			p.Changes = append(p.Changes, c)
+		}
+	}
+
+	for _, server := range cur.pruned {
+		p.Changes = append(p.Changes, Change{Action: Delete, Kind: KindServer, Name: server.Title, UUID: server.UUID})
+	}
+
+	sort.SliceStable(p.Changes, func(i, j int) bool {
+		return p.Changes[i].firstStep() < p.Changes[j].firstStep()
+	})
+
+	return p, nil
+}
+
+// Returns the action resolving the differences
+func action(diffs []Diff) Action {
+	if len(diffs) == 0 {
+		return ""
+	}
+
+	for _, d := range diffs {
+		if d.Replace {
+			return Replace
+		}
+	}
+
+	return Modify
+}
+
+// Returns how a network differs from its spec
+func networkDiffs(spec *Spec, n *NetworkSpec, existing *upcloud.Network) []Diff {
+	var diffs []Diff
+	if zone := spec.zone(n.Zone); existing.Zone != zone {
+		diffs = append(diffs, Diff{Field: "zone", Current: existing.Zone, Desired: zone, Replace: true})
+	}
+
+	if len(existing.IPNetworks) != len(n.IPNetworks) {
+		return append(diffs, Diff{
+			Field:   "ip_networks",
+			Current: fmt.Sprintf("%d networks", len(existing.IPNetworks)),
+			Desired: fmt.Sprintf("%d networks", len(n.IPNetworks)),
+			Replace: true,
+		})
+	}
+
+	for i, desired := range n.IPNetworks {
+		current := existing.IPNetworks[i]
+		field := fmt.Sprintf("ip_networks[%d].", i)
+		diffs = appendDiff(diffs, field+"address", current.Address, desired.Address, true)
+		diffs = appendDiff(diffs, field+"family", current.Family, desired.Family, true)
+		diffs = appendDiff(diffs, field+"dhcp", fmt.Sprint(current.DHCP.Bool()), fmt.Sprint(desired.DHCP), false)
+		diffs = appendDiff(diffs, field+"dhcp_default_route",
+			fmt.Sprint(current.DHCPDefaultRoute.Bool()), fmt.Sprint(desired.DHCPDefaultRoute), false)
+		if desired.DHCPDNS != nil {
+			diffs = appendDiff(diffs, field+"dhcp_dns",
+				strings.Join(current.DHCPDns, ","), strings.Join(desired.DHCPDNS, ","), false)
+		}
+		if desired.Gateway != "" {
+			diffs = appendDiff(diffs, field+"gateway", current.Gateway, desired.Gateway, false)
+		}
+	}
+
+	return diffs
+}
+
+// Returns how a storage differs from its spec
+func storageDiffs(spec *Spec, st *StorageSpec, existing *upcloud.Storage) []Diff {
+	var diffs []Diff
+	diffs = appendDiff(diffs, "zone", existing.Zone, spec.zone(st.Zone), true)
+	if st.Tier != "" {
+		diffs = appendDiff(diffs, "tier", existing.Tier, st.Tier, true)
+	}
+	// Storages can only grow
+	diffs = appendDiff(diffs, "size", fmt.Sprint(existing.Size), fmt.Sprint(st.Size), st.Size < existing.Size)
+
+	return diffs
+}
+
+// Returns how a server differs from its spec. The storages and networks are compared by the names they have in the
+// spec, and only those that are kept count as attached.
+func serverDiffs(spec *Spec, sv *ServerSpec, existing *serverState, cur *current, kept map[string]bool) []Diff {
+	var diffs []Diff
+	diffs = appendDiff(diffs, "zone", existing.Zone, spec.zone(sv.Zone), true)
+
+	if sv.Networks != nil {
+		names := make(map[string]string)
+		for name, n := range cur.networks {
+			if kept[n.UUID] {
+				names[n.UUID] = name
+			}
+		}
+		var current, desired []string
+		for _, iface := range existing.Networking.Interfaces {
+			network := ""
+			if iface.Type == upcloud.NetworkTypePrivate {
+				network = iface.Network
+				if name, ok := names[iface.Network]; ok {
+					network = name
+				}
+			}
+			family := upcloud.IPAddressFamilyIPv4
+			if len(iface.IPAddresses) > 0 {
+				family = iface.IPAddresses[0].Family
+			}
+			current = append(current, describeInterface(iface.Type, network, family))
+		}
+		for _, iface := range sv.Networks {
+			desired = append(desired, describeInterface(iface.Type, iface.Network, iface.family()))
+		}
+		diffs = appendDiff(diffs, "networks", strings.Join(current, ", "), strings.Join(desired, ", "), true)
+	}
+
+	diffs = appendDiff(diffs, "hostname", existing.Hostname, sv.hostname(), false)
+	if sv.Plan != "" {
+		diffs = appendDiff(diffs, "plan", existing.Plan, sv.Plan, false)
+	}
+
+	diffs = appendDiff(diffs, "tags", joinSorted(existing.Tags), joinSorted(serverTags(spec, sv)), false)
+
+	titles := make(map[string]string)
+	for title, st := range cur.storages {
+		if kept[st.UUID] {
+			titles[st.UUID] = title
+		}
+	}
+	var attached []string
+	for _, device := range existing.StorageDevices {
+		if title, ok := titles[device.UUID]; ok {
+			attached = append(attached, title)
+		}
+	}
+	diffs = appendDiff(diffs, "storages", joinSorted(attached), joinSorted(sv.Storages), false)
+
+	if sv.Firewall != nil {
+		current := "off"
+		if existing.Firewall == "on" {
+			current = fmt.Sprintf("%d rules", len(existing.rules))
+		}
+		desired := fmt.Sprintf("%d rules", len(sv.Firewall.Rules))
+		if current == desired && !sameRules(existing.rules, sv.Firewall.Rules) {
+			desired = fmt.Sprintf("%d changed rules", len(sv.Firewall.Rules))
+		}
+		diffs = appendDiff(diffs, "firewall", current, desired, false)
+	}
+
+	return diffs
+}
+
+// Appends a difference unless the values are equal
+func appendDiff(diffs []Diff, field, current, desired string, replace bool) []Diff {
+	if current == desired {
+		return diffs
+	}
+
+	return append(diffs, Diff{Field: field, Current: current, Desired: desired, Replace: replace})
+}
+
+// Returns an interface as "type", "type network" or either with the family unless it's IPv4
+func describeInterface(interfaceType, network, family string) string {
+	s := interfaceType
+	if network != "" {
+		s += " " + network
+	}
+	if family != upcloud.IPAddressFamilyIPv4 {
+		s += " " + family
+	}
+
+	return s
+}
+
+// Returns the tags a server of the spec has
+func serverTags(spec *Spec, sv *ServerSpec) []string {
+	tags := append([]string(nil), sv.Tags...)
+	if spec.Tag != "" && !hasTag(tags, spec.Tag) {
+		tags = append(tags, spec.Tag)
+	}
+
+	return tags
+}
+
+// Returns the sorted values separated by commas
+func joinSorted(values []string) string {
+	sorted := append([]string(nil), values...)
+	sort.Strings(sorted)
+
+	return strings.Join(sorted, ",")
+}
+
+// Returns whether the current rules are the rules of the spec, ignoring their positions
+func sameRules(current []upcloud.FirewallRule, desired []FirewallRuleSpec) bool {
+	return len(service.DiffFirewallRules(current, firewallRules(desired))) == 0
+}
+
+// Returns the firewall rules of the spec
+func firewallRules(specs []FirewallRuleSpec) []upcloud.FirewallRule {
+	rules := make([]upcloud.FirewallRule, len(specs))
+	for i, rule := range specs {
+		rules[i] = rule.FirewallRule()
+	}
+
+	return rules
+}
@@ -0,0 +1,167 @@
+package state
+
+import (
+	"context"
+	"testing"
+
+	"github.com/UpCloudLtd/upcloud-go-api/upcloud/request"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestPlan tests that the changes are ordered by their dependencies and that replacing a network replaces the
+// servers in it
+func TestPlan(t *testing.T) {
+	e, svc := newTestEngine(t)
+	apply(t, e, loadTestSpec(t, nil))
+
+	// A server with the tag of the spec that isn't in the spec is deleted
+	old, err := svc.CreateServer(&request.CreateServerRequest{
+		Zone:             "fi-hel2",
+		Title:            "old",
+		Hostname:         "old.example.com",
+		PasswordDelivery: request.PasswordDeliveryNone,
+		StorageDevices: request.CreateServerStorageDeviceSlice{{
+			Action:  request.CreateServerStorageDeviceActionClone,
+			Storage: "01000000-0000-4000-8000-000030060200",
+			Title:   "old OS",
+		}},
+	})
+	require.NoError(t, err)
+	_, err = svc.TagServer(&request.TagServerRequest{UUID: old.UUID, Tags: []string{"PROD"}})
+	require.NoError(t, err)
+
+	spec := loadTestSpec(t, func(s *Spec) {
+		s.Networks[0].IPNetworks[0].Address = "10.1.0.0/24"
+		s.Storages[0].Size = 30
+		s.Servers[0].Plan = "2xCPU-4GB"
+		s.Servers = append(s.Servers, ServerSpec{
+			Title: "web-2",
+			OS:    &OSSpec{Template: "01000000-0000-4000-8000-000030060200"},
+		})
+	})
+	plan, err := e.Plan(context.Background(), spec)
+	require.NoError(t, err)
+
+	network := serverDetails(t, svc, "web-1").Networking.Interfaces[2].Network
+	assert.Equal(t, `-/+ replace server web-1
+    networks: public, utility, private `+network+`, public IPv6 -> public, utility, private backend, public IPv6 (replaces)
+    plan: 1xCPU-2GB -> 2xCPU-4GB
+- delete server old
+-/+ replace network backend
+    ip_networks[0].address: 10.0.0.0/24 -> 10.1.0.0/24 (replaces)
+~ modify storage data
+    size: 20 -> 30
++ create server web-2
+`, plan.String())
+
+	var drift []string
+	for _, c := range plan.Drift() {
+		drift = append(drift, c.Name)
+	}
+	assert.Equal(t, []string{"web-1", "old", "backend", "data"}, drift)
+
+	result, err := e.Apply(context.Background(), plan)
+	require.NoError(t, err)
+	assert.Empty(t, result.Drift)
+	assert.Len(t, result.Applied, 5)
+
+	servers, err := svc.GetServers()
+	require.NoError(t, err)
+	var titles []string
+	for _, server := range servers.Servers {
+		titles = append(titles, server.Title)
+	}
+	assert.ElementsMatch(t, []string{"web-1", "web-2"}, titles)
+}
+
+// TestPlanUnmanaged tests that without a tag only the servers in the spec are considered and that templates are
+// looked up by title
+func TestPlanUnmanaged(t *testing.T) {
+	e, svc := newTestEngine(t)
+
+	_, err := svc.CreateServer(&request.CreateServerRequest{
+		Zone:             "fi-hel2",
+		Title:            "other",
+		Hostname:         "other.example.com",
+		PasswordDelivery: request.PasswordDeliveryNone,
+		StorageDevices: request.CreateServerStorageDeviceSlice{{
+			Action:  request.CreateServerStorageDeviceActionClone,
+			Storage: "01000000-0000-4000-8000-000030060200",
+			Title:   "other OS",
+		}},
+	})
+	require.NoError(t, err)
+
+	spec := &Spec{
+		Zone: "fi-hel2",
+		Servers: []ServerSpec{
+			{Title: "web-1", OS: &OSSpec{Template: "Debian GNU/Linux 10 (Buster)"}},
+			{Title: "web-2", Absent: true},
+		},
+	}
+	plan, err := e.Plan(context.Background(), spec)
+	require.NoError(t, err)
+	require.Len(t, plan.Changes, 1)
+	assert.Equal(t, Create, plan.Changes[0].Action)
+	assert.Equal(t, "01000000-0000-4000-8000-000020050100", plan.Changes[0].template)
+	assert.Empty(t, plan.Drift())
+
+	spec.Servers[0].OS.Template = "Windows"
+	_, err = e.Plan(context.Background(), spec)
+	assert.EqualError(t, err, `template "Windows" doesn't exist`)
+
+	spec.Servers[0].OS = nil
+	_, err = e.Plan(context.Background(), spec)
+	assert.EqualError(t, err, `server "web-1": os.template is required to create the server`)
+
+	// Servers are matched by title
+	_, err = svc.CreateServer(&request.CreateServerRequest{
+		Zone:             "fi-hel2",
+		Title:            "other",
+		Hostname:         "other.example.com",
+		PasswordDelivery: request.PasswordDeliveryNone,
+		StorageDevices: request.CreateServerStorageDeviceSlice{{
+			Action:  request.CreateServerStorageDeviceActionClone,
+			Storage: "01000000-0000-4000-8000-000030060200",
+		}},
+	})
+	require.NoError(t, err)
+	_, err = e.Plan(context.Background(), &Spec{Zone: "fi-hel2", Servers: []ServerSpec{{Title: "other"}}})
+	assert.EqualError(t, err, `server "other": the title is ambiguous, there are several servers with it`)
+
+	_, err = e.Plan(context.Background(), &Spec{Servers: []ServerSpec{{Title: "other"}}})
+	assert.EqualError(t, err, `server "other": zone is required`)
+}
+
+// TestPlanUnknownTemplate tests that the templates are only looked up for the servers that are created or replaced
+func TestPlanUnknownTemplate(t *testing.T) {
+	e, svc := newTestEngine(t)
+
+	_, err := svc.CreateServer(&request.CreateServerRequest{
+		Zone:             "fi-hel2",
+		Title:            "web-1",
+		Hostname:         "web-1",
+		PasswordDelivery: request.PasswordDeliveryNone,
+		StorageDevices: request.CreateServerStorageDeviceSlice{{
+			Action:  request.CreateServerStorageDeviceActionClone,
+			Storage: "01000000-0000-4000-8000-000030060200",
+			Title:   "web-1 OS",
+		}},
+	})
+	require.NoError(t, err)
+
+	// The template of the server has since been renamed or removed
+	spec := &Spec{
+		Zone:    "fi-hel2",
+		Servers: []ServerSpec{{Title: "web-1", Plan: "2xCPU-4GB", OS: &OSSpec{Template: "Ubuntu Server 16.04 LTS"}}},
+	}
+	plan, err := e.Plan(context.Background(), spec)
+	require.NoError(t, err)
+	require.Len(t, plan.Changes, 1)
+	assert.Equal(t, Modify, plan.Changes[0].Action)
+
+	spec.Servers[0].Zone = "de-fra1"
+	_, err = e.Plan(context.Background(), spec)
+	assert.EqualError(t, err, `template "Ubuntu Server 16.04 LTS" doesn't exist`)
+}
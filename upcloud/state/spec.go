@@ -0,0 +1,320 @@
+package state
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"io/ioutil"
+
+	"github.com/UpCloudLtd/upcloud-go-api/upcloud"
+	"gopkg.in/yaml.v2"
+)
+
+// Spec is the desired state of the resources of an environment
+type Spec struct {
+	// Zone is the zone of the resources that don't specify one
+	Zone string `yaml:"zone"`
+	// Tag marks the servers of the environment. When set, only the servers with the tag are managed: they're matched
+	// by title among each other, and those missing from the spec are deleted. The servers get the tag when they're
+	// created.
+	Tag string `yaml:"tag"`
+
+	Networks []NetworkSpec `yaml:"networks"`
+	Storages []StorageSpec `yaml:"storages"`
+	Servers  []ServerSpec  `yaml:"servers"`
+}
+
+// NetworkSpec is the desired state of a private network, identified by its name
+type NetworkSpec struct {
+	Name       string          `yaml:"name"`
+	Zone       string          `yaml:"zone"`
+	IPNetworks []IPNetworkSpec `yaml:"ip_networks"`
+	// Absent deletes the network
+	Absent bool `yaml:"absent"`
+}
+
+// IPNetworkSpec is an IP network of a private network. Changing the address or the family replaces the network.
+type IPNetworkSpec struct {
+	Address          string `yaml:"address"`
+	Family           string `yaml:"family"`
+	DHCP             bool   `yaml:"dhcp"`
+	DHCPDefaultRoute bool   `yaml:"dhcp_default_route"`
+	// DHCPDNS and Gateway are left as they are unless set
+	DHCPDNS []string `yaml:"dhcp_dns"`
+	Gateway string   `yaml:"gateway"`
+}
+
+// StorageSpec is the desired state of a private storage, identified by its title. Growing the storage modifies it,
+// any other change of the size, the tier or the zone replaces it.
+type StorageSpec struct {
+	Title string `yaml:"title"`
+	Zone  string `yaml:"zone"`
+	// Size is the size in gigabytes
+	Size int    `yaml:"size"`
+	Tier string `yaml:"tier"`
+	// Absent deletes the storage
+	Absent bool `yaml:"absent"`
+}
+
+// ServerSpec is the desired state of a server, identified by its title. Changing the zone or the network interfaces
+// replaces the server, the other changes modify it.
+type ServerSpec struct {
+	Title string `yaml:"title"`
+	// Hostname defaults to the title
+	Hostname string `yaml:"hostname"`
+	Zone     string `yaml:"zone"`
+	Plan     string `yaml:"plan"`
+	// OS is the system disk of the server. It's only used when the server is created.
+	OS *OSSpec `yaml:"os"`
+	// Storages are the titles of the storages of the spec attached to the server
+	Storages []string `yaml:"storages"`
+	// Networks are the network interfaces of the server. The interfaces are left as they are unless set, and new
+	// servers get the default interfaces of the API.
+	Networks []InterfaceSpec `yaml:"networks"`
+	// Tags are the tags of the server besides the tag of the spec
+	Tags []string `yaml:"tags"`
+	// Firewall enables the firewall of the server with its rules. The firewall is left as it is unless set.
+	Firewall *FirewallSpec `yaml:"firewall"`
+	// Absent deletes the server and the storages attached to it that aren't in the spec
+	Absent bool `yaml:"absent"`
+}
+
+// OSSpec is the system disk a server is created with
+type OSSpec struct {
+	// Template is the UUID or the title of the template cloned to the disk
+	Template string `yaml:"template"`
+	// Size is the size in gigabytes
+	Size int    `yaml:"size"`
+	Tier string `yaml:"tier"`
+}
+
+// InterfaceSpec is a network interface of a server. In YAML an interface can also be written as "public", "utility"
+// or the name of a private network of the spec.
+type InterfaceSpec struct {
+	// Type is public, utility or private
+	Type string `yaml:"type"`
+	// Network is the name of the private network of the spec the interface is in
+	Network string `yaml:"network"`
+	// Family is the IP address family of the interface, IPv4 by default
+	Family string `yaml:"family"`
+}
+
+// UnmarshalYAML implements yaml.Unmarshaler for the short form of the interfaces
+func (i *InterfaceSpec) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	var name string
+	if err := unmarshal(&name); err == nil {
+		switch name {
+		case upcloud.NetworkTypePublic, upcloud.NetworkTypeUtility:
+			*i = InterfaceSpec{Type: name}
+		default:
+			*i = InterfaceSpec{Type: upcloud.NetworkTypePrivate, Network: name}
+		}
+		return nil
+	}
+
+	type plain InterfaceSpec
+	return unmarshal((*plain)(i))
+}
+
+// FirewallSpec is the firewall of a server
+type FirewallSpec struct {
+	Rules []FirewallRuleSpec `yaml:"rules"`
+}
+
+// FirewallRuleSpec is a firewall rule, see upcloud.FirewallRule. The rules are in order, so they have no position.
+type FirewallRuleSpec struct {
+	Direction               string `yaml:"direction"`
+	Action                  string `yaml:"action"`
+	Family                  string `yaml:"family"`
+	Protocol                string `yaml:"protocol"`
+	ICMPType                string `yaml:"icmp_type"`
+	SourceAddressStart      string `yaml:"source_address_start"`
+	SourceAddressEnd        string `yaml:"source_address_end"`
+	SourcePortStart         string `yaml:"source_port_start"`
+	SourcePortEnd           string `yaml:"source_port_end"`
+	DestinationAddressStart string `yaml:"destination_address_start"`
+	DestinationAddressEnd   string `yaml:"destination_address_end"`
+	DestinationPortStart    string `yaml:"destination_port_start"`
+	DestinationPortEnd      string `yaml:"destination_port_end"`
+	Comment                 string `yaml:"comment"`
+}
+
+// FirewallRule returns the rule as an upcloud.FirewallRule without a position
+func (r FirewallRuleSpec) FirewallRule() upcloud.FirewallRule {
+	return upcloud.FirewallRule{
+		Direction:               r.Direction,
+		Action:                  r.Action,
+		Family:                  r.Family,
+		Protocol:                r.Protocol,
+		ICMPType:                r.ICMPType,
+		SourceAddressStart:      r.SourceAddressStart,
+		SourceAddressEnd:        r.SourceAddressEnd,
+		SourcePortStart:         r.SourcePortStart,
+		SourcePortEnd:           r.SourcePortEnd,
+		DestinationAddressStart: r.DestinationAddressStart,
+		DestinationAddressEnd:   r.DestinationAddressEnd,
+		DestinationPortStart:    r.DestinationPortStart,
+		DestinationPortEnd:      r.DestinationPortEnd,
+		Comment:                 r.Comment,
+	}
+}
+
+// Load reads a spec in YAML and validates it. Unknown fields are an error.
+func Load(r io.Reader) (*Spec, error) {
+	data, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	spec := Spec{}
+	if err := yaml.UnmarshalStrict(data, &spec); err != nil {
+		return nil, fmt.Errorf("unable to parse spec: %w", err)
+	}
+
+	if err := spec.Validate(); err != nil {
+		return nil, err
+	}
+
+	return &spec, nil
+}
+
+// LoadFile reads a spec in YAML from a file and validates it
+func LoadFile(path string) (*Spec, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	spec, err := Load(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+
+	return spec, nil
+}
+
+// Validate checks that the resources have a name and a zone, that the names are unique and that the servers only
+// refer to the storages and the networks of the spec in their zone
+func (s *Spec) Validate() error {
+	networks := make(map[string]*NetworkSpec)
+	for i := range s.Networks {
+		n := &s.Networks[i]
+		if n.Name == "" {
+			return fmt.Errorf("networks[%d]: name is required", i)
+		}
+		if networks[n.Name] != nil {
+			return fmt.Errorf("networks[%d]: network %q is defined more than once", i, n.Name)
+		}
+		if s.zone(n.Zone) == "" {
+			return fmt.Errorf("network %q: zone is required", n.Name)
+		}
+		if !n.Absent && len(n.IPNetworks) == 0 {
+			return fmt.Errorf("network %q: ip_networks is required", n.Name)
+		}
+		networks[n.Name] = n
+	}
+
+	storages := make(map[string]*StorageSpec)
+	for i := range s.Storages {
+		st := &s.Storages[i]
+		if st.Title == "" {
+			return fmt.Errorf("storages[%d]: title is required", i)
+		}
+		if storages[st.Title] != nil {
+			return fmt.Errorf("storages[%d]: storage %q is defined more than once", i, st.Title)
+		}
+		if s.zone(st.Zone) == "" {
+			return fmt.Errorf("storage %q: zone is required", st.Title)
+		}
+		if !st.Absent && st.Size <= 0 {
+			return fmt.Errorf("storage %q: size is required", st.Title)
+		}
+		storages[st.Title] = st
+	}
+
+	servers := make(map[string]bool)
+	attached := make(map[string]string)
+	for i := range s.Servers {
+		sv := &s.Servers[i]
+		if sv.Title == "" {
+			return fmt.Errorf("servers[%d]: title is required", i)
+		}
+		if servers[sv.Title] {
+			return fmt.Errorf("servers[%d]: server %q is defined more than once", i, sv.Title)
+		}
+		servers[sv.Title] = true
+		zone := s.zone(sv.Zone)
+		if zone == "" {
+			return fmt.Errorf("server %q: zone is required", sv.Title)
+		}
+		if sv.Absent {
+			continue
+		}
+
+		for _, title := range sv.Storages {
+			st := storages[title]
+			switch {
+			case st == nil || st.Absent:
+				return fmt.Errorf("server %q: storage %q isn't in the spec", sv.Title, title)
+			case s.zone(st.Zone) != zone:
+				return fmt.Errorf("server %q: storage %q is in another zone", sv.Title, title)
+			case attached[title] != "":
+				return fmt.Errorf("server %q: storage %q is already attached to server %q", sv.Title, title, attached[title])
+			}
+			attached[title] = sv.Title
+		}
+
+		for _, iface := range sv.Networks {
+			switch iface.Type {
+			case upcloud.NetworkTypePublic, upcloud.NetworkTypeUtility:
+			case upcloud.NetworkTypePrivate:
+				n := networks[iface.Network]
+				if n == nil || n.Absent {
+					return fmt.Errorf("server %q: network %q isn't in the spec", sv.Title, iface.Network)
+				}
+				if s.zone(n.Zone) != zone {
+					return fmt.Errorf("server %q: network %q is in another zone", sv.Title, iface.Network)
+				}
+			default:
+				return fmt.Errorf("server %q: invalid network interface type %q", sv.Title, iface.Type)
+			}
+		}
+
+		if sv.Firewall != nil {
+			for j, rule := range sv.Firewall.Rules {
+				if rule.Direction == "" || rule.Action == "" || rule.Family == "" {
+					return fmt.Errorf("server %q: firewall rule %d: direction, action and family are required", sv.Title, j+1)
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+// Returns the zone of a resource
+func (s *Spec) zone(zone string) string {
+	if zone != "" {
+		return zone
+	}
+
+	return s.Zone
+}
+
+// Returns the hostname of a server
+func (sv *ServerSpec) hostname() string {
+	if sv.Hostname != "" {
+		return sv.Hostname
+	}
+
+	return sv.Title
+}
+
+// Returns the IP address family of an interface
+func (i InterfaceSpec) family() string {
+	if i.Family != "" {
+		return i.Family
+	}
+
+	return upcloud.IPAddressFamilyIPv4
+}
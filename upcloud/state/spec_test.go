@@ -0,0 +1,185 @@
+package state
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/UpCloudLtd/upcloud-go-api/upcloud"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testSpec = `
+zone: fi-hel2
+tag: PROD
+networks:
+  - name: backend
+    ip_networks:
+      - address: 10.0.0.0/24
+        family: IPv4
+        dhcp: true
+storages:
+  - title: data
+    size: 20
+    tier: maxiops
+servers:
+  - title: web-1
+    hostname: web-1.example.com
+    plan: 1xCPU-2GB
+    os:
+      template: Ubuntu Server 20.04 LTS (Focal Fossa)
+      size: 25
+    storages: [data]
+    networks:
+      - public
+      - utility
+      - backend
+      - type: public
+        family: IPv6
+    tags: [WEB]
+    firewall:
+      rules:
+        - direction: in
+          action: accept
+          family: IPv4
+          protocol: tcp
+          destination_port_start: "22"
+          destination_port_end: "22"
+`
+
+// TestLoad tests that a spec is read from YAML with the short form of the network interfaces
+func TestLoad(t *testing.T) {
+	spec, err := Load(strings.NewReader(testSpec))
+	require.NoError(t, err)
+
+	assert.Equal(t, "fi-hel2", spec.Zone)
+	assert.Equal(t, "PROD", spec.Tag)
+	assert.Equal(t, []NetworkSpec{{
+		Name:       "backend",
+		IPNetworks: []IPNetworkSpec{{Address: "10.0.0.0/24", Family: upcloud.IPAddressFamilyIPv4, DHCP: true}},
+	}}, spec.Networks)
+	assert.Equal(t, []StorageSpec{{Title: "data", Size: 20, Tier: upcloud.StorageTierMaxIOPS}}, spec.Storages)
+
+	require.Len(t, spec.Servers, 1)
+	server := spec.Servers[0]
+	assert.Equal(t, &OSSpec{Template: "Ubuntu Server 20.04 LTS (Focal Fossa)", Size: 25}, server.OS)
+	assert.Equal(t, []InterfaceSpec{
+		{Type: upcloud.NetworkTypePublic},
+		{Type: upcloud.NetworkTypeUtility},
+		{Type: upcloud.NetworkTypePrivate, Network: "backend"},
+		{Type: upcloud.NetworkTypePublic, Family: upcloud.IPAddressFamilyIPv6},
+	}, server.Networks)
+	require.Len(t, server.Firewall.Rules, 1)
+	assert.Equal(t, upcloud.FirewallRule{
+		Direction:            upcloud.FirewallRuleDirectionIn,
+		Action:               upcloud.FirewallRuleActionAccept,
+		Family:               upcloud.IPAddressFamilyIPv4,
+		Protocol:             upcloud.FirewallRuleProtocolTCP,
+		DestinationPortStart: "22",
+		DestinationPortEnd:   "22",
+	}, server.Firewall.Rules[0].FirewallRule())
+
+	_, err = Load(strings.NewReader("zone: fi-hel2\nservers:\n  - title: web-1\n    plann: 1xCPU-2GB\n"))
+	assert.Error(t, err)
+}
+
+// TestLoadFile tests that errors in a spec file mention the file
+func TestLoadFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "state")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "spec.yaml")
+	require.NoError(t, ioutil.WriteFile(path, []byte(testSpec), 0600))
+	spec, err := LoadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, "PROD", spec.Tag)
+
+	require.NoError(t, ioutil.WriteFile(path, []byte("storages:\n  - title: data\n    size: 10\n"), 0600))
+	_, err = LoadFile(path)
+	assert.EqualError(t, err, path+`: storage "data": zone is required`)
+}
+
+// TestSpecValidate tests that invalid specs are rejected
+func TestSpecValidate(t *testing.T) {
+	tests := []struct {
+		name string
+		spec Spec
+		err  string
+	}{
+		{
+			name: "duplicate network",
+			spec: Spec{Zone: "fi-hel2", Networks: []NetworkSpec{
+				{Name: "backend", IPNetworks: []IPNetworkSpec{{}}},
+				{Name: "backend", IPNetworks: []IPNetworkSpec{{}}},
+			}},
+			err: `networks[1]: network "backend" is defined more than once`,
+		},
+		{
+			name: "storage without size",
+			spec: Spec{Zone: "fi-hel2", Storages: []StorageSpec{{Title: "data"}}},
+			err:  `storage "data": size is required`,
+		},
+		{
+			name: "unknown storage",
+			spec: Spec{Zone: "fi-hel2", Servers: []ServerSpec{{Title: "web-1", Storages: []string{"data"}}}},
+			err:  `server "web-1": storage "data" isn't in the spec`,
+		},
+		{
+			name: "storage in another zone",
+			spec: Spec{
+				Zone:     "fi-hel2",
+				Storages: []StorageSpec{{Title: "data", Size: 10, Zone: "de-fra1"}},
+				Servers:  []ServerSpec{{Title: "web-1", Storages: []string{"data"}}},
+			},
+			err: `server "web-1": storage "data" is in another zone`,
+		},
+		{
+			name: "storage attached twice",
+			spec: Spec{
+				Zone:     "fi-hel2",
+				Storages: []StorageSpec{{Title: "data", Size: 10}},
+				Servers: []ServerSpec{
+					{Title: "web-1", Storages: []string{"data"}},
+					{Title: "web-2", Storages: []string{"data"}},
+				},
+			},
+			err: `server "web-2": storage "data" is already attached to server "web-1"`,
+		},
+		{
+			name: "absent network",
+			spec: Spec{
+				Zone:     "fi-hel2",
+				Networks: []NetworkSpec{{Name: "backend", Absent: true}},
+				Servers:  []ServerSpec{{Title: "web-1", Networks: []InterfaceSpec{{Type: upcloud.NetworkTypePrivate, Network: "backend"}}}},
+			},
+			err: `server "web-1": network "backend" isn't in the spec`,
+		},
+		{
+			name: "invalid firewall rule",
+			spec: Spec{Zone: "fi-hel2", Servers: []ServerSpec{{
+				Title:    "web-1",
+				Firewall: &FirewallSpec{Rules: []FirewallRuleSpec{{Direction: upcloud.FirewallRuleDirectionIn}}},
+			}}},
+			err: `server "web-1": firewall rule 1: direction, action and family are required`,
+		},
+		{
+			name: "absent server",
+			spec: Spec{Zone: "fi-hel2", Servers: []ServerSpec{{Title: "web-1", Storages: []string{"data"}, Absent: true}}},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := test.spec.Validate()
+			if test.err == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assert.EqualError(t, err, test.err)
+		})
+	}
+}
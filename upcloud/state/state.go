@@ -0,0 +1,130 @@
+// Package state brings servers, storages, private networks and firewalls to a desired state described in YAML:
+//
+//	spec, err := state.LoadFile("production.yaml")
+//	engine := state.New(service.New(c))
+//
+//	plan, err := engine.Plan(ctx, spec)
+//	fmt.Print(plan) // e.g. "+ create server web-1"
+//	result, err := engine.Apply(ctx, plan)
+//
+// The resources are identified by their titles, or names for networks. With a tag in the spec, only the servers
+// with the tag are considered and the servers with the tag that are missing from the spec are deleted. Other
+// resources are only deleted when they're marked absent in the spec.
+//
+// A plan lists the changes to make in the order of their dependencies, and applying it waits for the servers and
+// the storages to reach their states between the steps. The changes to the resources that already exist are the
+// drift of the resources from the spec.
+package state
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/UpCloudLtd/upcloud-go-api/upcloud/service"
+)
+
+// DefaultTimeout is how long applying a plan waits for a server or a storage to reach a state by default
+const DefaultTimeout = 10 * time.Minute
+
+// Option configures an Engine
+type Option func(e *Engine)
+
+// WithTimeout sets how long applying a plan waits for a server or a storage to reach a state, e.g. for a new server
+// to start
+func WithTimeout(timeout time.Duration) Option {
+	return func(e *Engine) {
+		e.timeout = timeout
+	}
+}
+
+// Engine plans and applies the changes bringing the resources to the state of a spec
+type Engine struct {
+	svc     service.API
+	timeout time.Duration
+}
+
+// New returns an engine managing resources through the specified service
+func New(svc service.API, opts ...Option) *Engine {
+	e := &Engine{
+		svc:     svc,
+		timeout: DefaultTimeout,
+	}
+
+	for _, opt := range opts {
+		opt(e)
+	}
+
+	return e
+}
+
+// Plan reads the current state of the resources of the spec and returns the changes bringing them to the state of
+// the spec
+func (e *Engine) Plan(ctx context.Context, spec *Spec) (*Plan, error) {
+	if err := spec.Validate(); err != nil {
+		return nil, err
+	}
+
+	cur, err := e.readCurrent(ctx, spec)
+	if err != nil {
+		return nil, err
+	}
+
+	return e.newPlan(ctx, spec, cur)
+}
+
+// Result is the outcome of applying a plan
+type Result struct {
+	// Applied are the changes that were applied
+	Applied []Change
+	// Drift are the changes a new plan has after the plan was applied, e.g. because the resources were changed
+	// while the plan was applied
+	Drift []Change
+}
+
+// ApplyError is returned when a change of a plan fails. The changes before it have been applied.
+type ApplyError struct {
+	Change Change
+	Err    error
+}
+
+// Error implements the error interface
+func (e *ApplyError) Error() string {
+	return fmt.Sprintf("unable to %s %s %s: %s", e.Change.Action, e.Change.Kind, e.Change.Name, e.Err)
+}
+
+// Unwrap returns the error the change failed with
+func (e *ApplyError) Unwrap() error {
+	return e.Err
+}
+
+// Apply applies the changes of a plan in order and plans again to report the drift that's left. The result lists
+// the changes applied before a change fails with an *ApplyError.
+func (e *Engine) Apply(ctx context.Context, plan *Plan) (*Result, error) {
+	a := newApplier(ctx, e, plan)
+	result := &Result{}
+
+	for step := 0; step < steps; step++ {
+		for i := range plan.Changes {
+			c := &plan.Changes[i]
+			if !c.inStep(step) {
+				continue
+			}
+
+			if err := a.apply(c, step); err != nil {
+				return result, &ApplyError{Change: *c, Err: err}
+			}
+			if step == c.lastStep() {
+				result.Applied = append(result.Applied, *c)
+			}
+		}
+	}
+
+	after, err := e.Plan(ctx, plan.spec)
+	if err != nil {
+		return result, err
+	}
+	result.Drift = after.Changes
+
+	return result, nil
+}
@@ -0,0 +1,204 @@
+package state
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/UpCloudLtd/upcloud-go-api/upcloud"
+	"github.com/UpCloudLtd/upcloud-go-api/upcloud/emulator"
+	"github.com/UpCloudLtd/upcloud-go-api/upcloud/request"
+	"github.com/UpCloudLtd/upcloud-go-api/upcloud/service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Returns an engine managing the resources of an emulator, along with the service it uses
+func newTestEngine(t *testing.T) (*Engine, *service.Service) {
+	srv := emulator.NewServer()
+	t.Cleanup(srv.Close)
+
+	svc := service.New(srv.Client())
+	policy := service.DefaultWaitPolicy()
+	policy.MinInterval = time.Millisecond
+	policy.MaxInterval = time.Millisecond
+	svc.SetWaitPolicy(policy)
+
+	return New(svc, WithTimeout(time.Minute)), svc
+}
+
+// Loads the test spec after applying the edit to it
+func loadTestSpec(t *testing.T, edit func(s *Spec)) *Spec {
+	spec, err := Load(strings.NewReader(testSpec))
+	require.NoError(t, err)
+	if edit != nil {
+		edit(spec)
+	}
+
+	return spec
+}
+
+// Plans and applies a spec, expecting no drift afterwards
+func apply(t *testing.T, e *Engine, spec *Spec) (*Plan, *Result) {
+	plan, err := e.Plan(context.Background(), spec)
+	require.NoError(t, err)
+	result, err := e.Apply(context.Background(), plan)
+	require.NoError(t, err)
+	assert.Empty(t, result.Drift)
+
+	return plan, result
+}
+
+// Returns the details of the server with the title
+func serverDetails(t *testing.T, svc *service.Service, title string) *upcloud.ServerDetails {
+	servers, err := svc.GetServers()
+	require.NoError(t, err)
+	for _, server := range servers.Servers {
+		if server.Title == title {
+			details, err := svc.GetServerDetails(&request.GetServerDetailsRequest{UUID: server.UUID})
+			require.NoError(t, err)
+			return details
+		}
+	}
+
+	t.Fatalf("server %q doesn't exist", title)
+	return nil
+}
+
+// TestApply tests that applying plans creates, modifies, replaces and deletes the resources of a spec
+func TestApply(t *testing.T) {
+	e, svc := newTestEngine(t)
+
+	plan, result := apply(t, e, loadTestSpec(t, nil))
+	assert.Equal(t, "+ create network backend\n+ create storage data\n+ create server web-1\n", plan.String())
+	assert.Len(t, result.Applied, 3)
+
+	server := serverDetails(t, svc, "web-1")
+	assert.Equal(t, upcloud.ServerStateStarted, server.State)
+	assert.Equal(t, "web-1.example.com", server.Hostname)
+	assert.Equal(t, "fi-hel2", server.Zone)
+	assert.ElementsMatch(t, []string{"PROD", "WEB"}, server.Tags)
+	assert.Equal(t, "on", server.Firewall)
+	require.Len(t, server.StorageDevices, 2)
+	assert.Equal(t, "web-1 OS", server.StorageDevices[0].Title)
+	assert.Equal(t, "data", server.StorageDevices[1].Title)
+	require.Len(t, server.Networking.Interfaces, 4)
+	assert.Equal(t, upcloud.NetworkTypePrivate, server.Networking.Interfaces[2].Type)
+	rules, err := svc.GetFirewallRules(&request.GetFirewallRulesRequest{ServerUUID: server.UUID})
+	require.NoError(t, err)
+	assert.Len(t, rules.FirewallRules, 1)
+
+	plan, err = e.Plan(context.Background(), loadTestSpec(t, nil))
+	require.NoError(t, err)
+	assert.True(t, plan.Empty())
+
+	// Replacing the storage detaches it from the server, which is modified to attach the new one
+	spec := loadTestSpec(t, func(s *Spec) {
+		s.Storages[0].Tier = upcloud.StorageTierHDD
+		s.Servers[0].Plan = "2xCPU-4GB"
+		s.Servers[0].Tags = []string{"APP"}
+		s.Servers[0].Firewall.Rules = append(s.Servers[0].Firewall.Rules, FirewallRuleSpec{
+			Direction: upcloud.FirewallRuleDirectionIn,
+			Action:    upcloud.FirewallRuleActionDrop,
+			Family:    upcloud.IPAddressFamilyIPv4,
+		})
+	})
+	plan, result = apply(t, e, spec)
+	assert.Equal(t, `-/+ replace storage data
+    tier: maxiops -> hdd (replaces)
+~ modify server web-1
+    plan: 1xCPU-2GB -> 2xCPU-4GB
+    tags: PROD,WEB -> APP,PROD
+    storages: (none) -> data
+    firewall: 1 rules -> 2 rules
+`, plan.String())
+	assert.Len(t, result.Applied, 2)
+
+	updated := serverDetails(t, svc, "web-1")
+	assert.Equal(t, server.UUID, updated.UUID)
+	assert.Equal(t, upcloud.ServerStateStarted, updated.State)
+	assert.Equal(t, "2xCPU-4GB", updated.Plan)
+	assert.ElementsMatch(t, []string{"APP", "PROD"}, updated.Tags)
+	require.Len(t, updated.StorageDevices, 2)
+	assert.NotEqual(t, server.StorageDevices[1].UUID, updated.StorageDevices[1].UUID)
+	storage, err := svc.GetStorageDetails(&request.GetStorageDetailsRequest{UUID: updated.StorageDevices[1].UUID})
+	require.NoError(t, err)
+	assert.Equal(t, upcloud.StorageTierHDD, storage.Tier)
+
+	// Everything is deleted, the storages of the server that aren't in the spec along with it
+	spec = loadTestSpec(t, func(s *Spec) {
+		s.Networks[0].Absent = true
+		s.Storages[0].Absent = true
+		s.Servers = nil
+	})
+	plan, _ = apply(t, e, spec)
+	assert.Equal(t, "- delete server web-1\n- delete storage data\n- delete network backend\n", plan.String())
+
+	servers, err := svc.GetServers()
+	require.NoError(t, err)
+	assert.Empty(t, servers.Servers)
+	storages, err := svc.GetStorages(&request.GetStoragesRequest{Access: upcloud.StorageAccessPrivate})
+	require.NoError(t, err)
+	assert.Empty(t, storages.Storages)
+}
+
+// TestApplyError tests that a failing change stops the plan with an error naming the change
+func TestApplyError(t *testing.T) {
+	e, _ := newTestEngine(t)
+
+	spec := loadTestSpec(t, func(s *Spec) {
+		s.Servers[0].Plan = "3xCPU-1GB"
+	})
+	plan, err := e.Plan(context.Background(), spec)
+	require.NoError(t, err)
+
+	result, err := e.Apply(context.Background(), plan)
+	var applyError *ApplyError
+	require.True(t, errors.As(err, &applyError))
+	assert.Equal(t, Create, applyError.Change.Action)
+	assert.Equal(t, "web-1", applyError.Change.Name)
+	assert.True(t, strings.HasPrefix(err.Error(), "unable to create server web-1: "))
+	assert.Len(t, result.Applied, 2)
+}
+
+// TestApplyPruneKeepsStorages tests that deleting a server only deletes its OS disk and keeps the other storages
+// attached to it
+func TestApplyPruneKeepsStorages(t *testing.T) {
+	e, svc := newTestEngine(t)
+
+	old, err := svc.CreateServer(&request.CreateServerRequest{
+		Zone:             "fi-hel2",
+		Title:            "old",
+		Hostname:         "old.example.com",
+		PasswordDelivery: request.PasswordDeliveryNone,
+		StorageDevices: request.CreateServerStorageDeviceSlice{
+			{
+				Action:  request.CreateServerStorageDeviceActionClone,
+				Storage: "01000000-0000-4000-8000-000030060200",
+				Title:   "old OS",
+			},
+			{
+				Action: request.CreateServerStorageDeviceActionCreate,
+				Title:  "old data",
+				Size:   10,
+			},
+		},
+	})
+	require.NoError(t, err)
+	_, err = svc.TagServer(&request.TagServerRequest{UUID: old.UUID, Tags: []string{"PROD"}})
+	require.NoError(t, err)
+
+	plan, _ := apply(t, e, loadTestSpec(t, nil))
+	assert.Contains(t, plan.String(), "- delete server old\n")
+
+	storages, err := svc.GetStorages(&request.GetStoragesRequest{Access: upcloud.StorageAccessPrivate})
+	require.NoError(t, err)
+	var titles []string
+	for _, st := range storages.Storages {
+		titles = append(titles, st.Title)
+	}
+	assert.Contains(t, titles, "old data")
+	assert.NotContains(t, titles, "old OS")
+}
@@ -0,0 +1,41 @@
+package upcloudtest
+
+import (
+	"os"
+
+	"github.com/dnaeon/go-vcr/cassette"
+)
+
+// Returns the file of the cassette with the specified name
+func cassetteFile(name string) string {
+	return name + ".yaml"
+}
+
+// CassetteExists returns whether the cassette with the specified name has been recorded
+func CassetteExists(name string) bool {
+	_, err := os.Stat(cassetteFile(name))
+	return err == nil
+}
+
+// RedactCassette redacts a cassette recorded earlier, e.g. one recorded before the library redacted cassettes or one
+// containing secrets in fields added with WithRedactedFields. Only the redaction options are used.
+func RedactCassette(name string, opts ...Option) error {
+	c := config{redactedFields: append([]string{}, defaultRedactedFields...)}
+	for _, opt := range opts {
+		opt(&c)
+	}
+
+	cas, err := cassette.Load(name)
+	if err != nil {
+		return err
+	}
+
+	r := newRedactor(c.redactedFields)
+	for _, i := range cas.Interactions {
+		if err := r.filter(i); err != nil {
+			return err
+		}
+	}
+
+	return cas.Save()
+}
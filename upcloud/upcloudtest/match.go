@@ -0,0 +1,117 @@
+package upcloudtest
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"reflect"
+	"regexp"
+
+	"github.com/dnaeon/go-vcr/cassette"
+)
+
+// The JSON fields whose generated parts are ignored by default
+var defaultTitleFields = []string{
+	"title",
+	"hostname",
+	"name",
+	"description",
+}
+
+// Matches the generated parts of titles by default: runs of six or more digits, such as timestamps
+var defaultGeneratedPattern = regexp.MustCompile(`[0-9]{6,}`)
+
+// Replaces the generated parts of titles when comparing request bodies
+const generatedPlaceholder = "{generated}"
+
+// matcher matches requests to recorded ones by their method, URL and body
+type matcher struct {
+	redactor    *redactor
+	titleFields map[string]bool
+	generated   []*regexp.Regexp
+}
+
+// Returns a matcher ignoring the generated parts of the specified fields
+func newMatcher(r *redactor, titleFields []string, generated []*regexp.Regexp) *matcher {
+	m := matcher{
+		redactor:    r,
+		titleFields: make(map[string]bool, len(titleFields)),
+		generated:   generated,
+	}
+	for _, field := range titleFields {
+		m.titleFields[field] = true
+	}
+
+	return &m
+}
+
+// match is a cassette.Matcher. The bodies are compared after redacting them and, if they are JSON, ignoring the
+// order of the fields and the generated parts of titles. The bodies of uploads aren't recorded and aren't compared.
+func (m *matcher) match(r *http.Request, i cassette.Request) bool {
+	if r.Method != i.Method || r.URL.String() != i.URL {
+		return false
+	}
+
+	if isUpload(r.Method, i.URL) {
+		return true
+	}
+	if r.Body == nil || r.Body == http.NoBody {
+		return i.Body == ""
+	}
+
+	body, err := ioutil.ReadAll(r.Body)
+	r.Body.Close()
+	// Restore the body for the recorder
+	r.Body = ioutil.NopCloser(bytes.NewReader(body))
+	if err != nil {
+		return false
+	}
+
+	return m.equalBodies(string(body), i.Body)
+}
+
+// Returns whether two request bodies are equal
+func (m *matcher) equalBodies(a, b string) bool {
+	a, b = m.redactor.body(a), m.redactor.body(b)
+	if a == b {
+		return true
+	}
+
+	var aValue, bValue interface{}
+	if !m.decode(a, &aValue) || !m.decode(b, &bValue) {
+		return false
+	}
+
+	return reflect.DeepEqual(m.normalize("", aValue), m.normalize("", bValue))
+}
+
+// Decodes a JSON body, returning false if it isn't JSON
+func (m *matcher) decode(body string, v *interface{}) bool {
+	dec := json.NewDecoder(bytes.NewReader([]byte(body)))
+	dec.UseNumber()
+	return dec.Decode(v) == nil
+}
+
+// Returns the value with the generated parts of the title fields replaced
+func (m *matcher) normalize(field string, v interface{}) interface{} {
+	switch value := v.(type) {
+	case map[string]interface{}:
+		for k, fieldValue := range value {
+			value[k] = m.normalize(k, fieldValue)
+		}
+	case []interface{}:
+		for i, item := range value {
+			value[i] = m.normalize(field, item)
+		}
+	case string:
+		if m.titleFields[field] {
+			for _, pattern := range m.generated {
+				value = pattern.ReplaceAllString(value, generatedPlaceholder)
+			}
+			return value
+		}
+	}
+
+	return v
+}
@@ -0,0 +1,58 @@
+package upcloudtest
+
+import (
+	"io/ioutil"
+	"net/http"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/dnaeon/go-vcr/cassette"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestMatcher tests that requests match recorded ones with the same method, URL and body, ignoring generated titles
+func TestMatcher(t *testing.T) {
+	r := newRedactor(defaultRedactedFields)
+	m := newMatcher(r, defaultTitleFields, []*regexp.Regexp{defaultGeneratedPattern, regexp.MustCompile(`run-[a-z]+`)})
+	recorded := cassette.Request{
+		Method: http.MethodPost,
+		URL:    "https://api.upcloud.com/1.3/server",
+		Body:   `{"server":{"title":"test-1603718820","hostname":"run-abc.example.com","zone":"fi-hel2","password":"[REDACTED]"}}`,
+	}
+
+	match := func(method, url, body string) bool {
+		req, err := http.NewRequest(method, url, strings.NewReader(body))
+		require.NoError(t, err)
+
+		matched := m.match(req, recorded)
+
+		// The body is still there for the recorder
+		restored, err := ioutil.ReadAll(req.Body)
+		require.NoError(t, err)
+		assert.Equal(t, body, string(restored))
+
+		return matched
+	}
+
+	assert.True(t, match(http.MethodPost, recorded.URL,
+		`{"server":{"zone":"fi-hel2","password":"secret","hostname":"run-xyz.example.com","title":"test-1603799999"}}`))
+	assert.False(t, match(http.MethodPost, recorded.URL,
+		`{"server":{"title":"test-1603799999","hostname":"run-xyz.example.com","zone":"de-fra1"}}`))
+	assert.False(t, match(http.MethodPost, recorded.URL,
+		`{"server":{"title":"other-1603799999","hostname":"run-xyz.example.com","zone":"fi-hel2","password":"secret"}}`))
+	assert.False(t, match(http.MethodPut, recorded.URL, recorded.Body))
+	assert.False(t, match(http.MethodPost, recorded.URL+"/start", recorded.Body))
+	assert.False(t, match(http.MethodPost, recorded.URL, "not JSON"))
+
+	req, err := http.NewRequest(http.MethodGet, recorded.URL, nil)
+	require.NoError(t, err)
+	assert.True(t, m.match(req, cassette.Request{Method: http.MethodGet, URL: recorded.URL}))
+
+	// Uploads aren't recorded
+	upload := cassette.Request{Method: http.MethodPut, URL: "https://fi-hel1.img.upcloud.com/uploader/session/0"}
+	req, err = http.NewRequest(http.MethodPut, upload.URL, strings.NewReader("raw image"))
+	require.NoError(t, err)
+	assert.True(t, m.match(req, upload))
+}
@@ -0,0 +1,268 @@
+// Package upcloudtest records the API traffic of tests into cassettes and replays it on later runs, so tests that use
+// the API run deterministically and without credentials once they have been recorded. It's what the library uses for
+// its own tests.
+//
+// Credentials, passwords and Object Storage keys are redacted before they're written to a cassette. Requests are
+// matched to the recorded ones by their method, URL and body, ignoring the generated parts of titles, e.g. timestamps.
+//
+//	func TestCreateServer(t *testing.T) {
+//		rec := upcloudtest.Start(t, "fixtures/createserver")
+//		svc := service.New(rec.Client())
+//
+//		...
+//	}
+//
+// Cassettes are recorded with the credentials in the UPCLOUD_GO_SDK_TEST_USER and UPCLOUD_GO_SDK_TEST_PASSWORD
+// environment variables unless others are set with WithCredentials. Replaying doesn't need credentials.
+package upcloudtest
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"regexp"
+	"testing"
+
+	"github.com/UpCloudLtd/upcloud-go-api/upcloud/client"
+	"github.com/dnaeon/go-vcr/cassette"
+	"github.com/dnaeon/go-vcr/recorder"
+)
+
+// Mode is the operating mode of a Recorder
+type Mode int
+
+const (
+	// ModeAuto replays the cassette if it exists and records it otherwise
+	ModeAuto Mode = iota
+	// ModeRecording records a new cassette, replacing an existing one
+	ModeRecording
+	// ModeReplaying replays the cassette and fails if it doesn't exist
+	ModeReplaying
+	// ModeDisabled passes requests through to the API without recording them
+	ModeDisabled
+)
+
+// String implements the Stringer interface
+func (m Mode) String() string {
+	switch m {
+	case ModeAuto:
+		return "auto"
+	case ModeRecording:
+		return "recording"
+	case ModeReplaying:
+		return "replaying"
+	case ModeDisabled:
+		return "disabled"
+	}
+
+	return fmt.Sprintf("Mode(%d)", int(m))
+}
+
+// ErrCassetteNotFound is returned when a cassette to be replayed doesn't exist
+var ErrCassetteNotFound = errors.New("cassette not found")
+
+// Option configures a Recorder
+type Option func(*config)
+
+type config struct {
+	mode           Mode
+	transport      http.RoundTripper
+	username       string
+	password       string
+	redactedFields []string
+	titleFields    []string
+	generated      []*regexp.Regexp
+	matcher        cassette.Matcher
+	filters        []cassette.Filter
+}
+
+// WithMode sets the operating mode. The default is ModeAuto.
+func WithMode(mode Mode) Option {
+	return func(c *config) {
+		c.mode = mode
+	}
+}
+
+// WithTransport sets the transport used for the requests to the API when recording. The default is
+// http.DefaultTransport.
+func WithTransport(transport http.RoundTripper) Option {
+	return func(c *config) {
+		c.transport = transport
+	}
+}
+
+// WithCredentials sets the credentials used when recording
+func WithCredentials(username, password string) Option {
+	return func(c *config) {
+		c.username = username
+		c.password = password
+	}
+}
+
+// WithRedactedFields adds JSON fields whose values are redacted from the request and response bodies, in addition
+// to the passwords and Object Storage keys redacted by default
+func WithRedactedFields(fields ...string) Option {
+	return func(c *config) {
+		c.redactedFields = append(c.redactedFields, fields...)
+	}
+}
+
+// WithTitleFields adds JSON fields whose generated parts are ignored when matching requests, in addition to the
+// title, hostname, name and description fields
+func WithTitleFields(fields ...string) Option {
+	return func(c *config) {
+		c.titleFields = append(c.titleFields, fields...)
+	}
+}
+
+// WithGeneratedPattern adds a pattern of generated values that are ignored in the title fields when matching
+// requests, in addition to runs of six or more digits such as timestamps
+func WithGeneratedPattern(pattern *regexp.Regexp) Option {
+	return func(c *config) {
+		c.generated = append(c.generated, pattern)
+	}
+}
+
+// WithMatcher replaces the function matching requests to the recorded ones
+func WithMatcher(matcher cassette.Matcher) Option {
+	return func(c *config) {
+		c.matcher = matcher
+	}
+}
+
+// WithFilter adds a filter that modifies interactions before they're recorded. Filters run after the redaction.
+func WithFilter(filter cassette.Filter) Option {
+	return func(c *config) {
+		c.filters = append(c.filters, filter)
+	}
+}
+
+// Recorder is an http.RoundTripper that records the API traffic into a cassette or replays it from one
+type Recorder struct {
+	vcr      *recorder.Recorder
+	mode     Mode
+	username string
+	password string
+}
+
+// NewRecorder returns a recorder using the cassette with the specified name. The name is a path without the .yaml
+// extension, e.g. "fixtures/createserver". The recorder must be stopped to save a recorded cassette.
+func NewRecorder(name string, opts ...Option) (*Recorder, error) {
+	c := config{
+		username:       os.Getenv("UPCLOUD_GO_SDK_TEST_USER"),
+		password:       os.Getenv("UPCLOUD_GO_SDK_TEST_PASSWORD"),
+		redactedFields: append([]string{}, defaultRedactedFields...),
+		titleFields:    append([]string{}, defaultTitleFields...),
+		generated:      []*regexp.Regexp{defaultGeneratedPattern},
+	}
+	for _, opt := range opts {
+		opt(&c)
+	}
+
+	mode := c.mode
+	if mode == ModeAuto || mode == ModeReplaying {
+		exists := CassetteExists(name)
+		switch {
+		case exists:
+			mode = ModeReplaying
+		case mode == ModeReplaying:
+			return nil, fmt.Errorf("%s: %w", cassetteFile(name), ErrCassetteNotFound)
+		default:
+			mode = ModeRecording
+		}
+	}
+
+	if mode != ModeReplaying && (c.username == "" || c.password == "") {
+		return nil, fmt.Errorf("%s requires credentials, export UPCLOUD_GO_SDK_TEST_USER and "+
+			"UPCLOUD_GO_SDK_TEST_PASSWORD or use WithCredentials", mode)
+	}
+
+	vcrMode := recorder.ModeReplaying
+	switch mode {
+	case ModeRecording:
+		vcrMode = recorder.ModeRecording
+	case ModeDisabled:
+		vcrMode = recorder.ModeDisabled
+	}
+
+	vcr, err := recorder.NewAsMode(name, vcrMode, c.transport)
+	if err != nil {
+		return nil, err
+	}
+
+	r := newRedactor(c.redactedFields)
+	vcr.AddFilter(r.filter)
+	for _, filter := range c.filters {
+		vcr.AddFilter(filter)
+	}
+
+	if c.matcher != nil {
+		vcr.SetMatcher(c.matcher)
+	} else {
+		vcr.SetMatcher(newMatcher(r, c.titleFields, c.generated).match)
+	}
+
+	return &Recorder{
+		vcr:      vcr,
+		mode:     mode,
+		username: c.username,
+		password: c.password,
+	}, nil
+}
+
+// Start returns a recorder using the cassette with the specified name and stops it when the test completes. The test
+// fails if the recorder can't be created or the cassette can't be saved.
+func Start(t testing.TB, name string, opts ...Option) *Recorder {
+	t.Helper()
+
+	r, err := NewRecorder(name, opts...)
+	if err != nil {
+		t.Fatalf("creating the recorder: %v", err)
+	}
+
+	t.Cleanup(func() {
+		if err := r.Stop(); err != nil {
+			t.Errorf("saving the cassette: %v", err)
+		}
+	})
+
+	return r
+}
+
+// Mode returns the mode the recorder operates in, either ModeRecording, ModeReplaying or ModeDisabled
+func (r *Recorder) Mode() Mode {
+	return r.mode
+}
+
+// Replaying returns whether the recorder replays a cassette. Replayed responses are returned immediately, so
+// waiting between polls can be skipped, e.g. with a short service.WaitPolicy.
+func (r *Recorder) Replaying() bool {
+	return r.mode == ModeReplaying
+}
+
+// RoundTrip implements the http.RoundTripper interface
+func (r *Recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	return r.vcr.RoundTrip(req)
+}
+
+// HTTPClient returns an HTTP client using the recorder as its transport
+func (r *Recorder) HTTPClient() *http.Client {
+	return &http.Client{Transport: r}
+}
+
+// Client returns an API client using the recorder. The recording credentials are used when recording and
+// placeholders when replaying. The options are applied after the HTTP client is set.
+func (r *Recorder) Client(opts ...client.Option) *client.Client {
+	username, password := r.username, r.password
+	if r.Replaying() {
+		username, password = "username", "password"
+	}
+
+	return client.NewWithOptions(username, password, append([]client.Option{client.WithHTTPClient(r.HTTPClient())}, opts...)...)
+}
+
+// Stop stops the recorder and saves the cassette when recording
+func (r *Recorder) Stop() error {
+	return r.vcr.Stop()
+}
@@ -0,0 +1,88 @@
+package upcloudtest
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/UpCloudLtd/upcloud-go-api/upcloud"
+	"github.com/UpCloudLtd/upcloud-go-api/upcloud/client"
+	"github.com/UpCloudLtd/upcloud-go-api/upcloud/emulator"
+	"github.com/UpCloudLtd/upcloud-go-api/upcloud/request"
+	"github.com/UpCloudLtd/upcloud-go-api/upcloud/service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Returns a temporary directory that's removed when the test completes
+func tempDir(t *testing.T) string {
+	dir, err := ioutil.TempDir("", "upcloudtest")
+	require.NoError(t, err)
+	t.Cleanup(func() { os.RemoveAll(dir) })
+
+	return dir
+}
+
+// Creates an Object Storage with a generated name, the way tests usually name their resources
+func createObjectStorage(svc *service.Service) (*upcloud.ObjectStorageDetails, error) {
+	return svc.CreateObjectStorage(&request.CreateObjectStorageRequest{
+		Name:      fmt.Sprintf("upcloudtest-%d", time.Now().UnixNano()),
+		Zone:      "fi-hel2",
+		AccessKey: "access-key-secret",
+		SecretKey: "secret-key-secret",
+		Size:      250,
+	})
+}
+
+// TestRecordReplay tests that a cassette is recorded without secrets and replayed without the API
+func TestRecordReplay(t *testing.T) {
+	name := filepath.Join(tempDir(t), "objectstorage")
+	srv := emulator.NewServer(emulator.WithCredentials("user", "pass"))
+	baseURL := client.WithBaseURL(srv.URL)
+
+	rec, err := NewRecorder(name, WithCredentials("user", "pass"))
+	require.NoError(t, err)
+	assert.Equal(t, ModeRecording, rec.Mode())
+	assert.False(t, rec.Replaying())
+
+	recorded, err := createObjectStorage(service.New(rec.Client(baseURL)))
+	require.NoError(t, err)
+	require.NoError(t, rec.Stop())
+	srv.Close()
+
+	cassette, err := ioutil.ReadFile(name + ".yaml")
+	require.NoError(t, err)
+	assert.NotContains(t, string(cassette), "access-key-secret")
+	assert.NotContains(t, string(cassette), "secret-key-secret")
+	assert.NotContains(t, string(cassette), "Authorization")
+	assert.Contains(t, string(cassette), Redacted)
+
+	// The emulator is gone, so the responses can only come from the cassette
+	rec = Start(t, name, WithMode(ModeReplaying))
+	assert.True(t, rec.Replaying())
+
+	replayed, err := createObjectStorage(service.New(rec.Client(baseURL)))
+	require.NoError(t, err)
+	assert.Equal(t, recorded.UUID, replayed.UUID)
+
+	// Each interaction is replayed once
+	_, err = createObjectStorage(service.New(rec.Client(baseURL)))
+	assert.Error(t, err)
+}
+
+// TestNewRecorderErrors tests that recorders that can't work aren't created
+func TestNewRecorderErrors(t *testing.T) {
+	name := filepath.Join(tempDir(t), "missing")
+
+	_, err := NewRecorder(name, WithMode(ModeReplaying))
+	assert.True(t, errors.Is(err, ErrCassetteNotFound))
+
+	_, err = NewRecorder(name, WithCredentials("", ""))
+	assert.EqualError(t, err, "recording requires credentials, export UPCLOUD_GO_SDK_TEST_USER and "+
+		"UPCLOUD_GO_SDK_TEST_PASSWORD or use WithCredentials")
+	assert.False(t, CassetteExists(name))
+}
@@ -0,0 +1,73 @@
+package upcloudtest
+
+import (
+	"net/http"
+	"regexp"
+	"strings"
+
+	"github.com/dnaeon/go-vcr/cassette"
+)
+
+// Redacted replaces the redacted values in cassettes
+const Redacted = "[REDACTED]"
+
+// The JSON fields redacted by default: server and remote access passwords and Object Storage keys
+var defaultRedactedFields = []string{
+	"password",
+	"remote_access_password",
+	"access_key",
+	"secret_key",
+}
+
+// The headers removed from the recorded requests
+var redactedHeaders = []string{
+	"Authorization",
+	"Cookie",
+}
+
+// redactor removes credentials and secrets from recorded interactions
+type redactor struct {
+	fields *regexp.Regexp
+}
+
+// Returns a redactor for the specified JSON fields
+func newRedactor(fields []string) *redactor {
+	quoted := make([]string, len(fields))
+	for i, field := range fields {
+		quoted[i] = regexp.QuoteMeta(field)
+	}
+
+	// Matches a field with a string value, keeping the field and the separator in the first group
+	return &redactor{
+		fields: regexp.MustCompile(`("(?:` + strings.Join(quoted, "|") + `)"\s*:\s*)"(?:[^"\\]|\\.)*"`),
+	}
+}
+
+// Returns the body with the values of the redacted fields replaced
+func (r *redactor) body(body string) string {
+	return r.fields.ReplaceAllString(body, `${1}"`+Redacted+`"`)
+}
+
+// Returns whether the request uploads the contents of a storage, which aren't recorded
+func isUpload(method, url string) bool {
+	return method == http.MethodPut && strings.Contains(url, "uploader")
+}
+
+// filter is a cassette.Filter redacting an interaction before it's recorded
+func (r *redactor) filter(i *cassette.Interaction) error {
+	// The headers are shared with the request sent, so work on a copy
+	headers := http.Header(i.Request.Headers).Clone()
+	for _, header := range redactedHeaders {
+		headers.Del(header)
+	}
+	i.Request.Headers = headers
+
+	if isUpload(i.Request.Method, i.Request.URL) {
+		i.Request.Body = ""
+	} else {
+		i.Request.Body = r.body(i.Request.Body)
+	}
+	i.Response.Body = r.body(i.Response.Body)
+
+	return nil
+}
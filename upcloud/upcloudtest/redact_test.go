@@ -0,0 +1,72 @@
+package upcloudtest
+
+import (
+	"io/ioutil"
+	"net/http"
+	"path/filepath"
+	"testing"
+
+	"github.com/dnaeon/go-vcr/cassette"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestRedactorBody tests that the values of the redacted fields are replaced in JSON bodies
+func TestRedactorBody(t *testing.T) {
+	r := newRedactor(append(defaultRedactedFields, "token"))
+
+	assert.Equal(t,
+		`{"password":"[REDACTED]","password_delivery":"none","title":"password"}`,
+		r.body(`{"password":"s3cr\"et","password_delivery":"none","title":"password"}`),
+	)
+	assert.Equal(t,
+		"{\n  \"remote_access_password\" : \"[REDACTED]\",\n  \"token\": \"[REDACTED]\"\n}",
+		r.body("{\n  \"remote_access_password\" : \"r7Mu387W\",\n  \"token\": \"abc\"\n}"),
+	)
+	assert.Equal(t, `{"access_key":"[REDACTED]","secret_key":"[REDACTED]","size":250}`,
+		r.body(`{"access_key":"access","secret_key":"secret","size":250}`))
+}
+
+// TestRedactorFilter tests that interactions are redacted without modifying the request sent
+func TestRedactorFilter(t *testing.T) {
+	headers := http.Header{"Authorization": {"Basic dXNlcjpwYXNz"}, "Accept": {"application/json"}}
+	i := &cassette.Interaction{
+		Request: cassette.Request{
+			Method:  http.MethodPut,
+			URL:     "https://fi-hel1.img.upcloud.com/uploader/session/0",
+			Headers: headers,
+			Body:    "raw image",
+		},
+		Response: cassette.Response{Body: `{"password":"secret"}`},
+	}
+
+	require.NoError(t, newRedactor(defaultRedactedFields).filter(i))
+	assert.Equal(t, http.Header{"Accept": {"application/json"}}, http.Header(i.Request.Headers))
+	assert.Equal(t, "Basic dXNlcjpwYXNz", headers.Get("Authorization"))
+	assert.Empty(t, i.Request.Body)
+	assert.Equal(t, `{"password":"[REDACTED]"}`, i.Response.Body)
+}
+
+// TestRedactCassette tests that a recorded cassette is rewritten with the secrets redacted
+func TestRedactCassette(t *testing.T) {
+	name := filepath.Join(tempDir(t), "cassette")
+	c := cassette.New(name)
+	c.AddInteraction(&cassette.Interaction{
+		Request: cassette.Request{
+			Method:  http.MethodPost,
+			URL:     "https://api.upcloud.com/1.3/object-storage",
+			Headers: http.Header{"Authorization": {"Basic dXNlcjpwYXNz"}},
+			Body:    `{"object_storage":{"access_key":"access","secret_key":"secret","token":"abc"}}`,
+		},
+		Response: cassette.Response{Body: `{"object_storage":{"name":"test"}}`, Code: http.StatusCreated},
+	})
+	require.NoError(t, c.Save())
+
+	require.NoError(t, RedactCassette(name, WithRedactedFields("token")))
+
+	data, err := ioutil.ReadFile(name + ".yaml")
+	require.NoError(t, err)
+	assert.NotContains(t, string(data), "dXNlcjpwYXNz")
+	assert.NotContains(t, string(data), "abc")
+	assert.Contains(t, string(data), `{"object_storage":{"access_key":"[REDACTED]","secret_key":"[REDACTED]","token":"[REDACTED]"}}`)
+}
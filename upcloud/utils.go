@@ -17,6 +17,11 @@ type Boolean int
 // deeply embedded values.
 func (b *Boolean) UnmarshalJSON(buf []byte) error {
 	str := string(buf)
+	if str == `null` {
+		// Leave the value untouched, like encoding/json does for other types
+		return nil
+	}
+
 	if str == `true` ||
 		str == `"true"` ||
 		str == `"yes"` ||
@@ -30,14 +35,18 @@ func (b *Boolean) UnmarshalJSON(buf []byte) error {
 	return nil
 }
 
-// MarshalJSON is a custom unmarshaller that deals with
-// deeply embedded values.
-func (b *Boolean) MarshalJSON() ([]byte, error) {
-	if (*b) == 1 {
+// MarshalJSON is a custom marshaller that deals with
+// deeply embedded values. Empty is marshalled as null, so fields that
+// shouldn't be sent when they're not set need the omitempty option.
+func (b Boolean) MarshalJSON() ([]byte, error) {
+	switch {
+	case b >= True:
 		return []byte(`"yes"`), nil
+	case b <= False:
+		return []byte(`"no"`), nil
 	}
 
-	return []byte(`"no"`), nil
+	return []byte(`null`), nil
 }
 
 // Bool converts to a standard bool value
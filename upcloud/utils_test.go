@@ -169,3 +169,49 @@ func TestBoolean_Empty(t *testing.T) {
 	var b Boolean
 	assert.True(t, b.Empty())
 }
+
+func TestBoolean_NullIsEmpty(t *testing.T) {
+	nullJSON := `
+	{
+		"value": null
+	}
+	`
+
+	s := testStruct{}
+
+	err := json.Unmarshal([]byte(nullJSON), &s)
+	assert.NoError(t, err)
+	assert.True(t, s.Value.Empty())
+}
+
+// TestBoolean_Marshal tests that all three states of a Boolean survive marshalling, also when the Boolean isn't
+// addressable
+func TestBoolean_Marshal(t *testing.T) {
+	for _, tc := range []struct {
+		value    Boolean
+		expected string
+	}{
+		{True, `"yes"`},
+		{False, `"no"`},
+		{Empty, `null`},
+	} {
+		actual, err := json.Marshal(tc.value)
+		assert.NoError(t, err)
+		assert.Equal(t, tc.expected, string(actual))
+
+		actual, err = json.Marshal(testStruct{Value: tc.value})
+		assert.NoError(t, err)
+		assert.JSONEq(t, `{"value":`+tc.expected+`}`, string(actual))
+
+		s := testStruct{}
+		assert.NoError(t, json.Unmarshal(actual, &s))
+		assert.Equal(t, tc.value, s.Value)
+	}
+
+	omitEmpty := struct {
+		Value Boolean `json:"value,omitempty"`
+	}{}
+	actual, err := json.Marshal(omitEmpty)
+	assert.NoError(t, err)
+	assert.Equal(t, `{}`, string(actual))
+}